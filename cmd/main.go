@@ -6,6 +6,7 @@ import (
 	"os/signal"
 	"pull-request-assigner/internal/app"
 	"pull-request-assigner/internal/config"
+	"pull-request-assigner/internal/lib/buildinfo"
 )
 
 const (
@@ -21,7 +22,11 @@ func main() {
 
 	log = log.With(slog.String("env", cfg.Env))
 
-	log.Info("initializing server", slog.String("address", cfg.Server.Port))
+	build := buildinfo.Get()
+	log.Info("initializing server",
+		slog.String("address", cfg.Server.Port),
+		slog.String("commit", build.Commit),
+		slog.String("build_time", build.BuildTime))
 	log.Debug("logger debug mode enabled")
 
 	application := app.MustNew(log)
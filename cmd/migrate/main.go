@@ -0,0 +1,92 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"pull-request-assigner/internal/config"
+	"pull-request-assigner/internal/lib/migrator"
+	"strconv"
+)
+
+const usage = `usage: migrate <command> [arg]
+
+commands:
+  up           apply all pending migrations
+  down [N]     roll back N migrations (default 1)
+  step N       apply N migrations, or roll back -N when N is negative
+  force V      set version V and clear the dirty flag without migrating
+  version      print the current and the latest schema version`
+
+var errUsage = errors.New(usage)
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+
+	cfg := config.MustLoad()
+
+	mg, err := migrator.New(cfg.Postgres)
+	if err != nil {
+		return err
+	}
+	defer mg.Close()
+
+	switch args[0] {
+	case "up":
+		err = mg.Up()
+	case "down":
+		n := 1
+		if len(args) > 1 {
+			if n, err = strconv.Atoi(args[1]); err != nil || n <= 0 {
+				return fmt.Errorf("down expects a positive number, got %q", args[1])
+			}
+		}
+		err = mg.Steps(-n)
+	case "step":
+		if len(args) < 2 {
+			return errUsage
+		}
+		n, convErr := strconv.Atoi(args[1])
+		if convErr != nil || n == 0 {
+			return fmt.Errorf("step expects a non-zero number, got %q", args[1])
+		}
+		err = mg.Steps(n)
+	case "force":
+		if len(args) < 2 {
+			return errUsage
+		}
+		v, convErr := strconv.Atoi(args[1])
+		if convErr != nil {
+			return fmt.Errorf("force expects a version, got %q", args[1])
+		}
+		err = mg.Force(v)
+	case "version":
+	default:
+		return fmt.Errorf("unknown command %q\n\n%s", args[0], usage)
+	}
+	if err != nil {
+		return err
+	}
+
+	version, dirty, err := mg.Version()
+	if err != nil {
+		return err
+	}
+
+	latest, err := migrator.LatestVersion()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("version: %d, dirty: %t, latest: %d\n", version, dirty, latest)
+	return nil
+}
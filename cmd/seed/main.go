@@ -0,0 +1,57 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"pull-request-assigner/internal/config"
+	"pull-request-assigner/internal/lib/migrator"
+	"pull-request-assigner/internal/lib/seed"
+	"pull-request-assigner/internal/repo"
+	"pull-request-assigner/internal/storage/postgresql"
+	"time"
+)
+
+func main() {
+	prs := flag.Int("prs", 120, "number of demo pull requests")
+	days := flag.Int("days", 60, "spread PR creation over this many past days")
+	rndSeed := flag.Int64("seed", 1, "random seed; the same seed produces the same data")
+	flag.Parse()
+
+	if *prs < 0 || *days <= 0 {
+		fmt.Fprintln(os.Stderr, "prs must not be negative and days must be positive")
+		os.Exit(2)
+	}
+
+	cfg := config.MustLoad()
+
+	log := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))
+
+	if err := migrator.RunMigrations(cfg.Postgres, log); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	storage := postgresql.Init(cfg.Postgres, log)
+	defer storage.Close()
+
+	dataset := seed.Generate(seed.Options{
+		Seed:         *rndSeed,
+		PullRequests: *prs,
+		Days:         *days,
+		Now:          time.Now(),
+	})
+
+	summary, err := repo.NewSeedRepo(storage.GetDB()).Apply(dataset)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	log.Info("demo data seeded",
+		slog.Int("teams", summary.Teams),
+		slog.Int("users", summary.Users),
+		slog.Int("pull_requests", summary.PullRequests),
+		slog.Int("skipped", summary.Skipped))
+}
@@ -3,13 +3,21 @@ package app
 import (
 	"context"
 	"log/slog"
+	"os"
 	"pull-request-assigner/internal/app/rest"
 	"pull-request-assigner/internal/config"
+	"pull-request-assigner/internal/domain/events"
 	v1 "pull-request-assigner/internal/http/v1"
+	"pull-request-assigner/internal/http/v1/middleware"
+	"pull-request-assigner/internal/lib/chaos"
+	"pull-request-assigner/internal/lib/forge"
+	"pull-request-assigner/internal/lib/logger/sl"
 	"pull-request-assigner/internal/lib/migrator"
+	"pull-request-assigner/internal/lib/siem"
 	"pull-request-assigner/internal/repo"
 	"pull-request-assigner/internal/service"
 	"pull-request-assigner/internal/storage/postgresql"
+	"sync"
 	"time"
 )
 
@@ -17,33 +25,190 @@ type App struct {
 	log     *slog.Logger
 	storage *postgresql.Storage
 	restApp *rest.App
+
+	usageService *service.UsageService
+	stopWorkers  context.CancelFunc
+	workers      sync.WaitGroup
 }
 
 func MustNew(log *slog.Logger) *App {
 	cfg := config.MustLoad()
 
-	if err := migrator.RunMigrations(cfg.Postgres, log); err != nil {
-		log.Error("failed to run migrations", "error", err)
+	if err := migrator.EnsureSchema(cfg.Postgres, cfg.Postgres.AutoMigrate, log); err != nil {
+		log.Error("failed to prepare database schema", "error", err)
 		panic(err)
 	}
 
-	storage := postgresql.Init(cfg.Postgres)
+	if cfg.Chaos.ErrorRate > 0 || cfg.Chaos.Latency > 0 {
+		if chaos.Enabled {
+			chaos.Configure(chaos.Config{ErrorRate: cfg.Chaos.ErrorRate, Latency: cfg.Chaos.Latency})
+			log.Warn("database fault injection enabled",
+				slog.Float64("error_rate", cfg.Chaos.ErrorRate),
+				slog.Duration("latency", cfg.Chaos.Latency))
+		} else {
+			log.Warn("chaos settings ignored: binary built without the chaos tag")
+		}
+	}
+
+	storage := postgresql.Init(cfg.Postgres, log)
 
 	userRepo := repo.NewUserRepo(storage.GetDB())
 	teamRepo := repo.NewTeamRepo(storage.GetDB())
 	pullRequestRepo := repo.NewPullRequestRepo(storage.GetDB())
 	statsRepo := repo.NewStatsRepo(storage.GetDB())
+	prStatusRepo := repo.NewPRStatusRepo(storage.GetDB())
+	archiveRepo := repo.NewArchiveRepo(storage.GetDB())
+	simulationRepo := repo.NewSimulationRepo(storage.GetDB())
+	dbCheckRepo := repo.NewDBCheckRepo(storage.GetDB())
+	usageRepo := repo.NewUsageRepo(storage.GetDB())
+	jobRepo := repo.NewJobRepo(storage.GetDB())
+	auditRepo := repo.NewAuditRepo(storage.GetDB())
+	certificationRepo := repo.NewCertificationRepo(storage.GetDB())
+	tokenRepo := repo.NewTokenRepo(storage.GetDB())
+	impersonationRepo := repo.NewImpersonationRepo(storage.GetDB())
+	freezeRepo := repo.NewFreezeRepo(storage.GetDB())
+	mergeWindowRepo := repo.NewMergeWindowRepo(storage.GetDB())
+	assignmentRepairRepo := repo.NewAssignmentRepairRepo(storage.GetDB())
+	poolRepo := repo.NewPoolRepo(storage.GetDB())
+	policyRepo := repo.NewPolicyRepo(storage.GetDB())
+	webhookRepo := repo.NewWebhookRepo(storage.GetDB())
+	nonceRepo := repo.NewNonceRepo(storage.GetDB())
+	activityRepo := repo.NewActivityRepo(storage.GetDB())
+	templateRepo := repo.NewNotificationTemplateRepo(storage.GetDB())
+	dashboardRepo := repo.NewDashboardRepo(storage.GetDB())
+	notifyRepo := repo.NewNotifyRepo(storage.GetDB())
+
+	bus := events.NewBus()
+	reviewWatcher := service.NewReviewWatcher()
+	bus.Subscribe(reviewWatcher.Handle)
+	bus.Subscribe(service.NewAuditSink(log, auditRepo))
+	bus.Subscribe(service.NewActivitySink(log, activityRepo))
+
+	candidateCache := service.NewCandidateCache(log, cfg.Review.CandidateCacheTTL, notifyRepo)
+	bus.Subscribe(candidateCache.Handle)
+
+	notificationTemplates := service.NewNotificationTemplates(log, templateRepo)
+	bus.Subscribe(notificationTemplates.Handle)
+
+	dashboardService := service.NewDashboardService(log, dashboardRepo, cfg.Review.SLA)
+	bus.Subscribe(dashboardService.Handle)
+
+	webhookHealth := service.NewIntegrationTracker(service.IntegrationWebhooks, true)
+	webhookService := service.NewWebhookService(log, webhookRepo, cfg.Webhook.Timeout, service.WebhookRetry{
+		MaxAttempts: cfg.Webhook.MaxAttempts,
+		Backoff:     cfg.Webhook.RetryBackoff,
+	}, webhookHealth, notificationTemplates, cfg.Review.SLA, cfg.Webhook.FailedRetention)
+	bus.Subscribe(webhookService.Handle)
 
-	userService := service.NewUserService(log, userRepo)
-	teamService := service.NewTeamService(log, teamRepo)
-	pullRequestService := service.NewPullRequestService(log, pullRequestRepo, teamRepo)
-	statsService := service.NewStatsService(log, statsRepo)
+	hostname, _ := os.Hostname()
+	siemShipper, err := siem.New(siem.Config{
+		Kind:     cfg.SIEM.Kind,
+		URL:      cfg.SIEM.URL,
+		Token:    cfg.SIEM.Token,
+		AppName:  cfg.SIEM.AppName,
+		Hostname: hostname,
+		Timeout:  cfg.SIEM.Timeout,
+	})
+	if err != nil {
+		log.Error("invalid siem configuration", sl.Err(err))
+		panic(err)
+	}
+	siemHealth := service.NewIntegrationTracker(service.IntegrationSIEM, siemShipper != nil)
+	var siemForwarder *service.SIEMForwarder
+	if siemShipper != nil {
+		siemForwarder = service.NewSIEMForwarder(log, siemShipper, service.SIEMBatching{
+			Size:     cfg.SIEM.BatchSize,
+			Interval: cfg.SIEM.FlushInterval,
+		}, service.WebhookRetry{
+			MaxAttempts: cfg.SIEM.MaxAttempts,
+			Backoff:     cfg.SIEM.RetryBackoff,
+		}, siemHealth)
+		bus.Subscribe(siemForwarder.Handle)
+	}
+
+	teamService := service.NewTeamService(log, teamRepo, auditRepo, bus)
+	pullRequestService := service.NewPullRequestService(log, pullRequestRepo, teamRepo, prStatusRepo, certificationRepo, freezeRepo, poolRepo, mergeWindowRepo, assignmentRepairRepo, service.SecurityReviewPolicy{
+		TeamName:     cfg.Security.Team,
+		Labels:       cfg.Security.Labels,
+		PathPrefixes: cfg.Security.Paths,
+	}, cfg.Review.MaxOpenReviews, candidateCache, cfg.Review.CreateLatencyBudget, bus)
+	userService := service.NewUserService(log, userRepo, bus, cfg.Review.SLA, cfg.Review.PRLinkTemplate, reviewWatcher, pullRequestService)
+	assignmentRepairService := service.NewAssignmentRepairService(log, assignmentRepairRepo, pullRequestService, cfg.Review.RepairInterval, cfg.Review.RepairAlertAttempts, bus)
+	statsService := service.NewStatsService(log, statsRepo, teamRepo)
+	adminService := service.NewAdminService(log, archiveRepo, userRepo, simulationRepo, dbCheckRepo, jobRepo, bus, cfg.Admin.Secret)
+	certificationService := service.NewCertificationService(log, certificationRepo)
+	poolService := service.NewPoolService(log, poolRepo)
+	policyService := service.NewPolicyService(log, policyRepo, bus)
+	tokenService := service.NewTokenService(log, tokenRepo, cfg.Admin.Secret)
+	impersonationService := service.NewImpersonationService(log, impersonationRepo, userRepo, bus, cfg.Admin.ImpersonationTTL)
+	usageService := service.NewUsageService(log, usageRepo, cfg.Usage.HourlyQuota)
+	adminSignatureService := service.NewAdminSignatureService(log, nonceRepo, cfg.Admin.SigningSecret, cfg.Admin.SignatureMaxSkew)
+	if !adminSignatureService.Enabled() {
+		log.Warn("admin request signing is disabled, set ADMIN_SIGNING_SECRET to require it")
+	}
+	forgeClient, err := forge.New(forge.Config{
+		Kind:             cfg.Forge.Kind,
+		BaseURL:          cfg.Forge.BaseURL,
+		Token:            cfg.Forge.Token,
+		Org:              cfg.Forge.Org,
+		Timeout:          cfg.Forge.Timeout,
+		MaxRateLimitWait: cfg.Forge.MaxRateLimitWait,
+	})
+	if err != nil {
+		log.Error("invalid forge configuration", sl.Err(err))
+		panic(err)
+	}
+	offboardingService := service.NewOffboardingService(log, userRepo, poolRepo, tokenRepo, webhookRepo, userService, pullRequestService, adminService, bus)
+	forgeHealth := service.NewIntegrationTracker(service.IntegrationForge, forgeClient != nil)
+	backfillService := service.NewBackfillService(log, forgeClient, userRepo, pullRequestService, forgeHealth)
+	activityService := service.NewActivityService(log, activityRepo)
+	templateService := service.NewNotificationTemplateService(log, templateRepo, bus)
+	forgeEventService := service.NewForgeEventService(log, cfg.Forge.Kind, cfg.Forge.WebhookSecret, userRepo, pullRequestService)
+	healthService := service.NewHealthService(log, storage.GetDB(), forgeHealth, webhookHealth, siemHealth)
+	metaService := service.NewMetaService(log, prStatusRepo)
+	fairnessService := service.NewFairnessService(
+		log,
+		statsRepo,
+		bus,
+		time.Duration(cfg.Fairness.WindowDays)*24*time.Hour,
+		cfg.Fairness.SkewThreshold,
+		cfg.Fairness.MinAssignments,
+	)
 
 	routerDependencies := v1.RouterDependencies{
-		UserService:        userService,
-		TeamService:        teamService,
-		PullRequestService: pullRequestService,
-		StatsService:       statsService,
+		UserService:          userService,
+		TeamService:          teamService,
+		PullRequestService:   pullRequestService,
+		StatsService:         statsService,
+		AdminService:         adminService,
+		UsageService:         usageService,
+		CertificationService: certificationService,
+		PoolService:          poolService,
+		PolicyService:        policyService,
+		TokenService:         tokenService,
+		ImpersonationService: impersonationService,
+		WebhookService:       webhookService,
+		TemplateService:      templateService,
+		RepairService:        assignmentRepairService,
+		DashboardService:     dashboardService,
+		AdminSignatures:      adminSignatureService,
+		BackfillService:      backfillService,
+		OffboardingService:   offboardingService,
+		ActivityService:      activityService,
+		HealthService:        healthService,
+		MetaService:          metaService,
+		ForgeEventService:    forgeEventService,
+		CreatePRLimiter: middleware.NewConcurrencyLimiter(
+			cfg.Server.CreatePRConcurrency,
+			cfg.Server.CreatePRQueueTimeout,
+			log,
+		),
+		AuthRequired: cfg.Auth.Required,
+		AdminEnabled: cfg.Admin.SecretConfigured(),
+	}
+
+	if !routerDependencies.AdminEnabled {
+		log.Error("admin routes are disabled: set ADMIN_SECRET to a random value")
 	}
 
 	restApp := rest.New(
@@ -52,11 +217,58 @@ func MustNew(log *slog.Logger) *App {
 		cfg.Server.Port,
 	)
 
-	return &App{
-		log:     log,
-		storage: storage,
-		restApp: restApp,
+	scheduler := service.NewScheduler(log, jobRepo)
+	scheduler.Register("usage_flush", cfg.Usage.FlushInterval, usageService.Flush)
+	scheduler.Register("assignment_skew", cfg.Fairness.CheckInterval, fairnessService.CheckAssignmentSkew)
+	scheduler.Register("membership_repair", cfg.Admin.MembershipRepairInterval, adminService.RepairMembership)
+	scheduler.Register("auto_merge", cfg.Review.AutoMergeInterval, pullRequestService.AutoMerge)
+	scheduler.Register("stats_snapshot", cfg.Stats.SnapshotInterval, statsService.SnapshotStats)
+	scheduler.Register("freeze_release", cfg.Admin.FreezeReleaseInterval, pullRequestService.ReleaseQueued)
+	scheduler.Register("assignment_repair", cfg.Review.RepairInterval, assignmentRepairService.RepairAssignments)
+	scheduler.Register("template_reload", cfg.Webhook.TemplateReloadInterval, notificationTemplates.Reload)
+	scheduler.Register("dashboard_rebuild", cfg.Review.DashboardRebuildInterval, dashboardService.Rebuild)
+	scheduler.Register("webhook_failure_purge", cfg.Webhook.FailedPurgeInterval, webhookService.PurgeFailedDeliveries)
+	if adminSignatureService.Enabled() {
+		scheduler.Register("admin_nonce_purge", cfg.Admin.NoncePurgeInterval, adminSignatureService.PurgeNonces)
+	}
+
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+
+	app := &App{
+		log:          log,
+		storage:      storage,
+		restApp:      restApp,
+		usageService: usageService,
+		stopWorkers:  stopWorkers,
 	}
+
+	app.workers.Add(1)
+	go func() {
+		defer app.workers.Done()
+		scheduler.Run(workersCtx)
+	}()
+
+	app.workers.Add(1)
+	go func() {
+		defer app.workers.Done()
+		webhookService.Run(workersCtx)
+	}()
+
+	app.workers.Add(1)
+	go func() {
+		defer app.workers.Done()
+		storage.Listen(workersCtx, service.CandidateCacheChannel, candidateCache.HandleNotification)
+	}()
+
+	if siemForwarder != nil {
+		app.workers.Add(1)
+		go func() {
+			defer app.workers.Done()
+			siemForwarder.Run(workersCtx)
+		}()
+	}
+
+	return app
 }
 
 func (a *App) MustRun() {
@@ -75,7 +287,14 @@ func (a *App) GracefulShutdown() {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 	if err := a.restApp.Stop(ctx); err != nil {
-		a.log.Error("failed to stop HTTP server", err)
+		a.log.Error("failed to stop HTTP server", sl.Err(err))
+	}
+
+	a.stopWorkers()
+	a.workers.Wait()
+
+	if err := a.usageService.Flush(ctx); err != nil {
+		a.log.Error("failed to flush usage", sl.Err(err))
 	}
 
 	if a.storage != nil {
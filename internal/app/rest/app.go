@@ -2,6 +2,7 @@ package rest
 
 import (
 	"context"
+	"expvar"
 	"github.com/go-chi/chi/v5"
 	"log/slog"
 	"net/http"
@@ -22,6 +23,7 @@ func New(
 	r := chi.NewRouter()
 
 	v1.SetupRoutes(r, deps, log)
+	r.Handle("/debug/vars", expvar.Handler())
 
 	httpServer := &http.Server{
 		Addr:    ":" + port,
@@ -0,0 +1,10 @@
+package apperrors
+
+import "errors"
+
+var (
+	ErrAreaRequired              = errors.New("certification area is required")
+	ErrCertificationNotFound     = errors.New("certification not found")
+	ErrNoCertifiedReviewer       = errors.New("no active certified reviewer available")
+	ErrCertifiedReviewerRequired = errors.New("PR must keep a certified reviewer for each required area")
+)
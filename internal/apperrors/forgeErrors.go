@@ -0,0 +1,9 @@
+package apperrors
+
+import "errors"
+
+var (
+	ErrForgeNotConfigured = errors.New("forge is not configured")
+	ErrForgeUnavailable   = errors.New("forge request failed")
+	ErrInvalidForgeEvent  = errors.New("malformed forge webhook payload")
+)
@@ -0,0 +1,12 @@
+package apperrors
+
+import "errors"
+
+var (
+	ErrImpersonatorRequired        = errors.New("admin identity is required to impersonate")
+	ErrImpersonationReasonRequired = errors.New("impersonation reason is required")
+	ErrSelfImpersonation           = errors.New("admin cannot impersonate themselves")
+	ErrImpersonationNotFound       = errors.New("impersonation session not found")
+	ErrInvalidImpersonation        = errors.New("invalid or expired impersonation session")
+	ErrImpersonationReadOnly       = errors.New("impersonation sessions are read-only")
+)
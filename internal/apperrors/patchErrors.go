@@ -0,0 +1,8 @@
+package apperrors
+
+import "errors"
+
+var (
+	ErrPreconditionFailed = errors.New("resource was modified since it was read")
+	ErrUsernameRequired   = errors.New("username is required")
+)
@@ -0,0 +1,10 @@
+package apperrors
+
+import "errors"
+
+var (
+	ErrPoolNameRequired  = errors.New("pool name is required")
+	ErrPoolNotFound      = errors.New("reviewer pool not found")
+	ErrPoolExists        = errors.New("reviewer pool already exists")
+	ErrInvalidPoolPolicy = errors.New("reviewers per PR must be between 1 and 5")
+)
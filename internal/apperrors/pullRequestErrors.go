@@ -8,10 +8,49 @@ var (
 	ErrPRAuthorNotFound     = errors.New("PR author not found")
 	ErrPRTeamNotFound       = errors.New("PR author team not found")
 	ErrPRAlreadyMerged      = errors.New("PR already merged")
+	ErrPRClosed             = errors.New("PR is closed")
 	ErrReviewerNotAssigned  = errors.New("reviewer is not assigned to this PR")
 	ErrNoReviewerCandidates = errors.New("no active replacement candidate in team")
 	ErrPRIDRequired         = errors.New("pull request id is required")
 	ErrPRNameRequired       = errors.New("pull request name is required")
 	ErrAuthorRequired       = errors.New("author id is required")
 	ErrOldReviewerRequired  = errors.New("old reviewer id is required")
+	ErrReasonRequired       = errors.New("reassignment reason is required")
+	ErrInvalidReason        = errors.New("invalid reassignment reason")
+	ErrHandoffNoteTooLong   = errors.New("hand-off note is too long")
+	ErrInvalidCIStatus      = errors.New("invalid ci status")
+	ErrPRStatusRequired     = errors.New("pull request status is required")
+	ErrUnknownPRStatus      = errors.New("unknown pull request status")
+	ErrInvalidPRTransition  = errors.New("pull request status transition is not allowed")
+	ErrPRStatusInUse        = errors.New("pull request status is in use")
+	ErrBuiltinPRStatus      = errors.New("OPEN and MERGED cannot be changed")
+	ErrInvalidStatusMachine = errors.New("invalid pull request status transitions")
+	ErrUnknownStrategy      = errors.New("unknown assignment strategy")
+	ErrInvalidPriority      = errors.New("invalid pull request priority")
+	ErrReviewerRequired     = errors.New("reviewer id is required")
+	ErrReviewerIsAuthor     = errors.New("author cannot review own PR")
+	ErrReviewerNotInTeam    = errors.New("reviewer is not a member of the author's team")
+	ErrReviewerInactive     = errors.New("reviewer is inactive")
+	ErrReviewerAuthorOnly   = errors.New("reviewer is author-only")
+	ErrReviewerAssigned     = errors.New("reviewer is already assigned to this PR")
+	ErrPRHasReviewers       = errors.New("PR already has reviewers")
+	ErrBelowMinReviewers    = errors.New("PR would have fewer reviewers than the team minimum")
+	ErrInvalidReviewerTeams = errors.New("invalid reviewer teams")
+	ErrReviewerTeamNotFound = errors.New("reviewer team not found")
+	ErrInvalidAutoMerge     = errors.New("invalid auto-merge approvals")
+	ErrInvalidReconcile     = errors.New("invalid status reconciliation")
+	ErrInvalidPRSearch      = errors.New("invalid pull request search")
+	ErrInvalidReviewerCount = errors.New("invalid reviewer count")
+	ErrInvalidDashboardPage = errors.New("invalid dashboard page")
+	ErrMergeWindowClosed    = errors.New("merging is blocked by the team's merge window")
+	ErrMergeOverrideDenied  = errors.New("only admins may override the merge window")
+	ErrInvalidMergeWindow   = errors.New("invalid merge window")
+
+	ErrNoSecurityReviewer       = errors.New("no active security team reviewer available")
+	ErrSecurityReviewerRequired = errors.New("PR must keep a security team reviewer")
+	ErrSecurityApprovalRequired = errors.New("PR requires approval from a security team reviewer")
+
+	ErrDelegateRequired    = errors.New("delegate id is required")
+	ErrDelegateNotTeammate = errors.New("delegate is not a member of the reviewer's team")
+	ErrDelegateAtCapacity  = errors.New("delegate has reached the open review limit")
 )
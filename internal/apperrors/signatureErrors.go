@@ -0,0 +1,9 @@
+package apperrors
+
+import "errors"
+
+var (
+	ErrInvalidSignature = errors.New("invalid request signature")
+	ErrStaleRequest     = errors.New("request timestamp outside the allowed window")
+	ErrReplayedRequest  = errors.New("request nonce already used")
+)
@@ -0,0 +1,8 @@
+package apperrors
+
+import "errors"
+
+var (
+	ErrInvalidStatsWindow = errors.New("invalid stats window")
+	ErrStatsForbidden     = errors.New("stats are not visible to the caller")
+)
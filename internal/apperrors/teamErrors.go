@@ -7,4 +7,11 @@ var (
 	ErrTeamNotFound     = errors.New("team not found")
 	ErrTeamNameRequired = errors.New("team name is required")
 	ErrMembersRequired  = errors.New("team must have at least one member")
+	ErrTeamNotArchived  = errors.New("team is not archived")
+	ErrTooManyTeams     = errors.New("too many teams requested")
+	ErrInvalidPolicy    = errors.New("invalid team policy")
+	ErrInvalidFreeze    = errors.New("invalid assignment freeze window")
+
+	ErrPrimaryTeam     = errors.New("team is the user's primary team")
+	ErrAuthorNotInTeam = errors.New("author is not a member of the team")
 )
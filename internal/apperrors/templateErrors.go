@@ -0,0 +1,8 @@
+package apperrors
+
+import "errors"
+
+var (
+	ErrTemplateNotFound = errors.New("notification template not found")
+	ErrInvalidTemplate  = errors.New("invalid notification template")
+)
@@ -0,0 +1,19 @@
+package apperrors
+
+import "errors"
+
+var (
+	ErrTokenNameRequired  = errors.New("token name is required")
+	ErrTokenUserRequired  = errors.New("token user is required")
+	ErrTokenScopeRequired = errors.New("at least one token scope is required")
+	ErrInvalidTokenScope  = errors.New("invalid token scope")
+	ErrTokenExpiryInPast  = errors.New("token expiry must be in the future")
+	ErrInvalidTokenQuota  = errors.New("token hourly quota must not be negative")
+	ErrTokenNotFound      = errors.New("token not found")
+	ErrTokenRevoked       = errors.New("token is revoked")
+	ErrInvalidToken       = errors.New("invalid or expired API key")
+	ErrTokenRequired      = errors.New("API key is required")
+	ErrInsufficientScope  = errors.New("API key lacks the required scope")
+	ErrInvalidBootstrap   = errors.New("invalid bootstrap secret")
+	ErrBootstrapClosed    = errors.New("an admin API key already exists")
+)
@@ -0,0 +1,7 @@
+package apperrors
+
+import "errors"
+
+var (
+	ErrInvalidUsageQuery = errors.New("invalid usage query")
+)
@@ -3,6 +3,16 @@ package apperrors
 import "errors"
 
 var (
-	ErrUserNotFound  = errors.New("user not found")
-	ErrInvalidUserID = errors.New("invalid user_id format")
+	ErrUserNotFound        = errors.New("user not found")
+	ErrInvalidUserID       = errors.New("invalid user_id format")
+	ErrUserActive          = errors.New("user is still active")
+	ErrUserAnonymized      = errors.New("user is already anonymized")
+	ErrInvalidConfirmation = errors.New("invalid confirmation token")
+	ErrUserIDsRequired     = errors.New("at least one user_id is required")
+	ErrBatchTooLarge       = errors.New("batch is too large")
+	ErrInvalidWait         = errors.New("invalid wait duration")
+	ErrInvalidProfile      = errors.New("invalid user profile")
+	ErrInvalidDailyCap     = errors.New("invalid daily assignment cap")
+	ErrInvalidAuthorOnly   = errors.New("invalid author-only period")
+	ErrInvalidQueueOrder   = errors.New("invalid review queue order")
 )
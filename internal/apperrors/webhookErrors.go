@@ -0,0 +1,9 @@
+package apperrors
+
+import "errors"
+
+var (
+	ErrWebhookNotFound = errors.New("team webhook not found")
+	ErrWebhookExists   = errors.New("team webhook already exists")
+	ErrInvalidWebhook  = errors.New("invalid team webhook")
+)
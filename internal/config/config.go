@@ -9,11 +9,25 @@ type Config struct {
 	Env      string         `env:"ENV" env-default:"dev"`
 	Server   HTTPServer     `env-prefix:"SERVER_"`
 	Postgres PostgresConfig `env-prefix:"PG_"`
+	Admin    AdminConfig    `env-prefix:"ADMIN_"`
+	Review   ReviewConfig   `env-prefix:"REVIEW_"`
+	Usage    UsageConfig    `env-prefix:"USAGE_"`
+	Fairness FairnessConfig `env-prefix:"FAIRNESS_"`
+	Stats    StatsConfig    `env-prefix:"STATS_"`
+	Security SecurityConfig `env-prefix:"SECURITY_"`
+	Auth     AuthConfig     `env-prefix:"AUTH_"`
+	Chaos    ChaosConfig    `env-prefix:"CHAOS_"`
+	Webhook  WebhookConfig  `env-prefix:"WEBHOOK_"`
+	Forge    ForgeConfig    `env-prefix:"FORGE_"`
+	SIEM     SIEMConfig     `env-prefix:"SIEM_"`
 }
 
 type HTTPServer struct {
 	Port    string        `env:"PORT" env-default:"8080"`
 	Timeout time.Duration `env:"TIMEOUT" env-default:"5s"`
+
+	CreatePRConcurrency  int           `env:"CREATE_PR_CONCURRENCY" env-default:"32"`
+	CreatePRQueueTimeout time.Duration `env:"CREATE_PR_QUEUE_TIMEOUT" env-default:"200ms"`
 }
 
 type PostgresConfig struct {
@@ -23,6 +37,135 @@ type PostgresConfig struct {
 	Password string `env:"PASSWORD" env-default:"postgres"`
 	DbName   string `env:"DBNAME" env-default:"pullrequest_db"`
 	SslMode  string `env:"SSLMODE" env-default:"disable"`
+
+	SlowQueryThreshold time.Duration `env:"SLOW_QUERY_THRESHOLD" env-default:"200ms"`
+
+	// AutoMigrate applies pending migrations at startup. Disable it when
+	// migrations are run separately ahead of a blue/green rollout.
+	AutoMigrate bool `env:"AUTO_MIGRATE" env-default:"true"`
+}
+
+// DefaultAdminSecret is the placeholder ADMIN_SECRET ships with. The admin
+// routes stay disabled until it is replaced.
+const DefaultAdminSecret = "change-me"
+
+type AdminConfig struct {
+	Secret           string        `env:"SECRET" env-default:"change-me"`
+	ImpersonationTTL time.Duration `env:"IMPERSONATION_TTL" env-default:"30m"`
+
+	MembershipRepairInterval time.Duration `env:"MEMBERSHIP_REPAIR_INTERVAL" env-default:"1h"`
+	FreezeReleaseInterval    time.Duration `env:"FREEZE_RELEASE_INTERVAL" env-default:"1m"`
+
+	// SigningSecret makes admin mutations require a signed request with a
+	// timestamp within SignatureMaxSkew and an unused nonce; empty disables
+	// the check.
+	SigningSecret      string        `env:"SIGNING_SECRET" env-default:""`
+	SignatureMaxSkew   time.Duration `env:"SIGNATURE_MAX_SKEW" env-default:"5m"`
+	NoncePurgeInterval time.Duration `env:"NONCE_PURGE_INTERVAL" env-default:"10m"`
+}
+
+type ReviewConfig struct {
+	SLA            time.Duration `env:"SLA" env-default:"24h"`
+	PRLinkTemplate string        `env:"PR_LINK_TEMPLATE" env-default:""`
+	MaxOpenReviews int           `env:"MAX_OPEN_REVIEWS" env-default:"0"`
+
+	AutoMergeInterval time.Duration `env:"AUTO_MERGE_INTERVAL" env-default:"1m"`
+
+	// CandidateCacheTTL is how long the active members of a team are reused
+	// when picking reviewers; zero disables the cache. CreateLatencyBudget
+	// is the create-and-assign time above which a warning is logged.
+	CandidateCacheTTL   time.Duration `env:"CANDIDATE_CACHE_TTL" env-default:"5s"`
+	CreateLatencyBudget time.Duration `env:"CREATE_LATENCY_BUDGET" env-default:"150ms"`
+
+	// DashboardRebuildInterval is how often the dashboard read model is
+	// rebuilt to pick up changes made without an event.
+	DashboardRebuildInterval time.Duration `env:"DASHBOARD_REBUILD_INTERVAL" env-default:"15m"`
+
+	// RepairInterval is how often PRs left without reviewers by a failed
+	// assignment are retried, and the first backoff of a failing one.
+	// RepairAlertAttempts failed retries alert admins.
+	RepairInterval      time.Duration `env:"REPAIR_INTERVAL" env-default:"1m"`
+	RepairAlertAttempts int           `env:"REPAIR_ALERT_ATTEMPTS" env-default:"5"`
+}
+
+type UsageConfig struct {
+	HourlyQuota   int           `env:"HOURLY_QUOTA" env-default:"0"`
+	FlushInterval time.Duration `env:"FLUSH_INTERVAL" env-default:"10s"`
+}
+
+type FairnessConfig struct {
+	CheckInterval  time.Duration `env:"CHECK_INTERVAL" env-default:"1h"`
+	WindowDays     int           `env:"WINDOW_DAYS" env-default:"14"`
+	SkewThreshold  float64       `env:"SKEW_THRESHOLD" env-default:"0.5"`
+	MinAssignments int           `env:"MIN_ASSIGNMENTS" env-default:"10"`
+}
+
+type StatsConfig struct {
+	SnapshotInterval time.Duration `env:"SNAPSHOT_INTERVAL" env-default:"24h"`
+}
+
+type SecurityConfig struct {
+	Team   string   `env:"TEAM" env-default:""`
+	Labels []string `env:"LABELS" env-default:"security" env-separator:","`
+	Paths  []string `env:"PATHS" env-separator:","`
+}
+
+// WebhookConfig tunes team webhook deliveries. A failed delivery is tried up
+// to MaxAttempts times in total, RetryBackoff apart and doubling.
+// TemplateReloadInterval bounds how long a notification template saved
+// through another instance takes to reach this one. Deliveries that finally
+// failed are kept for FailedRetention and purged every FailedPurgeInterval.
+type WebhookConfig struct {
+	Timeout                time.Duration `env:"TIMEOUT" env-default:"5s"`
+	MaxAttempts            int           `env:"MAX_ATTEMPTS" env-default:"4"`
+	RetryBackoff           time.Duration `env:"RETRY_BACKOFF" env-default:"10s"`
+	TemplateReloadInterval time.Duration `env:"TEMPLATE_RELOAD_INTERVAL" env-default:"1m"`
+	FailedRetention        time.Duration `env:"FAILED_RETENTION" env-default:"168h"`
+	FailedPurgeInterval    time.Duration `env:"FAILED_PURGE_INTERVAL" env-default:"1h"`
+}
+
+// ForgeConfig points POST /admin/backfill at a GitHub organization or a
+// GitLab group; an empty Kind disables the import. WebhookSecret verifies
+// the deliveries to POST /forge/events; empty refuses them.
+type ForgeConfig struct {
+	Kind    string        `env:"KIND" env-default:""`
+	BaseURL string        `env:"BASE_URL" env-default:""`
+	Token   string        `env:"TOKEN" env-default:""`
+	Org     string        `env:"ORG" env-default:""`
+	Timeout time.Duration `env:"TIMEOUT" env-default:"10s"`
+
+	MaxRateLimitWait time.Duration `env:"MAX_RATE_LIMIT_WAIT" env-default:"1m"`
+
+	WebhookSecret string `env:"WEBHOOK_SECRET" env-default:""`
+}
+
+// SIEMConfig forwards audit entries to a SIEM next to the local audit log:
+// Kind "http" posts JSON arrays to URL with Token as a bearer token, Kind
+// "syslog" writes RFC 5424 messages to a udp:// or tcp:// URL; empty
+// disables forwarding. Entries are batched up to BatchSize or FlushInterval,
+// and a failed batch is tried up to MaxAttempts times, RetryBackoff apart
+// and doubling.
+type SIEMConfig struct {
+	Kind    string        `env:"KIND" env-default:""`
+	URL     string        `env:"URL" env-default:""`
+	Token   string        `env:"TOKEN" env-default:""`
+	AppName string        `env:"APP_NAME" env-default:"pull-request-assigner"`
+	Timeout time.Duration `env:"TIMEOUT" env-default:"5s"`
+
+	BatchSize     int           `env:"BATCH_SIZE" env-default:"100"`
+	FlushInterval time.Duration `env:"FLUSH_INTERVAL" env-default:"5s"`
+	MaxAttempts   int           `env:"MAX_ATTEMPTS" env-default:"5"`
+	RetryBackoff  time.Duration `env:"RETRY_BACKOFF" env-default:"2s"`
+}
+
+// SecretConfigured reports whether ADMIN_SECRET was set to something other
+// than the placeholder.
+func (c AdminConfig) SecretConfigured() bool {
+	return c.Secret != "" && c.Secret != DefaultAdminSecret
+}
+
+type AuthConfig struct {
+	Required bool `env:"REQUIRED" env-default:"false"`
 }
 
 func MustLoad() *Config {
@@ -34,3 +177,10 @@ func MustLoad() *Config {
 
 	return &cfg
 }
+
+// ChaosConfig injects database faults. It only has an effect in binaries
+// built with the chaos build tag.
+type ChaosConfig struct {
+	ErrorRate float64       `env:"ERROR_RATE" env-default:"0"`
+	Latency   time.Duration `env:"LATENCY" env-default:"0s"`
+}
@@ -0,0 +1,50 @@
+package events
+
+import (
+	"context"
+	"sync"
+)
+
+type Handler func(ctx context.Context, event Event)
+
+type Publisher interface {
+	Publish(ctx context.Context, event Event)
+}
+
+type Bus interface {
+	Publisher
+	Subscribe(handler Handler)
+}
+
+// InProcessBus delivers events synchronously, in subscription order, on the
+// publisher's goroutine. Handlers must not block.
+type InProcessBus struct {
+	mu       sync.RWMutex
+	handlers []Handler
+}
+
+func NewBus() *InProcessBus {
+	return &InProcessBus{}
+}
+
+func (b *InProcessBus) Subscribe(handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.handlers = append(b.handlers, handler)
+}
+
+// Publish hands the event to every subscriber. A nil bus drops events.
+func (b *InProcessBus) Publish(ctx context.Context, event Event) {
+	if b == nil {
+		return
+	}
+
+	b.mu.RLock()
+	handlers := b.handlers
+	b.mu.RUnlock()
+
+	for _, handler := range handlers {
+		handler(ctx, event)
+	}
+}
@@ -0,0 +1,159 @@
+package events
+
+import (
+	"pull-request-assigner/internal/domain/models"
+	"time"
+)
+
+const (
+	NamePullRequestCreated          = "pull_request.created"
+	NamePullRequestMerged           = "pull_request.merged"
+	NamePullRequestAutoMerged       = "pull_request.auto_merged"
+	NamePullRequestMergedExternally = "pull_request.merged_externally"
+	NamePullRequestStatusChanged    = "pull_request.status_changed"
+	NamePullRequestCIStatusChanged  = "pull_request.ci_status_changed"
+	NameReviewersReleased           = "pull_request.reviewers_released"
+	NameReviewerReassigned          = "review.reassigned"
+	NameReviewerAssigned            = "review.assigned"
+	NameReviewerDelegated           = "review.delegated"
+	NameReviewerUnassigned          = "review.unassigned"
+	NameReviewStarted               = "review.started"
+	NameReviewCompleted             = "review.completed"
+	NameReviewApproved              = "review.approved"
+	NameAuditRecorded               = "audit.recorded"
+)
+
+// Event is a fact published by a service after the change is persisted.
+type Event interface {
+	Name() string
+}
+
+type PullRequestCreated struct {
+	PullRequestID string
+	AuthorID      string
+	Reviewers     []string
+	CreatedAt     time.Time
+}
+
+func (PullRequestCreated) Name() string { return NamePullRequestCreated }
+
+type PullRequestMerged struct {
+	PullRequestID string
+	Reviewers     []string
+	MergedBy      string
+	MergedAt      time.Time
+}
+
+func (PullRequestMerged) Name() string { return NamePullRequestMerged }
+
+// PullRequestAutoMerged follows PullRequestMerged when the auto-merge job did
+// the merge, so the author can be told nobody clicked the button.
+type PullRequestAutoMerged struct {
+	PullRequestID string
+	AuthorID      string
+	Approvals     int
+	MergedAt      time.Time
+}
+
+func (PullRequestAutoMerged) Name() string { return NamePullRequestAutoMerged }
+
+// PullRequestMergedExternally follows PullRequestMerged when the merge was
+// reported by a forge webhook. MergedByLogin is the forge account, which may
+// not match any user.
+type PullRequestMergedExternally struct {
+	PullRequestID string
+	MergedByLogin string
+}
+
+func (PullRequestMergedExternally) Name() string { return NamePullRequestMergedExternally }
+
+type PullRequestStatusChanged struct {
+	PullRequestID string
+	Status        string
+	Reviewers     []string
+}
+
+func (PullRequestStatusChanged) Name() string { return NamePullRequestStatusChanged }
+
+type PullRequestCIStatusChanged struct {
+	PullRequestID string
+	CIStatus      string
+}
+
+func (PullRequestCIStatusChanged) Name() string { return NamePullRequestCIStatusChanged }
+
+// ReviewersReleased is published when reviewers held back until green CI
+// are finally assigned.
+type ReviewersReleased struct {
+	PullRequestID string
+	Reviewers     []string
+}
+
+func (ReviewersReleased) Name() string { return NameReviewersReleased }
+
+type ReviewerReassigned struct {
+	PullRequestID string
+	OldReviewerID string
+	NewReviewerID string
+	Reason        string
+	Note          string
+}
+
+func (ReviewerReassigned) Name() string { return NameReviewerReassigned }
+
+type ReviewerAssigned struct {
+	PullRequestID     string
+	ReviewerID        string
+	ReplaceReviewerID string
+	ActorID           string
+}
+
+func (ReviewerAssigned) Name() string { return NameReviewerAssigned }
+
+type ReviewerDelegated struct {
+	PullRequestID string
+	ReviewerID    string
+	DelegateID    string
+}
+
+func (ReviewerDelegated) Name() string { return NameReviewerDelegated }
+
+type ReviewerUnassigned struct {
+	PullRequestID string
+	ReviewerID    string
+	ActorID       string
+}
+
+func (ReviewerUnassigned) Name() string { return NameReviewerUnassigned }
+
+type ReviewStarted struct {
+	PullRequestID string
+	ReviewerID    string
+	StartedAt     time.Time
+}
+
+func (ReviewStarted) Name() string { return NameReviewStarted }
+
+type ReviewCompleted struct {
+	PullRequestID string
+	ReviewerID    string
+	CompletedAt   time.Time
+}
+
+func (ReviewCompleted) Name() string { return NameReviewCompleted }
+
+type ReviewApproved struct {
+	PullRequestID string
+	ReviewerID    string
+	ApprovedAt    time.Time
+}
+
+func (ReviewApproved) Name() string { return NameReviewApproved }
+
+// AuditRecorded carries an audit entry; the actor is already resolved by the
+// publisher.
+type AuditRecorded struct {
+	Entry models.AuditEvent
+}
+
+func (AuditRecorded) Name() string { return NameAuditRecorded }
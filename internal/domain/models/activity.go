@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// Kinds of PR activity feed entries.
+const (
+	ActivityPRCreated          = "PR_CREATED"
+	ActivityReviewerAssigned   = "REVIEWER_ASSIGNED"
+	ActivityReviewerUnassigned = "REVIEWER_UNASSIGNED"
+	ActivityReviewStarted      = "REVIEW_STARTED"
+	ActivityReviewCompleted    = "REVIEW_COMPLETED"
+	ActivityApproved           = "APPROVED"
+	ActivityStatusChanged      = "STATUS_CHANGED"
+	ActivityMerged             = "MERGED"
+	ActivityAutoMerged         = "AUTO_MERGED"
+	ActivityMergedExternally   = "MERGED_EXTERNALLY"
+	ActivityNotificationSent   = "NOTIFICATION_SENT"
+	ActivityNotificationFailed = "NOTIFICATION_FAILED"
+)
+
+// PRActivity is one entry of a PR's activity feed. UserID is the user the
+// entry is about, such as the assigned reviewer; ActorID is who caused it,
+// when known.
+type PRActivity struct {
+	Kind       string    `db:"kind" json:"kind"`
+	OccurredAt time.Time `db:"occurred_at" json:"occurred_at"`
+	UserID     string    `db:"user_id" json:"user_id,omitempty"`
+	ActorID    string    `db:"actor_id" json:"actor_id,omitempty"`
+	Details    string    `db:"details" json:"details,omitempty"`
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+type ArchivedTeam struct {
+	TeamName     string    `db:"team_name" json:"team_name"`
+	ArchivedAt   time.Time `db:"archived_at" json:"archived_at"`
+	MemberCount  int       `db:"member_count" json:"member_count"`
+	AuthoredPRs  int       `db:"authored_prs" json:"authored_prs"`
+	ReviewsCount int       `db:"reviews_count" json:"reviews_count"`
+}
+
+type ArchivedUser struct {
+	UserID       string `db:"user_id" json:"user_id"`
+	Username     string `db:"username" json:"username"`
+	TeamName     string `db:"team_name" json:"team_name"`
+	AuthoredPRs  int    `db:"authored_prs" json:"authored_prs"`
+	ReviewsCount int    `db:"reviews_count" json:"reviews_count"`
+}
+
+type AnonymizationResult struct {
+	UserID            string `json:"user_id"`
+	ConfirmationToken string `json:"confirmation_token,omitempty"`
+	Pseudonym         string `json:"pseudonym,omitempty"`
+	Anonymized        bool   `json:"anonymized"`
+	PurgedDeliveries  int    `json:"purged_deliveries,omitempty"`
+}
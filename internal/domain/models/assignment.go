@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+const (
+	AssignmentActionAuto     = "AUTO"
+	AssignmentActionManual   = "MANUAL"
+	AssignmentActionReassign = "REASSIGN"
+	AssignmentActionUnassign = "UNASSIGN"
+	AssignmentActionDelegate = "DELEGATE"
+)
+
+// Reassignment reasons explain why a reviewer was swapped out.
+const (
+	ReassignReasonVacation    = "VACATION"
+	ReassignReasonOverloaded  = "OVERLOADED"
+	ReassignReasonConflict    = "CONFLICT"
+	ReassignReasonDeclined    = "DECLINED"
+	ReassignReasonManual      = "MANUAL"
+	ReassignReasonOffboarded  = "OFFBOARDED"
+	ReassignReasonDeactivated = "DEACTIVATED"
+)
+
+// MaxHandoffNoteLength bounds the note a reassignment passes to the new
+// reviewer, in characters.
+const MaxHandoffNoteLength = 1000
+
+func IsValidReassignReason(reason string) bool {
+	switch reason {
+	case ReassignReasonVacation, ReassignReasonOverloaded, ReassignReasonConflict, ReassignReasonDeclined, ReassignReasonManual, ReassignReasonOffboarded, ReassignReasonDeactivated:
+		return true
+	}
+	return false
+}
+
+type AssignmentHistoryEntry struct {
+	PullRequestId string    `db:"pull_request_id" json:"pull_request_id"`
+	ReviewerID    string    `db:"reviewer_id" json:"reviewer_id"`
+	Action        string    `db:"action" json:"action"`
+	Reason        *string   `db:"reason" json:"reason,omitempty"`
+	ActorID       *string   `db:"actor_id" json:"actor_id,omitempty"`
+	CreatedAt     time.Time `db:"created_at" json:"created_at"`
+}
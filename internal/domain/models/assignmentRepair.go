@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// AssignmentRepair is a PR stored without its reviewers because assigning
+// them failed. A worker retries the assignment until it succeeds or the PR
+// is closed; AlertedAt is set once admins were alerted about it.
+type AssignmentRepair struct {
+	PullRequestID string     `db:"pull_request_id" json:"pull_request_id"`
+	TeamName      string     `db:"team_name" json:"team_name,omitempty"`
+	Attempts      int        `db:"attempts" json:"attempts"`
+	LastError     string     `db:"last_error" json:"last_error,omitempty"`
+	NextAttemptAt time.Time  `db:"next_attempt_at" json:"next_attempt_at"`
+	AlertedAt     *time.Time `db:"alerted_at" json:"alerted_at,omitempty"`
+	CreatedAt     time.Time  `db:"created_at" json:"created_at"`
+}
+
+// AssignmentRepairReport lists the PRs a repair run assigned reviewers to
+// and the repairs that failed again.
+type AssignmentRepairReport struct {
+	Repaired []string           `json:"repaired_pull_requests"`
+	Failed   []AssignmentRepair `json:"failed"`
+}
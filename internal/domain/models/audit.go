@@ -0,0 +1,51 @@
+package models
+
+import "time"
+
+const (
+	AuditTeamCreated       = "TEAM_CREATED"
+	AuditTeamDeactivated   = "TEAM_DEACTIVATED"
+	AuditTeamArchived      = "TEAM_ARCHIVED"
+	AuditTeamRestored      = "TEAM_RESTORED"
+	AuditMemberAdded       = "MEMBER_ADDED"
+	AuditMemberRemoved     = "MEMBER_REMOVED"
+	AuditMemberActivated   = "MEMBER_ACTIVATED"
+	AuditMemberDeactivated = "MEMBER_DEACTIVATED"
+	AuditMemberOffboarded  = "MEMBER_OFFBOARDED"
+	AuditMemberAuthorOnly  = "MEMBER_AUTHOR_ONLY"
+	AuditMemberReviewing   = "MEMBER_REVIEWING"
+	AuditUserAnonymized    = "USER_ANONYMIZED"
+	AuditPolicyChanged     = "POLICY_CHANGED"
+	AuditOrgPolicyChanged  = "ORG_POLICY_CHANGED"
+	AuditAssignmentSkew    = "ASSIGNMENT_SKEW"
+
+	AuditImpersonationStarted = "IMPERSONATION_STARTED"
+	AuditImpersonationEnded   = "IMPERSONATION_ENDED"
+
+	AuditAssignmentFrozen   = "ASSIGNMENT_FROZEN"
+	AuditAssignmentUnfrozen = "ASSIGNMENT_UNFROZEN"
+
+	AuditMergeWindowChanged    = "MERGE_WINDOW_CHANGED"
+	AuditMergeWindowOverridden = "MERGE_WINDOW_OVERRIDDEN"
+
+	AuditTeamLeadAdded   = "TEAM_LEAD_ADDED"
+	AuditTeamLeadRemoved = "TEAM_LEAD_REMOVED"
+
+	AuditPRStatusReconciled = "PR_STATUS_RECONCILED"
+	AuditPRStatusesChanged  = "PR_STATUSES_CHANGED"
+
+	AuditAssignmentRepairFailing = "ASSIGNMENT_REPAIR_FAILING"
+
+	AuditTemplateSaved   = "TEMPLATE_SAVED"
+	AuditTemplateDeleted = "TEMPLATE_DELETED"
+)
+
+type AuditEvent struct {
+	ID        int64     `json:"id"`
+	TeamName  string    `json:"team_name,omitempty"`
+	Action    string    `json:"action"`
+	SubjectID string    `json:"subject_id,omitempty"`
+	ActorID   string    `json:"actor_id,omitempty"`
+	Details   string    `json:"details,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,15 @@
+package models
+
+// BackfillResult reports a forge import: the IDs of the PRs created, how
+// many were already known, and why the others were left out.
+type BackfillResult struct {
+	Fetched  int            `json:"fetched"`
+	Created  []string       `json:"created"`
+	Existing int            `json:"existing"`
+	Skipped  []BackfillSkip `json:"skipped"`
+}
+
+type BackfillSkip struct {
+	PullRequestId string `json:"pull_request_id"`
+	Reason        string `json:"reason"`
+}
@@ -0,0 +1,9 @@
+package models
+
+import "time"
+
+type Certification struct {
+	UserID      string    `db:"user_id" json:"user_id"`
+	Area        string    `db:"area" json:"area"`
+	CertifiedAt time.Time `db:"certified_at" json:"certified_at"`
+}
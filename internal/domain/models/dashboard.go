@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// DashboardPR is one row of the dashboard read model: a PR together with its
+// author's team, reviewers, approvals and review deadline.
+type DashboardPR struct {
+	PullRequestId   string     `db:"pull_request_id" json:"pull_request_id"`
+	PullRequestName string     `db:"pull_request_name" json:"pull_request_name"`
+	AuthorID        string     `db:"author_id" json:"author_id"`
+	AuthorName      string     `db:"author_name" json:"author_name"`
+	TeamName        string     `db:"team_name" json:"team_name"`
+	Status          string     `db:"status" json:"status"`
+	CIStatus        string     `db:"ci_status" json:"ci_status"`
+	Priority        string     `db:"priority" json:"priority"`
+	UnderReviewed   bool       `db:"under_reviewed" json:"under_reviewed"`
+	Reviewers       []string   `db:"-" json:"assigned_reviewers"`
+	Approvers       []string   `db:"-" json:"approvers"`
+	Approvals       int        `db:"-" json:"approvals"`
+	CreatedAt       time.Time  `db:"created_at" json:"created_at"`
+	MergedAt        *time.Time `db:"merged_at" json:"merged_at,omitempty"`
+	DueAt           *time.Time `db:"-" json:"due_at,omitempty"`
+	Overdue         bool       `db:"-" json:"overdue"`
+	ProjectedAt     time.Time  `db:"projected_at" json:"projected_at"`
+
+	// Open is false once the PR reached a terminal status.
+	Open bool `db:"-" json:"-"`
+}
+
+// DashboardCursor is the position after the last row of a dashboard page.
+type DashboardCursor struct {
+	CreatedAt     time.Time
+	PullRequestID string
+}
+
+// DashboardFilter narrows the dashboard. Empty fields do not filter; Open
+// keeps PRs in a non-terminal status and Overdue open PRs past their due time.
+type DashboardFilter struct {
+	TeamName   string
+	ReviewerID string
+	Status     string
+	Open       bool
+	Overdue    bool
+}
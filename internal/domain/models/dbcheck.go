@@ -0,0 +1,39 @@
+package models
+
+type QueryPlanCheck struct {
+	Name          string   `json:"name"`
+	Query         string   `json:"query"`
+	TotalCost     float64  `json:"total_cost"`
+	NodeTypes     []string `json:"node_types"`
+	SeqScanTables []string `json:"seq_scan_tables"`
+	Flagged       bool     `json:"flagged"`
+}
+
+type MigrationStatus struct {
+	Version       uint `json:"version"`
+	Dirty         bool `json:"dirty"`
+	Latest        uint `json:"latest"`
+	Pending       int  `json:"pending"`
+	MinCompatible uint `json:"min_compatible"`
+	Compatible    bool `json:"compatible"`
+}
+
+const (
+	// MembershipMissing: users.team_name names a team with no matching
+	// team_members row.
+	MembershipMissing = "MISSING_MEMBERSHIP"
+	// MembershipStale: a team_members row points at a team other than the
+	// user's users.team_name.
+	MembershipStale = "STALE_MEMBERSHIP"
+)
+
+type MembershipDrift struct {
+	UserID   string `db:"user_id" json:"user_id"`
+	TeamName string `db:"team_name" json:"team_name"`
+	Kind     string `db:"kind" json:"kind"`
+}
+
+type MembershipReport struct {
+	Drift    []MembershipDrift `json:"drift"`
+	Repaired bool              `json:"repaired"`
+}
@@ -0,0 +1,24 @@
+package models
+
+// Names of the enumerations listed by GET /meta/enums.
+const (
+	EnumPRStatus         = "pr_status"
+	EnumPriority         = "priority"
+	EnumCIStatus         = "ci_status"
+	EnumReviewState      = "review_state"
+	EnumAssignmentAction = "assignment_action"
+	EnumReassignReason   = "reassign_reason"
+	EnumActivityKind     = "activity_kind"
+)
+
+// EnumValue is one value of a user-facing enumeration. Label is the display
+// name; the service returns it in English and the HTTP layer translates it.
+type EnumValue struct {
+	Value string `json:"value"`
+	Label string `json:"label"`
+}
+
+type Enum struct {
+	Name   string      `json:"name"`
+	Values []EnumValue `json:"values"`
+}
@@ -0,0 +1,27 @@
+package models
+
+// ForecastInputs is what a review load forecast is computed from. TeamPRs
+// counts PRs other team members created during the history window.
+type ForecastInputs struct {
+	TeamName string `db:"team_name"`
+	// IsActive and ActiveMembers leave out author-only users, who are never
+	// picked as reviewers.
+	IsActive      bool `db:"is_active"`
+	OpenReviews   int  `db:"open_reviews"`
+	TeamPRs       int  `db:"team_prs"`
+	ActiveMembers int  `db:"active_members"`
+	// ReviewersPerPR is the team's effective reviewers-per-PR policy.
+	ReviewersPerPR int `db:"reviewers_per_pr"`
+}
+
+// ReviewForecast estimates a user's review load for the coming week.
+type ReviewForecast struct {
+	UserID               string  `json:"user_id"`
+	TeamName             string  `json:"team_name"`
+	HistoryDays          int     `json:"history_days"`
+	OpenReviews          int     `json:"open_reviews"`
+	TeamPRsPerWeek       float64 `json:"team_prs_per_week"`
+	SelectionProbability float64 `json:"selection_probability"`
+	ExpectedNewReviews   float64 `json:"expected_new_reviews"`
+	ExpectedLoad         float64 `json:"expected_load"`
+}
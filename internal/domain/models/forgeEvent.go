@@ -0,0 +1,17 @@
+package models
+
+// Outcomes of a forge webhook delivery.
+const (
+	ForgeEventMerged        = "merged"
+	ForgeEventAlreadyMerged = "already_merged"
+	ForgeEventIgnored       = "ignored"
+)
+
+// ForgeEventResult reports what a forge webhook delivery changed. Ignored
+// deliveries are still acknowledged, so the forge does not retry them.
+type ForgeEventResult struct {
+	Action        string `json:"action"`
+	PullRequestID string `json:"pull_request_id,omitempty"`
+	MergedBy      string `json:"merged_by,omitempty"`
+	Reason        string `json:"reason,omitempty"`
+}
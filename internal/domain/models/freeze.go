@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// AssignmentFreeze pauses reviewer assignment for one team, or for every
+// team when TeamName is empty, between StartsAt and EndsAt. An open-ended
+// freeze lasts until it is lifted.
+type AssignmentFreeze struct {
+	FreezeID  int64      `db:"freeze_id" json:"freeze_id"`
+	TeamName  string     `db:"team_name" json:"team_name,omitempty"`
+	Reason    string     `db:"reason" json:"reason"`
+	StartsAt  time.Time  `db:"starts_at" json:"starts_at"`
+	EndsAt    *time.Time `db:"ends_at" json:"ends_at,omitempty"`
+	CreatedAt time.Time  `db:"created_at" json:"created_at"`
+	LiftedAt  *time.Time `db:"lifted_at" json:"lifted_at,omitempty"`
+}
+
+// UnfreezeResult reports the freezes lifted by an unfreeze and the queued
+// PRs that received reviewers as a result.
+type UnfreezeResult struct {
+	Lifted   int      `json:"lifted"`
+	Released []string `json:"released_pull_requests"`
+}
+
+// PendingAssignment is a PR waiting in the assignment queue for its author
+// team's freeze to end. Position counts from one within the team, oldest
+// first. FrozenUntil is the latest end of the freezes holding the team and
+// is nil while one of them is open-ended; EstimatedWait is nil then too.
+type PendingAssignment struct {
+	PullRequestID   string     `db:"pull_request_id" json:"pull_request_id"`
+	PullRequestName string     `db:"pull_request_name" json:"pull_request_name"`
+	AuthorID        string     `db:"author_id" json:"author_id"`
+	TeamName        string     `db:"team_name" json:"team_name"`
+	CreatedAt       time.Time  `db:"created_at" json:"created_at"`
+	Position        int        `db:"position" json:"position"`
+	Frozen          bool       `db:"frozen" json:"frozen"`
+	FrozenUntil     *time.Time `db:"frozen_until" json:"frozen_until,omitempty"`
+	EstimatedWait   *int64     `db:"-" json:"estimated_wait_seconds"`
+}
@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// Integration health states. Degraded is only used for the overall report.
+const (
+	HealthOK            = "ok"
+	HealthFailing       = "failing"
+	HealthUnknown       = "unknown"
+	HealthNotConfigured = "not_configured"
+	HealthDegraded      = "degraded"
+)
+
+// IntegrationHealth describes one external dependency. Unknown means it is
+// configured but has not been called since the service started.
+type IntegrationHealth struct {
+	Name                string     `json:"name"`
+	Status              string     `json:"status"`
+	LastSuccessAt       *time.Time `json:"last_success_at"`
+	LastFailureAt       *time.Time `json:"last_failure_at"`
+	LastError           string     `json:"last_error,omitempty"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	// Dropped counts the items given up on since the service started, for
+	// integrations that queue their calls.
+	Dropped int64 `json:"dropped,omitempty"`
+}
+
+type IntegrationsReport struct {
+	Status       string              `json:"status"`
+	CheckedAt    time.Time           `json:"checked_at"`
+	Integrations []IntegrationHealth `json:"integrations"`
+}
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+type ImpersonationSession struct {
+	SessionID int64      `db:"session_id" json:"session_id"`
+	AdminID   string     `db:"admin_id" json:"admin_id"`
+	UserID    string     `db:"user_id" json:"user_id"`
+	Reason    string     `db:"reason" json:"reason"`
+	CreatedAt time.Time  `db:"created_at" json:"created_at"`
+	ExpiresAt time.Time  `db:"expires_at" json:"expires_at"`
+	EndedAt   *time.Time `db:"ended_at" json:"ended_at,omitempty"`
+}
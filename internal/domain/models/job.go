@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+const (
+	JobOutcomeSuccess = "SUCCESS"
+	JobOutcomeFailure = "FAILURE"
+)
+
+type Job struct {
+	Name            string     `db:"name" json:"name"`
+	IntervalSeconds int        `db:"interval_seconds" json:"interval_seconds"`
+	LastStartedAt   *time.Time `db:"last_started_at" json:"last_started_at,omitempty"`
+	LastDurationMs  *int64     `db:"last_duration_ms" json:"last_duration_ms,omitempty"`
+	LastOutcome     *string    `db:"last_outcome" json:"last_outcome,omitempty"`
+	LastError       *string    `db:"last_error" json:"last_error,omitempty"`
+	NextRunAt       *time.Time `db:"next_run_at" json:"next_run_at,omitempty"`
+}
+
+type JobRun struct {
+	Name      string
+	Interval  time.Duration
+	StartedAt time.Time
+	Duration  time.Duration
+	Outcome   string
+	Error     string
+	NextRunAt time.Time
+}
@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+const (
+	MergeWarningClosingSoon = "MERGE_WINDOW_CLOSING_SOON"
+	MergeWarningJustOpened  = "MERGE_WINDOW_JUST_OPENED"
+	MergeWarningOverridden  = "MERGE_WINDOW_OVERRIDDEN"
+)
+
+// MergeWindow is a team's weekly schedule of periods when its PRs cannot be
+// merged, such as Fridays. Times are in TimeZone. A merge within WarnMinutes
+// of a blocked period is allowed but answered with a warning; zero disables
+// the warnings.
+type MergeWindow struct {
+	TeamName    string       `json:"team_name"`
+	TimeZone    string       `json:"time_zone"`
+	WarnMinutes int          `json:"warn_minutes"`
+	Blocks      []MergeBlock `json:"blocks"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+}
+
+// MergeBlock blocks merging every week on Weekday from Start to End, given as
+// HH:MM; End "24:00" runs to midnight. Blocks on consecutive days that meet
+// at midnight form one period, e.g. a weekend.
+type MergeBlock struct {
+	Weekday string `json:"weekday"`
+	Start   string `json:"start"`
+	End     string `json:"end"`
+}
+
+// MergeWarning tells the caller a merge happened next to a blocked period, or
+// inside one on an admin override. At is the edge of that period.
+type MergeWarning struct {
+	Code    string    `json:"code"`
+	Message string    `json:"message"`
+	At      time.Time `json:"at"`
+}
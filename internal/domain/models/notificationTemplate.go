@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// NotificationChannelWebhook is the team webhook delivery; its rendered text
+// is sent in the "text" field of the delivery.
+const NotificationChannelWebhook = "webhook"
+
+// NotificationChannels are the channels a notification template can target.
+var NotificationChannels = []string{NotificationChannelWebhook}
+
+// NotificationTemplate customizes the text of one event on one channel. Body
+// is a Go text/template executed against NotificationTemplateData.
+type NotificationTemplate struct {
+	Event     string    `db:"event" json:"event"`
+	Channel   string    `db:"channel" json:"channel"`
+	Body      string    `db:"body" json:"body"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+	UpdatedBy string    `db:"-" json:"updated_by,omitempty"`
+}
+
+// NotificationTemplateData are the variables available to a template, e.g.
+// {{.PullRequestName}}, {{.AuthorName}} or {{.DueAt.Format "02.01 15:04"}}.
+// Data holds the event payload and Users the profiles it refers to.
+type NotificationTemplateData struct {
+	Event           string
+	PullRequestID   string
+	PullRequestName string
+	AuthorID        string
+	AuthorName      string
+	CreatedAt       time.Time
+	DueAt           time.Time
+	Data            map[string]any
+	Users           map[string]User
+}
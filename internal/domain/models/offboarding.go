@@ -0,0 +1,30 @@
+package models
+
+// OffboardingReport lists what offboarding a user did. Deactivated is false
+// when the user was already inactive; Unreassigned holds the reviews no one
+// could take over, with the reason, which are left for a human to resolve.
+// CancelledDeliveries counts the pending webhook deliveries about the user
+// that were dropped.
+type OffboardingReport struct {
+	UserID              string               `json:"user_id"`
+	Deactivated         bool                 `json:"deactivated"`
+	Reassigned          []OffboardedReview   `json:"reassigned"`
+	Unreassigned        []OffboardedReview   `json:"unreassigned"`
+	RemovedFromPools    []string             `json:"removed_from_pools"`
+	RevokedTokens       []int64              `json:"revoked_tokens"`
+	CancelledDeliveries int                  `json:"cancelled_deliveries"`
+	Anonymization       *AnonymizationResult `json:"anonymization,omitempty"`
+}
+
+type OffboardedReview struct {
+	PullRequestId string `json:"pull_request_id"`
+	NewReviewerID string `json:"new_reviewer_id,omitempty"`
+	Reason        string `json:"reason,omitempty"`
+}
+
+// ReviewHandover lists what happened to the open reviews of a user
+// deactivated with their reviews handed over.
+type ReviewHandover struct {
+	Reassigned   []OffboardedReview `json:"reassigned"`
+	Unreassigned []OffboardedReview `json:"unreassigned"`
+}
@@ -0,0 +1,135 @@
+package models
+
+import "time"
+
+const (
+	PolicySourceOrg  = "ORG"
+	PolicySourceTeam = "TEAM"
+)
+
+// Policy fields a team may leave unset to inherit the org default.
+const (
+	PolicyFieldHoldUntilCIGreen      = "hold_until_ci_green"
+	PolicyFieldMinReviewers          = "min_reviewers"
+	PolicyFieldReviewersPerPR        = "reviewers_per_pr"
+	PolicyFieldDefaultPriority       = "default_priority"
+	PolicyFieldDefaultLabels         = "default_labels"
+	PolicyFieldDefaultRequiredSkills = "default_required_skills"
+	PolicyFieldExcludeCoAuthors      = "exclude_co_authors"
+	PolicyFieldExcludePairingSession = "exclude_pairing_session"
+	PolicyFieldDiversifyPairs        = "diversify_reviewer_pairs"
+)
+
+var InheritablePolicyFields = []string{
+	PolicyFieldHoldUntilCIGreen,
+	PolicyFieldMinReviewers,
+	PolicyFieldReviewersPerPR,
+	PolicyFieldDefaultPriority,
+	PolicyFieldDefaultLabels,
+	PolicyFieldDefaultRequiredSkills,
+	PolicyFieldExcludeCoAuthors,
+	PolicyFieldExcludePairingSession,
+	PolicyFieldDiversifyPairs,
+}
+
+// OrgPolicy holds the defaults every team inherits for the policy fields it
+// does not override.
+type OrgPolicy struct {
+	HoldUntilCIGreen bool `json:"hold_until_ci_green"`
+	MinReviewers     int  `json:"min_reviewers"`
+	ReviewersPerPR   int  `json:"reviewers_per_pr"`
+
+	DefaultPriority       string   `json:"default_priority,omitempty"`
+	DefaultLabels         []string `json:"default_labels"`
+	DefaultRequiredSkills []string `json:"default_required_skills"`
+
+	ExcludeCoAuthors      bool `json:"exclude_co_authors"`
+	ExcludePairingSession bool `json:"exclude_pairing_session"`
+
+	DiversifyPairs bool `json:"diversify_reviewer_pairs"`
+
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// OrgPolicyUpdate carries a partial org policy change; nil fields are left
+// untouched.
+type OrgPolicyUpdate struct {
+	HoldUntilCIGreen *bool
+	MinReviewers     *int
+	ReviewersPerPR   *int
+
+	DefaultPriority       *string
+	DefaultLabels         *[]string
+	DefaultRequiredSkills *[]string
+
+	ExcludeCoAuthors      *bool
+	ExcludePairingSession *bool
+
+	DiversifyPairs *bool
+}
+
+// TeamPolicyOverrides is the policy a team stores itself and the document
+// its settings endpoint patches. Nil fields inherit the org default; an empty
+// DefaultPriority or tag list is an explicit override. ReviewLabels is
+// team-only and never inherited.
+type TeamPolicyOverrides struct {
+	TeamName         string `json:"-"`
+	HoldUntilCIGreen *bool  `json:"hold_until_ci_green"`
+	MinReviewers     *int   `json:"min_reviewers"`
+	ReviewersPerPR   *int   `json:"reviewers_per_pr"`
+
+	DefaultPriority       *string   `json:"default_priority"`
+	DefaultLabels         *[]string `json:"default_labels"`
+	DefaultRequiredSkills *[]string `json:"default_required_skills"`
+	ReviewLabels          []string  `json:"review_labels"`
+
+	ExcludeCoAuthors      *bool `json:"exclude_co_authors"`
+	ExcludePairingSession *bool `json:"exclude_pairing_session"`
+
+	DiversifyPairs *bool `json:"diversify_reviewer_pairs"`
+}
+
+// EffectivePolicy is a team policy after resolution, with the layer every
+// inheritable field came from.
+type EffectivePolicy struct {
+	TeamName string            `json:"team_name"`
+	Policy   TeamPolicy        `json:"policy"`
+	Sources  map[string]string `json:"sources"`
+}
+
+// ResolvePolicy applies the team overrides on top of the org defaults.
+func ResolvePolicy(org OrgPolicy, team TeamPolicyOverrides) EffectivePolicy {
+	sources := make(map[string]string, len(InheritablePolicyFields))
+
+	policy := TeamPolicy{
+		TeamName:         team.TeamName,
+		HoldUntilCIGreen: resolveField(sources, PolicyFieldHoldUntilCIGreen, team.HoldUntilCIGreen, org.HoldUntilCIGreen),
+		MinReviewers:     resolveField(sources, PolicyFieldMinReviewers, team.MinReviewers, org.MinReviewers),
+		ReviewersPerPR:   resolveField(sources, PolicyFieldReviewersPerPR, team.ReviewersPerPR, org.ReviewersPerPR),
+
+		DefaultPriority:       resolveField(sources, PolicyFieldDefaultPriority, team.DefaultPriority, org.DefaultPriority),
+		DefaultLabels:         resolveField(sources, PolicyFieldDefaultLabels, team.DefaultLabels, org.DefaultLabels),
+		DefaultRequiredSkills: resolveField(sources, PolicyFieldDefaultRequiredSkills, team.DefaultRequiredSkills, org.DefaultRequiredSkills),
+		ReviewLabels:          team.ReviewLabels,
+
+		ExcludeCoAuthors:      resolveField(sources, PolicyFieldExcludeCoAuthors, team.ExcludeCoAuthors, org.ExcludeCoAuthors),
+		ExcludePairingSession: resolveField(sources, PolicyFieldExcludePairingSession, team.ExcludePairingSession, org.ExcludePairingSession),
+
+		DiversifyPairs: resolveField(sources, PolicyFieldDiversifyPairs, team.DiversifyPairs, org.DiversifyPairs),
+	}
+
+	return EffectivePolicy{
+		TeamName: team.TeamName,
+		Policy:   policy,
+		Sources:  sources,
+	}
+}
+
+func resolveField[T any](sources map[string]string, field string, override *T, orgDefault T) T {
+	if override != nil {
+		sources[field] = PolicySourceTeam
+		return *override
+	}
+	sources[field] = PolicySourceOrg
+	return orgDefault
+}
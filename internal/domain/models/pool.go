@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// ReviewerPool is a group of reviewers that is independent of the teams, for
+// example a guild spanning several teams. A PR targeting a pool gets
+// ReviewersPerPR of its members instead of reviewers from the author's team.
+type ReviewerPool struct {
+	PoolName       string    `db:"pool_name" json:"pool_name"`
+	ReviewersPerPR int       `db:"reviewers_per_pr" json:"reviewers_per_pr"`
+	Members        []string  `db:"-" json:"members"`
+	CreatedAt      time.Time `db:"created_at" json:"created_at"`
+}
+
+type PoolMemberStats struct {
+	UserID       string `db:"user_id" json:"user_id"`
+	IsActive     bool   `db:"is_active" json:"is_active"`
+	OpenReviews  int    `db:"open_reviews" json:"open_reviews"`
+	TotalReviews int    `db:"total_reviews" json:"total_reviews"`
+}
+
+type PoolStats struct {
+	PoolName  string            `json:"pool_name"`
+	OpenPRs   int               `db:"open_prs" json:"open_prs"`
+	MergedPRs int               `db:"merged_prs" json:"merged_prs"`
+	Members   []PoolMemberStats `json:"members"`
+}
@@ -0,0 +1,26 @@
+package models
+
+const (
+	PRStatusOpen   = "OPEN"
+	PRStatusMerged = "MERGED"
+)
+
+type PRStatus struct {
+	Status     string `db:"status" json:"status"`
+	IsTerminal bool   `db:"is_terminal" json:"is_terminal"`
+}
+
+type PRStatusTransition struct {
+	FromStatus string `db:"from_status" json:"from_status"`
+	ToStatus   string `db:"to_status" json:"to_status"`
+}
+
+// PRStatusMachine is the state machine the PRs of a team follow. Statuses
+// are shared by the org; the transitions are the team's own when it set
+// any (Source TEAM) and the org's otherwise (Source ORG).
+type PRStatusMachine struct {
+	TeamName    string               `json:"team_name,omitempty"`
+	Source      string               `json:"source"`
+	Statuses    []PRStatus           `json:"statuses"`
+	Transitions []PRStatusTransition `json:"transitions"`
+}
@@ -2,21 +2,164 @@ package models
 
 import (
 	"database/sql"
+	"strings"
 	"time"
 )
 
+const (
+	PriorityLow      = "LOW"
+	PriorityNormal   = "NORMAL"
+	PriorityHigh     = "HIGH"
+	PriorityCritical = "CRITICAL"
+)
+
+const (
+	ReviewStateAssigned   = "ASSIGNED"
+	ReviewStateInProgress = "IN_PROGRESS"
+	ReviewStateCompleted  = "COMPLETED"
+)
+
+const (
+	CIStatusUnknown = "UNKNOWN"
+	CIStatusPending = "PENDING"
+	CIStatusSuccess = "SUCCESS"
+	CIStatusFailure = "FAILURE"
+)
+
 type PullRequest struct {
-	PullRequestId   string       `db:"pull_request_id" json:"pull_request_id"`
-	PullRequestName string       `db:"pull_request_name" json:"pull_request_name"`
-	AuthorID        string       `db:"author_id" json:"author_id"`
-	Status          string       `db:"status" json:"status"`
-	CreatedAt       time.Time    `db:"created_at" json:"created_at"`
-	MergedAt        sql.NullTime `db:"merged_at" json:"merged_at,omitempty"`
+	PullRequestId   string   `db:"pull_request_id" json:"pull_request_id"`
+	PullRequestName string   `db:"pull_request_name" json:"pull_request_name"`
+	AuthorID        string   `db:"author_id" json:"author_id"`
+	Status          string   `db:"status" json:"status"`
+	CIStatus        string   `db:"ci_status" json:"ci_status"`
+	Priority        string   `db:"priority" json:"priority"`
+	Labels          []string `db:"-" json:"labels"`
+	RequiredSkills  []string `db:"-" json:"required_skills"`
+	ChangedPaths    []string `db:"-" json:"changed_paths"`
+
+	// CoAuthors and PairingSession are request metadata used only by the
+	// reviewer exclusion rules of the author's team policy.
+	CoAuthors      []string `db:"-" json:"co_authors"`
+	PairingSession []string `db:"-" json:"pairing_session"`
+
+	RequiredCertifications []string            `db:"-" json:"required_certifications"`
+	ReviewerTeams          []ReviewerTeamQuota `db:"-" json:"reviewer_teams"`
+	CreatedAt              time.Time           `db:"created_at" json:"created_at"`
+	MergedAt               sql.NullTime        `db:"merged_at" json:"merged_at,omitempty"`
+	MergedBy               string              `db:"-" json:"merged_by,omitempty"`
+
+	// AutoMerge asks the auto-merge job to merge the PR once it has
+	// AutoMergeApprovals approvals and CI is green or not tracked.
+	AutoMerge          bool `db:"auto_merge" json:"auto_merge"`
+	AutoMergeApprovals int  `db:"auto_merge_approvals" json:"auto_merge_approvals"`
+
+	// AssignmentQueued marks a PR created during an assignment freeze; it
+	// gets reviewers once the freeze is over.
+	AssignmentQueued bool `db:"assignment_queued" json:"assignment_queued"`
+
+	// AssignmentPending marks a PR just created without the reviewers
+	// selected for it; the assignment repair worker assigns them.
+	AssignmentPending bool `db:"-" json:"assignment_pending,omitempty"`
+
+	// UnderReviewed marks a PR a reviewer declined without a replacement
+	// available; the next reviewer added to it clears the mark.
+	UnderReviewed bool `db:"under_reviewed" json:"under_reviewed"`
+
+	// ReviewerPool, when set, replaces the author's team as the source of
+	// the PR's own reviewers.
+	ReviewerPool string `db:"reviewer_pool" json:"reviewer_pool,omitempty"`
+
+	// AuthorTeam is the author's team the PR was opened for. PRs without it
+	// use the author's primary team.
+	AuthorTeam string `db:"author_team" json:"author_team,omitempty"`
+}
+
+// ReviewerTeamQuota is the number of reviewers a PR requests from one team.
+type ReviewerTeamQuota struct {
+	TeamName  string `db:"team_name" json:"team_name"`
+	Reviewers int    `db:"reviewers" json:"reviewers"`
 }
 
 type PullRequestShort struct {
-	PullRequestId   string `db:"pull_request_id" json:"pull_request_id"`
-	PullRequestName string `db:"pull_request_name" json:"pull_request_name"`
-	AuthorID        string `db:"author_id" json:"author_id"`
-	Status          string `db:"status" json:"status"`
+	PullRequestId     string     `db:"pull_request_id" json:"pull_request_id"`
+	PullRequestName   string     `db:"pull_request_name" json:"pull_request_name"`
+	AuthorID          string     `db:"author_id" json:"author_id"`
+	Status            string     `db:"status" json:"status"`
+	ReviewState       string     `db:"review_state" json:"review_state"`
+	ReviewStartedAt   *time.Time `db:"review_started_at" json:"review_started_at,omitempty"`
+	ReviewCompletedAt *time.Time `db:"review_completed_at" json:"review_completed_at,omitempty"`
+	HandoffNote       string     `db:"handoff_note" json:"handoff_note,omitempty"`
+}
+
+type PullRequestExport struct {
+	PullRequestId   string     `db:"pull_request_id" json:"pull_request_id"`
+	PullRequestName string     `db:"pull_request_name" json:"pull_request_name"`
+	AuthorID        string     `db:"author_id" json:"author_id"`
+	Status          string     `db:"status" json:"status"`
+	CIStatus        string     `db:"ci_status" json:"ci_status"`
+	Priority        string     `db:"priority" json:"priority"`
+	CreatedAt       time.Time  `db:"created_at" json:"created_at"`
+	MergedAt        *time.Time `db:"merged_at" json:"merged_at,omitempty"`
+	Reviewers       []string   `db:"-" json:"assigned_reviewers"`
+}
+
+// PRSearch narrows a PR listing. Empty fields do not filter; Name matches a
+// case-insensitive substring of the PR name and the time ranges are
+// half-open, [from, to).
+type PRSearch struct {
+	Name        string
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	MergedFrom  *time.Time
+	MergedTo    *time.Time
+	ReviewerID  string
+}
+
+func IsValidCIStatus(status string) bool {
+	switch status {
+	case CIStatusUnknown, CIStatusPending, CIStatusSuccess, CIStatusFailure:
+		return true
+	}
+	return false
+}
+
+func IsValidPriority(priority string) bool {
+	switch priority {
+	case PriorityLow, PriorityNormal, PriorityHigh, PriorityCritical:
+		return true
+	}
+	return false
+}
+
+// MergeTags returns the union of defaults and explicit tags, trimmed and
+// de-duplicated, keeping the order in which tags first appear.
+func MergeTags(defaults []string, explicit []string) []string {
+	seen := make(map[string]bool)
+	result := make([]string, 0, len(defaults)+len(explicit))
+
+	for _, list := range [][]string{defaults, explicit} {
+		for _, tag := range list {
+			tag = strings.TrimSpace(tag)
+			if tag == "" || seen[tag] {
+				continue
+			}
+			seen[tag] = true
+			result = append(result, tag)
+		}
+	}
+
+	return result
+}
+
+// PriorityRank orders priorities from least to most urgent.
+func PriorityRank(priority string) int {
+	switch priority {
+	case PriorityLow:
+		return 0
+	case PriorityHigh:
+		return 2
+	case PriorityCritical:
+		return 3
+	}
+	return 1
 }
@@ -0,0 +1,30 @@
+package models
+
+// MemberWorkload is a team member's open review load. IsActive is also false
+// for author-only members, so rebalancing moves their reviews off them too.
+type MemberWorkload struct {
+	UserID      string `db:"user_id"`
+	IsActive    bool   `db:"is_active"`
+	OpenReviews int    `db:"open_reviews"`
+}
+
+// MovableAssignment is an open review nobody has started, approved or
+// completed yet, so it can go to another reviewer without losing work.
+type MovableAssignment struct {
+	PullRequestId string `db:"pull_request_id"`
+	ReviewerID    string `db:"reviewer_id"`
+}
+
+type RebalanceMove struct {
+	PullRequestId  string `json:"pull_request_id"`
+	FromReviewerID string `json:"from_reviewer_id"`
+	ToReviewerID   string `json:"to_reviewer_id"`
+}
+
+type RebalancePlan struct {
+	TeamName   string          `json:"team_name"`
+	DryRun     bool            `json:"dry_run"`
+	Moves      []RebalanceMove `json:"moves"`
+	LoadBefore map[string]int  `json:"load_before"`
+	LoadAfter  map[string]int  `json:"load_after"`
+}
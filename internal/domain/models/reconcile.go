@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// Outcomes of reconciling one PR against an external status.
+const (
+	ReconcileUnchanged = "UNCHANGED"
+	ReconcileCorrected = "CORRECTED"
+	ReconcileNotFound  = "NOT_FOUND"
+)
+
+// PRStatusClaim is the status of a PR according to an external source of
+// truth. MergedAt is only allowed with the MERGED status.
+type PRStatusClaim struct {
+	PullRequestID string     `json:"pull_request_id"`
+	Status        string     `json:"status"`
+	MergedAt      *time.Time `json:"merged_at"`
+}
+
+type ReconcileResult struct {
+	PullRequestID    string     `json:"pull_request_id"`
+	Outcome          string     `json:"outcome"`
+	PreviousStatus   string     `json:"previous_status,omitempty"`
+	Status           string     `json:"status,omitempty"`
+	PreviousMergedAt *time.Time `json:"previous_merged_at,omitempty"`
+	MergedAt         *time.Time `json:"merged_at,omitempty"`
+}
+
+type ReconcileReport struct {
+	DryRun    bool              `json:"dry_run"`
+	Checked   int               `json:"checked"`
+	Corrected int               `json:"corrected"`
+	Unchanged int               `json:"unchanged"`
+	NotFound  int               `json:"not_found"`
+	Results   []ReconcileResult `json:"results"`
+}
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+type ReviewAssignment struct {
+	PullRequestId   string    `db:"pull_request_id" json:"pull_request_id"`
+	PullRequestName string    `db:"pull_request_name" json:"pull_request_name"`
+	AuthorID        string    `db:"author_id" json:"author_id"`
+	Status          string    `db:"status" json:"status"`
+	Priority        string    `db:"priority" json:"priority"`
+	CreatedAt       time.Time `db:"created_at" json:"created_at"`
+	DueAt           time.Time `db:"-" json:"due_at"`
+	Overdue         bool      `db:"-" json:"overdue"`
+	Link            string    `db:"-" json:"link,omitempty"`
+	QueuePosition   *int      `db:"queue_position" json:"queue_position,omitempty"`
+}
+
+type ReviewerCandidate struct {
+	UserID         string  `db:"user_id" json:"user_id"`
+	Username       string  `db:"username" json:"username"`
+	OpenReviews    int     `db:"open_reviews" json:"open_reviews"`
+	InProgress     int     `db:"in_progress" json:"in_progress"`
+	RecentPairings int     `db:"recent_pairings" json:"recent_pairings"`
+	Score          float64 `db:"-" json:"score"`
+}
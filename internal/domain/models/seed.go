@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// SeedDataset is demo data generated for staging environments.
+type SeedDataset struct {
+	Teams        []SeedTeam
+	PullRequests []SeedPullRequest
+}
+
+type SeedTeam struct {
+	Name    string
+	Members []User
+}
+
+type SeedPullRequest struct {
+	PullRequest
+	Reviews []SeedReview
+}
+
+type SeedReview struct {
+	ReviewerID  string
+	AssignedAt  time.Time
+	StartedAt   *time.Time
+	CompletedAt *time.Time
+}
+
+type SeedSummary struct {
+	Teams        int `json:"teams"`
+	Users        int `json:"users"`
+	PullRequests int `json:"pull_requests"`
+	Skipped      int `json:"skipped"`
+}
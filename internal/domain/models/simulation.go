@@ -0,0 +1,39 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+const (
+	StrategyRandom      = "random"
+	StrategyLeastLoaded = "least_loaded"
+)
+
+type SimulationPR struct {
+	PullRequestId string       `db:"pull_request_id"`
+	AuthorID      string       `db:"author_id"`
+	TeamName      string       `db:"team_name"`
+	CreatedAt     time.Time    `db:"created_at"`
+	MergedAt      sql.NullTime `db:"merged_at"`
+}
+
+type ReviewerLoad struct {
+	UserID    string `json:"user_id"`
+	TeamName  string `json:"team_name"`
+	Simulated int    `json:"simulated"`
+	Actual    int    `json:"actual"`
+}
+
+type SimulationReport struct {
+	Strategy       string         `json:"strategy"`
+	ReviewersPerPR int            `json:"reviewers_per_pr"`
+	WindowDays     int            `json:"window_days"`
+	TotalPRs       int            `json:"total_prs"`
+	UnassignedPRs  int            `json:"unassigned_prs"`
+	MaxLoad        int            `json:"max_load"`
+	MinLoad        int            `json:"min_load"`
+	StdDev         float64        `json:"std_dev"`
+	ActualStdDev   float64        `json:"actual_std_dev"`
+	Reviewers      []ReviewerLoad `json:"reviewers"`
+}
@@ -1,8 +1,141 @@
 package models
 
+import "time"
+
 type PRStats struct {
-	TotalPRs          int     `json:"total_prs"`
-	OpenPRs           int     `json:"open_prs"`
-	MergedPRs         int     `json:"merged_prs"`
-	AvgReviewersPerPR float64 `json:"avg_reviewers_per_pr"`
+	TotalPRs              int            `json:"total_prs"`
+	OpenPRs               int            `json:"open_prs"`
+	MergedPRs             int            `json:"merged_prs"`
+	AvgReviewersPerPR     float64        `json:"avg_reviewers_per_pr"`
+	ByStatus              map[string]int `json:"by_status"`
+	AssignmentsByAction   map[string]int `json:"assignments_by_action"`
+	ReassignmentsByReason map[string]int `json:"reassignments_by_reason"`
+	MergesByUser          map[string]int `json:"merges_by_user"`
+}
+
+type TeamPRStats struct {
+	TeamName          string  `db:"team_name" json:"team_name"`
+	TotalPRs          int     `db:"total_prs" json:"total_prs"`
+	OpenPRs           int     `db:"open_prs" json:"open_prs"`
+	MergedPRs         int     `db:"merged_prs" json:"merged_prs"`
+	AvgReviewersPerPR float64 `db:"avg_reviewers_per_pr" json:"avg_reviewers_per_pr"`
+}
+
+type ReviewerShare struct {
+	TeamName        string  `db:"team_name" json:"team_name"`
+	UserID          string  `db:"user_id" json:"user_id"`
+	Assignments     int     `db:"assignments" json:"assignments"`
+	TeamAssignments int     `db:"team_assignments" json:"team_assignments"`
+	TeamMembers     int     `db:"team_members" json:"team_members"`
+	Share           float64 `db:"-" json:"share"`
+}
+
+// CycleTime summarizes created-to-merged durations, in seconds, of the PRs
+// merged within a window.
+type CycleTime struct {
+	TeamName  string  `db:"team_name" json:"team_name,omitempty"`
+	MergedPRs int     `db:"merged_prs" json:"merged_prs"`
+	P50       float64 `db:"p50" json:"p50_seconds"`
+	P90       float64 `db:"p90" json:"p90_seconds"`
+	P99       float64 `db:"p99" json:"p99_seconds"`
+}
+
+type CycleTimeStats struct {
+	WindowDays int         `json:"window_days"`
+	Since      time.Time   `json:"since"`
+	Overall    CycleTime   `json:"overall"`
+	Teams      []CycleTime `json:"teams"`
+}
+
+const (
+	TagKindLabel = "label"
+	TagKindSkill = "skill"
+)
+
+// TagStats summarizes the PRs created within a window that carry one label
+// or required skill. Merge times are created-to-merged, in seconds.
+// TopReviewerShare is the part of the tag's reviews held by its busiest
+// reviewer; a value close to one marks a review bottleneck.
+type TagStats struct {
+	Kind             string          `db:"kind" json:"kind"`
+	Tag              string          `db:"tag" json:"tag"`
+	TotalPRs         int             `db:"total_prs" json:"total_prs"`
+	OpenPRs          int             `db:"open_prs" json:"open_prs"`
+	MergedPRs        int             `db:"merged_prs" json:"merged_prs"`
+	MergeP50         float64         `db:"merge_p50" json:"merge_p50_seconds"`
+	MergeP90         float64         `db:"merge_p90" json:"merge_p90_seconds"`
+	TopReviewerShare float64         `db:"-" json:"top_reviewer_share"`
+	Reviewers        []TagReviewLoad `db:"-" json:"reviewers"`
+}
+
+// TagReviewLoad counts the reviews one reviewer holds on the PRs of a tag.
+type TagReviewLoad struct {
+	Kind    string `db:"kind" json:"-"`
+	Tag     string `db:"tag" json:"-"`
+	UserID  string `db:"user_id" json:"user_id"`
+	Reviews int    `db:"reviews" json:"reviews"`
+}
+
+type TagStatsReport struct {
+	WindowDays int        `json:"window_days"`
+	Since      time.Time  `json:"since"`
+	Labels     []TagStats `json:"labels"`
+	Skills     []TagStats `json:"skills"`
+}
+
+// StatsSnapshot is one day of stats history for a team, or for the whole
+// service when TeamName is empty. OpenReviews is the number of unfinished
+// reviews held by the team's members on non-terminal PRs.
+type StatsSnapshot struct {
+	SnapshotDate      time.Time `db:"snapshot_date" json:"snapshot_date"`
+	TeamName          string    `db:"team_name" json:"team_name,omitempty"`
+	TotalPRs          int       `db:"total_prs" json:"total_prs"`
+	OpenPRs           int       `db:"open_prs" json:"open_prs"`
+	MergedPRs         int       `db:"merged_prs" json:"merged_prs"`
+	AvgReviewersPerPR float64   `db:"avg_reviewers_per_pr" json:"avg_reviewers_per_pr"`
+	OpenReviews       int       `db:"open_reviews" json:"open_reviews"`
+	ActiveMembers     int       `db:"active_members" json:"active_members"`
+}
+
+// MemberCapacity is one member's review load for capacity planning.
+// Inactive members are treated as away for the whole planned period.
+type MemberCapacity struct {
+	TeamName         string `db:"team_name" json:"team_name"`
+	UserID           string `db:"user_id" json:"user_id"`
+	Username         string `db:"username" json:"username"`
+	IsActive         bool   `db:"is_active" json:"is_active"`
+	OpenReviews      int    `db:"open_reviews" json:"open_reviews"`
+	CompletedReviews int    `db:"completed_reviews" json:"completed_reviews"`
+	CompletedRecent  int    `db:"completed_recent" json:"completed_recent"`
+}
+
+// AvailableOn reports whether the member can take reviews on the given day.
+func (m MemberCapacity) AvailableOn(day time.Time) bool {
+	weekday := day.Weekday()
+	return m.IsActive && weekday != time.Saturday && weekday != time.Sunday
+}
+
+// CapacityPlan covers the calendar month starting at MonthStart.
+type CapacityPlan struct {
+	MonthStart time.Time
+	Days       []time.Time
+	Members    []MemberCapacity
+}
+
+// ReviewerPair counts the PRs two reviewers were assigned to together.
+// FirstID is the lower user ID of the two.
+type ReviewerPair struct {
+	FirstID   string `db:"first_id" json:"first_id"`
+	SecondID  string `db:"second_id" json:"second_id"`
+	CoReviews int    `db:"co_reviews" json:"co_reviews"`
+}
+
+// PairingMatrix shows how often the team's members co-reviewed PRs created
+// within the window. Matrix[i][j] counts Members[i] together with Members[j].
+type PairingMatrix struct {
+	TeamName   string         `json:"team_name"`
+	WindowDays int            `json:"window_days"`
+	Members    []string       `json:"members"`
+	Matrix     [][]int        `json:"matrix"`
+	Pairs      []ReviewerPair `json:"pairs"`
 }
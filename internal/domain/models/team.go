@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 type Team struct {
 	TeamName string `db:"team_name" json:"team_name"`
 	Members  []User `db:"-" json:"members"`
@@ -9,3 +11,59 @@ type TeamMember struct {
 	TeamName string `db:"team_name"`
 	UserID   string `db:"user_id"`
 }
+
+type TeamPolicy struct {
+	TeamName         string `db:"team_name" json:"team_name"`
+	HoldUntilCIGreen bool   `db:"hold_until_ci_green" json:"hold_until_ci_green"`
+	MinReviewers     int    `db:"min_reviewers" json:"min_reviewers"`
+
+	// ReviewersPerPR is how many reviewers the author's team gives a PR
+	// that does not ask for a different quota.
+	ReviewersPerPR int `db:"reviewers_per_pr" json:"reviewers_per_pr"`
+
+	DefaultPriority       string   `db:"-" json:"default_priority,omitempty"`
+	DefaultLabels         []string `db:"-" json:"default_labels"`
+	DefaultRequiredSkills []string `db:"-" json:"default_required_skills"`
+
+	// ReviewLabels makes the team a reviewer team for every PR carrying one
+	// of these labels, with a quota of one reviewer.
+	ReviewLabels []string `db:"-" json:"review_labels"`
+
+	// ExcludeCoAuthors and ExcludePairingSession keep the PR's co-authors and
+	// pairing partners from being picked as its reviewers.
+	ExcludeCoAuthors      bool `db:"exclude_co_authors" json:"exclude_co_authors"`
+	ExcludePairingSession bool `db:"exclude_pairing_session" json:"exclude_pairing_session"`
+
+	// DiversifyPairs prefers reviewers who have rarely reviewed together
+	// when a PR gets more than one reviewer.
+	DiversifyPairs bool `db:"diversify_reviewer_pairs" json:"diversify_reviewer_pairs"`
+}
+
+type PolicyVersion struct {
+	TeamName  string     `json:"team_name"`
+	Version   int        `json:"version"`
+	Policy    TeamPolicy `json:"policy"`
+	Changes   []string   `json:"changes"`
+	ActorID   *string    `json:"actor_id,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// TeamPolicyUpdate carries a partial policy change; nil fields are left
+// untouched and fields named in Inherit drop the team override.
+type TeamPolicyUpdate struct {
+	Inherit []string
+
+	HoldUntilCIGreen *bool
+	MinReviewers     *int
+	ReviewersPerPR   *int
+
+	DefaultPriority       *string
+	DefaultLabels         *[]string
+	DefaultRequiredSkills *[]string
+	ReviewLabels          *[]string
+
+	ExcludeCoAuthors      *bool
+	ExcludePairingSession *bool
+
+	DiversifyPairs *bool
+}
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"slices"
+	"time"
+)
+
+type TeamLead struct {
+	TeamName  string    `db:"team_name" json:"team_name"`
+	UserID    string    `db:"user_id" json:"user_id"`
+	Username  string    `db:"username" json:"username"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+// StatsVisibility is what team and user level stats a caller may see: all
+// of them, or only those of Teams and of their members.
+type StatsVisibility struct {
+	All     bool
+	Teams   []string
+	Members []string
+}
+
+func (v StatsVisibility) AllowsTeam(teamName string) bool {
+	return v.All || slices.Contains(v.Teams, teamName)
+}
+
+func (v StatsVisibility) AllowsUser(userID string) bool {
+	return v.All || slices.Contains(v.Members, userID)
+}
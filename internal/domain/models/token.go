@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+const (
+	TokenScopeRead  = "read"
+	TokenScopeWrite = "write"
+	TokenScopeAdmin = "admin"
+)
+
+// APIToken is an issued API key. Requests made with it act as UserID; keys
+// issued before tokens were bound to users have none and act as nobody.
+// HourlyQuota limits the key's requests per hour, 0 meaning no limit; keys
+// without one follow the default quota.
+type APIToken struct {
+	TokenID     int64      `json:"token_id"`
+	Name        string     `json:"name"`
+	UserID      string     `json:"user_id,omitempty"`
+	Scopes      []string   `json:"scopes"`
+	HourlyQuota *int       `json:"hourly_quota,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	RevokedAt   *time.Time `json:"revoked_at,omitempty"`
+	LastUsedAt  *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	RotatedAt   *time.Time `json:"rotated_at,omitempty"`
+}
+
+func IsValidTokenScope(scope string) bool {
+	switch scope {
+	case TokenScopeRead, TokenScopeWrite, TokenScopeAdmin:
+		return true
+	}
+	return false
+}
+
+// HasScope reports whether the token grants the scope. admin implies write,
+// and write implies read.
+func (t *APIToken) HasScope(scope string) bool {
+	rank := map[string]int{TokenScopeRead: 1, TokenScopeWrite: 2, TokenScopeAdmin: 3}
+	for _, granted := range t.Scopes {
+		if rank[granted] >= rank[scope] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,49 @@
+package models
+
+const (
+	ExclusionAuthor          = "AUTHOR"
+	ExclusionCoAuthor        = "CO_AUTHOR"
+	ExclusionPairingSession  = "PAIRING_SESSION"
+	ExclusionAlreadyAssigned = "ALREADY_ASSIGNED"
+	ExclusionDailyCap        = "DAILY_CAP"
+)
+
+const (
+	TraceSourceTeam          = "TEAM"
+	TraceSourceCertification = "CERTIFICATION"
+	TraceSourcePool          = "POOL"
+)
+
+type ReviewerExclusion struct {
+	UserID string `json:"user_id"`
+	Reason string `json:"reason"`
+}
+
+// AssignmentTraceStep is one random pick of Wanted reviewers from Pool.
+// Candidates carries the same ranking as /pullRequest/candidates for the
+// pool's team, for comparison with the pick.
+type AssignmentTraceStep struct {
+	Source     string              `json:"source"`
+	TeamName   string              `json:"team_name,omitempty"`
+	PoolName   string              `json:"pool_name,omitempty"`
+	Area       string              `json:"area,omitempty"`
+	Wanted     int                 `json:"wanted"`
+	Excluded   []string            `json:"excluded"`
+	Pool       []string            `json:"pool"`
+	Candidates []ReviewerCandidate `json:"candidates,omitempty"`
+	Picked     []string            `json:"picked"`
+}
+
+type AssignmentTrace struct {
+	Exclusions []ReviewerExclusion   `json:"exclusions"`
+	Steps      []AssignmentTraceStep `json:"steps"`
+	Picks      []string              `json:"picks"`
+}
+
+func NewAssignmentTrace() *AssignmentTrace {
+	return &AssignmentTrace{
+		Exclusions: make([]ReviewerExclusion, 0),
+		Steps:      make([]AssignmentTraceStep, 0),
+		Picks:      make([]string, 0),
+	}
+}
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+const (
+	UsageBucketHour = "hour"
+	UsageBucketDay  = "day"
+)
+
+type UsageRecord struct {
+	ClientID  string    `db:"client_id" json:"client_id"`
+	Bucket    time.Time `db:"bucket" json:"bucket"`
+	Requests  int64     `db:"requests" json:"requests"`
+	Mutations int64     `db:"mutations" json:"mutations"`
+}
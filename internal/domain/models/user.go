@@ -1,8 +1,74 @@
 package models
 
+import "time"
+
 type User struct {
 	UserID   string `db:"user_id" json:"user_id"`
 	Username string `db:"username" json:"username"`
 	TeamName string `db:"team_name" json:"team_name"`
 	IsActive bool   `db:"is_active" json:"is_active"`
+
+	// MaxDailyAssignments caps the reviews automatically assigned to the
+	// user per UTC day. Zero means no cap.
+	MaxDailyAssignments int `db:"max_daily_assignments" json:"max_daily_assignments,omitempty"`
+
+	// AuthorOnly users author PRs but are never picked as reviewers. Unlike
+	// inactive users they stay in their teams and stats. AuthorOnlyUntil ends
+	// the period; nil keeps it until it is cleared.
+	AuthorOnly      bool       `db:"author_only" json:"author_only,omitempty"`
+	AuthorOnlyUntil *time.Time `db:"author_only_until" json:"author_only_until,omitempty"`
+
+	UserProfile
+}
+
+// UserSummary is a user with their review load. OpenReviews counts the open
+// PRs the user still has to review, TotalReviews every PR the user is a
+// reviewer of, whatever its status.
+type UserSummary struct {
+	User
+	OpenReviews  int `db:"open_reviews" json:"open_reviews"`
+	TotalReviews int `db:"total_reviews" json:"total_reviews"`
+}
+
+// UserProfile is how a user is shown and reached. DisplayName may use any
+// script, unlike Username which is the handle the forge knows. Empty fields
+// are unset.
+type UserProfile struct {
+	DisplayName string `db:"display_name" json:"display_name,omitempty"`
+	Email       string `db:"email" json:"email,omitempty"`
+	AvatarURL   string `db:"avatar_url" json:"avatar_url,omitempty"`
+	Locale      string `db:"locale" json:"locale,omitempty"`
+}
+
+// UserSettings are the user fields clients change with a merge patch.
+type UserSettings struct {
+	Username string `json:"username"`
+	IsActive bool   `json:"is_active"`
+
+	MaxDailyAssignments int `json:"max_daily_assignments,omitempty"`
+
+	DisplayName string `json:"display_name,omitempty"`
+	Email       string `json:"email,omitempty"`
+	AvatarURL   string `json:"avatar_url,omitempty"`
+	Locale      string `json:"locale,omitempty"`
+}
+
+func (s UserSettings) Profile() UserProfile {
+	return UserProfile{
+		DisplayName: s.DisplayName,
+		Email:       s.Email,
+		AvatarURL:   s.AvatarURL,
+		Locale:      s.Locale,
+	}
+}
+
+type BatchFailure struct {
+	UserID  string `json:"user_id"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type BatchActiveResult struct {
+	Updated []User         `json:"updated"`
+	Failed  []BatchFailure `json:"failed"`
 }
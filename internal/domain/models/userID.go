@@ -0,0 +1,39 @@
+package models
+
+import (
+	"strconv"
+
+	"pull-request-assigner/internal/apperrors"
+)
+
+// UserID is the numeric user id behind the API's "u<N>" form.
+type UserID int
+
+// ParseUserID accepts only "u" followed by decimal digits that fit the
+// database column, so short or malformed input is rejected instead of sliced.
+func ParseUserID(s string) (UserID, error) {
+	if len(s) < 2 || s[0] != 'u' {
+		return 0, apperrors.ErrInvalidUserID
+	}
+
+	for _, c := range s[1:] {
+		if c < '0' || c > '9' {
+			return 0, apperrors.ErrInvalidUserID
+		}
+	}
+
+	n, err := strconv.ParseInt(s[1:], 10, 32)
+	if err != nil {
+		return 0, apperrors.ErrInvalidUserID
+	}
+
+	return UserID(n), nil
+}
+
+func (id UserID) Int() int {
+	return int(id)
+}
+
+func (id UserID) String() string {
+	return "u" + strconv.Itoa(int(id))
+}
@@ -0,0 +1,72 @@
+package models
+
+import "time"
+
+// TeamWebhook is a callback a team registers for assignment events on the
+// PRs its members author. An empty Events list subscribes to all of them.
+// The secret signs every delivery and is never returned.
+type TeamWebhook struct {
+	ID        int64     `db:"id" json:"id"`
+	TeamName  string    `db:"team_name" json:"team_name"`
+	URL       string    `db:"url" json:"url"`
+	Secret    string    `db:"secret" json:"-"`
+	Events    []string  `db:"-" json:"events"`
+	IsActive  bool      `db:"is_active" json:"is_active"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+
+	LastDeliveryAt *time.Time `db:"last_delivery_at" json:"last_delivery_at,omitempty"`
+	LastStatus     *int       `db:"last_status" json:"last_status,omitempty"`
+	LastError      *string    `db:"last_error" json:"last_error,omitempty"`
+}
+
+// TeamWebhookUpdate carries a partial webhook change; nil fields are left
+// untouched.
+type TeamWebhookUpdate struct {
+	URL      *string
+	Secret   *string
+	Events   *[]string
+	IsActive *bool
+}
+
+// WebhookDelivery is the JSON body posted to a team webhook. Users holds the
+// profile of every user the data refers to, keyed by user ID, so receivers
+// can address them by display name and locale. Text is the event rendered
+// with the admin's webhook template, if one is saved.
+type WebhookDelivery struct {
+	Event         string          `json:"event"`
+	TeamName      string          `json:"team_name"`
+	PullRequestID string          `json:"pull_request_id"`
+	Data          map[string]any  `json:"data"`
+	Users         map[string]User `json:"users"`
+	Text          string          `json:"text,omitempty"`
+	SentAt        time.Time       `json:"sent_at"`
+}
+
+// Webhook delivery statuses: a pending delivery waits for its next attempt,
+// a failed one was rejected or ran out of attempts and is kept for admins.
+const (
+	WebhookDeliveryPending = "PENDING"
+	WebhookDeliveryFailed  = "FAILED"
+)
+
+// StoredWebhookDelivery is a delivery that did not succeed on its first
+// attempt. Attempt is the number of the next try while pending and of the
+// last one once failed. UserIDs are the users the body mentions; they are
+// only written with a new delivery.
+type StoredWebhookDelivery struct {
+	ID            int64      `db:"id" json:"id"`
+	WebhookID     int64      `db:"webhook_id" json:"webhook_id"`
+	TeamName      string     `db:"team_name" json:"team_name"`
+	URL           string     `db:"url" json:"url"`
+	Event         string     `db:"event" json:"event"`
+	PullRequestID string     `db:"pull_request_id" json:"pull_request_id"`
+	Body          []byte     `db:"body" json:"-"`
+	UserIDs       []int      `db:"-" json:"-"`
+	Attempt       int        `db:"attempt" json:"attempt"`
+	Status        string     `db:"status" json:"status"`
+	NextAttemptAt *time.Time `db:"next_attempt_at" json:"next_attempt_at,omitempty"`
+	LastStatus    *int       `db:"last_status" json:"last_status,omitempty"`
+	LastError     *string    `db:"last_error" json:"last_error,omitempty"`
+	CreatedAt     time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt     time.Time  `db:"updated_at" json:"updated_at"`
+}
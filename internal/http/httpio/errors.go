@@ -0,0 +1,82 @@
+package httpio
+
+import (
+	"errors"
+	"net/http"
+	"pull-request-assigner/internal/apperrors"
+)
+
+type errorMapping struct {
+	err     error
+	status  int
+	code    string
+	message string
+}
+
+// errorMappings holds the responses shared by every endpoint. Handlers only
+// switch on errors whose response is specific to the endpoint and leave the
+// rest to Responder.Fail.
+var errorMappings = []errorMapping{
+	{apperrors.ErrTeamNotFound, http.StatusNotFound, "NOT_FOUND", "resource not found"},
+	{apperrors.ErrUserNotFound, http.StatusNotFound, "NOT_FOUND", "resource not found"},
+	{apperrors.ErrPRNotFound, http.StatusNotFound, "NOT_FOUND", "resource not found"},
+	{apperrors.ErrPRAuthorNotFound, http.StatusNotFound, "NOT_FOUND", "resource not found"},
+	{apperrors.ErrReviewerNotAssigned, http.StatusNotFound, "NOT_FOUND", "resource not found"},
+	{apperrors.ErrTokenNotFound, http.StatusNotFound, "NOT_FOUND", "resource not found"},
+	{apperrors.ErrCertificationNotFound, http.StatusNotFound, "NOT_FOUND", "resource not found"},
+	{apperrors.ErrImpersonationNotFound, http.StatusNotFound, "NOT_FOUND", "resource not found"},
+	{apperrors.ErrPoolNotFound, http.StatusNotFound, "NOT_FOUND", "resource not found"},
+	{apperrors.ErrWebhookNotFound, http.StatusNotFound, "NOT_FOUND", "resource not found"},
+	{apperrors.ErrTemplateNotFound, http.StatusNotFound, "NOT_FOUND", "resource not found"},
+
+	{apperrors.ErrInvalidUserID, http.StatusBadRequest, "INVALID_USER_ID", "invalid user_id format"},
+	{apperrors.ErrAuthorNotInTeam, http.StatusBadRequest, "AUTHOR_NOT_IN_TEAM", "author is not a member of team_name"},
+	{apperrors.ErrTeamNameRequired, http.StatusBadRequest, "TEAM_NAME_REQUIRED", "team_name is required"},
+	{apperrors.ErrUsernameRequired, http.StatusBadRequest, "USERNAME_REQUIRED", "username is required"},
+	{apperrors.ErrAreaRequired, http.StatusBadRequest, "AREA_REQUIRED", "area is required"},
+	{apperrors.ErrPoolNameRequired, http.StatusBadRequest, "POOL_NAME_REQUIRED", "pool_name is required"},
+	{apperrors.ErrInvalidPoolPolicy, http.StatusBadRequest, "INVALID_POOL_POLICY",
+		"reviewers_per_pr must be between 1 and 5"},
+	{apperrors.ErrInvalidWebhook, http.StatusBadRequest, "INVALID_WEBHOOK",
+		"webhook needs an http(s) url, a secret and known events"},
+	{apperrors.ErrInvalidTemplate, http.StatusBadRequest, "INVALID_TEMPLATE",
+		"template needs a known event and channel and a body that renders"},
+	{apperrors.ErrInvalidProfile, http.StatusBadRequest, "INVALID_PROFILE",
+		"display_name, email, avatar_url or locale is invalid"},
+	{apperrors.ErrInvalidDailyCap, http.StatusBadRequest, "INVALID_DAILY_CAP",
+		"max_daily_assignments must be between 0 and 100"},
+	{apperrors.ErrInvalidCIStatus, http.StatusBadRequest, "INVALID_CI_STATUS",
+		"ci_status must be one of UNKNOWN, PENDING, SUCCESS, FAILURE"},
+
+	{apperrors.ErrStatsForbidden, http.StatusForbidden, "FORBIDDEN", "stats of this team are not visible to the caller"},
+
+	{apperrors.ErrPreconditionFailed, http.StatusPreconditionFailed, "PRECONDITION_FAILED",
+		"resource was modified since it was read"},
+
+	{apperrors.ErrWebhookExists, http.StatusConflict, "WEBHOOK_EXISTS", "team already has a webhook with this url"},
+	{apperrors.ErrPRAlreadyMerged, http.StatusConflict, "PR_MERGED", "PR is already merged"},
+	{apperrors.ErrPRClosed, http.StatusConflict, "PR_CLOSED", "PR is closed"},
+	{apperrors.ErrReviewerIsAuthor, http.StatusConflict, "REVIEWER_IS_AUTHOR", "author cannot review own PR"},
+	{apperrors.ErrReviewerInactive, http.StatusConflict, "REVIEWER_INACTIVE", "reviewer is inactive"},
+	{apperrors.ErrMergeWindowClosed, http.StatusConflict, "MERGE_WINDOW_CLOSED", "merging is blocked by the team's merge window"},
+	{apperrors.ErrMergeOverrideDenied, http.StatusForbidden, "FORBIDDEN", "only admins may override the merge window"},
+	{apperrors.ErrReviewerAuthorOnly, http.StatusConflict, "REVIEWER_AUTHOR_ONLY", "reviewer is author-only and cannot be assigned"},
+	{apperrors.ErrReviewerAssigned, http.StatusConflict, "ALREADY_ASSIGNED", "reviewer is already assigned to this PR"},
+	{apperrors.ErrReviewerNotInTeam, http.StatusConflict, "NOT_IN_TEAM", "reviewer is not a member of the author's team"},
+	{apperrors.ErrSecurityReviewerRequired, http.StatusConflict, "SECURITY_REVIEWER_REQUIRED",
+		"PR must keep a security team reviewer"},
+	{apperrors.ErrSecurityApprovalRequired, http.StatusConflict, "SECURITY_APPROVAL_REQUIRED",
+		"PR requires approval from a security team reviewer"},
+	{apperrors.ErrCertifiedReviewerRequired, http.StatusConflict, "CERTIFIED_REVIEWER_REQUIRED",
+		"PR must keep a certified reviewer for each required area"},
+}
+
+func lookup(err error) (errorMapping, bool) {
+	for _, mapping := range errorMappings {
+		if errors.Is(err, mapping.err) {
+			return mapping, true
+		}
+	}
+
+	return errorMapping{}, false
+}
@@ -0,0 +1,14 @@
+package httpio
+
+import (
+	"pull-request-assigner/internal/lib/i18n"
+	"testing"
+)
+
+func TestErrorMappingsTranslated(t *testing.T) {
+	for _, mapping := range errorMappings {
+		if i18n.Translate(i18n.LangRU, mapping.message) == mapping.message {
+			t.Errorf("%s: message %q has no %s translation", mapping.code, mapping.message, i18n.LangRU)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package httpio
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"pull-request-assigner/internal/lib/fieldset"
+	"pull-request-assigner/internal/lib/i18n"
+	"pull-request-assigner/internal/lib/localtime"
+	"pull-request-assigner/internal/lib/logger/sl"
+	"pull-request-assigner/internal/lib/mergepatch"
+	"time"
+)
+
+type (
+	ErrorResponse struct {
+		Error ErrorDetail `json:"error"`
+	}
+
+	ErrorDetail struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	}
+)
+
+type contextKey string
+
+const locationKey contextKey = "location"
+
+// WithLocation sets the time zone the request's response timestamps are
+// written in.
+func WithLocation(ctx context.Context, loc *time.Location) context.Context {
+	return context.WithValue(ctx, locationKey, loc)
+}
+
+// Location returns the time zone requested for the response, or nil to keep
+// timestamps as stored.
+func Location(r *http.Request) *time.Location {
+	loc, _ := r.Context().Value(locationKey).(*time.Location)
+	return loc
+}
+
+// Language negotiates the response language from the request's
+// Accept-Language header.
+func Language(r *http.Request) string {
+	return i18n.FromAcceptLanguage(r.Header.Get("Accept-Language"))
+}
+
+func WriteJSON(w http.ResponseWriter, status int, data any, log *slog.Logger) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		log.Error("failed to encode JSON response", sl.Err(err))
+	}
+}
+
+// WriteError translates message according to Accept-Language; message
+// doubles as a format string for args. Codes stay untranslated.
+func WriteError(w http.ResponseWriter, r *http.Request, log *slog.Logger, status int, code, message string, args ...any) {
+	lang := Language(r)
+	message = i18n.Translate(lang, message)
+	if len(args) > 0 {
+		message = fmt.Sprintf(message, args...)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Language", lang)
+	w.WriteHeader(status)
+
+	errorResp := ErrorResponse{
+		Error: ErrorDetail{
+			Code:    code,
+			Message: message,
+		},
+	}
+
+	if err := json.NewEncoder(w).Encode(errorResp); err != nil {
+		log.Error("failed to encode error response", sl.Err(err))
+	}
+}
+
+// Responder binds the writers to a handler's logger.
+type Responder struct {
+	log *slog.Logger
+}
+
+func NewResponder(log *slog.Logger) *Responder {
+	return &Responder{
+		log: log,
+	}
+}
+
+// JSON writes data with its timestamps in the time zone of the request.
+func (rs *Responder) JSON(w http.ResponseWriter, r *http.Request, status int, data any) {
+	WriteJSON(w, status, localtime.In(data, Location(r)), rs.log)
+}
+
+func (rs *Responder) Error(w http.ResponseWriter, r *http.Request, status int, code, message string, args ...any) {
+	WriteError(w, r, rs.log, status, code, message, args...)
+}
+
+// Fail writes the shared response for a known service error, or a 500 with
+// fallback as the message when err has no shared mapping.
+func (rs *Responder) Fail(w http.ResponseWriter, r *http.Request, err error, fallback string) {
+	var patchErr *mergepatch.InvalidPatchError
+	if errors.As(err, &patchErr) {
+		rs.Error(w, r, http.StatusBadRequest, "INVALID_PATCH", "invalid merge patch: %s", patchErr.Reason)
+		return
+	}
+
+	if mapped, ok := lookup(err); ok {
+		rs.Error(w, r, mapped.status, mapped.code, mapped.message)
+		return
+	}
+
+	rs.Error(w, r, http.StatusInternalServerError, "INTERNAL_ERROR", fallback)
+}
+
+// Tagged writes data with its entity tag, which clients send back in
+// If-Match to make a later change conditional.
+func (rs *Responder) Tagged(w http.ResponseWriter, r *http.Request, status int, etag string, data any) {
+	w.Header().Set("ETag", etag)
+	rs.JSON(w, r, status, data)
+}
+
+// Selected writes data reduced to the sparse fieldset requested with
+// ?fields=. Without the parameter the full response is written.
+func (rs *Responder) Selected(w http.ResponseWriter, r *http.Request, status int, data any) {
+	selected, err := fieldset.Apply(localtime.In(data, Location(r)), fieldset.Parse(r.URL.Query().Get("fields")))
+	if err != nil {
+		var fieldErr *fieldset.UnknownFieldError
+		if errors.As(err, &fieldErr) {
+			rs.Error(w, r, http.StatusBadRequest, "INVALID_FIELDS", "unknown field %s", fieldErr.Field)
+			return
+		}
+		rs.Error(w, r, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to select response fields")
+		return
+	}
+
+	WriteJSON(w, status, selected, rs.log)
+}
@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"pull-request-assigner/internal/domain/models"
+	"pull-request-assigner/internal/http/httpio"
+	"pull-request-assigner/internal/lib/logger/sl"
+)
+
+type PRActivityResponse struct {
+	PullRequestID string              `json:"pull_request_id"`
+	Activity      []models.PRActivity `json:"activity"`
+}
+
+type PRActivityViewer interface {
+	GetPRActivity(ctx context.Context, prID string) ([]models.PRActivity, error)
+}
+
+type ActivityHandler struct {
+	activityService PRActivityViewer
+	log             *slog.Logger
+	resp            *httpio.Responder
+}
+
+func NewActivityHandler(activityService PRActivityViewer, log *slog.Logger) *ActivityHandler {
+	return &ActivityHandler{
+		activityService: activityService,
+		log:             log,
+		resp:            httpio.NewResponder(log),
+	}
+}
+
+func (h *ActivityHandler) GetPRActivity(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.activity.GetPRActivity"
+
+	log := h.log.With(slog.String("op", op))
+
+	prID := r.URL.Query().Get("pull_request_id")
+	if prID == "" {
+		log.Error("pull_request_id is required")
+		h.resp.Error(w, r, http.StatusBadRequest, "PR_ID_REQUIRED", "pull_request_id query parameter is required")
+		return
+	}
+
+	activity, err := h.activityService.GetPRActivity(r.Context(), prID)
+	if err != nil {
+		log.Error("failed to get PR activity", sl.Err(err))
+		h.resp.Fail(w, r, err, "failed to get PR activity")
+		return
+	}
+
+	h.resp.JSON(w, r, http.StatusOK, PRActivityResponse{
+		PullRequestID: prID,
+		Activity:      activity,
+	})
+	log.Info("PR activity returned successfully", slog.Int("entry_count", len(activity)))
+}
@@ -0,0 +1,21 @@
+package handler
+
+import (
+	"net/http"
+	"pull-request-assigner/internal/apperrors"
+	"testing"
+)
+
+func TestActivityHandlerErrors(t *testing.T) {
+	mock := &prActivityViewerMock{}
+	h := NewActivityHandler(mock, discardLogger())
+
+	runErrorCases(t, &mock.mockBase, []errorCase{
+		{name: "missing pr", serve: h.GetPRActivity, method: http.MethodGet, target: "/pullRequest/activity",
+			status: http.StatusBadRequest, code: "PR_ID_REQUIRED"},
+		{name: "pr not found", serve: h.GetPRActivity, method: http.MethodGet, target: "/pullRequest/activity?pull_request_id=pr-1",
+			err: apperrors.ErrPRNotFound, status: http.StatusNotFound, code: "NOT_FOUND", called: "GetPRActivity"},
+		{name: "internal", serve: h.GetPRActivity, method: http.MethodGet, target: "/pullRequest/activity?pull_request_id=pr-1",
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "GetPRActivity"},
+	})
+}
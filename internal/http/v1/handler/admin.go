@@ -0,0 +1,428 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"pull-request-assigner/internal/apperrors"
+	"pull-request-assigner/internal/domain/models"
+	"pull-request-assigner/internal/http/httpio"
+	"pull-request-assigner/internal/lib/logger/sl"
+	"pull-request-assigner/internal/service"
+	"time"
+)
+
+type (
+	GetArchiveResponse struct {
+		Teams []models.ArchivedTeam `json:"teams"`
+		Users []models.ArchivedUser `json:"users"`
+	}
+
+	RestoreRequest struct {
+		TeamName string `json:"team_name"`
+		UserID   string `json:"user_id"`
+	}
+
+	RestoreResponse struct {
+		TeamName string `json:"team_name,omitempty"`
+		UserID   string `json:"user_id,omitempty"`
+		Restored bool   `json:"restored"`
+	}
+
+	AnonymizeUserRequest struct {
+		UserID            string `json:"user_id"`
+		ConfirmationToken string `json:"confirmation_token"`
+	}
+
+	AnonymizeUserResponse struct {
+		Result *models.AnonymizationResult `json:"result"`
+	}
+
+	SimulateRequest struct {
+		Strategy       string `json:"strategy"`
+		TeamName       string `json:"team_name"`
+		ReviewersPerPR int    `json:"reviewers_per_pr"`
+		WindowDays     int    `json:"window_days"`
+		Seed           int64  `json:"seed"`
+	}
+
+	SimulateResponse struct {
+		Report *models.SimulationReport `json:"report"`
+	}
+
+	DBCheckResponse struct {
+		Queries []models.QueryPlanCheck `json:"queries"`
+		Flagged int                     `json:"flagged"`
+	}
+
+	UsageResponse struct {
+		From    time.Time            `json:"from"`
+		To      time.Time            `json:"to"`
+		Bucket  string               `json:"bucket"`
+		Records []models.UsageRecord `json:"records"`
+	}
+
+	JobsResponse struct {
+		Jobs []models.Job `json:"jobs"`
+	}
+
+	MigrationsResponse struct {
+		Migrations *models.MigrationStatus `json:"migrations"`
+	}
+
+	MembershipResponse struct {
+		Membership *models.MembershipReport `json:"membership"`
+	}
+)
+
+type AdminManager interface {
+	GetArchive(ctx context.Context) ([]models.ArchivedTeam, []models.ArchivedUser, error)
+	RestoreTeam(ctx context.Context, teamName string) error
+	RestoreUser(ctx context.Context, userID string) error
+	AnonymizeUser(ctx context.Context, userID string, confirmationToken string) (*models.AnonymizationResult, error)
+	Simulate(ctx context.Context, strategy string, teamName string, reviewersPerPR int, windowDays int, seed int64) (*models.SimulationReport, error)
+	CheckDB(ctx context.Context) ([]models.QueryPlanCheck, error)
+	GetJobs(ctx context.Context) ([]models.Job, error)
+	GetMigrationStatus(ctx context.Context) (*models.MigrationStatus, error)
+	CheckMembership(ctx context.Context, repair bool) (*models.MembershipReport, error)
+}
+
+type UsageReporter interface {
+	GetUsage(ctx context.Context, from time.Time, to time.Time, bucket string) ([]models.UsageRecord, error)
+}
+
+type AdminHandler struct {
+	adminService AdminManager
+	usageService UsageReporter
+	log          *slog.Logger
+	resp         *httpio.Responder
+}
+
+func NewAdminHandler(adminService AdminManager, usageService UsageReporter, log *slog.Logger) *AdminHandler {
+	return &AdminHandler{
+		adminService: adminService,
+		usageService: usageService,
+		log:          log,
+		resp:         httpio.NewResponder(log),
+	}
+}
+
+func (h *AdminHandler) GetArchive(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.admin.GetArchive"
+
+	log := h.log.With(slog.String("op", op))
+
+	teams, users, err := h.adminService.GetArchive(r.Context())
+	if err != nil {
+		log.Error("failed to get archive", sl.Err(err))
+		h.resp.Error(w, r, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to get archive")
+		return
+	}
+
+	response := GetArchiveResponse{
+		Teams: teams,
+		Users: users,
+	}
+
+	h.resp.JSON(w, r, http.StatusOK, response)
+	log.Info("archive returned successfully")
+}
+
+func (h *AdminHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.admin.Restore"
+
+	log := h.log.With(slog.String("op", op))
+
+	var req RestoreRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("invalid request body", sl.Err(err))
+		h.resp.Error(w, r, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+		return
+	}
+
+	if (req.TeamName == "") == (req.UserID == "") {
+		log.Error("exactly one of team_name or user_id is required")
+		h.resp.Error(w, r, http.StatusBadRequest, "INVALID_REQUEST", "exactly one of team_name or user_id is required")
+		return
+	}
+
+	var err error
+	if req.TeamName != "" {
+		err = h.adminService.RestoreTeam(r.Context(), req.TeamName)
+	} else {
+		err = h.adminService.RestoreUser(r.Context(), req.UserID)
+	}
+
+	if err != nil {
+		log.Error("failed to restore", sl.Err(err))
+
+		switch {
+		case errors.Is(err, apperrors.ErrTeamNotArchived):
+			h.resp.Error(w, r, http.StatusNotFound, "NOT_FOUND", "archived team not found")
+		default:
+			h.resp.Fail(w, r, err, "failed to restore")
+		}
+		return
+	}
+
+	response := RestoreResponse{
+		TeamName: req.TeamName,
+		UserID:   req.UserID,
+		Restored: true,
+	}
+
+	h.resp.JSON(w, r, http.StatusOK, response)
+	log.Info("restored successfully")
+}
+
+// AdminDisabledHandler rejects admin requests while ADMIN_SECRET is unset or
+// left at its default.
+type AdminDisabledHandler struct {
+	log  *slog.Logger
+	resp *httpio.Responder
+}
+
+func NewAdminDisabledHandler(log *slog.Logger) *AdminDisabledHandler {
+	return &AdminDisabledHandler{
+		log:  log,
+		resp: httpio.NewResponder(log),
+	}
+}
+
+func (h *AdminDisabledHandler) Reject(w http.ResponseWriter, r *http.Request) {
+	h.log.Warn("admin route called while admin routes are disabled", slog.String("path", r.URL.Path))
+	h.resp.Error(w, r, http.StatusServiceUnavailable, "ADMIN_DISABLED", "admin routes are disabled until ADMIN_SECRET is set")
+}
+
+func (h *AdminHandler) AnonymizeUser(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.admin.AnonymizeUser"
+
+	log := h.log.With(slog.String("op", op))
+
+	var req AnonymizeUserRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("invalid request body", sl.Err(err))
+		h.resp.Error(w, r, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+		return
+	}
+
+	if req.UserID == "" {
+		log.Error("user_id is required")
+		h.resp.Error(w, r, http.StatusBadRequest, "USER_ID_REQUIRED", "user_id is required")
+		return
+	}
+
+	result, err := h.adminService.AnonymizeUser(r.Context(), req.UserID, req.ConfirmationToken)
+	if err != nil {
+		log.Error("failed to anonymize user", sl.Err(err))
+
+		switch {
+		case errors.Is(err, apperrors.ErrUserActive):
+			h.resp.Error(w, r, http.StatusConflict, "USER_ACTIVE", "only deactivated users can be anonymized")
+		case errors.Is(err, apperrors.ErrUserAnonymized):
+			h.resp.Error(w, r, http.StatusConflict, "ALREADY_ANONYMIZED", "user is already anonymized")
+		case errors.Is(err, apperrors.ErrInvalidConfirmation):
+			h.resp.Error(w, r, http.StatusBadRequest, "INVALID_CONFIRMATION", "confirmation_token does not match")
+		default:
+			h.resp.Fail(w, r, err, "failed to anonymize user")
+		}
+		return
+	}
+
+	status := http.StatusOK
+	if !result.Anonymized {
+		status = http.StatusAccepted
+	}
+
+	h.resp.JSON(w, r, status, AnonymizeUserResponse{Result: result})
+	log.Info("anonymization request handled", slog.Bool("anonymized", result.Anonymized))
+}
+
+func (h *AdminHandler) Simulate(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.admin.Simulate"
+
+	log := h.log.With(slog.String("op", op))
+
+	var req SimulateRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("invalid request body", sl.Err(err))
+		h.resp.Error(w, r, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+		return
+	}
+
+	if req.Strategy == "" {
+		req.Strategy = models.StrategyRandom
+	}
+
+	if req.ReviewersPerPR < 0 {
+		log.Error("reviewers_per_pr must not be negative")
+		h.resp.Error(w, r, http.StatusBadRequest, "INVALID_REQUEST", "reviewers_per_pr must not be negative")
+		return
+	}
+
+	report, err := h.adminService.Simulate(r.Context(), req.Strategy, req.TeamName, req.ReviewersPerPR, req.WindowDays, req.Seed)
+	if err != nil {
+		log.Error("failed to run simulation", sl.Err(err))
+
+		switch {
+		case errors.Is(err, apperrors.ErrUnknownStrategy):
+			h.resp.Error(w, r, http.StatusBadRequest, "UNKNOWN_STRATEGY", "strategy must be one of random, least_loaded")
+		case errors.Is(err, apperrors.ErrInvalidStatsWindow):
+			h.resp.Error(w, r, http.StatusBadRequest, "INVALID_WINDOW",
+				"window_days must be between 1 and %d", service.MaxStatsWindowDays)
+		default:
+			h.resp.Fail(w, r, err, "failed to run simulation")
+		}
+		return
+	}
+
+	h.resp.JSON(w, r, http.StatusOK, SimulateResponse{Report: report})
+	log.Info("simulation finished successfully")
+}
+
+func (h *AdminHandler) CheckDB(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.admin.CheckDB"
+
+	log := h.log.With(slog.String("op", op))
+
+	checks, err := h.adminService.CheckDB(r.Context())
+	if err != nil {
+		log.Error("failed to check database", sl.Err(err))
+		h.resp.Error(w, r, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to check database")
+		return
+	}
+
+	flagged := 0
+	for _, check := range checks {
+		if check.Flagged {
+			flagged++
+		}
+	}
+
+	h.resp.JSON(w, r, http.StatusOK, DBCheckResponse{Queries: checks, Flagged: flagged})
+	log.Info("database check finished", slog.Int("flagged", flagged))
+}
+
+func (h *AdminHandler) GetUsage(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.admin.GetUsage"
+
+	log := h.log.With(slog.String("op", op))
+
+	query := r.URL.Query()
+
+	to := time.Now().UTC()
+	if raw := query.Get("to"); raw != "" {
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			log.Error("invalid to parameter", sl.Err(err))
+			h.resp.Error(w, r, http.StatusBadRequest, "INVALID_REQUEST", "to must be an RFC3339 timestamp")
+			return
+		}
+		to = parsed
+	}
+
+	from := to.Add(-24 * time.Hour)
+	if raw := query.Get("from"); raw != "" {
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			log.Error("invalid from parameter", sl.Err(err))
+			h.resp.Error(w, r, http.StatusBadRequest, "INVALID_REQUEST", "from must be an RFC3339 timestamp")
+			return
+		}
+		from = parsed
+	}
+
+	bucket := query.Get("bucket")
+	if bucket == "" {
+		bucket = models.UsageBucketHour
+	}
+
+	records, err := h.usageService.GetUsage(r.Context(), from, to, bucket)
+	if err != nil {
+		log.Error("failed to get usage", sl.Err(err))
+
+		switch {
+		case errors.Is(err, apperrors.ErrInvalidUsageQuery):
+			h.resp.Error(w, r, http.StatusBadRequest, "INVALID_REQUEST", "bucket must be hour or day and the window at most 31 days")
+		default:
+			h.resp.Fail(w, r, err, "failed to get usage")
+		}
+		return
+	}
+
+	response := UsageResponse{
+		From:    from,
+		To:      to,
+		Bucket:  bucket,
+		Records: records,
+	}
+
+	h.resp.JSON(w, r, http.StatusOK, response)
+	log.Info("usage returned successfully", slog.Int("records", len(records)))
+}
+
+func (h *AdminHandler) GetJobs(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.admin.GetJobs"
+
+	log := h.log.With(slog.String("op", op))
+
+	jobs, err := h.adminService.GetJobs(r.Context())
+	if err != nil {
+		log.Error("failed to get jobs", sl.Err(err))
+		h.resp.Error(w, r, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to get jobs")
+		return
+	}
+
+	h.resp.JSON(w, r, http.StatusOK, JobsResponse{Jobs: jobs})
+	log.Info("jobs returned successfully", slog.Int("jobs", len(jobs)))
+}
+
+func (h *AdminHandler) GetMigrations(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.admin.GetMigrations"
+
+	log := h.log.With(slog.String("op", op))
+
+	status, err := h.adminService.GetMigrationStatus(r.Context())
+	if err != nil {
+		log.Error("failed to get migration status", sl.Err(err))
+		h.resp.Error(w, r, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to get migration status")
+		return
+	}
+
+	h.resp.JSON(w, r, http.StatusOK, MigrationsResponse{Migrations: status})
+	log.Info("migration status returned successfully",
+		slog.Uint64("version", uint64(status.Version)),
+		slog.Bool("dirty", status.Dirty))
+}
+
+func (h *AdminHandler) GetMembership(w http.ResponseWriter, r *http.Request) {
+	h.checkMembership(w, r, false)
+}
+
+func (h *AdminHandler) RepairMembership(w http.ResponseWriter, r *http.Request) {
+	h.checkMembership(w, r, true)
+}
+
+func (h *AdminHandler) checkMembership(w http.ResponseWriter, r *http.Request, repair bool) {
+	const op = "handler.admin.checkMembership"
+
+	log := h.log.With(
+		slog.String("op", op),
+		slog.Bool("repair", repair),
+	)
+
+	report, err := h.adminService.CheckMembership(r.Context(), repair)
+	if err != nil {
+		log.Error("failed to check team membership", sl.Err(err))
+		h.resp.Error(w, r, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to check team membership")
+		return
+	}
+
+	h.resp.JSON(w, r, http.StatusOK, MembershipResponse{Membership: report})
+	log.Info("team membership checked successfully", slog.Int("drift", len(report.Drift)))
+}
@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"net/http"
+	"pull-request-assigner/internal/apperrors"
+	"testing"
+)
+
+func TestAdminHandlerErrors(t *testing.T) {
+	admin := &adminManagerMock{}
+	usage := &usageReporterMock{}
+	h := NewAdminHandler(admin, usage, discardLogger())
+
+	runErrorCases(t, &admin.mockBase, []errorCase{
+		{name: "archive internal", serve: h.GetArchive, method: http.MethodGet, target: "/admin/archive",
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "GetArchive"},
+
+		{name: "restore invalid body", serve: h.Restore, target: "/admin/restore", body: "{",
+			status: http.StatusBadRequest, code: "INVALID_REQUEST"},
+		{name: "restore ambiguous", serve: h.Restore, target: "/admin/restore", body: `{"team_name":"backend","user_id":"u1"}`,
+			status: http.StatusBadRequest, code: "INVALID_REQUEST"},
+		{name: "restore team not archived", serve: h.Restore, target: "/admin/restore", body: `{"team_name":"backend"}`,
+			err: apperrors.ErrTeamNotArchived, status: http.StatusNotFound, code: "NOT_FOUND", called: "RestoreTeam"},
+		{name: "restore user not found", serve: h.Restore, target: "/admin/restore", body: `{"user_id":"u1"}`,
+			err: apperrors.ErrUserNotFound, status: http.StatusNotFound, code: "NOT_FOUND", called: "RestoreUser"},
+		{name: "restore internal", serve: h.Restore, target: "/admin/restore", body: `{"user_id":"u1"}`,
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "RestoreUser"},
+
+		{name: "anonymize invalid body", serve: h.AnonymizeUser, target: "/admin/anonymizeUser", body: "{",
+			status: http.StatusBadRequest, code: "INVALID_REQUEST"},
+		{name: "anonymize missing user", serve: h.AnonymizeUser, target: "/admin/anonymizeUser", body: `{}`,
+			status: http.StatusBadRequest, code: "USER_ID_REQUIRED"},
+		{name: "anonymize not found", serve: h.AnonymizeUser, target: "/admin/anonymizeUser", body: `{"user_id":"u1"}`,
+			err: apperrors.ErrUserNotFound, status: http.StatusNotFound, code: "NOT_FOUND", called: "AnonymizeUser"},
+		{name: "anonymize invalid user", serve: h.AnonymizeUser, target: "/admin/anonymizeUser", body: `{"user_id":"u1"}`,
+			err: apperrors.ErrInvalidUserID, status: http.StatusBadRequest, code: "INVALID_USER_ID", called: "AnonymizeUser"},
+		{name: "anonymize active", serve: h.AnonymizeUser, target: "/admin/anonymizeUser", body: `{"user_id":"u1"}`,
+			err: apperrors.ErrUserActive, status: http.StatusConflict, code: "USER_ACTIVE", called: "AnonymizeUser"},
+		{name: "anonymize already anonymized", serve: h.AnonymizeUser, target: "/admin/anonymizeUser", body: `{"user_id":"u1"}`,
+			err: apperrors.ErrUserAnonymized, status: http.StatusConflict, code: "ALREADY_ANONYMIZED", called: "AnonymizeUser"},
+		{name: "anonymize wrong confirmation", serve: h.AnonymizeUser, target: "/admin/anonymizeUser", body: `{"user_id":"u1"}`,
+			err: apperrors.ErrInvalidConfirmation, status: http.StatusBadRequest, code: "INVALID_CONFIRMATION", called: "AnonymizeUser"},
+		{name: "anonymize internal", serve: h.AnonymizeUser, target: "/admin/anonymizeUser", body: `{"user_id":"u1"}`,
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "AnonymizeUser"},
+
+		{name: "simulate invalid body", serve: h.Simulate, target: "/admin/simulate", body: "{",
+			status: http.StatusBadRequest, code: "INVALID_REQUEST"},
+		{name: "simulate negative reviewers", serve: h.Simulate, target: "/admin/simulate", body: `{"reviewers_per_pr":-1}`,
+			status: http.StatusBadRequest, code: "INVALID_REQUEST"},
+		{name: "simulate unknown strategy", serve: h.Simulate, target: "/admin/simulate", body: `{"strategy":"psychic"}`,
+			err: apperrors.ErrUnknownStrategy, status: http.StatusBadRequest, code: "UNKNOWN_STRATEGY", called: "Simulate"},
+		{name: "simulate invalid window", serve: h.Simulate, target: "/admin/simulate", body: `{"window_days":400}`,
+			err: apperrors.ErrInvalidStatsWindow, status: http.StatusBadRequest, code: "INVALID_WINDOW", called: "Simulate"},
+		{name: "simulate team not found", serve: h.Simulate, target: "/admin/simulate", body: `{"team_name":"ghost"}`,
+			err: apperrors.ErrTeamNotFound, status: http.StatusNotFound, code: "NOT_FOUND", called: "Simulate"},
+		{name: "simulate internal", serve: h.Simulate, target: "/admin/simulate", body: `{}`,
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "Simulate"},
+
+		{name: "db check internal", serve: h.CheckDB, method: http.MethodGet, target: "/admin/dbcheck",
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "CheckDB"},
+		{name: "jobs internal", serve: h.GetJobs, method: http.MethodGet, target: "/admin/jobs",
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "GetJobs"},
+		{name: "migrations internal", serve: h.GetMigrations, method: http.MethodGet, target: "/admin/migrations",
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "GetMigrationStatus"},
+		{name: "membership internal", serve: h.GetMembership, method: http.MethodGet, target: "/admin/membership",
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "CheckMembership"},
+		{name: "membership repair internal", serve: h.RepairMembership, target: "/admin/membership/repair",
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "CheckMembership"},
+	})
+
+	runErrorCases(t, &usage.mockBase, []errorCase{
+		{name: "usage invalid to", serve: h.GetUsage, method: http.MethodGet, target: "/admin/usage?to=yesterday",
+			status: http.StatusBadRequest, code: "INVALID_REQUEST"},
+		{name: "usage invalid from", serve: h.GetUsage, method: http.MethodGet, target: "/admin/usage?from=yesterday",
+			status: http.StatusBadRequest, code: "INVALID_REQUEST"},
+		{name: "usage invalid query", serve: h.GetUsage, method: http.MethodGet, target: "/admin/usage?bucket=week",
+			err: apperrors.ErrInvalidUsageQuery, status: http.StatusBadRequest, code: "INVALID_REQUEST", called: "GetUsage"},
+		{name: "usage internal", serve: h.GetUsage, method: http.MethodGet, target: "/admin/usage",
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "GetUsage"},
+	})
+}
+
+func TestAdminDisabledHandler(t *testing.T) {
+	h := NewAdminDisabledHandler(discardLogger())
+
+	runErrorCases(t, &mockBase{}, []errorCase{
+		{name: "mutation", serve: h.Reject, target: "/admin/anonymizeUser", body: `{"user_id":"u1"}`,
+			status: http.StatusServiceUnavailable, code: "ADMIN_DISABLED"},
+		{name: "read", serve: h.Reject, method: http.MethodGet, target: "/admin/tokens/list",
+			status: http.StatusServiceUnavailable, code: "ADMIN_DISABLED"},
+	})
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"pull-request-assigner/internal/domain/models"
+	"pull-request-assigner/internal/http/httpio"
+	"pull-request-assigner/internal/lib/logger/sl"
+)
+
+type (
+	AssignmentRepairsResponse struct {
+		Repairs []models.AssignmentRepair `json:"repairs"`
+	}
+
+	AssignmentRepairRunResponse struct {
+		Report *models.AssignmentRepairReport `json:"report"`
+	}
+)
+
+type AssignmentRepairer interface {
+	GetRepairs(ctx context.Context) ([]models.AssignmentRepair, error)
+	RunRepairs(ctx context.Context) (*models.AssignmentRepairReport, error)
+}
+
+type AssignmentRepairHandler struct {
+	repairService AssignmentRepairer
+	log           *slog.Logger
+	resp          *httpio.Responder
+}
+
+func NewAssignmentRepairHandler(repairService AssignmentRepairer, log *slog.Logger) *AssignmentRepairHandler {
+	return &AssignmentRepairHandler{
+		repairService: repairService,
+		log:           log,
+		resp:          httpio.NewResponder(log),
+	}
+}
+
+func (h *AssignmentRepairHandler) GetRepairs(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.assignmentRepair.GetRepairs"
+
+	log := h.log.With(slog.String("op", op))
+
+	repairs, err := h.repairService.GetRepairs(r.Context())
+	if err != nil {
+		log.Error("failed to get assignment repairs", sl.Err(err))
+		h.resp.Error(w, r, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to get assignment repairs")
+		return
+	}
+
+	h.resp.JSON(w, r, http.StatusOK, AssignmentRepairsResponse{Repairs: repairs})
+}
+
+// RunRepairs retries the due repairs now instead of waiting for the worker.
+func (h *AssignmentRepairHandler) RunRepairs(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.assignmentRepair.RunRepairs"
+
+	log := h.log.With(slog.String("op", op))
+
+	report, err := h.repairService.RunRepairs(r.Context())
+	if err != nil {
+		log.Error("failed to run assignment repairs", sl.Err(err))
+		h.resp.Error(w, r, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to run assignment repairs")
+		return
+	}
+
+	h.resp.JSON(w, r, http.StatusOK, AssignmentRepairRunResponse{Report: report})
+	log.Info("assignment repairs run",
+		slog.Int("repaired", len(report.Repaired)),
+		slog.Int("failed", len(report.Failed)))
+}
@@ -0,0 +1,18 @@
+package handler
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestAssignmentRepairHandlerErrors(t *testing.T) {
+	mock := &assignmentRepairerMock{}
+	h := NewAssignmentRepairHandler(mock, discardLogger())
+
+	runErrorCases(t, &mock.mockBase, []errorCase{
+		{name: "list internal", serve: h.GetRepairs, method: http.MethodGet, target: "/admin/assignmentRepairs",
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "GetRepairs"},
+		{name: "run internal", serve: h.RunRepairs, target: "/admin/assignmentRepairs/run",
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "RunRepairs"},
+	})
+}
@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"pull-request-assigner/internal/apperrors"
+	"pull-request-assigner/internal/domain/models"
+	"pull-request-assigner/internal/http/httpio"
+	"pull-request-assigner/internal/lib/logger/sl"
+)
+
+type (
+	BackfillResponse struct {
+		Backfill *models.BackfillResult `json:"backfill"`
+	}
+)
+
+type PRBackfiller interface {
+	Backfill(ctx context.Context) (*models.BackfillResult, error)
+}
+
+type BackfillHandler struct {
+	backfillService PRBackfiller
+	log             *slog.Logger
+	resp            *httpio.Responder
+}
+
+func NewBackfillHandler(backfillService PRBackfiller, log *slog.Logger) *BackfillHandler {
+	return &BackfillHandler{
+		backfillService: backfillService,
+		log:             log,
+		resp:            httpio.NewResponder(log),
+	}
+}
+
+func (h *BackfillHandler) Backfill(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.backfill.Backfill"
+
+	log := h.log.With(slog.String("op", op))
+
+	result, err := h.backfillService.Backfill(r.Context())
+	if err != nil {
+		log.Error("failed to backfill PRs", sl.Err(err))
+
+		switch {
+		case errors.Is(err, apperrors.ErrForgeNotConfigured):
+			h.resp.Error(w, r, http.StatusServiceUnavailable, "FORGE_NOT_CONFIGURED", "forge is not configured")
+		case errors.Is(err, apperrors.ErrForgeUnavailable):
+			h.resp.Error(w, r, http.StatusBadGateway, "FORGE_UNAVAILABLE", "failed to fetch open PRs from forge")
+		default:
+			h.resp.Fail(w, r, err, "failed to backfill PRs")
+		}
+		return
+	}
+
+	h.resp.JSON(w, r, http.StatusOK, BackfillResponse{Backfill: result})
+	log.Info("PRs backfilled successfully", slog.Int("created", len(result.Created)))
+}
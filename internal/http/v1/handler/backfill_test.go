@@ -0,0 +1,23 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"pull-request-assigner/internal/apperrors"
+	"testing"
+)
+
+func TestBackfillHandlerErrors(t *testing.T) {
+	mock := &prBackfillerMock{}
+	h := NewBackfillHandler(mock, discardLogger())
+
+	runErrorCases(t, &mock.mockBase, []errorCase{
+		{name: "forge not configured", serve: h.Backfill, target: "/admin/backfill",
+			err: apperrors.ErrForgeNotConfigured, status: http.StatusServiceUnavailable, code: "FORGE_NOT_CONFIGURED", called: "Backfill"},
+		{name: "forge unavailable", serve: h.Backfill, target: "/admin/backfill",
+			err:    fmt.Errorf("service.backfill.Backfill: %w: %w", apperrors.ErrForgeUnavailable, errUnexpected),
+			status: http.StatusBadGateway, code: "FORGE_UNAVAILABLE", called: "Backfill"},
+		{name: "backfill internal", serve: h.Backfill, target: "/admin/backfill",
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "Backfill"},
+	})
+}
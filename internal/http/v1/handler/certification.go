@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"pull-request-assigner/internal/domain/models"
+	"pull-request-assigner/internal/http/httpio"
+	"pull-request-assigner/internal/lib/logger/sl"
+)
+
+type (
+	CertificationRequest struct {
+		UserID string `json:"user_id"`
+		Area   string `json:"area"`
+	}
+
+	GrantCertificationResponse struct {
+		Certification *models.Certification `json:"certification"`
+	}
+
+	RevokeCertificationResponse struct {
+		UserID  string `json:"user_id"`
+		Area    string `json:"area"`
+		Revoked bool   `json:"revoked"`
+	}
+
+	ListCertificationsResponse struct {
+		Certifications []models.Certification `json:"certifications"`
+	}
+)
+
+type CertificationManager interface {
+	GrantCertification(ctx context.Context, userID string, area string) (*models.Certification, error)
+	RevokeCertification(ctx context.Context, userID string, area string) error
+	ListCertifications(ctx context.Context, userID string, area string) ([]models.Certification, error)
+}
+
+type CertificationHandler struct {
+	certificationService CertificationManager
+	log                  *slog.Logger
+	resp                 *httpio.Responder
+}
+
+func NewCertificationHandler(certificationService CertificationManager, log *slog.Logger) *CertificationHandler {
+	return &CertificationHandler{
+		certificationService: certificationService,
+		log:                  log,
+		resp:                 httpio.NewResponder(log),
+	}
+}
+
+func (h *CertificationHandler) GrantCertification(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.certification.GrantCertification"
+
+	log := h.log.With(slog.String("op", op))
+
+	var req CertificationRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("invalid request body", sl.Err(err))
+		h.resp.Error(w, r, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+		return
+	}
+
+	certification, err := h.certificationService.GrantCertification(r.Context(), req.UserID, req.Area)
+	if err != nil {
+		log.Error("failed to grant certification", sl.Err(err))
+		h.resp.Fail(w, r, err, "failed to grant certification")
+		return
+	}
+
+	h.resp.JSON(w, r, http.StatusOK, GrantCertificationResponse{Certification: certification})
+	log.Info("certification granted successfully")
+}
+
+func (h *CertificationHandler) RevokeCertification(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.certification.RevokeCertification"
+
+	log := h.log.With(slog.String("op", op))
+
+	var req CertificationRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("invalid request body", sl.Err(err))
+		h.resp.Error(w, r, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+		return
+	}
+
+	err := h.certificationService.RevokeCertification(r.Context(), req.UserID, req.Area)
+	if err != nil {
+		log.Error("failed to revoke certification", sl.Err(err))
+		h.resp.Fail(w, r, err, "failed to revoke certification")
+		return
+	}
+
+	response := RevokeCertificationResponse{
+		UserID:  req.UserID,
+		Area:    req.Area,
+		Revoked: true,
+	}
+
+	h.resp.JSON(w, r, http.StatusOK, response)
+	log.Info("certification revoked successfully")
+}
+
+func (h *CertificationHandler) ListCertifications(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.certification.ListCertifications"
+
+	log := h.log.With(slog.String("op", op))
+
+	userID := r.URL.Query().Get("user_id")
+	area := r.URL.Query().Get("area")
+
+	certifications, err := h.certificationService.ListCertifications(r.Context(), userID, area)
+	if err != nil {
+		log.Error("failed to list certifications", sl.Err(err))
+		h.resp.Fail(w, r, err, "failed to list certifications")
+		return
+	}
+
+	h.resp.JSON(w, r, http.StatusOK, ListCertificationsResponse{Certifications: certifications})
+	log.Info("certifications listed successfully")
+}
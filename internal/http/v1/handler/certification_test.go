@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"net/http"
+	"pull-request-assigner/internal/apperrors"
+	"testing"
+)
+
+func TestCertificationHandlerErrors(t *testing.T) {
+	mock := &certificationManagerMock{}
+	h := NewCertificationHandler(mock, discardLogger())
+
+	const body = `{"user_id":"u1","area":"payments"}`
+
+	runErrorCases(t, &mock.mockBase, []errorCase{
+		{name: "grant invalid body", serve: h.GrantCertification, target: "/certifications/grant", body: "{",
+			status: http.StatusBadRequest, code: "INVALID_REQUEST"},
+		{name: "grant invalid user", serve: h.GrantCertification, target: "/certifications/grant", body: body,
+			err: apperrors.ErrInvalidUserID, status: http.StatusBadRequest, code: "INVALID_USER_ID", called: "GrantCertification"},
+		{name: "grant area required", serve: h.GrantCertification, target: "/certifications/grant", body: body,
+			err: apperrors.ErrAreaRequired, status: http.StatusBadRequest, code: "AREA_REQUIRED", called: "GrantCertification"},
+		{name: "grant user not found", serve: h.GrantCertification, target: "/certifications/grant", body: body,
+			err: apperrors.ErrUserNotFound, status: http.StatusNotFound, code: "NOT_FOUND", called: "GrantCertification"},
+		{name: "grant internal", serve: h.GrantCertification, target: "/certifications/grant", body: body,
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "GrantCertification"},
+
+		{name: "revoke invalid body", serve: h.RevokeCertification, target: "/certifications/revoke", body: "{",
+			status: http.StatusBadRequest, code: "INVALID_REQUEST"},
+		{name: "revoke not found", serve: h.RevokeCertification, target: "/certifications/revoke", body: body,
+			err: apperrors.ErrCertificationNotFound, status: http.StatusNotFound, code: "NOT_FOUND", called: "RevokeCertification"},
+		{name: "revoke area required", serve: h.RevokeCertification, target: "/certifications/revoke", body: body,
+			err: apperrors.ErrAreaRequired, status: http.StatusBadRequest, code: "AREA_REQUIRED", called: "RevokeCertification"},
+		{name: "revoke internal", serve: h.RevokeCertification, target: "/certifications/revoke", body: body,
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "RevokeCertification"},
+
+		{name: "list invalid user", serve: h.ListCertifications, method: http.MethodGet, target: "/certifications/list?user_id=bad",
+			err: apperrors.ErrInvalidUserID, status: http.StatusBadRequest, code: "INVALID_USER_ID", called: "ListCertifications"},
+		{name: "list internal", serve: h.ListCertifications, method: http.MethodGet, target: "/certifications/list",
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "ListCertifications"},
+	})
+}
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"pull-request-assigner/internal/apperrors"
+	"pull-request-assigner/internal/domain/models"
+	"pull-request-assigner/internal/http/httpio"
+	"pull-request-assigner/internal/lib/logger/sl"
+	"pull-request-assigner/internal/service"
+	"strconv"
+)
+
+type DashboardResponse struct {
+	PullRequests []models.DashboardPR `json:"pull_requests"`
+	NextCursor   string               `json:"next_cursor,omitempty"`
+}
+
+type DashboardViewer interface {
+	ListDashboard(ctx context.Context, filter models.DashboardFilter, cursor string, limit int) ([]models.DashboardPR, string, error)
+}
+
+type DashboardHandler struct {
+	dashboardService DashboardViewer
+	log              *slog.Logger
+	resp             *httpio.Responder
+}
+
+func NewDashboardHandler(dashboardService DashboardViewer, log *slog.Logger) *DashboardHandler {
+	return &DashboardHandler{
+		dashboardService: dashboardService,
+		log:              log,
+		resp:             httpio.NewResponder(log),
+	}
+}
+
+func (h *DashboardHandler) GetDashboard(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.dashboard.GetDashboard"
+
+	log := h.log.With(slog.String("op", op))
+
+	query := r.URL.Query()
+	filter := models.DashboardFilter{
+		TeamName:   query.Get("team_name"),
+		ReviewerID: query.Get("reviewer_id"),
+		Status:     query.Get("status"),
+	}
+
+	for _, param := range []struct {
+		name   string
+		target *bool
+	}{
+		{"open", &filter.Open},
+		{"overdue", &filter.Overdue},
+	} {
+		raw := query.Get(param.name)
+		if raw == "" {
+			continue
+		}
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			log.Error("invalid dashboard flag", slog.String("param", param.name), sl.Err(err))
+			h.resp.Error(w, r, http.StatusBadRequest, "INVALID_FILTER", "open and overdue must be true or false")
+			return
+		}
+		*param.target = parsed
+	}
+
+	limit := 0
+	if raw := query.Get("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil {
+			log.Error("invalid limit", sl.Err(err))
+			h.resp.Error(w, r, http.StatusBadRequest, "INVALID_PAGE",
+				"limit must be between 1 and %d", service.MaxDashboardLimit)
+			return
+		}
+		limit = parsed
+	}
+
+	page, next, err := h.dashboardService.ListDashboard(r.Context(), filter, query.Get("cursor"), limit)
+	if err != nil {
+		log.Error("failed to get dashboard", sl.Err(err))
+
+		switch {
+		case errors.Is(err, apperrors.ErrInvalidDashboardPage):
+			h.resp.Error(w, r, http.StatusBadRequest, "INVALID_PAGE",
+				"limit must be between 1 and %d and cursor must come from a previous page", service.MaxDashboardLimit)
+		default:
+			h.resp.Fail(w, r, err, "failed to get dashboard")
+		}
+		return
+	}
+
+	h.resp.JSON(w, r, http.StatusOK, DashboardResponse{
+		PullRequests: page,
+		NextCursor:   next,
+	})
+	log.Info("dashboard returned successfully", slog.Int("pull_request_count", len(page)))
+}
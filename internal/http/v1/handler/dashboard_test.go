@@ -0,0 +1,25 @@
+package handler
+
+import (
+	"net/http"
+	"pull-request-assigner/internal/apperrors"
+	"testing"
+)
+
+func TestDashboardHandlerErrors(t *testing.T) {
+	mock := &dashboardViewerMock{}
+	h := NewDashboardHandler(mock, discardLogger())
+
+	runErrorCases(t, &mock.mockBase, []errorCase{
+		{name: "invalid overdue", serve: h.GetDashboard, method: http.MethodGet, target: "/pullRequest/dashboard?overdue=soon",
+			status: http.StatusBadRequest, code: "INVALID_FILTER"},
+		{name: "invalid limit", serve: h.GetDashboard, method: http.MethodGet, target: "/pullRequest/dashboard?limit=many",
+			status: http.StatusBadRequest, code: "INVALID_PAGE"},
+		{name: "invalid page", serve: h.GetDashboard, method: http.MethodGet, target: "/pullRequest/dashboard?cursor=x",
+			err: apperrors.ErrInvalidDashboardPage, status: http.StatusBadRequest, code: "INVALID_PAGE", called: "ListDashboard"},
+		{name: "invalid reviewer", serve: h.GetDashboard, method: http.MethodGet, target: "/pullRequest/dashboard?reviewer_id=bob",
+			err: apperrors.ErrInvalidUserID, status: http.StatusBadRequest, code: "INVALID_USER_ID", called: "ListDashboard"},
+		{name: "internal", serve: h.GetDashboard, method: http.MethodGet, target: "/pullRequest/dashboard",
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "ListDashboard"},
+	})
+}
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"pull-request-assigner/internal/apperrors"
+	"pull-request-assigner/internal/domain/models"
+	"pull-request-assigner/internal/http/httpio"
+	"pull-request-assigner/internal/lib/logger/sl"
+)
+
+// maxForgeEventSize bounds a forge webhook body; GitHub caps its payloads
+// at 25 MB, but merge events are far smaller.
+const maxForgeEventSize = 5 << 20
+
+type ForgeEventApplier interface {
+	HandleEvent(ctx context.Context, header http.Header, body []byte) (*models.ForgeEventResult, error)
+}
+
+type ForgeEventHandler struct {
+	forgeEventService ForgeEventApplier
+	log               *slog.Logger
+	resp              *httpio.Responder
+}
+
+func NewForgeEventHandler(forgeEventService ForgeEventApplier, log *slog.Logger) *ForgeEventHandler {
+	return &ForgeEventHandler{
+		forgeEventService: forgeEventService,
+		log:               log,
+		resp:              httpio.NewResponder(log),
+	}
+}
+
+func (h *ForgeEventHandler) HandleEvent(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.forgeEvent.HandleEvent"
+
+	log := h.log.With(slog.String("op", op))
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxForgeEventSize))
+	if err != nil {
+		log.Error("failed to read request body", sl.Err(err))
+		h.resp.Error(w, r, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+		return
+	}
+
+	result, err := h.forgeEventService.HandleEvent(r.Context(), r.Header, body)
+	if err != nil {
+		log.Error("failed to handle forge event", sl.Err(err))
+
+		switch {
+		case errors.Is(err, apperrors.ErrForgeNotConfigured):
+			h.resp.Error(w, r, http.StatusServiceUnavailable, "FORGE_NOT_CONFIGURED", "forge webhooks are not configured")
+		case errors.Is(err, apperrors.ErrInvalidSignature):
+			h.resp.Error(w, r, http.StatusUnauthorized, "INVALID_SIGNATURE", "forge webhook signature is missing or invalid")
+		case errors.Is(err, apperrors.ErrInvalidForgeEvent):
+			h.resp.Error(w, r, http.StatusBadRequest, "INVALID_FORGE_EVENT", "malformed forge webhook payload")
+		default:
+			h.resp.Fail(w, r, err, "failed to handle forge event")
+		}
+		return
+	}
+
+	h.resp.JSON(w, r, http.StatusOK, result)
+	log.Info("forge event handled", slog.String("action", result.Action))
+}
@@ -0,0 +1,25 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"pull-request-assigner/internal/apperrors"
+	"testing"
+)
+
+func TestForgeEventHandlerErrors(t *testing.T) {
+	mock := &forgeEventApplierMock{}
+	h := NewForgeEventHandler(mock, discardLogger())
+
+	runErrorCases(t, &mock.mockBase, []errorCase{
+		{name: "forge webhooks not configured", serve: h.HandleEvent, target: "/forge/events", body: `{}`,
+			err: apperrors.ErrForgeNotConfigured, status: http.StatusServiceUnavailable, code: "FORGE_NOT_CONFIGURED", called: "HandleEvent"},
+		{name: "invalid signature", serve: h.HandleEvent, target: "/forge/events", body: `{}`,
+			err: apperrors.ErrInvalidSignature, status: http.StatusUnauthorized, code: "INVALID_SIGNATURE", called: "HandleEvent"},
+		{name: "malformed event", serve: h.HandleEvent, target: "/forge/events", body: `{`,
+			err:    fmt.Errorf("%w: %w", apperrors.ErrInvalidForgeEvent, errUnexpected),
+			status: http.StatusBadRequest, code: "INVALID_FORGE_EVENT", called: "HandleEvent"},
+		{name: "forge event internal", serve: h.HandleEvent, target: "/forge/events", body: `{}`,
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "HandleEvent"},
+	})
+}
@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"pull-request-assigner/internal/apperrors"
+	"pull-request-assigner/internal/domain/models"
+	"pull-request-assigner/internal/http/httpio"
+	"pull-request-assigner/internal/lib/logger/sl"
+	"time"
+)
+
+type (
+	// FreezeRequest freezes one team, or every team when TeamName is empty.
+	FreezeRequest struct {
+		TeamName string     `json:"team_name"`
+		Reason   string     `json:"reason"`
+		StartsAt *time.Time `json:"starts_at"`
+		EndsAt   *time.Time `json:"ends_at"`
+	}
+
+	FreezeResponse struct {
+		Freeze *models.AssignmentFreeze `json:"freeze"`
+	}
+
+	UnfreezeRequest struct {
+		TeamName string `json:"team_name"`
+	}
+
+	UnfreezeResponse struct {
+		Unfreeze *models.UnfreezeResult `json:"unfreeze"`
+	}
+
+	FreezesResponse struct {
+		Freezes []models.AssignmentFreeze `json:"freezes"`
+	}
+)
+
+type AssignmentFreezer interface {
+	FreezeAssignments(ctx context.Context, teamName string, reason string, startsAt time.Time, endsAt *time.Time) (*models.AssignmentFreeze, error)
+	UnfreezeAssignments(ctx context.Context, teamName string) (*models.UnfreezeResult, error)
+	GetFreezes(ctx context.Context) ([]models.AssignmentFreeze, error)
+}
+
+type FreezeHandler struct {
+	prService AssignmentFreezer
+	log       *slog.Logger
+	resp      *httpio.Responder
+}
+
+func NewFreezeHandler(prService AssignmentFreezer, log *slog.Logger) *FreezeHandler {
+	return &FreezeHandler{
+		prService: prService,
+		log:       log,
+		resp:      httpio.NewResponder(log),
+	}
+}
+
+func (h *FreezeHandler) Freeze(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.freeze.Freeze"
+
+	log := h.log.With(slog.String("op", op))
+
+	var req FreezeRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("invalid request body", sl.Err(err))
+		h.resp.Error(w, r, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+		return
+	}
+
+	var startsAt time.Time
+	if req.StartsAt != nil {
+		startsAt = *req.StartsAt
+	}
+
+	freeze, err := h.prService.FreezeAssignments(r.Context(), req.TeamName, req.Reason, startsAt, req.EndsAt)
+	if err != nil {
+		log.Error("failed to freeze assignments", sl.Err(err))
+
+		switch {
+		case errors.Is(err, apperrors.ErrInvalidFreeze):
+			h.resp.Error(w, r, http.StatusBadRequest, "INVALID_FREEZE", "ends_at must be in the future and after starts_at")
+		default:
+			h.resp.Fail(w, r, err, "failed to freeze assignments")
+		}
+		return
+	}
+
+	h.resp.JSON(w, r, http.StatusCreated, FreezeResponse{Freeze: freeze})
+	log.Info("assignments frozen successfully", slog.String("team_name", req.TeamName))
+}
+
+func (h *FreezeHandler) Unfreeze(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.freeze.Unfreeze"
+
+	log := h.log.With(slog.String("op", op))
+
+	var req UnfreezeRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("invalid request body", sl.Err(err))
+		h.resp.Error(w, r, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+		return
+	}
+
+	result, err := h.prService.UnfreezeAssignments(r.Context(), req.TeamName)
+	if err != nil {
+		log.Error("failed to unfreeze assignments", sl.Err(err))
+		h.resp.Error(w, r, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to unfreeze assignments")
+		return
+	}
+
+	h.resp.JSON(w, r, http.StatusOK, UnfreezeResponse{Unfreeze: result})
+	log.Info("assignments unfrozen successfully",
+		slog.String("team_name", req.TeamName),
+		slog.Int("lifted", result.Lifted))
+}
+
+func (h *FreezeHandler) GetFreezes(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.freeze.GetFreezes"
+
+	log := h.log.With(slog.String("op", op))
+
+	freezes, err := h.prService.GetFreezes(r.Context())
+	if err != nil {
+		log.Error("failed to get freezes", sl.Err(err))
+		h.resp.Error(w, r, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to get freezes")
+		return
+	}
+
+	h.resp.JSON(w, r, http.StatusOK, FreezesResponse{Freezes: freezes})
+}
@@ -0,0 +1,31 @@
+package handler
+
+import (
+	"net/http"
+	"pull-request-assigner/internal/apperrors"
+	"testing"
+)
+
+func TestFreezeHandlerErrors(t *testing.T) {
+	mock := &assignmentFreezerMock{}
+	h := NewFreezeHandler(mock, discardLogger())
+
+	runErrorCases(t, &mock.mockBase, []errorCase{
+		{name: "freeze invalid body", serve: h.Freeze, target: "/admin/freeze", body: "{",
+			status: http.StatusBadRequest, code: "INVALID_REQUEST"},
+		{name: "freeze invalid window", serve: h.Freeze, target: "/admin/freeze", body: `{"ends_at":"2000-01-01T00:00:00Z"}`,
+			err: apperrors.ErrInvalidFreeze, status: http.StatusBadRequest, code: "INVALID_FREEZE", called: "FreezeAssignments"},
+		{name: "freeze team not found", serve: h.Freeze, target: "/admin/freeze", body: `{"team_name":"ghost"}`,
+			err: apperrors.ErrTeamNotFound, status: http.StatusNotFound, code: "NOT_FOUND", called: "FreezeAssignments"},
+		{name: "freeze internal", serve: h.Freeze, target: "/admin/freeze", body: `{}`,
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "FreezeAssignments"},
+
+		{name: "unfreeze invalid body", serve: h.Unfreeze, target: "/admin/unfreeze", body: "{",
+			status: http.StatusBadRequest, code: "INVALID_REQUEST"},
+		{name: "unfreeze internal", serve: h.Unfreeze, target: "/admin/unfreeze", body: `{}`,
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "UnfreezeAssignments"},
+
+		{name: "freezes internal", serve: h.GetFreezes, method: http.MethodGet, target: "/admin/freezes",
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "GetFreezes"},
+	})
+}
@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"pull-request-assigner/internal/http/httpio"
+	"pull-request-assigner/internal/lib/actor"
+	"slices"
+	"strings"
+	"testing"
+)
+
+var errUnexpected = errors.New("unexpected failure")
+
+// errorCase drives one error branch of a handler: the service mock fails
+// with err (nil when the handler is expected to reject the request itself)
+// and the response must carry status and code.
+type errorCase struct {
+	name   string
+	serve  http.HandlerFunc
+	method string
+	target string
+	body   string
+	userID string
+	err    error
+	status int
+	code   string
+	called string
+}
+
+func discardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func runErrorCases(t *testing.T, mock *mockBase, cases []errorCase) {
+	t.Helper()
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock.err = tc.err
+			mock.calls = nil
+
+			method := tc.method
+			if method == "" {
+				method = http.MethodPost
+			}
+
+			req := httptest.NewRequest(method, tc.target, strings.NewReader(tc.body))
+			if tc.userID != "" {
+				req = req.WithContext(actor.WithUserID(req.Context(), tc.userID))
+			}
+			rec := httptest.NewRecorder()
+
+			tc.serve(rec, req)
+
+			if rec.Code != tc.status {
+				t.Fatalf("expected status %d, got %d: %s", tc.status, rec.Code, rec.Body.String())
+			}
+
+			var resp httpio.ErrorResponse
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode error response: %v", err)
+			}
+			if resp.Error.Code != tc.code {
+				t.Fatalf("expected code %s, got %s", tc.code, resp.Error.Code)
+			}
+			if resp.Error.Message == "" {
+				t.Fatalf("expected non-empty error message")
+			}
+
+			var expectedCalls []string
+			if tc.called != "" {
+				expectedCalls = []string{tc.called}
+			}
+			if !slices.Equal(mock.calls, expectedCalls) {
+				t.Fatalf("expected service calls %v, got %v", expectedCalls, mock.calls)
+			}
+		})
+	}
+}
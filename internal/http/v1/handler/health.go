@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"pull-request-assigner/internal/domain/models"
+	"pull-request-assigner/internal/http/httpio"
+)
+
+type IntegrationsChecker interface {
+	CheckIntegrations(ctx context.Context) *models.IntegrationsReport
+}
+
+type HealthHandler struct {
+	healthService IntegrationsChecker
+	log           *slog.Logger
+	resp          *httpio.Responder
+}
+
+func NewHealthHandler(healthService IntegrationsChecker, log *slog.Logger) *HealthHandler {
+	return &HealthHandler{
+		healthService: healthService,
+		log:           log,
+		resp:          httpio.NewResponder(log),
+	}
+}
+
+// GetIntegrations answers 200 even when degraded: a failing forge or team
+// webhook is no reason to take the instance out of rotation, so monitors
+// should read the status field.
+func (h *HealthHandler) GetIntegrations(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.health.GetIntegrations"
+
+	log := h.log.With(slog.String("op", op))
+
+	report := h.healthService.CheckIntegrations(r.Context())
+
+	h.resp.JSON(w, r, http.StatusOK, report)
+	log.Info("integrations health returned", slog.String("status", report.Status))
+}
@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"pull-request-assigner/internal/apperrors"
+	"pull-request-assigner/internal/domain/models"
+	"pull-request-assigner/internal/http/httpio"
+	"pull-request-assigner/internal/lib/logger/sl"
+)
+
+type (
+	StartImpersonationRequest struct {
+		UserID string `json:"user_id"`
+		Reason string `json:"reason"`
+	}
+
+	// StartImpersonationResponse is the only response that carries the raw
+	// session key.
+	StartImpersonationResponse struct {
+		Session *models.ImpersonationSession `json:"session"`
+		Key     string                       `json:"key"`
+	}
+
+	EndImpersonationRequest struct {
+		SessionID int64 `json:"session_id"`
+	}
+
+	EndImpersonationResponse struct {
+		Session *models.ImpersonationSession `json:"session"`
+	}
+)
+
+type ImpersonationManager interface {
+	StartImpersonation(ctx context.Context, userID string, reason string) (*models.ImpersonationSession, string, error)
+	EndImpersonation(ctx context.Context, sessionID int64) (*models.ImpersonationSession, error)
+}
+
+type ImpersonationHandler struct {
+	impersonationService ImpersonationManager
+	log                  *slog.Logger
+	resp                 *httpio.Responder
+}
+
+func NewImpersonationHandler(impersonationService ImpersonationManager, log *slog.Logger) *ImpersonationHandler {
+	return &ImpersonationHandler{
+		impersonationService: impersonationService,
+		log:                  log,
+		resp:                 httpio.NewResponder(log),
+	}
+}
+
+func (h *ImpersonationHandler) StartImpersonation(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.impersonation.StartImpersonation"
+
+	log := h.log.With(slog.String("op", op))
+
+	var req StartImpersonationRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("invalid request body", sl.Err(err))
+		h.resp.Error(w, r, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+		return
+	}
+
+	session, key, err := h.impersonationService.StartImpersonation(r.Context(), req.UserID, req.Reason)
+	if err != nil {
+		log.Error("failed to start impersonation", sl.Err(err))
+
+		switch {
+		case errors.Is(err, apperrors.ErrImpersonatorRequired):
+			h.resp.Error(w, r, http.StatusUnauthorized, "UNAUTHORIZED", "caller identity is required")
+		case errors.Is(err, apperrors.ErrImpersonationReasonRequired):
+			h.resp.Error(w, r, http.StatusBadRequest, "REASON_REQUIRED", "reason is required")
+		case errors.Is(err, apperrors.ErrSelfImpersonation):
+			h.resp.Error(w, r, http.StatusBadRequest, "SELF_IMPERSONATION", "cannot impersonate yourself")
+		default:
+			h.resp.Fail(w, r, err, "failed to start impersonation")
+		}
+		return
+	}
+
+	h.resp.JSON(w, r, http.StatusCreated, StartImpersonationResponse{Session: session, Key: key})
+	log.Info("impersonation started successfully")
+}
+
+func (h *ImpersonationHandler) EndImpersonation(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.impersonation.EndImpersonation"
+
+	log := h.log.With(slog.String("op", op))
+
+	var req EndImpersonationRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("invalid request body", sl.Err(err))
+		h.resp.Error(w, r, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+		return
+	}
+
+	session, err := h.impersonationService.EndImpersonation(r.Context(), req.SessionID)
+	if err != nil {
+		log.Error("failed to end impersonation", sl.Err(err))
+		h.resp.Fail(w, r, err, "failed to end impersonation")
+		return
+	}
+
+	h.resp.JSON(w, r, http.StatusOK, EndImpersonationResponse{Session: session})
+	log.Info("impersonation ended successfully")
+}
@@ -0,0 +1,36 @@
+package handler
+
+import (
+	"net/http"
+	"pull-request-assigner/internal/apperrors"
+	"testing"
+)
+
+func TestImpersonationHandlerErrors(t *testing.T) {
+	mock := &impersonationManagerMock{}
+	h := NewImpersonationHandler(mock, discardLogger())
+
+	const body = `{"user_id":"u2","reason":"support ticket"}`
+
+	runErrorCases(t, &mock.mockBase, []errorCase{
+		{name: "start invalid body", serve: h.StartImpersonation, target: "/admin/impersonation/start", body: "{",
+			status: http.StatusBadRequest, code: "INVALID_REQUEST"},
+		{name: "start without identity", serve: h.StartImpersonation, target: "/admin/impersonation/start", body: body,
+			err: apperrors.ErrImpersonatorRequired, status: http.StatusUnauthorized, code: "UNAUTHORIZED", called: "StartImpersonation"},
+		{name: "start reason required", serve: h.StartImpersonation, target: "/admin/impersonation/start", body: body,
+			err: apperrors.ErrImpersonationReasonRequired, status: http.StatusBadRequest, code: "REASON_REQUIRED", called: "StartImpersonation"},
+		{name: "start self", serve: h.StartImpersonation, target: "/admin/impersonation/start", body: body,
+			err: apperrors.ErrSelfImpersonation, status: http.StatusBadRequest, code: "SELF_IMPERSONATION", called: "StartImpersonation"},
+		{name: "start user not found", serve: h.StartImpersonation, target: "/admin/impersonation/start", body: body,
+			err: apperrors.ErrUserNotFound, status: http.StatusNotFound, code: "NOT_FOUND", called: "StartImpersonation"},
+		{name: "start internal", serve: h.StartImpersonation, target: "/admin/impersonation/start", body: body,
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "StartImpersonation"},
+
+		{name: "end invalid body", serve: h.EndImpersonation, target: "/admin/impersonation/end", body: "{",
+			status: http.StatusBadRequest, code: "INVALID_REQUEST"},
+		{name: "end not found", serve: h.EndImpersonation, target: "/admin/impersonation/end", body: `{"session_id":1}`,
+			err: apperrors.ErrImpersonationNotFound, status: http.StatusNotFound, code: "NOT_FOUND", called: "EndImpersonation"},
+		{name: "end internal", serve: h.EndImpersonation, target: "/admin/impersonation/end", body: `{"session_id":1}`,
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "EndImpersonation"},
+	})
+}
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"pull-request-assigner/internal/apperrors"
+	"pull-request-assigner/internal/domain/models"
+	"pull-request-assigner/internal/http/httpio"
+	"pull-request-assigner/internal/lib/logger/sl"
+)
+
+type (
+	// SetMergeWindowRequest replaces the team's merge window; an empty Blocks
+	// list stops blocking merges.
+	SetMergeWindowRequest struct {
+		TeamName    string              `json:"team_name"`
+		TimeZone    string              `json:"time_zone"`
+		WarnMinutes int                 `json:"warn_minutes"`
+		Blocks      []models.MergeBlock `json:"blocks"`
+	}
+
+	MergeWindowResponse struct {
+		MergeWindow *models.MergeWindow `json:"merge_window"`
+	}
+
+	MergeWindowsResponse struct {
+		MergeWindows []models.MergeWindow `json:"merge_windows"`
+	}
+)
+
+type MergeWindowManager interface {
+	SetMergeWindow(ctx context.Context, window models.MergeWindow) (*models.MergeWindow, error)
+	GetMergeWindows(ctx context.Context) ([]models.MergeWindow, error)
+}
+
+type MergeWindowHandler struct {
+	prService MergeWindowManager
+	log       *slog.Logger
+	resp      *httpio.Responder
+}
+
+func NewMergeWindowHandler(prService MergeWindowManager, log *slog.Logger) *MergeWindowHandler {
+	return &MergeWindowHandler{
+		prService: prService,
+		log:       log,
+		resp:      httpio.NewResponder(log),
+	}
+}
+
+func (h *MergeWindowHandler) SetMergeWindow(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.mergeWindow.SetMergeWindow"
+
+	log := h.log.With(slog.String("op", op))
+
+	var req SetMergeWindowRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("invalid request body", sl.Err(err))
+		h.resp.Error(w, r, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+		return
+	}
+
+	window, err := h.prService.SetMergeWindow(r.Context(), models.MergeWindow{
+		TeamName:    req.TeamName,
+		TimeZone:    req.TimeZone,
+		WarnMinutes: req.WarnMinutes,
+		Blocks:      req.Blocks,
+	})
+	if err != nil {
+		log.Error("failed to set merge window", sl.Err(err))
+
+		switch {
+		case errors.Is(err, apperrors.ErrInvalidMergeWindow):
+			h.resp.Error(w, r, http.StatusBadRequest, "INVALID_MERGE_WINDOW",
+				"blocks need a weekday and a start before the end as HH:MM, time_zone an IANA time zone and warn_minutes 0 to 1440")
+		default:
+			h.resp.Fail(w, r, err, "failed to set merge window")
+		}
+		return
+	}
+
+	h.resp.JSON(w, r, http.StatusOK, MergeWindowResponse{MergeWindow: window})
+	log.Info("merge window set", slog.String("team_name", window.TeamName))
+}
+
+func (h *MergeWindowHandler) GetMergeWindows(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.mergeWindow.GetMergeWindows"
+
+	log := h.log.With(slog.String("op", op))
+
+	windows, err := h.prService.GetMergeWindows(r.Context())
+	if err != nil {
+		log.Error("failed to get merge windows", sl.Err(err))
+		h.resp.Error(w, r, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to get merge windows")
+		return
+	}
+
+	h.resp.JSON(w, r, http.StatusOK, MergeWindowsResponse{MergeWindows: windows})
+}
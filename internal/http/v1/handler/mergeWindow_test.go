@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"net/http"
+	"pull-request-assigner/internal/apperrors"
+	"testing"
+)
+
+func TestMergeWindowHandlerErrors(t *testing.T) {
+	mock := &mergeWindowManagerMock{}
+	h := NewMergeWindowHandler(mock, discardLogger())
+
+	const windowBody = `{"team_name":"Backend","blocks":[{"weekday":"friday","start":"00:00","end":"24:00"}]}`
+
+	runErrorCases(t, &mock.mockBase, []errorCase{
+		{name: "set invalid body", serve: h.SetMergeWindow, target: "/admin/mergeWindow/set", body: "{",
+			status: http.StatusBadRequest, code: "INVALID_REQUEST"},
+		{name: "set missing team", serve: h.SetMergeWindow, target: "/admin/mergeWindow/set", body: `{}`,
+			err: apperrors.ErrTeamNameRequired, status: http.StatusBadRequest, code: "TEAM_NAME_REQUIRED", called: "SetMergeWindow"},
+		{name: "set invalid window", serve: h.SetMergeWindow, target: "/admin/mergeWindow/set", body: windowBody,
+			err: apperrors.ErrInvalidMergeWindow, status: http.StatusBadRequest, code: "INVALID_MERGE_WINDOW", called: "SetMergeWindow"},
+		{name: "set team not found", serve: h.SetMergeWindow, target: "/admin/mergeWindow/set", body: windowBody,
+			err: apperrors.ErrTeamNotFound, status: http.StatusNotFound, code: "NOT_FOUND", called: "SetMergeWindow"},
+		{name: "set internal", serve: h.SetMergeWindow, target: "/admin/mergeWindow/set", body: windowBody,
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "SetMergeWindow"},
+
+		{name: "list internal", serve: h.GetMergeWindows, method: http.MethodGet, target: "/admin/mergeWindows",
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "GetMergeWindows"},
+	})
+}
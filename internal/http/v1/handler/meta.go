@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"pull-request-assigner/internal/domain/models"
+	"pull-request-assigner/internal/http/httpio"
+	"pull-request-assigner/internal/lib/i18n"
+	"pull-request-assigner/internal/lib/logger/sl"
+)
+
+type EnumsResponse struct {
+	Language string        `json:"language"`
+	Enums    []models.Enum `json:"enums"`
+}
+
+type EnumLister interface {
+	ListEnums(ctx context.Context) ([]models.Enum, error)
+}
+
+type MetaHandler struct {
+	metaService EnumLister
+	log         *slog.Logger
+	resp        *httpio.Responder
+}
+
+func NewMetaHandler(metaService EnumLister, log *slog.Logger) *MetaHandler {
+	return &MetaHandler{
+		metaService: metaService,
+		log:         log,
+		resp:        httpio.NewResponder(log),
+	}
+}
+
+// GetEnums lists the enumerations with labels in the language negotiated
+// from Accept-Language.
+func (h *MetaHandler) GetEnums(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.meta.GetEnums"
+
+	log := h.log.With(slog.String("op", op))
+
+	enums, err := h.metaService.ListEnums(r.Context())
+	if err != nil {
+		log.Error("failed to list enums", sl.Err(err))
+		h.resp.Fail(w, r, err, "failed to list enums")
+		return
+	}
+
+	lang := httpio.Language(r)
+	for i := range enums {
+		for j := range enums[i].Values {
+			enums[i].Values[j].Label = i18n.Translate(lang, enums[i].Values[j].Label)
+		}
+	}
+
+	w.Header().Set("Content-Language", lang)
+	h.resp.JSON(w, r, http.StatusOK, EnumsResponse{Language: lang, Enums: enums})
+}
@@ -0,0 +1,16 @@
+package handler
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestMetaHandlerErrors(t *testing.T) {
+	mock := &enumListerMock{}
+	h := NewMetaHandler(mock, discardLogger())
+
+	runErrorCases(t, &mock.mockBase, []errorCase{
+		{name: "internal", serve: h.GetEnums, method: http.MethodGet, target: "/meta/enums",
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "ListEnums"},
+	})
+}
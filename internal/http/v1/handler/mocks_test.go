@@ -0,0 +1,492 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"pull-request-assigner/internal/domain/models"
+	"time"
+)
+
+// mockBase records the service methods a handler called and fails every
+// call with err. Successful calls return zero values.
+type mockBase struct {
+	err   error
+	calls []string
+}
+
+func (m *mockBase) record(method string) error {
+	m.calls = append(m.calls, method)
+	return m.err
+}
+
+type adminManagerMock struct{ mockBase }
+
+func (m *adminManagerMock) GetArchive(ctx context.Context) ([]models.ArchivedTeam, []models.ArchivedUser, error) {
+	return nil, nil, m.record("GetArchive")
+}
+
+func (m *adminManagerMock) RestoreTeam(ctx context.Context, teamName string) error {
+	return m.record("RestoreTeam")
+}
+
+func (m *adminManagerMock) RestoreUser(ctx context.Context, userID string) error {
+	return m.record("RestoreUser")
+}
+
+func (m *adminManagerMock) AnonymizeUser(ctx context.Context, userID string, confirmationToken string) (*models.AnonymizationResult, error) {
+	return &models.AnonymizationResult{}, m.record("AnonymizeUser")
+}
+
+func (m *adminManagerMock) Simulate(ctx context.Context, strategy string, teamName string, reviewersPerPR int, windowDays int, seed int64) (*models.SimulationReport, error) {
+	return &models.SimulationReport{}, m.record("Simulate")
+}
+
+func (m *adminManagerMock) CheckDB(ctx context.Context) ([]models.QueryPlanCheck, error) {
+	return nil, m.record("CheckDB")
+}
+
+func (m *adminManagerMock) GetJobs(ctx context.Context) ([]models.Job, error) {
+	return nil, m.record("GetJobs")
+}
+
+func (m *adminManagerMock) GetMigrationStatus(ctx context.Context) (*models.MigrationStatus, error) {
+	return &models.MigrationStatus{}, m.record("GetMigrationStatus")
+}
+
+func (m *adminManagerMock) CheckMembership(ctx context.Context, repair bool) (*models.MembershipReport, error) {
+	return &models.MembershipReport{}, m.record("CheckMembership")
+}
+
+type usageReporterMock struct{ mockBase }
+
+func (m *usageReporterMock) GetUsage(ctx context.Context, from time.Time, to time.Time, bucket string) ([]models.UsageRecord, error) {
+	return nil, m.record("GetUsage")
+}
+
+type certificationManagerMock struct{ mockBase }
+
+func (m *certificationManagerMock) GrantCertification(ctx context.Context, userID string, area string) (*models.Certification, error) {
+	return &models.Certification{}, m.record("GrantCertification")
+}
+
+func (m *certificationManagerMock) RevokeCertification(ctx context.Context, userID string, area string) error {
+	return m.record("RevokeCertification")
+}
+
+func (m *certificationManagerMock) ListCertifications(ctx context.Context, userID string, area string) ([]models.Certification, error) {
+	return nil, m.record("ListCertifications")
+}
+
+type assignmentFreezerMock struct{ mockBase }
+
+func (m *assignmentFreezerMock) FreezeAssignments(ctx context.Context, teamName string, reason string, startsAt time.Time, endsAt *time.Time) (*models.AssignmentFreeze, error) {
+	return &models.AssignmentFreeze{}, m.record("FreezeAssignments")
+}
+
+func (m *assignmentFreezerMock) UnfreezeAssignments(ctx context.Context, teamName string) (*models.UnfreezeResult, error) {
+	return &models.UnfreezeResult{}, m.record("UnfreezeAssignments")
+}
+
+func (m *assignmentFreezerMock) GetFreezes(ctx context.Context) ([]models.AssignmentFreeze, error) {
+	return nil, m.record("GetFreezes")
+}
+
+type mergeWindowManagerMock struct{ mockBase }
+
+func (m *mergeWindowManagerMock) SetMergeWindow(ctx context.Context, window models.MergeWindow) (*models.MergeWindow, error) {
+	return &window, m.record("SetMergeWindow")
+}
+
+func (m *mergeWindowManagerMock) GetMergeWindows(ctx context.Context) ([]models.MergeWindow, error) {
+	return nil, m.record("GetMergeWindows")
+}
+
+type prStatusConfiguratorMock struct{ mockBase }
+
+func (m *prStatusConfiguratorMock) SaveStatus(ctx context.Context, status models.PRStatus) (*models.PRStatus, error) {
+	return &status, m.record("SaveStatus")
+}
+
+func (m *prStatusConfiguratorMock) DeleteStatus(ctx context.Context, status string) error {
+	return m.record("DeleteStatus")
+}
+
+func (m *prStatusConfiguratorMock) SetTransitions(ctx context.Context, teamName string, transitions []models.PRStatusTransition) (*models.PRStatusMachine, error) {
+	return &models.PRStatusMachine{}, m.record("SetTransitions")
+}
+
+type assignmentRepairerMock struct{ mockBase }
+
+func (m *assignmentRepairerMock) GetRepairs(ctx context.Context) ([]models.AssignmentRepair, error) {
+	return nil, m.record("GetRepairs")
+}
+
+func (m *assignmentRepairerMock) RunRepairs(ctx context.Context) (*models.AssignmentRepairReport, error) {
+	return &models.AssignmentRepairReport{}, m.record("RunRepairs")
+}
+
+type impersonationManagerMock struct{ mockBase }
+
+func (m *impersonationManagerMock) StartImpersonation(ctx context.Context, userID string, reason string) (*models.ImpersonationSession, string, error) {
+	return &models.ImpersonationSession{}, "", m.record("StartImpersonation")
+}
+
+func (m *impersonationManagerMock) EndImpersonation(ctx context.Context, sessionID int64) (*models.ImpersonationSession, error) {
+	return &models.ImpersonationSession{}, m.record("EndImpersonation")
+}
+
+type teamRebalancerMock struct{ mockBase }
+
+func (m *teamRebalancerMock) RebalanceTeam(ctx context.Context, teamName string, dryRun bool) (*models.RebalancePlan, error) {
+	return &models.RebalancePlan{}, m.record("RebalanceTeam")
+}
+
+type prBackfillerMock struct{ mockBase }
+
+func (m *prBackfillerMock) Backfill(ctx context.Context) (*models.BackfillResult, error) {
+	return &models.BackfillResult{}, m.record("Backfill")
+}
+
+type statusReconcilerMock struct{ mockBase }
+
+func (m *statusReconcilerMock) ReconcileStatuses(ctx context.Context, claims []models.PRStatusClaim, dryRun bool) (*models.ReconcileReport, error) {
+	return &models.ReconcileReport{}, m.record("ReconcileStatuses")
+}
+
+type forgeEventApplierMock struct{ mockBase }
+
+func (m *forgeEventApplierMock) HandleEvent(ctx context.Context, header http.Header, body []byte) (*models.ForgeEventResult, error) {
+	return &models.ForgeEventResult{}, m.record("HandleEvent")
+}
+
+type userOffboarderMock struct{ mockBase }
+
+func (m *userOffboarderMock) OffboardUser(ctx context.Context, userID string, anonymize bool, confirmationToken string) (*models.OffboardingReport, error) {
+	return &models.OffboardingReport{}, m.record("OffboardUser")
+}
+
+type prActivityViewerMock struct{ mockBase }
+
+func (m *prActivityViewerMock) GetPRActivity(ctx context.Context, prID string) ([]models.PRActivity, error) {
+	return nil, m.record("GetPRActivity")
+}
+
+type dashboardViewerMock struct{ mockBase }
+
+func (m *dashboardViewerMock) ListDashboard(ctx context.Context, filter models.DashboardFilter, cursor string, limit int) ([]models.DashboardPR, string, error) {
+	return nil, "", m.record("ListDashboard")
+}
+
+type enumListerMock struct{ mockBase }
+
+func (m *enumListerMock) ListEnums(ctx context.Context) ([]models.Enum, error) {
+	return nil, m.record("ListEnums")
+}
+
+type statsReporterMock struct{ mockBase }
+
+func (m *statsReporterMock) GetPRStats(ctx context.Context) (*models.PRStats, error) {
+	return &models.PRStats{}, m.record("GetPRStats")
+}
+
+func (m *statsReporterMock) GetTeamsPRStats(ctx context.Context, teamNames []string) ([]models.TeamPRStats, error) {
+	return nil, m.record("GetTeamsPRStats")
+}
+
+func (m *statsReporterMock) GetCycleTime(ctx context.Context, windowDays int) (*models.CycleTimeStats, error) {
+	return &models.CycleTimeStats{}, m.record("GetCycleTime")
+}
+
+func (m *statsReporterMock) GetStatsHistory(ctx context.Context, teamName string, windowDays int) ([]models.StatsSnapshot, error) {
+	return nil, m.record("GetStatsHistory")
+}
+
+func (m *statsReporterMock) GetTagStats(ctx context.Context, windowDays int) (*models.TagStatsReport, error) {
+	return &models.TagStatsReport{}, m.record("GetTagStats")
+}
+
+func (m *statsReporterMock) GetCapacityPlan(ctx context.Context, teamName string) (*models.CapacityPlan, error) {
+	return &models.CapacityPlan{}, m.record("GetCapacityPlan")
+}
+
+func (m *statsReporterMock) GetPairingMatrix(ctx context.Context, teamName string, windowDays int) (*models.PairingMatrix, error) {
+	return &models.PairingMatrix{}, m.record("GetPairingMatrix")
+}
+
+type teamManagerMock struct{ mockBase }
+
+func (m *teamManagerMock) CreateTeamWithMembers(ctx context.Context, team models.Team) (*models.Team, error) {
+	return &team, m.record("CreateTeamWithMembers")
+}
+
+func (m *teamManagerMock) GetTeamWithMembers(ctx context.Context, teamName string) (*models.Team, error) {
+	return &models.Team{}, m.record("GetTeamWithMembers")
+}
+
+func (m *teamManagerMock) SetSecondaryMember(ctx context.Context, teamName string, userID string, isMember bool) (*models.Team, error) {
+	return &models.Team{}, m.record("SetSecondaryMember")
+}
+
+func (m *teamManagerMock) DeactivateTeamUsers(ctx context.Context, teamName string) (int, error) {
+	return 0, m.record("DeactivateTeamUsers")
+}
+
+func (m *teamManagerMock) ArchiveTeam(ctx context.Context, teamName string) (int, error) {
+	return 0, m.record("ArchiveTeam")
+}
+
+func (m *teamManagerMock) UpdateTeamPolicy(ctx context.Context, teamName string, update models.TeamPolicyUpdate, actorID string) (*models.TeamPolicy, error) {
+	return &models.TeamPolicy{}, m.record("UpdateTeamPolicy")
+}
+
+func (m *teamManagerMock) GetPolicyHistory(ctx context.Context, teamName string) ([]models.PolicyVersion, error) {
+	return nil, m.record("GetPolicyHistory")
+}
+
+func (m *teamManagerMock) GetTeamChanges(ctx context.Context, teamName string) ([]models.AuditEvent, error) {
+	return nil, m.record("GetTeamChanges")
+}
+
+func (m *teamManagerMock) GetTeamSettings(ctx context.Context, teamName string) (*models.TeamPolicyOverrides, string, error) {
+	return &models.TeamPolicyOverrides{}, "", m.record("GetTeamSettings")
+}
+
+func (m *teamManagerMock) PatchTeamSettings(ctx context.Context, teamName string, patch []byte, ifMatch string, actorID string) (*models.TeamPolicyOverrides, string, error) {
+	return &models.TeamPolicyOverrides{}, "", m.record("PatchTeamSettings")
+}
+
+type teamLeadManagerMock struct{ mockBase }
+
+func (m *teamLeadManagerMock) SetTeamLead(ctx context.Context, teamName string, userID string, isLead bool) error {
+	return m.record("SetTeamLead")
+}
+
+func (m *teamLeadManagerMock) GetTeamLeads(ctx context.Context) ([]models.TeamLead, error) {
+	return nil, m.record("GetTeamLeads")
+}
+
+type tokenManagerMock struct{ mockBase }
+
+func (m *tokenManagerMock) IssueToken(ctx context.Context, name string, userID string, scopes []string, hourlyQuota *int, expiresAt *time.Time) (*models.APIToken, string, error) {
+	return &models.APIToken{}, "", m.record("IssueToken")
+}
+
+func (m *tokenManagerMock) BootstrapToken(ctx context.Context, secret string, name string, userID string) (*models.APIToken, string, error) {
+	return &models.APIToken{}, "", m.record("BootstrapToken")
+}
+
+func (m *tokenManagerMock) ListTokens(ctx context.Context) ([]models.APIToken, error) {
+	return nil, m.record("ListTokens")
+}
+
+func (m *tokenManagerMock) RevokeToken(ctx context.Context, tokenID int64) (*models.APIToken, error) {
+	return &models.APIToken{}, m.record("RevokeToken")
+}
+
+func (m *tokenManagerMock) SetTokenQuota(ctx context.Context, tokenID int64, hourlyQuota *int) (*models.APIToken, error) {
+	return &models.APIToken{}, m.record("SetTokenQuota")
+}
+
+func (m *tokenManagerMock) RotateToken(ctx context.Context, tokenID int64) (*models.APIToken, string, error) {
+	return &models.APIToken{}, "", m.record("RotateToken")
+}
+
+type reviewerDirectoryMock struct{ mockBase }
+
+func (m *reviewerDirectoryMock) SetUserActiveStatus(ctx context.Context, isActive bool, userID string, reassignOpenReviews bool) (models.User, *models.ReviewHandover, error) {
+	return models.User{}, nil, m.record("SetUserActiveStatus")
+}
+
+func (m *reviewerDirectoryMock) SetAuthorOnly(ctx context.Context, userID string, authorOnly bool, until *time.Time) (models.User, error) {
+	return models.User{}, m.record("SetAuthorOnly")
+}
+
+func (m *reviewerDirectoryMock) GetReviewForecast(ctx context.Context, userID string) (*models.ReviewForecast, error) {
+	return &models.ReviewForecast{}, m.record("GetReviewForecast")
+}
+
+func (m *reviewerDirectoryMock) SetUsersActiveStatus(ctx context.Context, isActive bool, userIDs []string) (*models.BatchActiveResult, error) {
+	return &models.BatchActiveResult{}, m.record("SetUsersActiveStatus")
+}
+
+func (m *reviewerDirectoryMock) GetUserReview(ctx context.Context, userID string) ([]models.PullRequestShort, error) {
+	return nil, m.record("GetUserReview")
+}
+
+func (m *reviewerDirectoryMock) WaitUserReview(ctx context.Context, userID string, wait time.Duration) ([]models.PullRequestShort, bool, error) {
+	return nil, false, m.record("WaitUserReview")
+}
+
+func (m *reviewerDirectoryMock) GetMyReviews(ctx context.Context, userID string) ([]models.ReviewAssignment, error) {
+	return nil, m.record("GetMyReviews")
+}
+
+func (m *reviewerDirectoryMock) GetUserSummary(ctx context.Context, userID string) (*models.UserSummary, error) {
+	return nil, m.record("GetUserSummary")
+}
+
+func (m *reviewerDirectoryMock) ReorderQueue(ctx context.Context, userID string, prIDs []string) ([]models.ReviewAssignment, error) {
+	return nil, m.record("ReorderQueue")
+}
+
+func (m *reviewerDirectoryMock) GetUserSettings(ctx context.Context, userID string) (*models.UserSettings, string, error) {
+	return &models.UserSettings{}, "", m.record("GetUserSettings")
+}
+
+func (m *reviewerDirectoryMock) PatchUserSettings(ctx context.Context, userID string, patch []byte, ifMatch string) (*models.UserSettings, string, error) {
+	return &models.UserSettings{}, "", m.record("PatchUserSettings")
+}
+
+type pullRequestManagerMock struct{ mockBase }
+
+func (m *pullRequestManagerMock) CreatePRWithReviewers(ctx context.Context, pr models.PullRequest) (*models.PullRequest, []string, error) {
+	return &pr, nil, m.record("CreatePRWithReviewers")
+}
+
+func (m *pullRequestManagerMock) MergePR(ctx context.Context, prID string, strict bool, mergedBy string, override bool) (*models.PullRequest, []string, bool, *models.MergeWarning, error) {
+	return &models.PullRequest{PullRequestId: prID}, nil, false, nil, m.record("MergePR")
+}
+
+func (m *pullRequestManagerMock) ListStatuses(ctx context.Context, teamName string) (*models.PRStatusMachine, error) {
+	return &models.PRStatusMachine{}, m.record("ListStatuses")
+}
+
+func (m *pullRequestManagerMock) SetStatus(ctx context.Context, prID string, status string) (*models.PullRequest, []string, error) {
+	return &models.PullRequest{PullRequestId: prID}, nil, m.record("SetStatus")
+}
+
+func (m *pullRequestManagerMock) UpdateCIStatus(ctx context.Context, prID string, ciStatus string) (*models.PullRequest, []string, error) {
+	return &models.PullRequest{PullRequestId: prID}, nil, m.record("UpdateCIStatus")
+}
+
+func (m *pullRequestManagerMock) ReassignReviewer(ctx context.Context, prID string, oldReviewerID string, reason string, note string) (*models.PullRequest, []string, string, error) {
+	return &models.PullRequest{PullRequestId: prID}, nil, "", m.record("ReassignReviewer")
+}
+
+func (m *pullRequestManagerMock) AssignReviewer(ctx context.Context, prID string, reviewerID string, replaceReviewerID string, actorID string) (*models.PullRequest, []string, error) {
+	return &models.PullRequest{PullRequestId: prID}, nil, m.record("AssignReviewer")
+}
+
+func (m *pullRequestManagerMock) SetReviewerCount(ctx context.Context, prID string, count int, actorID string) (*models.PullRequest, []string, []string, []string, error) {
+	return &models.PullRequest{PullRequestId: prID}, nil, nil, nil, m.record("SetReviewerCount")
+}
+
+func (m *pullRequestManagerMock) DeclineReview(ctx context.Context, prID string, reviewerID string) (*models.PullRequest, []string, string, error) {
+	return &models.PullRequest{PullRequestId: prID}, nil, "", m.record("DeclineReview")
+}
+
+func (m *pullRequestManagerMock) UnassignReviewer(ctx context.Context, prID string, reviewerID string, actorID string) (*models.PullRequest, []string, error) {
+	return &models.PullRequest{PullRequestId: prID}, nil, m.record("UnassignReviewer")
+}
+
+func (m *pullRequestManagerMock) DelegateReview(ctx context.Context, prID string, reviewerID string, delegateID string) (*models.PullRequest, []string, error) {
+	return &models.PullRequest{PullRequestId: prID}, nil, m.record("DelegateReview")
+}
+
+func (m *pullRequestManagerMock) GetCandidates(ctx context.Context, prID string) ([]models.ReviewerCandidate, error) {
+	return nil, m.record("GetCandidates")
+}
+
+func (m *pullRequestManagerMock) StartReview(ctx context.Context, prID string, reviewerID string) (time.Time, error) {
+	return time.Time{}, m.record("StartReview")
+}
+
+func (m *pullRequestManagerMock) CompleteReview(ctx context.Context, prID string, reviewerID string) (time.Time, error) {
+	return time.Time{}, m.record("CompleteReview")
+}
+
+func (m *pullRequestManagerMock) ApprovePR(ctx context.Context, prID string, reviewerID string) (time.Time, error) {
+	return time.Time{}, m.record("ApprovePR")
+}
+
+func (m *pullRequestManagerMock) GetPendingAssignments(ctx context.Context, teamName string) ([]models.PendingAssignment, error) {
+	return nil, m.record("GetPendingAssignments")
+}
+
+func (m *pullRequestManagerMock) ExportPRs(ctx context.Context, search models.PRSearch, emit func(page []models.PullRequestExport) error) (int, error) {
+	return 0, m.record("ExportPRs")
+}
+
+type poolManagerMock struct{ mockBase }
+
+func (m *poolManagerMock) CreatePool(ctx context.Context, poolName string, reviewersPerPR int, members []string) (*models.ReviewerPool, error) {
+	return &models.ReviewerPool{PoolName: poolName}, m.record("CreatePool")
+}
+
+func (m *poolManagerMock) GetPool(ctx context.Context, poolName string) (*models.ReviewerPool, error) {
+	return &models.ReviewerPool{PoolName: poolName}, m.record("GetPool")
+}
+
+func (m *poolManagerMock) ListPools(ctx context.Context) ([]models.ReviewerPool, error) {
+	return nil, m.record("ListPools")
+}
+
+func (m *poolManagerMock) UpdatePool(ctx context.Context, poolName string, reviewersPerPR int) (*models.ReviewerPool, error) {
+	return &models.ReviewerPool{PoolName: poolName}, m.record("UpdatePool")
+}
+
+func (m *poolManagerMock) DeletePool(ctx context.Context, poolName string) error {
+	return m.record("DeletePool")
+}
+
+func (m *poolManagerMock) AddPoolMembers(ctx context.Context, poolName string, members []string) (*models.ReviewerPool, error) {
+	return &models.ReviewerPool{PoolName: poolName}, m.record("AddPoolMembers")
+}
+
+func (m *poolManagerMock) RemovePoolMembers(ctx context.Context, poolName string, members []string) (*models.ReviewerPool, error) {
+	return &models.ReviewerPool{PoolName: poolName}, m.record("RemovePoolMembers")
+}
+
+func (m *poolManagerMock) GetPoolStats(ctx context.Context, poolName string) (*models.PoolStats, error) {
+	return &models.PoolStats{PoolName: poolName}, m.record("GetPoolStats")
+}
+
+type policyManagerMock struct{ mockBase }
+
+func (m *policyManagerMock) GetOrgPolicy(ctx context.Context) (*models.OrgPolicy, error) {
+	return &models.OrgPolicy{}, m.record("GetOrgPolicy")
+}
+
+func (m *policyManagerMock) UpdateOrgPolicy(ctx context.Context, update models.OrgPolicyUpdate, actorID string) (*models.OrgPolicy, error) {
+	return &models.OrgPolicy{}, m.record("UpdateOrgPolicy")
+}
+
+func (m *policyManagerMock) GetEffectivePolicy(ctx context.Context, teamName string) (*models.EffectivePolicy, error) {
+	return &models.EffectivePolicy{TeamName: teamName}, m.record("GetEffectivePolicy")
+}
+
+type webhookManagerMock struct{ mockBase }
+
+func (m *webhookManagerMock) CreateWebhook(ctx context.Context, teamName string, callbackURL string, secret string, eventNames []string) (*models.TeamWebhook, error) {
+	return &models.TeamWebhook{TeamName: teamName}, m.record("CreateWebhook")
+}
+
+func (m *webhookManagerMock) ListWebhooks(ctx context.Context, teamName string) ([]models.TeamWebhook, error) {
+	return nil, m.record("ListWebhooks")
+}
+
+func (m *webhookManagerMock) UpdateWebhook(ctx context.Context, id int64, update models.TeamWebhookUpdate) (*models.TeamWebhook, error) {
+	return &models.TeamWebhook{ID: id}, m.record("UpdateWebhook")
+}
+
+func (m *webhookManagerMock) DeleteWebhook(ctx context.Context, id int64) error {
+	return m.record("DeleteWebhook")
+}
+
+func (m *webhookManagerMock) ListFailedDeliveries(ctx context.Context, teamName string) ([]models.StoredWebhookDelivery, error) {
+	return nil, m.record("ListFailedDeliveries")
+}
+
+type templateManagerMock struct{ mockBase }
+
+func (m *templateManagerMock) ListTemplates(ctx context.Context) ([]models.NotificationTemplate, error) {
+	return nil, m.record("ListTemplates")
+}
+
+func (m *templateManagerMock) SaveTemplate(ctx context.Context, event string, channel string, body string) (*models.NotificationTemplate, error) {
+	return &models.NotificationTemplate{Event: event, Channel: channel, Body: body}, m.record("SaveTemplate")
+}
+
+func (m *templateManagerMock) DeleteTemplate(ctx context.Context, event string, channel string) error {
+	return m.record("DeleteTemplate")
+}
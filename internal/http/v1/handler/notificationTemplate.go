@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"pull-request-assigner/internal/domain/models"
+	"pull-request-assigner/internal/http/httpio"
+	"pull-request-assigner/internal/lib/logger/sl"
+	"pull-request-assigner/internal/service"
+)
+
+type (
+	SaveTemplateRequest struct {
+		Event   string `json:"event"`
+		Channel string `json:"channel"`
+		Body    string `json:"body"`
+	}
+
+	DeleteTemplateRequest struct {
+		Event   string `json:"event"`
+		Channel string `json:"channel"`
+	}
+
+	TemplateResponse struct {
+		Template *models.NotificationTemplate `json:"template"`
+	}
+
+	ListTemplatesResponse struct {
+		Templates []models.NotificationTemplate `json:"templates"`
+	}
+
+	DeleteTemplateResponse struct {
+		Event   string `json:"event"`
+		Channel string `json:"channel"`
+		Deleted bool   `json:"deleted"`
+	}
+)
+
+type NotificationTemplateManager interface {
+	ListTemplates(ctx context.Context) ([]models.NotificationTemplate, error)
+	SaveTemplate(ctx context.Context, event string, channel string, body string) (*models.NotificationTemplate, error)
+	DeleteTemplate(ctx context.Context, event string, channel string) error
+}
+
+type NotificationTemplateHandler struct {
+	templateService NotificationTemplateManager
+	log             *slog.Logger
+	resp            *httpio.Responder
+}
+
+func NewNotificationTemplateHandler(templateService NotificationTemplateManager, log *slog.Logger) *NotificationTemplateHandler {
+	return &NotificationTemplateHandler{
+		templateService: templateService,
+		log:             log,
+		resp:            httpio.NewResponder(log),
+	}
+}
+
+func (h *NotificationTemplateHandler) ListTemplates(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.notificationTemplate.ListTemplates"
+
+	log := h.log.With(slog.String("op", op))
+
+	templates, err := h.templateService.ListTemplates(r.Context())
+	if err != nil {
+		log.Error("failed to list notification templates", sl.Err(err))
+		h.resp.Fail(w, r, err, "failed to list notification templates")
+		return
+	}
+
+	h.resp.JSON(w, r, http.StatusOK, ListTemplatesResponse{Templates: templates})
+}
+
+func (h *NotificationTemplateHandler) SaveTemplate(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.notificationTemplate.SaveTemplate"
+
+	log := h.log.With(slog.String("op", op))
+
+	var req SaveTemplateRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("invalid request body", sl.Err(err))
+		h.resp.Error(w, r, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+		return
+	}
+
+	tpl, err := h.templateService.SaveTemplate(r.Context(), req.Event, req.Channel, req.Body)
+	if err != nil {
+		log.Error("failed to save notification template", sl.Err(err))
+
+		var tplErr *service.TemplateError
+		if errors.As(err, &tplErr) {
+			h.resp.Error(w, r, http.StatusBadRequest, "INVALID_TEMPLATE", "invalid notification template: %s", tplErr.Reason)
+			return
+		}
+		h.resp.Fail(w, r, err, "failed to save notification template")
+		return
+	}
+
+	h.resp.JSON(w, r, http.StatusOK, TemplateResponse{Template: tpl})
+	log.Info("notification template saved successfully")
+}
+
+func (h *NotificationTemplateHandler) DeleteTemplate(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.notificationTemplate.DeleteTemplate"
+
+	log := h.log.With(slog.String("op", op))
+
+	var req DeleteTemplateRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("invalid request body", sl.Err(err))
+		h.resp.Error(w, r, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+		return
+	}
+
+	if err := h.templateService.DeleteTemplate(r.Context(), req.Event, req.Channel); err != nil {
+		log.Error("failed to delete notification template", sl.Err(err))
+		h.resp.Fail(w, r, err, "failed to delete notification template")
+		return
+	}
+
+	h.resp.JSON(w, r, http.StatusOK, DeleteTemplateResponse{Event: req.Event, Channel: req.Channel, Deleted: true})
+	log.Info("notification template deleted successfully")
+}
@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"net/http"
+	"pull-request-assigner/internal/apperrors"
+	"pull-request-assigner/internal/service"
+	"testing"
+)
+
+func TestNotificationTemplateHandlerErrors(t *testing.T) {
+	mock := &templateManagerMock{}
+	h := NewNotificationTemplateHandler(mock, discardLogger())
+
+	const (
+		saveBody   = `{"event":"review.assigned","channel":"webhook","body":"{{.PullRequestName}}"}`
+		deleteBody = `{"event":"review.assigned","channel":"webhook"}`
+	)
+
+	runErrorCases(t, &mock.mockBase, []errorCase{
+		{name: "list internal", serve: h.ListTemplates, method: http.MethodGet, target: "/admin/templates",
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "ListTemplates"},
+
+		{name: "save invalid body", serve: h.SaveTemplate, target: "/admin/templates/save", body: "{",
+			status: http.StatusBadRequest, code: "INVALID_REQUEST"},
+		{name: "save invalid template", serve: h.SaveTemplate, target: "/admin/templates/save", body: saveBody,
+			err: &service.TemplateError{Reason: "unknown channel"}, status: http.StatusBadRequest, code: "INVALID_TEMPLATE", called: "SaveTemplate"},
+		{name: "save internal", serve: h.SaveTemplate, target: "/admin/templates/save", body: saveBody,
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "SaveTemplate"},
+
+		{name: "delete invalid body", serve: h.DeleteTemplate, target: "/admin/templates/delete", body: "{",
+			status: http.StatusBadRequest, code: "INVALID_REQUEST"},
+		{name: "delete not found", serve: h.DeleteTemplate, target: "/admin/templates/delete", body: deleteBody,
+			err: apperrors.ErrTemplateNotFound, status: http.StatusNotFound, code: "NOT_FOUND", called: "DeleteTemplate"},
+		{name: "delete internal", serve: h.DeleteTemplate, target: "/admin/templates/delete", body: deleteBody,
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "DeleteTemplate"},
+	})
+}
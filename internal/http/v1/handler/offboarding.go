@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"pull-request-assigner/internal/apperrors"
+	"pull-request-assigner/internal/domain/models"
+	"pull-request-assigner/internal/http/httpio"
+	"pull-request-assigner/internal/lib/logger/sl"
+)
+
+type (
+	OffboardRequest struct {
+		UserID            string `json:"user_id"`
+		Anonymize         bool   `json:"anonymize"`
+		ConfirmationToken string `json:"confirmation_token"`
+	}
+
+	OffboardResponse struct {
+		Offboarding *models.OffboardingReport `json:"offboarding"`
+	}
+)
+
+type UserOffboarder interface {
+	OffboardUser(ctx context.Context, userID string, anonymize bool, confirmationToken string) (*models.OffboardingReport, error)
+}
+
+type OffboardingHandler struct {
+	offboardingService UserOffboarder
+	log                *slog.Logger
+	resp               *httpio.Responder
+}
+
+func NewOffboardingHandler(offboardingService UserOffboarder, log *slog.Logger) *OffboardingHandler {
+	return &OffboardingHandler{
+		offboardingService: offboardingService,
+		log:                log,
+		resp:               httpio.NewResponder(log),
+	}
+}
+
+func (h *OffboardingHandler) Offboard(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.offboarding.Offboard"
+
+	log := h.log.With(slog.String("op", op))
+
+	var req OffboardRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("invalid request body", sl.Err(err))
+		h.resp.Error(w, r, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+		return
+	}
+
+	if req.UserID == "" {
+		log.Error("user_id is required")
+		h.resp.Error(w, r, http.StatusBadRequest, "USER_ID_REQUIRED", "user_id is required")
+		return
+	}
+
+	report, err := h.offboardingService.OffboardUser(r.Context(), req.UserID, req.Anonymize, req.ConfirmationToken)
+	if err != nil {
+		log.Error("failed to offboard user", sl.Err(err))
+
+		switch {
+		case errors.Is(err, apperrors.ErrInvalidConfirmation):
+			h.resp.Error(w, r, http.StatusBadRequest, "INVALID_CONFIRMATION", "confirmation_token does not match")
+		default:
+			h.resp.Fail(w, r, err, "failed to offboard user")
+		}
+		return
+	}
+
+	h.resp.JSON(w, r, http.StatusOK, OffboardResponse{Offboarding: report})
+	log.Info("user offboarded successfully",
+		slog.String("user_id", report.UserID),
+		slog.Int("reassigned", len(report.Reassigned)))
+}
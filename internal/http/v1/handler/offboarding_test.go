@@ -0,0 +1,27 @@
+package handler
+
+import (
+	"net/http"
+	"pull-request-assigner/internal/apperrors"
+	"testing"
+)
+
+func TestOffboardingHandlerErrors(t *testing.T) {
+	mock := &userOffboarderMock{}
+	h := NewOffboardingHandler(mock, discardLogger())
+
+	runErrorCases(t, &mock.mockBase, []errorCase{
+		{name: "invalid body", serve: h.Offboard, target: "/users/offboard", body: "{",
+			status: http.StatusBadRequest, code: "INVALID_REQUEST"},
+		{name: "missing user", serve: h.Offboard, target: "/users/offboard", body: `{}`,
+			status: http.StatusBadRequest, code: "USER_ID_REQUIRED"},
+		{name: "invalid user id", serve: h.Offboard, target: "/users/offboard", body: `{"user_id":"x"}`,
+			err: apperrors.ErrInvalidUserID, status: http.StatusBadRequest, code: "INVALID_USER_ID", called: "OffboardUser"},
+		{name: "user not found", serve: h.Offboard, target: "/users/offboard", body: `{"user_id":"u9"}`,
+			err: apperrors.ErrUserNotFound, status: http.StatusNotFound, code: "NOT_FOUND", called: "OffboardUser"},
+		{name: "invalid confirmation", serve: h.Offboard, target: "/users/offboard", body: `{"user_id":"u9","anonymize":true,"confirmation_token":"x"}`,
+			err: apperrors.ErrInvalidConfirmation, status: http.StatusBadRequest, code: "INVALID_CONFIRMATION", called: "OffboardUser"},
+		{name: "internal", serve: h.Offboard, target: "/users/offboard", body: `{"user_id":"u9"}`,
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "OffboardUser"},
+	})
+}
@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"pull-request-assigner/internal/apperrors"
+	"pull-request-assigner/internal/domain/models"
+	"pull-request-assigner/internal/http/httpio"
+	"pull-request-assigner/internal/http/v1/middleware"
+	"pull-request-assigner/internal/lib/logger/sl"
+)
+
+type (
+	UpdateOrgPolicyRequest struct {
+		HoldUntilCIGreen *bool `json:"hold_until_ci_green"`
+		MinReviewers     *int  `json:"min_reviewers"`
+		ReviewersPerPR   *int  `json:"reviewers_per_pr"`
+
+		DefaultPriority       *string   `json:"default_priority"`
+		DefaultLabels         *[]string `json:"default_labels"`
+		DefaultRequiredSkills *[]string `json:"default_required_skills"`
+
+		ExcludeCoAuthors      *bool `json:"exclude_co_authors"`
+		ExcludePairingSession *bool `json:"exclude_pairing_session"`
+
+		DiversifyPairs *bool `json:"diversify_reviewer_pairs"`
+	}
+
+	OrgPolicyResponse struct {
+		Policy *models.OrgPolicy `json:"policy"`
+	}
+)
+
+type PolicyManager interface {
+	GetOrgPolicy(ctx context.Context) (*models.OrgPolicy, error)
+	UpdateOrgPolicy(ctx context.Context, update models.OrgPolicyUpdate, actorID string) (*models.OrgPolicy, error)
+	GetEffectivePolicy(ctx context.Context, teamName string) (*models.EffectivePolicy, error)
+}
+
+type PolicyHandler struct {
+	policyService PolicyManager
+	log           *slog.Logger
+	resp          *httpio.Responder
+}
+
+func NewPolicyHandler(policyService PolicyManager, log *slog.Logger) *PolicyHandler {
+	return &PolicyHandler{
+		policyService: policyService,
+		log:           log,
+		resp:          httpio.NewResponder(log),
+	}
+}
+
+func (h *PolicyHandler) GetOrgPolicy(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.policy.GetOrgPolicy"
+
+	log := h.log.With(slog.String("op", op))
+
+	policy, err := h.policyService.GetOrgPolicy(r.Context())
+	if err != nil {
+		log.Error("failed to get org policy", sl.Err(err))
+		h.resp.Fail(w, r, err, "failed to get org policy")
+		return
+	}
+
+	h.resp.JSON(w, r, http.StatusOK, OrgPolicyResponse{Policy: policy})
+}
+
+func (h *PolicyHandler) UpdateOrgPolicy(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.policy.UpdateOrgPolicy"
+
+	log := h.log.With(slog.String("op", op))
+
+	var req UpdateOrgPolicyRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("invalid request body", sl.Err(err))
+		h.resp.Error(w, r, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+		return
+	}
+
+	update := models.OrgPolicyUpdate{
+		HoldUntilCIGreen: req.HoldUntilCIGreen,
+		MinReviewers:     req.MinReviewers,
+		ReviewersPerPR:   req.ReviewersPerPR,
+
+		DefaultPriority:       req.DefaultPriority,
+		DefaultLabels:         req.DefaultLabels,
+		DefaultRequiredSkills: req.DefaultRequiredSkills,
+
+		ExcludeCoAuthors:      req.ExcludeCoAuthors,
+		ExcludePairingSession: req.ExcludePairingSession,
+
+		DiversifyPairs: req.DiversifyPairs,
+	}
+
+	actorID, _ := middleware.UserIDFromContext(r.Context())
+
+	policy, err := h.policyService.UpdateOrgPolicy(r.Context(), update, actorID)
+	if err != nil {
+		log.Error("failed to update org policy", sl.Err(err))
+
+		switch {
+		case errors.Is(err, apperrors.ErrInvalidPolicy):
+			h.resp.Error(w, r, http.StatusBadRequest, "INVALID_POLICY", "invalid org policy")
+		default:
+			h.resp.Fail(w, r, err, "failed to update org policy")
+		}
+		return
+	}
+
+	h.resp.JSON(w, r, http.StatusOK, OrgPolicyResponse{Policy: policy})
+	log.Info("org policy updated successfully")
+}
+
+func (h *PolicyHandler) GetEffectivePolicy(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.policy.GetEffectivePolicy"
+
+	log := h.log.With(slog.String("op", op))
+
+	teamName := r.URL.Query().Get("team_name")
+	if teamName == "" {
+		log.Error("team_name is required")
+		h.resp.Error(w, r, http.StatusBadRequest, "TEAM_NAME_REQUIRED", "team_name is required")
+		return
+	}
+
+	policy, err := h.policyService.GetEffectivePolicy(r.Context(), teamName)
+	if err != nil {
+		log.Error("failed to get effective policy", sl.Err(err))
+		h.resp.Fail(w, r, err, "failed to get effective policy")
+		return
+	}
+
+	h.resp.JSON(w, r, http.StatusOK, policy)
+}
@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"net/http"
+	"pull-request-assigner/internal/apperrors"
+	"testing"
+)
+
+func TestPolicyHandlerErrors(t *testing.T) {
+	mock := &policyManagerMock{}
+	h := NewPolicyHandler(mock, discardLogger())
+
+	const updateBody = `{"min_reviewers":2}`
+
+	runErrorCases(t, &mock.mockBase, []errorCase{
+		{name: "org get internal", serve: h.GetOrgPolicy, method: http.MethodGet, target: "/policy/org",
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "GetOrgPolicy"},
+
+		{name: "org update invalid body", serve: h.UpdateOrgPolicy, target: "/admin/policy/update", body: "{",
+			status: http.StatusBadRequest, code: "INVALID_REQUEST"},
+		{name: "org update invalid policy", serve: h.UpdateOrgPolicy, target: "/admin/policy/update", body: `{"min_reviewers":-1}`,
+			err: apperrors.ErrInvalidPolicy, status: http.StatusBadRequest, code: "INVALID_POLICY", called: "UpdateOrgPolicy"},
+		{name: "org update internal", serve: h.UpdateOrgPolicy, target: "/admin/policy/update", body: updateBody,
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "UpdateOrgPolicy"},
+
+		{name: "effective missing name", serve: h.GetEffectivePolicy, method: http.MethodGet, target: "/policy/effective",
+			status: http.StatusBadRequest, code: "TEAM_NAME_REQUIRED"},
+		{name: "effective not found", serve: h.GetEffectivePolicy, method: http.MethodGet, target: "/policy/effective?team_name=ghost",
+			err: apperrors.ErrTeamNotFound, status: http.StatusNotFound, code: "NOT_FOUND", called: "GetEffectivePolicy"},
+		{name: "effective internal", serve: h.GetEffectivePolicy, method: http.MethodGet, target: "/policy/effective?team_name=backend",
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "GetEffectivePolicy"},
+	})
+}
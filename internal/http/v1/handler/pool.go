@@ -0,0 +1,244 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"pull-request-assigner/internal/apperrors"
+	"pull-request-assigner/internal/domain/models"
+	"pull-request-assigner/internal/http/httpio"
+	"pull-request-assigner/internal/lib/logger/sl"
+)
+
+type (
+	CreatePoolRequest struct {
+		PoolName       string   `json:"pool_name"`
+		ReviewersPerPR int      `json:"reviewers_per_pr"`
+		Members        []string `json:"members"`
+	}
+
+	UpdatePoolRequest struct {
+		PoolName       string `json:"pool_name"`
+		ReviewersPerPR int    `json:"reviewers_per_pr"`
+	}
+
+	DeletePoolRequest struct {
+		PoolName string `json:"pool_name"`
+	}
+
+	PoolMembersRequest struct {
+		PoolName string   `json:"pool_name"`
+		UserIDs  []string `json:"user_ids"`
+	}
+
+	PoolResponse struct {
+		Pool *models.ReviewerPool `json:"pool"`
+	}
+
+	ListPoolsResponse struct {
+		Pools []models.ReviewerPool `json:"pools"`
+	}
+
+	DeletePoolResponse struct {
+		PoolName string `json:"pool_name"`
+		Deleted  bool   `json:"deleted"`
+	}
+)
+
+type PoolManager interface {
+	CreatePool(ctx context.Context, poolName string, reviewersPerPR int, members []string) (*models.ReviewerPool, error)
+	GetPool(ctx context.Context, poolName string) (*models.ReviewerPool, error)
+	ListPools(ctx context.Context) ([]models.ReviewerPool, error)
+	UpdatePool(ctx context.Context, poolName string, reviewersPerPR int) (*models.ReviewerPool, error)
+	DeletePool(ctx context.Context, poolName string) error
+	AddPoolMembers(ctx context.Context, poolName string, members []string) (*models.ReviewerPool, error)
+	RemovePoolMembers(ctx context.Context, poolName string, members []string) (*models.ReviewerPool, error)
+	GetPoolStats(ctx context.Context, poolName string) (*models.PoolStats, error)
+}
+
+type PoolHandler struct {
+	poolService PoolManager
+	log         *slog.Logger
+	resp        *httpio.Responder
+}
+
+func NewPoolHandler(poolService PoolManager, log *slog.Logger) *PoolHandler {
+	return &PoolHandler{
+		poolService: poolService,
+		log:         log,
+		resp:        httpio.NewResponder(log),
+	}
+}
+
+func (h *PoolHandler) CreatePool(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.pool.CreatePool"
+
+	log := h.log.With(slog.String("op", op))
+
+	var req CreatePoolRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("invalid request body", sl.Err(err))
+		h.resp.Error(w, r, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+		return
+	}
+
+	pool, err := h.poolService.CreatePool(r.Context(), req.PoolName, req.ReviewersPerPR, req.Members)
+	if err != nil {
+		log.Error("failed to create reviewer pool", sl.Err(err))
+
+		switch {
+		case errors.Is(err, apperrors.ErrPoolExists):
+			h.resp.Error(w, r, http.StatusConflict, "POOL_EXISTS",
+				"reviewer pool %s already exists", req.PoolName)
+		default:
+			h.resp.Fail(w, r, err, "failed to create reviewer pool")
+		}
+		return
+	}
+
+	h.resp.JSON(w, r, http.StatusCreated, PoolResponse{Pool: pool})
+	log.Info("reviewer pool created successfully")
+}
+
+func (h *PoolHandler) GetPool(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.pool.GetPool"
+
+	log := h.log.With(slog.String("op", op))
+
+	pool, err := h.poolService.GetPool(r.Context(), r.URL.Query().Get("pool_name"))
+	if err != nil {
+		log.Error("failed to get reviewer pool", sl.Err(err))
+		h.resp.Fail(w, r, err, "failed to get reviewer pool")
+		return
+	}
+
+	h.resp.JSON(w, r, http.StatusOK, PoolResponse{Pool: pool})
+}
+
+func (h *PoolHandler) ListPools(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.pool.ListPools"
+
+	log := h.log.With(slog.String("op", op))
+
+	pools, err := h.poolService.ListPools(r.Context())
+	if err != nil {
+		log.Error("failed to list reviewer pools", sl.Err(err))
+		h.resp.Fail(w, r, err, "failed to list reviewer pools")
+		return
+	}
+
+	h.resp.JSON(w, r, http.StatusOK, ListPoolsResponse{Pools: pools})
+}
+
+func (h *PoolHandler) UpdatePool(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.pool.UpdatePool"
+
+	log := h.log.With(slog.String("op", op))
+
+	var req UpdatePoolRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("invalid request body", sl.Err(err))
+		h.resp.Error(w, r, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+		return
+	}
+
+	pool, err := h.poolService.UpdatePool(r.Context(), req.PoolName, req.ReviewersPerPR)
+	if err != nil {
+		log.Error("failed to update reviewer pool", sl.Err(err))
+		h.resp.Fail(w, r, err, "failed to update reviewer pool")
+		return
+	}
+
+	h.resp.JSON(w, r, http.StatusOK, PoolResponse{Pool: pool})
+	log.Info("reviewer pool updated successfully")
+}
+
+func (h *PoolHandler) DeletePool(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.pool.DeletePool"
+
+	log := h.log.With(slog.String("op", op))
+
+	var req DeletePoolRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("invalid request body", sl.Err(err))
+		h.resp.Error(w, r, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+		return
+	}
+
+	if err := h.poolService.DeletePool(r.Context(), req.PoolName); err != nil {
+		log.Error("failed to delete reviewer pool", sl.Err(err))
+		h.resp.Fail(w, r, err, "failed to delete reviewer pool")
+		return
+	}
+
+	h.resp.JSON(w, r, http.StatusOK, DeletePoolResponse{PoolName: req.PoolName, Deleted: true})
+	log.Info("reviewer pool deleted successfully")
+}
+
+func (h *PoolHandler) AddPoolMembers(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.pool.AddPoolMembers"
+
+	log := h.log.With(slog.String("op", op))
+
+	var req PoolMembersRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("invalid request body", sl.Err(err))
+		h.resp.Error(w, r, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+		return
+	}
+
+	pool, err := h.poolService.AddPoolMembers(r.Context(), req.PoolName, req.UserIDs)
+	if err != nil {
+		log.Error("failed to add reviewer pool members", sl.Err(err))
+		h.resp.Fail(w, r, err, "failed to add reviewer pool members")
+		return
+	}
+
+	h.resp.JSON(w, r, http.StatusOK, PoolResponse{Pool: pool})
+	log.Info("reviewer pool members added successfully")
+}
+
+func (h *PoolHandler) RemovePoolMembers(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.pool.RemovePoolMembers"
+
+	log := h.log.With(slog.String("op", op))
+
+	var req PoolMembersRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("invalid request body", sl.Err(err))
+		h.resp.Error(w, r, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+		return
+	}
+
+	pool, err := h.poolService.RemovePoolMembers(r.Context(), req.PoolName, req.UserIDs)
+	if err != nil {
+		log.Error("failed to remove reviewer pool members", sl.Err(err))
+		h.resp.Fail(w, r, err, "failed to remove reviewer pool members")
+		return
+	}
+
+	h.resp.JSON(w, r, http.StatusOK, PoolResponse{Pool: pool})
+	log.Info("reviewer pool members removed successfully")
+}
+
+func (h *PoolHandler) GetPoolStats(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.pool.GetPoolStats"
+
+	log := h.log.With(slog.String("op", op))
+
+	stats, err := h.poolService.GetPoolStats(r.Context(), r.URL.Query().Get("pool_name"))
+	if err != nil {
+		log.Error("failed to get reviewer pool stats", sl.Err(err))
+		h.resp.Fail(w, r, err, "failed to get reviewer pool stats")
+		return
+	}
+
+	h.resp.JSON(w, r, http.StatusOK, stats)
+}
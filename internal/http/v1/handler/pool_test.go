@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"net/http"
+	"pull-request-assigner/internal/apperrors"
+	"testing"
+)
+
+func TestPoolHandlerErrors(t *testing.T) {
+	mock := &poolManagerMock{}
+	h := NewPoolHandler(mock, discardLogger())
+
+	const (
+		createBody  = `{"pool_name":"api-guild","reviewers_per_pr":2,"members":["u1","u10"]}`
+		updateBody  = `{"pool_name":"api-guild","reviewers_per_pr":3}`
+		poolBody    = `{"pool_name":"api-guild"}`
+		membersBody = `{"pool_name":"api-guild","user_ids":["u2"]}`
+	)
+
+	runErrorCases(t, &mock.mockBase, []errorCase{
+		{name: "create invalid body", serve: h.CreatePool, target: "/pool/create", body: "{",
+			status: http.StatusBadRequest, code: "INVALID_REQUEST"},
+		{name: "create name required", serve: h.CreatePool, target: "/pool/create", body: createBody,
+			err: apperrors.ErrPoolNameRequired, status: http.StatusBadRequest, code: "POOL_NAME_REQUIRED", called: "CreatePool"},
+		{name: "create invalid policy", serve: h.CreatePool, target: "/pool/create", body: createBody,
+			err: apperrors.ErrInvalidPoolPolicy, status: http.StatusBadRequest, code: "INVALID_POOL_POLICY", called: "CreatePool"},
+		{name: "create invalid member", serve: h.CreatePool, target: "/pool/create", body: createBody,
+			err: apperrors.ErrInvalidUserID, status: http.StatusBadRequest, code: "INVALID_USER_ID", called: "CreatePool"},
+		{name: "create member not found", serve: h.CreatePool, target: "/pool/create", body: createBody,
+			err: apperrors.ErrUserNotFound, status: http.StatusNotFound, code: "NOT_FOUND", called: "CreatePool"},
+		{name: "create exists", serve: h.CreatePool, target: "/pool/create", body: createBody,
+			err: apperrors.ErrPoolExists, status: http.StatusConflict, code: "POOL_EXISTS", called: "CreatePool"},
+		{name: "create internal", serve: h.CreatePool, target: "/pool/create", body: createBody,
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "CreatePool"},
+
+		{name: "get not found", serve: h.GetPool, method: http.MethodGet, target: "/pool/get?pool_name=api-guild",
+			err: apperrors.ErrPoolNotFound, status: http.StatusNotFound, code: "NOT_FOUND", called: "GetPool"},
+		{name: "get name required", serve: h.GetPool, method: http.MethodGet, target: "/pool/get",
+			err: apperrors.ErrPoolNameRequired, status: http.StatusBadRequest, code: "POOL_NAME_REQUIRED", called: "GetPool"},
+		{name: "list internal", serve: h.ListPools, method: http.MethodGet, target: "/pool/list",
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "ListPools"},
+
+		{name: "update invalid body", serve: h.UpdatePool, target: "/pool/update", body: "{",
+			status: http.StatusBadRequest, code: "INVALID_REQUEST"},
+		{name: "update invalid policy", serve: h.UpdatePool, target: "/pool/update", body: updateBody,
+			err: apperrors.ErrInvalidPoolPolicy, status: http.StatusBadRequest, code: "INVALID_POOL_POLICY", called: "UpdatePool"},
+		{name: "update not found", serve: h.UpdatePool, target: "/pool/update", body: updateBody,
+			err: apperrors.ErrPoolNotFound, status: http.StatusNotFound, code: "NOT_FOUND", called: "UpdatePool"},
+
+		{name: "delete invalid body", serve: h.DeletePool, target: "/pool/delete", body: "{",
+			status: http.StatusBadRequest, code: "INVALID_REQUEST"},
+		{name: "delete not found", serve: h.DeletePool, target: "/pool/delete", body: poolBody,
+			err: apperrors.ErrPoolNotFound, status: http.StatusNotFound, code: "NOT_FOUND", called: "DeletePool"},
+		{name: "delete internal", serve: h.DeletePool, target: "/pool/delete", body: poolBody,
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "DeletePool"},
+
+		{name: "add members invalid body", serve: h.AddPoolMembers, target: "/pool/members/add", body: "{",
+			status: http.StatusBadRequest, code: "INVALID_REQUEST"},
+		{name: "add members pool not found", serve: h.AddPoolMembers, target: "/pool/members/add", body: membersBody,
+			err: apperrors.ErrPoolNotFound, status: http.StatusNotFound, code: "NOT_FOUND", called: "AddPoolMembers"},
+		{name: "add members invalid user", serve: h.AddPoolMembers, target: "/pool/members/add", body: membersBody,
+			err: apperrors.ErrInvalidUserID, status: http.StatusBadRequest, code: "INVALID_USER_ID", called: "AddPoolMembers"},
+		{name: "remove members invalid body", serve: h.RemovePoolMembers, target: "/pool/members/remove", body: "{",
+			status: http.StatusBadRequest, code: "INVALID_REQUEST"},
+		{name: "remove members pool not found", serve: h.RemovePoolMembers, target: "/pool/members/remove", body: membersBody,
+			err: apperrors.ErrPoolNotFound, status: http.StatusNotFound, code: "NOT_FOUND", called: "RemovePoolMembers"},
+
+		{name: "stats not found", serve: h.GetPoolStats, method: http.MethodGet, target: "/pool/stats?pool_name=api-guild",
+			err: apperrors.ErrPoolNotFound, status: http.StatusNotFound, code: "NOT_FOUND", called: "GetPoolStats"},
+		{name: "stats internal", serve: h.GetPoolStats, method: http.MethodGet, target: "/pool/stats?pool_name=api-guild",
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "GetPoolStats"},
+	})
+}
@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"pull-request-assigner/internal/apperrors"
+	"pull-request-assigner/internal/domain/models"
+	"pull-request-assigner/internal/http/httpio"
+	"pull-request-assigner/internal/lib/logger/sl"
+)
+
+type (
+	SavePRStatusRequest struct {
+		Status     string `json:"status"`
+		IsTerminal bool   `json:"is_terminal"`
+	}
+
+	SavePRStatusResponse struct {
+		Status *models.PRStatus `json:"status"`
+	}
+
+	DeletePRStatusRequest struct {
+		Status string `json:"status"`
+	}
+
+	DeletePRStatusResponse struct {
+		Status  string `json:"status"`
+		Deleted bool   `json:"deleted"`
+	}
+
+	// SetPRTransitionsRequest replaces the team's transitions, or the org's
+	// when TeamName is empty. An empty Transitions list makes the team follow
+	// the org's transitions again.
+	SetPRTransitionsRequest struct {
+		TeamName    string                      `json:"team_name"`
+		Transitions []models.PRStatusTransition `json:"transitions"`
+	}
+)
+
+type PRStatusConfigurator interface {
+	SaveStatus(ctx context.Context, status models.PRStatus) (*models.PRStatus, error)
+	DeleteStatus(ctx context.Context, status string) error
+	SetTransitions(ctx context.Context, teamName string, transitions []models.PRStatusTransition) (*models.PRStatusMachine, error)
+}
+
+type PRStatusHandler struct {
+	prService PRStatusConfigurator
+	log       *slog.Logger
+	resp      *httpio.Responder
+}
+
+func NewPRStatusHandler(prService PRStatusConfigurator, log *slog.Logger) *PRStatusHandler {
+	return &PRStatusHandler{
+		prService: prService,
+		log:       log,
+		resp:      httpio.NewResponder(log),
+	}
+}
+
+func (h *PRStatusHandler) SaveStatus(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.prStatus.SaveStatus"
+
+	log := h.log.With(slog.String("op", op))
+
+	var req SavePRStatusRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("invalid request body", sl.Err(err))
+		h.resp.Error(w, r, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+		return
+	}
+
+	status, err := h.prService.SaveStatus(r.Context(), models.PRStatus{
+		Status:     req.Status,
+		IsTerminal: req.IsTerminal,
+	})
+	if err != nil {
+		log.Error("failed to save PR status", sl.Err(err))
+		h.failStatus(w, r, err, "failed to save PR status")
+		return
+	}
+
+	h.resp.JSON(w, r, http.StatusOK, SavePRStatusResponse{Status: status})
+	log.Info("PR status saved", slog.String("status", status.Status))
+}
+
+func (h *PRStatusHandler) DeleteStatus(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.prStatus.DeleteStatus"
+
+	log := h.log.With(slog.String("op", op))
+
+	var req DeletePRStatusRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("invalid request body", sl.Err(err))
+		h.resp.Error(w, r, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+		return
+	}
+
+	if err := h.prService.DeleteStatus(r.Context(), req.Status); err != nil {
+		log.Error("failed to delete PR status", sl.Err(err))
+		h.failStatus(w, r, err, "failed to delete PR status")
+		return
+	}
+
+	h.resp.JSON(w, r, http.StatusOK, DeletePRStatusResponse{Status: req.Status, Deleted: true})
+	log.Info("PR status deleted", slog.String("status", req.Status))
+}
+
+func (h *PRStatusHandler) SetTransitions(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.prStatus.SetTransitions"
+
+	log := h.log.With(slog.String("op", op))
+
+	var req SetPRTransitionsRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("invalid request body", sl.Err(err))
+		h.resp.Error(w, r, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+		return
+	}
+
+	machine, err := h.prService.SetTransitions(r.Context(), req.TeamName, req.Transitions)
+	if err != nil {
+		log.Error("failed to set PR status transitions", sl.Err(err))
+		h.failStatus(w, r, err, "failed to set PR status transitions")
+		return
+	}
+
+	h.resp.JSON(w, r, http.StatusOK, ListStatusesResponse{PRStatusMachine: machine})
+	log.Info("PR status transitions set", slog.String("team_name", req.TeamName))
+}
+
+func (h *PRStatusHandler) failStatus(w http.ResponseWriter, r *http.Request, err error, message string) {
+	switch {
+	case errors.Is(err, apperrors.ErrPRStatusRequired):
+		h.resp.Error(w, r, http.StatusBadRequest, "STATUS_REQUIRED", "status is required")
+	case errors.Is(err, apperrors.ErrUnknownPRStatus):
+		h.resp.Error(w, r, http.StatusBadRequest, "UNKNOWN_STATUS", "status is not configured")
+	case errors.Is(err, apperrors.ErrBuiltinPRStatus):
+		h.resp.Error(w, r, http.StatusBadRequest, "BUILTIN_STATUS", "OPEN and MERGED cannot be changed")
+	case errors.Is(err, apperrors.ErrInvalidStatusMachine):
+		h.resp.Error(w, r, http.StatusBadRequest, "INVALID_TRANSITIONS",
+			"transitions must keep MERGED reachable from OPEN and may not leave a terminal status")
+	case errors.Is(err, apperrors.ErrPRStatusInUse):
+		h.resp.Error(w, r, http.StatusConflict, "STATUS_IN_USE", "status is still used by pull requests or transitions")
+	default:
+		h.resp.Fail(w, r, err, message)
+	}
+}
@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"net/http"
+	"pull-request-assigner/internal/apperrors"
+	"testing"
+)
+
+func TestPRStatusHandlerErrors(t *testing.T) {
+	mock := &prStatusConfiguratorMock{}
+	h := NewPRStatusHandler(mock, discardLogger())
+
+	const (
+		statusBody      = `{"status":"CLOSED","is_terminal":true}`
+		transitionsBody = `{"team_name":"Backend","transitions":[{"from_status":"OPEN","to_status":"MERGED"}]}`
+	)
+
+	runErrorCases(t, &mock.mockBase, []errorCase{
+		{name: "save invalid body", serve: h.SaveStatus, target: "/admin/prStatuses/save", body: "{",
+			status: http.StatusBadRequest, code: "INVALID_REQUEST"},
+		{name: "save missing status", serve: h.SaveStatus, target: "/admin/prStatuses/save", body: `{}`,
+			err: apperrors.ErrPRStatusRequired, status: http.StatusBadRequest, code: "STATUS_REQUIRED", called: "SaveStatus"},
+		{name: "save built-in", serve: h.SaveStatus, target: "/admin/prStatuses/save", body: `{"status":"MERGED"}`,
+			err: apperrors.ErrBuiltinPRStatus, status: http.StatusBadRequest, code: "BUILTIN_STATUS", called: "SaveStatus"},
+		{name: "save terminal with transitions", serve: h.SaveStatus, target: "/admin/prStatuses/save", body: statusBody,
+			err: apperrors.ErrInvalidStatusMachine, status: http.StatusBadRequest, code: "INVALID_TRANSITIONS", called: "SaveStatus"},
+		{name: "save internal", serve: h.SaveStatus, target: "/admin/prStatuses/save", body: statusBody,
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "SaveStatus"},
+
+		{name: "delete invalid body", serve: h.DeleteStatus, target: "/admin/prStatuses/delete", body: "{",
+			status: http.StatusBadRequest, code: "INVALID_REQUEST"},
+		{name: "delete unknown", serve: h.DeleteStatus, target: "/admin/prStatuses/delete", body: `{"status":"CLOSED"}`,
+			err: apperrors.ErrUnknownPRStatus, status: http.StatusBadRequest, code: "UNKNOWN_STATUS", called: "DeleteStatus"},
+		{name: "delete in use", serve: h.DeleteStatus, target: "/admin/prStatuses/delete", body: `{"status":"CLOSED"}`,
+			err: apperrors.ErrPRStatusInUse, status: http.StatusConflict, code: "STATUS_IN_USE", called: "DeleteStatus"},
+
+		{name: "transitions invalid body", serve: h.SetTransitions, target: "/admin/prStatuses/transitions", body: "{",
+			status: http.StatusBadRequest, code: "INVALID_REQUEST"},
+		{name: "transitions unknown status", serve: h.SetTransitions, target: "/admin/prStatuses/transitions", body: transitionsBody,
+			err: apperrors.ErrUnknownPRStatus, status: http.StatusBadRequest, code: "UNKNOWN_STATUS", called: "SetTransitions"},
+		{name: "transitions invalid", serve: h.SetTransitions, target: "/admin/prStatuses/transitions", body: transitionsBody,
+			err: apperrors.ErrInvalidStatusMachine, status: http.StatusBadRequest, code: "INVALID_TRANSITIONS", called: "SetTransitions"},
+		{name: "transitions team not found", serve: h.SetTransitions, target: "/admin/prStatuses/transitions", body: transitionsBody,
+			err: apperrors.ErrTeamNotFound, status: http.StatusNotFound, code: "NOT_FOUND", called: "SetTransitions"},
+	})
+}
@@ -1,14 +1,18 @@
 package handler
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"errors"
-	"fmt"
 	"log/slog"
 	"net/http"
 	"pull-request-assigner/internal/apperrors"
 	"pull-request-assigner/internal/domain/models"
+	"pull-request-assigner/internal/http/httpio"
+	"pull-request-assigner/internal/http/v1/middleware"
+	"pull-request-assigner/internal/lib/i18n"
+	"pull-request-assigner/internal/lib/localtime"
 	"pull-request-assigner/internal/lib/logger/sl"
 	"pull-request-assigner/internal/service"
 	"time"
@@ -16,31 +20,175 @@ import (
 
 type (
 	CreatePRRequest struct {
-		PullRequestID   string `json:"pull_request_id"`
-		PullRequestName string `json:"pull_request_name"`
-		AuthorID        string `json:"author_id"`
+		PullRequestID   string   `json:"pull_request_id"`
+		PullRequestName string   `json:"pull_request_name"`
+		AuthorID        string   `json:"author_id"`
+		TeamName        string   `json:"team_name"`
+		CIStatus        string   `json:"ci_status"`
+		Priority        string   `json:"priority"`
+		Labels          []string `json:"labels"`
+		RequiredSkills  []string `json:"required_skills"`
+		ChangedPaths    []string `json:"changed_paths"`
+		CoAuthors       []string `json:"co_authors"`
+		PairingSession  []string `json:"pairing_session"`
+
+		AutoMerge          bool `json:"auto_merge"`
+		AutoMergeApprovals int  `json:"auto_merge_approvals"`
+
+		ReviewerTeams          []models.ReviewerTeamQuota `json:"reviewer_teams"`
+		RequiredCertifications []string                   `json:"required_certifications"`
+		ReviewerPool           string                     `json:"reviewer_pool"`
 	}
 
 	CreatePRResponse struct {
-		PR *PullRequestWithReviewers `json:"pr"`
+		PR    *PullRequestWithReviewers `json:"pr"`
+		Trace *models.AssignmentTrace   `json:"trace,omitempty"`
 	}
 
+	// MergePRRequest sets OverrideMergeWindow to merge while the team's merge
+	// window is closed; only admins may.
 	MergePRRequest struct {
-		PullRequestID string `json:"pull_request_id"`
+		PullRequestID       string `json:"pull_request_id"`
+		Strict              bool   `json:"strict"`
+		MergedBy            string `json:"merged_by"`
+		OverrideMergeWindow bool   `json:"override_merge_window"`
 	}
 
 	MergePRResponse struct {
+		PR            *PullRequestWithReviewers `json:"pr"`
+		AlreadyMerged bool                      `json:"already_merged"`
+		Warning       *models.MergeWarning      `json:"warning,omitempty"`
+	}
+
+	UpdateCIStatusRequest struct {
+		PullRequestID string `json:"pull_request_id"`
+		CIStatus      string `json:"ci_status"`
+	}
+
+	UpdateCIStatusResponse struct {
+		PR *PullRequestWithReviewers `json:"pr"`
+	}
+
+	SetStatusRequest struct {
+		PullRequestID string `json:"pull_request_id"`
+		Status        string `json:"status"`
+	}
+
+	SetStatusResponse struct {
 		PR *PullRequestWithReviewers `json:"pr"`
 	}
 
+	ListStatusesResponse struct {
+		*models.PRStatusMachine
+	}
+
+	GetCandidatesResponse struct {
+		PullRequestID string                     `json:"pull_request_id"`
+		Candidates    []models.ReviewerCandidate `json:"candidates"`
+	}
+
+	PendingAssignmentsResponse struct {
+		TeamName     string                     `json:"team_name,omitempty"`
+		PullRequests []models.PendingAssignment `json:"pull_requests"`
+	}
+
 	ReassignReviewerRequest struct {
 		PullRequestID string `json:"pull_request_id"`
 		OldReviewerID string `json:"old_reviewer_id"`
+		Reason        string `json:"reason"`
+		Note          string `json:"note"`
 	}
 
 	ReassignReviewerResponse struct {
 		PR         *PullRequestWithReviewers `json:"pr"`
 		ReplacedBy string                    `json:"replaced_by"`
+		Trace      *models.AssignmentTrace   `json:"trace,omitempty"`
+	}
+
+	AssignReviewerRequest struct {
+		PullRequestID     string `json:"pull_request_id"`
+		ReviewerID        string `json:"reviewer_id"`
+		ReplaceReviewerID string `json:"replace_reviewer_id"`
+	}
+
+	AssignReviewerResponse struct {
+		PR *PullRequestWithReviewers `json:"pr"`
+	}
+
+	UnassignReviewerRequest struct {
+		PullRequestID string `json:"pull_request_id"`
+		ReviewerID    string `json:"reviewer_id"`
+	}
+
+	UnassignReviewerResponse struct {
+		PR *PullRequestWithReviewers `json:"pr"`
+	}
+
+	SetReviewerCountRequest struct {
+		PullRequestID string `json:"pull_request_id"`
+		Reviewers     int    `json:"reviewers"`
+	}
+
+	SetReviewerCountResponse struct {
+		PR      *PullRequestWithReviewers `json:"pr"`
+		Added   []string                  `json:"added"`
+		Removed []string                  `json:"removed"`
+	}
+
+	DeclineReviewRequest struct {
+		PullRequestID string `json:"pull_request_id"`
+		ReviewerID    string `json:"reviewer_id"`
+	}
+
+	DeclineReviewResponse struct {
+		PR         *PullRequestWithReviewers `json:"pr"`
+		ReplacedBy string                    `json:"replaced_by,omitempty"`
+	}
+
+	DelegateReviewRequest struct {
+		PullRequestID string `json:"pull_request_id"`
+		ReviewerID    string `json:"reviewer_id"`
+		DelegateID    string `json:"delegate_id"`
+	}
+
+	DelegateReviewResponse struct {
+		PR          *PullRequestWithReviewers `json:"pr"`
+		DelegatedTo string                    `json:"delegated_to"`
+	}
+
+	StartReviewRequest struct {
+		PullRequestID string `json:"pull_request_id"`
+		ReviewerID    string `json:"reviewer_id"`
+	}
+
+	StartReviewResponse struct {
+		PullRequestID   string    `json:"pull_request_id"`
+		ReviewerID      string    `json:"reviewer_id"`
+		ReviewState     string    `json:"review_state"`
+		ReviewStartedAt time.Time `json:"review_started_at"`
+	}
+
+	CompleteReviewRequest struct {
+		PullRequestID string `json:"pull_request_id"`
+		ReviewerID    string `json:"reviewer_id"`
+	}
+
+	CompleteReviewResponse struct {
+		PullRequestID     string    `json:"pull_request_id"`
+		ReviewerID        string    `json:"reviewer_id"`
+		ReviewState       string    `json:"review_state"`
+		ReviewCompletedAt time.Time `json:"review_completed_at"`
+	}
+
+	ApprovePRRequest struct {
+		PullRequestID string `json:"pull_request_id"`
+		ReviewerID    string `json:"reviewer_id"`
+	}
+
+	ApprovePRResponse struct {
+		PullRequestID string    `json:"pull_request_id"`
+		ReviewerID    string    `json:"reviewer_id"`
+		ApprovedAt    time.Time `json:"approved_at"`
 	}
 
 	PullRequestWithReviewers struct {
@@ -48,29 +196,84 @@ type (
 		PullRequestName   string   `json:"pull_request_name"`
 		AuthorID          string   `json:"author_id"`
 		Status            string   `json:"status"`
+		CIStatus          string   `json:"ci_status"`
+		Priority          string   `json:"priority"`
+		Labels            []string `json:"labels"`
+		RequiredSkills    []string `json:"required_skills"`
 		AssignedReviewers []string `json:"assigned_reviewers"`
 		MergedAt          string   `json:"mergedAt,omitempty"`
-	}
+		MergedBy          string   `json:"merged_by,omitempty"`
+		AutoMerge         bool     `json:"auto_merge,omitempty"`
+		AssignmentQueued  bool     `json:"assignment_queued,omitempty"`
+		AssignmentPending bool     `json:"assignment_pending,omitempty"`
+		UnderReviewed     bool     `json:"under_reviewed,omitempty"`
+		AuthorTeam        string   `json:"author_team,omitempty"`
+		ReviewerPool      string   `json:"reviewer_pool,omitempty"`
 
-	PRErrorResponse struct {
-		Error PRErrorDetail `json:"error"`
-	}
-
-	PRErrorDetail struct {
-		Code    string `json:"code"`
-		Message string `json:"message"`
+		ReviewerTeams          []models.ReviewerTeamQuota `json:"reviewer_teams,omitempty"`
+		RequiredCertifications []string                   `json:"required_certifications,omitempty"`
 	}
 )
 
+const reassignReasonMessage = "reason must be one of VACATION, OVERLOADED, CONFLICT, DECLINED, MANUAL, OFFBOARDED, DEACTIVATED"
+
+type PRCreator interface {
+	CreatePRWithReviewers(ctx context.Context, pr models.PullRequest) (*models.PullRequest, []string, error)
+}
+
+type PRStatusManager interface {
+	MergePR(ctx context.Context, prID string, strict bool, mergedBy string, override bool) (*models.PullRequest, []string, bool, *models.MergeWarning, error)
+	ListStatuses(ctx context.Context, teamName string) (*models.PRStatusMachine, error)
+	SetStatus(ctx context.Context, prID string, status string) (*models.PullRequest, []string, error)
+	UpdateCIStatus(ctx context.Context, prID string, ciStatus string) (*models.PullRequest, []string, error)
+}
+
+type ReviewerAssigner interface {
+	ReassignReviewer(ctx context.Context, prID string, oldReviewerID string, reason string, note string) (*models.PullRequest, []string, string, error)
+	AssignReviewer(ctx context.Context, prID string, reviewerID string, replaceReviewerID string, actorID string) (*models.PullRequest, []string, error)
+	UnassignReviewer(ctx context.Context, prID string, reviewerID string, actorID string) (*models.PullRequest, []string, error)
+	SetReviewerCount(ctx context.Context, prID string, count int, actorID string) (*models.PullRequest, []string, []string, []string, error)
+	DelegateReview(ctx context.Context, prID string, reviewerID string, delegateID string) (*models.PullRequest, []string, error)
+	DeclineReview(ctx context.Context, prID string, reviewerID string) (*models.PullRequest, []string, string, error)
+	GetCandidates(ctx context.Context, prID string) ([]models.ReviewerCandidate, error)
+}
+
+type ReviewTracker interface {
+	StartReview(ctx context.Context, prID string, reviewerID string) (time.Time, error)
+	CompleteReview(ctx context.Context, prID string, reviewerID string) (time.Time, error)
+	ApprovePR(ctx context.Context, prID string, reviewerID string) (time.Time, error)
+}
+
+type PendingAssignmentViewer interface {
+	GetPendingAssignments(ctx context.Context, teamName string) ([]models.PendingAssignment, error)
+}
+
+type PRExporter interface {
+	ExportPRs(ctx context.Context, search models.PRSearch, emit func(page []models.PullRequestExport) error) (int, error)
+}
+
+// PullRequestManager is everything PullRequestHandler needs from the
+// pull request service.
+type PullRequestManager interface {
+	PRCreator
+	PRStatusManager
+	ReviewerAssigner
+	ReviewTracker
+	PRExporter
+	PendingAssignmentViewer
+}
+
 type PullRequestHandler struct {
-	prService *service.PullRequestService
+	prService PullRequestManager
 	log       *slog.Logger
+	resp      *httpio.Responder
 }
 
-func NewPullRequestHandler(prService *service.PullRequestService, log *slog.Logger) *PullRequestHandler {
+func NewPullRequestHandler(prService PullRequestManager, log *slog.Logger) *PullRequestHandler {
 	return &PullRequestHandler{
 		prService: prService,
 		log:       log,
+		resp:      httpio.NewResponder(log),
 	}
 }
 
@@ -79,29 +282,36 @@ func (h *PullRequestHandler) CreatePR(w http.ResponseWriter, r *http.Request) {
 
 	log := h.log.With(slog.String("op", op))
 
+	r, assignmentTrace, err := withAssignmentTrace(r)
+	if err != nil {
+		log.Error("invalid debug flag", sl.Err(err))
+		h.resp.Error(w, r, http.StatusBadRequest, "INVALID_DEBUG", "debug must be true or false")
+		return
+	}
+
 	var req CreatePRRequest
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		log.Error("invalid request body", sl.Err(err))
-		h.writeErrorResponse(w, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+		h.resp.Error(w, r, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
 		return
 	}
 
 	if req.PullRequestID == "" {
 		log.Error("pull_request_id is required")
-		h.writeErrorResponse(w, http.StatusBadRequest, "PR_ID_REQUIRED", "pull_request_id is required")
+		h.resp.Error(w, r, http.StatusBadRequest, "PR_ID_REQUIRED", "pull_request_id is required")
 		return
 	}
 
 	if req.PullRequestName == "" {
 		log.Error("pull_request_name is required")
-		h.writeErrorResponse(w, http.StatusBadRequest, "PR_NAME_REQUIRED", "pull_request_name is required")
+		h.resp.Error(w, r, http.StatusBadRequest, "PR_NAME_REQUIRED", "pull_request_name is required")
 		return
 	}
 
 	if req.AuthorID == "" {
 		log.Error("author_id is required")
-		h.writeErrorResponse(w, http.StatusBadRequest, "AUTHOR_REQUIRED", "author_id is required")
+		h.resp.Error(w, r, http.StatusBadRequest, "AUTHOR_REQUIRED", "author_id is required")
 		return
 	}
 
@@ -109,6 +319,21 @@ func (h *PullRequestHandler) CreatePR(w http.ResponseWriter, r *http.Request) {
 		PullRequestId:   req.PullRequestID,
 		PullRequestName: req.PullRequestName,
 		AuthorID:        req.AuthorID,
+		CIStatus:        req.CIStatus,
+		Priority:        req.Priority,
+		Labels:          req.Labels,
+		RequiredSkills:  req.RequiredSkills,
+		ChangedPaths:    req.ChangedPaths,
+		CoAuthors:       req.CoAuthors,
+		PairingSession:  req.PairingSession,
+		ReviewerTeams:   req.ReviewerTeams,
+
+		AutoMerge:          req.AutoMerge,
+		AutoMergeApprovals: req.AutoMergeApprovals,
+
+		RequiredCertifications: req.RequiredCertifications,
+		ReviewerPool:           req.ReviewerPool,
+		AuthorTeam:             req.TeamName,
 	}
 
 	createdPR, reviewers, err := h.prService.CreatePRWithReviewers(r.Context(), pr)
@@ -117,16 +342,29 @@ func (h *PullRequestHandler) CreatePR(w http.ResponseWriter, r *http.Request) {
 
 		switch {
 		case errors.Is(err, apperrors.ErrPRExists):
-			h.writeErrorResponse(w, http.StatusConflict, "PR_EXISTS",
-				fmt.Sprintf("PR %s already exists", req.PullRequestID))
-		case errors.Is(err, apperrors.ErrPRAuthorNotFound):
-			h.writeErrorResponse(w, http.StatusNotFound, "NOT_FOUND", "resource not found")
+			h.resp.Error(w, r, http.StatusConflict, "PR_EXISTS",
+				"PR %s already exists", req.PullRequestID)
 		case errors.Is(err, apperrors.ErrPRTeamNotFound):
-			h.writeErrorResponse(w, http.StatusNotFound, "TEAM_NOT_FOUND", "author team not found")
+			h.resp.Error(w, r, http.StatusNotFound, "TEAM_NOT_FOUND", "author team not found")
 		case errors.Is(err, apperrors.ErrNoReviewerCandidates):
-			h.writeErrorResponse(w, http.StatusNotFound, "NO_REVIEWERS", "no active reviewers available in team")
+			h.resp.Error(w, r, http.StatusNotFound, "NO_REVIEWERS", "no active reviewers available in team")
+		case errors.Is(err, apperrors.ErrInvalidPriority):
+			h.resp.Error(w, r, http.StatusBadRequest, "INVALID_PRIORITY", "priority must be one of LOW, NORMAL, HIGH, CRITICAL")
+		case errors.Is(err, apperrors.ErrInvalidAutoMerge):
+			h.resp.Error(w, r, http.StatusBadRequest, "INVALID_AUTO_MERGE",
+				"auto_merge_approvals must be between 1 and %d", service.MaxAutoMergeApprovals)
+		case errors.Is(err, apperrors.ErrInvalidReviewerTeams):
+			h.resp.Error(w, r, http.StatusBadRequest, "INVALID_REVIEWER_TEAMS", "reviewer_teams must list distinct teams, 1-5 reviewers each")
+		case errors.Is(err, apperrors.ErrReviewerTeamNotFound):
+			h.resp.Error(w, r, http.StatusNotFound, "TEAM_NOT_FOUND", "reviewer team not found")
+		case errors.Is(err, apperrors.ErrPoolNotFound):
+			h.resp.Error(w, r, http.StatusNotFound, "POOL_NOT_FOUND", "reviewer pool not found")
+		case errors.Is(err, apperrors.ErrNoSecurityReviewer):
+			h.resp.Error(w, r, http.StatusNotFound, "NO_SECURITY_REVIEWERS", "no active security team reviewer available")
+		case errors.Is(err, apperrors.ErrNoCertifiedReviewer):
+			h.resp.Error(w, r, http.StatusNotFound, "NO_CERTIFIED_REVIEWERS", "no active certified reviewer available")
 		default:
-			h.writeErrorResponse(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to create PR")
+			h.resp.Fail(w, r, err, "failed to create PR")
 		}
 		return
 	}
@@ -137,12 +375,27 @@ func (h *PullRequestHandler) CreatePR(w http.ResponseWriter, r *http.Request) {
 			PullRequestName:   createdPR.PullRequestName,
 			AuthorID:          createdPR.AuthorID,
 			Status:            createdPR.Status,
+			CIStatus:          createdPR.CIStatus,
+			Priority:          createdPR.Priority,
+			Labels:            createdPR.Labels,
+			RequiredSkills:    createdPR.RequiredSkills,
 			AssignedReviewers: reviewers,
-			MergedAt:          formatMergedAt(createdPR.MergedAt),
+			MergedAt:          formatMergedAt(r, createdPR.MergedAt),
+			MergedBy:          createdPR.MergedBy,
+			AutoMerge:         createdPR.AutoMerge,
+			AssignmentQueued:  createdPR.AssignmentQueued,
+			AssignmentPending: createdPR.AssignmentPending,
+			UnderReviewed:     createdPR.UnderReviewed,
+			AuthorTeam:        createdPR.AuthorTeam,
+			ReviewerPool:      createdPR.ReviewerPool,
+			ReviewerTeams:     createdPR.ReviewerTeams,
+
+			RequiredCertifications: createdPR.RequiredCertifications,
 		},
+		Trace: assignmentTrace,
 	}
 
-	h.writeJSON(w, http.StatusCreated, response)
+	h.resp.JSON(w, r, http.StatusCreated, response)
 	log.Info("PR created successfully")
 }
 
@@ -155,25 +408,27 @@ func (h *PullRequestHandler) MergePR(w http.ResponseWriter, r *http.Request) {
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		log.Error("invalid request body", sl.Err(err))
-		h.writeErrorResponse(w, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+		h.resp.Error(w, r, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
 		return
 	}
 
 	if req.PullRequestID == "" {
 		log.Error("pull_request_id is required")
-		h.writeErrorResponse(w, http.StatusBadRequest, "PR_ID_REQUIRED", "pull_request_id is required")
+		h.resp.Error(w, r, http.StatusBadRequest, "PR_ID_REQUIRED", "pull_request_id is required")
 		return
 	}
 
-	mergedPR, reviewers, err := h.prService.MergePR(r.Context(), req.PullRequestID)
+	mergedPR, reviewers, alreadyMerged, warning, err := h.prService.MergePR(r.Context(), req.PullRequestID, req.Strict, req.MergedBy, req.OverrideMergeWindow)
 	if err != nil {
 		log.Error("failed to merge PR", sl.Err(err))
 
 		switch {
-		case errors.Is(err, apperrors.ErrPRNotFound):
-			h.writeErrorResponse(w, http.StatusNotFound, "NOT_FOUND", "resource not found")
+		case errors.Is(err, apperrors.ErrInvalidPRTransition):
+			h.resp.Error(w, r, http.StatusConflict, "INVALID_TRANSITION", "PR cannot be merged from its current status")
+		case errors.Is(err, apperrors.ErrInvalidUserID):
+			h.resp.Error(w, r, http.StatusBadRequest, "INVALID_USER_ID", "invalid merged_by format")
 		default:
-			h.writeErrorResponse(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to merge PR")
+			h.resp.Fail(w, r, err, "failed to merge PR")
 		}
 		return
 	}
@@ -184,55 +439,228 @@ func (h *PullRequestHandler) MergePR(w http.ResponseWriter, r *http.Request) {
 			PullRequestName:   mergedPR.PullRequestName,
 			AuthorID:          mergedPR.AuthorID,
 			Status:            mergedPR.Status,
+			CIStatus:          mergedPR.CIStatus,
+			Priority:          mergedPR.Priority,
+			Labels:            mergedPR.Labels,
+			RequiredSkills:    mergedPR.RequiredSkills,
 			AssignedReviewers: reviewers,
-			MergedAt:          formatMergedAt(mergedPR.MergedAt),
+			MergedAt:          formatMergedAt(r, mergedPR.MergedAt),
+			MergedBy:          mergedPR.MergedBy,
+			AutoMerge:         mergedPR.AutoMerge,
+			AssignmentQueued:  mergedPR.AssignmentQueued,
+			UnderReviewed:     mergedPR.UnderReviewed,
+			AuthorTeam:        mergedPR.AuthorTeam,
+			ReviewerPool:      mergedPR.ReviewerPool,
 		},
+		AlreadyMerged: alreadyMerged,
 	}
 
-	h.writeJSON(w, http.StatusOK, response)
+	if warning != nil {
+		warning.Message = i18n.Translate(httpio.Language(r), warning.Message)
+		response.Warning = warning
+	}
+
+	h.resp.JSON(w, r, http.StatusOK, response)
 	log.Info("PR merged successfully")
 }
 
+func (h *PullRequestHandler) ListStatuses(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.pullRequest.ListStatuses"
+
+	log := h.log.With(slog.String("op", op))
+
+	machine, err := h.prService.ListStatuses(r.Context(), r.URL.Query().Get("team_name"))
+	if err != nil {
+		log.Error("failed to list PR statuses", sl.Err(err))
+		h.resp.Error(w, r, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to list PR statuses")
+		return
+	}
+
+	response := ListStatusesResponse{PRStatusMachine: machine}
+
+	h.resp.JSON(w, r, http.StatusOK, response)
+}
+
+func (h *PullRequestHandler) SetStatus(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.pullRequest.SetStatus"
+
+	log := h.log.With(slog.String("op", op))
+
+	var req SetStatusRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("invalid request body", sl.Err(err))
+		h.resp.Error(w, r, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+		return
+	}
+
+	if req.PullRequestID == "" {
+		log.Error("pull_request_id is required")
+		h.resp.Error(w, r, http.StatusBadRequest, "PR_ID_REQUIRED", "pull_request_id is required")
+		return
+	}
+
+	if req.Status == "" {
+		log.Error("status is required")
+		h.resp.Error(w, r, http.StatusBadRequest, "STATUS_REQUIRED", "status is required")
+		return
+	}
+
+	updatedPR, reviewers, err := h.prService.SetStatus(r.Context(), req.PullRequestID, req.Status)
+	if err != nil {
+		log.Error("failed to set PR status", sl.Err(err))
+
+		switch {
+		case errors.Is(err, apperrors.ErrUnknownPRStatus):
+			h.resp.Error(w, r, http.StatusBadRequest, "UNKNOWN_STATUS",
+				"status %s is not configured", req.Status)
+		case errors.Is(err, apperrors.ErrInvalidPRTransition):
+			h.resp.Error(w, r, http.StatusConflict, "INVALID_TRANSITION",
+				"transition to %s is not allowed", req.Status)
+		default:
+			h.resp.Fail(w, r, err, "failed to set PR status")
+		}
+		return
+	}
+
+	response := SetStatusResponse{
+		PR: &PullRequestWithReviewers{
+			PullRequestID:     updatedPR.PullRequestId,
+			PullRequestName:   updatedPR.PullRequestName,
+			AuthorID:          updatedPR.AuthorID,
+			Status:            updatedPR.Status,
+			CIStatus:          updatedPR.CIStatus,
+			Priority:          updatedPR.Priority,
+			Labels:            updatedPR.Labels,
+			RequiredSkills:    updatedPR.RequiredSkills,
+			AssignedReviewers: reviewers,
+			MergedAt:          formatMergedAt(r, updatedPR.MergedAt),
+			MergedBy:          updatedPR.MergedBy,
+			AutoMerge:         updatedPR.AutoMerge,
+			AssignmentQueued:  updatedPR.AssignmentQueued,
+			UnderReviewed:     updatedPR.UnderReviewed,
+			AuthorTeam:        updatedPR.AuthorTeam,
+			ReviewerPool:      updatedPR.ReviewerPool,
+		},
+	}
+
+	h.resp.JSON(w, r, http.StatusOK, response)
+	log.Info("PR status changed successfully")
+}
+
+func (h *PullRequestHandler) UpdateCIStatus(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.pullRequest.UpdateCIStatus"
+
+	log := h.log.With(slog.String("op", op))
+
+	var req UpdateCIStatusRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("invalid request body", sl.Err(err))
+		h.resp.Error(w, r, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+		return
+	}
+
+	if req.PullRequestID == "" {
+		log.Error("pull_request_id is required")
+		h.resp.Error(w, r, http.StatusBadRequest, "PR_ID_REQUIRED", "pull_request_id is required")
+		return
+	}
+
+	updatedPR, reviewers, err := h.prService.UpdateCIStatus(r.Context(), req.PullRequestID, req.CIStatus)
+	if err != nil {
+		log.Error("failed to update CI status", sl.Err(err))
+
+		switch {
+		case errors.Is(err, apperrors.ErrPRAlreadyMerged):
+			h.resp.Error(w, r, http.StatusConflict, "PR_MERGED", "cannot update CI status on merged PR")
+		default:
+			h.resp.Fail(w, r, err, "failed to update CI status")
+		}
+		return
+	}
+
+	response := UpdateCIStatusResponse{
+		PR: &PullRequestWithReviewers{
+			PullRequestID:     updatedPR.PullRequestId,
+			PullRequestName:   updatedPR.PullRequestName,
+			AuthorID:          updatedPR.AuthorID,
+			Status:            updatedPR.Status,
+			CIStatus:          updatedPR.CIStatus,
+			Priority:          updatedPR.Priority,
+			Labels:            updatedPR.Labels,
+			RequiredSkills:    updatedPR.RequiredSkills,
+			AssignedReviewers: reviewers,
+			MergedAt:          formatMergedAt(r, updatedPR.MergedAt),
+			MergedBy:          updatedPR.MergedBy,
+			AutoMerge:         updatedPR.AutoMerge,
+			AssignmentQueued:  updatedPR.AssignmentQueued,
+			UnderReviewed:     updatedPR.UnderReviewed,
+			AuthorTeam:        updatedPR.AuthorTeam,
+			ReviewerPool:      updatedPR.ReviewerPool,
+		},
+	}
+
+	h.resp.JSON(w, r, http.StatusOK, response)
+	log.Info("PR CI status updated successfully")
+}
+
 func (h *PullRequestHandler) ReassignReviewer(w http.ResponseWriter, r *http.Request) {
 	const op = "handler.pullRequest.ReassignReviewer"
 
 	log := h.log.With(slog.String("op", op))
 
+	r, assignmentTrace, err := withAssignmentTrace(r)
+	if err != nil {
+		log.Error("invalid debug flag", sl.Err(err))
+		h.resp.Error(w, r, http.StatusBadRequest, "INVALID_DEBUG", "debug must be true or false")
+		return
+	}
+
 	var req ReassignReviewerRequest
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		log.Error("invalid request body", sl.Err(err))
-		h.writeErrorResponse(w, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+		h.resp.Error(w, r, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
 		return
 	}
 
 	if req.PullRequestID == "" {
 		log.Error("pull_request_id is required")
-		h.writeErrorResponse(w, http.StatusNotFound, "NOT_FOUND", "resource not found")
+		h.resp.Error(w, r, http.StatusNotFound, "NOT_FOUND", "resource not found")
 		return
 	}
 
 	if req.OldReviewerID == "" {
 		log.Error("old_reviewer_id is required")
-		h.writeErrorResponse(w, http.StatusNotFound, "NOT_FOUND", "resource not found")
+		h.resp.Error(w, r, http.StatusNotFound, "NOT_FOUND", "resource not found")
+		return
+	}
+
+	if req.Reason == "" {
+		log.Error("reason is required")
+		h.resp.Error(w, r, http.StatusBadRequest, "REASON_REQUIRED", reassignReasonMessage)
 		return
 	}
 
-	updatedPR, reviewers, newReviewer, err := h.prService.ReassignReviewer(r.Context(), req.PullRequestID, req.OldReviewerID)
+	updatedPR, reviewers, newReviewer, err := h.prService.ReassignReviewer(r.Context(), req.PullRequestID, req.OldReviewerID, req.Reason, req.Note)
 	if err != nil {
 		log.Error("failed to reassign reviewer", sl.Err(err))
 
 		switch {
-		case errors.Is(err, apperrors.ErrPRNotFound):
-			h.writeErrorResponse(w, http.StatusNotFound, "NOT_FOUND", "resource not found")
+		case errors.Is(err, apperrors.ErrReasonRequired):
+			h.resp.Error(w, r, http.StatusBadRequest, "REASON_REQUIRED", reassignReasonMessage)
+		case errors.Is(err, apperrors.ErrInvalidReason):
+			h.resp.Error(w, r, http.StatusBadRequest, "INVALID_REASON", reassignReasonMessage)
+		case errors.Is(err, apperrors.ErrHandoffNoteTooLong):
+			h.resp.Error(w, r, http.StatusBadRequest, "INVALID_NOTE",
+				"note must be at most %d characters", models.MaxHandoffNoteLength)
 		case errors.Is(err, apperrors.ErrPRAlreadyMerged):
-			h.writeErrorResponse(w, http.StatusConflict, "PR_MERGED", "cannot reassign on merged PR")
-		case errors.Is(err, apperrors.ErrReviewerNotAssigned):
-			h.writeErrorResponse(w, http.StatusNotFound, "NOT_FOUND", "resource not found")
+			h.resp.Error(w, r, http.StatusConflict, "PR_MERGED", "cannot reassign on merged PR")
 		case errors.Is(err, apperrors.ErrNoReviewerCandidates):
-			h.writeErrorResponse(w, http.StatusConflict, "NO_CANDIDATE", "no active replacement candidate in team")
+			h.resp.Error(w, r, http.StatusConflict, "NO_CANDIDATE", "no active replacement candidate in team")
 		default:
-			h.writeErrorResponse(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to reassign reviewer")
+			h.resp.Fail(w, r, err, "failed to reassign reviewer")
 		}
 		return
 	}
@@ -243,43 +671,670 @@ func (h *PullRequestHandler) ReassignReviewer(w http.ResponseWriter, r *http.Req
 			PullRequestName:   updatedPR.PullRequestName,
 			AuthorID:          updatedPR.AuthorID,
 			Status:            updatedPR.Status,
+			CIStatus:          updatedPR.CIStatus,
+			Priority:          updatedPR.Priority,
+			Labels:            updatedPR.Labels,
+			RequiredSkills:    updatedPR.RequiredSkills,
 			AssignedReviewers: reviewers,
-			MergedAt:          formatMergedAt(updatedPR.MergedAt),
+			MergedAt:          formatMergedAt(r, updatedPR.MergedAt),
+			MergedBy:          updatedPR.MergedBy,
+			AutoMerge:         updatedPR.AutoMerge,
+			AssignmentQueued:  updatedPR.AssignmentQueued,
+			UnderReviewed:     updatedPR.UnderReviewed,
+			AuthorTeam:        updatedPR.AuthorTeam,
+			ReviewerPool:      updatedPR.ReviewerPool,
 		},
 		ReplacedBy: newReviewer,
+		Trace:      assignmentTrace,
 	}
 
-	h.writeJSON(w, http.StatusOK, response)
+	h.resp.JSON(w, r, http.StatusOK, response)
 	log.Info("reviewer reassigned successfully")
 }
 
-func (h *PullRequestHandler) writeJSON(w http.ResponseWriter, status int, data interface{}) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	if err := json.NewEncoder(w).Encode(data); err != nil {
-		h.log.Error("failed to encode JSON response", sl.Err(err))
+func (h *PullRequestHandler) AssignReviewer(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.pullRequest.AssignReviewer"
+
+	log := h.log.With(slog.String("op", op))
+
+	var req AssignReviewerRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("invalid request body", sl.Err(err))
+		h.resp.Error(w, r, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+		return
+	}
+
+	if req.PullRequestID == "" {
+		log.Error("pull_request_id is required")
+		h.resp.Error(w, r, http.StatusBadRequest, "PR_ID_REQUIRED", "pull_request_id is required")
+		return
+	}
+
+	if req.ReviewerID == "" {
+		log.Error("reviewer_id is required")
+		h.resp.Error(w, r, http.StatusBadRequest, "REVIEWER_REQUIRED", "reviewer_id is required")
+		return
+	}
+
+	actorID, _ := middleware.UserIDFromContext(r.Context())
+
+	updatedPR, reviewers, err := h.prService.AssignReviewer(r.Context(), req.PullRequestID, req.ReviewerID, req.ReplaceReviewerID, actorID)
+	if err != nil {
+		log.Error("failed to assign reviewer", sl.Err(err))
+
+		switch {
+		case errors.Is(err, apperrors.ErrReviewerNotAssigned):
+			h.resp.Error(w, r, http.StatusNotFound, "NOT_ASSIGNED", "reviewer to replace is not assigned to this PR")
+		case errors.Is(err, apperrors.ErrPRAlreadyMerged):
+			h.resp.Error(w, r, http.StatusConflict, "PR_MERGED", "cannot assign on merged PR")
+		default:
+			h.resp.Fail(w, r, err, "failed to assign reviewer")
+		}
+		return
+	}
+
+	response := AssignReviewerResponse{
+		PR: &PullRequestWithReviewers{
+			PullRequestID:     updatedPR.PullRequestId,
+			PullRequestName:   updatedPR.PullRequestName,
+			AuthorID:          updatedPR.AuthorID,
+			Status:            updatedPR.Status,
+			CIStatus:          updatedPR.CIStatus,
+			Priority:          updatedPR.Priority,
+			Labels:            updatedPR.Labels,
+			RequiredSkills:    updatedPR.RequiredSkills,
+			AssignedReviewers: reviewers,
+			MergedAt:          formatMergedAt(r, updatedPR.MergedAt),
+			MergedBy:          updatedPR.MergedBy,
+			AutoMerge:         updatedPR.AutoMerge,
+			AssignmentQueued:  updatedPR.AssignmentQueued,
+			UnderReviewed:     updatedPR.UnderReviewed,
+			AuthorTeam:        updatedPR.AuthorTeam,
+			ReviewerPool:      updatedPR.ReviewerPool,
+		},
+	}
+
+	h.resp.JSON(w, r, http.StatusOK, response)
+	log.Info("reviewer assigned successfully")
+}
+
+func (h *PullRequestHandler) UnassignReviewer(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.pullRequest.UnassignReviewer"
+
+	log := h.log.With(slog.String("op", op))
+
+	var req UnassignReviewerRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("invalid request body", sl.Err(err))
+		h.resp.Error(w, r, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+		return
+	}
+
+	if req.PullRequestID == "" {
+		log.Error("pull_request_id is required")
+		h.resp.Error(w, r, http.StatusBadRequest, "PR_ID_REQUIRED", "pull_request_id is required")
+		return
+	}
+
+	if req.ReviewerID == "" {
+		log.Error("reviewer_id is required")
+		h.resp.Error(w, r, http.StatusBadRequest, "REVIEWER_REQUIRED", "reviewer_id is required")
+		return
+	}
+
+	actorID, _ := middleware.UserIDFromContext(r.Context())
+
+	updatedPR, reviewers, err := h.prService.UnassignReviewer(r.Context(), req.PullRequestID, req.ReviewerID, actorID)
+	if err != nil {
+		log.Error("failed to unassign reviewer", sl.Err(err))
+
+		switch {
+		case errors.Is(err, apperrors.ErrPRAlreadyMerged):
+			h.resp.Error(w, r, http.StatusConflict, "PR_MERGED", "cannot unassign on merged PR")
+		case errors.Is(err, apperrors.ErrBelowMinReviewers):
+			h.resp.Error(w, r, http.StatusConflict, "MIN_REVIEWERS", "PR would have fewer reviewers than the team minimum")
+		default:
+			h.resp.Fail(w, r, err, "failed to unassign reviewer")
+		}
+		return
+	}
+
+	response := UnassignReviewerResponse{
+		PR: &PullRequestWithReviewers{
+			PullRequestID:     updatedPR.PullRequestId,
+			PullRequestName:   updatedPR.PullRequestName,
+			AuthorID:          updatedPR.AuthorID,
+			Status:            updatedPR.Status,
+			CIStatus:          updatedPR.CIStatus,
+			Priority:          updatedPR.Priority,
+			Labels:            updatedPR.Labels,
+			RequiredSkills:    updatedPR.RequiredSkills,
+			AssignedReviewers: reviewers,
+			MergedAt:          formatMergedAt(r, updatedPR.MergedAt),
+			MergedBy:          updatedPR.MergedBy,
+			AutoMerge:         updatedPR.AutoMerge,
+			AssignmentQueued:  updatedPR.AssignmentQueued,
+			UnderReviewed:     updatedPR.UnderReviewed,
+			AuthorTeam:        updatedPR.AuthorTeam,
+			ReviewerPool:      updatedPR.ReviewerPool,
+		},
+	}
+
+	h.resp.JSON(w, r, http.StatusOK, response)
+	log.Info("reviewer unassigned successfully")
+}
+
+func (h *PullRequestHandler) SetReviewerCount(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.pullRequest.SetReviewerCount"
+
+	log := h.log.With(slog.String("op", op))
+
+	var req SetReviewerCountRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("invalid request body", sl.Err(err))
+		h.resp.Error(w, r, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+		return
+	}
+
+	if req.PullRequestID == "" {
+		log.Error("pull_request_id is required")
+		h.resp.Error(w, r, http.StatusBadRequest, "PR_ID_REQUIRED", "pull_request_id is required")
+		return
+	}
+
+	actorID, _ := middleware.UserIDFromContext(r.Context())
+
+	updatedPR, reviewers, added, removed, err := h.prService.SetReviewerCount(r.Context(), req.PullRequestID, req.Reviewers, actorID)
+	if err != nil {
+		log.Error("failed to set reviewer count", sl.Err(err))
+
+		switch {
+		case errors.Is(err, apperrors.ErrInvalidReviewerCount):
+			h.resp.Error(w, r, http.StatusBadRequest, "INVALID_REVIEWER_COUNT", "reviewers must be between 1 and 10")
+		case errors.Is(err, apperrors.ErrPRTeamNotFound):
+			h.resp.Error(w, r, http.StatusNotFound, "TEAM_NOT_FOUND", "author team not found")
+		case errors.Is(err, apperrors.ErrPRAlreadyMerged):
+			h.resp.Error(w, r, http.StatusConflict, "PR_MERGED", "cannot change reviewers on merged PR")
+		case errors.Is(err, apperrors.ErrBelowMinReviewers):
+			h.resp.Error(w, r, http.StatusConflict, "MIN_REVIEWERS", "PR would have fewer reviewers than the team minimum")
+		case errors.Is(err, apperrors.ErrNoReviewerCandidates):
+			h.resp.Error(w, r, http.StatusConflict, "NO_CANDIDATE", "no active candidate for an extra reviewer")
+		case errors.Is(err, apperrors.ErrReviewerNotAssigned):
+			h.resp.Error(w, r, http.StatusConflict, "REVIEWERS_CHANGED", "reviewers changed concurrently, retry")
+		default:
+			h.resp.Fail(w, r, err, "failed to set reviewer count")
+		}
+		return
+	}
+
+	response := SetReviewerCountResponse{
+		PR: &PullRequestWithReviewers{
+			PullRequestID:     updatedPR.PullRequestId,
+			PullRequestName:   updatedPR.PullRequestName,
+			AuthorID:          updatedPR.AuthorID,
+			Status:            updatedPR.Status,
+			CIStatus:          updatedPR.CIStatus,
+			Priority:          updatedPR.Priority,
+			Labels:            updatedPR.Labels,
+			RequiredSkills:    updatedPR.RequiredSkills,
+			AssignedReviewers: reviewers,
+			MergedAt:          formatMergedAt(r, updatedPR.MergedAt),
+			MergedBy:          updatedPR.MergedBy,
+			AutoMerge:         updatedPR.AutoMerge,
+			AssignmentQueued:  updatedPR.AssignmentQueued,
+			UnderReviewed:     updatedPR.UnderReviewed,
+			AuthorTeam:        updatedPR.AuthorTeam,
+			ReviewerPool:      updatedPR.ReviewerPool,
+		},
+		Added:   added,
+		Removed: removed,
+	}
+
+	h.resp.JSON(w, r, http.StatusOK, response)
+	log.Info("reviewer count set successfully")
+}
+
+func (h *PullRequestHandler) DeclineReview(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.pullRequest.DeclineReview"
+
+	log := h.log.With(slog.String("op", op))
+
+	var req DeclineReviewRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("invalid request body", sl.Err(err))
+		h.resp.Error(w, r, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+		return
+	}
+
+	if req.PullRequestID == "" {
+		log.Error("pull_request_id is required")
+		h.resp.Error(w, r, http.StatusBadRequest, "PR_ID_REQUIRED", "pull_request_id is required")
+		return
+	}
+
+	if req.ReviewerID == "" {
+		req.ReviewerID, _ = middleware.UserIDFromContext(r.Context())
+	}
+
+	if req.ReviewerID == "" {
+		log.Error("reviewer_id is required")
+		h.resp.Error(w, r, http.StatusBadRequest, "REVIEWER_REQUIRED", "reviewer_id is required")
+		return
+	}
+
+	updatedPR, reviewers, newReviewer, err := h.prService.DeclineReview(r.Context(), req.PullRequestID, req.ReviewerID)
+	if err != nil {
+		log.Error("failed to decline review", sl.Err(err))
+
+		switch {
+		case errors.Is(err, apperrors.ErrReviewerNotAssigned):
+			h.resp.Error(w, r, http.StatusNotFound, "NOT_ASSIGNED", "reviewer is not assigned to this PR")
+		case errors.Is(err, apperrors.ErrPRAlreadyMerged):
+			h.resp.Error(w, r, http.StatusConflict, "PR_MERGED", "cannot decline review on merged PR")
+		default:
+			h.resp.Fail(w, r, err, "failed to decline review")
+		}
+		return
+	}
+
+	response := DeclineReviewResponse{
+		PR: &PullRequestWithReviewers{
+			PullRequestID:     updatedPR.PullRequestId,
+			PullRequestName:   updatedPR.PullRequestName,
+			AuthorID:          updatedPR.AuthorID,
+			Status:            updatedPR.Status,
+			CIStatus:          updatedPR.CIStatus,
+			Priority:          updatedPR.Priority,
+			Labels:            updatedPR.Labels,
+			RequiredSkills:    updatedPR.RequiredSkills,
+			AssignedReviewers: reviewers,
+			MergedAt:          formatMergedAt(r, updatedPR.MergedAt),
+			MergedBy:          updatedPR.MergedBy,
+			AutoMerge:         updatedPR.AutoMerge,
+			AssignmentQueued:  updatedPR.AssignmentQueued,
+			UnderReviewed:     updatedPR.UnderReviewed,
+			AuthorTeam:        updatedPR.AuthorTeam,
+			ReviewerPool:      updatedPR.ReviewerPool,
+		},
+		ReplacedBy: newReviewer,
 	}
+
+	h.resp.JSON(w, r, http.StatusOK, response)
+	log.Info("review declined successfully")
 }
 
-func (h *PullRequestHandler) writeErrorResponse(w http.ResponseWriter, status int, code, message string) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
+func (h *PullRequestHandler) DelegateReview(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.pullRequest.DelegateReview"
+
+	log := h.log.With(slog.String("op", op))
+
+	var req DelegateReviewRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("invalid request body", sl.Err(err))
+		h.resp.Error(w, r, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+		return
+	}
+
+	if req.PullRequestID == "" {
+		log.Error("pull_request_id is required")
+		h.resp.Error(w, r, http.StatusBadRequest, "PR_ID_REQUIRED", "pull_request_id is required")
+		return
+	}
+
+	if req.ReviewerID == "" {
+		req.ReviewerID, _ = middleware.UserIDFromContext(r.Context())
+	}
+
+	if req.ReviewerID == "" {
+		log.Error("reviewer_id is required")
+		h.resp.Error(w, r, http.StatusBadRequest, "REVIEWER_REQUIRED", "reviewer_id is required")
+		return
+	}
+
+	if req.DelegateID == "" {
+		log.Error("delegate_id is required")
+		h.resp.Error(w, r, http.StatusBadRequest, "DELEGATE_REQUIRED", "delegate_id is required")
+		return
+	}
+
+	updatedPR, reviewers, err := h.prService.DelegateReview(r.Context(), req.PullRequestID, req.ReviewerID, req.DelegateID)
+	if err != nil {
+		log.Error("failed to delegate review", sl.Err(err))
+
+		switch {
+		case errors.Is(err, apperrors.ErrReviewerNotAssigned):
+			h.resp.Error(w, r, http.StatusNotFound, "NOT_ASSIGNED", "reviewer is not assigned to this PR")
+		case errors.Is(err, apperrors.ErrPRAlreadyMerged):
+			h.resp.Error(w, r, http.StatusConflict, "PR_MERGED", "cannot delegate on merged PR")
+		case errors.Is(err, apperrors.ErrDelegateNotTeammate):
+			h.resp.Error(w, r, http.StatusConflict, "NOT_IN_TEAM", "delegate is not a member of the reviewer's team")
+		case errors.Is(err, apperrors.ErrDelegateAtCapacity):
+			h.resp.Error(w, r, http.StatusConflict, "AT_CAPACITY", "delegate has reached the open review limit")
+		default:
+			h.resp.Fail(w, r, err, "failed to delegate review")
+		}
+		return
+	}
 
-	errorResp := PRErrorResponse{
-		Error: PRErrorDetail{
-			Code:    code,
-			Message: message,
+	response := DelegateReviewResponse{
+		PR: &PullRequestWithReviewers{
+			PullRequestID:     updatedPR.PullRequestId,
+			PullRequestName:   updatedPR.PullRequestName,
+			AuthorID:          updatedPR.AuthorID,
+			Status:            updatedPR.Status,
+			CIStatus:          updatedPR.CIStatus,
+			Priority:          updatedPR.Priority,
+			Labels:            updatedPR.Labels,
+			RequiredSkills:    updatedPR.RequiredSkills,
+			AssignedReviewers: reviewers,
+			MergedAt:          formatMergedAt(r, updatedPR.MergedAt),
+			MergedBy:          updatedPR.MergedBy,
+			AutoMerge:         updatedPR.AutoMerge,
+			AssignmentQueued:  updatedPR.AssignmentQueued,
+			UnderReviewed:     updatedPR.UnderReviewed,
+			AuthorTeam:        updatedPR.AuthorTeam,
+			ReviewerPool:      updatedPR.ReviewerPool,
 		},
+		DelegatedTo: req.DelegateID,
+	}
+
+	h.resp.JSON(w, r, http.StatusOK, response)
+	log.Info("review delegated successfully")
+}
+
+func (h *PullRequestHandler) StartReview(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.pullRequest.StartReview"
+
+	log := h.log.With(slog.String("op", op))
+
+	var req StartReviewRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("invalid request body", sl.Err(err))
+		h.resp.Error(w, r, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+		return
+	}
+
+	if req.PullRequestID == "" {
+		log.Error("pull_request_id is required")
+		h.resp.Error(w, r, http.StatusBadRequest, "PR_ID_REQUIRED", "pull_request_id is required")
+		return
 	}
 
-	if err := json.NewEncoder(w).Encode(errorResp); err != nil {
-		h.log.Error("failed to encode error response", sl.Err(err))
+	if req.ReviewerID == "" {
+		req.ReviewerID, _ = middleware.UserIDFromContext(r.Context())
 	}
+
+	if req.ReviewerID == "" {
+		log.Error("reviewer_id is required")
+		h.resp.Error(w, r, http.StatusBadRequest, "REVIEWER_REQUIRED", "reviewer_id is required")
+		return
+	}
+
+	startedAt, err := h.prService.StartReview(r.Context(), req.PullRequestID, req.ReviewerID)
+	if err != nil {
+		log.Error("failed to start review", sl.Err(err))
+
+		switch {
+		case errors.Is(err, apperrors.ErrPRAlreadyMerged):
+			h.resp.Error(w, r, http.StatusConflict, "PR_MERGED", "cannot start review on merged PR")
+		default:
+			h.resp.Fail(w, r, err, "failed to start review")
+		}
+		return
+	}
+
+	response := StartReviewResponse{
+		PullRequestID:   req.PullRequestID,
+		ReviewerID:      req.ReviewerID,
+		ReviewState:     models.ReviewStateInProgress,
+		ReviewStartedAt: startedAt,
+	}
+
+	h.resp.JSON(w, r, http.StatusOK, response)
+	log.Info("review started successfully")
+}
+
+func (h *PullRequestHandler) CompleteReview(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.pullRequest.CompleteReview"
+
+	log := h.log.With(slog.String("op", op))
+
+	var req CompleteReviewRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("invalid request body", sl.Err(err))
+		h.resp.Error(w, r, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+		return
+	}
+
+	if req.PullRequestID == "" {
+		log.Error("pull_request_id is required")
+		h.resp.Error(w, r, http.StatusBadRequest, "PR_ID_REQUIRED", "pull_request_id is required")
+		return
+	}
+
+	if req.ReviewerID == "" {
+		req.ReviewerID, _ = middleware.UserIDFromContext(r.Context())
+	}
+
+	if req.ReviewerID == "" {
+		log.Error("reviewer_id is required")
+		h.resp.Error(w, r, http.StatusBadRequest, "REVIEWER_REQUIRED", "reviewer_id is required")
+		return
+	}
+
+	completedAt, err := h.prService.CompleteReview(r.Context(), req.PullRequestID, req.ReviewerID)
+	if err != nil {
+		log.Error("failed to complete review", sl.Err(err))
+
+		switch {
+		case errors.Is(err, apperrors.ErrPRAlreadyMerged):
+			h.resp.Error(w, r, http.StatusConflict, "PR_MERGED", "cannot complete review on merged PR")
+		default:
+			h.resp.Fail(w, r, err, "failed to complete review")
+		}
+		return
+	}
+
+	response := CompleteReviewResponse{
+		PullRequestID:     req.PullRequestID,
+		ReviewerID:        req.ReviewerID,
+		ReviewState:       models.ReviewStateCompleted,
+		ReviewCompletedAt: completedAt,
+	}
+
+	h.resp.JSON(w, r, http.StatusOK, response)
+	log.Info("review completed successfully")
+}
+
+func (h *PullRequestHandler) ApprovePR(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.pullRequest.ApprovePR"
+
+	log := h.log.With(slog.String("op", op))
+
+	var req ApprovePRRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("invalid request body", sl.Err(err))
+		h.resp.Error(w, r, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+		return
+	}
+
+	if req.PullRequestID == "" {
+		log.Error("pull_request_id is required")
+		h.resp.Error(w, r, http.StatusBadRequest, "PR_ID_REQUIRED", "pull_request_id is required")
+		return
+	}
+
+	if req.ReviewerID == "" {
+		req.ReviewerID, _ = middleware.UserIDFromContext(r.Context())
+	}
+
+	if req.ReviewerID == "" {
+		log.Error("reviewer_id is required")
+		h.resp.Error(w, r, http.StatusBadRequest, "REVIEWER_REQUIRED", "reviewer_id is required")
+		return
+	}
+
+	approvedAt, err := h.prService.ApprovePR(r.Context(), req.PullRequestID, req.ReviewerID)
+	if err != nil {
+		log.Error("failed to approve PR", sl.Err(err))
+
+		switch {
+		case errors.Is(err, apperrors.ErrPRAlreadyMerged):
+			h.resp.Error(w, r, http.StatusConflict, "PR_MERGED", "cannot approve merged PR")
+		default:
+			h.resp.Fail(w, r, err, "failed to approve PR")
+		}
+		return
+	}
+
+	response := ApprovePRResponse{
+		PullRequestID: req.PullRequestID,
+		ReviewerID:    req.ReviewerID,
+		ApprovedAt:    approvedAt,
+	}
+
+	h.resp.JSON(w, r, http.StatusOK, response)
+	log.Info("PR approved successfully")
+}
+
+func (h *PullRequestHandler) ExportPRs(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.pullRequest.ExportPRs"
+
+	log := h.log.With(slog.String("op", op))
+
+	query := r.URL.Query()
+	search := models.PRSearch{
+		Name:       query.Get("name"),
+		ReviewerID: query.Get("reviewer_id"),
+	}
+	for _, param := range []struct {
+		name   string
+		target **time.Time
+	}{
+		{"created_from", &search.CreatedFrom},
+		{"created_to", &search.CreatedTo},
+		{"merged_from", &search.MergedFrom},
+		{"merged_to", &search.MergedTo},
+	} {
+		raw := query.Get(param.name)
+		if raw == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			log.Error("invalid search time", slog.String("param", param.name), sl.Err(err))
+			h.resp.Error(w, r, http.StatusBadRequest, "INVALID_SEARCH",
+				"created_from, created_to, merged_from and merged_to must be RFC3339 timestamps")
+			return
+		}
+		*param.target = &parsed
+	}
+
+	flusher, _ := w.(http.Flusher)
+	encoder := json.NewEncoder(w)
+	headerWritten := false
+
+	exported, err := h.prService.ExportPRs(r.Context(), search, func(page []models.PullRequestExport) error {
+		if !headerWritten {
+			w.Header().Set("Content-Type", "application/x-ndjson")
+			w.WriteHeader(http.StatusOK)
+			headerWritten = true
+		}
+
+		for i := range page {
+			if err := encoder.Encode(localtime.In(&page[i], httpio.Location(r))); err != nil {
+				return err
+			}
+		}
+
+		if flusher != nil {
+			flusher.Flush()
+		}
+		return nil
+	})
+	if err != nil {
+		log.Error("failed to export PRs", sl.Err(err), slog.Int("exported", exported))
+		if !headerWritten {
+			if errors.Is(err, apperrors.ErrInvalidPRSearch) {
+				h.resp.Error(w, r, http.StatusBadRequest, "INVALID_SEARCH", "search ranges must start before they end")
+				return
+			}
+			h.resp.Fail(w, r, err, "failed to export PRs")
+		}
+		return
+	}
+
+	if !headerWritten {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.WriteHeader(http.StatusOK)
+	}
+
+	log.Info("PRs exported successfully", slog.Int("exported", exported))
+}
+
+func (h *PullRequestHandler) GetCandidates(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.pullRequest.GetCandidates"
+
+	log := h.log.With(slog.String("op", op))
+
+	prID := r.URL.Query().Get("pull_request_id")
+	if prID == "" {
+		log.Error("pull_request_id is required")
+		h.resp.Error(w, r, http.StatusBadRequest, "PR_ID_REQUIRED", "pull_request_id query parameter is required")
+		return
+	}
+
+	candidates, err := h.prService.GetCandidates(r.Context(), prID)
+	if err != nil {
+		log.Error("failed to get candidates", sl.Err(err))
+		h.resp.Fail(w, r, err, "failed to get candidates")
+		return
+	}
+
+	response := GetCandidatesResponse{
+		PullRequestID: prID,
+		Candidates:    candidates,
+	}
+
+	h.resp.JSON(w, r, http.StatusOK, response)
+	log.Info("candidates returned successfully", slog.Int("candidate_count", len(candidates)))
+}
+
+func (h *PullRequestHandler) GetPendingAssignments(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.pullRequest.GetPendingAssignments"
+
+	log := h.log.With(slog.String("op", op))
+
+	teamName := r.URL.Query().Get("team_name")
+
+	pending, err := h.prService.GetPendingAssignments(r.Context(), teamName)
+	if err != nil {
+		log.Error("failed to get pending assignments", sl.Err(err))
+		h.resp.Fail(w, r, err, "failed to get pending assignments")
+		return
+	}
+
+	h.resp.JSON(w, r, http.StatusOK, PendingAssignmentsResponse{
+		TeamName:     teamName,
+		PullRequests: pending,
+	})
+	log.Info("pending assignments returned successfully", slog.Int("count", len(pending)))
 }
 
-func formatMergedAt(mergedAt sql.NullTime) string {
-	if mergedAt.Valid {
-		return mergedAt.Time.Format(time.RFC3339)
+func formatMergedAt(r *http.Request, mergedAt sql.NullTime) string {
+	if !mergedAt.Valid {
+		return ""
+	}
+	if loc := httpio.Location(r); loc != nil {
+		return mergedAt.Time.In(loc).Format(time.RFC3339)
 	}
-	return ""
+	return mergedAt.Time.Format(time.RFC3339)
 }
@@ -0,0 +1,303 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"pull-request-assigner/internal/apperrors"
+	"testing"
+)
+
+func TestPullRequestHandlerErrors(t *testing.T) {
+	mock := &pullRequestManagerMock{}
+	h := NewPullRequestHandler(mock, discardLogger())
+
+	const (
+		createBody   = `{"pull_request_id":"pr-1","pull_request_name":"Fix","author_id":"u1"}`
+		prBody       = `{"pull_request_id":"pr-1"}`
+		reviewerBody = `{"pull_request_id":"pr-1","reviewer_id":"u2"}`
+		reassignBody = `{"pull_request_id":"pr-1","old_reviewer_id":"u2","reason":"vacation"}`
+	)
+
+	cases := []errorCase{
+		{name: "create invalid debug", serve: h.CreatePR, target: "/pullRequest/create?debug=maybe", body: createBody,
+			status: http.StatusBadRequest, code: "INVALID_DEBUG"},
+		{name: "create invalid body", serve: h.CreatePR, target: "/pullRequest/create", body: "{",
+			status: http.StatusBadRequest, code: "INVALID_REQUEST"},
+		{name: "create missing id", serve: h.CreatePR, target: "/pullRequest/create", body: `{"pull_request_name":"Fix","author_id":"u1"}`,
+			status: http.StatusBadRequest, code: "PR_ID_REQUIRED"},
+		{name: "create missing name", serve: h.CreatePR, target: "/pullRequest/create", body: `{"pull_request_id":"pr-1","author_id":"u1"}`,
+			status: http.StatusBadRequest, code: "PR_NAME_REQUIRED"},
+		{name: "create missing author", serve: h.CreatePR, target: "/pullRequest/create", body: `{"pull_request_id":"pr-1","pull_request_name":"Fix"}`,
+			status: http.StatusBadRequest, code: "AUTHOR_REQUIRED"},
+		{name: "create exists", serve: h.CreatePR, target: "/pullRequest/create", body: createBody, err: apperrors.ErrPRExists,
+			status: http.StatusConflict, code: "PR_EXISTS", called: "CreatePRWithReviewers"},
+		{name: "create author not found", serve: h.CreatePR, target: "/pullRequest/create", body: createBody, err: apperrors.ErrPRAuthorNotFound,
+			status: http.StatusNotFound, code: "NOT_FOUND", called: "CreatePRWithReviewers"},
+		{name: "create team not found", serve: h.CreatePR, target: "/pullRequest/create", body: createBody, err: apperrors.ErrPRTeamNotFound,
+			status: http.StatusNotFound, code: "TEAM_NOT_FOUND", called: "CreatePRWithReviewers"},
+		{name: "create author not in team", serve: h.CreatePR, target: "/pullRequest/create", body: createBody, err: apperrors.ErrAuthorNotInTeam,
+			status: http.StatusBadRequest, code: "AUTHOR_NOT_IN_TEAM", called: "CreatePRWithReviewers"},
+		{name: "create no reviewers", serve: h.CreatePR, target: "/pullRequest/create", body: createBody, err: apperrors.ErrNoReviewerCandidates,
+			status: http.StatusNotFound, code: "NO_REVIEWERS", called: "CreatePRWithReviewers"},
+		{name: "create invalid ci status", serve: h.CreatePR, target: "/pullRequest/create", body: createBody, err: apperrors.ErrInvalidCIStatus,
+			status: http.StatusBadRequest, code: "INVALID_CI_STATUS", called: "CreatePRWithReviewers"},
+		{name: "create invalid priority", serve: h.CreatePR, target: "/pullRequest/create", body: createBody, err: apperrors.ErrInvalidPriority,
+			status: http.StatusBadRequest, code: "INVALID_PRIORITY", called: "CreatePRWithReviewers"},
+		{name: "create invalid auto merge", serve: h.CreatePR, target: "/pullRequest/create", body: createBody, err: apperrors.ErrInvalidAutoMerge,
+			status: http.StatusBadRequest, code: "INVALID_AUTO_MERGE", called: "CreatePRWithReviewers"},
+		{name: "create invalid reviewer teams", serve: h.CreatePR, target: "/pullRequest/create", body: createBody, err: apperrors.ErrInvalidReviewerTeams,
+			status: http.StatusBadRequest, code: "INVALID_REVIEWER_TEAMS", called: "CreatePRWithReviewers"},
+		{name: "create reviewer team not found", serve: h.CreatePR, target: "/pullRequest/create", body: createBody, err: apperrors.ErrReviewerTeamNotFound,
+			status: http.StatusNotFound, code: "TEAM_NOT_FOUND", called: "CreatePRWithReviewers"},
+		{name: "create reviewer pool not found", serve: h.CreatePR, target: "/pullRequest/create", body: createBody, err: apperrors.ErrPoolNotFound,
+			status: http.StatusNotFound, code: "POOL_NOT_FOUND", called: "CreatePRWithReviewers"},
+		{name: "create no security reviewers", serve: h.CreatePR, target: "/pullRequest/create", body: createBody, err: apperrors.ErrNoSecurityReviewer,
+			status: http.StatusNotFound, code: "NO_SECURITY_REVIEWERS", called: "CreatePRWithReviewers"},
+		{name: "create no certified reviewers", serve: h.CreatePR, target: "/pullRequest/create", body: createBody, err: apperrors.ErrNoCertifiedReviewer,
+			status: http.StatusNotFound, code: "NO_CERTIFIED_REVIEWERS", called: "CreatePRWithReviewers"},
+		{name: "create internal", serve: h.CreatePR, target: "/pullRequest/create", body: createBody, err: errUnexpected,
+			status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "CreatePRWithReviewers"},
+
+		{name: "merge invalid body", serve: h.MergePR, target: "/pullRequest/merge", body: "{",
+			status: http.StatusBadRequest, code: "INVALID_REQUEST"},
+		{name: "merge missing id", serve: h.MergePR, target: "/pullRequest/merge", body: `{}`,
+			status: http.StatusBadRequest, code: "PR_ID_REQUIRED"},
+		{name: "merge not found", serve: h.MergePR, target: "/pullRequest/merge", body: prBody, err: apperrors.ErrPRNotFound,
+			status: http.StatusNotFound, code: "NOT_FOUND", called: "MergePR"},
+		{name: "merge invalid transition", serve: h.MergePR, target: "/pullRequest/merge", body: prBody, err: apperrors.ErrInvalidPRTransition,
+			status: http.StatusConflict, code: "INVALID_TRANSITION", called: "MergePR"},
+		{name: "merge security approval", serve: h.MergePR, target: "/pullRequest/merge", body: prBody, err: apperrors.ErrSecurityApprovalRequired,
+			status: http.StatusConflict, code: "SECURITY_APPROVAL_REQUIRED", called: "MergePR"},
+		{name: "merge already merged", serve: h.MergePR, target: "/pullRequest/merge", body: prBody, err: apperrors.ErrPRAlreadyMerged,
+			status: http.StatusConflict, code: "PR_MERGED", called: "MergePR"},
+		{name: "merge invalid merged_by", serve: h.MergePR, target: "/pullRequest/merge", body: prBody, err: apperrors.ErrInvalidUserID,
+			status: http.StatusBadRequest, code: "INVALID_USER_ID", called: "MergePR"},
+		{name: "merge merged_by not found", serve: h.MergePR, target: "/pullRequest/merge", body: prBody, err: apperrors.ErrUserNotFound,
+			status: http.StatusNotFound, code: "NOT_FOUND", called: "MergePR"},
+		{name: "merge window closed", serve: h.MergePR, target: "/pullRequest/merge", body: prBody, err: apperrors.ErrMergeWindowClosed,
+			status: http.StatusConflict, code: "MERGE_WINDOW_CLOSED", called: "MergePR"},
+		{name: "merge override denied", serve: h.MergePR, target: "/pullRequest/merge", body: `{"pull_request_id":"pr-1","override_merge_window":true}`,
+			err: apperrors.ErrMergeOverrideDenied, status: http.StatusForbidden, code: "FORBIDDEN", called: "MergePR"},
+		{name: "merge internal", serve: h.MergePR, target: "/pullRequest/merge", body: prBody, err: errUnexpected,
+			status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "MergePR"},
+
+		{name: "statuses internal", serve: h.ListStatuses, method: http.MethodGet, target: "/pullRequest/statuses", err: errUnexpected,
+			status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "ListStatuses"},
+
+		{name: "set status invalid body", serve: h.SetStatus, target: "/pullRequest/setStatus", body: "{",
+			status: http.StatusBadRequest, code: "INVALID_REQUEST"},
+		{name: "set status missing id", serve: h.SetStatus, target: "/pullRequest/setStatus", body: `{"status":"OPEN"}`,
+			status: http.StatusBadRequest, code: "PR_ID_REQUIRED"},
+		{name: "set status missing status", serve: h.SetStatus, target: "/pullRequest/setStatus", body: prBody,
+			status: http.StatusBadRequest, code: "STATUS_REQUIRED"},
+		{name: "set status not found", serve: h.SetStatus, target: "/pullRequest/setStatus", body: `{"pull_request_id":"pr-1","status":"OPEN"}`,
+			err: apperrors.ErrPRNotFound, status: http.StatusNotFound, code: "NOT_FOUND", called: "SetStatus"},
+		{name: "set status unknown", serve: h.SetStatus, target: "/pullRequest/setStatus", body: `{"pull_request_id":"pr-1","status":"OPEN"}`,
+			err: apperrors.ErrUnknownPRStatus, status: http.StatusBadRequest, code: "UNKNOWN_STATUS", called: "SetStatus"},
+		{name: "set status invalid transition", serve: h.SetStatus, target: "/pullRequest/setStatus", body: `{"pull_request_id":"pr-1","status":"OPEN"}`,
+			err: apperrors.ErrInvalidPRTransition, status: http.StatusConflict, code: "INVALID_TRANSITION", called: "SetStatus"},
+		{name: "set status security approval", serve: h.SetStatus, target: "/pullRequest/setStatus", body: `{"pull_request_id":"pr-1","status":"OPEN"}`,
+			err: apperrors.ErrSecurityApprovalRequired, status: http.StatusConflict, code: "SECURITY_APPROVAL_REQUIRED", called: "SetStatus"},
+		{name: "set status internal", serve: h.SetStatus, target: "/pullRequest/setStatus", body: `{"pull_request_id":"pr-1","status":"OPEN"}`,
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "SetStatus"},
+
+		{name: "ci status invalid body", serve: h.UpdateCIStatus, target: "/pullRequest/ciStatus", body: "{",
+			status: http.StatusBadRequest, code: "INVALID_REQUEST"},
+		{name: "ci status missing id", serve: h.UpdateCIStatus, target: "/pullRequest/ciStatus", body: `{}`,
+			status: http.StatusBadRequest, code: "PR_ID_REQUIRED"},
+		{name: "ci status not found", serve: h.UpdateCIStatus, target: "/pullRequest/ciStatus", body: prBody, err: apperrors.ErrPRNotFound,
+			status: http.StatusNotFound, code: "NOT_FOUND", called: "UpdateCIStatus"},
+		{name: "ci status invalid", serve: h.UpdateCIStatus, target: "/pullRequest/ciStatus", body: prBody, err: apperrors.ErrInvalidCIStatus,
+			status: http.StatusBadRequest, code: "INVALID_CI_STATUS", called: "UpdateCIStatus"},
+		{name: "ci status merged", serve: h.UpdateCIStatus, target: "/pullRequest/ciStatus", body: prBody, err: apperrors.ErrPRAlreadyMerged,
+			status: http.StatusConflict, code: "PR_MERGED", called: "UpdateCIStatus"},
+		{name: "ci status internal", serve: h.UpdateCIStatus, target: "/pullRequest/ciStatus", body: prBody, err: errUnexpected,
+			status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "UpdateCIStatus"},
+
+		{name: "reassign invalid debug", serve: h.ReassignReviewer, target: "/pullRequest/reassign?debug=maybe",
+			body: reassignBody, status: http.StatusBadRequest, code: "INVALID_DEBUG"},
+		{name: "reassign invalid body", serve: h.ReassignReviewer, target: "/pullRequest/reassign", body: "{",
+			status: http.StatusBadRequest, code: "INVALID_REQUEST"},
+		{name: "reassign missing id", serve: h.ReassignReviewer, target: "/pullRequest/reassign", body: `{"old_reviewer_id":"u2"}`,
+			status: http.StatusNotFound, code: "NOT_FOUND"},
+		{name: "reassign missing reviewer", serve: h.ReassignReviewer, target: "/pullRequest/reassign", body: prBody,
+			status: http.StatusNotFound, code: "NOT_FOUND"},
+		{name: "reassign missing reason", serve: h.ReassignReviewer, target: "/pullRequest/reassign", body: `{"pull_request_id":"pr-1","old_reviewer_id":"u2"}`,
+			status: http.StatusBadRequest, code: "REASON_REQUIRED"},
+		{name: "reassign invalid reason", serve: h.ReassignReviewer, target: "/pullRequest/reassign", body: reassignBody,
+			err: apperrors.ErrInvalidReason, status: http.StatusBadRequest, code: "INVALID_REASON", called: "ReassignReviewer"},
+		{name: "reassign note too long", serve: h.ReassignReviewer, target: "/pullRequest/reassign", body: reassignBody,
+			err: apperrors.ErrHandoffNoteTooLong, status: http.StatusBadRequest, code: "INVALID_NOTE", called: "ReassignReviewer"},
+		{name: "reassign not found", serve: h.ReassignReviewer, target: "/pullRequest/reassign", body: reassignBody,
+			err: apperrors.ErrPRNotFound, status: http.StatusNotFound, code: "NOT_FOUND", called: "ReassignReviewer"},
+		{name: "reassign not assigned", serve: h.ReassignReviewer, target: "/pullRequest/reassign", body: reassignBody,
+			err: apperrors.ErrReviewerNotAssigned, status: http.StatusNotFound, code: "NOT_FOUND", called: "ReassignReviewer"},
+		{name: "reassign merged", serve: h.ReassignReviewer, target: "/pullRequest/reassign", body: reassignBody,
+			err: apperrors.ErrPRAlreadyMerged, status: http.StatusConflict, code: "PR_MERGED", called: "ReassignReviewer"},
+		{name: "reassign no candidate", serve: h.ReassignReviewer, target: "/pullRequest/reassign", body: reassignBody,
+			err: apperrors.ErrNoReviewerCandidates, status: http.StatusConflict, code: "NO_CANDIDATE", called: "ReassignReviewer"},
+		{name: "reassign security reviewer", serve: h.ReassignReviewer, target: "/pullRequest/reassign", body: reassignBody,
+			err: apperrors.ErrSecurityReviewerRequired, status: http.StatusConflict, code: "SECURITY_REVIEWER_REQUIRED", called: "ReassignReviewer"},
+		{name: "reassign certified reviewer", serve: h.ReassignReviewer, target: "/pullRequest/reassign", body: reassignBody,
+			err: apperrors.ErrCertifiedReviewerRequired, status: http.StatusConflict, code: "CERTIFIED_REVIEWER_REQUIRED", called: "ReassignReviewer"},
+		{name: "reassign internal", serve: h.ReassignReviewer, target: "/pullRequest/reassign", body: reassignBody,
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "ReassignReviewer"},
+
+		{name: "assign invalid body", serve: h.AssignReviewer, target: "/pullRequest/assign", body: "{",
+			status: http.StatusBadRequest, code: "INVALID_REQUEST"},
+		{name: "assign missing id", serve: h.AssignReviewer, target: "/pullRequest/assign", body: `{"reviewer_id":"u2"}`,
+			status: http.StatusBadRequest, code: "PR_ID_REQUIRED"},
+		{name: "assign missing reviewer", serve: h.AssignReviewer, target: "/pullRequest/assign", body: prBody,
+			status: http.StatusBadRequest, code: "REVIEWER_REQUIRED"},
+		{name: "assign not found", serve: h.AssignReviewer, target: "/pullRequest/assign", body: reviewerBody, err: apperrors.ErrUserNotFound,
+			status: http.StatusNotFound, code: "NOT_FOUND", called: "AssignReviewer"},
+		{name: "assign replaced not assigned", serve: h.AssignReviewer, target: "/pullRequest/assign", body: reviewerBody, err: apperrors.ErrReviewerNotAssigned,
+			status: http.StatusNotFound, code: "NOT_ASSIGNED", called: "AssignReviewer"},
+		{name: "assign merged", serve: h.AssignReviewer, target: "/pullRequest/assign", body: reviewerBody, err: apperrors.ErrPRAlreadyMerged,
+			status: http.StatusConflict, code: "PR_MERGED", called: "AssignReviewer"},
+		{name: "assign author", serve: h.AssignReviewer, target: "/pullRequest/assign", body: reviewerBody, err: apperrors.ErrReviewerIsAuthor,
+			status: http.StatusConflict, code: "REVIEWER_IS_AUTHOR", called: "AssignReviewer"},
+		{name: "assign not in team", serve: h.AssignReviewer, target: "/pullRequest/assign", body: reviewerBody, err: apperrors.ErrReviewerNotInTeam,
+			status: http.StatusConflict, code: "NOT_IN_TEAM", called: "AssignReviewer"},
+		{name: "assign inactive", serve: h.AssignReviewer, target: "/pullRequest/assign", body: reviewerBody, err: apperrors.ErrReviewerInactive,
+			status: http.StatusConflict, code: "REVIEWER_INACTIVE", called: "AssignReviewer"},
+		{name: "assign author only", serve: h.AssignReviewer, target: "/pullRequest/assign", body: reviewerBody, err: apperrors.ErrReviewerAuthorOnly,
+			status: http.StatusConflict, code: "REVIEWER_AUTHOR_ONLY", called: "AssignReviewer"},
+		{name: "assign already assigned", serve: h.AssignReviewer, target: "/pullRequest/assign", body: reviewerBody, err: apperrors.ErrReviewerAssigned,
+			status: http.StatusConflict, code: "ALREADY_ASSIGNED", called: "AssignReviewer"},
+		{name: "assign internal", serve: h.AssignReviewer, target: "/pullRequest/assign", body: reviewerBody, err: errUnexpected,
+			status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "AssignReviewer"},
+
+		{name: "unassign invalid body", serve: h.UnassignReviewer, target: "/pullRequest/unassign", body: "{",
+			status: http.StatusBadRequest, code: "INVALID_REQUEST"},
+		{name: "unassign missing id", serve: h.UnassignReviewer, target: "/pullRequest/unassign", body: `{"reviewer_id":"u2"}`,
+			status: http.StatusBadRequest, code: "PR_ID_REQUIRED"},
+		{name: "unassign missing reviewer", serve: h.UnassignReviewer, target: "/pullRequest/unassign", body: prBody,
+			status: http.StatusBadRequest, code: "REVIEWER_REQUIRED"},
+		{name: "unassign not assigned", serve: h.UnassignReviewer, target: "/pullRequest/unassign", body: reviewerBody, err: apperrors.ErrReviewerNotAssigned,
+			status: http.StatusNotFound, code: "NOT_FOUND", called: "UnassignReviewer"},
+		{name: "unassign merged", serve: h.UnassignReviewer, target: "/pullRequest/unassign", body: reviewerBody, err: apperrors.ErrPRAlreadyMerged,
+			status: http.StatusConflict, code: "PR_MERGED", called: "UnassignReviewer"},
+		{name: "unassign below minimum", serve: h.UnassignReviewer, target: "/pullRequest/unassign", body: reviewerBody, err: apperrors.ErrBelowMinReviewers,
+			status: http.StatusConflict, code: "MIN_REVIEWERS", called: "UnassignReviewer"},
+		{name: "unassign internal", serve: h.UnassignReviewer, target: "/pullRequest/unassign", body: reviewerBody, err: errUnexpected,
+			status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "UnassignReviewer"},
+
+		{name: "set reviewer count invalid body", serve: h.SetReviewerCount, target: "/pullRequest/setReviewerCount", body: "{",
+			status: http.StatusBadRequest, code: "INVALID_REQUEST"},
+		{name: "set reviewer count missing id", serve: h.SetReviewerCount, target: "/pullRequest/setReviewerCount", body: `{"reviewers":3}`,
+			status: http.StatusBadRequest, code: "PR_ID_REQUIRED"},
+		{name: "set reviewer count invalid", serve: h.SetReviewerCount, target: "/pullRequest/setReviewerCount", body: prBody, err: apperrors.ErrInvalidReviewerCount,
+			status: http.StatusBadRequest, code: "INVALID_REVIEWER_COUNT", called: "SetReviewerCount"},
+		{name: "set reviewer count merged", serve: h.SetReviewerCount, target: "/pullRequest/setReviewerCount", body: prBody, err: apperrors.ErrPRAlreadyMerged,
+			status: http.StatusConflict, code: "PR_MERGED", called: "SetReviewerCount"},
+		{name: "set reviewer count below minimum", serve: h.SetReviewerCount, target: "/pullRequest/setReviewerCount", body: prBody, err: apperrors.ErrBelowMinReviewers,
+			status: http.StatusConflict, code: "MIN_REVIEWERS", called: "SetReviewerCount"},
+		{name: "set reviewer count no candidate", serve: h.SetReviewerCount, target: "/pullRequest/setReviewerCount", body: prBody, err: apperrors.ErrNoReviewerCandidates,
+			status: http.StatusConflict, code: "NO_CANDIDATE", called: "SetReviewerCount"},
+		{name: "set reviewer count certified", serve: h.SetReviewerCount, target: "/pullRequest/setReviewerCount", body: prBody, err: apperrors.ErrCertifiedReviewerRequired,
+			status: http.StatusConflict, code: "CERTIFIED_REVIEWER_REQUIRED", called: "SetReviewerCount"},
+		{name: "set reviewer count internal", serve: h.SetReviewerCount, target: "/pullRequest/setReviewerCount", body: prBody, err: errUnexpected,
+			status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "SetReviewerCount"},
+
+		{name: "delegate invalid body", serve: h.DelegateReview, target: "/pullRequest/delegate", body: "{",
+			status: http.StatusBadRequest, code: "INVALID_REQUEST"},
+		{name: "delegate missing id", serve: h.DelegateReview, target: "/pullRequest/delegate", body: `{"reviewer_id":"u2","delegate_id":"u3"}`,
+			status: http.StatusBadRequest, code: "PR_ID_REQUIRED"},
+		{name: "delegate missing reviewer", serve: h.DelegateReview, target: "/pullRequest/delegate", body: `{"pull_request_id":"pr-1","delegate_id":"u3"}`,
+			status: http.StatusBadRequest, code: "REVIEWER_REQUIRED"},
+		{name: "delegate missing delegate", serve: h.DelegateReview, target: "/pullRequest/delegate", body: prBody, userID: "u2",
+			status: http.StatusBadRequest, code: "DELEGATE_REQUIRED"},
+		{name: "delegate not found", serve: h.DelegateReview, target: "/pullRequest/delegate", body: `{"pull_request_id":"pr-1","delegate_id":"u3"}`, userID: "u2",
+			err: apperrors.ErrPRNotFound, status: http.StatusNotFound, code: "NOT_FOUND", called: "DelegateReview"},
+		{name: "delegate not assigned", serve: h.DelegateReview, target: "/pullRequest/delegate", body: `{"pull_request_id":"pr-1","delegate_id":"u3"}`, userID: "u2",
+			err: apperrors.ErrReviewerNotAssigned, status: http.StatusNotFound, code: "NOT_ASSIGNED", called: "DelegateReview"},
+		{name: "delegate merged", serve: h.DelegateReview, target: "/pullRequest/delegate", body: `{"pull_request_id":"pr-1","delegate_id":"u3"}`, userID: "u2",
+			err: apperrors.ErrPRAlreadyMerged, status: http.StatusConflict, code: "PR_MERGED", called: "DelegateReview"},
+		{name: "delegate not teammate", serve: h.DelegateReview, target: "/pullRequest/delegate", body: `{"pull_request_id":"pr-1","delegate_id":"u3"}`, userID: "u2",
+			err: apperrors.ErrDelegateNotTeammate, status: http.StatusConflict, code: "NOT_IN_TEAM", called: "DelegateReview"},
+		{name: "delegate at capacity", serve: h.DelegateReview, target: "/pullRequest/delegate", body: `{"pull_request_id":"pr-1","delegate_id":"u3"}`, userID: "u2",
+			err: apperrors.ErrDelegateAtCapacity, status: http.StatusConflict, code: "AT_CAPACITY", called: "DelegateReview"},
+		{name: "delegate inactive", serve: h.DelegateReview, target: "/pullRequest/delegate", body: `{"pull_request_id":"pr-1","delegate_id":"u3"}`, userID: "u2",
+			err: apperrors.ErrReviewerInactive, status: http.StatusConflict, code: "REVIEWER_INACTIVE", called: "DelegateReview"},
+		{name: "delegate internal", serve: h.DelegateReview, target: "/pullRequest/delegate", body: `{"pull_request_id":"pr-1","delegate_id":"u3"}`, userID: "u2",
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "DelegateReview"},
+
+		{name: "decline invalid body", serve: h.DeclineReview, target: "/pullRequest/decline", body: "{",
+			status: http.StatusBadRequest, code: "INVALID_REQUEST"},
+		{name: "decline missing id", serve: h.DeclineReview, target: "/pullRequest/decline", body: `{}`, userID: "u2",
+			status: http.StatusBadRequest, code: "PR_ID_REQUIRED"},
+		{name: "decline missing reviewer", serve: h.DeclineReview, target: "/pullRequest/decline", body: prBody,
+			status: http.StatusBadRequest, code: "REVIEWER_REQUIRED"},
+		{name: "decline not found", serve: h.DeclineReview, target: "/pullRequest/decline", body: prBody, userID: "u2",
+			err: apperrors.ErrPRNotFound, status: http.StatusNotFound, code: "NOT_FOUND", called: "DeclineReview"},
+		{name: "decline not assigned", serve: h.DeclineReview, target: "/pullRequest/decline", body: prBody, userID: "u2",
+			err: apperrors.ErrReviewerNotAssigned, status: http.StatusNotFound, code: "NOT_ASSIGNED", called: "DeclineReview"},
+		{name: "decline merged", serve: h.DeclineReview, target: "/pullRequest/decline", body: prBody, userID: "u2",
+			err: apperrors.ErrPRAlreadyMerged, status: http.StatusConflict, code: "PR_MERGED", called: "DeclineReview"},
+		{name: "decline internal", serve: h.DeclineReview, target: "/pullRequest/decline", body: prBody, userID: "u2",
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "DeclineReview"},
+
+		{name: "start review invalid body", serve: h.StartReview, target: "/pullRequest/startReview", body: "{",
+			status: http.StatusBadRequest, code: "INVALID_REQUEST"},
+		{name: "start review missing id", serve: h.StartReview, target: "/pullRequest/startReview", body: `{"reviewer_id":"u2"}`,
+			status: http.StatusBadRequest, code: "PR_ID_REQUIRED"},
+		{name: "start review missing reviewer", serve: h.StartReview, target: "/pullRequest/startReview", body: prBody,
+			status: http.StatusBadRequest, code: "REVIEWER_REQUIRED"},
+		{name: "start review not assigned", serve: h.StartReview, target: "/pullRequest/startReview", body: prBody, userID: "u2",
+			err: apperrors.ErrReviewerNotAssigned, status: http.StatusNotFound, code: "NOT_FOUND", called: "StartReview"},
+		{name: "start review merged", serve: h.StartReview, target: "/pullRequest/startReview", body: reviewerBody,
+			err: apperrors.ErrPRAlreadyMerged, status: http.StatusConflict, code: "PR_MERGED", called: "StartReview"},
+		{name: "start review internal", serve: h.StartReview, target: "/pullRequest/startReview", body: reviewerBody,
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "StartReview"},
+
+		{name: "complete review invalid body", serve: h.CompleteReview, target: "/pullRequest/completeReview", body: "{",
+			status: http.StatusBadRequest, code: "INVALID_REQUEST"},
+		{name: "complete review missing id", serve: h.CompleteReview, target: "/pullRequest/completeReview", body: `{"reviewer_id":"u2"}`,
+			status: http.StatusBadRequest, code: "PR_ID_REQUIRED"},
+		{name: "complete review missing reviewer", serve: h.CompleteReview, target: "/pullRequest/completeReview", body: prBody,
+			status: http.StatusBadRequest, code: "REVIEWER_REQUIRED"},
+		{name: "complete review not found", serve: h.CompleteReview, target: "/pullRequest/completeReview", body: reviewerBody,
+			err: apperrors.ErrPRNotFound, status: http.StatusNotFound, code: "NOT_FOUND", called: "CompleteReview"},
+		{name: "complete review merged", serve: h.CompleteReview, target: "/pullRequest/completeReview", body: reviewerBody,
+			err: apperrors.ErrPRAlreadyMerged, status: http.StatusConflict, code: "PR_MERGED", called: "CompleteReview"},
+		{name: "complete review internal", serve: h.CompleteReview, target: "/pullRequest/completeReview", body: reviewerBody,
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "CompleteReview"},
+
+		{name: "approve invalid body", serve: h.ApprovePR, target: "/pullRequest/approve", body: "{",
+			status: http.StatusBadRequest, code: "INVALID_REQUEST"},
+		{name: "approve missing id", serve: h.ApprovePR, target: "/pullRequest/approve", body: `{"reviewer_id":"u2"}`,
+			status: http.StatusBadRequest, code: "PR_ID_REQUIRED"},
+		{name: "approve missing reviewer", serve: h.ApprovePR, target: "/pullRequest/approve", body: prBody,
+			status: http.StatusBadRequest, code: "REVIEWER_REQUIRED"},
+		{name: "approve not assigned", serve: h.ApprovePR, target: "/pullRequest/approve", body: reviewerBody,
+			err: apperrors.ErrReviewerNotAssigned, status: http.StatusNotFound, code: "NOT_FOUND", called: "ApprovePR"},
+		{name: "approve merged", serve: h.ApprovePR, target: "/pullRequest/approve", body: reviewerBody,
+			err: apperrors.ErrPRAlreadyMerged, status: http.StatusConflict, code: "PR_MERGED", called: "ApprovePR"},
+		{name: "approve internal", serve: h.ApprovePR, target: "/pullRequest/approve", body: reviewerBody,
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "ApprovePR"},
+
+		{name: "export internal", serve: h.ExportPRs, method: http.MethodGet, target: "/pullRequest/export",
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "ExportPRs"},
+		{name: "export invalid search time", serve: h.ExportPRs, method: http.MethodGet, target: "/pullRequest/export?created_from=yesterday",
+			status: http.StatusBadRequest, code: "INVALID_SEARCH"},
+		{name: "export invalid search range", serve: h.ExportPRs, method: http.MethodGet, target: "/pullRequest/export?created_from=2026-02-01T00:00:00Z&created_to=2026-01-01T00:00:00Z",
+			err:    fmt.Errorf("%w: created_from must be before created_to", apperrors.ErrInvalidPRSearch),
+			status: http.StatusBadRequest, code: "INVALID_SEARCH", called: "ExportPRs"},
+		{name: "export invalid reviewer", serve: h.ExportPRs, method: http.MethodGet, target: "/pullRequest/export?reviewer_id=bob",
+			err: apperrors.ErrInvalidUserID, status: http.StatusBadRequest, code: "INVALID_USER_ID", called: "ExportPRs"},
+
+		{name: "candidates missing id", serve: h.GetCandidates, method: http.MethodGet, target: "/pullRequest/candidates",
+			status: http.StatusBadRequest, code: "PR_ID_REQUIRED"},
+		{name: "candidates not found", serve: h.GetCandidates, method: http.MethodGet, target: "/pullRequest/candidates?pull_request_id=pr-1",
+			err: apperrors.ErrPRNotFound, status: http.StatusNotFound, code: "NOT_FOUND", called: "GetCandidates"},
+		{name: "candidates internal", serve: h.GetCandidates, method: http.MethodGet, target: "/pullRequest/candidates?pull_request_id=pr-1",
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "GetCandidates"},
+		{name: "pending assignments team not found", serve: h.GetPendingAssignments, method: http.MethodGet, target: "/pullRequest/pendingAssignments?team_name=ghost",
+			err: apperrors.ErrTeamNotFound, status: http.StatusNotFound, code: "NOT_FOUND", called: "GetPendingAssignments"},
+		{name: "pending assignments internal", serve: h.GetPendingAssignments, method: http.MethodGet, target: "/pullRequest/pendingAssignments",
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "GetPendingAssignments"},
+	}
+
+	runErrorCases(t, &mock.mockBase, cases)
+}
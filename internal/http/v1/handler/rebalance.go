@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"pull-request-assigner/internal/domain/models"
+	"pull-request-assigner/internal/http/httpio"
+	"pull-request-assigner/internal/lib/logger/sl"
+	"strconv"
+)
+
+type (
+	RebalanceResponse struct {
+		Rebalance *models.RebalancePlan `json:"rebalance"`
+	}
+)
+
+type TeamRebalancer interface {
+	RebalanceTeam(ctx context.Context, teamName string, dryRun bool) (*models.RebalancePlan, error)
+}
+
+type RebalanceHandler struct {
+	prService TeamRebalancer
+	log       *slog.Logger
+	resp      *httpio.Responder
+}
+
+func NewRebalanceHandler(prService TeamRebalancer, log *slog.Logger) *RebalanceHandler {
+	return &RebalanceHandler{
+		prService: prService,
+		log:       log,
+		resp:      httpio.NewResponder(log),
+	}
+}
+
+func (h *RebalanceHandler) Rebalance(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.rebalance.Rebalance"
+
+	log := h.log.With(slog.String("op", op))
+
+	teamName := r.URL.Query().Get("team_name")
+	if teamName == "" {
+		log.Error("team_name is required")
+		h.resp.Error(w, r, http.StatusBadRequest, "TEAM_NAME_REQUIRED", "team_name is required")
+		return
+	}
+
+	dryRun := false
+	if raw := r.URL.Query().Get("dry_run"); raw != "" {
+		var err error
+		dryRun, err = strconv.ParseBool(raw)
+		if err != nil {
+			log.Error("invalid dry_run flag", sl.Err(err))
+			h.resp.Error(w, r, http.StatusBadRequest, "INVALID_DRY_RUN", "dry_run must be true or false")
+			return
+		}
+	}
+
+	plan, err := h.prService.RebalanceTeam(r.Context(), teamName, dryRun)
+	if err != nil {
+		log.Error("failed to rebalance team", sl.Err(err))
+		h.resp.Fail(w, r, err, "failed to rebalance team")
+		return
+	}
+
+	h.resp.JSON(w, r, http.StatusOK, RebalanceResponse{Rebalance: plan})
+	log.Info("team rebalanced successfully",
+		slog.String("team_name", teamName),
+		slog.Bool("dry_run", dryRun),
+		slog.Int("moves", len(plan.Moves)))
+}
@@ -0,0 +1,23 @@
+package handler
+
+import (
+	"net/http"
+	"pull-request-assigner/internal/apperrors"
+	"testing"
+)
+
+func TestRebalanceHandlerErrors(t *testing.T) {
+	mock := &teamRebalancerMock{}
+	h := NewRebalanceHandler(mock, discardLogger())
+
+	runErrorCases(t, &mock.mockBase, []errorCase{
+		{name: "missing team", serve: h.Rebalance, target: "/admin/rebalance",
+			status: http.StatusBadRequest, code: "TEAM_NAME_REQUIRED"},
+		{name: "invalid dry run", serve: h.Rebalance, target: "/admin/rebalance?team_name=backend&dry_run=maybe",
+			status: http.StatusBadRequest, code: "INVALID_DRY_RUN"},
+		{name: "team not found", serve: h.Rebalance, target: "/admin/rebalance?team_name=ghost",
+			err: apperrors.ErrTeamNotFound, status: http.StatusNotFound, code: "NOT_FOUND", called: "RebalanceTeam"},
+		{name: "internal", serve: h.Rebalance, target: "/admin/rebalance?team_name=backend",
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "RebalanceTeam"},
+	})
+}
@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"pull-request-assigner/internal/apperrors"
+	"pull-request-assigner/internal/domain/models"
+	"pull-request-assigner/internal/http/httpio"
+	"pull-request-assigner/internal/lib/logger/sl"
+	"strconv"
+)
+
+type (
+	ReconcileRequest struct {
+		PullRequests []models.PRStatusClaim `json:"pull_requests"`
+	}
+
+	ReconcileResponse struct {
+		Reconcile *models.ReconcileReport `json:"reconcile"`
+	}
+)
+
+type StatusReconciler interface {
+	ReconcileStatuses(ctx context.Context, claims []models.PRStatusClaim, dryRun bool) (*models.ReconcileReport, error)
+}
+
+type ReconcileHandler struct {
+	prService StatusReconciler
+	log       *slog.Logger
+	resp      *httpio.Responder
+}
+
+func NewReconcileHandler(prService StatusReconciler, log *slog.Logger) *ReconcileHandler {
+	return &ReconcileHandler{
+		prService: prService,
+		log:       log,
+		resp:      httpio.NewResponder(log),
+	}
+}
+
+func (h *ReconcileHandler) Reconcile(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.reconcile.Reconcile"
+
+	log := h.log.With(slog.String("op", op))
+
+	dryRun := false
+	if raw := r.URL.Query().Get("dry_run"); raw != "" {
+		var err error
+		dryRun, err = strconv.ParseBool(raw)
+		if err != nil {
+			log.Error("invalid dry_run flag", sl.Err(err))
+			h.resp.Error(w, r, http.StatusBadRequest, "INVALID_DRY_RUN", "dry_run must be true or false")
+			return
+		}
+	}
+
+	var req ReconcileRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("invalid request body", sl.Err(err))
+		h.resp.Error(w, r, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+		return
+	}
+
+	report, err := h.prService.ReconcileStatuses(r.Context(), req.PullRequests, dryRun)
+	if err != nil {
+		log.Error("failed to reconcile PR statuses", sl.Err(err))
+
+		if errors.Is(err, apperrors.ErrInvalidReconcile) {
+			h.resp.Error(w, r, http.StatusBadRequest, "INVALID_RECONCILE",
+				"pull_requests needs 1 to 1000 unique ids with known statuses, and merged_at only with MERGED")
+			return
+		}
+		h.resp.Fail(w, r, err, "failed to reconcile PR statuses")
+		return
+	}
+
+	h.resp.JSON(w, r, http.StatusOK, ReconcileResponse{Reconcile: report})
+	log.Info("PR statuses reconciled",
+		slog.Bool("dry_run", dryRun),
+		slog.Int("corrected", report.Corrected))
+}
@@ -0,0 +1,25 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"pull-request-assigner/internal/apperrors"
+	"testing"
+)
+
+func TestReconcileHandlerErrors(t *testing.T) {
+	mock := &statusReconcilerMock{}
+	h := NewReconcileHandler(mock, discardLogger())
+
+	runErrorCases(t, &mock.mockBase, []errorCase{
+		{name: "invalid dry run", serve: h.Reconcile, target: "/admin/reconcile?dry_run=maybe", body: `{"pull_requests": []}`,
+			status: http.StatusBadRequest, code: "INVALID_DRY_RUN"},
+		{name: "invalid body", serve: h.Reconcile, target: "/admin/reconcile", body: `{`,
+			status: http.StatusBadRequest, code: "INVALID_REQUEST"},
+		{name: "invalid claims", serve: h.Reconcile, target: "/admin/reconcile", body: `{"pull_requests": []}`,
+			err:    fmt.Errorf("%w: at least one pull request is required", apperrors.ErrInvalidReconcile),
+			status: http.StatusBadRequest, code: "INVALID_RECONCILE", called: "ReconcileStatuses"},
+		{name: "internal", serve: h.Reconcile, target: "/admin/reconcile", body: `{"pull_requests": [{"pull_request_id": "PR-1", "status": "OPEN"}]}`,
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "ReconcileStatuses"},
+	})
+}
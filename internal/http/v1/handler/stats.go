@@ -1,11 +1,21 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
+	"pull-request-assigner/internal/apperrors"
+	"pull-request-assigner/internal/domain/models"
+	"pull-request-assigner/internal/http/httpio"
 	"pull-request-assigner/internal/lib/logger/sl"
+	"pull-request-assigner/internal/lib/xlsx"
 	"pull-request-assigner/internal/service"
+	"strconv"
+	"time"
 )
 
 type (
@@ -14,31 +24,62 @@ type (
 	}
 
 	PRStatsData struct {
-		TotalPRs          int     `json:"total_prs"`
-		OpenPRs           int     `json:"open_prs"`
-		MergedPRs         int     `json:"merged_prs"`
-		AvgReviewersPerPR float64 `json:"avg_reviewers_per_pr"`
+		TotalPRs            int            `json:"total_prs"`
+		OpenPRs             int            `json:"open_prs"`
+		MergedPRs           int            `json:"merged_prs"`
+		AvgReviewersPerPR   float64        `json:"avg_reviewers_per_pr"`
+		ByStatus            map[string]int `json:"by_status"`
+		AssignmentsByAction map[string]int `json:"assignments_by_action"`
+		MergesByUser        map[string]int `json:"merges_by_user"`
 	}
 
-	StatsErrorResponse struct {
-		Error StatsErrorDetail `json:"error"`
+	TeamsStatsRequest struct {
+		TeamNames []string `json:"team_names"`
 	}
 
-	StatsErrorDetail struct {
-		Code    string `json:"code"`
-		Message string `json:"message"`
+	TeamsStatsResponse struct {
+		Teams []models.TeamPRStats `json:"teams"`
+	}
+
+	CycleTimeResponse struct {
+		CycleTime *models.CycleTimeStats `json:"cycle_time"`
+	}
+
+	TagStatsResponse struct {
+		Stats *models.TagStatsReport `json:"stats"`
+	}
+
+	PairingResponse struct {
+		Pairing *models.PairingMatrix `json:"pairing"`
+	}
+
+	StatsHistoryResponse struct {
+		TeamName  string                 `json:"team_name,omitempty"`
+		Snapshots []models.StatsSnapshot `json:"snapshots"`
 	}
 )
 
+type StatsReporter interface {
+	GetPRStats(ctx context.Context) (*models.PRStats, error)
+	GetTeamsPRStats(ctx context.Context, teamNames []string) ([]models.TeamPRStats, error)
+	GetCycleTime(ctx context.Context, windowDays int) (*models.CycleTimeStats, error)
+	GetStatsHistory(ctx context.Context, teamName string, windowDays int) ([]models.StatsSnapshot, error)
+	GetCapacityPlan(ctx context.Context, teamName string) (*models.CapacityPlan, error)
+	GetTagStats(ctx context.Context, windowDays int) (*models.TagStatsReport, error)
+	GetPairingMatrix(ctx context.Context, teamName string, windowDays int) (*models.PairingMatrix, error)
+}
+
 type StatsHandler struct {
-	statsService *service.StatsService
+	statsService StatsReporter
 	log          *slog.Logger
+	resp         *httpio.Responder
 }
 
-func NewStatsHandler(statsService *service.StatsService, log *slog.Logger) *StatsHandler {
+func NewStatsHandler(statsService StatsReporter, log *slog.Logger) *StatsHandler {
 	return &StatsHandler{
 		statsService: statsService,
 		log:          log,
+		resp:         httpio.NewResponder(log),
 	}
 }
 
@@ -52,45 +93,277 @@ func (h *StatsHandler) GetPRStats(w http.ResponseWriter, r *http.Request) {
 	stats, err := h.statsService.GetPRStats(r.Context())
 	if err != nil {
 		log.Error("failed to get PR stats", sl.Err(err))
-		h.writeErrorResponse(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to get PR statistics")
+		h.resp.Error(w, r, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to get PR statistics")
 		return
 	}
 
 	response := PRStatsResponse{
 		Stats: PRStatsData{
-			TotalPRs:          stats.TotalPRs,
-			OpenPRs:           stats.OpenPRs,
-			MergedPRs:         stats.MergedPRs,
-			AvgReviewersPerPR: stats.AvgReviewersPerPR,
+			TotalPRs:            stats.TotalPRs,
+			OpenPRs:             stats.OpenPRs,
+			MergedPRs:           stats.MergedPRs,
+			AvgReviewersPerPR:   stats.AvgReviewersPerPR,
+			ByStatus:            stats.ByStatus,
+			AssignmentsByAction: stats.AssignmentsByAction,
+			MergesByUser:        stats.MergesByUser,
 		},
 	}
 
-	h.writeJSON(w, http.StatusOK, response)
+	h.resp.Selected(w, r, http.StatusOK, response)
 	log.Info("PR stats returned successfully",
 		slog.Int("total_prs", stats.TotalPRs),
 		slog.Int("open_prs", stats.OpenPRs))
 }
 
-func (h *StatsHandler) writeJSON(w http.ResponseWriter, status int, data interface{}) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	if err := json.NewEncoder(w).Encode(data); err != nil {
-		h.log.Error("failed to encode JSON response", sl.Err(err))
+func (h *StatsHandler) GetTeamsStats(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.stats.GetTeamsStats"
+
+	log := h.log.With(slog.String("op", op))
+
+	var req TeamsStatsRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("invalid request body", sl.Err(err))
+		h.resp.Error(w, r, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+		return
+	}
+
+	stats, err := h.statsService.GetTeamsPRStats(r.Context(), req.TeamNames)
+	if err != nil {
+		log.Error("failed to get teams stats", sl.Err(err))
+
+		switch {
+		case errors.Is(err, apperrors.ErrTeamNameRequired):
+			h.resp.Error(w, r, http.StatusBadRequest, "TEAM_NAME_REQUIRED", "team_names must contain non-empty team names")
+		case errors.Is(err, apperrors.ErrTooManyTeams):
+			h.resp.Error(w, r, http.StatusBadRequest, "TOO_MANY_TEAMS",
+				"at most %d teams can be requested at once", service.MaxTeamsPerStatsRequest)
+		default:
+			h.resp.Fail(w, r, err, "failed to get teams statistics")
+		}
+		return
 	}
+
+	h.resp.Selected(w, r, http.StatusOK, TeamsStatsResponse{Teams: stats})
+	log.Info("teams stats returned successfully", slog.Int("team_count", len(stats)))
 }
 
-func (h *StatsHandler) writeErrorResponse(w http.ResponseWriter, status int, code, message string) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
+func (h *StatsHandler) GetCycleTime(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.stats.GetCycleTime"
 
-	errorResp := StatsErrorResponse{
-		Error: StatsErrorDetail{
-			Code:    code,
-			Message: message,
-		},
+	log := h.log.With(slog.String("op", op))
+
+	windowDays, ok := parseWindowDays(r)
+	if !ok {
+		log.Error("invalid window_days")
+		h.resp.Error(w, r, http.StatusBadRequest, "INVALID_WINDOW",
+			"window_days must be between 1 and %d", service.MaxStatsWindowDays)
+		return
+	}
+
+	stats, err := h.statsService.GetCycleTime(r.Context(), windowDays)
+	if err != nil {
+		log.Error("failed to get cycle time", sl.Err(err))
+
+		switch {
+		case errors.Is(err, apperrors.ErrInvalidStatsWindow):
+			h.resp.Error(w, r, http.StatusBadRequest, "INVALID_WINDOW",
+				"window_days must be between 1 and %d", service.MaxStatsWindowDays)
+		default:
+			h.resp.Fail(w, r, err, "failed to get cycle time")
+		}
+		return
+	}
+
+	h.resp.Selected(w, r, http.StatusOK, CycleTimeResponse{CycleTime: stats})
+	log.Info("cycle time returned successfully", slog.Int("team_count", len(stats.Teams)))
+}
+
+func (h *StatsHandler) GetTagStats(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.stats.GetTagStats"
+
+	log := h.log.With(slog.String("op", op))
+
+	windowDays, ok := parseWindowDays(r)
+	if !ok {
+		log.Error("invalid window_days")
+		h.resp.Error(w, r, http.StatusBadRequest, "INVALID_WINDOW",
+			"window_days must be between 1 and %d", service.MaxStatsWindowDays)
+		return
+	}
+
+	stats, err := h.statsService.GetTagStats(r.Context(), windowDays)
+	if err != nil {
+		log.Error("failed to get tag stats", sl.Err(err))
+
+		switch {
+		case errors.Is(err, apperrors.ErrInvalidStatsWindow):
+			h.resp.Error(w, r, http.StatusBadRequest, "INVALID_WINDOW",
+				"window_days must be between 1 and %d", service.MaxStatsWindowDays)
+		default:
+			h.resp.Fail(w, r, err, "failed to get tag stats")
+		}
+		return
+	}
+
+	h.resp.Selected(w, r, http.StatusOK, TagStatsResponse{Stats: stats})
+	log.Info("tag stats returned successfully",
+		slog.Int("labels", len(stats.Labels)),
+		slog.Int("skills", len(stats.Skills)))
+}
+
+func (h *StatsHandler) GetPairing(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.stats.GetPairing"
+
+	log := h.log.With(slog.String("op", op))
+
+	windowDays, ok := parseWindowDays(r)
+	if !ok {
+		log.Error("invalid window_days")
+		h.resp.Error(w, r, http.StatusBadRequest, "INVALID_WINDOW",
+			"window_days must be between 1 and %d", service.MaxStatsWindowDays)
+		return
 	}
 
-	if err := json.NewEncoder(w).Encode(errorResp); err != nil {
-		h.log.Error("failed to encode error response", sl.Err(err))
+	pairing, err := h.statsService.GetPairingMatrix(r.Context(), r.URL.Query().Get("team_name"), windowDays)
+	if err != nil {
+		log.Error("failed to get pairing matrix", sl.Err(err))
+
+		switch {
+		case errors.Is(err, apperrors.ErrInvalidStatsWindow):
+			h.resp.Error(w, r, http.StatusBadRequest, "INVALID_WINDOW",
+				"window_days must be between 1 and %d", service.MaxStatsWindowDays)
+		default:
+			h.resp.Fail(w, r, err, "failed to get pairing matrix")
+		}
+		return
+	}
+
+	h.resp.Selected(w, r, http.StatusOK, PairingResponse{Pairing: pairing})
+	log.Info("pairing matrix returned successfully",
+		slog.Int("members", len(pairing.Members)),
+		slog.Int("pairs", len(pairing.Pairs)))
+}
+
+func (h *StatsHandler) GetHistory(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.stats.GetHistory"
+
+	log := h.log.With(slog.String("op", op))
+
+	teamName := r.URL.Query().Get("team_name")
+
+	windowDays, ok := parseWindowDays(r)
+	if !ok {
+		log.Error("invalid window_days")
+		h.resp.Error(w, r, http.StatusBadRequest, "INVALID_WINDOW",
+			"window_days must be between 1 and %d", service.MaxStatsWindowDays)
+		return
+	}
+
+	snapshots, err := h.statsService.GetStatsHistory(r.Context(), teamName, windowDays)
+	if err != nil {
+		log.Error("failed to get stats history", sl.Err(err))
+
+		switch {
+		case errors.Is(err, apperrors.ErrInvalidStatsWindow):
+			h.resp.Error(w, r, http.StatusBadRequest, "INVALID_WINDOW",
+				"window_days must be between 1 and %d", service.MaxStatsWindowDays)
+		default:
+			h.resp.Fail(w, r, err, "failed to get stats history")
+		}
+		return
 	}
+
+	h.resp.Selected(w, r, http.StatusOK, StatsHistoryResponse{TeamName: teamName, Snapshots: snapshots})
+	log.Info("stats history returned successfully", slog.Int("snapshots", len(snapshots)))
+}
+
+func (h *StatsHandler) GetCapacity(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.stats.GetCapacity"
+
+	log := h.log.With(slog.String("op", op))
+
+	if format := r.URL.Query().Get("format"); format != "" && format != "xlsx" {
+		log.Error("unsupported export format", slog.String("format", format))
+		h.resp.Error(w, r, http.StatusBadRequest, "INVALID_FORMAT", "format must be xlsx")
+		return
+	}
+
+	plan, err := h.statsService.GetCapacityPlan(r.Context(), r.URL.Query().Get("team_name"))
+	if err != nil {
+		log.Error("failed to get capacity plan", sl.Err(err))
+		h.resp.Fail(w, r, err, "failed to build capacity plan")
+		return
+	}
+
+	w.Header().Set("Content-Type", xlsx.ContentType)
+	w.Header().Set("Content-Disposition",
+		fmt.Sprintf(`attachment; filename="capacity-%s.xlsx"`, plan.MonthStart.Format("2006-01")))
+	w.WriteHeader(http.StatusOK)
+
+	if err := writeCapacitySheet(w, plan); err != nil {
+		log.Error("failed to write capacity sheet", sl.Err(err))
+		return
+	}
+
+	log.Info("capacity plan exported successfully", slog.Int("members", len(plan.Members)))
+}
+
+// writeCapacitySheet renders one row per member: load columns followed by
+// one column per day of the month, 1 for an available working day, OFF for
+// a working day the member is away and empty for weekends.
+func writeCapacitySheet(w io.Writer, plan *models.CapacityPlan) error {
+	sheet, err := xlsx.NewWriter(w, "Capacity "+plan.MonthStart.Format("2006-01"))
+	if err != nil {
+		return err
+	}
+
+	header := []any{"team_name", "user_id", "username", "is_active", "open_reviews", "completed_reviews",
+		fmt.Sprintf("completed_last_%d_days", service.CapacityRecentDays), "available_days"}
+	for _, day := range plan.Days {
+		header = append(header, day.Format("2006-01-02"))
+	}
+	if err := sheet.WriteRow(header...); err != nil {
+		return err
+	}
+
+	for _, member := range plan.Members {
+		days := make([]any, 0, len(plan.Days))
+		available := 0
+		for _, day := range plan.Days {
+			switch {
+			case member.AvailableOn(day):
+				available++
+				days = append(days, 1)
+			case day.Weekday() == time.Saturday || day.Weekday() == time.Sunday:
+				days = append(days, nil)
+			default:
+				days = append(days, "OFF")
+			}
+		}
+
+		row := append([]any{member.TeamName, member.UserID, member.Username, member.IsActive,
+			member.OpenReviews, member.CompletedReviews, member.CompletedRecent, available}, days...)
+		if err := sheet.WriteRow(row...); err != nil {
+			return err
+		}
+	}
+
+	return sheet.Close()
+}
+
+// parseWindowDays reads the optional window_days query parameter; zero means
+// it was not given.
+func parseWindowDays(r *http.Request) (int, bool) {
+	raw := r.URL.Query().Get("window_days")
+	if raw == "" {
+		return 0, true
+	}
+
+	windowDays, err := strconv.Atoi(raw)
+	if err != nil || windowDays < 1 {
+		return 0, false
+	}
+
+	return windowDays, true
 }
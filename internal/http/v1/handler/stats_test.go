@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"net/http"
+	"pull-request-assigner/internal/apperrors"
+	"testing"
+)
+
+func TestStatsHandlerErrors(t *testing.T) {
+	mock := &statsReporterMock{}
+	h := NewStatsHandler(mock, discardLogger())
+
+	runErrorCases(t, &mock.mockBase, []errorCase{
+		{name: "prs internal", serve: h.GetPRStats, method: http.MethodGet, target: "/stats/prs",
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "GetPRStats"},
+
+		{name: "teams invalid body", serve: h.GetTeamsStats, target: "/stats/teams", body: "{",
+			status: http.StatusBadRequest, code: "INVALID_REQUEST"},
+		{name: "teams empty name", serve: h.GetTeamsStats, target: "/stats/teams", body: `{"team_names":[""]}`,
+			err: apperrors.ErrTeamNameRequired, status: http.StatusBadRequest, code: "TEAM_NAME_REQUIRED", called: "GetTeamsPRStats"},
+		{name: "teams too many", serve: h.GetTeamsStats, target: "/stats/teams", body: `{"team_names":["backend"]}`,
+			err: apperrors.ErrTooManyTeams, status: http.StatusBadRequest, code: "TOO_MANY_TEAMS", called: "GetTeamsPRStats"},
+		{name: "teams forbidden", serve: h.GetTeamsStats, target: "/stats/teams", body: `{"team_names":["backend"]}`,
+			err: apperrors.ErrStatsForbidden, status: http.StatusForbidden, code: "FORBIDDEN", called: "GetTeamsPRStats"},
+		{name: "teams internal", serve: h.GetTeamsStats, target: "/stats/teams", body: `{"team_names":["backend"]}`,
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "GetTeamsPRStats"},
+
+		{name: "cycle time invalid window", serve: h.GetCycleTime, method: http.MethodGet, target: "/stats/cycleTime?window_days=0",
+			status: http.StatusBadRequest, code: "INVALID_WINDOW"},
+		{name: "cycle time window too large", serve: h.GetCycleTime, method: http.MethodGet, target: "/stats/cycleTime?window_days=30",
+			err: apperrors.ErrInvalidStatsWindow, status: http.StatusBadRequest, code: "INVALID_WINDOW", called: "GetCycleTime"},
+		{name: "tag stats invalid window", serve: h.GetTagStats, method: http.MethodGet, target: "/stats/labels?window_days=-1",
+			status: http.StatusBadRequest, code: "INVALID_WINDOW"},
+		{name: "tag stats window too large", serve: h.GetTagStats, method: http.MethodGet, target: "/stats/labels?window_days=400",
+			err: apperrors.ErrInvalidStatsWindow, status: http.StatusBadRequest, code: "INVALID_WINDOW", called: "GetTagStats"},
+		{name: "tag stats internal", serve: h.GetTagStats, method: http.MethodGet, target: "/stats/labels",
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "GetTagStats"},
+		{name: "cycle time internal", serve: h.GetCycleTime, method: http.MethodGet, target: "/stats/cycleTime",
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "GetCycleTime"},
+
+		{name: "history invalid window", serve: h.GetHistory, method: http.MethodGet, target: "/stats/history?window_days=abc",
+			status: http.StatusBadRequest, code: "INVALID_WINDOW"},
+		{name: "history window too large", serve: h.GetHistory, method: http.MethodGet, target: "/stats/history?window_days=30",
+			err: apperrors.ErrInvalidStatsWindow, status: http.StatusBadRequest, code: "INVALID_WINDOW", called: "GetStatsHistory"},
+		{name: "history team not found", serve: h.GetHistory, method: http.MethodGet, target: "/stats/history?team_name=ghost",
+			err: apperrors.ErrTeamNotFound, status: http.StatusNotFound, code: "NOT_FOUND", called: "GetStatsHistory"},
+		{name: "history internal", serve: h.GetHistory, method: http.MethodGet, target: "/stats/history",
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "GetStatsHistory"},
+
+		{name: "pairing invalid window", serve: h.GetPairing, method: http.MethodGet, target: "/stats/pairing?team_name=backend&window_days=0",
+			status: http.StatusBadRequest, code: "INVALID_WINDOW"},
+		{name: "pairing team required", serve: h.GetPairing, method: http.MethodGet, target: "/stats/pairing",
+			err: apperrors.ErrTeamNameRequired, status: http.StatusBadRequest, code: "TEAM_NAME_REQUIRED", called: "GetPairingMatrix"},
+		{name: "pairing forbidden", serve: h.GetPairing, method: http.MethodGet, target: "/stats/pairing?team_name=backend",
+			err: apperrors.ErrStatsForbidden, status: http.StatusForbidden, code: "FORBIDDEN", called: "GetPairingMatrix"},
+		{name: "pairing team not found", serve: h.GetPairing, method: http.MethodGet, target: "/stats/pairing?team_name=ghost",
+			err: apperrors.ErrTeamNotFound, status: http.StatusNotFound, code: "NOT_FOUND", called: "GetPairingMatrix"},
+		{name: "pairing internal", serve: h.GetPairing, method: http.MethodGet, target: "/stats/pairing?team_name=backend",
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "GetPairingMatrix"},
+
+		{name: "capacity invalid format", serve: h.GetCapacity, method: http.MethodGet, target: "/stats/capacity?format=csv",
+			status: http.StatusBadRequest, code: "INVALID_FORMAT"},
+		{name: "capacity team not found", serve: h.GetCapacity, method: http.MethodGet, target: "/stats/capacity?team_name=ghost",
+			err: apperrors.ErrTeamNotFound, status: http.StatusNotFound, code: "NOT_FOUND", called: "GetCapacityPlan"},
+		{name: "capacity internal", serve: h.GetCapacity, method: http.MethodGet, target: "/stats/capacity",
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "GetCapacityPlan"},
+	})
+}
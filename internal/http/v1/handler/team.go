@@ -1,15 +1,17 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
-	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
 	"pull-request-assigner/internal/apperrors"
 	"pull-request-assigner/internal/domain/models"
+	"pull-request-assigner/internal/http/httpio"
+	"pull-request-assigner/internal/http/v1/middleware"
 	"pull-request-assigner/internal/lib/logger/sl"
-	"pull-request-assigner/internal/service"
 )
 
 type (
@@ -28,13 +30,47 @@ type (
 		Members  []models.User `json:"members"`
 	}
 
-	TeamErrorResponse struct {
-		Error TeamErrorDetail `json:"error"`
+	UpdateTeamRequest struct {
+		TeamName         string `json:"team_name"`
+		HoldUntilCIGreen *bool  `json:"hold_until_ci_green"`
+		MinReviewers     *int   `json:"min_reviewers"`
+		ReviewersPerPR   *int   `json:"reviewers_per_pr"`
+
+		DefaultPriority       *string   `json:"default_priority"`
+		DefaultLabels         *[]string `json:"default_labels"`
+		DefaultRequiredSkills *[]string `json:"default_required_skills"`
+		ReviewLabels          *[]string `json:"review_labels"`
+
+		ExcludeCoAuthors      *bool `json:"exclude_co_authors"`
+		ExcludePairingSession *bool `json:"exclude_pairing_session"`
+
+		DiversifyPairs *bool `json:"diversify_reviewer_pairs"`
+	}
+
+	UpdateTeamResponse struct {
+		Policy *models.TeamPolicy `json:"policy"`
 	}
 
-	TeamErrorDetail struct {
-		Code    string `json:"code"`
-		Message string `json:"message"`
+	PolicyHistoryResponse struct {
+		TeamName string                 `json:"team_name"`
+		Versions []models.PolicyVersion `json:"versions"`
+	}
+
+	TeamChangesResponse struct {
+		TeamName string              `json:"team_name"`
+		Changes  []models.AuditEvent `json:"changes"`
+	}
+
+	ArchiveTeamResponse struct {
+		TeamName         string `json:"team_name"`
+		DeactivatedUsers int    `json:"deactivated_users"`
+		Archived         bool   `json:"archived"`
+	}
+
+	SetSecondaryMemberRequest struct {
+		TeamName string `json:"team_name"`
+		UserID   string `json:"user_id"`
+		IsMember bool   `json:"is_member"`
 	}
 
 	DeactivateTeamUsersResponse struct {
@@ -43,15 +79,30 @@ type (
 	}
 )
 
+type TeamManager interface {
+	CreateTeamWithMembers(ctx context.Context, team models.Team) (*models.Team, error)
+	GetTeamWithMembers(ctx context.Context, teamName string) (*models.Team, error)
+	DeactivateTeamUsers(ctx context.Context, teamName string) (int, error)
+	ArchiveTeam(ctx context.Context, teamName string) (int, error)
+	UpdateTeamPolicy(ctx context.Context, teamName string, update models.TeamPolicyUpdate, actorID string) (*models.TeamPolicy, error)
+	GetPolicyHistory(ctx context.Context, teamName string) ([]models.PolicyVersion, error)
+	GetTeamSettings(ctx context.Context, teamName string) (*models.TeamPolicyOverrides, string, error)
+	PatchTeamSettings(ctx context.Context, teamName string, patch []byte, ifMatch string, actorID string) (*models.TeamPolicyOverrides, string, error)
+	GetTeamChanges(ctx context.Context, teamName string) ([]models.AuditEvent, error)
+	SetSecondaryMember(ctx context.Context, teamName string, userID string, isMember bool) (*models.Team, error)
+}
+
 type TeamHandler struct {
-	teamService *service.TeamService
+	teamService TeamManager
 	log         *slog.Logger
+	resp        *httpio.Responder
 }
 
-func NewTeamHandler(teamService *service.TeamService, log *slog.Logger) *TeamHandler {
+func NewTeamHandler(teamService TeamManager, log *slog.Logger) *TeamHandler {
 	return &TeamHandler{
 		teamService: teamService,
 		log:         log,
+		resp:        httpio.NewResponder(log),
 	}
 }
 
@@ -66,31 +117,31 @@ func (h *TeamHandler) CreateTeam(w http.ResponseWriter, r *http.Request) {
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		log.Error("invalid request body", sl.Err(err))
-		h.writeErrorResponse(w, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+		h.resp.Error(w, r, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
 		return
 	}
 
 	if req.TeamName == "" {
 		log.Error("team_name is required")
-		h.writeErrorResponse(w, http.StatusBadRequest, "TEAM_NAME_REQUIRED", "team_name is required")
+		h.resp.Error(w, r, http.StatusBadRequest, "TEAM_NAME_REQUIRED", "team_name is required")
 		return
 	}
 
 	if len(req.Members) == 0 {
 		log.Error("team must have at least one member")
-		h.writeErrorResponse(w, http.StatusBadRequest, "MEMBERS_REQUIRED", "team must have at least one member")
+		h.resp.Error(w, r, http.StatusBadRequest, "MEMBERS_REQUIRED", "team must have at least one member")
 		return
 	}
 
 	for i, member := range req.Members {
 		if member.UserID == "" {
-			h.writeErrorResponse(w, http.StatusBadRequest, "INVALID_MEMBER",
-				fmt.Sprintf("user_id is required for member at index %d", i))
+			h.resp.Error(w, r, http.StatusBadRequest, "INVALID_MEMBER",
+				"user_id is required for member at index %d", i)
 			return
 		}
 		if member.Username == "" {
-			h.writeErrorResponse(w, http.StatusBadRequest, "INVALID_MEMBER",
-				fmt.Sprintf("username is required for member at index %d", i))
+			h.resp.Error(w, r, http.StatusBadRequest, "INVALID_MEMBER",
+				"username is required for member at index %d", i)
 			return
 		}
 	}
@@ -106,14 +157,12 @@ func (h *TeamHandler) CreateTeam(w http.ResponseWriter, r *http.Request) {
 
 		switch {
 		case errors.Is(err, apperrors.ErrTeamExists):
-			h.writeErrorResponse(w, http.StatusBadRequest, "TEAM_EXISTS",
-				fmt.Sprintf("team %s already exists", req.TeamName))
-		case errors.Is(err, apperrors.ErrTeamNameRequired):
-			h.writeErrorResponse(w, http.StatusBadRequest, "TEAM_NAME_REQUIRED", "team_name is required")
+			h.resp.Error(w, r, http.StatusBadRequest, "TEAM_EXISTS",
+				"team %s already exists", req.TeamName)
 		case errors.Is(err, apperrors.ErrMembersRequired):
-			h.writeErrorResponse(w, http.StatusBadRequest, "MEMBERS_REQUIRED", "team must have at least one member")
+			h.resp.Error(w, r, http.StatusBadRequest, "MEMBERS_REQUIRED", "team must have at least one member")
 		default:
-			h.writeErrorResponse(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to create team")
+			h.resp.Fail(w, r, err, "failed to create team")
 		}
 		return
 	}
@@ -123,7 +172,7 @@ func (h *TeamHandler) CreateTeam(w http.ResponseWriter, r *http.Request) {
 		Members:  createdTeam.Members,
 	}
 
-	h.writeJSON(w, http.StatusCreated, response)
+	h.resp.JSON(w, r, http.StatusCreated, response)
 	log.Info("team created successfully")
 }
 
@@ -137,21 +186,56 @@ func (h *TeamHandler) GetTeam(w http.ResponseWriter, r *http.Request) {
 	teamName := r.URL.Query().Get("team_name")
 	if teamName == "" {
 		log.Error("team_name is required")
-		h.writeErrorResponse(w, http.StatusBadRequest, "TEAM_NAME_REQUIRED", "team_name query parameter is required")
+		h.resp.Error(w, r, http.StatusBadRequest, "TEAM_NAME_REQUIRED", "team_name query parameter is required")
 		return
 	}
 
 	team, err := h.teamService.GetTeamWithMembers(r.Context(), teamName)
 	if err != nil {
 		log.Error("failed to get team", sl.Err(err))
+		h.resp.Fail(w, r, err, "failed to get team")
+		return
+	}
+
+	response := GetTeamResponse{
+		TeamName: team.TeamName,
+		Members:  team.Members,
+	}
+
+	h.resp.Selected(w, r, http.StatusOK, response)
+	log.Info("team retrieved successfully")
+}
+
+func (h *TeamHandler) SetSecondaryMember(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.team.SetSecondaryMember"
+
+	log := h.log.With(
+		slog.String("op", op),
+	)
+
+	var req SetSecondaryMemberRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("invalid request body", sl.Err(err))
+		h.resp.Error(w, r, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+		return
+	}
+
+	if req.UserID == "" {
+		log.Error("user_id is required")
+		h.resp.Error(w, r, http.StatusBadRequest, "USER_ID_REQUIRED", "user_id is required")
+		return
+	}
+
+	team, err := h.teamService.SetSecondaryMember(r.Context(), req.TeamName, req.UserID, req.IsMember)
+	if err != nil {
+		log.Error("failed to set secondary member", sl.Err(err))
 
 		switch {
-		case errors.Is(err, apperrors.ErrTeamNotFound):
-			h.writeErrorResponse(w, http.StatusNotFound, "NOT_FOUND", "resource not found")
-		case errors.Is(err, apperrors.ErrTeamNameRequired):
-			h.writeErrorResponse(w, http.StatusBadRequest, "TEAM_NAME_REQUIRED", "team_name is required")
+		case errors.Is(err, apperrors.ErrPrimaryTeam):
+			h.resp.Error(w, r, http.StatusConflict, "PRIMARY_TEAM", "team is the user's primary team; change it through /team/add")
 		default:
-			h.writeErrorResponse(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to get team")
+			h.resp.Fail(w, r, err, "failed to set secondary member")
 		}
 		return
 	}
@@ -161,8 +245,11 @@ func (h *TeamHandler) GetTeam(w http.ResponseWriter, r *http.Request) {
 		Members:  team.Members,
 	}
 
-	h.writeJSON(w, http.StatusOK, response)
-	log.Info("team retrieved successfully")
+	h.resp.JSON(w, r, http.StatusOK, response)
+	log.Info("secondary membership updated",
+		slog.String("team_name", req.TeamName),
+		slog.String("user_id", req.UserID),
+		slog.Bool("is_member", req.IsMember))
 }
 
 func (h *TeamHandler) DeactivateTeamUsers(w http.ResponseWriter, r *http.Request) {
@@ -176,56 +263,269 @@ func (h *TeamHandler) DeactivateTeamUsers(w http.ResponseWriter, r *http.Request
 	teamName := r.URL.Query().Get("team_name")
 	if teamName == "" {
 		log.Error("team_name is required")
-		h.writeErrorResponse(w, http.StatusBadRequest, "TEAM_NAME_REQUIRED", "team_name query parameter is required")
+		h.resp.Error(w, r, http.StatusBadRequest, "TEAM_NAME_REQUIRED", "team_name query parameter is required")
 		return
 	}
 
 	deactivatedCount, err := h.teamService.DeactivateTeamUsers(r.Context(), teamName)
 	if err != nil {
 		log.Error("failed to deactivate team users", sl.Err(err))
+		h.resp.Fail(w, r, err, "failed to deactivate team users")
+		return
+	}
+
+	response := DeactivateTeamUsersResponse{
+		TeamName:         teamName,
+		DeactivatedUsers: deactivatedCount,
+	}
+
+	h.resp.JSON(w, r, http.StatusOK, response)
+	log.Info("team users deactivated successfully",
+		slog.String("team_name", teamName),
+		slog.Int("deactivated_count", deactivatedCount))
+}
+
+func (h *TeamHandler) UpdateTeam(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.team.UpdateTeam"
+
+	log := h.log.With(
+		slog.String("op", op),
+	)
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		log.Error("failed to read request body", sl.Err(err))
+		h.resp.Error(w, r, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+		return
+	}
+
+	var req UpdateTeamRequest
+	var fields map[string]json.RawMessage
+
+	if err := json.Unmarshal(body, &req); err != nil {
+		log.Error("invalid request body", sl.Err(err))
+		h.resp.Error(w, r, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+		return
+	}
+
+	if err := json.Unmarshal(body, &fields); err != nil {
+		log.Error("invalid request body", sl.Err(err))
+		h.resp.Error(w, r, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+		return
+	}
+
+	if req.TeamName == "" {
+		log.Error("team_name is required")
+		h.resp.Error(w, r, http.StatusBadRequest, "TEAM_NAME_REQUIRED", "team_name is required")
+		return
+	}
+
+	// An explicit null resets the field to the org default, while an absent
+	// field is left untouched.
+	var inherit []string
+	for _, field := range models.InheritablePolicyFields {
+		if value, ok := fields[field]; ok && string(value) == "null" {
+			inherit = append(inherit, field)
+		}
+	}
+
+	update := models.TeamPolicyUpdate{
+		Inherit: inherit,
+
+		HoldUntilCIGreen: req.HoldUntilCIGreen,
+		MinReviewers:     req.MinReviewers,
+		ReviewersPerPR:   req.ReviewersPerPR,
+
+		DefaultPriority:       req.DefaultPriority,
+		DefaultLabels:         req.DefaultLabels,
+		DefaultRequiredSkills: req.DefaultRequiredSkills,
+		ReviewLabels:          req.ReviewLabels,
+
+		ExcludeCoAuthors:      req.ExcludeCoAuthors,
+		ExcludePairingSession: req.ExcludePairingSession,
+
+		DiversifyPairs: req.DiversifyPairs,
+	}
+
+	actorID, _ := middleware.UserIDFromContext(r.Context())
+
+	policy, err := h.teamService.UpdateTeamPolicy(r.Context(), req.TeamName, update, actorID)
+	if err != nil {
+		log.Error("failed to update team", sl.Err(err))
 
 		switch {
-		case errors.Is(err, apperrors.ErrTeamNotFound):
-			h.writeErrorResponse(w, http.StatusNotFound, "NOT_FOUND", "resource not found")
-		case errors.Is(err, apperrors.ErrTeamNameRequired):
-			h.writeErrorResponse(w, http.StatusBadRequest, "TEAM_NAME_REQUIRED", "team_name is required")
+		case errors.Is(err, apperrors.ErrInvalidPolicy):
+			h.resp.Error(w, r, http.StatusBadRequest, "INVALID_POLICY", "invalid team policy")
 		default:
-			h.writeErrorResponse(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to deactivate team users")
+			h.resp.Fail(w, r, err, "failed to update team")
 		}
 		return
 	}
 
-	response := DeactivateTeamUsersResponse{
+	response := UpdateTeamResponse{
+		Policy: policy,
+	}
+
+	h.resp.JSON(w, r, http.StatusOK, response)
+	log.Info("team updated successfully")
+}
+
+func (h *TeamHandler) GetPolicyHistory(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.team.GetPolicyHistory"
+
+	log := h.log.With(
+		slog.String("op", op),
+	)
+
+	teamName := r.URL.Query().Get("team_name")
+	if teamName == "" {
+		log.Error("team_name is required")
+		h.resp.Error(w, r, http.StatusBadRequest, "TEAM_NAME_REQUIRED", "team_name query parameter is required")
+		return
+	}
+
+	versions, err := h.teamService.GetPolicyHistory(r.Context(), teamName)
+	if err != nil {
+		log.Error("failed to get policy history", sl.Err(err))
+		h.resp.Fail(w, r, err, "failed to get policy history")
+		return
+	}
+
+	response := PolicyHistoryResponse{
+		TeamName: teamName,
+		Versions: versions,
+	}
+
+	h.resp.JSON(w, r, http.StatusOK, response)
+	log.Info("policy history retrieved successfully")
+}
+
+func (h *TeamHandler) GetTeamChanges(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.team.GetTeamChanges"
+
+	log := h.log.With(
+		slog.String("op", op),
+	)
+
+	teamName := r.URL.Query().Get("team_name")
+	if teamName == "" {
+		log.Error("team_name is required")
+		h.resp.Error(w, r, http.StatusBadRequest, "TEAM_NAME_REQUIRED", "team_name query parameter is required")
+		return
+	}
+
+	changes, err := h.teamService.GetTeamChanges(r.Context(), teamName)
+	if err != nil {
+		log.Error("failed to get team changes", sl.Err(err))
+		h.resp.Fail(w, r, err, "failed to get team changes")
+		return
+	}
+
+	response := TeamChangesResponse{
+		TeamName: teamName,
+		Changes:  changes,
+	}
+
+	h.resp.JSON(w, r, http.StatusOK, response)
+	log.Info("team changes retrieved successfully")
+}
+
+func (h *TeamHandler) ArchiveTeam(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.team.ArchiveTeam"
+
+	log := h.log.With(
+		slog.String("op", op),
+	)
+
+	teamName := r.URL.Query().Get("team_name")
+	if teamName == "" {
+		log.Error("team_name is required")
+		h.resp.Error(w, r, http.StatusBadRequest, "TEAM_NAME_REQUIRED", "team_name query parameter is required")
+		return
+	}
+
+	deactivatedCount, err := h.teamService.ArchiveTeam(r.Context(), teamName)
+	if err != nil {
+		log.Error("failed to archive team", sl.Err(err))
+		h.resp.Fail(w, r, err, "failed to archive team")
+		return
+	}
+
+	response := ArchiveTeamResponse{
 		TeamName:         teamName,
 		DeactivatedUsers: deactivatedCount,
+		Archived:         true,
 	}
 
-	h.writeJSON(w, http.StatusOK, response)
-	log.Info("team users deactivated successfully",
+	h.resp.JSON(w, r, http.StatusOK, response)
+	log.Info("team archived successfully",
 		slog.String("team_name", teamName),
 		slog.Int("deactivated_count", deactivatedCount))
 }
 
-func (h *TeamHandler) writeJSON(w http.ResponseWriter, status int, data interface{}) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	if err := json.NewEncoder(w).Encode(data); err != nil {
-		h.log.Error("failed to encode JSON response", sl.Err(err))
+func (h *TeamHandler) GetSettings(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.team.GetSettings"
+
+	log := h.log.With(
+		slog.String("op", op),
+	)
+
+	teamName := r.URL.Query().Get("team_name")
+	if teamName == "" {
+		log.Error("team_name is required")
+		h.resp.Error(w, r, http.StatusBadRequest, "TEAM_NAME_REQUIRED", "team_name is required")
+		return
 	}
+
+	settings, etag, err := h.teamService.GetTeamSettings(r.Context(), teamName)
+	if err != nil {
+		log.Error("failed to get team settings", sl.Err(err))
+		h.resp.Fail(w, r, err, "failed to get team settings")
+		return
+	}
+
+	h.resp.Tagged(w, r, http.StatusOK, etag, settings)
 }
 
-func (h *TeamHandler) writeErrorResponse(w http.ResponseWriter, status int, code, message string) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
+// PatchSettings applies an RFC 7396 merge patch to the team's own policy
+// fields; null resets a field to the org default. An If-Match header makes
+// the change conditional on the entity tag.
+func (h *TeamHandler) PatchSettings(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.team.PatchSettings"
+
+	log := h.log.With(
+		slog.String("op", op),
+	)
+
+	teamName := r.URL.Query().Get("team_name")
+	if teamName == "" {
+		log.Error("team_name is required")
+		h.resp.Error(w, r, http.StatusBadRequest, "TEAM_NAME_REQUIRED", "team_name is required")
+		return
+	}
 
-	errorResp := TeamErrorResponse{
-		Error: TeamErrorDetail{
-			Code:    code,
-			Message: message,
-		},
+	patch, err := io.ReadAll(r.Body)
+	if err != nil {
+		log.Error("failed to read request body", sl.Err(err))
+		h.resp.Error(w, r, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+		return
 	}
 
-	if err := json.NewEncoder(w).Encode(errorResp); err != nil {
-		h.log.Error("failed to encode error response", sl.Err(err))
+	actorID, _ := middleware.UserIDFromContext(r.Context())
+
+	settings, etag, err := h.teamService.PatchTeamSettings(r.Context(), teamName, patch, r.Header.Get("If-Match"), actorID)
+	if err != nil {
+		log.Error("failed to patch team settings", sl.Err(err))
+
+		switch {
+		case errors.Is(err, apperrors.ErrInvalidPolicy):
+			h.resp.Error(w, r, http.StatusBadRequest, "INVALID_POLICY", "invalid team policy")
+		default:
+			h.resp.Fail(w, r, err, "failed to patch team settings")
+		}
+		return
 	}
+
+	h.resp.Tagged(w, r, http.StatusOK, etag, settings)
+	log.Info("team settings patched successfully")
 }
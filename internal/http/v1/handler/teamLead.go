@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"pull-request-assigner/internal/domain/models"
+	"pull-request-assigner/internal/http/httpio"
+	"pull-request-assigner/internal/lib/logger/sl"
+)
+
+type (
+	SetTeamLeadRequest struct {
+		TeamName string `json:"team_name"`
+		UserID   string `json:"user_id"`
+		IsLead   bool   `json:"is_lead"`
+	}
+
+	TeamLeadsResponse struct {
+		Leads []models.TeamLead `json:"leads"`
+	}
+)
+
+type TeamLeadManager interface {
+	SetTeamLead(ctx context.Context, teamName string, userID string, isLead bool) error
+	GetTeamLeads(ctx context.Context) ([]models.TeamLead, error)
+}
+
+type TeamLeadHandler struct {
+	teamService TeamLeadManager
+	log         *slog.Logger
+	resp        *httpio.Responder
+}
+
+func NewTeamLeadHandler(teamService TeamLeadManager, log *slog.Logger) *TeamLeadHandler {
+	return &TeamLeadHandler{
+		teamService: teamService,
+		log:         log,
+		resp:        httpio.NewResponder(log),
+	}
+}
+
+func (h *TeamLeadHandler) SetTeamLead(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.teamLead.SetTeamLead"
+
+	log := h.log.With(slog.String("op", op))
+
+	var req SetTeamLeadRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("invalid request body", sl.Err(err))
+		h.resp.Error(w, r, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+		return
+	}
+
+	if req.UserID == "" {
+		log.Error("user_id is required")
+		h.resp.Error(w, r, http.StatusBadRequest, "USER_ID_REQUIRED", "user_id is required")
+		return
+	}
+
+	if err := h.teamService.SetTeamLead(r.Context(), req.TeamName, req.UserID, req.IsLead); err != nil {
+		log.Error("failed to set team lead", sl.Err(err))
+		h.resp.Fail(w, r, err, "failed to set team lead")
+		return
+	}
+
+	h.list(w, r, log)
+}
+
+func (h *TeamLeadHandler) GetTeamLeads(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.teamLead.GetTeamLeads"
+
+	h.list(w, r, h.log.With(slog.String("op", op)))
+}
+
+func (h *TeamLeadHandler) list(w http.ResponseWriter, r *http.Request, log *slog.Logger) {
+	leads, err := h.teamService.GetTeamLeads(r.Context())
+	if err != nil {
+		log.Error("failed to get team leads", sl.Err(err))
+		h.resp.Fail(w, r, err, "failed to get team leads")
+		return
+	}
+
+	h.resp.JSON(w, r, http.StatusOK, TeamLeadsResponse{Leads: leads})
+}
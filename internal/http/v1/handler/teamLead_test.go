@@ -0,0 +1,27 @@
+package handler
+
+import (
+	"net/http"
+	"pull-request-assigner/internal/apperrors"
+	"testing"
+)
+
+func TestTeamLeadHandlerErrors(t *testing.T) {
+	mock := &teamLeadManagerMock{}
+	h := NewTeamLeadHandler(mock, discardLogger())
+
+	runErrorCases(t, &mock.mockBase, []errorCase{
+		{name: "invalid body", serve: h.SetTeamLead, target: "/admin/teamLeads/set", body: "{",
+			status: http.StatusBadRequest, code: "INVALID_REQUEST"},
+		{name: "missing user", serve: h.SetTeamLead, target: "/admin/teamLeads/set", body: `{"team_name":"backend"}`,
+			status: http.StatusBadRequest, code: "USER_ID_REQUIRED"},
+		{name: "missing team", serve: h.SetTeamLead, target: "/admin/teamLeads/set", body: `{"user_id":"u1"}`,
+			err: apperrors.ErrTeamNameRequired, status: http.StatusBadRequest, code: "TEAM_NAME_REQUIRED", called: "SetTeamLead"},
+		{name: "team not found", serve: h.SetTeamLead, target: "/admin/teamLeads/set", body: `{"team_name":"ghost","user_id":"u1","is_lead":true}`,
+			err: apperrors.ErrTeamNotFound, status: http.StatusNotFound, code: "NOT_FOUND", called: "SetTeamLead"},
+		{name: "internal", serve: h.SetTeamLead, target: "/admin/teamLeads/set", body: `{"team_name":"backend","user_id":"u1"}`,
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "SetTeamLead"},
+		{name: "list internal", serve: h.GetTeamLeads, method: http.MethodGet, target: "/admin/teamLeads",
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "GetTeamLeads"},
+	})
+}
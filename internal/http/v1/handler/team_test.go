@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"net/http"
+	"pull-request-assigner/internal/apperrors"
+	"pull-request-assigner/internal/lib/mergepatch"
+	"testing"
+)
+
+func TestTeamHandlerErrors(t *testing.T) {
+	mock := &teamManagerMock{}
+	h := NewTeamHandler(mock, discardLogger())
+
+	const createBody = `{"team_name":"backend","members":[{"user_id":"u1","username":"Alice","is_active":true}]}`
+
+	cases := []errorCase{
+		{name: "create invalid body", serve: h.CreateTeam, target: "/team/add", body: "{",
+			status: http.StatusBadRequest, code: "INVALID_REQUEST"},
+		{name: "create missing name", serve: h.CreateTeam, target: "/team/add", body: `{"members":[{"user_id":"u1","username":"Alice"}]}`,
+			status: http.StatusBadRequest, code: "TEAM_NAME_REQUIRED"},
+		{name: "create missing members", serve: h.CreateTeam, target: "/team/add", body: `{"team_name":"backend"}`,
+			status: http.StatusBadRequest, code: "MEMBERS_REQUIRED"},
+		{name: "create member without id", serve: h.CreateTeam, target: "/team/add", body: `{"team_name":"backend","members":[{"username":"Alice"}]}`,
+			status: http.StatusBadRequest, code: "INVALID_MEMBER"},
+		{name: "create member without username", serve: h.CreateTeam, target: "/team/add", body: `{"team_name":"backend","members":[{"user_id":"u1"}]}`,
+			status: http.StatusBadRequest, code: "INVALID_MEMBER"},
+		{name: "create exists", serve: h.CreateTeam, target: "/team/add", body: createBody,
+			err: apperrors.ErrTeamExists, status: http.StatusBadRequest, code: "TEAM_EXISTS", called: "CreateTeamWithMembers"},
+		{name: "create name required", serve: h.CreateTeam, target: "/team/add", body: createBody,
+			err: apperrors.ErrTeamNameRequired, status: http.StatusBadRequest, code: "TEAM_NAME_REQUIRED", called: "CreateTeamWithMembers"},
+		{name: "create members required", serve: h.CreateTeam, target: "/team/add", body: createBody,
+			err: apperrors.ErrMembersRequired, status: http.StatusBadRequest, code: "MEMBERS_REQUIRED", called: "CreateTeamWithMembers"},
+		{name: "create invalid profile", serve: h.CreateTeam, target: "/team/add", body: createBody,
+			err: apperrors.ErrInvalidProfile, status: http.StatusBadRequest, code: "INVALID_PROFILE", called: "CreateTeamWithMembers"},
+		{name: "create invalid user", serve: h.CreateTeam, target: "/team/add", body: createBody,
+			err: apperrors.ErrInvalidUserID, status: http.StatusBadRequest, code: "INVALID_USER_ID", called: "CreateTeamWithMembers"},
+		{name: "create internal", serve: h.CreateTeam, target: "/team/add", body: createBody,
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "CreateTeamWithMembers"},
+
+		{name: "update invalid body", serve: h.UpdateTeam, target: "/team/update", body: "{",
+			status: http.StatusBadRequest, code: "INVALID_REQUEST"},
+		{name: "update missing name", serve: h.UpdateTeam, target: "/team/update", body: `{}`,
+			status: http.StatusBadRequest, code: "TEAM_NAME_REQUIRED"},
+		{name: "update invalid policy", serve: h.UpdateTeam, target: "/team/update", body: `{"team_name":"backend","min_reviewers":9}`,
+			err: apperrors.ErrInvalidPolicy, status: http.StatusBadRequest, code: "INVALID_POLICY", called: "UpdateTeamPolicy"},
+		{name: "update not found", serve: h.UpdateTeam, target: "/team/update", body: `{"team_name":"ghost"}`,
+			err: apperrors.ErrTeamNotFound, status: http.StatusNotFound, code: "NOT_FOUND", called: "UpdateTeamPolicy"},
+		{name: "update internal", serve: h.UpdateTeam, target: "/team/update", body: `{"team_name":"backend"}`,
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "UpdateTeamPolicy"},
+
+		{name: "secondary invalid body", serve: h.SetSecondaryMember, target: "/team/members/setSecondary", body: "{",
+			status: http.StatusBadRequest, code: "INVALID_REQUEST"},
+		{name: "secondary missing user", serve: h.SetSecondaryMember, target: "/team/members/setSecondary", body: `{"team_name":"backend"}`,
+			status: http.StatusBadRequest, code: "USER_ID_REQUIRED"},
+		{name: "secondary primary team", serve: h.SetSecondaryMember, target: "/team/members/setSecondary", body: `{"team_name":"backend","user_id":"u1","is_member":true}`,
+			err: apperrors.ErrPrimaryTeam, status: http.StatusConflict, code: "PRIMARY_TEAM", called: "SetSecondaryMember"},
+		{name: "secondary not found", serve: h.SetSecondaryMember, target: "/team/members/setSecondary", body: `{"team_name":"ghost","user_id":"u1","is_member":true}`,
+			err: apperrors.ErrTeamNotFound, status: http.StatusNotFound, code: "NOT_FOUND", called: "SetSecondaryMember"},
+		{name: "secondary internal", serve: h.SetSecondaryMember, target: "/team/members/setSecondary", body: `{"team_name":"backend","user_id":"u1"}`,
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "SetSecondaryMember"},
+
+		{name: "patch settings invalid patch", serve: h.PatchSettings, method: http.MethodPatch, target: "/team/settings?team_name=backend", body: `[]`,
+			err: &mergepatch.InvalidPatchError{Reason: "patch must be a JSON object"}, status: http.StatusBadRequest, code: "INVALID_PATCH", called: "PatchTeamSettings"},
+		{name: "patch settings invalid policy", serve: h.PatchSettings, method: http.MethodPatch, target: "/team/settings?team_name=backend", body: `{"min_reviewers":-1}`,
+			err: apperrors.ErrInvalidPolicy, status: http.StatusBadRequest, code: "INVALID_POLICY", called: "PatchTeamSettings"},
+		{name: "patch settings stale", serve: h.PatchSettings, method: http.MethodPatch, target: "/team/settings?team_name=backend", body: `{"min_reviewers":2}`,
+			err: apperrors.ErrPreconditionFailed, status: http.StatusPreconditionFailed, code: "PRECONDITION_FAILED", called: "PatchTeamSettings"},
+	}
+
+	queryEndpoints := []struct {
+		name   string
+		serve  http.HandlerFunc
+		method string
+		path   string
+		called string
+	}{
+		{"get", h.GetTeam, http.MethodGet, "/team/get", "GetTeamWithMembers"},
+		{"deactivate", h.DeactivateTeamUsers, http.MethodPost, "/team/deactivate", "DeactivateTeamUsers"},
+		{"policy history", h.GetPolicyHistory, http.MethodGet, "/team/policy/history", "GetPolicyHistory"},
+		{"changes", h.GetTeamChanges, http.MethodGet, "/team/changes", "GetTeamChanges"},
+		{"settings", h.GetSettings, http.MethodGet, "/team/settings", "GetTeamSettings"},
+		{"patch settings", h.PatchSettings, http.MethodPatch, "/team/settings", "PatchTeamSettings"},
+		{"archive", h.ArchiveTeam, http.MethodPost, "/team/archive", "ArchiveTeam"},
+	}
+
+	for _, e := range queryEndpoints {
+		cases = append(cases,
+			errorCase{name: e.name + " missing name", serve: e.serve, method: e.method, target: e.path,
+				status: http.StatusBadRequest, code: "TEAM_NAME_REQUIRED"},
+			errorCase{name: e.name + " not found", serve: e.serve, method: e.method, target: e.path + "?team_name=ghost",
+				err: apperrors.ErrTeamNotFound, status: http.StatusNotFound, code: "NOT_FOUND", called: e.called},
+			errorCase{name: e.name + " internal", serve: e.serve, method: e.method, target: e.path + "?team_name=backend",
+				err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: e.called},
+		)
+	}
+
+	runErrorCases(t, &mock.mockBase, cases)
+}
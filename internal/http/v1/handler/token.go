@@ -0,0 +1,259 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"pull-request-assigner/internal/apperrors"
+	"pull-request-assigner/internal/domain/models"
+	"pull-request-assigner/internal/http/httpio"
+	"pull-request-assigner/internal/lib/logger/sl"
+	"time"
+)
+
+type (
+	IssueTokenRequest struct {
+		Name        string     `json:"name"`
+		UserID      string     `json:"user_id"`
+		Scopes      []string   `json:"scopes"`
+		HourlyQuota *int       `json:"hourly_quota"`
+		ExpiresAt   *time.Time `json:"expires_at"`
+	}
+
+	// BootstrapTokenRequest asks for the first admin token; Secret is the
+	// configured ADMIN_SECRET.
+	BootstrapTokenRequest struct {
+		Name   string `json:"name"`
+		UserID string `json:"user_id"`
+		Secret string `json:"secret"`
+	}
+
+	TokenIDRequest struct {
+		TokenID int64 `json:"token_id"`
+	}
+
+	// SetTokenQuotaRequest sets the token's hourly quota; a null
+	// HourlyQuota makes it follow the default quota.
+	SetTokenQuotaRequest struct {
+		TokenID     int64 `json:"token_id"`
+		HourlyQuota *int  `json:"hourly_quota"`
+	}
+
+	// TokenSecretResponse is the only response that carries the raw key.
+	TokenSecretResponse struct {
+		Token *models.APIToken `json:"token"`
+		Key   string           `json:"key"`
+	}
+
+	TokenResponse struct {
+		Token *models.APIToken `json:"token"`
+	}
+
+	ListTokensResponse struct {
+		Tokens []models.APIToken `json:"tokens"`
+	}
+)
+
+type TokenManager interface {
+	IssueToken(ctx context.Context, name string, userID string, scopes []string, hourlyQuota *int, expiresAt *time.Time) (*models.APIToken, string, error)
+	BootstrapToken(ctx context.Context, secret string, name string, userID string) (*models.APIToken, string, error)
+	ListTokens(ctx context.Context) ([]models.APIToken, error)
+	RevokeToken(ctx context.Context, tokenID int64) (*models.APIToken, error)
+	RotateToken(ctx context.Context, tokenID int64) (*models.APIToken, string, error)
+	SetTokenQuota(ctx context.Context, tokenID int64, hourlyQuota *int) (*models.APIToken, error)
+}
+
+type TokenHandler struct {
+	tokenService TokenManager
+	log          *slog.Logger
+	resp         *httpio.Responder
+}
+
+func NewTokenHandler(tokenService TokenManager, log *slog.Logger) *TokenHandler {
+	return &TokenHandler{
+		tokenService: tokenService,
+		log:          log,
+		resp:         httpio.NewResponder(log),
+	}
+}
+
+func (h *TokenHandler) IssueToken(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.token.IssueToken"
+
+	log := h.log.With(slog.String("op", op))
+
+	var req IssueTokenRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("invalid request body", sl.Err(err))
+		h.resp.Error(w, r, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+		return
+	}
+
+	token, key, err := h.tokenService.IssueToken(r.Context(), req.Name, req.UserID, req.Scopes, req.HourlyQuota, req.ExpiresAt)
+	if err != nil {
+		log.Error("failed to issue token", sl.Err(err))
+
+		switch {
+		case errors.Is(err, apperrors.ErrTokenNameRequired):
+			h.resp.Error(w, r, http.StatusBadRequest, "NAME_REQUIRED", "name is required")
+		case errors.Is(err, apperrors.ErrTokenUserRequired):
+			h.resp.Error(w, r, http.StatusBadRequest, "USER_ID_REQUIRED", "user_id is required")
+		case errors.Is(err, apperrors.ErrTokenScopeRequired):
+			h.resp.Error(w, r, http.StatusBadRequest, "SCOPE_REQUIRED", "at least one scope is required")
+		case errors.Is(err, apperrors.ErrInvalidTokenScope):
+			h.resp.Error(w, r, http.StatusBadRequest, "INVALID_SCOPE", "scopes must be read, write or admin")
+		case errors.Is(err, apperrors.ErrInvalidTokenQuota):
+			h.resp.Error(w, r, http.StatusBadRequest, "INVALID_QUOTA", "hourly_quota must not be negative")
+		case errors.Is(err, apperrors.ErrTokenExpiryInPast):
+			h.resp.Error(w, r, http.StatusBadRequest, "INVALID_EXPIRY", "expires_at must be in the future")
+		default:
+			h.resp.Fail(w, r, err, "failed to issue token")
+		}
+		return
+	}
+
+	h.resp.JSON(w, r, http.StatusCreated, TokenSecretResponse{Token: token, Key: key})
+	log.Info("token issued successfully")
+}
+
+// BootstrapToken issues the first admin token. It is not behind an API key:
+// the request carries ADMIN_SECRET instead, and it only works until an admin
+// token exists.
+func (h *TokenHandler) BootstrapToken(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.token.BootstrapToken"
+
+	log := h.log.With(slog.String("op", op))
+
+	var req BootstrapTokenRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("invalid request body", sl.Err(err))
+		h.resp.Error(w, r, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+		return
+	}
+
+	token, key, err := h.tokenService.BootstrapToken(r.Context(), req.Secret, req.Name, req.UserID)
+	if err != nil {
+		log.Error("failed to bootstrap token", sl.Err(err))
+
+		switch {
+		case errors.Is(err, apperrors.ErrInvalidBootstrap):
+			h.resp.Error(w, r, http.StatusUnauthorized, "UNAUTHORIZED", "invalid bootstrap secret")
+		case errors.Is(err, apperrors.ErrBootstrapClosed):
+			h.resp.Error(w, r, http.StatusConflict, "BOOTSTRAP_CLOSED", "an admin API key already exists")
+		case errors.Is(err, apperrors.ErrTokenNameRequired):
+			h.resp.Error(w, r, http.StatusBadRequest, "NAME_REQUIRED", "name is required")
+		case errors.Is(err, apperrors.ErrTokenUserRequired):
+			h.resp.Error(w, r, http.StatusBadRequest, "USER_ID_REQUIRED", "user_id is required")
+		default:
+			h.resp.Fail(w, r, err, "failed to bootstrap token")
+		}
+		return
+	}
+
+	h.resp.JSON(w, r, http.StatusCreated, TokenSecretResponse{Token: token, Key: key})
+	log.Info("admin token bootstrapped successfully")
+}
+
+func (h *TokenHandler) ListTokens(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.token.ListTokens"
+
+	log := h.log.With(slog.String("op", op))
+
+	tokens, err := h.tokenService.ListTokens(r.Context())
+	if err != nil {
+		log.Error("failed to list tokens", sl.Err(err))
+		h.resp.Error(w, r, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to list tokens")
+		return
+	}
+
+	h.resp.JSON(w, r, http.StatusOK, ListTokensResponse{Tokens: tokens})
+	log.Info("tokens listed successfully")
+}
+
+func (h *TokenHandler) RotateToken(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.token.RotateToken"
+
+	log := h.log.With(slog.String("op", op))
+
+	var req TokenIDRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("invalid request body", sl.Err(err))
+		h.resp.Error(w, r, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+		return
+	}
+
+	token, key, err := h.tokenService.RotateToken(r.Context(), req.TokenID)
+	if err != nil {
+		log.Error("failed to rotate token", sl.Err(err))
+
+		switch {
+		case errors.Is(err, apperrors.ErrTokenRevoked):
+			h.resp.Error(w, r, http.StatusConflict, "TOKEN_REVOKED", "cannot rotate revoked token")
+		default:
+			h.resp.Fail(w, r, err, "failed to rotate token")
+		}
+		return
+	}
+
+	h.resp.JSON(w, r, http.StatusOK, TokenSecretResponse{Token: token, Key: key})
+	log.Info("token rotated successfully")
+}
+
+func (h *TokenHandler) RevokeToken(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.token.RevokeToken"
+
+	log := h.log.With(slog.String("op", op))
+
+	var req TokenIDRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("invalid request body", sl.Err(err))
+		h.resp.Error(w, r, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+		return
+	}
+
+	token, err := h.tokenService.RevokeToken(r.Context(), req.TokenID)
+	if err != nil {
+		log.Error("failed to revoke token", sl.Err(err))
+		h.resp.Fail(w, r, err, "failed to revoke token")
+		return
+	}
+
+	h.resp.JSON(w, r, http.StatusOK, TokenResponse{Token: token})
+	log.Info("token revoked successfully")
+}
+
+func (h *TokenHandler) SetTokenQuota(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.token.SetTokenQuota"
+
+	log := h.log.With(slog.String("op", op))
+
+	var req SetTokenQuotaRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("invalid request body", sl.Err(err))
+		h.resp.Error(w, r, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+		return
+	}
+
+	token, err := h.tokenService.SetTokenQuota(r.Context(), req.TokenID, req.HourlyQuota)
+	if err != nil {
+		log.Error("failed to set token quota", sl.Err(err))
+
+		switch {
+		case errors.Is(err, apperrors.ErrInvalidTokenQuota):
+			h.resp.Error(w, r, http.StatusBadRequest, "INVALID_QUOTA", "hourly_quota must not be negative")
+		default:
+			h.resp.Fail(w, r, err, "failed to set token quota")
+		}
+		return
+	}
+
+	h.resp.JSON(w, r, http.StatusOK, TokenResponse{Token: token})
+	log.Info("token quota set successfully")
+}
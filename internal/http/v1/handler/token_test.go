@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"net/http"
+	"pull-request-assigner/internal/apperrors"
+	"testing"
+)
+
+func TestTokenHandlerErrors(t *testing.T) {
+	mock := &tokenManagerMock{}
+	h := NewTokenHandler(mock, discardLogger())
+
+	const (
+		issueBody     = `{"name":"ci","user_id":"u1","scopes":["read"]}`
+		bootstrapBody = `{"name":"ops","user_id":"u1","secret":"s3cret"}`
+	)
+
+	runErrorCases(t, &mock.mockBase, []errorCase{
+		{name: "issue invalid body", serve: h.IssueToken, target: "/admin/tokens/issue", body: "{",
+			status: http.StatusBadRequest, code: "INVALID_REQUEST"},
+		{name: "issue name required", serve: h.IssueToken, target: "/admin/tokens/issue", body: issueBody,
+			err: apperrors.ErrTokenNameRequired, status: http.StatusBadRequest, code: "NAME_REQUIRED", called: "IssueToken"},
+		{name: "issue user required", serve: h.IssueToken, target: "/admin/tokens/issue", body: issueBody,
+			err: apperrors.ErrTokenUserRequired, status: http.StatusBadRequest, code: "USER_ID_REQUIRED", called: "IssueToken"},
+		{name: "issue invalid user", serve: h.IssueToken, target: "/admin/tokens/issue", body: issueBody,
+			err: apperrors.ErrInvalidUserID, status: http.StatusBadRequest, code: "INVALID_USER_ID", called: "IssueToken"},
+		{name: "issue user not found", serve: h.IssueToken, target: "/admin/tokens/issue", body: issueBody,
+			err: apperrors.ErrUserNotFound, status: http.StatusNotFound, code: "NOT_FOUND", called: "IssueToken"},
+		{name: "issue scope required", serve: h.IssueToken, target: "/admin/tokens/issue", body: issueBody,
+			err: apperrors.ErrTokenScopeRequired, status: http.StatusBadRequest, code: "SCOPE_REQUIRED", called: "IssueToken"},
+		{name: "issue invalid scope", serve: h.IssueToken, target: "/admin/tokens/issue", body: issueBody,
+			err: apperrors.ErrInvalidTokenScope, status: http.StatusBadRequest, code: "INVALID_SCOPE", called: "IssueToken"},
+		{name: "issue negative quota", serve: h.IssueToken, target: "/admin/tokens/issue", body: issueBody,
+			err: apperrors.ErrInvalidTokenQuota, status: http.StatusBadRequest, code: "INVALID_QUOTA", called: "IssueToken"},
+		{name: "issue expiry in past", serve: h.IssueToken, target: "/admin/tokens/issue", body: issueBody,
+			err: apperrors.ErrTokenExpiryInPast, status: http.StatusBadRequest, code: "INVALID_EXPIRY", called: "IssueToken"},
+		{name: "issue internal", serve: h.IssueToken, target: "/admin/tokens/issue", body: issueBody,
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "IssueToken"},
+
+		{name: "bootstrap invalid body", serve: h.BootstrapToken, target: "/admin/tokens/bootstrap", body: "{",
+			status: http.StatusBadRequest, code: "INVALID_REQUEST"},
+		{name: "bootstrap invalid secret", serve: h.BootstrapToken, target: "/admin/tokens/bootstrap", body: bootstrapBody,
+			err: apperrors.ErrInvalidBootstrap, status: http.StatusUnauthorized, code: "UNAUTHORIZED", called: "BootstrapToken"},
+		{name: "bootstrap closed", serve: h.BootstrapToken, target: "/admin/tokens/bootstrap", body: bootstrapBody,
+			err: apperrors.ErrBootstrapClosed, status: http.StatusConflict, code: "BOOTSTRAP_CLOSED", called: "BootstrapToken"},
+		{name: "bootstrap user not found", serve: h.BootstrapToken, target: "/admin/tokens/bootstrap", body: bootstrapBody,
+			err: apperrors.ErrUserNotFound, status: http.StatusNotFound, code: "NOT_FOUND", called: "BootstrapToken"},
+		{name: "bootstrap internal", serve: h.BootstrapToken, target: "/admin/tokens/bootstrap", body: bootstrapBody,
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "BootstrapToken"},
+
+		{name: "list internal", serve: h.ListTokens, method: http.MethodGet, target: "/admin/tokens/list",
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "ListTokens"},
+
+		{name: "rotate invalid body", serve: h.RotateToken, target: "/admin/tokens/rotate", body: "{",
+			status: http.StatusBadRequest, code: "INVALID_REQUEST"},
+		{name: "rotate not found", serve: h.RotateToken, target: "/admin/tokens/rotate", body: `{"token_id":1}`,
+			err: apperrors.ErrTokenNotFound, status: http.StatusNotFound, code: "NOT_FOUND", called: "RotateToken"},
+		{name: "rotate revoked", serve: h.RotateToken, target: "/admin/tokens/rotate", body: `{"token_id":1}`,
+			err: apperrors.ErrTokenRevoked, status: http.StatusConflict, code: "TOKEN_REVOKED", called: "RotateToken"},
+		{name: "rotate internal", serve: h.RotateToken, target: "/admin/tokens/rotate", body: `{"token_id":1}`,
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "RotateToken"},
+
+		{name: "revoke invalid body", serve: h.RevokeToken, target: "/admin/tokens/revoke", body: "{",
+			status: http.StatusBadRequest, code: "INVALID_REQUEST"},
+		{name: "revoke not found", serve: h.RevokeToken, target: "/admin/tokens/revoke", body: `{"token_id":1}`,
+			err: apperrors.ErrTokenNotFound, status: http.StatusNotFound, code: "NOT_FOUND", called: "RevokeToken"},
+		{name: "revoke internal", serve: h.RevokeToken, target: "/admin/tokens/revoke", body: `{"token_id":1}`,
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "RevokeToken"},
+
+		{name: "set quota invalid body", serve: h.SetTokenQuota, target: "/admin/tokens/setQuota", body: "{",
+			status: http.StatusBadRequest, code: "INVALID_REQUEST"},
+		{name: "set quota negative", serve: h.SetTokenQuota, target: "/admin/tokens/setQuota", body: `{"token_id":1,"hourly_quota":-1}`,
+			err: apperrors.ErrInvalidTokenQuota, status: http.StatusBadRequest, code: "INVALID_QUOTA", called: "SetTokenQuota"},
+		{name: "set quota not found", serve: h.SetTokenQuota, target: "/admin/tokens/setQuota", body: `{"token_id":1,"hourly_quota":10}`,
+			err: apperrors.ErrTokenNotFound, status: http.StatusNotFound, code: "NOT_FOUND", called: "SetTokenQuota"},
+		{name: "set quota internal", serve: h.SetTokenQuota, target: "/admin/tokens/setQuota", body: `{"token_id":1,"hourly_quota":10}`,
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "SetTokenQuota"},
+	})
+}
@@ -0,0 +1,25 @@
+package handler
+
+import (
+	"net/http"
+	"pull-request-assigner/internal/domain/models"
+	"pull-request-assigner/internal/lib/trace"
+	"strconv"
+)
+
+// withAssignmentTrace attaches an assignment trace to the request context when
+// ?debug=true is set. The trace is nil when debugging was not requested.
+func withAssignmentTrace(r *http.Request) (*http.Request, *models.AssignmentTrace, error) {
+	raw := r.URL.Query().Get("debug")
+	if raw == "" {
+		return r, nil, nil
+	}
+
+	debug, err := strconv.ParseBool(raw)
+	if err != nil || !debug {
+		return r, nil, err
+	}
+
+	t := models.NewAssignmentTrace()
+	return r.WithContext(trace.WithAssignment(r.Context(), t)), t, nil
+}
@@ -1,55 +1,112 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"io"
 	"log/slog"
 	"net/http"
 	"pull-request-assigner/internal/apperrors"
 	"pull-request-assigner/internal/domain/models"
+	"pull-request-assigner/internal/http/httpio"
+	"pull-request-assigner/internal/http/v1/middleware"
 	"pull-request-assigner/internal/lib/logger/sl"
 	"pull-request-assigner/internal/service"
-	"strings"
+	"time"
 )
 
 type (
+	// SetIsActiveRequest hands the user's open reviews to other reviewers
+	// when ReassignOpenReviews is set on deactivation.
 	SetIsActiveRequest struct {
-		UserID   string `json:"user_id"`
-		IsActive bool   `json:"is_active"`
+		UserID              string `json:"user_id"`
+		IsActive            bool   `json:"is_active"`
+		ReassignOpenReviews bool   `json:"reassign_open_reviews"`
+	}
+
+	// SetAuthorOnlyRequest clears the author-only period when AuthorOnly is
+	// false; Until is ignored then.
+	SetAuthorOnlyRequest struct {
+		UserID     string     `json:"user_id"`
+		AuthorOnly bool       `json:"author_only"`
+		Until      *time.Time `json:"until"`
+	}
+
+	SetAuthorOnlyResponse struct {
+		User models.User `json:"user"`
+	}
+
+	SetIsActiveBatchRequest struct {
+		UserIDs  []string `json:"user_ids"`
+		IsActive bool     `json:"is_active"`
+	}
+
+	SetIsActiveBatchResponse struct {
+		Updated []models.User         `json:"updated"`
+		Failed  []models.BatchFailure `json:"failed"`
 	}
 
 	GetReviewRequest struct {
 		UserID string `json:"user_id"`
 	}
 
+	// SetIsActiveResponse carries the reassigned and unreassigned reviews
+	// only when open reviews were handed over.
 	SetIsActiveResponse struct {
 		User models.User `json:"user"`
+		*models.ReviewHandover
 	}
 
 	GetReviewResponse struct {
 		UserID       string                    `json:"user_id"`
 		PullRequests []models.PullRequestShort `json:"pull_requests"`
+		Changed      *bool                     `json:"changed,omitempty"`
 	}
 
-	UserErrorResponse struct {
-		Error UserErrorDetail `json:"error"`
+	ReorderQueueRequest struct {
+		PullRequestIDs []string `json:"pull_request_ids"`
 	}
 
-	UserErrorDetail struct {
-		Code    string `json:"code"`
-		Message string `json:"message"`
+	MyReviewsResponse struct {
+		UserID  string                    `json:"user_id"`
+		Reviews []models.ReviewAssignment `json:"reviews"`
+	}
+
+	GetUserResponse struct {
+		User *models.UserSummary `json:"user"`
+	}
+
+	ForecastResponse struct {
+		Forecast *models.ReviewForecast `json:"forecast"`
 	}
 )
 
+type ReviewerDirectory interface {
+	SetUserActiveStatus(ctx context.Context, isActive bool, userID string, reassignOpenReviews bool) (models.User, *models.ReviewHandover, error)
+	SetUsersActiveStatus(ctx context.Context, isActive bool, userIDs []string) (*models.BatchActiveResult, error)
+	SetAuthorOnly(ctx context.Context, userID string, authorOnly bool, until *time.Time) (models.User, error)
+	GetUserReview(ctx context.Context, userID string) ([]models.PullRequestShort, error)
+	WaitUserReview(ctx context.Context, userID string, wait time.Duration) ([]models.PullRequestShort, bool, error)
+	GetMyReviews(ctx context.Context, userID string) ([]models.ReviewAssignment, error)
+	ReorderQueue(ctx context.Context, userID string, prIDs []string) ([]models.ReviewAssignment, error)
+	GetReviewForecast(ctx context.Context, userID string) (*models.ReviewForecast, error)
+	GetUserSummary(ctx context.Context, userID string) (*models.UserSummary, error)
+	GetUserSettings(ctx context.Context, userID string) (*models.UserSettings, string, error)
+	PatchUserSettings(ctx context.Context, userID string, patch []byte, ifMatch string) (*models.UserSettings, string, error)
+}
+
 type UserHandler struct {
-	userService *service.UserService
+	userService ReviewerDirectory
 	log         *slog.Logger
+	resp        *httpio.Responder
 }
 
-func NewUserHandler(userService *service.UserService, log *slog.Logger) *UserHandler {
+func NewUserHandler(userService ReviewerDirectory, log *slog.Logger) *UserHandler {
 	return &UserHandler{
 		userService: userService,
 		log:         log,
+		resp:        httpio.NewResponder(log),
 	}
 }
 
@@ -64,43 +121,121 @@ func (h *UserHandler) SetIsActive(w http.ResponseWriter, r *http.Request) {
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		log.Error("invalid request body", sl.Err(err))
-		h.writeErrorResponse(w, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+		h.resp.Error(w, r, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
 		return
 	}
 
 	if req.UserID == "" {
 		log.Error("user_id is required")
-		h.writeErrorResponse(w, http.StatusBadRequest, "USER_ID_REQUIRED", "user_id is required")
+		h.resp.Error(w, r, http.StatusBadRequest, "USER_ID_REQUIRED", "user_id is required")
 		return
 	}
 
-	if !strings.HasPrefix(req.UserID, "u") {
+	if _, err := models.ParseUserID(req.UserID); err != nil {
 		log.Error("invalid user_id format", slog.String("user_id", req.UserID))
-		h.writeErrorResponse(w, http.StatusBadRequest, "INVALID_USER_ID", "user_id must start with 'u'")
+		h.resp.Error(w, r, http.StatusBadRequest, "INVALID_USER_ID", "invalid user_id format")
 		return
 	}
 
-	user, err := h.userService.SetUserActiveStatus(r.Context(), req.IsActive, req.UserID)
+	user, handover, err := h.userService.SetUserActiveStatus(r.Context(), req.IsActive, req.UserID, req.ReassignOpenReviews)
 	if err != nil {
 		log.Error("failed to set user active status", sl.Err(err))
+		h.resp.Fail(w, r, err, "failed to set user active status")
+		return
+	}
+
+	response := SetIsActiveResponse{
+		User:           user,
+		ReviewHandover: handover,
+	}
+
+	h.resp.JSON(w, r, http.StatusOK, response)
+	log.Info("user active status updated successfully")
+}
+
+func (h *UserHandler) SetAuthorOnly(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.user.setAuthorOnly"
+
+	log := h.log.With(
+		slog.String("op", op),
+	)
+
+	var req SetAuthorOnlyRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("invalid request body", sl.Err(err))
+		h.resp.Error(w, r, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+		return
+	}
+
+	if req.UserID == "" {
+		log.Error("user_id is required")
+		h.resp.Error(w, r, http.StatusBadRequest, "USER_ID_REQUIRED", "user_id is required")
+		return
+	}
+
+	if _, err := models.ParseUserID(req.UserID); err != nil {
+		log.Error("invalid user_id format", slog.String("user_id", req.UserID))
+		h.resp.Error(w, r, http.StatusBadRequest, "INVALID_USER_ID", "invalid user_id format")
+		return
+	}
+
+	user, err := h.userService.SetAuthorOnly(r.Context(), req.UserID, req.AuthorOnly, req.Until)
+	if err != nil {
+		log.Error("failed to set author-only status", sl.Err(err))
+
+		if errors.Is(err, apperrors.ErrInvalidAuthorOnly) {
+			h.resp.Error(w, r, http.StatusBadRequest, "INVALID_UNTIL", "until must be in the future")
+			return
+		}
+		h.resp.Fail(w, r, err, "failed to set author-only status")
+		return
+	}
+
+	h.resp.JSON(w, r, http.StatusOK, SetAuthorOnlyResponse{User: user})
+	log.Info("user author-only status updated")
+}
+
+func (h *UserHandler) SetIsActiveBatch(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.user.setIsActiveBatch"
+
+	log := h.log.With(
+		slog.String("op", op),
+	)
+
+	var req SetIsActiveBatchRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("invalid request body", sl.Err(err))
+		h.resp.Error(w, r, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+		return
+	}
+
+	result, err := h.userService.SetUsersActiveStatus(r.Context(), req.IsActive, req.UserIDs)
+	if err != nil {
+		log.Error("failed to set users active status", sl.Err(err))
 
 		switch {
-		case errors.Is(err, apperrors.ErrUserNotFound):
-			h.writeErrorResponse(w, http.StatusNotFound, "NOT_FOUND", "resource not found")
-		case errors.Is(err, apperrors.ErrInvalidUserID):
-			h.writeErrorResponse(w, http.StatusBadRequest, "INVALID_USER_ID", "invalid user_id format")
+		case errors.Is(err, apperrors.ErrUserIDsRequired):
+			h.resp.Error(w, r, http.StatusBadRequest, "USER_IDS_REQUIRED", "user_ids must not be empty")
+		case errors.Is(err, apperrors.ErrBatchTooLarge):
+			h.resp.Error(w, r, http.StatusBadRequest, "BATCH_TOO_LARGE",
+				"at most %d user_ids are allowed per request", service.MaxUsersPerBatch)
 		default:
-			h.writeErrorResponse(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to set user active status")
+			h.resp.Fail(w, r, err, "failed to set users active status")
 		}
 		return
 	}
 
-	response := SetIsActiveResponse{
-		User: user,
+	response := SetIsActiveBatchResponse{
+		Updated: result.Updated,
+		Failed:  result.Failed,
 	}
 
-	h.writeJSON(w, http.StatusOK, response)
-	log.Info("user active status updated successfully")
+	h.resp.JSON(w, r, http.StatusOK, response)
+	log.Info("users active status updated",
+		slog.Int("updated", len(result.Updated)),
+		slog.Int("failed", len(result.Failed)))
 }
 
 func (h *UserHandler) GetReview(w http.ResponseWriter, r *http.Request) {
@@ -113,25 +248,51 @@ func (h *UserHandler) GetReview(w http.ResponseWriter, r *http.Request) {
 	userID := r.URL.Query().Get("user_id")
 	if userID == "" {
 		log.Error("user_id is required")
-		h.writeErrorResponse(w, http.StatusBadRequest, "USER_ID_REQUIRED", "user_id query parameter is required")
+		h.resp.Error(w, r, http.StatusBadRequest, "USER_ID_REQUIRED", "user_id query parameter is required")
 		return
 	}
 
-	if !strings.HasPrefix(userID, "u") {
+	if _, err := models.ParseUserID(userID); err != nil {
 		log.Error("invalid user_id format", slog.String("user_id", userID))
-		h.writeErrorResponse(w, http.StatusBadRequest, "INVALID_USER_ID", "user_id must start with 'u'")
+		h.resp.Error(w, r, http.StatusBadRequest, "INVALID_USER_ID", "invalid user_id format")
 		return
 	}
 
-	prs, err := h.userService.GetUserReview(r.Context(), userID)
+	var (
+		prs     []models.PullRequestShort
+		changed *bool
+		err     error
+	)
+
+	if waitParam := r.URL.Query().Get("wait"); waitParam != "" {
+		wait, parseErr := time.ParseDuration(waitParam)
+		if parseErr != nil {
+			log.Error("invalid wait duration", sl.Err(parseErr))
+			h.resp.Error(w, r, http.StatusBadRequest, "INVALID_WAIT",
+				"wait must be a duration up to %s", service.MaxReviewWait)
+			return
+		}
+
+		var didChange bool
+		prs, didChange, err = h.userService.WaitUserReview(r.Context(), userID, wait)
+		changed = &didChange
+	} else {
+		prs, err = h.userService.GetUserReview(r.Context(), userID)
+	}
+
 	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			return
+		}
+
 		log.Error("failed to get user reviews", sl.Err(err))
 
 		switch {
-		case errors.Is(err, apperrors.ErrInvalidUserID):
-			h.writeErrorResponse(w, http.StatusBadRequest, "INVALID_USER_ID", "invalid user_id format")
+		case errors.Is(err, apperrors.ErrInvalidWait):
+			h.resp.Error(w, r, http.StatusBadRequest, "INVALID_WAIT",
+				"wait must be a duration up to %s", service.MaxReviewWait)
 		default:
-			h.writeErrorResponse(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to get user reviews")
+			h.resp.Fail(w, r, err, "failed to get user reviews")
 		}
 		return
 	}
@@ -139,33 +300,201 @@ func (h *UserHandler) GetReview(w http.ResponseWriter, r *http.Request) {
 	response := GetReviewResponse{
 		UserID:       userID,
 		PullRequests: prs,
+		Changed:      changed,
 	}
 
-	h.writeJSON(w, http.StatusOK, response)
+	h.resp.Selected(w, r, http.StatusOK, response)
 	log.Info("user reviews retrieved successfully",
 		slog.Int("pull_request_count", len(prs)))
 }
 
-func (h *UserHandler) writeJSON(w http.ResponseWriter, status int, data interface{}) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	if err := json.NewEncoder(w).Encode(data); err != nil {
-		h.log.Error("failed to encode JSON response", sl.Err(err))
+func (h *UserHandler) MyReviews(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.user.myReviews"
+
+	log := h.log.With(
+		slog.String("op", op),
+	)
+
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		log.Error("caller identity is missing")
+		h.resp.Error(w, r, http.StatusUnauthorized, "UNAUTHORIZED", "caller identity is required")
+		return
+	}
+
+	reviews, err := h.userService.GetMyReviews(r.Context(), userID)
+	if err != nil {
+		log.Error("failed to get review queue", sl.Err(err))
+		h.resp.Fail(w, r, err, "failed to get review queue")
+		return
+	}
+
+	response := MyReviewsResponse{
+		UserID:  userID,
+		Reviews: reviews,
 	}
+
+	h.resp.Selected(w, r, http.StatusOK, response)
+	log.Info("review queue retrieved successfully",
+		slog.Int("pull_request_count", len(reviews)))
 }
 
-func (h *UserHandler) writeErrorResponse(w http.ResponseWriter, status int, code, message string) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
+func (h *UserHandler) ReorderQueue(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.user.reorderQueue"
+
+	log := h.log.With(
+		slog.String("op", op),
+	)
 
-	errorResp := UserErrorResponse{
-		Error: UserErrorDetail{
-			Code:    code,
-			Message: message,
-		},
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		log.Error("caller identity is missing")
+		h.resp.Error(w, r, http.StatusUnauthorized, "UNAUTHORIZED", "caller identity is required")
+		return
+	}
+
+	var req ReorderQueueRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("invalid request body", sl.Err(err))
+		h.resp.Error(w, r, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+		return
+	}
+
+	reviews, err := h.userService.ReorderQueue(r.Context(), userID, req.PullRequestIDs)
+	if err != nil {
+		log.Error("failed to reorder review queue", sl.Err(err))
+
+		switch {
+		case errors.Is(err, apperrors.ErrInvalidQueueOrder):
+			h.resp.Error(w, r, http.StatusBadRequest, "INVALID_QUEUE_ORDER", "pull request IDs must be non-empty and unique")
+		case errors.Is(err, apperrors.ErrReviewerNotAssigned):
+			h.resp.Error(w, r, http.StatusNotFound, "NOT_IN_QUEUE", "pull request is not in the review queue")
+		default:
+			h.resp.Fail(w, r, err, "failed to reorder review queue")
+		}
+		return
+	}
+
+	h.resp.JSON(w, r, http.StatusOK, MyReviewsResponse{
+		UserID:  userID,
+		Reviews: reviews,
+	})
+	log.Info("review queue reordered successfully",
+		slog.Int("pull_request_count", len(reviews)))
+}
+
+func (h *UserHandler) GetUser(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.user.GetUser"
+
+	log := h.log.With(
+		slog.String("op", op),
+	)
+
+	userID := r.URL.Query().Get("user_id")
+	if userID == "" {
+		log.Error("user_id is required")
+		h.resp.Error(w, r, http.StatusBadRequest, "USER_ID_REQUIRED", "user_id query parameter is required")
+		return
+	}
+
+	summary, err := h.userService.GetUserSummary(r.Context(), userID)
+	if err != nil {
+		log.Error("failed to get user", sl.Err(err))
+		h.resp.Fail(w, r, err, "failed to get user")
+		return
+	}
+
+	h.resp.JSON(w, r, http.StatusOK, GetUserResponse{User: summary})
+	log.Info("user retrieved successfully")
+}
+
+func (h *UserHandler) Forecast(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.user.Forecast"
+
+	log := h.log.With(
+		slog.String("op", op),
+	)
+
+	userID := r.URL.Query().Get("user_id")
+	if userID == "" {
+		log.Error("user_id is required")
+		h.resp.Error(w, r, http.StatusBadRequest, "USER_ID_REQUIRED", "user_id query parameter is required")
+		return
 	}
 
-	if err := json.NewEncoder(w).Encode(errorResp); err != nil {
-		h.log.Error("failed to encode error response", sl.Err(err))
+	forecast, err := h.userService.GetReviewForecast(r.Context(), userID)
+	if err != nil {
+		log.Error("failed to get review forecast", sl.Err(err))
+		h.resp.Fail(w, r, err, "failed to get review forecast")
+		return
 	}
+
+	h.resp.JSON(w, r, http.StatusOK, ForecastResponse{Forecast: forecast})
+	log.Info("review forecast returned successfully",
+		slog.Float64("expected_load", forecast.ExpectedLoad))
+}
+
+func (h *UserHandler) GetSettings(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.user.GetSettings"
+
+	log := h.log.With(
+		slog.String("op", op),
+	)
+
+	userID := r.URL.Query().Get("user_id")
+	if userID == "" {
+		log.Error("user_id is required")
+		h.resp.Error(w, r, http.StatusBadRequest, "USER_ID_REQUIRED", "user_id query parameter is required")
+		return
+	}
+
+	settings, etag, err := h.userService.GetUserSettings(r.Context(), userID)
+	if err != nil {
+		log.Error("failed to get user settings", sl.Err(err))
+		h.resp.Fail(w, r, err, "failed to get user settings")
+		return
+	}
+
+	h.resp.Tagged(w, r, http.StatusOK, etag, settings)
+}
+
+// PatchSettings applies an RFC 7396 merge patch to the user's settings.
+// An If-Match header makes the change conditional on the entity tag.
+func (h *UserHandler) PatchSettings(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.user.PatchSettings"
+
+	log := h.log.With(
+		slog.String("op", op),
+	)
+
+	userID := r.URL.Query().Get("user_id")
+	if userID == "" {
+		log.Error("user_id is required")
+		h.resp.Error(w, r, http.StatusBadRequest, "USER_ID_REQUIRED", "user_id query parameter is required")
+		return
+	}
+
+	patch, err := io.ReadAll(r.Body)
+	if err != nil {
+		log.Error("failed to read request body", sl.Err(err))
+		h.resp.Error(w, r, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+		return
+	}
+
+	settings, etag, err := h.userService.PatchUserSettings(r.Context(), userID, patch, r.Header.Get("If-Match"))
+	if err != nil {
+		log.Error("failed to patch user settings", sl.Err(err))
+
+		switch {
+		case errors.Is(err, apperrors.ErrUserAnonymized):
+			h.resp.Error(w, r, http.StatusConflict, "USER_ANONYMIZED", "anonymized user cannot be renamed or given a profile")
+		default:
+			h.resp.Fail(w, r, err, "failed to patch user settings")
+		}
+		return
+	}
+
+	h.resp.Tagged(w, r, http.StatusOK, etag, settings)
+	log.Info("user settings patched successfully")
 }
@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"pull-request-assigner/internal/apperrors"
+	"pull-request-assigner/internal/lib/mergepatch"
+	"testing"
+)
+
+func TestUserHandlerErrors(t *testing.T) {
+	mock := &reviewerDirectoryMock{}
+	h := NewUserHandler(mock, discardLogger())
+
+	runErrorCases(t, &mock.mockBase, []errorCase{
+		{name: "set active invalid body", serve: h.SetIsActive, target: "/users/setIsActive", body: "{",
+			status: http.StatusBadRequest, code: "INVALID_REQUEST"},
+		{name: "set active missing user", serve: h.SetIsActive, target: "/users/setIsActive", body: `{"is_active":false}`,
+			status: http.StatusBadRequest, code: "USER_ID_REQUIRED"},
+		{name: "set active invalid user", serve: h.SetIsActive, target: "/users/setIsActive", body: `{"user_id":"alice"}`,
+			status: http.StatusBadRequest, code: "INVALID_USER_ID"},
+		{name: "set active not found", serve: h.SetIsActive, target: "/users/setIsActive", body: `{"user_id":"u1"}`,
+			err: apperrors.ErrUserNotFound, status: http.StatusNotFound, code: "NOT_FOUND", called: "SetUserActiveStatus"},
+		{name: "set active internal", serve: h.SetIsActive, target: "/users/setIsActive", body: `{"user_id":"u1"}`,
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "SetUserActiveStatus"},
+
+		{name: "author only invalid body", serve: h.SetAuthorOnly, target: "/users/setAuthorOnly", body: "{",
+			status: http.StatusBadRequest, code: "INVALID_REQUEST"},
+		{name: "author only missing user", serve: h.SetAuthorOnly, target: "/users/setAuthorOnly", body: `{"author_only":true}`,
+			status: http.StatusBadRequest, code: "USER_ID_REQUIRED"},
+		{name: "author only invalid user", serve: h.SetAuthorOnly, target: "/users/setAuthorOnly", body: `{"user_id":"alice"}`,
+			status: http.StatusBadRequest, code: "INVALID_USER_ID"},
+		{name: "author only past until", serve: h.SetAuthorOnly, target: "/users/setAuthorOnly", body: `{"user_id":"u1","author_only":true,"until":"2020-01-01T00:00:00Z"}`,
+			err: apperrors.ErrInvalidAuthorOnly, status: http.StatusBadRequest, code: "INVALID_UNTIL", called: "SetAuthorOnly"},
+		{name: "author only not found", serve: h.SetAuthorOnly, target: "/users/setAuthorOnly", body: `{"user_id":"u1","author_only":true}`,
+			err: apperrors.ErrUserNotFound, status: http.StatusNotFound, code: "NOT_FOUND", called: "SetAuthorOnly"},
+		{name: "author only internal", serve: h.SetAuthorOnly, target: "/users/setAuthorOnly", body: `{"user_id":"u1","author_only":true}`,
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "SetAuthorOnly"},
+
+		{name: "batch invalid body", serve: h.SetIsActiveBatch, target: "/users/setIsActiveBatch", body: "{",
+			status: http.StatusBadRequest, code: "INVALID_REQUEST"},
+		{name: "batch empty", serve: h.SetIsActiveBatch, target: "/users/setIsActiveBatch", body: `{"user_ids":[]}`,
+			err: apperrors.ErrUserIDsRequired, status: http.StatusBadRequest, code: "USER_IDS_REQUIRED", called: "SetUsersActiveStatus"},
+		{name: "batch too large", serve: h.SetIsActiveBatch, target: "/users/setIsActiveBatch", body: `{"user_ids":["u1"]}`,
+			err: apperrors.ErrBatchTooLarge, status: http.StatusBadRequest, code: "BATCH_TOO_LARGE", called: "SetUsersActiveStatus"},
+		{name: "batch invalid user", serve: h.SetIsActiveBatch, target: "/users/setIsActiveBatch", body: `{"user_ids":["alice"]}`,
+			err: apperrors.ErrInvalidUserID, status: http.StatusBadRequest, code: "INVALID_USER_ID", called: "SetUsersActiveStatus"},
+		{name: "batch internal", serve: h.SetIsActiveBatch, target: "/users/setIsActiveBatch", body: `{"user_ids":["u1"]}`,
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "SetUsersActiveStatus"},
+
+		{name: "review missing user", serve: h.GetReview, method: http.MethodGet, target: "/users/getReview",
+			status: http.StatusBadRequest, code: "USER_ID_REQUIRED"},
+		{name: "review invalid user", serve: h.GetReview, method: http.MethodGet, target: "/users/getReview?user_id=alice",
+			status: http.StatusBadRequest, code: "INVALID_USER_ID"},
+		{name: "review invalid wait", serve: h.GetReview, method: http.MethodGet, target: "/users/getReview?user_id=u1&wait=soon",
+			status: http.StatusBadRequest, code: "INVALID_WAIT"},
+		{name: "review wait too long", serve: h.GetReview, method: http.MethodGet, target: "/users/getReview?user_id=u1&wait=1h",
+			err: apperrors.ErrInvalidWait, status: http.StatusBadRequest, code: "INVALID_WAIT", called: "WaitUserReview"},
+		{name: "review not found", serve: h.GetReview, method: http.MethodGet, target: "/users/getReview?user_id=u1",
+			err: apperrors.ErrUserNotFound, status: http.StatusNotFound, code: "NOT_FOUND", called: "GetUserReview"},
+		{name: "review internal", serve: h.GetReview, method: http.MethodGet, target: "/users/getReview?user_id=u1",
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "GetUserReview"},
+
+		{name: "my reviews without identity", serve: h.MyReviews, method: http.MethodGet, target: "/users/myReviews",
+			status: http.StatusUnauthorized, code: "UNAUTHORIZED"},
+		{name: "my reviews not found", serve: h.MyReviews, method: http.MethodGet, target: "/users/myReviews", userID: "u1",
+			err: apperrors.ErrUserNotFound, status: http.StatusNotFound, code: "NOT_FOUND", called: "GetMyReviews"},
+		{name: "my reviews internal", serve: h.MyReviews, method: http.MethodGet, target: "/users/myReviews", userID: "u1",
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "GetMyReviews"},
+
+		{name: "reorder queue without identity", serve: h.ReorderQueue, target: "/users/reorderQueue", body: `{"pull_request_ids":["pr-1"]}`,
+			status: http.StatusUnauthorized, code: "UNAUTHORIZED"},
+		{name: "reorder queue invalid body", serve: h.ReorderQueue, target: "/users/reorderQueue", body: "{", userID: "u1",
+			status: http.StatusBadRequest, code: "INVALID_REQUEST"},
+		{name: "reorder queue repeated PR", serve: h.ReorderQueue, target: "/users/reorderQueue", body: `{"pull_request_ids":["pr-1","pr-1"]}`, userID: "u1",
+			err: apperrors.ErrInvalidQueueOrder, status: http.StatusBadRequest, code: "INVALID_QUEUE_ORDER", called: "ReorderQueue"},
+		{name: "reorder queue foreign PR", serve: h.ReorderQueue, target: "/users/reorderQueue", body: `{"pull_request_ids":["pr-9"]}`, userID: "u1",
+			err: apperrors.ErrReviewerNotAssigned, status: http.StatusNotFound, code: "NOT_IN_QUEUE", called: "ReorderQueue"},
+		{name: "reorder queue internal", serve: h.ReorderQueue, target: "/users/reorderQueue", body: `{"pull_request_ids":[]}`, userID: "u1",
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "ReorderQueue"},
+
+		{name: "get user missing user", serve: h.GetUser, method: http.MethodGet, target: "/users/get",
+			status: http.StatusBadRequest, code: "USER_ID_REQUIRED"},
+		{name: "get user invalid user", serve: h.GetUser, method: http.MethodGet, target: "/users/get?user_id=x",
+			err: apperrors.ErrInvalidUserID, status: http.StatusBadRequest, code: "INVALID_USER_ID", called: "GetUserSummary"},
+		{name: "get user not found", serve: h.GetUser, method: http.MethodGet, target: "/users/get?user_id=u404",
+			err: apperrors.ErrUserNotFound, status: http.StatusNotFound, code: "NOT_FOUND", called: "GetUserSummary"},
+		{name: "get user internal", serve: h.GetUser, method: http.MethodGet, target: "/users/get?user_id=u1",
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "GetUserSummary"},
+
+		{name: "forecast missing user", serve: h.Forecast, method: http.MethodGet, target: "/users/forecast",
+			status: http.StatusBadRequest, code: "USER_ID_REQUIRED"},
+		{name: "forecast invalid user", serve: h.Forecast, method: http.MethodGet, target: "/users/forecast?user_id=x",
+			err: apperrors.ErrInvalidUserID, status: http.StatusBadRequest, code: "INVALID_USER_ID", called: "GetReviewForecast"},
+		{name: "forecast not found", serve: h.Forecast, method: http.MethodGet, target: "/users/forecast?user_id=u9",
+			err: apperrors.ErrUserNotFound, status: http.StatusNotFound, code: "NOT_FOUND", called: "GetReviewForecast"},
+		{name: "forecast internal", serve: h.Forecast, method: http.MethodGet, target: "/users/forecast?user_id=u1",
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "GetReviewForecast"},
+
+		{name: "settings missing user", serve: h.GetSettings, method: http.MethodGet, target: "/users/settings",
+			status: http.StatusBadRequest, code: "USER_ID_REQUIRED"},
+		{name: "settings not found", serve: h.GetSettings, method: http.MethodGet, target: "/users/settings?user_id=u1",
+			err: apperrors.ErrUserNotFound, status: http.StatusNotFound, code: "NOT_FOUND", called: "GetUserSettings"},
+		{name: "patch settings missing user", serve: h.PatchSettings, method: http.MethodPatch, target: "/users/settings", body: `{"username":"Al"}`,
+			status: http.StatusBadRequest, code: "USER_ID_REQUIRED"},
+		{name: "patch settings invalid patch", serve: h.PatchSettings, method: http.MethodPatch, target: "/users/settings?user_id=u1", body: `{"team_name":"QA"}`,
+			err: &mergepatch.InvalidPatchError{Reason: `unknown field "team_name"`}, status: http.StatusBadRequest, code: "INVALID_PATCH", called: "PatchUserSettings"},
+		{name: "patch settings stale", serve: h.PatchSettings, method: http.MethodPatch, target: "/users/settings?user_id=u1", body: `{"username":"Al"}`,
+			err: apperrors.ErrPreconditionFailed, status: http.StatusPreconditionFailed, code: "PRECONDITION_FAILED", called: "PatchUserSettings"},
+		{name: "patch settings username required", serve: h.PatchSettings, method: http.MethodPatch, target: "/users/settings?user_id=u1", body: `{"username":""}`,
+			err: apperrors.ErrUsernameRequired, status: http.StatusBadRequest, code: "USERNAME_REQUIRED", called: "PatchUserSettings"},
+		{name: "patch settings invalid daily cap", serve: h.PatchSettings, method: http.MethodPatch, target: "/users/settings?user_id=u1", body: `{"max_daily_assignments":-1}`,
+			err: apperrors.ErrInvalidDailyCap, status: http.StatusBadRequest, code: "INVALID_DAILY_CAP", called: "PatchUserSettings"},
+		{name: "patch settings invalid profile", serve: h.PatchSettings, method: http.MethodPatch, target: "/users/settings?user_id=u1", body: `{"locale":"english"}`,
+			err: apperrors.ErrInvalidProfile, status: http.StatusBadRequest, code: "INVALID_PROFILE", called: "PatchUserSettings"},
+		{name: "patch settings anonymized", serve: h.PatchSettings, method: http.MethodPatch, target: "/users/settings?user_id=u1", body: `{"username":"Al"}`,
+			err: apperrors.ErrUserAnonymized, status: http.StatusConflict, code: "USER_ANONYMIZED", called: "PatchUserSettings"},
+		{name: "patch settings internal", serve: h.PatchSettings, method: http.MethodPatch, target: "/users/settings?user_id=u1", body: `{"username":"Al"}`,
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "PatchUserSettings"},
+	})
+}
+
+func TestUserHandlerCanceledWaitWritesNothing(t *testing.T) {
+	mock := &reviewerDirectoryMock{mockBase{err: context.Canceled}}
+	h := NewUserHandler(mock, discardLogger())
+
+	req := httptest.NewRequest(http.MethodGet, "/users/getReview?user_id=u1&wait=10s", nil)
+	rec := httptest.NewRecorder()
+
+	h.GetReview(rec, req)
+
+	if rec.Body.Len() != 0 {
+		t.Fatalf("expected no response body for a canceled wait, got %s", rec.Body.String())
+	}
+}
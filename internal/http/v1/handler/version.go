@@ -0,0 +1,27 @@
+package handler
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"pull-request-assigner/internal/lib/buildinfo"
+	"pull-request-assigner/internal/lib/logger/sl"
+)
+
+type VersionHandler struct {
+	log *slog.Logger
+}
+
+func NewVersionHandler(log *slog.Logger) *VersionHandler {
+	return &VersionHandler{
+		log: log,
+	}
+}
+
+func (h *VersionHandler) GetVersion(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(buildinfo.Get()); err != nil {
+		h.log.Error("failed to encode JSON response", slog.String("op", "handler.version.GetVersion"), sl.Err(err))
+	}
+}
@@ -0,0 +1,185 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"pull-request-assigner/internal/domain/models"
+	"pull-request-assigner/internal/http/httpio"
+	"pull-request-assigner/internal/lib/logger/sl"
+)
+
+type (
+	CreateWebhookRequest struct {
+		TeamName string   `json:"team_name"`
+		URL      string   `json:"url"`
+		Secret   string   `json:"secret"`
+		Events   []string `json:"events"`
+	}
+
+	UpdateWebhookRequest struct {
+		ID       int64     `json:"id"`
+		URL      *string   `json:"url"`
+		Secret   *string   `json:"secret"`
+		Events   *[]string `json:"events"`
+		IsActive *bool     `json:"is_active"`
+	}
+
+	DeleteWebhookRequest struct {
+		ID int64 `json:"id"`
+	}
+
+	WebhookResponse struct {
+		Webhook *models.TeamWebhook `json:"webhook"`
+	}
+
+	ListWebhooksResponse struct {
+		TeamName string               `json:"team_name"`
+		Webhooks []models.TeamWebhook `json:"webhooks"`
+	}
+
+	DeleteWebhookResponse struct {
+		ID      int64 `json:"id"`
+		Deleted bool  `json:"deleted"`
+	}
+
+	WebhookFailuresResponse struct {
+		TeamName   string                         `json:"team_name,omitempty"`
+		Deliveries []models.StoredWebhookDelivery `json:"deliveries"`
+	}
+)
+
+type WebhookManager interface {
+	CreateWebhook(ctx context.Context, teamName string, callbackURL string, secret string, eventNames []string) (*models.TeamWebhook, error)
+	ListWebhooks(ctx context.Context, teamName string) ([]models.TeamWebhook, error)
+	UpdateWebhook(ctx context.Context, id int64, update models.TeamWebhookUpdate) (*models.TeamWebhook, error)
+	DeleteWebhook(ctx context.Context, id int64) error
+	ListFailedDeliveries(ctx context.Context, teamName string) ([]models.StoredWebhookDelivery, error)
+}
+
+type WebhookHandler struct {
+	webhookService WebhookManager
+	log            *slog.Logger
+	resp           *httpio.Responder
+}
+
+func NewWebhookHandler(webhookService WebhookManager, log *slog.Logger) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+		log:            log,
+		resp:           httpio.NewResponder(log),
+	}
+}
+
+func (h *WebhookHandler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.webhook.CreateWebhook"
+
+	log := h.log.With(slog.String("op", op))
+
+	var req CreateWebhookRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("invalid request body", sl.Err(err))
+		h.resp.Error(w, r, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+		return
+	}
+
+	hook, err := h.webhookService.CreateWebhook(r.Context(), req.TeamName, req.URL, req.Secret, req.Events)
+	if err != nil {
+		log.Error("failed to create team webhook", sl.Err(err))
+		h.resp.Fail(w, r, err, "failed to create team webhook")
+		return
+	}
+
+	h.resp.JSON(w, r, http.StatusCreated, WebhookResponse{Webhook: hook})
+	log.Info("team webhook created successfully")
+}
+
+func (h *WebhookHandler) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.webhook.ListWebhooks"
+
+	log := h.log.With(slog.String("op", op))
+
+	teamName := r.URL.Query().Get("team_name")
+
+	hooks, err := h.webhookService.ListWebhooks(r.Context(), teamName)
+	if err != nil {
+		log.Error("failed to list team webhooks", sl.Err(err))
+		h.resp.Fail(w, r, err, "failed to list team webhooks")
+		return
+	}
+
+	h.resp.JSON(w, r, http.StatusOK, ListWebhooksResponse{TeamName: teamName, Webhooks: hooks})
+}
+
+func (h *WebhookHandler) UpdateWebhook(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.webhook.UpdateWebhook"
+
+	log := h.log.With(slog.String("op", op))
+
+	var req UpdateWebhookRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("invalid request body", sl.Err(err))
+		h.resp.Error(w, r, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+		return
+	}
+
+	hook, err := h.webhookService.UpdateWebhook(r.Context(), req.ID, models.TeamWebhookUpdate{
+		URL:      req.URL,
+		Secret:   req.Secret,
+		Events:   req.Events,
+		IsActive: req.IsActive,
+	})
+	if err != nil {
+		log.Error("failed to update team webhook", sl.Err(err))
+		h.resp.Fail(w, r, err, "failed to update team webhook")
+		return
+	}
+
+	h.resp.JSON(w, r, http.StatusOK, WebhookResponse{Webhook: hook})
+	log.Info("team webhook updated successfully")
+}
+
+func (h *WebhookHandler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.webhook.DeleteWebhook"
+
+	log := h.log.With(slog.String("op", op))
+
+	var req DeleteWebhookRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("invalid request body", sl.Err(err))
+		h.resp.Error(w, r, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+		return
+	}
+
+	if err := h.webhookService.DeleteWebhook(r.Context(), req.ID); err != nil {
+		log.Error("failed to delete team webhook", sl.Err(err))
+		h.resp.Fail(w, r, err, "failed to delete team webhook")
+		return
+	}
+
+	h.resp.JSON(w, r, http.StatusOK, DeleteWebhookResponse{ID: req.ID, Deleted: true})
+	log.Info("team webhook deleted successfully")
+}
+
+// ListFailedDeliveries lists the deliveries that were rejected or ran out of
+// attempts; team_name narrows the list to one team.
+func (h *WebhookHandler) ListFailedDeliveries(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.webhook.ListFailedDeliveries"
+
+	log := h.log.With(slog.String("op", op))
+
+	teamName := r.URL.Query().Get("team_name")
+
+	deliveries, err := h.webhookService.ListFailedDeliveries(r.Context(), teamName)
+	if err != nil {
+		log.Error("failed to list failed webhook deliveries", sl.Err(err))
+		h.resp.Error(w, r, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to list failed webhook deliveries")
+		return
+	}
+
+	h.resp.JSON(w, r, http.StatusOK, WebhookFailuresResponse{TeamName: teamName, Deliveries: deliveries})
+}
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"net/http"
+	"pull-request-assigner/internal/apperrors"
+	"testing"
+)
+
+func TestWebhookHandlerErrors(t *testing.T) {
+	mock := &webhookManagerMock{}
+	h := NewWebhookHandler(mock, discardLogger())
+
+	const (
+		createBody = `{"team_name":"backend","url":"https://chat.example.com/hook","secret":"s3cret","events":["reviewer.assigned"]}`
+		updateBody = `{"id":1,"is_active":false}`
+		deleteBody = `{"id":1}`
+	)
+
+	runErrorCases(t, &mock.mockBase, []errorCase{
+		{name: "create invalid body", serve: h.CreateWebhook, target: "/team/webhooks/create", body: "{",
+			status: http.StatusBadRequest, code: "INVALID_REQUEST"},
+		{name: "create team required", serve: h.CreateWebhook, target: "/team/webhooks/create", body: createBody,
+			err: apperrors.ErrTeamNameRequired, status: http.StatusBadRequest, code: "TEAM_NAME_REQUIRED", called: "CreateWebhook"},
+		{name: "create invalid webhook", serve: h.CreateWebhook, target: "/team/webhooks/create", body: createBody,
+			err: apperrors.ErrInvalidWebhook, status: http.StatusBadRequest, code: "INVALID_WEBHOOK", called: "CreateWebhook"},
+		{name: "create team not found", serve: h.CreateWebhook, target: "/team/webhooks/create", body: createBody,
+			err: apperrors.ErrTeamNotFound, status: http.StatusNotFound, code: "NOT_FOUND", called: "CreateWebhook"},
+		{name: "create exists", serve: h.CreateWebhook, target: "/team/webhooks/create", body: createBody,
+			err: apperrors.ErrWebhookExists, status: http.StatusConflict, code: "WEBHOOK_EXISTS", called: "CreateWebhook"},
+		{name: "create internal", serve: h.CreateWebhook, target: "/team/webhooks/create", body: createBody,
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "CreateWebhook"},
+
+		{name: "list team required", serve: h.ListWebhooks, method: http.MethodGet, target: "/team/webhooks",
+			err: apperrors.ErrTeamNameRequired, status: http.StatusBadRequest, code: "TEAM_NAME_REQUIRED", called: "ListWebhooks"},
+		{name: "list team not found", serve: h.ListWebhooks, method: http.MethodGet, target: "/team/webhooks?team_name=ghost",
+			err: apperrors.ErrTeamNotFound, status: http.StatusNotFound, code: "NOT_FOUND", called: "ListWebhooks"},
+
+		{name: "update invalid body", serve: h.UpdateWebhook, target: "/team/webhooks/update", body: "{",
+			status: http.StatusBadRequest, code: "INVALID_REQUEST"},
+		{name: "update not found", serve: h.UpdateWebhook, target: "/team/webhooks/update", body: updateBody,
+			err: apperrors.ErrWebhookNotFound, status: http.StatusNotFound, code: "NOT_FOUND", called: "UpdateWebhook"},
+		{name: "update invalid webhook", serve: h.UpdateWebhook, target: "/team/webhooks/update", body: updateBody,
+			err: apperrors.ErrInvalidWebhook, status: http.StatusBadRequest, code: "INVALID_WEBHOOK", called: "UpdateWebhook"},
+
+		{name: "delete invalid body", serve: h.DeleteWebhook, target: "/team/webhooks/delete", body: "{",
+			status: http.StatusBadRequest, code: "INVALID_REQUEST"},
+		{name: "delete not found", serve: h.DeleteWebhook, target: "/team/webhooks/delete", body: deleteBody,
+			err: apperrors.ErrWebhookNotFound, status: http.StatusNotFound, code: "NOT_FOUND", called: "DeleteWebhook"},
+		{name: "delete internal", serve: h.DeleteWebhook, target: "/team/webhooks/delete", body: deleteBody,
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "DeleteWebhook"},
+
+		{name: "failures internal", serve: h.ListFailedDeliveries, method: http.MethodGet, target: "/admin/webhooks/failures",
+			err: errUnexpected, status: http.StatusInternalServerError, code: "INTERNAL_ERROR", called: "ListFailedDeliveries"},
+	})
+}
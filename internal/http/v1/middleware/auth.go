@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"pull-request-assigner/internal/apperrors"
+	"pull-request-assigner/internal/domain/models"
+	"pull-request-assigner/internal/http/httpio"
+	"pull-request-assigner/internal/lib/actor"
+	"pull-request-assigner/internal/lib/logger/sl"
+	"slices"
+	"strings"
+)
+
+type TokenAuthenticator interface {
+	Authenticate(ctx context.Context, key string) (*models.APIToken, error)
+}
+
+// Auth checks the API key of every request against the issued tokens and the
+// scope the route needs: admin under /admin and on adminRoutes, write for
+// mutations and read otherwise. Requests without a key are rejected on admin
+// routes and pass through elsewhere unless required is set. publicRoutes
+// authenticate the caller themselves and are not checked. A request with a
+// key acts as the key's user, whatever its X-User-ID says.
+func Auth(authenticator TokenAuthenticator, required bool, log *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if slices.Contains(publicRoutes, r.URL.Path) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			key := r.Header.Get(APIKeyHeader)
+			if key == "" {
+				if requiredScope(r) == models.TokenScopeAdmin {
+					log.Warn("missing API key on admin route", slog.String("path", r.URL.Path))
+					httpio.WriteError(w, r, log, http.StatusUnauthorized, "UNAUTHORIZED", "admin routes require an API key")
+					return
+				}
+				if required {
+					log.Warn("missing API key", slog.String("path", r.URL.Path))
+					httpio.WriteError(w, r, log, http.StatusUnauthorized, "UNAUTHORIZED", "API key is required")
+					return
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			token, err := authenticator.Authenticate(r.Context(), key)
+			if err != nil {
+				if errors.Is(err, apperrors.ErrInvalidToken) {
+					httpio.WriteError(w, r, log, http.StatusUnauthorized, "UNAUTHORIZED", "invalid or expired API key")
+					return
+				}
+				log.Error("failed to authenticate API key", sl.Err(err))
+				httpio.WriteError(w, r, log, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to authenticate API key")
+				return
+			}
+
+			scope := requiredScope(r)
+			if !token.HasScope(scope) {
+				log.Warn("API key lacks scope",
+					slog.Int64("token_id", token.TokenID),
+					slog.String("scope", scope),
+					slog.String("path", r.URL.Path))
+				httpio.WriteError(w, r, log, http.StatusForbidden, "FORBIDDEN", "API key lacks the required scope")
+				return
+			}
+
+			ctx := actor.WithUserID(r.Context(), token.UserID)
+			ctx = actor.WithScopes(ctx, token.Scopes)
+			ctx = actor.WithToken(ctx, token.TokenID, token.HourlyQuota)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// adminRoutes need the admin scope outside /admin.
+var adminRoutes = []string{"/users/offboard"}
+
+// publicRoutes cannot send an API key and check the caller themselves: forges
+// sign their webhooks, and the first admin key is bootstrapped with
+// ADMIN_SECRET.
+var publicRoutes = []string{"/forge/events", "/admin/tokens/bootstrap"}
+
+func requiredScope(r *http.Request) string {
+	if r.URL.Path == "/admin" || strings.HasPrefix(r.URL.Path, "/admin/") || slices.Contains(adminRoutes, r.URL.Path) {
+		return models.TokenScopeAdmin
+	}
+	if isMutation(r.Method) {
+		return models.TokenScopeWrite
+	}
+	return models.TokenScopeRead
+}
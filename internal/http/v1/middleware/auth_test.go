@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"pull-request-assigner/internal/apperrors"
+	"pull-request-assigner/internal/domain/models"
+	"testing"
+)
+
+// authenticatorFake knows a single key with the given scopes.
+type authenticatorFake struct {
+	key    string
+	scopes []string
+}
+
+func (f *authenticatorFake) Authenticate(ctx context.Context, key string) (*models.APIToken, error) {
+	if key != f.key {
+		return nil, apperrors.ErrInvalidToken
+	}
+	return &models.APIToken{TokenID: 1, UserID: "u1", Scopes: f.scopes}, nil
+}
+
+func TestAuth(t *testing.T) {
+	authenticator := &authenticatorFake{key: "pra_read", scopes: []string{models.TokenScopeRead}}
+
+	tests := []struct {
+		name     string
+		required bool
+		method   string
+		path     string
+		key      string
+		want     int
+	}{
+		{name: "keyless read", method: http.MethodGet, path: "/team/get", want: http.StatusOK},
+		{name: "keyless mutation", method: http.MethodPost, path: "/team/add", want: http.StatusOK},
+		{name: "keyless read when required", required: true, method: http.MethodGet, path: "/team/get", want: http.StatusUnauthorized},
+		{name: "keyless admin", method: http.MethodGet, path: "/admin/tokens/list", want: http.StatusUnauthorized},
+		{name: "keyless admin route outside /admin", method: http.MethodPost, path: "/users/offboard", want: http.StatusUnauthorized},
+		{name: "keyless bootstrap", method: http.MethodPost, path: "/admin/tokens/bootstrap", want: http.StatusOK},
+		{name: "keyless forge event", required: true, method: http.MethodPost, path: "/forge/events", want: http.StatusOK},
+		{name: "read key on read", method: http.MethodGet, path: "/team/get", key: "pra_read", want: http.StatusOK},
+		{name: "read key on mutation", method: http.MethodPost, path: "/team/add", key: "pra_read", want: http.StatusForbidden},
+		{name: "read key on admin", method: http.MethodGet, path: "/admin/tokens/list", key: "pra_read", want: http.StatusForbidden},
+		{name: "unknown key", method: http.MethodGet, path: "/team/get", key: "pra_unknown", want: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := Auth(authenticator, tt.required, discardLogger())(http.HandlerFunc(okHandler))
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.key != "" {
+				req.Header.Set(APIKeyHeader, tt.key)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Fatalf("expected %d, got %d", tt.want, rec.Code)
+			}
+		})
+	}
+}
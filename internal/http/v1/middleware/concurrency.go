@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"log/slog"
+	"math"
+	"net/http"
+	"pull-request-assigner/internal/http/httpio"
+	"strconv"
+	"time"
+)
+
+// ConcurrencyLimiter bounds the number of requests executing a handler at
+// once. Requests that cannot get a slot within the queue timeout are rejected
+// with 503 and a Retry-After header instead of piling up on a slow database.
+type ConcurrencyLimiter struct {
+	sem          chan struct{}
+	queueTimeout time.Duration
+	log          *slog.Logger
+}
+
+func NewConcurrencyLimiter(limit int, queueTimeout time.Duration, log *slog.Logger) *ConcurrencyLimiter {
+	l := &ConcurrencyLimiter{
+		queueTimeout: queueTimeout,
+		log:          log,
+	}
+	if limit > 0 {
+		l.sem = make(chan struct{}, limit)
+	}
+	return l
+}
+
+func (l *ConcurrencyLimiter) Handler(next http.Handler) http.Handler {
+	if l == nil || l.sem == nil {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !l.acquire(r) {
+			l.log.Warn("concurrency limit reached, rejecting request",
+				slog.String("path", r.URL.Path),
+				slog.Int("limit", cap(l.sem)))
+			l.reject(w, r)
+			return
+		}
+		defer func() { <-l.sem }()
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (l *ConcurrencyLimiter) acquire(r *http.Request) bool {
+	select {
+	case l.sem <- struct{}{}:
+		return true
+	default:
+	}
+
+	if l.queueTimeout <= 0 {
+		return false
+	}
+
+	timer := time.NewTimer(l.queueTimeout)
+	defer timer.Stop()
+
+	select {
+	case l.sem <- struct{}{}:
+		return true
+	case <-timer.C:
+		return false
+	case <-r.Context().Done():
+		return false
+	}
+}
+
+func (l *ConcurrencyLimiter) reject(w http.ResponseWriter, r *http.Request) {
+	retryAfter := int(math.Ceil(l.queueTimeout.Seconds()))
+	if retryAfter < 1 {
+		retryAfter = 1
+	}
+
+	w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	httpio.WriteError(w, r, l.log, http.StatusServiceUnavailable, "OVERLOADED", "server is busy, retry later")
+}
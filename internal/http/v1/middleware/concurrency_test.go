@@ -0,0 +1,136 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"pull-request-assigner/internal/http/httpio"
+	"sync"
+	"testing"
+	"time"
+)
+
+// holdingHandler keeps every request in flight until release is closed and
+// reports each one that got in on started.
+func holdingHandler(started chan<- struct{}, release <-chan struct{}) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		<-release
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+// holdRequests starts n requests through h and waits until all of them are
+// executing the handler.
+func holdRequests(t *testing.T, h http.Handler, n int, started <-chan struct{}) (codes func() []int) {
+	t.Helper()
+
+	var wg sync.WaitGroup
+	results := make([]int, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/team/get", nil))
+			results[i] = rec.Code
+		}(i)
+	}
+
+	for i := 0; i < n; i++ {
+		select {
+		case <-started:
+		case <-time.After(time.Second):
+			t.Fatalf("only %d of %d requests got in", i, n)
+		}
+	}
+
+	return func() []int {
+		wg.Wait()
+		return results
+	}
+}
+
+func TestConcurrencyLimiterRejectsOverLimit(t *testing.T) {
+	const limit = 3
+
+	started := make(chan struct{}, limit+1)
+	release := make(chan struct{})
+	h := NewConcurrencyLimiter(limit, 20*time.Millisecond, discardLogger()).Handler(holdingHandler(started, release))
+
+	codes := holdRequests(t, h, limit, started)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/team/get", nil))
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 for request %d, got %d", limit+1, rec.Code)
+	}
+	if got := rec.Header().Get("Retry-After"); got != "1" {
+		t.Fatalf("expected Retry-After 1, got %q", got)
+	}
+
+	var resp httpio.ErrorResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode error response: %v", err)
+	}
+	if resp.Error.Code != "OVERLOADED" {
+		t.Fatalf("expected OVERLOADED, got %s", resp.Error.Code)
+	}
+
+	close(release)
+	for i, code := range codes() {
+		if code != http.StatusOK {
+			t.Fatalf("expected held request %d to finish with 200, got %d", i, code)
+		}
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/team/get", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected the freed slots to be reused, got %d", rec.Code)
+	}
+}
+
+func TestConcurrencyLimiterQueuesUntilSlotFrees(t *testing.T) {
+	started := make(chan struct{}, 2)
+	release := make(chan struct{})
+	h := NewConcurrencyLimiter(1, time.Second, discardLogger()).Handler(holdingHandler(started, release))
+
+	codes := holdRequests(t, h, 1, started)
+
+	queued := make(chan int, 1)
+	go func() {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/team/get", nil))
+		queued <- rec.Code
+	}()
+
+	select {
+	case <-started:
+		t.Fatal("the queued request must wait for a slot")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(release)
+	codes()
+
+	select {
+	case code := <-queued:
+		if code != http.StatusOK {
+			t.Fatalf("expected the queued request to get the freed slot, got %d", code)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("queued request did not finish")
+	}
+}
+
+func TestConcurrencyLimiterDisabled(t *testing.T) {
+	h := NewConcurrencyLimiter(0, time.Second, discardLogger()).Handler(http.HandlerFunc(okHandler))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/team/get", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected a disabled limiter to pass requests, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"pull-request-assigner/internal/lib/actor"
+)
+
+// UserIDHeader carries the caller's user_id as established by the auth
+// gateway in front of the service.
+const UserIDHeader = "X-User-ID"
+
+func Identity(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if userID := r.Header.Get(UserIDHeader); userID != "" {
+			r = r.WithContext(actor.WithUserID(r.Context(), userID))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func UserIDFromContext(ctx context.Context) (string, bool) {
+	return actor.UserID(ctx)
+}
@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"pull-request-assigner/internal/apperrors"
+	"pull-request-assigner/internal/domain/models"
+	"pull-request-assigner/internal/http/httpio"
+	"pull-request-assigner/internal/lib/actor"
+	"pull-request-assigner/internal/lib/logger/sl"
+)
+
+// ImpersonationHeader carries the key of an impersonation session opened via
+// /admin/impersonation/start.
+const ImpersonationHeader = "X-Impersonation-Key"
+
+type ImpersonationResolver interface {
+	ResolveSession(ctx context.Context, key string) (*models.ImpersonationSession, error)
+}
+
+// Impersonation replaces the caller identity with the impersonated user for
+// requests carrying an active session key. Such requests are read-only.
+func Impersonation(resolver ImpersonationResolver, log *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(ImpersonationHeader)
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			session, err := resolver.ResolveSession(r.Context(), key)
+			if err != nil {
+				if errors.Is(err, apperrors.ErrInvalidImpersonation) {
+					httpio.WriteError(w, r, log, http.StatusUnauthorized, "UNAUTHORIZED", "invalid or expired impersonation session")
+					return
+				}
+				log.Error("failed to resolve impersonation session", sl.Err(err))
+				httpio.WriteError(w, r, log, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to resolve impersonation session")
+				return
+			}
+
+			if isMutation(r.Method) {
+				log.Warn("mutation rejected during impersonation",
+					slog.Int64("session_id", session.SessionID),
+					slog.String("path", r.URL.Path))
+				httpio.WriteError(w, r, log, http.StatusForbidden, "IMPERSONATION_READ_ONLY", "impersonation sessions are read-only")
+				return
+			}
+
+			log.Info("impersonated request",
+				slog.Int64("session_id", session.SessionID),
+				slog.String("admin_id", session.AdminID),
+				slog.String("user_id", session.UserID),
+				slog.String("path", r.URL.Path))
+
+			ctx := actor.WithUserID(r.Context(), session.UserID)
+			ctx = actor.WithImpersonator(ctx, session.AdminID)
+			w.Header().Set("X-Impersonated-User", session.UserID)
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
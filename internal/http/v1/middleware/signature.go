@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"pull-request-assigner/internal/apperrors"
+	"pull-request-assigner/internal/http/httpio"
+	"pull-request-assigner/internal/lib/logger/sl"
+)
+
+// Headers of a signed admin request; see service.SignAdminRequest.
+const (
+	AdminTimestampHeader = "X-Admin-Timestamp"
+	AdminNonceHeader     = "X-Admin-Nonce"
+	AdminSignatureHeader = "X-Admin-Signature"
+)
+
+type AdminRequestVerifier interface {
+	Enabled() bool
+	Verify(ctx context.Context, method, uri, timestamp, nonce, signature string, body []byte) error
+}
+
+// AdminSignature rejects admin mutations that are unsigned, stale or
+// replayed. Reads pass through, as do all requests while signing is off.
+func AdminSignature(verifier AdminRequestVerifier, log *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !isMutation(r.Method) || !verifier.Enabled() {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				httpio.WriteError(w, r, log, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			err = verifier.Verify(r.Context(), r.Method, r.URL.RequestURI(),
+				r.Header.Get(AdminTimestampHeader), r.Header.Get(AdminNonceHeader), r.Header.Get(AdminSignatureHeader), body)
+			if err != nil {
+				switch {
+				case errors.Is(err, apperrors.ErrInvalidSignature):
+					httpio.WriteError(w, r, log, http.StatusUnauthorized, "INVALID_SIGNATURE", "admin request signature is missing or invalid")
+				case errors.Is(err, apperrors.ErrStaleRequest):
+					httpio.WriteError(w, r, log, http.StatusUnauthorized, "STALE_REQUEST", "admin request timestamp is outside the allowed window")
+				case errors.Is(err, apperrors.ErrReplayedRequest):
+					httpio.WriteError(w, r, log, http.StatusConflict, "REPLAYED_REQUEST", "admin request nonce was already used")
+				default:
+					log.Error("failed to verify admin request", sl.Err(err))
+					httpio.WriteError(w, r, log, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to verify admin request")
+				}
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
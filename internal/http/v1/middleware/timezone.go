@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"pull-request-assigner/internal/http/httpio"
+	"time"
+
+	// Embedded so ?tz= works in images without a zoneinfo database.
+	_ "time/tzdata"
+)
+
+// TimezoneParam names the IANA time zone response timestamps are written in.
+const TimezoneParam = "tz"
+
+// TimezoneHeader carries the time zone for clients that cannot add ?tz= to
+// every URL.
+const TimezoneHeader = "X-Timezone"
+
+// Timezone resolves the time zone of the response from ?tz=, or else from
+// the X-Timezone header. The zone is only ever the one the caller names:
+// without either, timestamps stay in UTC whatever the language.
+func Timezone(log *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			name := r.URL.Query().Get(TimezoneParam)
+			if name == "" {
+				name = r.Header.Get(TimezoneHeader)
+			}
+			if name == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// "Local" would expose the server's own zone.
+			loc, err := time.LoadLocation(name)
+			if err != nil || name == "Local" {
+				log.Warn("unknown time zone", slog.String("tz", name))
+				httpio.WriteError(w, r, log, http.StatusBadRequest, "INVALID_TIMEZONE", "tz must be an IANA time zone such as Europe/Moscow")
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(httpio.WithLocation(r.Context(), loc)))
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"pull-request-assigner/internal/http/httpio"
+	"testing"
+)
+
+func TestTimezone(t *testing.T) {
+	tests := []struct {
+		name     string
+		target   string
+		header   string
+		language string
+		want     string
+		status   int
+	}{
+		{name: "default", target: "/users/myReviews", status: http.StatusOK},
+		{name: "language alone keeps UTC", target: "/users/myReviews", language: "ru-RU,ru;q=0.9", status: http.StatusOK},
+		{name: "query", target: "/users/myReviews?tz=Asia/Tokyo", want: "Asia/Tokyo", status: http.StatusOK},
+		{name: "header", target: "/users/myReviews", header: "Asia/Yekaterinburg", language: "ru", want: "Asia/Yekaterinburg", status: http.StatusOK},
+		{name: "query over header", target: "/users/myReviews?tz=UTC", header: "Asia/Tokyo", want: "UTC", status: http.StatusOK},
+		{name: "unknown", target: "/users/myReviews?tz=Mars/Olympus", status: http.StatusBadRequest},
+		{name: "server zone", target: "/users/myReviews", header: "Local", status: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			h := Timezone(discardLogger())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if loc := httpio.Location(r); loc != nil {
+					got = loc.String()
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			r := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.header != "" {
+				r.Header.Set(TimezoneHeader, tt.header)
+			}
+			if tt.language != "" {
+				r.Header.Set("Accept-Language", tt.language)
+			}
+
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, r)
+
+			if rec.Code != tt.status {
+				t.Fatalf("expected %d, got %d", tt.status, rec.Code)
+			}
+			if got != tt.want {
+				t.Fatalf("expected zone %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"pull-request-assigner/internal/http/httpio"
+	"strconv"
+	"time"
+)
+
+// APIKeyHeader carries the key of the calling client.
+const APIKeyHeader = "X-API-Key"
+
+type UsageRecorder interface {
+	Record(ctx context.Context, clientID string, mutation bool) (bool, time.Duration)
+}
+
+// Usage accounts every request to the calling client and rejects requests of
+// clients over their quota with 429. It runs after Auth, so requests with a
+// key are counted against the key's own quota.
+func Usage(recorder UsageRecorder, log *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			clientID := ClientID(r)
+
+			allowed, retryAfter := recorder.Record(r.Context(), clientID, isMutation(r.Method))
+			if !allowed {
+				log.Warn("client quota exceeded",
+					slog.String("client_id", clientID),
+					slog.String("path", r.URL.Path))
+				rejectQuota(w, r, retryAfter, log)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ClientID identifies the caller by a fingerprint of its API key so raw keys
+// never end up in storage or logs. Callers without a key are told apart by
+// their X-User-ID, or by their address when they send none.
+func ClientID(r *http.Request) string {
+	key := r.Header.Get(APIKeyHeader)
+	if key == "" {
+		if userID := r.Header.Get(UserIDHeader); userID != "" {
+			return "user:" + fingerprint(userID)
+		}
+		return "ip:" + fingerprint(remoteHost(r))
+	}
+
+	return "key:" + fingerprint(key)
+}
+
+func fingerprint(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+func remoteHost(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+func isMutation(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	}
+	return true
+}
+
+func rejectQuota(w http.ResponseWriter, r *http.Request, retryAfter time.Duration, log *slog.Logger) {
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	httpio.WriteError(w, r, log, http.StatusTooManyRequests, "QUOTA_EXCEEDED", "hourly request quota exceeded")
+}
@@ -0,0 +1,105 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"pull-request-assigner/internal/http/httpio"
+	"testing"
+	"time"
+)
+
+type usageRecorderFake struct {
+	clients    []string
+	allowed    bool
+	retryAfter time.Duration
+}
+
+func (f *usageRecorderFake) Record(ctx context.Context, clientID string, mutation bool) (bool, time.Duration) {
+	f.clients = append(f.clients, clientID)
+	return f.allowed, f.retryAfter
+}
+
+func discardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func okHandler(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+}
+
+func TestUsageRejectsOverQuota(t *testing.T) {
+	recorder := &usageRecorderFake{retryAfter: 90*time.Second + time.Millisecond}
+	h := Usage(recorder, discardLogger())(http.HandlerFunc(okHandler))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/team/get", nil))
+
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429, got %d", rec.Code)
+	}
+	if got := rec.Header().Get("Retry-After"); got != "91" {
+		t.Fatalf("expected Retry-After 91, got %q", got)
+	}
+
+	var resp httpio.ErrorResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode error response: %v", err)
+	}
+	if resp.Error.Code != "QUOTA_EXCEEDED" {
+		t.Fatalf("expected QUOTA_EXCEEDED, got %s", resp.Error.Code)
+	}
+}
+
+func TestUsagePassesWithinQuota(t *testing.T) {
+	recorder := &usageRecorderFake{allowed: true}
+	h := Usage(recorder, discardLogger())(http.HandlerFunc(okHandler))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/team/get", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if rec.Header().Get("Retry-After") != "" {
+		t.Fatal("expected no Retry-After within the quota")
+	}
+}
+
+func TestClientID(t *testing.T) {
+	request := func(remoteAddr string, headers map[string]string) *http.Request {
+		r := httptest.NewRequest(http.MethodGet, "/team/get", nil)
+		r.RemoteAddr = remoteAddr
+		for name, value := range headers {
+			r.Header.Set(name, value)
+		}
+		return r
+	}
+
+	keyA := ClientID(request("10.0.0.1:1000", map[string]string{APIKeyHeader: "pra_a"}))
+	keyAOtherAddr := ClientID(request("10.0.0.2:2000", map[string]string{APIKeyHeader: "pra_a"}))
+	keyB := ClientID(request("10.0.0.1:1000", map[string]string{APIKeyHeader: "pra_b"}))
+	addr1 := ClientID(request("10.0.0.1:1000", nil))
+	addr1OtherPort := ClientID(request("10.0.0.1:3000", nil))
+	addr2 := ClientID(request("10.0.0.2:1000", nil))
+	user1 := ClientID(request("10.0.0.1:1000", map[string]string{UserIDHeader: "u1"}))
+	user2 := ClientID(request("10.0.0.1:1000", map[string]string{UserIDHeader: "u2"}))
+
+	if keyA != keyAOtherAddr || addr1 != addr1OtherPort {
+		t.Fatal("expected the same client to keep its ID")
+	}
+
+	ids := map[string]bool{}
+	for _, id := range []string{keyA, keyB, addr1, addr2, user1, user2} {
+		if ids[id] {
+			t.Fatalf("expected distinct client IDs, %s repeats", id)
+		}
+		ids[id] = true
+		if len(id) > 64 {
+			t.Fatalf("client ID %s does not fit the usage table", id)
+		}
+	}
+}
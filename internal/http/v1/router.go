@@ -3,6 +3,7 @@ package v1
 import (
 	"github.com/go-chi/chi/v5"
 	"log/slog"
+	"pull-request-assigner/internal/http/v1/middleware"
 	"pull-request-assigner/internal/http/v1/router"
 	"pull-request-assigner/internal/service"
 )
@@ -12,18 +13,61 @@ type Router interface {
 }
 
 type RouterDependencies struct {
-	TeamService        *service.TeamService
-	UserService        *service.UserService
-	PullRequestService *service.PullRequestService
-	StatsService       *service.StatsService
+	TeamService          *service.TeamService
+	UserService          *service.UserService
+	PullRequestService   *service.PullRequestService
+	StatsService         *service.StatsService
+	AdminService         *service.AdminService
+	UsageService         *service.UsageService
+	CertificationService *service.CertificationService
+	PoolService          *service.PoolService
+	PolicyService        *service.PolicyService
+	TokenService         *service.TokenService
+	ImpersonationService *service.ImpersonationService
+	WebhookService       *service.WebhookService
+	TemplateService      *service.NotificationTemplateService
+	RepairService        *service.AssignmentRepairService
+	AdminSignatures      *service.AdminSignatureService
+	BackfillService      *service.BackfillService
+	OffboardingService   *service.OffboardingService
+	ActivityService      *service.ActivityService
+	DashboardService     *service.DashboardService
+	HealthService        *service.HealthService
+	MetaService          *service.MetaService
+	ForgeEventService    *service.ForgeEventService
+	CreatePRLimiter      *middleware.ConcurrencyLimiter
+	AuthRequired         bool
+
+	// AdminEnabled mounts the /admin routes. Without it they all answer 503,
+	// so a deployment left with the default ADMIN_SECRET exposes none of them.
+	AdminEnabled bool
 }
 
 func SetupRoutes(r chi.Router, deps *RouterDependencies, log *slog.Logger) {
+	r.Use(middleware.Identity)
+	r.Use(middleware.Timezone(log))
+	r.Use(middleware.Auth(deps.TokenService, deps.AuthRequired, log))
+	r.Use(middleware.Impersonation(deps.ImpersonationService, log))
+	r.Use(middleware.Usage(deps.UsageService, log))
+
 	routers := []Router{
-		router.NewTeamRouter(deps.TeamService, log),
-		router.NewUserRouter(deps.UserService, log),
-		router.NewPullRequestRouter(deps.PullRequestService, log),
+		router.NewTeamRouter(deps.TeamService, deps.WebhookService, log),
+		router.NewUserRouter(deps.UserService, deps.OffboardingService, deps.AdminSignatures, log),
+		router.NewPullRequestRouter(deps.PullRequestService, deps.ActivityService, deps.DashboardService, deps.CreatePRLimiter, log),
 		router.NewStatsRouter(deps.StatsService, log),
+		router.NewCertificationRouter(deps.CertificationService, log),
+		router.NewPoolRouter(deps.PoolService, log),
+		router.NewPolicyRouter(deps.PolicyService, log),
+		router.NewVersionRouter(log),
+		router.NewMetaRouter(deps.MetaService, log),
+		router.NewHealthRouter(deps.HealthService, log),
+		router.NewForgeRouter(deps.ForgeEventService, log),
+	}
+
+	if deps.AdminEnabled {
+		routers = append(routers, router.NewAdminRouter(deps.AdminService, deps.UsageService, deps.TokenService, deps.ImpersonationService, deps.PullRequestService, deps.PolicyService, deps.AdminSignatures, deps.BackfillService, deps.TeamService, deps.TemplateService, deps.RepairService, deps.WebhookService, log))
+	} else {
+		routers = append(routers, router.NewDisabledAdminRouter(log))
 	}
 
 	for _, serviceRouter := range routers {
@@ -0,0 +1,128 @@
+package router
+
+import (
+	"github.com/go-chi/chi/v5"
+	"log/slog"
+	"net/http"
+	"pull-request-assigner/internal/http/v1/handler"
+	"pull-request-assigner/internal/http/v1/middleware"
+	"pull-request-assigner/internal/service"
+)
+
+type AdminRouter struct {
+	handler              *handler.AdminHandler
+	tokenHandler         *handler.TokenHandler
+	impersonationHandler *handler.ImpersonationHandler
+	rebalanceHandler     *handler.RebalanceHandler
+	freezeHandler        *handler.FreezeHandler
+	repairHandler        *handler.AssignmentRepairHandler
+	mergeWindowHandler   *handler.MergeWindowHandler
+	prStatusHandler      *handler.PRStatusHandler
+	policyHandler        *handler.PolicyHandler
+	backfillHandler      *handler.BackfillHandler
+	teamLeadHandler      *handler.TeamLeadHandler
+	reconcileHandler     *handler.ReconcileHandler
+	templateHandler      *handler.NotificationTemplateHandler
+	webhookHandler       *handler.WebhookHandler
+	signatures           func(http.Handler) http.Handler
+}
+
+func NewAdminRouter(
+	adminService *service.AdminService,
+	usageService *service.UsageService,
+	tokenService *service.TokenService,
+	impersonationService *service.ImpersonationService,
+	prService *service.PullRequestService,
+	policyService *service.PolicyService,
+	signatureService *service.AdminSignatureService,
+	backfillService *service.BackfillService,
+	teamService *service.TeamService,
+	templateService *service.NotificationTemplateService,
+	repairService *service.AssignmentRepairService,
+	webhookService *service.WebhookService,
+	log *slog.Logger,
+) *AdminRouter {
+	return &AdminRouter{
+		handler:              handler.NewAdminHandler(adminService, usageService, log),
+		tokenHandler:         handler.NewTokenHandler(tokenService, log),
+		impersonationHandler: handler.NewImpersonationHandler(impersonationService, log),
+		rebalanceHandler:     handler.NewRebalanceHandler(prService, log),
+		freezeHandler:        handler.NewFreezeHandler(prService, log),
+		repairHandler:        handler.NewAssignmentRepairHandler(repairService, log),
+		mergeWindowHandler:   handler.NewMergeWindowHandler(prService, log),
+		prStatusHandler:      handler.NewPRStatusHandler(prService, log),
+		policyHandler:        handler.NewPolicyHandler(policyService, log),
+		backfillHandler:      handler.NewBackfillHandler(backfillService, log),
+		teamLeadHandler:      handler.NewTeamLeadHandler(teamService, log),
+		reconcileHandler:     handler.NewReconcileHandler(prService, log),
+		templateHandler:      handler.NewNotificationTemplateHandler(templateService, log),
+		webhookHandler:       handler.NewWebhookHandler(webhookService, log),
+		signatures:           middleware.AdminSignature(signatureService, log),
+	}
+}
+
+func (ar *AdminRouter) SetupRoutes(r chi.Router) {
+
+	r.Route("/admin", func(r chi.Router) {
+		r.Use(ar.signatures)
+
+		r.Post("/restore", ar.handler.Restore)
+		r.Post("/anonymizeUser", ar.handler.AnonymizeUser)
+		r.Post("/simulate", ar.handler.Simulate)
+		r.Post("/membership/repair", ar.handler.RepairMembership)
+		r.Post("/rebalance", ar.rebalanceHandler.Rebalance)
+		r.Post("/freeze", ar.freezeHandler.Freeze)
+		r.Post("/unfreeze", ar.freezeHandler.Unfreeze)
+		r.Post("/assignmentRepairs/run", ar.repairHandler.RunRepairs)
+		r.Post("/mergeWindow/set", ar.mergeWindowHandler.SetMergeWindow)
+		r.Post("/prStatuses/save", ar.prStatusHandler.SaveStatus)
+		r.Post("/prStatuses/delete", ar.prStatusHandler.DeleteStatus)
+		r.Post("/prStatuses/transitions", ar.prStatusHandler.SetTransitions)
+		r.Post("/policy/update", ar.policyHandler.UpdateOrgPolicy)
+		r.Post("/backfill", ar.backfillHandler.Backfill)
+		r.Post("/teamLeads/set", ar.teamLeadHandler.SetTeamLead)
+		r.Post("/reconcile", ar.reconcileHandler.Reconcile)
+
+		r.Get("/archive", ar.handler.GetArchive)
+		r.Get("/dbcheck", ar.handler.CheckDB)
+		r.Get("/usage", ar.handler.GetUsage)
+		r.Get("/jobs", ar.handler.GetJobs)
+		r.Get("/migrations", ar.handler.GetMigrations)
+		r.Get("/membership", ar.handler.GetMembership)
+		r.Get("/freezes", ar.freezeHandler.GetFreezes)
+		r.Get("/assignmentRepairs", ar.repairHandler.GetRepairs)
+		r.Get("/mergeWindows", ar.mergeWindowHandler.GetMergeWindows)
+		r.Get("/teamLeads", ar.teamLeadHandler.GetTeamLeads)
+
+		r.Post("/tokens/bootstrap", ar.tokenHandler.BootstrapToken)
+		r.Post("/tokens/issue", ar.tokenHandler.IssueToken)
+		r.Post("/tokens/rotate", ar.tokenHandler.RotateToken)
+		r.Post("/tokens/revoke", ar.tokenHandler.RevokeToken)
+		r.Post("/tokens/setQuota", ar.tokenHandler.SetTokenQuota)
+		r.Get("/tokens/list", ar.tokenHandler.ListTokens)
+
+		r.Post("/impersonation/start", ar.impersonationHandler.StartImpersonation)
+		r.Post("/impersonation/end", ar.impersonationHandler.EndImpersonation)
+
+		r.Get("/templates", ar.templateHandler.ListTemplates)
+		r.Post("/templates/save", ar.templateHandler.SaveTemplate)
+		r.Post("/templates/delete", ar.templateHandler.DeleteTemplate)
+
+		r.Get("/webhooks/failures", ar.webhookHandler.ListFailedDeliveries)
+	})
+}
+
+// DisabledAdminRouter answers every admin route with 503 ADMIN_DISABLED; it
+// stands in for AdminRouter while ADMIN_SECRET is not configured.
+type DisabledAdminRouter struct {
+	handler *handler.AdminDisabledHandler
+}
+
+func NewDisabledAdminRouter(log *slog.Logger) *DisabledAdminRouter {
+	return &DisabledAdminRouter{handler: handler.NewAdminDisabledHandler(log)}
+}
+
+func (dr *DisabledAdminRouter) SetupRoutes(r chi.Router) {
+	r.HandleFunc("/admin", dr.handler.Reject)
+	r.HandleFunc("/admin/*", dr.handler.Reject)
+}
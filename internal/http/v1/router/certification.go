@@ -0,0 +1,28 @@
+package router
+
+import (
+	"github.com/go-chi/chi/v5"
+	"log/slog"
+	"pull-request-assigner/internal/http/v1/handler"
+	"pull-request-assigner/internal/service"
+)
+
+type CertificationRouter struct {
+	handler *handler.CertificationHandler
+}
+
+func NewCertificationRouter(certificationService *service.CertificationService, log *slog.Logger) *CertificationRouter {
+	return &CertificationRouter{
+		handler: handler.NewCertificationHandler(certificationService, log),
+	}
+}
+
+func (cr *CertificationRouter) SetupRoutes(r chi.Router) {
+
+	r.Route("/certifications", func(r chi.Router) {
+		r.Post("/grant", cr.handler.GrantCertification)
+		r.Post("/revoke", cr.handler.RevokeCertification)
+
+		r.Get("/list", cr.handler.ListCertifications)
+	})
+}
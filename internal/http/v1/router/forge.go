@@ -0,0 +1,22 @@
+package router
+
+import (
+	"github.com/go-chi/chi/v5"
+	"log/slog"
+	"pull-request-assigner/internal/http/v1/handler"
+	"pull-request-assigner/internal/service"
+)
+
+type ForgeRouter struct {
+	handler *handler.ForgeEventHandler
+}
+
+func NewForgeRouter(forgeEventService *service.ForgeEventService, log *slog.Logger) *ForgeRouter {
+	return &ForgeRouter{
+		handler: handler.NewForgeEventHandler(forgeEventService, log),
+	}
+}
+
+func (fr *ForgeRouter) SetupRoutes(r chi.Router) {
+	r.Post("/forge/events", fr.handler.HandleEvent)
+}
@@ -0,0 +1,22 @@
+package router
+
+import (
+	"github.com/go-chi/chi/v5"
+	"log/slog"
+	"pull-request-assigner/internal/http/v1/handler"
+	"pull-request-assigner/internal/service"
+)
+
+type HealthRouter struct {
+	handler *handler.HealthHandler
+}
+
+func NewHealthRouter(healthService *service.HealthService, log *slog.Logger) *HealthRouter {
+	return &HealthRouter{
+		handler: handler.NewHealthHandler(healthService, log),
+	}
+}
+
+func (hr *HealthRouter) SetupRoutes(r chi.Router) {
+	r.Get("/healthz/integrations", hr.handler.GetIntegrations)
+}
@@ -0,0 +1,25 @@
+package router
+
+import (
+	"github.com/go-chi/chi/v5"
+	"log/slog"
+	"pull-request-assigner/internal/http/v1/handler"
+	"pull-request-assigner/internal/service"
+)
+
+type MetaRouter struct {
+	handler *handler.MetaHandler
+}
+
+func NewMetaRouter(metaService *service.MetaService, log *slog.Logger) *MetaRouter {
+	return &MetaRouter{
+		handler: handler.NewMetaHandler(metaService, log),
+	}
+}
+
+func (mr *MetaRouter) SetupRoutes(r chi.Router) {
+
+	r.Route("/meta", func(r chi.Router) {
+		r.Get("/enums", mr.handler.GetEnums)
+	})
+}
@@ -0,0 +1,26 @@
+package router
+
+import (
+	"github.com/go-chi/chi/v5"
+	"log/slog"
+	"pull-request-assigner/internal/http/v1/handler"
+	"pull-request-assigner/internal/service"
+)
+
+type PolicyRouter struct {
+	handler *handler.PolicyHandler
+}
+
+func NewPolicyRouter(policyService *service.PolicyService, log *slog.Logger) *PolicyRouter {
+	return &PolicyRouter{
+		handler: handler.NewPolicyHandler(policyService, log),
+	}
+}
+
+func (pr *PolicyRouter) SetupRoutes(r chi.Router) {
+
+	r.Route("/policy", func(r chi.Router) {
+		r.Get("/org", pr.handler.GetOrgPolicy)
+		r.Get("/effective", pr.handler.GetEffectivePolicy)
+	})
+}
@@ -0,0 +1,33 @@
+package router
+
+import (
+	"github.com/go-chi/chi/v5"
+	"log/slog"
+	"pull-request-assigner/internal/http/v1/handler"
+	"pull-request-assigner/internal/service"
+)
+
+type PoolRouter struct {
+	handler *handler.PoolHandler
+}
+
+func NewPoolRouter(poolService *service.PoolService, log *slog.Logger) *PoolRouter {
+	return &PoolRouter{
+		handler: handler.NewPoolHandler(poolService, log),
+	}
+}
+
+func (pr *PoolRouter) SetupRoutes(r chi.Router) {
+
+	r.Route("/pool", func(r chi.Router) {
+		r.Post("/create", pr.handler.CreatePool)
+		r.Post("/update", pr.handler.UpdatePool)
+		r.Post("/delete", pr.handler.DeletePool)
+		r.Post("/members/add", pr.handler.AddPoolMembers)
+		r.Post("/members/remove", pr.handler.RemovePoolMembers)
+
+		r.Get("/get", pr.handler.GetPool)
+		r.Get("/list", pr.handler.ListPools)
+		r.Get("/stats", pr.handler.GetPoolStats)
+	})
+}
@@ -4,24 +4,54 @@ import (
 	"github.com/go-chi/chi/v5"
 	"log/slog"
 	"pull-request-assigner/internal/http/v1/handler"
+	"pull-request-assigner/internal/http/v1/middleware"
 	"pull-request-assigner/internal/service"
 )
 
 type PullRequestRouter struct {
-	handler *handler.PullRequestHandler
+	handler          *handler.PullRequestHandler
+	activityHandler  *handler.ActivityHandler
+	dashboardHandler *handler.DashboardHandler
+	createLimiter    *middleware.ConcurrencyLimiter
 }
 
-func NewPullRequestRouter(pullRequestService *service.PullRequestService, log *slog.Logger) *PullRequestRouter {
+func NewPullRequestRouter(
+	pullRequestService *service.PullRequestService,
+	activityService *service.ActivityService,
+	dashboardService *service.DashboardService,
+	createLimiter *middleware.ConcurrencyLimiter,
+	log *slog.Logger,
+) *PullRequestRouter {
 	return &PullRequestRouter{
-		handler: handler.NewPullRequestHandler(pullRequestService, log),
+		handler:          handler.NewPullRequestHandler(pullRequestService, log),
+		activityHandler:  handler.NewActivityHandler(activityService, log),
+		dashboardHandler: handler.NewDashboardHandler(dashboardService, log),
+		createLimiter:    createLimiter,
 	}
 }
 func (prr *PullRequestRouter) SetupRoutes(r chi.Router) {
 
 	r.Route("/pullRequest", func(r chi.Router) {
-		r.Post("/create", prr.handler.CreatePR)
+		r.With(prr.createLimiter.Handler).Post("/create", prr.handler.CreatePR)
 		r.Post("/merge", prr.handler.MergePR)
 		r.Post("/reassign", prr.handler.ReassignReviewer)
+		r.Post("/ciStatus", prr.handler.UpdateCIStatus)
+		r.Post("/setStatus", prr.handler.SetStatus)
+		r.Post("/assign", prr.handler.AssignReviewer)
+		r.Post("/unassign", prr.handler.UnassignReviewer)
+		r.Post("/setReviewerCount", prr.handler.SetReviewerCount)
+		r.Post("/delegate", prr.handler.DelegateReview)
+		r.Post("/decline", prr.handler.DeclineReview)
+		r.Post("/startReview", prr.handler.StartReview)
+		r.Post("/completeReview", prr.handler.CompleteReview)
+		r.Post("/approve", prr.handler.ApprovePR)
+
+		r.Get("/statuses", prr.handler.ListStatuses)
+		r.Get("/export", prr.handler.ExportPRs)
+		r.Get("/candidates", prr.handler.GetCandidates)
+		r.Get("/pendingAssignments", prr.handler.GetPendingAssignments)
+		r.Get("/activity", prr.activityHandler.GetPRActivity)
+		r.Get("/dashboard", prr.dashboardHandler.GetDashboard)
 	})
 
 }
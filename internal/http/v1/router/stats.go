@@ -21,5 +21,12 @@ func (sr *StatsRouter) SetupRoutes(r chi.Router) {
 
 	r.Route("/stats", func(r chi.Router) {
 		r.Get("/prs", sr.handler.GetPRStats)
+		r.Get("/cycleTime", sr.handler.GetCycleTime)
+		r.Get("/history", sr.handler.GetHistory)
+		r.Get("/capacity", sr.handler.GetCapacity)
+		r.Get("/labels", sr.handler.GetTagStats)
+		r.Get("/pairing", sr.handler.GetPairing)
+
+		r.Post("/teams", sr.handler.GetTeamsStats)
 	})
 }
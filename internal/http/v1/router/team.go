@@ -8,12 +8,14 @@ import (
 )
 
 type TeamRouter struct {
-	handler *handler.TeamHandler
+	handler  *handler.TeamHandler
+	webhooks *handler.WebhookHandler
 }
 
-func NewTeamRouter(teamService *service.TeamService, log *slog.Logger) *TeamRouter {
+func NewTeamRouter(teamService *service.TeamService, webhookService *service.WebhookService, log *slog.Logger) *TeamRouter {
 	return &TeamRouter{
-		handler: handler.NewTeamHandler(teamService, log),
+		handler:  handler.NewTeamHandler(teamService, log),
+		webhooks: handler.NewWebhookHandler(webhookService, log),
 	}
 }
 func (tr *TeamRouter) SetupRoutes(r chi.Router) {
@@ -21,8 +23,30 @@ func (tr *TeamRouter) SetupRoutes(r chi.Router) {
 	r.Route("/team", func(r chi.Router) {
 		r.Post("/add", tr.handler.CreateTeam)
 		r.Post("/deactivate", tr.handler.DeactivateTeamUsers)
+		r.Post("/update", tr.handler.UpdateTeam)
+		r.Post("/archive", tr.handler.ArchiveTeam)
+		r.Post("/members/setSecondary", tr.handler.SetSecondaryMember)
 
 		r.Get("/get", tr.handler.GetTeam)
+		r.Get("/policy/history", tr.handler.GetPolicyHistory)
+		r.Get("/changes", tr.handler.GetTeamChanges)
+
+		r.Get("/settings", tr.handler.GetSettings)
+		r.Patch("/settings", tr.handler.PatchSettings)
+
+		r.Route("/webhooks", func(r chi.Router) {
+			r.Get("/", tr.webhooks.ListWebhooks)
+			r.Post("/create", tr.webhooks.CreateWebhook)
+			r.Post("/update", tr.webhooks.UpdateWebhook)
+			r.Post("/delete", tr.webhooks.DeleteWebhook)
+		})
+	})
+
+	// Short aliases of the team webhook routes.
+	r.Route("/webhooks", func(r chi.Router) {
+		r.Post("/add", tr.webhooks.CreateWebhook)
+		r.Get("/list", tr.webhooks.ListWebhooks)
+		r.Post("/delete", tr.webhooks.DeleteWebhook)
 	})
 
 }
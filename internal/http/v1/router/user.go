@@ -3,25 +3,48 @@ package router
 import (
 	"github.com/go-chi/chi/v5"
 	"log/slog"
+	"net/http"
 	"pull-request-assigner/internal/http/v1/handler"
+	"pull-request-assigner/internal/http/v1/middleware"
 	"pull-request-assigner/internal/service"
 )
 
 type UserRouter struct {
-	handler *handler.UserHandler
+	handler            *handler.UserHandler
+	offboardingHandler *handler.OffboardingHandler
+	signatures         func(http.Handler) http.Handler
 }
 
-func NewUserRouter(userService *service.UserService, log *slog.Logger) *UserRouter {
+func NewUserRouter(
+	userService *service.UserService,
+	offboardingService *service.OffboardingService,
+	signatureService *service.AdminSignatureService,
+	log *slog.Logger,
+) *UserRouter {
 	return &UserRouter{
-		handler: handler.NewUserHandler(userService, log),
+		handler:            handler.NewUserHandler(userService, log),
+		offboardingHandler: handler.NewOffboardingHandler(offboardingService, log),
+		signatures:         middleware.AdminSignature(signatureService, log),
 	}
 }
 func (ur *UserRouter) SetupRoutes(r chi.Router) {
 
 	r.Route("/users", func(r chi.Router) {
 		r.Post("/setIsActive", ur.handler.SetIsActive)
+		r.Post("/setIsActiveBatch", ur.handler.SetIsActiveBatch)
+		r.Post("/setAuthorOnly", ur.handler.SetAuthorOnly)
 
+		// Offboarding can anonymize, so it is an admin mutation.
+		r.With(ur.signatures).Post("/offboard", ur.offboardingHandler.Offboard)
+
+		r.Get("/get", ur.handler.GetUser)
 		r.Get("/getReview", ur.handler.GetReview)
+		r.Get("/myReviews", ur.handler.MyReviews)
+		r.Post("/reorderQueue", ur.handler.ReorderQueue)
+		r.Get("/forecast", ur.handler.Forecast)
+
+		r.Get("/settings", ur.handler.GetSettings)
+		r.Patch("/settings", ur.handler.PatchSettings)
 	})
 
 }
@@ -0,0 +1,21 @@
+package router
+
+import (
+	"github.com/go-chi/chi/v5"
+	"log/slog"
+	"pull-request-assigner/internal/http/v1/handler"
+)
+
+type VersionRouter struct {
+	handler *handler.VersionHandler
+}
+
+func NewVersionRouter(log *slog.Logger) *VersionRouter {
+	return &VersionRouter{
+		handler: handler.NewVersionHandler(log),
+	}
+}
+
+func (vr *VersionRouter) SetupRoutes(r chi.Router) {
+	r.Get("/version", vr.handler.GetVersion)
+}
@@ -0,0 +1,67 @@
+package actor
+
+import "context"
+
+type contextKey string
+
+const userIDKey contextKey = "user_id"
+
+// WithUserID stores the identity of the user performing the request.
+func WithUserID(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, userIDKey, userID)
+}
+
+// UserID returns the identity of the user performing the request, if known.
+func UserID(ctx context.Context) (string, bool) {
+	userID, ok := ctx.Value(userIDKey).(string)
+	return userID, ok && userID != ""
+}
+
+const impersonatorKey contextKey = "impersonator_id"
+
+// WithImpersonator marks the request as performed by an admin on behalf of the
+// user stored with WithUserID.
+func WithImpersonator(ctx context.Context, adminID string) context.Context {
+	return context.WithValue(ctx, impersonatorKey, adminID)
+}
+
+// Impersonator returns the admin impersonating the current user, if any.
+func Impersonator(ctx context.Context) (string, bool) {
+	adminID, ok := ctx.Value(impersonatorKey).(string)
+	return adminID, ok && adminID != ""
+}
+
+const scopesKey contextKey = "token_scopes"
+
+// WithScopes stores the scopes of the API key the request was authenticated
+// with.
+func WithScopes(ctx context.Context, scopes []string) context.Context {
+	return context.WithValue(ctx, scopesKey, scopes)
+}
+
+// Scopes returns the scopes of the request's API key. ok is false for
+// requests made without a key.
+func Scopes(ctx context.Context) (scopes []string, ok bool) {
+	scopes, ok = ctx.Value(scopesKey).([]string)
+	return scopes, ok
+}
+
+const tokenKey contextKey = "token"
+
+type token struct {
+	id          int64
+	hourlyQuota *int
+}
+
+// WithToken stores the API key the request was authenticated with and its
+// hourly quota; a nil quota means the key follows the default one.
+func WithToken(ctx context.Context, tokenID int64, hourlyQuota *int) context.Context {
+	return context.WithValue(ctx, tokenKey, token{id: tokenID, hourlyQuota: hourlyQuota})
+}
+
+// Token returns the request's API key and its hourly quota. ok is false for
+// requests made without a key.
+func Token(ctx context.Context) (tokenID int64, hourlyQuota *int, ok bool) {
+	t, ok := ctx.Value(tokenKey).(token)
+	return t.id, t.hourlyQuota, ok
+}
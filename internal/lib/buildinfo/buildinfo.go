@@ -0,0 +1,70 @@
+// Package buildinfo describes the running build. Commit, BuildTime and
+// Features are injected at link time:
+//
+//	go build -ldflags "-X pull-request-assigner/internal/lib/buildinfo.Commit=$(git rev-parse HEAD) \
+//	    -X pull-request-assigner/internal/lib/buildinfo.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ) \
+//	    -X pull-request-assigner/internal/lib/buildinfo.Features=auto_merge,freeze"
+package buildinfo
+
+import (
+	"runtime"
+	"runtime/debug"
+	"strings"
+)
+
+var (
+	Commit    string
+	BuildTime string
+
+	// Features is a comma-separated list of feature flags enabled for the
+	// build.
+	Features string
+)
+
+const unknown = "unknown"
+
+type Info struct {
+	Commit    string   `json:"commit"`
+	BuildTime string   `json:"build_time"`
+	GoVersion string   `json:"go_version"`
+	Features  []string `json:"features"`
+}
+
+// Get returns the injected build info. Without ldflags it falls back to the
+// VCS stamp Go embeds when building from a checkout.
+func Get() Info {
+	info := Info{
+		Commit:    Commit,
+		BuildTime: BuildTime,
+		GoVersion: runtime.Version(),
+		Features:  make([]string, 0),
+	}
+
+	if info.Commit == "" || info.BuildTime == "" {
+		if build, ok := debug.ReadBuildInfo(); ok {
+			for _, setting := range build.Settings {
+				switch {
+				case setting.Key == "vcs.revision" && info.Commit == "":
+					info.Commit = setting.Value
+				case setting.Key == "vcs.time" && info.BuildTime == "":
+					info.BuildTime = setting.Value
+				}
+			}
+		}
+	}
+
+	if info.Commit == "" {
+		info.Commit = unknown
+	}
+	if info.BuildTime == "" {
+		info.BuildTime = unknown
+	}
+
+	for _, feature := range strings.Split(Features, ",") {
+		if feature = strings.TrimSpace(feature); feature != "" {
+			info.Features = append(info.Features, feature)
+		}
+	}
+
+	return info
+}
@@ -0,0 +1,20 @@
+// Package chaos injects database faults for staging and integration tests.
+// It is compiled in only with the chaos build tag; regular builds get no-op
+// stubs, so production binaries cannot inject faults whatever their config.
+package chaos
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrInjected is returned by statements that were chosen to fail.
+var ErrInjected = errors.New("chaos: injected database fault")
+
+// Config controls the faults. ErrorRate is the probability in [0, 1] that a
+// statement or transaction start fails with ErrInjected; Latency is added
+// before every statement.
+type Config struct {
+	ErrorRate float64
+	Latency   time.Duration
+}
@@ -0,0 +1,16 @@
+//go:build !chaos
+
+package chaos
+
+import "database/sql/driver"
+
+// Enabled reports whether the binary was built with fault injection.
+const Enabled = false
+
+// Configure is a no-op without the chaos build tag.
+func Configure(Config) {}
+
+// Wrap returns the connector unchanged without the chaos build tag.
+func Wrap(connector driver.Connector) driver.Connector {
+	return connector
+}
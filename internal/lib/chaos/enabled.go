@@ -0,0 +1,132 @@
+//go:build chaos
+
+package chaos
+
+import (
+	"context"
+	"database/sql/driver"
+	"math/rand"
+	"sync/atomic"
+	"time"
+)
+
+// Enabled reports whether the binary was built with fault injection.
+const Enabled = true
+
+var current atomic.Pointer[Config]
+
+// Configure replaces the faults injected into connections from Wrap. It can
+// be called at any time; a zero Config turns injection off.
+func Configure(cfg Config) {
+	current.Store(&cfg)
+}
+
+// Wrap makes connections from connector subject to the configured faults.
+func Wrap(connector driver.Connector) driver.Connector {
+	return &faultyConnector{Connector: connector}
+}
+
+type faultyConnector struct {
+	driver.Connector
+}
+
+func (c *faultyConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &faultyConn{Conn: conn}, nil
+}
+
+type faultyConn struct {
+	driver.Conn
+}
+
+func (c *faultyConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	if err := inject(ctx); err != nil {
+		return nil, err
+	}
+	return queryer.QueryContext(ctx, query, args)
+}
+
+func (c *faultyConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	if err := inject(ctx); err != nil {
+		return nil, err
+	}
+	return execer.ExecContext(ctx, query, args)
+}
+
+func (c *faultyConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	if err := inject(ctx); err != nil {
+		return nil, err
+	}
+	if preparer, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		return preparer.PrepareContext(ctx, query)
+	}
+	return c.Conn.Prepare(query)
+}
+
+func (c *faultyConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if err := inject(ctx); err != nil {
+		return nil, err
+	}
+	if beginner, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return beginner.BeginTx(ctx, opts)
+	}
+	return c.Conn.Begin()
+}
+
+func (c *faultyConn) Ping(ctx context.Context) error {
+	if pinger, ok := c.Conn.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *faultyConn) ResetSession(ctx context.Context) error {
+	if resetter, ok := c.Conn.(driver.SessionResetter); ok {
+		return resetter.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *faultyConn) IsValid() bool {
+	if validator, ok := c.Conn.(driver.Validator); ok {
+		return validator.IsValid()
+	}
+	return true
+}
+
+// inject sleeps for the configured latency and then fails with the
+// configured probability.
+func inject(ctx context.Context) error {
+	cfg := current.Load()
+	if cfg == nil {
+		return nil
+	}
+
+	if cfg.Latency > 0 {
+		timer := time.NewTimer(cfg.Latency)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	if cfg.ErrorRate > 0 && rand.Float64() < cfg.ErrorRate {
+		return ErrInjected
+	}
+
+	return nil
+}
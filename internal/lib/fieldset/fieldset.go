@@ -0,0 +1,182 @@
+package fieldset
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// UnknownFieldError reports a requested field that the response does not have.
+type UnknownFieldError struct {
+	Field string
+}
+
+func (e *UnknownFieldError) Error() string {
+	return "unknown field: " + e.Field
+}
+
+// Parse splits a comma-separated ?fields= value into dotted paths. An empty
+// value selects the whole response.
+func Parse(raw string) []string {
+	paths := make([]string, 0)
+	for _, path := range strings.Split(raw, ",") {
+		path = strings.TrimSpace(path)
+		if path != "" {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// Apply reduces data to the given paths. Paths use the JSON names of fields,
+// nested fields are joined with dots and arrays are filtered element-wise,
+// so "members.user_id" keeps only user_id of every member. Paths are checked
+// against the type of data, so omitted empty fields are not reported as
+// unknown.
+func Apply(data any, paths []string) (any, error) {
+	if len(paths) == 0 {
+		return data, nil
+	}
+
+	tree := make(node)
+	for _, path := range paths {
+		tree.add(strings.Split(path, "."))
+	}
+
+	if err := tree.validate(reflect.TypeOf(data), ""); err != nil {
+		return nil, err
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	return tree.apply(value), nil
+}
+
+// node is a set of selected fields; a nil child selects the field as a whole.
+type node map[string]node
+
+func (n node) add(parts []string) {
+	child, seen := n[parts[0]]
+	if len(parts) == 1 {
+		n[parts[0]] = nil
+		return
+	}
+	if seen && child == nil {
+		return
+	}
+	if child == nil {
+		child = make(node)
+		n[parts[0]] = child
+	}
+	child.add(parts[1:])
+}
+
+func (n node) validate(t reflect.Type, prefix string) error {
+	for t != nil && (t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+		t = t.Elem()
+	}
+
+	// Maps and interfaces have no fixed set of fields to check against.
+	if t == nil || t.Kind() == reflect.Map || t.Kind() == reflect.Interface {
+		return nil
+	}
+
+	if t.Kind() != reflect.Struct {
+		for name := range n {
+			return &UnknownFieldError{Field: prefix + name}
+		}
+		return nil
+	}
+
+	fields := jsonFields(t)
+	for name, child := range n {
+		field, ok := fields[name]
+		if !ok {
+			return &UnknownFieldError{Field: prefix + name}
+		}
+		if child == nil {
+			continue
+		}
+		if err := child.validate(field, prefix+name+"."); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// jsonFields maps the JSON names of the struct's fields to their types,
+// following embedded structs the way encoding/json does.
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name, _, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" {
+			embedded := f.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				for embeddedName, embeddedType := range jsonFields(embedded) {
+					if _, ok := fields[embeddedName]; !ok {
+						fields[embeddedName] = embeddedType
+					}
+				}
+				continue
+			}
+		}
+
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = f.Type
+	}
+	return fields
+}
+
+func (n node) apply(value any) any {
+	switch v := value.(type) {
+	case []any:
+		result := make([]any, 0, len(v))
+		for _, item := range v {
+			result = append(result, n.apply(item))
+		}
+		return result
+	case map[string]any:
+		result := make(map[string]any, len(n))
+		for name, child := range n {
+			field, ok := v[name]
+			if !ok {
+				continue
+			}
+			if child == nil {
+				result[name] = field
+				continue
+			}
+			result[name] = child.apply(field)
+		}
+		return result
+	}
+	return value
+}
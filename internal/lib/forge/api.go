@@ -0,0 +1,115 @@
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+const maxRateLimitRetries = 5
+
+var nextLink = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+// api fetches paginated JSON lists from a forge REST API, following Link
+// headers and waiting out rate limits.
+type api struct {
+	client    *http.Client
+	maxWait   time.Duration
+	authorize func(req *http.Request)
+}
+
+// list decodes every page starting at url into a fresh T and hands it to
+// page.
+func list[T any](ctx context.Context, a *api, url string, page func(items []T)) error {
+	for url != "" {
+		body, next, err := a.get(ctx, url)
+		if err != nil {
+			return err
+		}
+
+		var items []T
+		if err := json.Unmarshal(body, &items); err != nil {
+			return fmt.Errorf("forge: decode %s: %w", url, err)
+		}
+
+		page(items)
+		url = next
+	}
+
+	return nil
+}
+
+func (a *api) get(ctx context.Context, url string) ([]byte, string, error) {
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, "", err
+		}
+		a.authorize(req)
+
+		resp, err := a.client.Do(req)
+		if err != nil {
+			return nil, "", fmt.Errorf("forge: GET %s: %w", url, err)
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, "", fmt.Errorf("forge: GET %s: %w", url, err)
+		}
+
+		if wait, limited := rateLimitWait(resp); limited {
+			if attempt >= maxRateLimitRetries || wait > a.maxWait {
+				return nil, "", fmt.Errorf("%w: GET %s, reset in %s", ErrRateLimited, url, wait)
+			}
+
+			timer := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return nil, "", ctx.Err()
+			case <-timer.C:
+			}
+			continue
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, "", fmt.Errorf("forge: GET %s: unexpected status %d", url, resp.StatusCode)
+		}
+
+		next := ""
+		if match := nextLink.FindStringSubmatch(resp.Header.Get("Link")); match != nil {
+			next = match[1]
+		}
+
+		return body, next, nil
+	}
+}
+
+// rateLimitWait reports whether the response is a rate limit rejection and
+// how long to wait before retrying. GitHub answers 403 or 429 with
+// X-RateLimit-Remaining: 0 and an X-RateLimit-Reset epoch; GitLab answers
+// 429 with RateLimit-Reset; both may send Retry-After instead.
+func rateLimitWait(resp *http.Response) (time.Duration, bool) {
+	exhausted := resp.Header.Get("X-RateLimit-Remaining") == "0"
+	if resp.StatusCode != http.StatusTooManyRequests && !(resp.StatusCode == http.StatusForbidden && exhausted) {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	for _, header := range []string{"X-RateLimit-Reset", "RateLimit-Reset"} {
+		if epoch, err := strconv.ParseInt(resp.Header.Get(header), 10, 64); err == nil {
+			return max(time.Until(time.Unix(epoch, 0)), 0), true
+		}
+	}
+
+	return time.Second, true
+}
@@ -0,0 +1,114 @@
+package forge
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+var (
+	ErrInvalidSignature = errors.New("forge: invalid webhook signature")
+	ErrMalformedEvent   = errors.New("forge: malformed webhook payload")
+)
+
+// MergeEvent is a merge reported by a forge webhook. PullRequestID has the
+// format of PullRequest.ID, so it matches the PRs created by the backfill.
+type MergeEvent struct {
+	PullRequestID string
+	MergedByLogin string
+}
+
+type gitHubPullEvent struct {
+	Action      string `json:"action"`
+	PullRequest struct {
+		Number   int  `json:"number"`
+		Merged   bool `json:"merged"`
+		MergedBy *struct {
+			Login string `json:"login"`
+		} `json:"merged_by"`
+	} `json:"pull_request"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+}
+
+type gitLabMergeRequestEvent struct {
+	ObjectKind string `json:"object_kind"`
+	User       struct {
+		Username string `json:"username"`
+	} `json:"user"`
+	Project struct {
+		PathWithNamespace string `json:"path_with_namespace"`
+	} `json:"project"`
+	ObjectAttributes struct {
+		IID    int    `json:"iid"`
+		Action string `json:"action"`
+	} `json:"object_attributes"`
+}
+
+// ParseMergeEvent authenticates a webhook delivery of the given forge kind
+// and returns the merge it reports. Deliveries about anything else, such as
+// a PR closed without merging, return a nil event and no error.
+//
+// GitHub deliveries are checked against the X-Hub-Signature-256 HMAC of the
+// body, GitLab ones against the X-Gitlab-Token secret.
+func ParseMergeEvent(kind string, secret string, header http.Header, body []byte) (*MergeEvent, error) {
+	switch kind {
+	case KindGitHub:
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write(body)
+		expected := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+		if !hmac.Equal([]byte(header.Get("X-Hub-Signature-256")), []byte(expected)) {
+			return nil, ErrInvalidSignature
+		}
+
+		if header.Get("X-GitHub-Event") != "pull_request" {
+			return nil, nil
+		}
+
+		var event gitHubPullEvent
+		if err := json.Unmarshal(body, &event); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrMalformedEvent, err)
+		}
+		if event.Action != "closed" || !event.PullRequest.Merged {
+			return nil, nil
+		}
+		if event.Repository.FullName == "" || event.PullRequest.Number == 0 {
+			return nil, fmt.Errorf("%w: repository or pull request number is missing", ErrMalformedEvent)
+		}
+
+		merge := &MergeEvent{
+			PullRequestID: fmt.Sprintf("%s#%d", event.Repository.FullName, event.PullRequest.Number),
+		}
+		if event.PullRequest.MergedBy != nil {
+			merge.MergedByLogin = event.PullRequest.MergedBy.Login
+		}
+		return merge, nil
+	case KindGitLab:
+		if !hmac.Equal([]byte(header.Get("X-Gitlab-Token")), []byte(secret)) {
+			return nil, ErrInvalidSignature
+		}
+
+		var event gitLabMergeRequestEvent
+		if err := json.Unmarshal(body, &event); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrMalformedEvent, err)
+		}
+		if event.ObjectKind != "merge_request" || event.ObjectAttributes.Action != "merge" {
+			return nil, nil
+		}
+		if event.Project.PathWithNamespace == "" || event.ObjectAttributes.IID == 0 {
+			return nil, fmt.Errorf("%w: project or merge request iid is missing", ErrMalformedEvent)
+		}
+
+		return &MergeEvent{
+			PullRequestID: fmt.Sprintf("%s!%d", event.Project.PathWithNamespace, event.ObjectAttributes.IID),
+			MergedByLogin: event.User.Username,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("%w: %q", ErrUnknownKind, kind)
+}
@@ -0,0 +1,84 @@
+package forge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	KindGitHub = "github"
+	KindGitLab = "gitlab"
+)
+
+var (
+	ErrUnknownKind = errors.New("forge: unknown kind")
+	ErrRateLimited = errors.New("forge: rate limit exceeded")
+)
+
+// PullRequest is an open pull or merge request as the forge reports it. ID
+// is the forge's own reference, such as "org/repo#12" or "group/project!12".
+type PullRequest struct {
+	ID          string
+	Title       string
+	AuthorLogin string
+	Labels      []string
+	Draft       bool
+	CreatedAt   time.Time
+}
+
+type Client interface {
+	OpenPullRequests(ctx context.Context) ([]PullRequest, error)
+}
+
+type Config struct {
+	Kind    string
+	BaseURL string
+	Token   string
+	// Org is the GitHub organization or the GitLab group path.
+	Org string
+
+	Timeout time.Duration
+	// MaxRateLimitWait is the longest the client sleeps for a rate limit to
+	// reset before giving up with ErrRateLimited.
+	MaxRateLimitWait time.Duration
+}
+
+// New returns the client for cfg.Kind; a nil client and error mean no forge
+// is configured.
+func New(cfg Config) (Client, error) {
+	api := &api{
+		client:  &http.Client{Timeout: cfg.Timeout},
+		maxWait: cfg.MaxRateLimitWait,
+	}
+
+	switch cfg.Kind {
+	case "":
+		return nil, nil
+	case KindGitHub:
+		if cfg.BaseURL == "" {
+			cfg.BaseURL = "https://api.github.com"
+		}
+		api.authorize = func(req *http.Request) {
+			req.Header.Set("Accept", "application/vnd.github+json")
+			if cfg.Token != "" {
+				req.Header.Set("Authorization", "Bearer "+cfg.Token)
+			}
+		}
+		return &gitHub{api: api, baseURL: cfg.BaseURL, org: cfg.Org}, nil
+	case KindGitLab:
+		if cfg.BaseURL == "" {
+			cfg.BaseURL = "https://gitlab.com"
+		}
+		api.authorize = func(req *http.Request) {
+			if cfg.Token != "" {
+				req.Header.Set("PRIVATE-TOKEN", cfg.Token)
+			}
+		}
+		return &gitLab{api: api, baseURL: cfg.BaseURL, group: cfg.Org}, nil
+	}
+
+	return nil, fmt.Errorf("%w: %q", ErrUnknownKind, cfg.Kind)
+}
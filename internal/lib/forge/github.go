@@ -0,0 +1,74 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+type gitHub struct {
+	api     *api
+	baseURL string
+	org     string
+}
+
+type gitHubRepo struct {
+	FullName string `json:"full_name"`
+	Archived bool   `json:"archived"`
+}
+
+type gitHubPull struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	Draft  bool   `json:"draft"`
+	User   struct {
+		Login string `json:"login"`
+	} `json:"user"`
+	Labels []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// OpenPullRequests lists the open pull requests of every repository of the
+// organization that is not archived.
+func (g *gitHub) OpenPullRequests(ctx context.Context) ([]PullRequest, error) {
+	var repos []gitHubRepo
+	err := list(ctx, g.api, fmt.Sprintf("%s/orgs/%s/repos?per_page=100", g.baseURL, url.PathEscape(g.org)), func(page []gitHubRepo) {
+		repos = append(repos, page...)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	prs := make([]PullRequest, 0)
+	for _, repo := range repos {
+		if repo.Archived {
+			continue
+		}
+
+		err := list(ctx, g.api, fmt.Sprintf("%s/repos/%s/pulls?state=open&per_page=100", g.baseURL, repo.FullName), func(page []gitHubPull) {
+			for _, pull := range page {
+				labels := make([]string, 0, len(pull.Labels))
+				for _, label := range pull.Labels {
+					labels = append(labels, label.Name)
+				}
+
+				prs = append(prs, PullRequest{
+					ID:          fmt.Sprintf("%s#%d", repo.FullName, pull.Number),
+					Title:       pull.Title,
+					AuthorLogin: pull.User.Login,
+					Labels:      labels,
+					Draft:       pull.Draft,
+					CreatedAt:   pull.CreatedAt,
+				})
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return prs, nil
+}
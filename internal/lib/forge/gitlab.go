@@ -0,0 +1,58 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+type gitLab struct {
+	api     *api
+	baseURL string
+	group   string
+}
+
+type gitLabMergeRequest struct {
+	Title  string   `json:"title"`
+	Draft  bool     `json:"draft"`
+	Labels []string `json:"labels"`
+	Author struct {
+		Username string `json:"username"`
+	} `json:"author"`
+	References struct {
+		Full string `json:"full"`
+	} `json:"references"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// OpenPullRequests lists the open merge requests of the group and its
+// subgroups.
+func (g *gitLab) OpenPullRequests(ctx context.Context) ([]PullRequest, error) {
+	endpoint := fmt.Sprintf("%s/api/v4/groups/%s/merge_requests?state=opened&scope=all&per_page=100",
+		g.baseURL, url.PathEscape(g.group))
+
+	prs := make([]PullRequest, 0)
+	err := list(ctx, g.api, endpoint, func(page []gitLabMergeRequest) {
+		for _, mr := range page {
+			labels := mr.Labels
+			if labels == nil {
+				labels = []string{}
+			}
+
+			prs = append(prs, PullRequest{
+				ID:          mr.References.Full,
+				Title:       mr.Title,
+				AuthorLogin: mr.Author.Username,
+				Labels:      labels,
+				Draft:       mr.Draft,
+				CreatedAt:   mr.CreatedAt,
+			})
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return prs, nil
+}
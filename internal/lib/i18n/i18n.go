@@ -0,0 +1,78 @@
+package i18n
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	LangEN = "en"
+	LangRU = "ru"
+)
+
+// catalogues maps the English message, which is the canonical text used in
+// the code, to its translation. Machine-readable error codes are never
+// translated.
+var catalogues = map[string]map[string]string{
+	LangRU: ru,
+}
+
+// FromAcceptLanguage picks the best supported language from an
+// Accept-Language header, falling back to English.
+func FromAcceptLanguage(header string) string {
+	type candidate struct {
+		lang string
+		q    float64
+	}
+
+	candidates := make([]candidate, 0)
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		tag := strings.ToLower(strings.TrimSpace(fields[0]))
+		if tag == "" {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if value, ok := strings.CutPrefix(param, "q="); ok {
+				if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+
+		base, _, _ := strings.Cut(tag, "-")
+		candidates = append(candidates, candidate{lang: base, q: q})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].q > candidates[j].q
+	})
+
+	for _, c := range candidates {
+		if c.q <= 0 {
+			continue
+		}
+		// English is the default, so it is what a wildcard stands for.
+		if c.lang == LangEN || c.lang == "*" {
+			return LangEN
+		}
+		if _, ok := catalogues[c.lang]; ok {
+			return c.lang
+		}
+	}
+
+	return LangEN
+}
+
+// Translate returns the message in the given language, or the message itself
+// when no translation exists.
+func Translate(lang string, message string) string {
+	if translated, ok := catalogues[lang][message]; ok {
+		return translated
+	}
+	return message
+}
@@ -0,0 +1,48 @@
+package i18n
+
+import "testing"
+
+func TestFromAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   string
+	}{
+		{name: "empty", header: "", want: LangEN},
+		{name: "russian", header: "ru", want: LangRU},
+		{name: "region", header: "ru-RU", want: LangRU},
+		{name: "case", header: "RU-ru", want: LangRU},
+		{name: "english first", header: "en-US, ru;q=0.9", want: LangEN},
+		{name: "higher q wins", header: "en;q=0.5, ru-RU;q=0.8", want: LangRU},
+		{name: "equal q keeps order", header: "ru;q=0.7, en;q=0.7", want: LangRU},
+		{name: "unsupported skipped", header: "fr-FR, de;q=0.9, ru;q=0.1", want: LangRU},
+		{name: "only unsupported", header: "fr, de", want: LangEN},
+		{name: "q=0 excludes", header: "ru;q=0", want: LangEN},
+		{name: "q=0 excludes before others", header: "ru;q=0, fr", want: LangEN},
+		{name: "spaces around params", header: " ru ; q=0.4 , en ; q=0.3 ", want: LangRU},
+		{name: "malformed q", header: "en;q=abc, ru;q=0.9", want: LangEN},
+		{name: "wildcard", header: "*", want: LangEN},
+		{name: "wildcard before russian", header: "*, ru;q=0.5", want: LangEN},
+		{name: "russian before wildcard", header: "ru, *;q=0.1", want: LangRU},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FromAcceptLanguage(tt.header); got != tt.want {
+				t.Fatalf("FromAcceptLanguage(%q) = %s, want %s", tt.header, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	if got := Translate(LangRU, "resource not found"); got == "resource not found" {
+		t.Fatal("expected a russian translation")
+	}
+	if got := Translate(LangEN, "resource not found"); got != "resource not found" {
+		t.Fatalf("expected english to be returned as is, got %s", got)
+	}
+	if got := Translate(LangRU, "no such message"); got != "no such message" {
+		t.Fatalf("expected an unknown message to be returned as is, got %s", got)
+	}
+}
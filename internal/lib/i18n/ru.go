@@ -0,0 +1,297 @@
+package i18n
+
+var ru = map[string]string{
+	"API key is required":               "требуется API-ключ",
+	"API key lacks the required scope":  "у API-ключа нет нужных прав",
+	"Approved":                          "Одобрено",
+	"Assigned":                          "Назначено",
+	"Assigned automatically":            "Назначен автоматически",
+	"Assigned manually":                 "Назначен вручную",
+	"Completed":                         "Завершено",
+	"Conflict of interest":              "Конфликт интересов",
+	"Critical":                          "Критический",
+	"Deactivated":                       "Деактивация",
+	"Declined":                          "Отказ",
+	"Delegated":                         "Делегировано",
+	"Failed":                            "Не пройдено",
+	"High":                              "Высокий",
+	"In progress":                       "В работе",
+	"Low":                               "Низкий",
+	"Manual":                            "Вручную",
+	"Merged":                            "Смержен",
+	"Merged automatically":              "Смержен автоматически",
+	"Merged in forge":                   "Смержен в forge",
+	"Normal":                            "Обычный",
+	"Notification failed":               "Уведомление не доставлено",
+	"Notification sent":                 "Уведомление отправлено",
+	"OPEN and MERGED cannot be changed": "OPEN и MERGED нельзя изменить",
+	"Offboarded":                        "Увольнение",
+	"Open":                              "Открыт",
+	"Overloaded":                        "Перегрузка",
+	"PR cannot be merged from its current status": "PR нельзя смержить из текущего статуса",
+	"PR created":           "PR создан",
+	"PR is already merged": "PR уже смержен",
+	"PR is closed":         "PR закрыт",
+	"PR must keep a certified reviewer for each required area": "у PR должен остаться сертифицированный ревьювер для каждой требуемой области",
+	"PR must keep a security team reviewer":                    "у PR должен остаться ревьювер из команды безопасности",
+	"PR requires approval from a security team reviewer":       "для PR требуется одобрение ревьювера из команды безопасности",
+	"PR would have fewer reviewers than the team minimum":      "у PR останется меньше ревьюверов, чем требует команда",
+	"Passed":                               "Пройдено",
+	"Pending":                              "Выполняется",
+	"Reassigned":                           "Переназначен",
+	"Review completed":                     "Ревью завершено",
+	"Review started":                       "Ревью начато",
+	"Reviewer assigned":                    "Назначен ревьювер",
+	"Reviewer unassigned":                  "Ревьювер снят",
+	"Status changed":                       "Статус изменён",
+	"Unassigned":                           "Снят",
+	"Unknown":                              "Неизвестно",
+	"Vacation":                             "Отпуск",
+	"admin request nonce was already used": "nonce админского запроса уже использован",
+	"admin request signature is missing or invalid":         "подпись админского запроса отсутствует или неверна",
+	"admin request timestamp is outside the allowed window": "время админского запроса вне допустимого окна",
+	"admin routes are disabled until ADMIN_SECRET is set":   "административные маршруты отключены, пока не задан ADMIN_SECRET",
+	"admin routes require an API key":                       "административные маршруты требуют API-ключ",
+	"an admin API key already exists":                       "admin-ключ уже выпущен",
+	"anonymized user cannot be renamed or given a profile":  "анонимизированного пользователя нельзя переименовать или дополнить профилем",
+	"archived team not found":                               "архивная команда не найдена",
+	"area is required":                                      "требуется area",
+	"at least one scope is required":                        "требуется хотя бы один scope",
+	"author cannot review own PR":                           "автор не может ревьюить свой PR",
+	"author is not a member of team_name":                   "автор не состоит в команде team_name",
+	"author team not found":                                 "команда автора не найдена",
+	"author_id is required":                                 "требуется author_id",
+	"blocks need a weekday and a start before the end as HH:MM, time_zone an IANA time zone and warn_minutes 0 to 1440": "блокам нужны день недели и начало раньше конца в формате HH:MM, time_zone — часовой пояс IANA, а warn_minutes — от 0 до 1440",
+	"bucket must be hour or day and the window at most 31 days":                                                         "bucket должен быть hour или day, а окно — не больше 31 дня",
+	"caller identity is required":                                                    "требуется идентификатор вызывающего пользователя",
+	"cannot approve merged PR":                                                       "нельзя одобрить смерженный PR",
+	"cannot assign on merged PR":                                                     "нельзя назначить ревьювера на смерженный PR",
+	"cannot change reviewers on merged PR":                                           "нельзя менять ревьюверов в смерженном PR",
+	"cannot complete review on merged PR":                                            "нельзя завершить ревью смерженного PR",
+	"cannot decline review on merged PR":                                             "нельзя отказаться от ревью смерженного PR",
+	"cannot delegate on merged PR":                                                   "нельзя передать ревью на смерженном PR",
+	"cannot impersonate yourself":                                                    "нельзя выдать себя за самого себя",
+	"cannot reassign on merged PR":                                                   "нельзя переназначить ревьювера на смерженном PR",
+	"cannot rotate revoked token":                                                    "нельзя перевыпустить отозванный токен",
+	"cannot start review on merged PR":                                               "нельзя начать ревью смерженного PR",
+	"cannot unassign on merged PR":                                                   "нельзя снять ревьювера со смерженного PR",
+	"cannot update CI status on merged PR":                                           "нельзя обновить статус CI у смерженного PR",
+	"ci_status must be one of UNKNOWN, PENDING, SUCCESS, FAILURE":                    "ci_status должен быть одним из UNKNOWN, PENDING, SUCCESS, FAILURE",
+	"confirmation_token does not match":                                              "confirmation_token не совпадает",
+	"created_from, created_to, merged_from and merged_to must be RFC3339 timestamps": "created_from, created_to, merged_from и merged_to должны быть временем в формате RFC3339",
+	"debug must be true or false":                                                    "debug должен быть true или false",
+	"delegate has reached the open review limit":                                     "у получателя достигнут лимит открытых ревью",
+	"delegate is not a member of the reviewer's team":                                "получатель не состоит в команде ревьювера",
+	"delegate_id is required":                                                        "требуется delegate_id",
+	"display_name, email, avatar_url or locale is invalid":                           "display_name, email, avatar_url или locale заданы неверно",
+	"dry_run must be true or false":                                                  "dry_run должен быть true или false",
+	"ends_at must be in the future and after starts_at":                              "ends_at должен быть в будущем и позже starts_at",
+	"exactly one of team_name or user_id is required":                                "требуется ровно одно из полей team_name или user_id",
+	"expires_at must be in the future":                                               "expires_at должен быть в будущем",
+	"failed to add reviewer pool members":                                            "не удалось добавить участников пула ревьюверов",
+	"failed to anonymize user":                                                       "не удалось анонимизировать пользователя",
+	"failed to archive team":                                                         "не удалось архивировать команду",
+	"failed to authenticate API key":                                                 "не удалось проверить API-ключ",
+	"failed to backfill PRs":                                                         "не удалось импортировать PR",
+	"failed to build capacity plan":                                                  "не удалось построить план загрузки",
+	"failed to check team membership":                                                "не удалось проверить состав команд",
+	"failed to complete review":                                                      "не удалось завершить ревью",
+	"failed to create reviewer pool":                                                 "не удалось создать пул ревьюверов",
+	"failed to create team webhook":                                                  "не удалось создать вебхук команды",
+	"failed to decline review":                                                       "не удалось отказаться от ревью",
+	"failed to delegate review":                                                      "не удалось передать ревью",
+	"failed to delete PR status":                                                     "не удалось удалить статус PR",
+	"failed to delete notification template":                                         "не удалось удалить шаблон уведомления",
+	"failed to delete reviewer pool":                                                 "не удалось удалить пул ревьюверов",
+	"failed to delete team webhook":                                                  "не удалось удалить вебхук команды",
+	"failed to end impersonation":                                                    "не удалось завершить сеанс имперсонации",
+	"failed to fetch open PRs from forge":                                            "не удалось получить открытые PR из forge",
+	"failed to freeze assignments":                                                   "не удалось заморозить назначение ревьюверов",
+	"failed to get PR activity":                                                      "не удалось получить историю PR",
+	"failed to get assignment repairs":                                               "не удалось получить очередь починки назначений",
+	"failed to get cycle time":                                                       "не удалось получить время цикла PR",
+	"failed to get dashboard":                                                        "не удалось получить дашборд",
+	"failed to get effective policy":                                                 "не удалось получить действующую политику команды",
+	"failed to get freezes":                                                          "не удалось получить список заморозок",
+	"failed to get merge windows":                                                    "не удалось получить окна мержей",
+	"failed to get migration status":                                                 "не удалось получить статус миграций",
+	"failed to get org policy":                                                       "не удалось получить политику организации",
+	"failed to get pending assignments":                                              "не удалось получить очередь назначений",
+	"failed to get review forecast":                                                  "не удалось получить прогноз нагрузки",
+	"failed to get reviewer pool":                                                    "не удалось получить пул ревьюверов",
+	"failed to get reviewer pool stats":                                              "не удалось получить статистику пула ревьюверов",
+	"failed to get stats history":                                                    "не удалось получить историю статистики",
+	"failed to get tag stats":                                                        "не удалось получить статистику по меткам",
+	"failed to get team leads":                                                       "не удалось получить руководителей команд",
+	"failed to get team settings":                                                    "не удалось получить настройки команды",
+	"failed to get user":                                                             "не удалось получить пользователя",
+	"failed to get user settings":                                                    "не удалось получить настройки пользователя",
+	"failed to grant certification":                                                  "не удалось выдать сертификацию",
+	"failed to handle forge event":                                                   "не удалось обработать событие forge",
+	"failed to issue token":                                                          "не удалось выпустить токен",
+	"failed to list certifications":                                                  "не удалось получить список сертификаций",
+	"failed to list enums":                                                           "не удалось получить перечисления",
+	"failed to list failed webhook deliveries":                                       "не удалось получить неудавшиеся доставки вебхуков",
+	"failed to list notification templates":                                          "не удалось получить шаблоны уведомлений",
+	"failed to list reviewer pools":                                                  "не удалось получить список пулов ревьюверов",
+	"failed to list team webhooks":                                                   "не удалось получить вебхуки команды",
+	"failed to list tokens":                                                          "не удалось получить список токенов",
+	"failed to offboard user":                                                        "не удалось вывести пользователя из ревью",
+	"failed to patch team settings":                                                  "не удалось изменить настройки команды",
+	"failed to patch user settings":                                                  "не удалось изменить настройки пользователя",
+	"failed to rebalance team":                                                       "не удалось перераспределить ревью в команде",
+	"failed to reconcile PR statuses":                                                "не удалось сверить статусы PR",
+	"failed to remove reviewer pool members":                                         "не удалось удалить участников пула ревьюверов",
+	"failed to reorder review queue":                                                 "не удалось изменить порядок очереди ревью",
+	"failed to resolve impersonation session":                                        "не удалось проверить сеанс имперсонации",
+	"failed to revoke certification":                                                 "не удалось отозвать сертификацию",
+	"failed to revoke token":                                                         "не удалось отозвать токен",
+	"failed to rotate token":                                                         "не удалось перевыпустить токен",
+	"failed to run assignment repairs":                                               "не удалось запустить починку назначений",
+	"failed to save PR status":                                                       "не удалось сохранить статус PR",
+	"failed to save notification template":                                           "не удалось сохранить шаблон уведомления",
+	"failed to select response fields":                                               "не удалось выбрать поля ответа",
+	"failed to set PR status transitions":                                            "не удалось задать переходы статусов PR",
+	"failed to set author-only status":                                               "не удалось изменить режим «только автор»",
+	"failed to set merge window":                                                     "не удалось задать окно мержей",
+	"failed to set secondary member":                                                 "не удалось изменить дополнительное членство в команде",
+	"failed to set team lead":                                                        "не удалось изменить руководителя команды",
+	"failed to set token quota":                                                      "не удалось задать лимит токена",
+	"failed to start impersonation":                                                  "не удалось начать сеанс имперсонации",
+	"failed to unfreeze assignments":                                                 "не удалось снять заморозку назначения ревьюверов",
+	"failed to update org policy":                                                    "не удалось обновить политику организации",
+	"failed to update reviewer pool":                                                 "не удалось обновить пул ревьюверов",
+	"failed to update team webhook":                                                  "не удалось изменить вебхук команды",
+	"failed to verify admin request":                                                 "не удалось проверить админский запрос",
+	"forge is not configured":                                                        "источник PR (forge) не настроен",
+	"forge webhook signature is missing or invalid":                                  "подпись вебхука forge отсутствует или неверна",
+	"forge webhooks are not configured":                                              "вебхуки forge не настроены",
+	"format must be xlsx":                                                            "format должен быть xlsx",
+	"hourly_quota must not be negative":                                              "hourly_quota не может быть отрицательным",
+	"impersonation sessions are read-only":                                           "в сеансе имперсонации доступно только чтение",
+	"invalid bootstrap secret":                                                       "неверный секрет для выпуска первого ключа",
+	"invalid merge patch: %s":                                                        "некорректный merge patch: %s",
+	"invalid merged_by format":                                                       "некорректный формат merged_by",
+	"invalid notification template: %s":                                              "некорректный шаблон уведомления: %s",
+	"invalid or expired API key":                                                     "недействительный или просроченный API-ключ",
+	"invalid or expired impersonation session":                                       "недействительный или истёкший сеанс имперсонации",
+	"invalid org policy":                                                             "некорректная политика организации",
+	"limit must be between 1 and %d":                                                 "limit должен быть от 1 до %d",
+	"limit must be between 1 and %d and cursor must come from a previous page":       "limit должен быть от 1 до %d, а cursor должен быть взят из предыдущей страницы",
+	"malformed forge webhook payload":                                                "некорректное тело вебхука forge",
+	"max_daily_assignments must be between 0 and 100":                                "max_daily_assignments должен быть от 0 до 100",
+	"merged shortly after the merge window reopened":                                 "смержен вскоре после открытия окна мержей",
+	"merged shortly before the merge window closes":                                  "смержен незадолго до закрытия окна мержей",
+	"merged while the merge window is closed, by admin override":                     "смержен при закрытом окне мержей в обход администратором",
+	"merging is blocked by the team's merge window":                                  "мерж запрещён окном мержей команды",
+	"name is required":                                                               "требуется name",
+	"no active candidate for an extra reviewer":                                      "нет активного кандидата в дополнительные ревьюверы",
+	"no active certified reviewer available":                                         "нет доступных сертифицированных ревьюверов",
+	"note must be at most %d characters":                                             "note должен быть не длиннее %d символов",
+	"only admins may override the merge window":                                      "обойти окно мержей могут только администраторы",
+	"open and overdue must be true or false":                                         "open и overdue должны быть true или false",
+	"pool_name is required":                                                          "требуется pool_name",
+	"pull request IDs must be non-empty and unique":                                  "идентификаторы PR должны быть непустыми и не повторяться",
+	"pull request is not in the review queue":                                        "PR нет в очереди ревью",
+	"pull_requests needs 1 to 1000 unique ids with known statuses, and merged_at only with MERGED": "pull_requests должен содержать от 1 до 1000 разных идентификаторов с известными статусами, а merged_at — только со статусом MERGED",
+	"reason is required": "требуется reason",
+	"reason must be one of VACATION, OVERLOADED, CONFLICT, DECLINED, MANUAL, OFFBOARDED, DEACTIVATED": "reason должен быть одним из VACATION, OVERLOADED, CONFLICT, DECLINED, MANUAL, OFFBOARDED, DEACTIVATED",
+	"resource was modified since it was read":                                                         "ресурс изменён после чтения",
+	"reviewer is author-only and cannot be assigned":                                                  "ревьювер в режиме «только автор» и не может быть назначен",
+	"reviewer is not assigned to this PR":                                                             "ревьювер не назначен на этот PR",
+	"reviewer pool not found":                                                                         "пул ревьюверов не найден",
+	"reviewers changed concurrently, retry":                                                           "ревьюверы изменились параллельно, повторите запрос",
+	"reviewers must be between 1 and 10":                                                              "reviewers должно быть от 1 до 10",
+	"reviewers_per_pr must be between 1 and 5":                                                        "reviewers_per_pr должен быть от 1 до 5",
+	"scopes must be read, write or admin":                                                             "scopes должны быть read, write или admin",
+	"search ranges must start before they end":                                                        "начало диапазона поиска должно быть раньше его конца",
+	"stats of this team are not visible to the caller":                                                "статистика этой команды вам недоступна",
+	"status is not configured":                                                                        "статус не настроен",
+	"status is still used by pull requests or transitions":                                            "статус всё ещё используется pull request-ами или переходами",
+	"team already has a webhook with this url":                                                        "у команды уже есть вебхук с этим url",
+	"team is the user's primary team; change it through /team/add":                                    "это основная команда пользователя; меняйте её через /team/add",
+	"template needs a known event and channel and a body that renders":                                "шаблону нужны известные событие и канал и тело, которое отрисовывается",
+	"transitions must keep MERGED reachable from OPEN and may not leave a terminal status":            "переходы должны оставлять MERGED достижимым из OPEN и не могут выходить из терминального статуса",
+	"tz must be an IANA time zone such as Europe/Moscow":                                              "tz должен быть часовым поясом IANA, например Europe/Moscow",
+	"until must be in the future":                                                                     "until должен быть в будущем",
+	"username is required":                                                                            "username обязателен",
+	"webhook needs an http(s) url, a secret and known events":                                         "вебхуку нужны http(s) url, секрет и известные события",
+	"failed to approve PR":                                                                            "не удалось одобрить PR",
+	"failed to assign reviewer":                                                                       "не удалось назначить ревьювера",
+	"failed to check database":                                                                        "не удалось проверить базу данных",
+	"failed to create PR":                                                                             "не удалось создать PR",
+	"failed to create team":                                                                           "не удалось создать команду",
+	"failed to deactivate team users":                                                                 "не удалось деактивировать пользователей команды",
+	"failed to export PRs":                                                                            "не удалось выгрузить PR",
+	"failed to get PR statistics":                                                                     "не удалось получить статистику PR",
+	"failed to get archive":                                                                           "не удалось получить архив",
+	"failed to get candidates":                                                                        "не удалось получить кандидатов",
+	"failed to get jobs":                                                                              "не удалось получить список фоновых задач",
+	"failed to get policy history":                                                                    "не удалось получить историю политики",
+	"failed to get team changes":                                                                      "не удалось получить историю изменений команды",
+	"failed to get review queue":                                                                      "не удалось получить очередь ревью",
+	"failed to get team":                                                                              "не удалось получить команду",
+	"failed to get teams statistics":                                                                  "не удалось получить статистику команд",
+	"failed to get usage":                                                                             "не удалось получить статистику использования",
+	"failed to get user reviews":                                                                      "не удалось получить ревью пользователя",
+	"failed to list PR statuses":                                                                      "не удалось получить статусы PR",
+	"failed to merge PR":                                                                              "не удалось смержить PR",
+	"failed to reassign reviewer":                                                                     "не удалось переназначить ревьювера",
+	"failed to restore":                                                                               "не удалось восстановить",
+	"failed to run simulation":                                                                        "не удалось выполнить симуляцию",
+	"failed to set PR status":                                                                         "не удалось установить статус PR",
+	"failed to set user active status":                                                                "не удалось изменить активность пользователя",
+	"failed to set users active status":                                                               "не удалось изменить активность пользователей",
+	"failed to start review":                                                                          "не удалось начать ревью",
+	"failed to unassign reviewer":                                                                     "не удалось снять ревьювера",
+	"failed to update CI status":                                                                      "не удалось обновить статус CI",
+	"failed to update team":                                                                           "не удалось обновить команду",
+	"from must be an RFC3339 timestamp":                                                               "from должен быть временем в формате RFC3339",
+	"invalid request body":                                                                            "некорректное тело запроса",
+	"invalid team policy":                                                                             "некорректная политика команды",
+	"invalid user_id format":                                                                          "некорректный формат user_id",
+	"no active replacement candidate in team":                                                         "в команде нет активного кандидата на замену",
+	"no active reviewers available in team":                                                           "в команде нет доступных активных ревьюверов",
+	"no active security team reviewer available":                                                      "нет доступных ревьюверов из команды безопасности",
+	"only deactivated users can be anonymized":                                                        "анонимизировать можно только деактивированных пользователей",
+	"priority must be one of LOW, NORMAL, HIGH, CRITICAL":                                             "priority должен быть одним из LOW, NORMAL, HIGH, CRITICAL",
+	"pull_request_id is required":                                                                     "требуется pull_request_id",
+	"pull_request_id query parameter is required":                                                     "требуется параметр запроса pull_request_id",
+	"pull_request_name is required":                                                                   "требуется pull_request_name",
+	"resource not found":                                                                              "ресурс не найден",
+	"reviewer is already assigned to this PR":                                                         "ревьювер уже назначен на этот PR",
+	"reviewer is inactive":                                                                            "ревьювер неактивен",
+	"reviewer is not a member of the author's team":                                                   "ревьювер не состоит в команде автора",
+	"reviewer team not found":                                                                         "команда ревьюверов не найдена",
+	"reviewer to replace is not assigned to this PR":                                                  "заменяемый ревьювер не назначен на этот PR",
+	"reviewer_id is required":                                                                         "требуется reviewer_id",
+	"reviewer_teams must list distinct teams, 1-5 reviewers each":                                     "reviewer_teams должен содержать разные команды, от 1 до 5 ревьюверов в каждой",
+	"status is required":                                                                              "требуется status",
+	"strategy must be one of random, least_loaded":                                                    "strategy должен быть одним из random, least_loaded",
+	"team must have at least one member":                                                              "в команде должен быть хотя бы один участник",
+	"team_name is required":                                                                           "требуется team_name",
+	"team_name query parameter is required":                                                           "требуется параметр запроса team_name",
+	"team_names must contain non-empty team names":                                                    "team_names должен содержать непустые названия команд",
+	"to must be an RFC3339 timestamp":                                                                 "to должен быть временем в формате RFC3339",
+	"user is already anonymized":                                                                      "пользователь уже анонимизирован",
+	"user_id is required":                                                                             "требуется user_id",
+	"user_id must start with 'u'":                                                                     "user_id должен начинаться с 'u'",
+	"user_id query parameter is required":                                                             "требуется параметр запроса user_id",
+	"user_ids must not be empty":                                                                      "user_ids не должен быть пустым",
+	"reviewers_per_pr must not be negative":                                                           "reviewers_per_pr не может быть отрицательным",
+	"at most %d teams can be requested at once":                                                       "за один запрос можно получить не больше %d команд",
+	"at most %d user_ids are allowed per request":                                                     "в одном запросе допускается не больше %d user_id",
+	"PR %s already exists":                                                                            "PR %s уже существует",
+	"status %s is not configured":                                                                     "статус %s не настроен",
+	"transition to %s is not allowed":                                                                 "переход в статус %s запрещён",
+	"user_id is required for member at index %d":                                                      "для участника с индексом %d требуется user_id",
+	"username is required for member at index %d":                                                     "для участника с индексом %d требуется username",
+	"team %s already exists":                                                                          "команда %s уже существует",
+	"reviewer pool %s already exists":                                                                 "пул ревьюверов %s уже существует",
+	"unknown field %s":                                                                                "неизвестное поле %s",
+	"wait must be a duration up to %s":                                                                "wait должен быть длительностью не больше %s",
+	"auto_merge_approvals must be between 1 and %d":                                                   "auto_merge_approvals должен быть от 1 до %d",
+	"window_days must be between 1 and %d":                                                            "window_days должен быть от 1 до %d",
+	"server is busy, retry later":                                                                     "сервер перегружен, повторите позже",
+	"hourly request quota exceeded":                                                                   "превышена часовая квота запросов",
+}
@@ -0,0 +1,84 @@
+package localtime
+
+import (
+	"reflect"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// In returns a copy of data with every time.Time in it, at any depth,
+// moved to loc. The copy keeps the types of data, so JSON names and field
+// selection work on it as on the original. A nil loc returns data as is.
+func In(data any, loc *time.Location) any {
+	if loc == nil || data == nil {
+		return data
+	}
+
+	return convert(reflect.ValueOf(data), loc).Interface()
+}
+
+func convert(v reflect.Value, loc *time.Location) reflect.Value {
+	if v.Type() == timeType {
+		return reflect.ValueOf(v.Interface().(time.Time).In(loc))
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return v
+		}
+		copied := reflect.New(v.Type().Elem())
+		copied.Elem().Set(convert(v.Elem(), loc))
+		return copied
+
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		copied := reflect.New(v.Type()).Elem()
+		copied.Set(convert(v.Elem(), loc))
+		return copied
+
+	case reflect.Struct:
+		copied := reflect.New(v.Type()).Elem()
+		copied.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if field := copied.Field(i); field.CanSet() {
+				field.Set(convert(v.Field(i), loc))
+			}
+		}
+		return copied
+
+	case reflect.Slice:
+		// Byte slices, such as raw JSON, hold no times.
+		if v.IsNil() || v.Type().Elem().Kind() == reflect.Uint8 {
+			return v
+		}
+		copied := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			copied.Index(i).Set(convert(v.Index(i), loc))
+		}
+		return copied
+
+	case reflect.Array:
+		copied := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			copied.Index(i).Set(convert(v.Index(i), loc))
+		}
+		return copied
+
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		copied := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			copied.SetMapIndex(iter.Key(), convert(iter.Value(), loc))
+		}
+		return copied
+	}
+
+	return v
+}
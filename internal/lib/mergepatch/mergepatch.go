@@ -0,0 +1,154 @@
+package mergepatch
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// ContentType is the media type of an RFC 7396 merge patch.
+const ContentType = "application/merge-patch+json"
+
+// InvalidPatchError reports a patch that cannot be applied to the target.
+type InvalidPatchError struct {
+	Reason string
+}
+
+func (e *InvalidPatchError) Error() string {
+	return "invalid merge patch: " + e.Reason
+}
+
+// Apply applies an RFC 7396 JSON merge patch to target, a pointer to a
+// struct. The patch must be a JSON object whose members name fields of the
+// target; null removes a member and is only accepted for fields that can
+// hold null (pointers, slices and maps) or are omitempty, which null resets
+// to the zero value. Nothing is written to target when the patch is
+// rejected.
+func Apply(target any, patch []byte) error {
+	value := reflect.ValueOf(target)
+	if value.Kind() != reflect.Pointer || value.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("mergepatch: target must be a pointer to a struct, got %T", target)
+	}
+
+	var changes map[string]any
+	if err := json.Unmarshal(patch, &changes); err != nil || changes == nil {
+		return &InvalidPatchError{Reason: "patch must be a JSON object"}
+	}
+
+	fields := jsonFields(value.Elem().Type())
+	for name, change := range changes {
+		nullable, known := fields[name]
+		if !known {
+			return &InvalidPatchError{Reason: fmt.Sprintf("unknown field %q", name)}
+		}
+		if change == nil && !nullable {
+			return &InvalidPatchError{Reason: name + " cannot be null"}
+		}
+	}
+
+	raw, err := json.Marshal(target)
+	if err != nil {
+		return err
+	}
+
+	var document map[string]any
+	if err := json.Unmarshal(raw, &document); err != nil {
+		return err
+	}
+
+	merged, err := json.Marshal(merge(document, changes))
+	if err != nil {
+		return err
+	}
+
+	result := reflect.New(value.Elem().Type())
+	decoder := json.NewDecoder(bytes.NewReader(merged))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(result.Interface()); err != nil {
+		return &InvalidPatchError{Reason: strings.TrimPrefix(err.Error(), "json: ")}
+	}
+
+	value.Elem().Set(result.Elem())
+	return nil
+}
+
+// Matches reports whether an If-Match header value accepts etag. An empty
+// header makes the request unconditional.
+func Matches(ifMatch string, etag string) bool {
+	if ifMatch == "" {
+		return true
+	}
+
+	for _, candidate := range strings.Split(ifMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ETag returns a strong entity tag for the JSON form of document.
+func ETag(document any) (string, error) {
+	raw, err := json.Marshal(document)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(raw)
+	return `"` + hex.EncodeToString(sum[:8]) + `"`, nil
+}
+
+func merge(document any, patch any) any {
+	changes, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+
+	target, ok := document.(map[string]any)
+	if !ok {
+		target = make(map[string]any)
+	}
+
+	for name, change := range changes {
+		if change == nil {
+			delete(target, name)
+			continue
+		}
+		target[name] = merge(target[name], change)
+	}
+
+	return target
+}
+
+// jsonFields maps the JSON names of the struct fields to whether the field
+// accepts null.
+func jsonFields(t reflect.Type) map[string]bool {
+	fields := make(map[string]bool)
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" || !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		switch field.Type.Kind() {
+		case reflect.Pointer, reflect.Slice, reflect.Map, reflect.Interface:
+			fields[name] = true
+		default:
+			fields[name] = slices.Contains(strings.Split(options, ","), "omitempty")
+		}
+	}
+
+	return fields
+}
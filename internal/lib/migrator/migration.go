@@ -8,44 +8,223 @@ import (
 	"github.com/golang-migrate/migrate/v4/database/postgres"
 	"github.com/golang-migrate/migrate/v4/source/iofs"
 	"github.com/jmoiron/sqlx"
+	"io/fs"
 	"log/slog"
 	"pull-request-assigner/internal/config"
+	"strconv"
+	"strings"
 )
 
 //go:embed migrations/*.sql
-var fs embed.FS
+var migrationsFS embed.FS
 
-// RunMigrations up migrations files from embed.FS - fs
-func RunMigrations(cfg config.PostgresConfig, log *slog.Logger) error {
-	const op = "migrator.RunMigrations"
+// MinCompatibleVersion is the oldest schema this build can run against. Bump
+// it when code starts relying on a newer migration; keep it below the latest
+// migration while the previous release must still run on the new schema.
+const MinCompatibleVersion = 53
+
+var (
+	ErrSchemaBehind = errors.New("database schema is older than this build supports")
+	ErrSchemaAhead  = errors.New("database schema is newer than this build knows")
+	ErrSchemaDirty  = errors.New("database schema is dirty")
+)
+
+// Migrator drives the embedded migrations against one database.
+type Migrator struct {
+	db *sqlx.DB
+	m  *migrate.Migrate
+}
+
+func New(cfg config.PostgresConfig) (*Migrator, error) {
+	const op = "migrator.New"
 
 	connStr := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
 		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.DbName, cfg.SslMode)
 
 	migrationDB, err := sqlx.Connect("postgres", connStr)
 	if err != nil {
-		return fmt.Errorf("%s: failed to connect: %w", op, err)
+		return nil, fmt.Errorf("%s: failed to connect: %w", op, err)
 	}
-	defer migrationDB.Close()
 
 	driver, err := postgres.WithInstance(migrationDB.DB, &postgres.Config{})
 	if err != nil {
-		return fmt.Errorf("%s: failed to create driver: %w", op, err)
+		migrationDB.Close()
+		return nil, fmt.Errorf("%s: failed to create driver: %w", op, err)
 	}
 
-	source, err := iofs.New(fs, "migrations")
+	source, err := iofs.New(migrationsFS, "migrations")
 	if err != nil {
-		return fmt.Errorf("%s: failed to create source: %w", op, err)
+		migrationDB.Close()
+		return nil, fmt.Errorf("%s: failed to create source: %w", op, err)
 	}
 
 	m, err := migrate.NewWithInstance("iofs", source, "postgres", driver)
 	if err != nil {
-		return fmt.Errorf("%s: failed to create migrate instance: %w", op, err)
+		migrationDB.Close()
+		return nil, fmt.Errorf("%s: failed to create migrate instance: %w", op, err)
+	}
+
+	return &Migrator{db: migrationDB, m: m}, nil
+}
+
+func (mg *Migrator) Close() {
+	mg.m.Close()
+	mg.db.Close()
+}
+
+// Up applies all pending migrations. Being up to date is not an error.
+func (mg *Migrator) Up() error {
+	const op = "migrator.Up"
+
+	if err := mg.m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// Steps applies n migrations up, or -n down when n is negative.
+func (mg *Migrator) Steps(n int) error {
+	const op = "migrator.Steps"
+
+	if err := mg.m.Steps(n); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// Force sets the version without running migrations and clears the dirty
+// flag, for recovering after a failed migration was fixed by hand.
+func (mg *Migrator) Force(version int) error {
+	const op = "migrator.Force"
+
+	if err := mg.m.Force(version); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// Version returns the applied schema version; 0 means nothing is applied.
+func (mg *Migrator) Version() (uint, bool, error) {
+	const op = "migrator.Version"
+
+	version, dirty, err := mg.m.Version()
+	if err != nil {
+		if errors.Is(err, migrate.ErrNilVersion) {
+			return 0, false, nil
+		}
+		return 0, false, fmt.Errorf("%s: %w", op, err)
+	}
+	return version, dirty, nil
+}
+
+// LatestVersion returns the highest version among the embedded migrations.
+func LatestVersion() (uint, error) {
+	const op = "migrator.LatestVersion"
+
+	entries, err := fs.ReadDir(migrationsFS, "migrations")
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var latest uint
+	for _, entry := range entries {
+		prefix, _, found := strings.Cut(entry.Name(), "_")
+		if !found {
+			continue
+		}
+
+		version, err := strconv.ParseUint(prefix, 10, 64)
+		if err != nil {
+			continue
+		}
+
+		if uint(version) > latest {
+			latest = uint(version)
+		}
+	}
+
+	return latest, nil
+}
+
+// CheckCompatibility reports whether a build whose newest migration is latest
+// can run against the schema at version.
+func CheckCompatibility(version uint, dirty bool, latest uint) error {
+	switch {
+	case dirty:
+		return fmt.Errorf("%w: version %d", ErrSchemaDirty, version)
+	case version > latest:
+		return fmt.Errorf("%w: schema %d, build knows up to %d", ErrSchemaAhead, version, latest)
+	case version < MinCompatibleVersion:
+		return fmt.Errorf("%w: schema %d, build needs at least %d", ErrSchemaBehind, version, MinCompatibleVersion)
+	}
+	return nil
+}
+
+// EnsureSchema applies pending migrations when autoMigrate is set and refuses
+// a schema this build is not compatible with. A schema ahead of the build is
+// never migrated, so an old release cannot fail halfway through Up.
+func EnsureSchema(cfg config.PostgresConfig, autoMigrate bool, log *slog.Logger) error {
+	const op = "migrator.EnsureSchema"
+
+	mg, err := New(cfg)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer mg.Close()
+
+	latest, err := LatestVersion()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	version, dirty, err := mg.Version()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if autoMigrate && !dirty && version < latest {
+		log.Info("applying database migrations",
+			slog.Uint64("from_version", uint64(version)),
+			slog.Uint64("to_version", uint64(latest)))
+
+		if err := mg.Up(); err != nil {
+			return fmt.Errorf("%s: migration failed: %w", op, err)
+		}
+
+		if version, dirty, err = mg.Version(); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := CheckCompatibility(version, dirty, latest); err != nil {
+		log.Error("database schema is not compatible with this build",
+			slog.Uint64("schema_version", uint64(version)),
+			slog.Bool("dirty", dirty),
+			slog.Uint64("min_compatible_version", MinCompatibleVersion),
+			slog.Uint64("latest_version", uint64(latest)))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("database schema is compatible",
+		slog.Uint64("schema_version", uint64(version)),
+		slog.Uint64("min_compatible_version", MinCompatibleVersion),
+		slog.Uint64("latest_version", uint64(latest)))
+
+	return nil
+}
+
+// RunMigrations up migrations files from embed.FS - migrationsFS
+func RunMigrations(cfg config.PostgresConfig, log *slog.Logger) error {
+	const op = "migrator.RunMigrations"
+
+	mg, err := New(cfg)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
 	}
-	defer m.Close()
+	defer mg.Close()
 
 	log.Info("applying database migrations")
-	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+	if err := mg.Up(); err != nil {
 		return fmt.Errorf("%s: migration failed: %w", op, err)
 	}
 
@@ -0,0 +1,8 @@
+ALTER TABLE teams
+    DROP COLUMN IF EXISTS default_required_skills,
+    DROP COLUMN IF EXISTS default_labels,
+    DROP COLUMN IF EXISTS default_priority;
+
+ALTER TABLE pull_requests
+    DROP COLUMN IF EXISTS required_skills,
+    DROP COLUMN IF EXISTS labels;
@@ -0,0 +1,9 @@
+ALTER TABLE pull_requests
+    ADD COLUMN IF NOT EXISTS labels          TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS required_skills TEXT[] NOT NULL DEFAULT '{}';
+
+ALTER TABLE teams
+    ADD COLUMN IF NOT EXISTS default_priority        VARCHAR(50) NULL
+        CHECK (default_priority IN ('LOW', 'NORMAL', 'HIGH', 'CRITICAL')),
+    ADD COLUMN IF NOT EXISTS default_labels          TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS default_required_skills TEXT[] NOT NULL DEFAULT '{}';
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS policy_versions
+(
+    team_name  VARCHAR(255) NOT NULL,
+    version    INTEGER      NOT NULL,
+    policy     JSONB        NOT NULL,
+    changes    TEXT[]       NOT NULL DEFAULT '{}',
+    actor_id   INTEGER      NULL,
+    created_at TIMESTAMP    NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (team_name, version),
+    FOREIGN KEY (team_name) REFERENCES teams (team_name) ON DELETE CASCADE
+    );
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS api_usage
+(
+    client_id VARCHAR(64) NOT NULL,
+    bucket    TIMESTAMP   NOT NULL,
+    requests  BIGINT      NOT NULL DEFAULT 0,
+    mutations BIGINT      NOT NULL DEFAULT 0,
+    PRIMARY KEY (client_id, bucket)
+    );
+
+CREATE INDEX idx_api_usage_bucket ON api_usage(bucket);
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS jobs
+(
+    name             VARCHAR(100) PRIMARY KEY,
+    interval_seconds INTEGER      NOT NULL,
+    last_started_at  TIMESTAMP    NULL,
+    last_duration_ms BIGINT       NULL,
+    last_outcome     VARCHAR(20)  NULL CHECK (last_outcome IN ('SUCCESS', 'FAILURE')),
+    last_error       TEXT         NULL,
+    next_run_at      TIMESTAMP    NULL
+    );
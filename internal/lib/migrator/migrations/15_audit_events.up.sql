@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS audit_events
+(
+    id         BIGSERIAL PRIMARY KEY,
+    team_name  VARCHAR(255) NULL,
+    action     VARCHAR(64)  NOT NULL,
+    subject_id INTEGER      NULL,
+    actor_id   INTEGER      NULL,
+    details    TEXT         NOT NULL DEFAULT '',
+    created_at TIMESTAMP    NOT NULL DEFAULT NOW()
+    );
+
+CREATE INDEX idx_audit_events_team ON audit_events(team_name, created_at);
@@ -0,0 +1,3 @@
+ALTER TABLE teams DROP COLUMN IF EXISTS review_labels;
+
+DROP TABLE IF EXISTS pr_review_teams;
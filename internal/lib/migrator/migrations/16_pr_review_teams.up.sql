@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS pr_review_teams
+(
+    pull_request_id VARCHAR(255) NOT NULL,
+    team_name       VARCHAR(255) NOT NULL,
+    reviewers       INTEGER      NOT NULL,
+    PRIMARY KEY (pull_request_id, team_name),
+    FOREIGN KEY (pull_request_id) REFERENCES pull_requests (pull_request_id) ON DELETE CASCADE,
+    FOREIGN KEY (team_name) REFERENCES teams (team_name) ON DELETE CASCADE
+    );
+
+ALTER TABLE teams ADD COLUMN IF NOT EXISTS review_labels TEXT[] NOT NULL DEFAULT '{}';
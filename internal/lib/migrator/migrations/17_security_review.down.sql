@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS pr_approvals;
+
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS changed_paths;
@@ -0,0 +1,11 @@
+ALTER TABLE pull_requests ADD COLUMN IF NOT EXISTS changed_paths TEXT[] NOT NULL DEFAULT '{}';
+
+CREATE TABLE IF NOT EXISTS pr_approvals
+(
+    pull_request_id VARCHAR(255) NOT NULL,
+    reviewer_id     INTEGER      NOT NULL,
+    approved_at     TIMESTAMP    NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (pull_request_id, reviewer_id),
+    FOREIGN KEY (pull_request_id) REFERENCES pull_requests (pull_request_id) ON DELETE CASCADE,
+    FOREIGN KEY (reviewer_id) REFERENCES users (user_id) ON DELETE CASCADE
+    );
@@ -0,0 +1,3 @@
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS required_certifications;
+
+DROP TABLE IF EXISTS user_certifications;
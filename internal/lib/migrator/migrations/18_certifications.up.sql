@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS user_certifications
+(
+    user_id      INTEGER      NOT NULL,
+    area         VARCHAR(64)  NOT NULL,
+    certified_at TIMESTAMP    NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, area),
+    FOREIGN KEY (user_id) REFERENCES users (user_id) ON DELETE CASCADE
+    );
+
+CREATE INDEX idx_user_certifications_area ON user_certifications(area);
+
+ALTER TABLE pull_requests ADD COLUMN IF NOT EXISTS required_certifications TEXT[] NOT NULL DEFAULT '{}';
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS api_tokens
+(
+    token_id     BIGSERIAL PRIMARY KEY,
+    name         VARCHAR(255) NOT NULL,
+    token_hash   CHAR(64)     NOT NULL UNIQUE,
+    scopes       TEXT[]       NOT NULL DEFAULT '{}',
+    expires_at   TIMESTAMP    NULL,
+    revoked_at   TIMESTAMP    NULL,
+    last_used_at TIMESTAMP    NULL,
+    created_at   TIMESTAMP    NOT NULL DEFAULT NOW(),
+    rotated_at   TIMESTAMP    NULL
+    );
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS impersonation_sessions
+(
+    session_id   BIGSERIAL PRIMARY KEY,
+    session_hash CHAR(64)  NOT NULL UNIQUE,
+    admin_id     INTEGER   NOT NULL,
+    user_id      INTEGER   NOT NULL,
+    reason       TEXT      NOT NULL,
+    created_at   TIMESTAMP NOT NULL DEFAULT NOW(),
+    expires_at   TIMESTAMP NOT NULL,
+    ended_at     TIMESTAMP NULL,
+    FOREIGN KEY (admin_id) REFERENCES users (user_id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users (user_id) ON DELETE CASCADE
+    );
@@ -0,0 +1,4 @@
+ALTER TABLE pull_requests
+    ADD COLUMN IF NOT EXISTS merged_by INTEGER NULL REFERENCES users (user_id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_pull_requests_merged_by ON pull_requests (merged_by) WHERE merged_by IS NOT NULL;
@@ -0,0 +1,2 @@
+ALTER TABLE pr_reviewers
+    ADD COLUMN IF NOT EXISTS review_completed_at TIMESTAMP NULL;
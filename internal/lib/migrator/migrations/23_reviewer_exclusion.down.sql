@@ -0,0 +1,7 @@
+ALTER TABLE pull_requests
+    DROP COLUMN IF EXISTS pairing_session,
+    DROP COLUMN IF EXISTS co_authors;
+
+ALTER TABLE teams
+    DROP COLUMN IF EXISTS exclude_pairing_session,
+    DROP COLUMN IF EXISTS exclude_co_authors;
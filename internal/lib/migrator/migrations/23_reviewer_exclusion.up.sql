@@ -0,0 +1,7 @@
+ALTER TABLE teams
+    ADD COLUMN IF NOT EXISTS exclude_co_authors      BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS exclude_pairing_session BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE pull_requests
+    ADD COLUMN IF NOT EXISTS co_authors      TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS pairing_session TEXT[] NOT NULL DEFAULT '{}';
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_pull_requests_auto_merge;
+
+ALTER TABLE pull_requests
+    DROP COLUMN IF EXISTS auto_merge_approvals,
+    DROP COLUMN IF EXISTS auto_merge;
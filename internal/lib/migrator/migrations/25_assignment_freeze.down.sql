@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_pull_requests_assignment_queued;
+
+ALTER TABLE pull_requests
+    DROP COLUMN IF EXISTS assignment_queued;
+
+DROP TABLE IF EXISTS assignment_freezes;
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS assignment_freezes (
+    freeze_id  SERIAL PRIMARY KEY,
+    team_name  VARCHAR(255) REFERENCES teams (team_name) ON DELETE CASCADE,
+    reason     TEXT        NOT NULL DEFAULT '',
+    starts_at  TIMESTAMP   NOT NULL DEFAULT NOW(),
+    ends_at    TIMESTAMP,
+    created_at TIMESTAMP   NOT NULL DEFAULT NOW(),
+    lifted_at  TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_assignment_freezes_active ON assignment_freezes (team_name) WHERE lifted_at IS NULL;
+
+ALTER TABLE pull_requests
+    ADD COLUMN IF NOT EXISTS assignment_queued BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS idx_pull_requests_assignment_queued ON pull_requests (created_at) WHERE assignment_queued;
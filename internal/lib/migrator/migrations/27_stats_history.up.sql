@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS stats_history
+(
+    snapshot_date        DATE             NOT NULL,
+    team_name            VARCHAR(255)     NOT NULL DEFAULT '',
+    total_prs            INTEGER          NOT NULL DEFAULT 0,
+    open_prs             INTEGER          NOT NULL DEFAULT 0,
+    merged_prs           INTEGER          NOT NULL DEFAULT 0,
+    avg_reviewers_per_pr DOUBLE PRECISION NOT NULL DEFAULT 0,
+    open_reviews         INTEGER          NOT NULL DEFAULT 0,
+    active_members       INTEGER          NOT NULL DEFAULT 0,
+    created_at           TIMESTAMP        NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (team_name, snapshot_date)
+);
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_pull_requests_reviewer_pool;
+
+ALTER TABLE pull_requests
+    DROP COLUMN IF EXISTS reviewer_pool;
+
+DROP TABLE IF EXISTS reviewer_pool_members;
+DROP TABLE IF EXISTS reviewer_pools;
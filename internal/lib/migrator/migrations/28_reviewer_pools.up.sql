@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS reviewer_pools (
+    pool_name        VARCHAR(255) PRIMARY KEY,
+    reviewers_per_pr INTEGER   NOT NULL DEFAULT 2 CHECK (reviewers_per_pr BETWEEN 1 AND 5),
+    created_at       TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS reviewer_pool_members (
+    pool_name VARCHAR(255) NOT NULL REFERENCES reviewer_pools (pool_name) ON DELETE CASCADE,
+    user_id   INTEGER      NOT NULL REFERENCES users (user_id) ON DELETE CASCADE,
+    added_at  TIMESTAMP    NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (pool_name, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_reviewer_pool_members_user ON reviewer_pool_members (user_id);
+
+ALTER TABLE pull_requests
+    ADD COLUMN IF NOT EXISTS reviewer_pool VARCHAR(255) REFERENCES reviewer_pools (pool_name) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_pull_requests_reviewer_pool ON pull_requests (reviewer_pool) WHERE reviewer_pool IS NOT NULL;
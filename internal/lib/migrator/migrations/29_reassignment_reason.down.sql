@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_assignment_history_reason;
+
+ALTER TABLE assignment_history
+    DROP COLUMN IF EXISTS reason;
@@ -0,0 +1,4 @@
+ALTER TABLE assignment_history
+    ADD COLUMN IF NOT EXISTS reason VARCHAR(32) NULL;
+
+CREATE INDEX IF NOT EXISTS idx_assignment_history_reason ON assignment_history (reason) WHERE reason IS NOT NULL;
@@ -0,0 +1,3 @@
+ALTER TABLE teams DROP COLUMN IF EXISTS hold_until_ci_green;
+
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS ci_status;
@@ -0,0 +1,6 @@
+ALTER TABLE pull_requests
+    ADD COLUMN IF NOT EXISTS ci_status VARCHAR(50) NOT NULL DEFAULT 'UNKNOWN'
+        CHECK (ci_status IN ('UNKNOWN', 'PENDING', 'SUCCESS', 'FAILURE'));
+
+ALTER TABLE teams
+    ADD COLUMN IF NOT EXISTS hold_until_ci_green BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,28 @@
+UPDATE teams t
+SET hold_until_ci_green     = COALESCE(t.hold_until_ci_green, o.hold_until_ci_green),
+    min_reviewers           = COALESCE(t.min_reviewers, o.min_reviewers),
+    default_priority        = NULLIF(COALESCE(t.default_priority, o.default_priority), ''),
+    default_labels          = COALESCE(t.default_labels, o.default_labels),
+    default_required_skills = COALESCE(t.default_required_skills, o.default_required_skills),
+    exclude_co_authors      = COALESCE(t.exclude_co_authors, o.exclude_co_authors),
+    exclude_pairing_session = COALESCE(t.exclude_pairing_session, o.exclude_pairing_session)
+FROM org_policy o;
+
+ALTER TABLE teams
+    DROP CONSTRAINT IF EXISTS teams_default_priority_check,
+    ADD CONSTRAINT teams_default_priority_check
+        CHECK (default_priority IN ('LOW', 'NORMAL', 'HIGH', 'CRITICAL')),
+    ALTER COLUMN hold_until_ci_green SET DEFAULT FALSE,
+    ALTER COLUMN hold_until_ci_green SET NOT NULL,
+    ALTER COLUMN min_reviewers SET DEFAULT 1,
+    ALTER COLUMN min_reviewers SET NOT NULL,
+    ALTER COLUMN default_labels SET DEFAULT '{}',
+    ALTER COLUMN default_labels SET NOT NULL,
+    ALTER COLUMN default_required_skills SET DEFAULT '{}',
+    ALTER COLUMN default_required_skills SET NOT NULL,
+    ALTER COLUMN exclude_co_authors SET DEFAULT FALSE,
+    ALTER COLUMN exclude_co_authors SET NOT NULL,
+    ALTER COLUMN exclude_pairing_session SET DEFAULT FALSE,
+    ALTER COLUMN exclude_pairing_session SET NOT NULL;
+
+DROP TABLE IF EXISTS org_policy;
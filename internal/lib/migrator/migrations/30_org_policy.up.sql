@@ -0,0 +1,41 @@
+CREATE TABLE IF NOT EXISTS org_policy (
+    id                      BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (id),
+    hold_until_ci_green     BOOLEAN     NOT NULL DEFAULT FALSE,
+    min_reviewers           INTEGER     NOT NULL DEFAULT 1 CHECK (min_reviewers >= 0),
+    default_priority        VARCHAR(50) NULL CHECK (default_priority IN ('LOW', 'NORMAL', 'HIGH', 'CRITICAL')),
+    default_labels          TEXT[]      NOT NULL DEFAULT '{}',
+    default_required_skills TEXT[]      NOT NULL DEFAULT '{}',
+    exclude_co_authors      BOOLEAN     NOT NULL DEFAULT FALSE,
+    exclude_pairing_session BOOLEAN     NOT NULL DEFAULT FALSE,
+    updated_at              TIMESTAMP   NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO org_policy DEFAULT VALUES ON CONFLICT DO NOTHING;
+
+-- NULL in a team policy column now means "inherit the org default". An empty
+-- default_priority is an explicit "no default priority" override.
+ALTER TABLE teams
+    ALTER COLUMN hold_until_ci_green DROP NOT NULL,
+    ALTER COLUMN hold_until_ci_green DROP DEFAULT,
+    ALTER COLUMN min_reviewers DROP NOT NULL,
+    ALTER COLUMN min_reviewers DROP DEFAULT,
+    ALTER COLUMN default_labels DROP NOT NULL,
+    ALTER COLUMN default_labels DROP DEFAULT,
+    ALTER COLUMN default_required_skills DROP NOT NULL,
+    ALTER COLUMN default_required_skills DROP DEFAULT,
+    ALTER COLUMN exclude_co_authors DROP NOT NULL,
+    ALTER COLUMN exclude_co_authors DROP DEFAULT,
+    ALTER COLUMN exclude_pairing_session DROP NOT NULL,
+    ALTER COLUMN exclude_pairing_session DROP DEFAULT,
+    DROP CONSTRAINT IF EXISTS teams_default_priority_check,
+    ADD CONSTRAINT teams_default_priority_check
+        CHECK (default_priority IN ('', 'LOW', 'NORMAL', 'HIGH', 'CRITICAL'));
+
+-- Values equal to the old column defaults become inherited; the seeded org
+-- defaults match them, so no effective policy changes.
+UPDATE teams SET hold_until_ci_green = NULL WHERE hold_until_ci_green = FALSE;
+UPDATE teams SET min_reviewers = NULL WHERE min_reviewers = 1;
+UPDATE teams SET default_labels = NULL WHERE default_labels = '{}';
+UPDATE teams SET default_required_skills = NULL WHERE default_required_skills = '{}';
+UPDATE teams SET exclude_co_authors = NULL WHERE exclude_co_authors = FALSE;
+UPDATE teams SET exclude_pairing_session = NULL WHERE exclude_pairing_session = FALSE;
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS team_webhooks (
+    id               BIGSERIAL PRIMARY KEY,
+    team_name        VARCHAR(255) NOT NULL REFERENCES teams (team_name) ON DELETE CASCADE,
+    url              TEXT         NOT NULL,
+    secret           TEXT         NOT NULL,
+    events           TEXT[]       NOT NULL DEFAULT '{}',
+    is_active        BOOLEAN      NOT NULL DEFAULT TRUE,
+    created_at       TIMESTAMP    NOT NULL DEFAULT NOW(),
+    last_delivery_at TIMESTAMP    NULL,
+    last_status      INTEGER      NULL,
+    last_error       TEXT         NULL,
+    UNIQUE (team_name, url)
+);
@@ -0,0 +1,5 @@
+ALTER TABLE users
+    DROP COLUMN IF EXISTS locale,
+    DROP COLUMN IF EXISTS avatar_url,
+    DROP COLUMN IF EXISTS email,
+    DROP COLUMN IF EXISTS display_name;
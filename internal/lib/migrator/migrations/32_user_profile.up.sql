@@ -0,0 +1,5 @@
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS display_name VARCHAR(255) NULL,
+    ADD COLUMN IF NOT EXISTS email        VARCHAR(320) NULL,
+    ADD COLUMN IF NOT EXISTS avatar_url   TEXT         NULL,
+    ADD COLUMN IF NOT EXISTS locale       VARCHAR(35)  NULL;
@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS admin_request_nonces (
+    nonce      VARCHAR(128) PRIMARY KEY,
+    created_at TIMESTAMP    NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_admin_request_nonces_created_at ON admin_request_nonces (created_at);
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS pr_events
+(
+    id              BIGSERIAL PRIMARY KEY,
+    pull_request_id VARCHAR(255) NOT NULL,
+    kind            VARCHAR(32)  NOT NULL,
+    actor_id        INTEGER      NULL,
+    details         TEXT         NOT NULL DEFAULT '',
+    created_at      TIMESTAMP    NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (pull_request_id) REFERENCES pull_requests (pull_request_id) ON DELETE CASCADE
+    );
+
+CREATE INDEX IF NOT EXISTS idx_pr_events_pr ON pr_events (pull_request_id, created_at);
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS team_leads
+(
+    team_name  VARCHAR(255) NOT NULL,
+    user_id    INTEGER      NOT NULL,
+    created_at TIMESTAMP    NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (team_name, user_id),
+    FOREIGN KEY (team_name) REFERENCES teams (team_name) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users (user_id) ON DELETE CASCADE
+    );
+
+CREATE INDEX IF NOT EXISTS idx_team_leads_user ON team_leads (user_id);
@@ -0,0 +1,4 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS idx_pull_requests_name_trgm ON pull_requests USING gin (pull_request_name gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_pull_requests_created_at ON pull_requests (created_at, pull_request_id);
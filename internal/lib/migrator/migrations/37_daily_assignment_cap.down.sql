@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS reviewer_daily_assignments;
+
+ALTER TABLE users DROP COLUMN IF EXISTS max_daily_assignments;
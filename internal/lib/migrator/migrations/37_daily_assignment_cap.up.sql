@@ -0,0 +1,10 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS max_daily_assignments INTEGER NOT NULL DEFAULT 0 CHECK (max_daily_assignments >= 0);
+
+CREATE TABLE IF NOT EXISTS reviewer_daily_assignments
+(
+    user_id     INTEGER NOT NULL,
+    day         DATE    NOT NULL,
+    assignments INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (user_id, day),
+    FOREIGN KEY (user_id) REFERENCES users (user_id) ON DELETE CASCADE
+    );
@@ -0,0 +1,3 @@
+ALTER TABLE teams DROP COLUMN IF EXISTS diversify_reviewer_pairs;
+
+ALTER TABLE org_policy DROP COLUMN IF EXISTS diversify_reviewer_pairs;
@@ -0,0 +1,4 @@
+ALTER TABLE org_policy ADD COLUMN IF NOT EXISTS diversify_reviewer_pairs BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- NULL inherits the org default, like the other team policy columns.
+ALTER TABLE teams ADD COLUMN IF NOT EXISTS diversify_reviewer_pairs BOOLEAN NULL;
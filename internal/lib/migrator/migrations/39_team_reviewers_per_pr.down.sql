@@ -0,0 +1,3 @@
+ALTER TABLE teams DROP COLUMN IF EXISTS reviewers_per_pr;
+
+ALTER TABLE org_policy DROP COLUMN IF EXISTS reviewers_per_pr;
@@ -0,0 +1,6 @@
+ALTER TABLE org_policy
+    ADD COLUMN IF NOT EXISTS reviewers_per_pr INTEGER NOT NULL DEFAULT 2 CHECK (reviewers_per_pr BETWEEN 1 AND 5);
+
+-- NULL inherits the org default, like the other team policy columns.
+ALTER TABLE teams
+    ADD COLUMN IF NOT EXISTS reviewers_per_pr INTEGER NULL CHECK (reviewers_per_pr BETWEEN 1 AND 5);
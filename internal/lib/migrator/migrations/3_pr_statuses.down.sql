@@ -0,0 +1,6 @@
+ALTER TABLE pull_requests DROP CONSTRAINT IF EXISTS pull_requests_status_fkey;
+ALTER TABLE pull_requests
+    ADD CONSTRAINT pull_requests_status_check CHECK (status IN ('OPEN', 'MERGED'));
+
+DROP TABLE IF EXISTS pr_status_transitions;
+DROP TABLE IF EXISTS pr_statuses;
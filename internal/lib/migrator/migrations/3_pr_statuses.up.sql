@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS pr_statuses
+(
+    status      VARCHAR(50) PRIMARY KEY,
+    is_terminal BOOLEAN NOT NULL DEFAULT false
+    );
+
+INSERT INTO pr_statuses (status, is_terminal) VALUES
+    ('OPEN', false),
+    ('MERGED', true)
+ON CONFLICT DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS pr_status_transitions
+(
+    from_status VARCHAR(50) NOT NULL,
+    to_status   VARCHAR(50) NOT NULL,
+    PRIMARY KEY (from_status, to_status),
+    FOREIGN KEY (from_status) REFERENCES pr_statuses (status) ON DELETE CASCADE,
+    FOREIGN KEY (to_status) REFERENCES pr_statuses (status) ON DELETE CASCADE
+    );
+
+INSERT INTO pr_status_transitions (from_status, to_status) VALUES
+    ('OPEN', 'MERGED')
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE pull_requests DROP CONSTRAINT IF EXISTS pull_requests_status_check;
+ALTER TABLE pull_requests
+    ADD CONSTRAINT pull_requests_status_fkey FOREIGN KEY (status) REFERENCES pr_statuses (status) ON DELETE RESTRICT;
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS notification_templates (
+    event      VARCHAR(64)  NOT NULL,
+    channel    VARCHAR(32)  NOT NULL,
+    body       TEXT         NOT NULL,
+    updated_at TIMESTAMP    NOT NULL DEFAULT NOW(),
+    updated_by INTEGER      NULL REFERENCES users (user_id) ON DELETE SET NULL,
+    PRIMARY KEY (event, channel)
+);
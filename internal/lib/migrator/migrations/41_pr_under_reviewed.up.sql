@@ -0,0 +1,2 @@
+ALTER TABLE pull_requests
+    ADD COLUMN IF NOT EXISTS under_reviewed BOOLEAN NOT NULL DEFAULT FALSE;
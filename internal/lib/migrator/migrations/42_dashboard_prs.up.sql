@@ -0,0 +1,57 @@
+CREATE TABLE IF NOT EXISTS dashboard_prs (
+    pull_request_id   VARCHAR(255) PRIMARY KEY REFERENCES pull_requests (pull_request_id) ON DELETE CASCADE,
+    pull_request_name VARCHAR(255) NOT NULL,
+    author_id         INTEGER      NOT NULL,
+    author_name       VARCHAR(255) NOT NULL,
+    team_name         VARCHAR(255) NOT NULL,
+    status            VARCHAR(50)  NOT NULL,
+    is_terminal       BOOLEAN      NOT NULL DEFAULT FALSE,
+    ci_status         VARCHAR(50)  NOT NULL,
+    priority          VARCHAR(50)  NOT NULL,
+    under_reviewed    BOOLEAN      NOT NULL DEFAULT FALSE,
+    reviewers         TEXT[]       NOT NULL DEFAULT '{}',
+    approvers         TEXT[]       NOT NULL DEFAULT '{}',
+    created_at        TIMESTAMP    NOT NULL,
+    merged_at         TIMESTAMP    NULL,
+    projected_at      TIMESTAMP    NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_dashboard_prs_team ON dashboard_prs (team_name, created_at, pull_request_id);
+CREATE INDEX IF NOT EXISTS idx_dashboard_prs_open ON dashboard_prs (created_at, pull_request_id) WHERE NOT is_terminal;
+CREATE INDEX IF NOT EXISTS idx_dashboard_prs_reviewers ON dashboard_prs USING gin (reviewers);
+
+INSERT INTO dashboard_prs (
+    pull_request_id, pull_request_name, author_id, author_name, team_name,
+    status, is_terminal, ci_status, priority, under_reviewed,
+    reviewers, approvers, created_at, merged_at
+)
+SELECT
+    pr.pull_request_id,
+    pr.pull_request_name,
+    pr.author_id,
+    u.username,
+    u.team_name,
+    pr.status,
+    COALESCE(ps.is_terminal, FALSE),
+    pr.ci_status,
+    pr.priority,
+    pr.under_reviewed,
+    ARRAY(
+        SELECT 'u' || prr.reviewer_id
+        FROM pr_reviewers prr
+        WHERE prr.pull_request_id = pr.pull_request_id
+        ORDER BY prr.reviewer_id
+    ),
+    ARRAY(
+        SELECT 'u' || a.reviewer_id
+        FROM pr_approvals a
+        JOIN pr_reviewers prr ON prr.pull_request_id = a.pull_request_id AND prr.reviewer_id = a.reviewer_id
+        WHERE a.pull_request_id = pr.pull_request_id
+        ORDER BY a.approved_at
+    ),
+    COALESCE(pr.created_at, NOW()),
+    pr.merged_at
+FROM pull_requests pr
+JOIN users u ON u.user_id = pr.author_id
+LEFT JOIN pr_statuses ps ON ps.status = pr.status
+ON CONFLICT (pull_request_id) DO NOTHING;
@@ -0,0 +1,7 @@
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS author_team;
+
+DROP INDEX IF EXISTS idx_team_members_secondary;
+
+DELETE FROM team_members WHERE NOT is_primary;
+
+ALTER TABLE team_members DROP COLUMN IF EXISTS is_primary;
@@ -0,0 +1,7 @@
+ALTER TABLE team_members
+    ADD COLUMN IF NOT EXISTS is_primary BOOLEAN NOT NULL DEFAULT TRUE;
+
+CREATE INDEX IF NOT EXISTS idx_team_members_secondary ON team_members (team_name, user_id) WHERE NOT is_primary;
+
+ALTER TABLE pull_requests
+    ADD COLUMN IF NOT EXISTS author_team VARCHAR(255) NULL REFERENCES teams (team_name) ON DELETE SET NULL;
@@ -0,0 +1,2 @@
+ALTER TABLE assignment_history
+    DROP COLUMN IF EXISTS note;
@@ -0,0 +1,2 @@
+ALTER TABLE assignment_history
+    ADD COLUMN IF NOT EXISTS note TEXT NULL;
@@ -0,0 +1,3 @@
+ALTER TABLE users
+    DROP COLUMN IF EXISTS author_only_until,
+    DROP COLUMN IF EXISTS author_only;
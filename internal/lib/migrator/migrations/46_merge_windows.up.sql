@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS team_merge_windows (
+    team_name    VARCHAR(255) PRIMARY KEY REFERENCES teams (team_name) ON DELETE CASCADE,
+    time_zone    VARCHAR(64)  NOT NULL DEFAULT 'UTC',
+    warn_minutes INTEGER      NOT NULL DEFAULT 0 CHECK (warn_minutes BETWEEN 0 AND 1440),
+    blocks       JSONB        NOT NULL DEFAULT '[]',
+    updated_at   TIMESTAMP    NOT NULL DEFAULT NOW()
+);
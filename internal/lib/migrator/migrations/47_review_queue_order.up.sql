@@ -0,0 +1,2 @@
+ALTER TABLE pr_reviewers
+    ADD COLUMN IF NOT EXISTS queue_position INT NULL;
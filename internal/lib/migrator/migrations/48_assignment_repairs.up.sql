@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS assignment_repairs
+(
+    pull_request_id VARCHAR(255) PRIMARY KEY,
+    attempts        INTEGER   NOT NULL DEFAULT 0,
+    last_error      TEXT      NOT NULL DEFAULT '',
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    alerted_at      TIMESTAMP NULL,
+    created_at      TIMESTAMP NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (pull_request_id) REFERENCES pull_requests (pull_request_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_assignment_repairs_next_attempt ON assignment_repairs(next_attempt_at);
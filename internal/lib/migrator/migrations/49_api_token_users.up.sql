@@ -0,0 +1,2 @@
+ALTER TABLE api_tokens
+    ADD COLUMN IF NOT EXISTS user_id INTEGER NULL REFERENCES users (user_id) ON DELETE CASCADE;
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS team_pr_status_transitions;
+DELETE FROM pr_statuses
+WHERE status IN ('IN_REVIEW', 'APPROVED', 'BLOCKED')
+  AND NOT EXISTS (SELECT 1 FROM pull_requests WHERE pull_requests.status = pr_statuses.status);
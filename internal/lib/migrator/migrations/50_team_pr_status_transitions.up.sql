@@ -0,0 +1,16 @@
+INSERT INTO pr_statuses (status, is_terminal) VALUES
+    ('IN_REVIEW', false),
+    ('APPROVED', false),
+    ('BLOCKED', false)
+ON CONFLICT DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS team_pr_status_transitions
+(
+    team_name   VARCHAR(255) NOT NULL,
+    from_status VARCHAR(50)  NOT NULL,
+    to_status   VARCHAR(50)  NOT NULL,
+    PRIMARY KEY (team_name, from_status, to_status),
+    FOREIGN KEY (team_name) REFERENCES teams (team_name) ON DELETE CASCADE,
+    FOREIGN KEY (from_status) REFERENCES pr_statuses (status) ON DELETE CASCADE,
+    FOREIGN KEY (to_status) REFERENCES pr_statuses (status) ON DELETE CASCADE
+    );
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS api_quota_counters;
+ALTER TABLE api_tokens DROP COLUMN IF EXISTS hourly_quota;
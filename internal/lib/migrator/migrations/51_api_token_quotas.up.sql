@@ -0,0 +1,12 @@
+ALTER TABLE api_tokens
+    ADD COLUMN IF NOT EXISTS hourly_quota INTEGER NULL CHECK (hourly_quota >= 0);
+
+CREATE TABLE IF NOT EXISTS api_quota_counters
+(
+    client_id    VARCHAR(64) NOT NULL,
+    window_start TIMESTAMP   NOT NULL,
+    requests     BIGINT      NOT NULL DEFAULT 0,
+    PRIMARY KEY (client_id, window_start)
+    );
+
+CREATE INDEX IF NOT EXISTS idx_api_quota_counters_window ON api_quota_counters (window_start);
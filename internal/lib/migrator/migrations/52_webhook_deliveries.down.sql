@@ -0,0 +1,15 @@
+UPDATE team_webhooks
+SET events = ARRAY(
+        SELECT CASE e
+                   WHEN 'pr.created' THEN 'pull_request.created'
+                   WHEN 'pr.merged' THEN 'pull_request.merged'
+                   WHEN 'pr.reviewers_released' THEN 'pull_request.reviewers_released'
+                   WHEN 'reviewer.assigned' THEN 'review.assigned'
+                   WHEN 'reviewer.reassigned' THEN 'review.reassigned'
+                   WHEN 'reviewer.delegated' THEN 'review.delegated'
+                   WHEN 'reviewer.unassigned' THEN 'review.unassigned'
+                   ELSE e
+                   END
+        FROM unnest(events) AS e);
+
+DROP TABLE IF EXISTS webhook_deliveries;
@@ -0,0 +1,33 @@
+CREATE TABLE IF NOT EXISTS webhook_deliveries
+(
+    id              BIGSERIAL PRIMARY KEY,
+    webhook_id      BIGINT       NOT NULL REFERENCES team_webhooks (id) ON DELETE CASCADE,
+    event           VARCHAR(64)  NOT NULL,
+    pull_request_id VARCHAR(255) NOT NULL,
+    body            BYTEA        NOT NULL,
+    attempt         INTEGER      NOT NULL,
+    status          VARCHAR(16)  NOT NULL CHECK (status IN ('PENDING', 'FAILED')),
+    next_attempt_at TIMESTAMP    NULL,
+    last_status     INTEGER      NULL,
+    last_error      TEXT         NULL,
+    created_at      TIMESTAMP    NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMP    NOT NULL DEFAULT NOW()
+    );
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries (next_attempt_at) WHERE status = 'PENDING';
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_failed ON webhook_deliveries (updated_at) WHERE status = 'FAILED';
+
+-- Subscriptions are stored under the event names sent to receivers.
+UPDATE team_webhooks
+SET events = ARRAY(
+        SELECT CASE e
+                   WHEN 'pull_request.created' THEN 'pr.created'
+                   WHEN 'pull_request.merged' THEN 'pr.merged'
+                   WHEN 'pull_request.reviewers_released' THEN 'pr.reviewers_released'
+                   WHEN 'review.assigned' THEN 'reviewer.assigned'
+                   WHEN 'review.reassigned' THEN 'reviewer.reassigned'
+                   WHEN 'review.delegated' THEN 'reviewer.delegated'
+                   WHEN 'review.unassigned' THEN 'reviewer.unassigned'
+                   ELSE e
+                   END
+        FROM unnest(events) AS e);
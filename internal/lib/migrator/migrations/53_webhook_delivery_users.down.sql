@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_webhook_deliveries_users;
+
+ALTER TABLE webhook_deliveries
+    DROP COLUMN IF EXISTS user_ids;
@@ -0,0 +1,12 @@
+ALTER TABLE webhook_deliveries
+    ADD COLUMN IF NOT EXISTS user_ids INTEGER[] NOT NULL DEFAULT '{}';
+
+-- Stored bodies list the users they mention under "users", keyed "u<id>".
+UPDATE webhook_deliveries
+SET user_ids = ARRAY(
+        SELECT substr(k, 2)::INTEGER
+        FROM jsonb_object_keys(convert_from(body, 'UTF8')::jsonb -> 'users') AS k
+        WHERE k ~ '^u[0-9]{1,9}$')
+WHERE jsonb_typeof(convert_from(body, 'UTF8')::jsonb -> 'users') = 'object';
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_users ON webhook_deliveries USING GIN (user_ids);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_team_members_user_id;
+
+DROP INDEX IF EXISTS idx_users_team_name_is_active;
+CREATE INDEX IF NOT EXISTS idx_users_team_active ON users(team_name, is_active) WHERE is_active = true;
+
+DROP INDEX IF EXISTS idx_pull_requests_status_author_id;
@@ -0,0 +1,8 @@
+CREATE INDEX IF NOT EXISTS idx_pr_reviewers_reviewer_id ON pr_reviewers(reviewer_id);
+
+CREATE INDEX IF NOT EXISTS idx_pull_requests_status_author_id ON pull_requests(status, author_id);
+
+DROP INDEX IF EXISTS idx_users_team_active;
+CREATE INDEX IF NOT EXISTS idx_users_team_name_is_active ON users(team_name, is_active);
+
+CREATE INDEX IF NOT EXISTS idx_team_members_user_id ON team_members(user_id);
@@ -0,0 +1,3 @@
+ALTER TABLE pull_requests
+    ADD COLUMN IF NOT EXISTS priority VARCHAR(50) NOT NULL DEFAULT 'NORMAL'
+        CHECK (priority IN ('LOW', 'NORMAL', 'HIGH', 'CRITICAL'));
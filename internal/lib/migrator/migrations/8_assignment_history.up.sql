@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS assignment_history
+(
+    id              BIGSERIAL PRIMARY KEY,
+    pull_request_id VARCHAR(255) NOT NULL,
+    reviewer_id     INTEGER      NOT NULL,
+    action          VARCHAR(32)  NOT NULL,
+    actor_id        INTEGER      NULL,
+    created_at      TIMESTAMP    NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (pull_request_id) REFERENCES pull_requests (pull_request_id) ON DELETE CASCADE,
+    FOREIGN KEY (reviewer_id) REFERENCES users (user_id) ON DELETE CASCADE
+    );
+
+CREATE INDEX idx_assignment_history_pr ON assignment_history(pull_request_id, created_at);
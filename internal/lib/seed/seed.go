@@ -0,0 +1,142 @@
+package seed
+
+import (
+	"database/sql"
+	"fmt"
+	"math/rand"
+	"pull-request-assigner/internal/domain/models"
+	"time"
+)
+
+// FirstUserID keeps demo users clear of ids handed out by hand.
+const FirstUserID = 1000
+
+type Options struct {
+	Seed         int64
+	PullRequests int
+	Days         int
+	Now          time.Time
+}
+
+var teams = []struct {
+	name   string
+	people []string
+}{
+	{"payments", []string{"Alice", "Boris", "Chen", "Dana", "Egor", "Fatima"}},
+	{"platform", []string{"Grace", "Hugo", "Irina", "Jamal", "Kira", "Leo"}},
+	{"mobile", []string{"Maya", "Nikita", "Olga", "Pavel", "Quinn", "Rita"}},
+	{"frontend", []string{"Sergey", "Tanya", "Umar", "Vera", "Will", "Yana"}},
+	{"data", []string{"Zoe", "Artem", "Bella", "Denis", "Elena", "Fedor"}},
+}
+
+var (
+	priorities = []string{models.PriorityLow, models.PriorityNormal, models.PriorityNormal, models.PriorityHigh, models.PriorityCritical}
+	labels     = []string{"bug", "feature", "refactoring", "docs", "security", "performance"}
+	subjects   = []string{"Fix", "Add", "Refactor", "Speed up", "Document", "Remove"}
+	objects    = []string{"login flow", "payment retries", "cache layer", "push notifications", "CSV export", "search index", "rate limiter", "onboarding"}
+)
+
+// Generate builds a dataset deterministic for the given seed: the last member
+// of every team is inactive, roughly 60% of PRs are merged and open PRs have
+// reviews in every state.
+func Generate(opts Options) models.SeedDataset {
+	rnd := rand.New(rand.NewSource(opts.Seed))
+
+	dataset := models.SeedDataset{}
+
+	nextID := FirstUserID
+	for _, t := range teams {
+		team := models.SeedTeam{Name: t.name}
+		for i, person := range t.people {
+			team.Members = append(team.Members, models.User{
+				UserID:   models.UserID(nextID).String(),
+				Username: person,
+				TeamName: t.name,
+				IsActive: i < len(t.people)-1,
+			})
+			nextID++
+		}
+		dataset.Teams = append(dataset.Teams, team)
+	}
+
+	window := time.Duration(opts.Days) * 24 * time.Hour
+
+	for i := 1; i <= opts.PullRequests; i++ {
+		team := dataset.Teams[rnd.Intn(len(dataset.Teams))]
+		active := team.Members[:len(team.Members)-1]
+
+		author := active[rnd.Intn(len(active))]
+		createdAt := opts.Now.Add(-time.Duration(rnd.Int63n(int64(window)))).Truncate(time.Second)
+
+		pr := models.SeedPullRequest{
+			PullRequest: models.PullRequest{
+				PullRequestId:   fmt.Sprintf("DEMO-%d", i),
+				PullRequestName: fmt.Sprintf("%s %s", subjects[rnd.Intn(len(subjects))], objects[rnd.Intn(len(objects))]),
+				AuthorID:        author.UserID,
+				Status:          models.PRStatusOpen,
+				CIStatus:        models.CIStatusSuccess,
+				Priority:        priorities[rnd.Intn(len(priorities))],
+				Labels:          []string{labels[rnd.Intn(len(labels))]},
+				CreatedAt:       createdAt,
+			},
+		}
+
+		merged := rnd.Float64() < 0.6
+		for _, reviewer := range pickReviewers(rnd, active, author.UserID, 2) {
+			review := models.SeedReview{ReviewerID: reviewer, AssignedAt: createdAt}
+
+			state := rnd.Intn(3)
+			if merged {
+				state = 2
+			}
+			if state >= 1 {
+				started := notAfter(createdAt.Add(time.Duration(1+rnd.Intn(8))*time.Hour), opts.Now)
+				review.StartedAt = &started
+			}
+			if state == 2 {
+				completed := notAfter(review.StartedAt.Add(time.Duration(1+rnd.Intn(24))*time.Hour), opts.Now)
+				review.CompletedAt = &completed
+			}
+
+			pr.Reviews = append(pr.Reviews, review)
+		}
+
+		if merged {
+			mergedAt := notAfter(createdAt.Add(time.Duration(12+rnd.Intn(72))*time.Hour), opts.Now)
+			pr.Status = models.PRStatusMerged
+			pr.MergedAt = sql.NullTime{Time: mergedAt, Valid: true}
+			pr.MergedBy = author.UserID
+		} else if rnd.Intn(4) == 0 {
+			pr.CIStatus = models.CIStatusPending
+		}
+
+		dataset.PullRequests = append(dataset.PullRequests, pr)
+	}
+
+	return dataset
+}
+
+func notAfter(t time.Time, limit time.Time) time.Time {
+	if t.After(limit) {
+		return limit
+	}
+	return t
+}
+
+func pickReviewers(rnd *rand.Rand, members []models.User, authorID string, max int) []string {
+	candidates := make([]string, 0, len(members))
+	for _, member := range members {
+		if member.UserID != authorID {
+			candidates = append(candidates, member.UserID)
+		}
+	}
+
+	rnd.Shuffle(len(candidates), func(i, j int) {
+		candidates[i], candidates[j] = candidates[j], candidates[i]
+	})
+
+	if len(candidates) > max {
+		candidates = candidates[:max]
+	}
+	return candidates
+}
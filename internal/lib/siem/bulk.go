@@ -0,0 +1,51 @@
+package siem
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"pull-request-assigner/internal/domain/models"
+)
+
+// bulk posts each batch as one JSON array.
+type bulk struct {
+	client *http.Client
+	url    string
+	token  string
+}
+
+func (b *bulk) Ship(ctx context.Context, entries []models.AuditEvent) error {
+	body, err := json.Marshal(entries)
+	if err != nil {
+		return fmt.Errorf("siem: encode batch: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if b.token != "" {
+		req.Header.Set("Authorization", "Bearer "+b.token)
+	}
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("siem: post batch: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode <= 299:
+		return nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError:
+		return fmt.Errorf("siem: unexpected status %d", resp.StatusCode)
+	}
+
+	return fmt.Errorf("%w: status %d", ErrRejected, resp.StatusCode)
+}
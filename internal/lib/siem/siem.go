@@ -0,0 +1,76 @@
+package siem
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"pull-request-assigner/internal/domain/models"
+	"time"
+)
+
+const (
+	KindHTTP   = "http"
+	KindSyslog = "syslog"
+)
+
+var (
+	ErrUnknownKind = errors.New("siem: unknown kind")
+	ErrInvalidURL  = errors.New("siem: invalid url")
+	// ErrRejected marks a batch the collector refused as such; sending it
+	// again gets the same answer.
+	ErrRejected = errors.New("siem: batch rejected")
+)
+
+// Shipper sends a batch of audit entries to a SIEM collector.
+type Shipper interface {
+	Ship(ctx context.Context, entries []models.AuditEvent) error
+}
+
+type Config struct {
+	Kind string
+	// URL is the HTTPS bulk endpoint for KindHTTP, and udp://host:port or
+	// tcp://host:port of the collector for KindSyslog.
+	URL   string
+	Token string
+
+	// AppName and Hostname identify this service in syslog headers.
+	AppName  string
+	Hostname string
+
+	Timeout time.Duration
+}
+
+// New returns the shipper for cfg.Kind; a nil shipper and error mean no SIEM
+// is configured.
+func New(cfg Config) (Shipper, error) {
+	switch cfg.Kind {
+	case "":
+		return nil, nil
+	case KindHTTP:
+		parsed, err := url.Parse(cfg.URL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return nil, fmt.Errorf("%w: %q is not an absolute http(s) URL", ErrInvalidURL, cfg.URL)
+		}
+		return &bulk{
+			client: &http.Client{Timeout: cfg.Timeout},
+			url:    cfg.URL,
+			token:  cfg.Token,
+		}, nil
+	case KindSyslog:
+		parsed, err := url.Parse(cfg.URL)
+		if err != nil || (parsed.Scheme != "udp" && parsed.Scheme != "tcp") || parsed.Host == "" {
+			return nil, fmt.Errorf("%w: %q is not a udp:// or tcp:// address", ErrInvalidURL, cfg.URL)
+		}
+		return &syslog{
+			network:  parsed.Scheme,
+			address:  parsed.Host,
+			appName:  headerField(cfg.AppName),
+			hostname: headerField(cfg.Hostname),
+			timeout:  cfg.Timeout,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("%w: %q", ErrUnknownKind, cfg.Kind)
+}
@@ -0,0 +1,91 @@
+package siem
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"pull-request-assigner/internal/domain/models"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// priority is facility authpriv (10) at severity notice (5), RFC 5424.
+const priority = 10*8 + 5
+
+// syslog writes one RFC 5424 message per entry, the entry's JSON being the
+// message body. Over TCP messages are framed by octet counting (RFC 6587);
+// over UDP each message is its own datagram.
+type syslog struct {
+	network  string
+	address  string
+	appName  string
+	hostname string
+	timeout  time.Duration
+}
+
+func (s *syslog) Ship(ctx context.Context, entries []models.AuditEvent) error {
+	dialer := net.Dialer{Timeout: s.timeout}
+	conn, err := dialer.DialContext(ctx, s.network, s.address)
+	if err != nil {
+		return fmt.Errorf("siem: dial %s: %w", s.network, err)
+	}
+	defer conn.Close()
+
+	if s.timeout > 0 {
+		_ = conn.SetWriteDeadline(time.Now().Add(s.timeout))
+	}
+
+	for _, entry := range entries {
+		msg, err := s.message(entry)
+		if err != nil {
+			return err
+		}
+
+		if s.network == "tcp" {
+			msg = append([]byte(strconv.Itoa(len(msg))+" "), msg...)
+		}
+
+		if _, err := conn.Write(msg); err != nil {
+			return fmt.Errorf("siem: write %s: %w", s.network, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *syslog) message(entry models.AuditEvent) ([]byte, error) {
+	body, err := json.Marshal(entry)
+	if err != nil {
+		return nil, fmt.Errorf("siem: encode entry: %w", err)
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "<%d>1 %s %s %s - %s - ",
+		priority,
+		entry.CreatedAt.UTC().Format(time.RFC3339Nano),
+		s.hostname,
+		s.appName,
+		headerField(entry.Action))
+	msg.Write(body)
+
+	return msg.Bytes(), nil
+}
+
+// headerField makes value a valid syslog header field: printable ASCII
+// without spaces, "-" when empty.
+func headerField(value string) string {
+	value = strings.Map(func(r rune) rune {
+		if r <= ' ' || r > '~' {
+			return -1
+		}
+		return r
+	}, value)
+
+	if value == "" {
+		return "-"
+	}
+	return value
+}
@@ -0,0 +1,141 @@
+package siem
+
+import (
+	"context"
+	"io"
+	"net"
+	"pull-request-assigner/internal/domain/models"
+	"strconv"
+	"testing"
+	"time"
+)
+
+var syslogEntry = models.AuditEvent{
+	ID:        42,
+	TeamName:  "Backend",
+	Action:    "pr.reassign",
+	SubjectID: "pr-1",
+	ActorID:   "u1",
+	CreatedAt: time.Date(2026, 3, 4, 5, 6, 7, 890000000, time.FixedZone("MSK", 3*60*60)),
+}
+
+const syslogGolden = `<85>1 2026-03-04T02:06:07.89Z host-1 pr-assigner - pr.reassign - ` +
+	`{"id":42,"team_name":"Backend","action":"pr.reassign","subject_id":"pr-1","actor_id":"u1","created_at":"2026-03-04T05:06:07.89+03:00"}`
+
+func TestSyslogMessage(t *testing.T) {
+	tests := []struct {
+		name     string
+		appName  string
+		hostname string
+		action   string
+		want     string
+	}{
+		{
+			name:     "golden",
+			appName:  "pr-assigner",
+			hostname: "host-1",
+			action:   "pr.reassign",
+			want:     syslogGolden,
+		},
+		{
+			name:   "header fields",
+			action: "pr reassign\n",
+			want: `<85>1 2026-03-04T02:06:07.89Z - - - prreassign - ` +
+				`{"id":42,"team_name":"Backend","action":"pr reassign\n","subject_id":"pr-1","actor_id":"u1","created_at":"2026-03-04T05:06:07.89+03:00"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &syslog{appName: headerField(tt.appName), hostname: headerField(tt.hostname)}
+			entry := syslogEntry
+			entry.Action = tt.action
+
+			msg, err := s.message(entry)
+			if err != nil {
+				t.Fatalf("failed to format message: %v", err)
+			}
+			if string(msg) != tt.want {
+				t.Fatalf("unexpected message\n got: %s\nwant: %s", msg, tt.want)
+			}
+		})
+	}
+}
+
+func TestSyslogShipTCPFraming(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer listener.Close()
+
+	received := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			received <- ""
+			return
+		}
+		defer conn.Close()
+		data, _ := io.ReadAll(conn)
+		received <- string(data)
+	}()
+
+	shipper, err := New(Config{
+		Kind:     KindSyslog,
+		URL:      "tcp://" + listener.Addr().String(),
+		AppName:  "pr-assigner",
+		Hostname: "host-1",
+		Timeout:  time.Second,
+	})
+	if err != nil {
+		t.Fatalf("failed to create shipper: %v", err)
+	}
+
+	if err := shipper.Ship(context.Background(), []models.AuditEvent{syslogEntry, syslogEntry}); err != nil {
+		t.Fatalf("failed to ship: %v", err)
+	}
+
+	frame := strconv.Itoa(len(syslogGolden)) + " " + syslogGolden
+	select {
+	case got := <-received:
+		if got != frame+frame {
+			t.Fatalf("unexpected stream\n got: %q\nwant: %q", got, frame+frame)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("collector received nothing")
+	}
+}
+
+func TestSyslogShipUDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer conn.Close()
+
+	shipper, err := New(Config{
+		Kind:     KindSyslog,
+		URL:      "udp://" + conn.LocalAddr().String(),
+		AppName:  "pr-assigner",
+		Hostname: "host-1",
+		Timeout:  time.Second,
+	})
+	if err != nil {
+		t.Fatalf("failed to create shipper: %v", err)
+	}
+
+	if err := shipper.Ship(context.Background(), []models.AuditEvent{syslogEntry}); err != nil {
+		t.Fatalf("failed to ship: %v", err)
+	}
+
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	buf := make([]byte, 2048)
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("collector received nothing: %v", err)
+	}
+	if got := string(buf[:n]); got != syslogGolden {
+		t.Fatalf("unexpected datagram\n got: %s\nwant: %s", got, syslogGolden)
+	}
+}
@@ -0,0 +1,23 @@
+package trace
+
+import (
+	"context"
+	"pull-request-assigner/internal/domain/models"
+)
+
+type contextKey string
+
+const assignmentKey contextKey = "assignment_trace"
+
+// WithAssignment asks the services to record their reviewer assignment
+// decisions in t.
+func WithAssignment(ctx context.Context, t *models.AssignmentTrace) context.Context {
+	return context.WithValue(ctx, assignmentKey, t)
+}
+
+// Assignment returns the trace requested with WithAssignment, or nil when the
+// request is not being traced.
+func Assignment(ctx context.Context) *models.AssignmentTrace {
+	t, _ := ctx.Value(assignmentKey).(*models.AssignmentTrace)
+	return t
+}
@@ -0,0 +1,149 @@
+// Package xlsx writes single-sheet spreadsheets row by row, so large
+// exports never have to be held in memory.
+package xlsx
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const ContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+
+var staticParts = []struct {
+	name    string
+	content string
+}{
+	{"[Content_Types].xml", xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`</Types>`},
+	{"_rels/.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`},
+	{"xl/_rels/workbook.xml.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`</Relationships>`},
+}
+
+// Writer streams one worksheet into an xlsx archive. Rows are written in
+// order; Close must be called to finish the file.
+type Writer struct {
+	zw    *zip.Writer
+	sheet io.Writer
+	err   error
+}
+
+// NewWriter starts an xlsx file on out with a single sheet of the given name.
+func NewWriter(out io.Writer, sheetName string) (*Writer, error) {
+	zw := zip.NewWriter(out)
+
+	for _, part := range staticParts {
+		if err := writePart(zw, part.name, part.content); err != nil {
+			return nil, err
+		}
+	}
+
+	workbook := xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+		`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="` + escape(sheetName) + `" sheetId="1" r:id="rId1"/></sheets></workbook>`
+	if err := writePart(zw, "xl/workbook.xml", workbook); err != nil {
+		return nil, err
+	}
+
+	sheet, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+
+	w := &Writer{zw: zw, sheet: sheet}
+	w.write(xml.Header + `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+
+	return w, w.err
+}
+
+// WriteRow appends a row. Strings become inline strings; integers, floats
+// and booleans become numbers; times are written as RFC 3339 text.
+func (w *Writer) WriteRow(cells ...any) error {
+	w.write("<row>")
+	for _, cell := range cells {
+		w.write(formatCell(cell))
+	}
+	w.write("</row>")
+
+	return w.err
+}
+
+// Close finishes the sheet and the archive. It does not close the
+// underlying writer.
+func (w *Writer) Close() error {
+	w.write("</sheetData></worksheet>")
+	if w.err != nil {
+		return w.err
+	}
+
+	return w.zw.Close()
+}
+
+func (w *Writer) write(s string) {
+	if w.err != nil {
+		return
+	}
+	_, w.err = io.WriteString(w.sheet, s)
+}
+
+func formatCell(cell any) string {
+	switch v := cell.(type) {
+	case nil:
+		return "<c/>"
+	case string:
+		return inlineString(v)
+	case int:
+		return number(strconv.Itoa(v))
+	case int64:
+		return number(strconv.FormatInt(v, 10))
+	case float64:
+		return number(strconv.FormatFloat(v, 'f', -1, 64))
+	case bool:
+		if v {
+			return `<c t="b"><v>1</v></c>`
+		}
+		return `<c t="b"><v>0</v></c>`
+	case time.Time:
+		return inlineString(v.Format(time.RFC3339))
+	default:
+		return inlineString(fmt.Sprint(v))
+	}
+}
+
+func inlineString(s string) string {
+	if s == "" {
+		return "<c/>"
+	}
+	return `<c t="inlineStr"><is><t xml:space="preserve">` + escape(s) + `</t></is></c>`
+}
+
+func number(s string) string {
+	return "<c><v>" + s + "</v></c>"
+}
+
+func escape(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+func writePart(zw *zip.Writer, name string, content string) error {
+	part, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(part, content)
+	return err
+}
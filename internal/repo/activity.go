@@ -0,0 +1,86 @@
+package repo
+
+import (
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"pull-request-assigner/internal/apperrors"
+	"pull-request-assigner/internal/domain/models"
+)
+
+type ActivityRepo struct {
+	storage *sqlx.DB
+}
+
+func NewActivityRepo(storage *sqlx.DB) *ActivityRepo {
+	return &ActivityRepo{storage: storage}
+}
+
+// RecordPREvent stores a PR event that no other table keeps.
+func (r *ActivityRepo) RecordPREvent(prID string, kind string, actorID string, details string) error {
+	const op = "repo.activity.RecordPREvent"
+
+	query := `INSERT INTO pr_events (pull_request_id, kind, actor_id, details) VALUES ($1, $2, $3, $4)`
+
+	if _, err := r.storage.Exec(query, prID, kind, nullableUserID(actorID), details); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// GetPRActivity builds the PR's feed, oldest first, from the PR itself, its
+// assignment history, review progress and approvals, and the recorded PR
+// events. The creation sorts before anything recorded at the same instant.
+func (r *ActivityRepo) GetPRActivity(prID string) ([]models.PRActivity, error) {
+	const op = "repo.activity.GetPRActivity"
+
+	var exists bool
+	if err := r.storage.Get(&exists, `SELECT EXISTS (SELECT 1 FROM pull_requests WHERE pull_request_id = $1)`, prID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("%s: %w", op, apperrors.ErrPRNotFound)
+	}
+
+	query := `
+		SELECT kind, occurred_at, user_id, actor_id, details FROM (
+			SELECT 0 AS rank, 'PR_CREATED' AS kind, created_at AS occurred_at,
+				'' AS user_id, 'u' || author_id AS actor_id, pull_request_name AS details
+			FROM pull_requests WHERE pull_request_id = $1
+
+			UNION ALL
+			SELECT 1, 'MERGED', merged_at, '', COALESCE('u' || merged_by, ''), ''
+			FROM pull_requests WHERE pull_request_id = $1 AND merged_at IS NOT NULL
+
+			UNION ALL
+			SELECT 1,
+				CASE WHEN action = 'UNASSIGN' THEN 'REVIEWER_UNASSIGNED' ELSE 'REVIEWER_ASSIGNED' END,
+				created_at, 'u' || reviewer_id, COALESCE('u' || actor_id, ''),
+				action || COALESCE(' ' || reason, '') || COALESCE(': ' || note, '')
+			FROM assignment_history WHERE pull_request_id = $1
+
+			UNION ALL
+			SELECT 1, 'REVIEW_STARTED', review_started_at, 'u' || reviewer_id, 'u' || reviewer_id, ''
+			FROM pr_reviewers WHERE pull_request_id = $1 AND review_started_at IS NOT NULL
+
+			UNION ALL
+			SELECT 1, 'REVIEW_COMPLETED', review_completed_at, 'u' || reviewer_id, 'u' || reviewer_id, ''
+			FROM pr_reviewers WHERE pull_request_id = $1 AND review_completed_at IS NOT NULL
+
+			UNION ALL
+			SELECT 1, 'APPROVED', approved_at, 'u' || reviewer_id, 'u' || reviewer_id, ''
+			FROM pr_approvals WHERE pull_request_id = $1
+
+			UNION ALL
+			SELECT 1, kind, created_at, '', COALESCE('u' || actor_id, ''), details
+			FROM pr_events WHERE pull_request_id = $1
+		) feed
+		ORDER BY occurred_at, rank`
+
+	activity := make([]models.PRActivity, 0)
+	if err := r.storage.Select(&activity, query, prID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return activity, nil
+}
@@ -0,0 +1,118 @@
+package repo
+
+import (
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"pull-request-assigner/internal/apperrors"
+	"pull-request-assigner/internal/domain/models"
+	"strconv"
+)
+
+type ArchiveRepo struct {
+	storage *sqlx.DB
+}
+
+func NewArchiveRepo(storage *sqlx.DB) *ArchiveRepo {
+	return &ArchiveRepo{storage: storage}
+}
+
+func (r *ArchiveRepo) GetArchivedTeams() ([]models.ArchivedTeam, error) {
+	const op = "repo.archive.GetArchivedTeams"
+
+	query := `
+		SELECT
+			t.team_name,
+			t.archived_at,
+			(SELECT COUNT(*) FROM team_members tm WHERE tm.team_name = t.team_name) as member_count,
+			(SELECT COUNT(*) FROM pull_requests pr
+				JOIN team_members tm ON tm.user_id = pr.author_id
+				WHERE tm.team_name = t.team_name) as authored_prs,
+			(SELECT COUNT(*) FROM pr_reviewers prr
+				JOIN team_members tm ON tm.user_id = prr.reviewer_id
+				WHERE tm.team_name = t.team_name) as reviews_count
+		FROM teams t
+		WHERE t.archived_at IS NOT NULL
+		ORDER BY t.archived_at DESC
+	`
+
+	teams := make([]models.ArchivedTeam, 0)
+	err := r.storage.Select(&teams, query)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return teams, nil
+}
+
+func (r *ArchiveRepo) GetDeactivatedUsers() ([]models.ArchivedUser, error) {
+	const op = "repo.archive.GetDeactivatedUsers"
+
+	query := `
+		SELECT
+			u.user_id,
+			u.username,
+			u.team_name,
+			(SELECT COUNT(*) FROM pull_requests pr WHERE pr.author_id = u.user_id) as authored_prs,
+			(SELECT COUNT(*) FROM pr_reviewers prr WHERE prr.reviewer_id = u.user_id) as reviews_count
+		FROM users u
+		WHERE u.is_active = false
+		ORDER BY u.user_id
+	`
+
+	users := make([]models.ArchivedUser, 0)
+	err := r.storage.Select(&users, query)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	for i := range users {
+		id, _ := strconv.Atoi(users[i].UserID)
+		users[i].UserID = models.UserID(id).String()
+	}
+
+	return users, nil
+}
+
+func (r *ArchiveRepo) RestoreTeam(teamName string) error {
+	const op = "repo.archive.RestoreTeam"
+
+	query := `UPDATE teams SET archived_at = NULL WHERE team_name = $1 AND archived_at IS NOT NULL`
+
+	result, err := r.storage.Exec(query, teamName)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("%s: %w", op, apperrors.ErrTeamNotArchived)
+	}
+
+	return nil
+}
+
+func (r *ArchiveRepo) RestoreUser(userID int) error {
+	const op = "repo.archive.RestoreUser"
+
+	query := `UPDATE users SET is_active = true WHERE user_id = $1`
+
+	result, err := r.storage.Exec(query, userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("%s: %w", op, apperrors.ErrUserNotFound)
+	}
+
+	return nil
+}
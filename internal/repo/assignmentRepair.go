@@ -0,0 +1,176 @@
+package repo
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"pull-request-assigner/internal/apperrors"
+	"pull-request-assigner/internal/domain/models"
+	"time"
+)
+
+type AssignmentRepairRepo struct {
+	storage *sqlx.DB
+}
+
+func NewAssignmentRepairRepo(storage *sqlx.DB) *AssignmentRepairRepo {
+	return &AssignmentRepairRepo{storage: storage}
+}
+
+const assignmentRepairColumns = `ar.pull_request_id, COALESCE(pr.author_team, u.team_name, '') AS team_name,
+	ar.attempts, ar.last_error, ar.next_attempt_at, ar.alerted_at, ar.created_at`
+
+const assignmentRepairFrom = `assignment_repairs ar
+	JOIN pull_requests pr ON pr.pull_request_id = ar.pull_request_id
+	LEFT JOIN users u ON u.user_id = pr.author_id`
+
+// QueueRepair puts the PR in the repair queue, due at once. A PR already
+// queued keeps its attempts.
+func (r *AssignmentRepairRepo) QueueRepair(prID string, lastError string) error {
+	const op = "repo.assignmentRepair.QueueRepair"
+
+	query := `
+		INSERT INTO assignment_repairs (pull_request_id, last_error)
+		VALUES ($1, $2)
+		ON CONFLICT (pull_request_id) DO UPDATE SET
+			last_error = EXCLUDED.last_error,
+			next_attempt_at = NOW()
+	`
+
+	_, err := r.storage.Exec(query, prID, lastError)
+	if err != nil {
+		if isForeignKeyError(err) {
+			return fmt.Errorf("%s: %w", op, apperrors.ErrPRNotFound)
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// PurgeSettledRepairs drops the repairs no longer needed: the PR reached a
+// terminal status or has reviewers by now.
+func (r *AssignmentRepairRepo) PurgeSettledRepairs() (int, error) {
+	const op = "repo.assignmentRepair.PurgeSettledRepairs"
+
+	query := `
+		DELETE FROM assignment_repairs ar
+		USING pull_requests pr, pr_statuses ps
+		WHERE pr.pull_request_id = ar.pull_request_id AND ps.status = pr.status
+			AND (ps.is_terminal
+				OR EXISTS (SELECT 1 FROM pr_reviewers prr WHERE prr.pull_request_id = ar.pull_request_id))
+	`
+
+	result, err := r.storage.Exec(query)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	purged, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(purged), nil
+}
+
+// ClaimDueRepairs returns up to limit due repairs and makes them due again
+// only after lease. The rows are locked with SKIP LOCKED while claimed, so
+// concurrent runs never get the same repair; one whose runner died is due
+// again once the lease runs out.
+func (r *AssignmentRepairRepo) ClaimDueRepairs(limit int, lease time.Duration) ([]models.AssignmentRepair, error) {
+	const op = "repo.assignmentRepair.ClaimDueRepairs"
+
+	query := `
+		WITH ar AS (
+			UPDATE assignment_repairs
+			SET next_attempt_at = NOW() + make_interval(secs => $2)
+			WHERE pull_request_id IN (
+				SELECT pull_request_id FROM assignment_repairs
+				WHERE next_attempt_at <= NOW()
+				ORDER BY next_attempt_at, pull_request_id
+				LIMIT $1
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING *
+		)
+		SELECT ` + assignmentRepairColumns + ` FROM ar
+		JOIN pull_requests pr ON pr.pull_request_id = ar.pull_request_id
+		LEFT JOIN users u ON u.user_id = pr.author_id
+		ORDER BY ar.created_at, ar.pull_request_id
+	`
+
+	repairs := make([]models.AssignmentRepair, 0)
+	if err := r.storage.Select(&repairs, query, limit, lease.Seconds()); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return repairs, nil
+}
+
+func (r *AssignmentRepairRepo) GetRepairs() ([]models.AssignmentRepair, error) {
+	const op = "repo.assignmentRepair.GetRepairs"
+
+	query := `SELECT ` + assignmentRepairColumns + ` FROM ` + assignmentRepairFrom + `
+		ORDER BY ar.created_at, ar.pull_request_id`
+
+	repairs := make([]models.AssignmentRepair, 0)
+	if err := r.storage.Select(&repairs, query); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return repairs, nil
+}
+
+// RecordRepairFailure counts a failed attempt and makes the repair due
+// again after backoff.
+func (r *AssignmentRepairRepo) RecordRepairFailure(prID string, lastError string, backoff time.Duration) (models.AssignmentRepair, error) {
+	const op = "repo.assignmentRepair.RecordRepairFailure"
+
+	query := `
+		WITH ar AS (
+			UPDATE assignment_repairs
+			SET attempts = attempts + 1,
+				last_error = $2,
+				next_attempt_at = NOW() + make_interval(secs => $3)
+			WHERE pull_request_id = $1
+			RETURNING *
+		)
+		SELECT ` + assignmentRepairColumns + ` FROM ar
+		JOIN pull_requests pr ON pr.pull_request_id = ar.pull_request_id
+		LEFT JOIN users u ON u.user_id = pr.author_id
+	`
+
+	var repair models.AssignmentRepair
+	err := r.storage.Get(&repair, query, prID, lastError, backoff.Seconds())
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return models.AssignmentRepair{}, fmt.Errorf("%s: %w", op, apperrors.ErrPRNotFound)
+		}
+		return models.AssignmentRepair{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return repair, nil
+}
+
+func (r *AssignmentRepairRepo) MarkRepairAlerted(prID string) error {
+	const op = "repo.assignmentRepair.MarkRepairAlerted"
+
+	_, err := r.storage.Exec(`UPDATE assignment_repairs SET alerted_at = NOW() WHERE pull_request_id = $1`, prID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *AssignmentRepairRepo) DeleteRepair(prID string) error {
+	const op = "repo.assignmentRepair.DeleteRepair"
+
+	_, err := r.storage.Exec(`DELETE FROM assignment_repairs WHERE pull_request_id = $1`, prID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,87 @@
+package repo
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"pull-request-assigner/internal/domain/models"
+	"time"
+)
+
+type AuditRepo struct {
+	storage *sqlx.DB
+}
+
+func NewAuditRepo(storage *sqlx.DB) *AuditRepo {
+	return &AuditRepo{storage: storage}
+}
+
+func (r *AuditRepo) RecordEvent(event models.AuditEvent) error {
+	const op = "repo.audit.RecordEvent"
+
+	query := `
+		INSERT INTO audit_events (team_name, action, subject_id, actor_id, details)
+		VALUES (NULLIF($1, ''), $2, $3, $4, $5)
+	`
+
+	_, err := r.storage.Exec(query, event.TeamName, event.Action,
+		nullableUserID(event.SubjectID), nullableUserID(event.ActorID), event.Details)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *AuditRepo) GetTeamEvents(teamName string) ([]models.AuditEvent, error) {
+	const op = "repo.audit.GetTeamEvents"
+
+	query := `
+		SELECT id, team_name, action, subject_id, actor_id, details, created_at
+		FROM audit_events
+		WHERE team_name = $1
+		ORDER BY created_at, id
+	`
+
+	var rows []struct {
+		ID        int64         `db:"id"`
+		TeamName  string        `db:"team_name"`
+		Action    string        `db:"action"`
+		SubjectID sql.NullInt64 `db:"subject_id"`
+		ActorID   sql.NullInt64 `db:"actor_id"`
+		Details   string        `db:"details"`
+		CreatedAt time.Time     `db:"created_at"`
+	}
+
+	if err := r.storage.Select(&rows, query, teamName); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	events := make([]models.AuditEvent, 0, len(rows))
+	for _, row := range rows {
+		event := models.AuditEvent{
+			ID:        row.ID,
+			TeamName:  row.TeamName,
+			Action:    row.Action,
+			Details:   row.Details,
+			CreatedAt: row.CreatedAt,
+		}
+		if row.SubjectID.Valid {
+			event.SubjectID = models.UserID(row.SubjectID.Int64).String()
+		}
+		if row.ActorID.Valid {
+			event.ActorID = models.UserID(row.ActorID.Int64).String()
+		}
+		events = append(events, event)
+	}
+
+	return events, nil
+}
+
+func nullableUserID(userID string) sql.NullInt64 {
+	id, err := extractUserID(userID)
+	if err != nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: int64(id), Valid: true}
+}
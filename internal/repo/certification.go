@@ -0,0 +1,145 @@
+package repo
+
+import (
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"pull-request-assigner/internal/apperrors"
+	"pull-request-assigner/internal/domain/models"
+	"time"
+)
+
+type CertificationRepo struct {
+	storage *sqlx.DB
+}
+
+func NewCertificationRepo(storage *sqlx.DB) *CertificationRepo {
+	return &CertificationRepo{storage: storage}
+}
+
+func (r *CertificationRepo) GrantCertification(userID int, area string) (*models.Certification, error) {
+	const op = "repo.certification.GrantCertification"
+
+	query := `
+		INSERT INTO user_certifications (user_id, area)
+		SELECT user_id, $2 FROM users WHERE user_id = $1
+		ON CONFLICT (user_id, area) DO UPDATE SET certified_at = user_certifications.certified_at
+		RETURNING certified_at
+	`
+
+	var certifiedAt time.Time
+	err := r.storage.Get(&certifiedAt, query, userID, area)
+	if err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return nil, fmt.Errorf("%s: %w", op, apperrors.ErrUserNotFound)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &models.Certification{
+		UserID:      models.UserID(userID).String(),
+		Area:        area,
+		CertifiedAt: certifiedAt,
+	}, nil
+}
+
+func (r *CertificationRepo) RevokeCertification(userID int, area string) error {
+	const op = "repo.certification.RevokeCertification"
+
+	result, err := r.storage.Exec(`DELETE FROM user_certifications WHERE user_id = $1 AND area = $2`, userID, area)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("%s: %w", op, apperrors.ErrCertificationNotFound)
+	}
+
+	return nil
+}
+
+// ListCertifications returns certifications filtered by user and/or area; an
+// empty filter matches everything.
+func (r *CertificationRepo) ListCertifications(userID int, area string) ([]models.Certification, error) {
+	const op = "repo.certification.ListCertifications"
+
+	query := `
+		SELECT 'u' || user_id AS user_id, area, certified_at
+		FROM user_certifications
+		WHERE ($1 = 0 OR user_id = $1)
+		  AND ($2 = '' OR area = $2)
+		ORDER BY area, user_id
+	`
+
+	certifications := make([]models.Certification, 0)
+	if err := r.storage.Select(&certifications, query, userID, area); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return certifications, nil
+}
+
+// GetCertifiedAreas returns the certifications held by the given users.
+func (r *CertificationRepo) GetCertifiedAreas(userIDs []string) ([]models.Certification, error) {
+	const op = "repo.certification.GetCertifiedAreas"
+
+	ids := make([]int64, 0, len(userIDs))
+	for _, userID := range userIDs {
+		id, err := extractUserID(userID)
+		if err != nil {
+			continue
+		}
+		ids = append(ids, int64(id))
+	}
+
+	query := `
+		SELECT 'u' || user_id AS user_id, area, certified_at
+		FROM user_certifications
+		WHERE user_id = ANY($1)
+	`
+
+	certifications := make([]models.Certification, 0)
+	if err := r.storage.Select(&certifications, query, pq.Array(ids)); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return certifications, nil
+}
+
+// GetCertifiedReviewers returns active users certified for area, except the
+// excluded ones.
+func (r *CertificationRepo) GetCertifiedReviewers(area string, excludeUserIDs []string) ([]string, error) {
+	const op = "repo.certification.GetCertifiedReviewers"
+
+	exclude := make([]int64, 0, len(excludeUserIDs))
+	for _, userID := range excludeUserIDs {
+		id, err := extractUserID(userID)
+		if err != nil {
+			continue
+		}
+		exclude = append(exclude, int64(id))
+	}
+
+	query := `
+		SELECT 'u' || u.user_id
+		FROM user_certifications c
+		JOIN users u ON u.user_id = c.user_id
+		WHERE c.area = $1
+		  AND u.is_active
+		  AND ` + reviewerEligible + `
+		  AND NOT (u.user_id = ANY($2))
+		ORDER BY u.user_id
+	`
+
+	reviewers := make([]string, 0)
+	if err := r.storage.Select(&reviewers, query, area, pq.Array(exclude)); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return reviewers, nil
+}
@@ -0,0 +1,200 @@
+package repo
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"pull-request-assigner/internal/apperrors"
+	"pull-request-assigner/internal/domain/models"
+	"time"
+)
+
+// projectDashboardQuery rebuilds dashboard rows from the source tables; the
+// condition picks the PRs to project.
+const projectDashboardQuery = `
+	INSERT INTO dashboard_prs (
+		pull_request_id, pull_request_name, author_id, author_name, team_name,
+		status, is_terminal, ci_status, priority, under_reviewed,
+		reviewers, approvers, created_at, merged_at, projected_at
+	)
+	SELECT
+		pr.pull_request_id,
+		pr.pull_request_name,
+		pr.author_id,
+		u.username,
+		COALESCE(pr.author_team, u.team_name),
+		pr.status,
+		COALESCE(ps.is_terminal, FALSE),
+		pr.ci_status,
+		pr.priority,
+		pr.under_reviewed,
+		ARRAY(
+			SELECT 'u' || prr.reviewer_id
+			FROM pr_reviewers prr
+			WHERE prr.pull_request_id = pr.pull_request_id
+			ORDER BY prr.reviewer_id
+		),
+		ARRAY(
+			SELECT 'u' || a.reviewer_id
+			FROM pr_approvals a
+			JOIN pr_reviewers prr ON prr.pull_request_id = a.pull_request_id AND prr.reviewer_id = a.reviewer_id
+			WHERE a.pull_request_id = pr.pull_request_id
+			ORDER BY a.approved_at
+		),
+		COALESCE(pr.created_at, NOW()),
+		pr.merged_at,
+		NOW()
+	FROM pull_requests pr
+	JOIN users u ON u.user_id = pr.author_id
+	LEFT JOIN pr_statuses ps ON ps.status = pr.status
+	WHERE %s
+	ON CONFLICT (pull_request_id) DO UPDATE SET
+		pull_request_name = EXCLUDED.pull_request_name,
+		author_id = EXCLUDED.author_id,
+		author_name = EXCLUDED.author_name,
+		team_name = EXCLUDED.team_name,
+		status = EXCLUDED.status,
+		is_terminal = EXCLUDED.is_terminal,
+		ci_status = EXCLUDED.ci_status,
+		priority = EXCLUDED.priority,
+		under_reviewed = EXCLUDED.under_reviewed,
+		reviewers = EXCLUDED.reviewers,
+		approvers = EXCLUDED.approvers,
+		created_at = EXCLUDED.created_at,
+		merged_at = EXCLUDED.merged_at,
+		projected_at = EXCLUDED.projected_at
+`
+
+type DashboardRepo struct {
+	storage *sqlx.DB
+}
+
+func NewDashboardRepo(storage *sqlx.DB) *DashboardRepo {
+	return &DashboardRepo{storage: storage}
+}
+
+// ProjectPR refreshes the dashboard row of the PR. A PR that no longer
+// exists has already lost its row with it.
+func (r *DashboardRepo) ProjectPR(prID string) error {
+	const op = "repo.dashboard.ProjectPR"
+
+	if _, err := r.storage.Exec(fmt.Sprintf(projectDashboardQuery, "pr.pull_request_id = $1"), prID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// Rebuild refreshes every dashboard row and returns how many were written.
+func (r *DashboardRepo) Rebuild() (int, error) {
+	const op = "repo.dashboard.Rebuild"
+
+	result, err := r.storage.Exec(fmt.Sprintf(projectDashboardQuery, "TRUE"))
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(rows), nil
+}
+
+// ListDashboard returns up to limit rows ordered by (created_at,
+// pull_request_id) strictly after the cursor; a nil cursor starts from the
+// beginning. With overdueBefore set only open PRs created before it are
+// returned.
+func (r *DashboardRepo) ListDashboard(filter models.DashboardFilter, overdueBefore *time.Time, after *models.DashboardCursor, limit int) ([]models.DashboardPR, error) {
+	const op = "repo.dashboard.ListDashboard"
+
+	query := `
+		SELECT
+			pull_request_id,
+			pull_request_name,
+			'u' || author_id AS author_id,
+			author_name,
+			team_name,
+			status,
+			ci_status,
+			priority,
+			under_reviewed,
+			reviewers,
+			approvers,
+			created_at,
+			merged_at,
+			is_terminal,
+			projected_at
+		FROM dashboard_prs
+		WHERE TRUE
+	`
+	var args []interface{}
+
+	if after != nil {
+		args = append(args, after.CreatedAt.UTC(), after.PullRequestID)
+		query += fmt.Sprintf(" AND (created_at, pull_request_id) > ($%d, $%d)", len(args)-1, len(args))
+	}
+	if filter.TeamName != "" {
+		args = append(args, filter.TeamName)
+		query += fmt.Sprintf(" AND team_name = $%d", len(args))
+	}
+	if filter.ReviewerID != "" {
+		if _, err := extractUserID(filter.ReviewerID); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, apperrors.ErrInvalidUserID)
+		}
+		args = append(args, pq.StringArray{filter.ReviewerID})
+		query += fmt.Sprintf(" AND reviewers @> $%d", len(args))
+	}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		query += fmt.Sprintf(" AND status = $%d", len(args))
+	}
+	if filter.Open || overdueBefore != nil {
+		query += " AND NOT is_terminal"
+	}
+	if overdueBefore != nil {
+		args = append(args, overdueBefore.UTC())
+		query += fmt.Sprintf(" AND created_at < $%d", len(args))
+	}
+
+	args = append(args, limit)
+	query += fmt.Sprintf(" ORDER BY created_at, pull_request_id LIMIT $%d", len(args))
+
+	rows, err := r.storage.Queryx(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	page := make([]models.DashboardPR, 0, limit)
+	for rows.Next() {
+		var pr models.DashboardPR
+		var reviewers, approvers pq.StringArray
+		var mergedAt sql.NullTime
+		var terminal bool
+
+		err := rows.Scan(&pr.PullRequestId, &pr.PullRequestName, &pr.AuthorID, &pr.AuthorName, &pr.TeamName,
+			&pr.Status, &pr.CIStatus, &pr.Priority, &pr.UnderReviewed, &reviewers, &approvers,
+			&pr.CreatedAt, &mergedAt, &terminal, &pr.ProjectedAt)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		pr.Reviewers = reviewers
+		pr.Approvers = approvers
+		pr.Approvals = len(approvers)
+		pr.Open = !terminal
+		if mergedAt.Valid {
+			pr.MergedAt = &mergedAt.Time
+		}
+		page = append(page, pr)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return page, nil
+}
@@ -0,0 +1,111 @@
+package repo
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"pull-request-assigner/internal/domain/models"
+	"strings"
+)
+
+type DBCheckRepo struct {
+	storage *sqlx.DB
+}
+
+func NewDBCheckRepo(storage *sqlx.DB) *DBCheckRepo {
+	return &DBCheckRepo{storage: storage}
+}
+
+type hotQuery struct {
+	name  string
+	query string
+	args  []interface{}
+}
+
+// hotQueries are the queries executed on every PR create, reassign and
+// review lookup. They reference the constants the repo methods run, so the
+// plans checked here are the plans served in production.
+var hotQueries = []hotQuery{
+	{name: "active_team_members", query: activeTeamMembersQuery, args: []interface{}{""}},
+	{name: "reviews_by_reviewer", query: reviewsByReviewerQuery, args: []interface{}{0}},
+	{name: "reviewers_by_pr", query: prReviewersQuery, args: []interface{}{""}},
+	{name: "open_reviews_by_reviewer", query: openReviewsByReviewerQuery, args: []interface{}{0}},
+	{name: "team_members", query: teamMembersQuery, args: []interface{}{""}},
+}
+
+type planNode struct {
+	NodeType     string     `json:"Node Type"`
+	RelationName string     `json:"Relation Name"`
+	TotalCost    float64    `json:"Total Cost"`
+	Plans        []planNode `json:"Plans"`
+}
+
+func (r *DBCheckRepo) ExplainHotQueries() ([]models.QueryPlanCheck, error) {
+	const op = "repo.dbcheck.ExplainHotQueries"
+
+	checks := make([]models.QueryPlanCheck, 0, len(hotQueries))
+
+	for _, hq := range hotQueries {
+		var raw string
+		err := r.storage.Get(&raw, "EXPLAIN (FORMAT JSON) "+hq.query, hq.args...)
+		if err != nil {
+			return nil, fmt.Errorf("%s: failed to explain %s: %w", op, hq.name, err)
+		}
+
+		var plans []struct {
+			Plan planNode `json:"Plan"`
+		}
+		if err := json.Unmarshal([]byte(raw), &plans); err != nil {
+			return nil, fmt.Errorf("%s: failed to parse plan for %s: %w", op, hq.name, err)
+		}
+
+		check := models.QueryPlanCheck{
+			Name:          hq.name,
+			Query:         strings.Join(strings.Fields(hq.query), " "),
+			NodeTypes:     make([]string, 0),
+			SeqScanTables: make([]string, 0),
+		}
+
+		if len(plans) > 0 {
+			check.TotalCost = plans[0].Plan.TotalCost
+			collectPlanNodes(plans[0].Plan, &check)
+		}
+
+		check.Flagged = len(check.SeqScanTables) > 0
+		checks = append(checks, check)
+	}
+
+	return checks, nil
+}
+
+func collectPlanNodes(node planNode, check *models.QueryPlanCheck) {
+	check.NodeTypes = append(check.NodeTypes, node.NodeType)
+	if node.NodeType == "Seq Scan" {
+		check.SeqScanTables = append(check.SeqScanTables, node.RelationName)
+	}
+	for _, child := range node.Plans {
+		collectPlanNodes(child, check)
+	}
+}
+
+// GetSchemaVersion reads the version recorded by the migrator; an empty
+// schema is version 0.
+func (r *DBCheckRepo) GetSchemaVersion() (uint, bool, error) {
+	const op = "repo.dbcheck.GetSchemaVersion"
+
+	var row struct {
+		Version int64 `db:"version"`
+		Dirty   bool  `db:"dirty"`
+	}
+
+	err := r.storage.Get(&row, `SELECT version, dirty FROM schema_migrations LIMIT 1`)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, false, nil
+		}
+		return 0, false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return uint(row.Version), row.Dirty, nil
+}
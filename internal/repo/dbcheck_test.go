@@ -0,0 +1,98 @@
+package repo
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"strings"
+	"testing"
+)
+
+// hotQueryUses names the repo method that runs each hot query.
+var hotQueryUses = []struct {
+	name   string
+	query  string
+	ident  string
+	method string
+}{
+	{"active_team_members", activeTeamMembersQuery, "activeTeamMembersQuery", "PullRequestRepo.GetActiveTeamMembers"},
+	{"reviews_by_reviewer", reviewsByReviewerQuery, "reviewsByReviewerQuery", "UserRepo.GetReview"},
+	{"reviewers_by_pr", prReviewersQuery, "prReviewersQuery", "PullRequestRepo.GetPRWithReviewers"},
+	{"open_reviews_by_reviewer", openReviewsByReviewerQuery, "openReviewsByReviewerQuery", "UserRepo.GetOpenReviews"},
+	{"team_members", teamMembersQuery, "teamMembersQuery", "TeamRepo.GetTeamWithMembers"},
+}
+
+func TestHotQueriesMatchRepoQueries(t *testing.T) {
+	if len(hotQueries) != len(hotQueryUses) {
+		t.Fatalf("expected %d hot queries, got %d", len(hotQueryUses), len(hotQueries))
+	}
+
+	for i, use := range hotQueryUses {
+		hq := hotQueries[i]
+		if hq.name != use.name {
+			t.Errorf("hot query %d: expected %s, got %s", i, use.name, hq.name)
+			continue
+		}
+		if hq.query != use.query {
+			t.Errorf("%s: dbcheck explains a different query than %s runs", use.name, use.method)
+		}
+	}
+
+	methods := parseRepoMethods(t)
+	for _, use := range hotQueryUses {
+		body, ok := methods[use.method]
+		if !ok {
+			t.Errorf("%s: method %s not found", use.name, use.method)
+			continue
+		}
+		if !referencesIdent(body, use.ident) {
+			t.Errorf("%s: %s no longer runs %s", use.name, use.method, use.ident)
+		}
+	}
+}
+
+// parseRepoMethods returns the bodies of the package's methods keyed by
+// "Type.Method".
+func parseRepoMethods(t *testing.T) map[string]*ast.BlockStmt {
+	t.Helper()
+
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, ".", func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		t.Fatalf("failed to parse package: %v", err)
+	}
+
+	methods := make(map[string]*ast.BlockStmt)
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			for _, decl := range file.Decls {
+				fn, ok := decl.(*ast.FuncDecl)
+				if !ok || fn.Recv == nil || len(fn.Recv.List) == 0 {
+					continue
+				}
+				recv := fn.Recv.List[0].Type
+				if star, ok := recv.(*ast.StarExpr); ok {
+					recv = star.X
+				}
+				if ident, ok := recv.(*ast.Ident); ok {
+					methods[ident.Name+"."+fn.Name.Name] = fn.Body
+				}
+			}
+		}
+	}
+	return methods
+}
+
+func referencesIdent(body *ast.BlockStmt, name string) bool {
+	found := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		if ident, ok := n.(*ast.Ident); ok && ident.Name == name {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
@@ -0,0 +1,182 @@
+package repo
+
+import (
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"pull-request-assigner/internal/apperrors"
+	"pull-request-assigner/internal/domain/models"
+	"time"
+)
+
+type FreezeRepo struct {
+	storage *sqlx.DB
+}
+
+func NewFreezeRepo(storage *sqlx.DB) *FreezeRepo {
+	return &FreezeRepo{storage: storage}
+}
+
+const freezeColumns = `
+	freeze_id, COALESCE(team_name, '') AS team_name, reason,
+	starts_at, ends_at, created_at, lifted_at`
+
+const activeFreeze = `
+	f.lifted_at IS NULL AND f.starts_at <= NOW()
+	AND (f.ends_at IS NULL OR f.ends_at > NOW())`
+
+func (r *FreezeRepo) CreateFreeze(teamName string, reason string, startsAt time.Time, endsAt *time.Time) (*models.AssignmentFreeze, error) {
+	const op = "repo.freeze.CreateFreeze"
+
+	query := `
+		INSERT INTO assignment_freezes (team_name, reason, starts_at, ends_at)
+		VALUES (NULLIF($1, ''), $2, $3, $4)
+		RETURNING ` + freezeColumns
+
+	var freeze models.AssignmentFreeze
+	if err := r.storage.Get(&freeze, query, teamName, reason, startsAt, endsAt); err != nil {
+		if isForeignKeyError(err) {
+			return nil, fmt.Errorf("%s: %w", op, apperrors.ErrTeamNotFound)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &freeze, nil
+}
+
+// LiftFreezes lifts the team's current and scheduled freezes, or every
+// freeze when teamName is empty, and returns how many were lifted.
+func (r *FreezeRepo) LiftFreezes(teamName string) (int, error) {
+	const op = "repo.freeze.LiftFreezes"
+
+	query := `
+		UPDATE assignment_freezes
+		SET lifted_at = NOW()
+		WHERE lifted_at IS NULL
+			AND (ends_at IS NULL OR ends_at > NOW())
+			AND ($1 = '' OR team_name = $1)`
+
+	result, err := r.storage.Exec(query, teamName)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	lifted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(lifted), nil
+}
+
+// GetFreezes returns the freezes that are in effect or scheduled to start.
+func (r *FreezeRepo) GetFreezes() ([]models.AssignmentFreeze, error) {
+	const op = "repo.freeze.GetFreezes"
+
+	query := `
+		SELECT ` + freezeColumns + `
+		FROM assignment_freezes
+		WHERE lifted_at IS NULL AND (ends_at IS NULL OR ends_at > NOW())
+		ORDER BY starts_at, freeze_id`
+
+	freezes := make([]models.AssignmentFreeze, 0)
+	if err := r.storage.Select(&freezes, query); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return freezes, nil
+}
+
+// IsFrozen reports whether a team-wide or global freeze is in effect for
+// the team right now.
+func (r *FreezeRepo) IsFrozen(teamName string) (bool, error) {
+	const op = "repo.freeze.IsFrozen"
+
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM assignment_freezes f
+			WHERE (f.team_name IS NULL OR f.team_name = $1) AND ` + activeFreeze + `
+		)`
+
+	var frozen bool
+	if err := r.storage.Get(&frozen, query, teamName); err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return frozen, nil
+}
+
+// SetAssignmentQueued marks or unmarks the PR as waiting for a freeze to end.
+func (r *FreezeRepo) SetAssignmentQueued(prID string, queued bool) error {
+	const op = "repo.freeze.SetAssignmentQueued"
+
+	_, err := r.storage.Exec(`UPDATE pull_requests SET assignment_queued = $2 WHERE pull_request_id = $1`, prID, queued)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// GetReleasableQueued returns open queued PRs whose author team is no longer
+// frozen, oldest first.
+func (r *FreezeRepo) GetReleasableQueued() ([]string, error) {
+	const op = "repo.freeze.GetReleasableQueued"
+
+	query := `
+		SELECT pr.pull_request_id
+		FROM pull_requests pr
+		JOIN pr_statuses ps ON ps.status = pr.status
+		JOIN users u ON u.user_id = pr.author_id
+		WHERE pr.assignment_queued AND ps.is_terminal = false
+			AND NOT EXISTS (
+				SELECT 1 FROM assignment_freezes f
+				WHERE (f.team_name IS NULL OR f.team_name = u.team_name) AND ` + activeFreeze + `
+			)
+		ORDER BY pr.created_at, pr.pull_request_id`
+
+	prIDs := make([]string, 0)
+	if err := r.storage.Select(&prIDs, query); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return prIDs, nil
+}
+
+// GetPendingAssignments returns the open queued PRs of the team, or of every
+// team when teamName is empty, with their place in the team's queue and the
+// freezes holding it.
+func (r *FreezeRepo) GetPendingAssignments(teamName string) ([]models.PendingAssignment, error) {
+	const op = "repo.freeze.GetPendingAssignments"
+
+	query := `
+		SELECT
+			pr.pull_request_id,
+			pr.pull_request_name,
+			'u' || pr.author_id AS author_id,
+			u.team_name,
+			pr.created_at,
+			ROW_NUMBER() OVER (PARTITION BY u.team_name ORDER BY pr.created_at, pr.pull_request_id) AS position,
+			fz.frozen,
+			CASE WHEN fz.open_ended THEN NULL ELSE fz.frozen_until END AS frozen_until
+		FROM pull_requests pr
+		JOIN pr_statuses ps ON ps.status = pr.status
+		JOIN users u ON u.user_id = pr.author_id
+		CROSS JOIN LATERAL (
+			SELECT
+				COUNT(*) > 0 AS frozen,
+				COALESCE(BOOL_OR(f.ends_at IS NULL), FALSE) AS open_ended,
+				MAX(f.ends_at) AS frozen_until
+			FROM assignment_freezes f
+			WHERE (f.team_name IS NULL OR f.team_name = u.team_name) AND ` + activeFreeze + `
+		) fz
+		WHERE pr.assignment_queued AND ps.is_terminal = false
+			AND ($1 = '' OR u.team_name = $1)
+		ORDER BY u.team_name, position`
+
+	pending := make([]models.PendingAssignment, 0)
+	if err := r.storage.Select(&pending, query, teamName); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return pending, nil
+}
@@ -0,0 +1,81 @@
+package repo
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"pull-request-assigner/internal/apperrors"
+	"pull-request-assigner/internal/domain/models"
+	"time"
+)
+
+type ImpersonationRepo struct {
+	storage *sqlx.DB
+}
+
+func NewImpersonationRepo(storage *sqlx.DB) *ImpersonationRepo {
+	return &ImpersonationRepo{storage: storage}
+}
+
+const impersonationColumns = `
+	session_id, 'u' || admin_id AS admin_id, 'u' || user_id AS user_id,
+	reason, created_at, expires_at, ended_at`
+
+func (r *ImpersonationRepo) CreateSession(sessionHash string, adminID int, userID int, reason string, expiresAt time.Time) (*models.ImpersonationSession, error) {
+	const op = "repo.impersonation.CreateSession"
+
+	query := `
+		INSERT INTO impersonation_sessions (session_hash, admin_id, user_id, reason, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING ` + impersonationColumns
+
+	var session models.ImpersonationSession
+	if err := r.storage.Get(&session, query, sessionHash, adminID, userID, reason, expiresAt); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &session, nil
+}
+
+// EndSession closes the session. Ending an already ended session keeps the
+// original end time.
+func (r *ImpersonationRepo) EndSession(sessionID int64) (*models.ImpersonationSession, error) {
+	const op = "repo.impersonation.EndSession"
+
+	query := `
+		UPDATE impersonation_sessions
+		SET ended_at = COALESCE(ended_at, LEAST(NOW(), expires_at))
+		WHERE session_id = $1
+		RETURNING ` + impersonationColumns
+
+	var session models.ImpersonationSession
+	if err := r.storage.Get(&session, query, sessionID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%s: %w", op, apperrors.ErrImpersonationNotFound)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &session, nil
+}
+
+// GetActiveSession returns the session with the given hash if it has neither
+// ended nor expired.
+func (r *ImpersonationRepo) GetActiveSession(sessionHash string) (*models.ImpersonationSession, error) {
+	const op = "repo.impersonation.GetActiveSession"
+
+	query := `
+		SELECT ` + impersonationColumns + `
+		FROM impersonation_sessions
+		WHERE session_hash = $1 AND ended_at IS NULL AND expires_at > NOW()`
+
+	var session models.ImpersonationSession
+	if err := r.storage.Get(&session, query, sessionHash); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%s: %w", op, apperrors.ErrImpersonationNotFound)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &session, nil
+}
@@ -0,0 +1,110 @@
+package repo
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"pull-request-assigner/internal/domain/models"
+	"time"
+)
+
+type JobRepo struct {
+	storage *sqlx.DB
+}
+
+func NewJobRepo(storage *sqlx.DB) *JobRepo {
+	return &JobRepo{storage: storage}
+}
+
+// RegisterJob makes the job visible before its first run. A job already
+// registered by another instance keeps its next_run_at.
+func (r *JobRepo) RegisterJob(name string, interval time.Duration, nextRunAt time.Time) error {
+	const op = "repo.job.RegisterJob"
+
+	query := `
+		INSERT INTO jobs (name, interval_seconds, next_run_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (name) DO UPDATE
+		SET interval_seconds = EXCLUDED.interval_seconds,
+			next_run_at = COALESCE(jobs.next_run_at, EXCLUDED.next_run_at)
+	`
+
+	if _, err := r.storage.Exec(query, name, int(interval.Seconds()), nextRunAt); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// ClaimRun takes the job's next run for the caller: when the run is due,
+// or due within early, next_run_at moves an interval ahead and true is
+// returned. The conditional update lets one instance win each run.
+func (r *JobRepo) ClaimRun(name string, interval time.Duration, early time.Duration) (bool, error) {
+	const op = "repo.job.ClaimRun"
+
+	query := `
+		UPDATE jobs
+		SET next_run_at = NOW() + make_interval(secs => $2)
+		WHERE name = $1 AND (next_run_at IS NULL OR next_run_at <= NOW() + make_interval(secs => $3))
+		RETURNING name
+	`
+
+	var claimed string
+	if err := r.storage.Get(&claimed, query, name, interval.Seconds(), early.Seconds()); err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return true, nil
+}
+
+// RecordRun stores the outcome of a run. next_run_at is only set for a job
+// not registered yet; otherwise ClaimRun owns it.
+func (r *JobRepo) RecordRun(run models.JobRun) error {
+	const op = "repo.job.RecordRun"
+
+	query := `
+		INSERT INTO jobs (name, interval_seconds, last_started_at, last_duration_ms, last_outcome, last_error, next_run_at)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), $7)
+		ON CONFLICT (name) DO UPDATE
+		SET interval_seconds = EXCLUDED.interval_seconds,
+			last_started_at = EXCLUDED.last_started_at,
+			last_duration_ms = EXCLUDED.last_duration_ms,
+			last_outcome = EXCLUDED.last_outcome,
+			last_error = EXCLUDED.last_error
+	`
+
+	_, err := r.storage.Exec(query,
+		run.Name,
+		int(run.Interval.Seconds()),
+		run.StartedAt,
+		run.Duration.Milliseconds(),
+		run.Outcome,
+		run.Error,
+		run.NextRunAt,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *JobRepo) GetJobs() ([]models.Job, error) {
+	const op = "repo.job.GetJobs"
+
+	query := `
+		SELECT name, interval_seconds, last_started_at, last_duration_ms, last_outcome, last_error, next_run_at
+		FROM jobs
+		ORDER BY name
+	`
+
+	jobs := make([]models.Job, 0)
+	if err := r.storage.Select(&jobs, query); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return jobs, nil
+}
@@ -0,0 +1,86 @@
+package repo
+
+import (
+	"fmt"
+	"pull-request-assigner/internal/domain/models"
+	"strconv"
+)
+
+// users.team_name is the source of truth for the primary membership; the
+// primary team_members rows mirror it and are what GetTeam reads, so every
+// check and repair below converges the mirror onto users. Secondary rows are
+// memberships of their own and are left alone.
+
+const missingMembershipQuery = `
+	SELECT u.user_id::text AS user_id, u.team_name, 'MISSING_MEMBERSHIP' AS kind
+	FROM users u
+	LEFT JOIN team_members tm ON tm.user_id = u.user_id AND tm.team_name = u.team_name
+	WHERE tm.user_id IS NULL`
+
+const staleMembershipQuery = `
+	SELECT tm.user_id::text AS user_id, tm.team_name, 'STALE_MEMBERSHIP' AS kind
+	FROM team_members tm
+	JOIN users u ON u.user_id = tm.user_id
+	WHERE tm.is_primary AND u.team_name <> tm.team_name`
+
+func (r *DBCheckRepo) FindMembershipDrift() ([]models.MembershipDrift, error) {
+	const op = "repo.dbcheck.FindMembershipDrift"
+
+	var drift []models.MembershipDrift
+	err := r.storage.Select(&drift, missingMembershipQuery+` UNION ALL `+staleMembershipQuery+` ORDER BY user_id, team_name`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return formatDriftUserIDs(drift), nil
+}
+
+// RepairMembershipDrift removes stale team_members rows and adds the missing
+// ones in one transaction, returning what it changed.
+func (r *DBCheckRepo) RepairMembershipDrift() ([]models.MembershipDrift, error) {
+	const op = "repo.dbcheck.RepairMembershipDrift"
+
+	tx, err := r.storage.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	var removed []models.MembershipDrift
+	err = tx.Select(&removed, `
+		DELETE FROM team_members tm
+		USING users u
+		WHERE u.user_id = tm.user_id AND tm.is_primary AND u.team_name <> tm.team_name
+		RETURNING tm.user_id::text AS user_id, tm.team_name, 'STALE_MEMBERSHIP' AS kind`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to remove stale memberships: %w", op, err)
+	}
+
+	var added []models.MembershipDrift
+	err = tx.Select(&added, `
+		INSERT INTO team_members (team_name, user_id)
+		SELECT u.team_name, u.user_id
+		FROM users u
+		LEFT JOIN team_members tm ON tm.user_id = u.user_id AND tm.team_name = u.team_name
+		WHERE tm.user_id IS NULL
+		RETURNING user_id::text AS user_id, team_name, 'MISSING_MEMBERSHIP' AS kind`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to add missing memberships: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: failed to commit transaction: %w", op, err)
+	}
+
+	return formatDriftUserIDs(append(removed, added...)), nil
+}
+
+func formatDriftUserIDs(drift []models.MembershipDrift) []models.MembershipDrift {
+	result := make([]models.MembershipDrift, 0, len(drift))
+	for _, d := range drift {
+		id, _ := strconv.Atoi(d.UserID)
+		d.UserID = models.UserID(id).String()
+		result = append(result, d)
+	}
+	return result
+}
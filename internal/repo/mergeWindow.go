@@ -0,0 +1,139 @@
+package repo
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"pull-request-assigner/internal/apperrors"
+	"pull-request-assigner/internal/domain/models"
+	"time"
+)
+
+type MergeWindowRepo struct {
+	storage *sqlx.DB
+}
+
+func NewMergeWindowRepo(storage *sqlx.DB) *MergeWindowRepo {
+	return &MergeWindowRepo{storage: storage}
+}
+
+type mergeWindowRow struct {
+	TeamName    string    `db:"team_name"`
+	TimeZone    string    `db:"time_zone"`
+	WarnMinutes int       `db:"warn_minutes"`
+	Blocks      []byte    `db:"blocks"`
+	UpdatedAt   time.Time `db:"updated_at"`
+}
+
+func (row mergeWindowRow) window() (*models.MergeWindow, error) {
+	window := &models.MergeWindow{
+		TeamName:    row.TeamName,
+		TimeZone:    row.TimeZone,
+		WarnMinutes: row.WarnMinutes,
+		UpdatedAt:   row.UpdatedAt,
+	}
+	if err := json.Unmarshal(row.Blocks, &window.Blocks); err != nil {
+		return nil, err
+	}
+	return window, nil
+}
+
+const mergeWindowColumns = `team_name, time_zone, warn_minutes, blocks, updated_at`
+
+// SetMergeWindow stores the team's merge window, replacing the previous one.
+func (r *MergeWindowRepo) SetMergeWindow(window models.MergeWindow) (*models.MergeWindow, error) {
+	const op = "repo.mergeWindow.SetMergeWindow"
+
+	blocks, err := json.Marshal(window.Blocks)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	query := `
+		INSERT INTO team_merge_windows (team_name, time_zone, warn_minutes, blocks)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (team_name) DO UPDATE SET
+			time_zone = EXCLUDED.time_zone,
+			warn_minutes = EXCLUDED.warn_minutes,
+			blocks = EXCLUDED.blocks,
+			updated_at = NOW()
+		RETURNING ` + mergeWindowColumns
+
+	var row mergeWindowRow
+	err = r.storage.Get(&row, query, window.TeamName, window.TimeZone, window.WarnMinutes, blocks)
+	if err != nil {
+		if isForeignKeyError(err) {
+			return nil, fmt.Errorf("%s: %w", op, apperrors.ErrTeamNotFound)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	stored, err := row.window()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return stored, nil
+}
+
+// DeleteMergeWindow removes the team's merge window and reports whether it
+// had one.
+func (r *MergeWindowRepo) DeleteMergeWindow(teamName string) (bool, error) {
+	const op = "repo.mergeWindow.DeleteMergeWindow"
+
+	result, err := r.storage.Exec(`DELETE FROM team_merge_windows WHERE team_name = $1`, teamName)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// GetMergeWindow returns the team's merge window, or nil when merging is
+// never blocked for it.
+func (r *MergeWindowRepo) GetMergeWindow(teamName string) (*models.MergeWindow, error) {
+	const op = "repo.mergeWindow.GetMergeWindow"
+
+	var row mergeWindowRow
+	err := r.storage.Get(&row, `SELECT `+mergeWindowColumns+` FROM team_merge_windows WHERE team_name = $1`, teamName)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	window, err := row.window()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return window, nil
+}
+
+func (r *MergeWindowRepo) GetMergeWindows() ([]models.MergeWindow, error) {
+	const op = "repo.mergeWindow.GetMergeWindows"
+
+	var rows []mergeWindowRow
+	err := r.storage.Select(&rows, `SELECT `+mergeWindowColumns+` FROM team_merge_windows ORDER BY team_name`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	windows := make([]models.MergeWindow, 0, len(rows))
+	for _, row := range rows {
+		window, err := row.window()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		windows = append(windows, *window)
+	}
+
+	return windows, nil
+}
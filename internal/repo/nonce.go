@@ -0,0 +1,50 @@
+package repo
+
+import (
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"pull-request-assigner/internal/apperrors"
+	"time"
+)
+
+type NonceRepo struct {
+	storage *sqlx.DB
+}
+
+func NewNonceRepo(storage *sqlx.DB) *NonceRepo {
+	return &NonceRepo{storage: storage}
+}
+
+// ClaimNonce stores the nonce of a signed admin request. A nonce that was
+// already stored means the request is a replay.
+func (r *NonceRepo) ClaimNonce(nonce string) error {
+	const op = "repo.nonce.ClaimNonce"
+
+	_, err := r.storage.Exec(`INSERT INTO admin_request_nonces (nonce) VALUES ($1)`, nonce)
+	if err != nil {
+		if isDuplicateKeyError(err) {
+			return fmt.Errorf("%s: %w", op, apperrors.ErrReplayedRequest)
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *NonceRepo) DeleteNoncesOlderThan(age time.Duration) (int64, error) {
+	const op = "repo.nonce.DeleteNoncesOlderThan"
+
+	query := `DELETE FROM admin_request_nonces WHERE created_at < NOW() - $1 * INTERVAL '1 second'`
+
+	result, err := r.storage.Exec(query, age.Seconds())
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return deleted, nil
+}
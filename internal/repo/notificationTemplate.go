@@ -0,0 +1,94 @@
+package repo
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"pull-request-assigner/internal/apperrors"
+	"pull-request-assigner/internal/domain/models"
+)
+
+type NotificationTemplateRepo struct {
+	storage *sqlx.DB
+}
+
+func NewNotificationTemplateRepo(storage *sqlx.DB) *NotificationTemplateRepo {
+	return &NotificationTemplateRepo{storage: storage}
+}
+
+type templateRow struct {
+	models.NotificationTemplate
+	UpdatedBy sql.NullInt64 `db:"updated_by"`
+}
+
+func (row templateRow) toModel() models.NotificationTemplate {
+	tpl := row.NotificationTemplate
+	if row.UpdatedBy.Valid {
+		tpl.UpdatedBy = models.UserID(row.UpdatedBy.Int64).String()
+	}
+	return tpl
+}
+
+func (r *NotificationTemplateRepo) ListTemplates() ([]models.NotificationTemplate, error) {
+	const op = "repo.notificationTemplate.ListTemplates"
+
+	query := `
+		SELECT event, channel, body, updated_at, updated_by
+		FROM notification_templates
+		ORDER BY event, channel
+	`
+
+	var rows []templateRow
+	if err := r.storage.Select(&rows, query); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	templates := make([]models.NotificationTemplate, 0, len(rows))
+	for _, row := range rows {
+		templates = append(templates, row.toModel())
+	}
+
+	return templates, nil
+}
+
+// SaveTemplate creates the template of the event and channel or replaces its
+// body. An updatedBy that is not a known user is stored as NULL.
+func (r *NotificationTemplateRepo) SaveTemplate(tpl models.NotificationTemplate, updatedBy string) (*models.NotificationTemplate, error) {
+	const op = "repo.notificationTemplate.SaveTemplate"
+
+	query := `
+		INSERT INTO notification_templates (event, channel, body, updated_at, updated_by)
+		VALUES ($1, $2, $3, NOW(), (SELECT user_id FROM users WHERE user_id = $4))
+		ON CONFLICT (event, channel) DO UPDATE
+		SET body = EXCLUDED.body, updated_at = EXCLUDED.updated_at, updated_by = EXCLUDED.updated_by
+		RETURNING event, channel, body, updated_at, updated_by
+	`
+
+	var row templateRow
+	if err := r.storage.Get(&row, query, tpl.Event, tpl.Channel, tpl.Body, nullableUserID(updatedBy)); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	saved := row.toModel()
+	return &saved, nil
+}
+
+func (r *NotificationTemplateRepo) DeleteTemplate(event string, channel string) error {
+	const op = "repo.notificationTemplate.DeleteTemplate"
+
+	result, err := r.storage.Exec(`DELETE FROM notification_templates WHERE event = $1 AND channel = $2`, event, channel)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("%s: %w", op, apperrors.ErrTemplateNotFound)
+	}
+
+	return nil
+}
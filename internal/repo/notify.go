@@ -0,0 +1,26 @@
+package repo
+
+import (
+	"fmt"
+	"github.com/jmoiron/sqlx"
+)
+
+type NotifyRepo struct {
+	storage *sqlx.DB
+}
+
+func NewNotifyRepo(storage *sqlx.DB) *NotifyRepo {
+	return &NotifyRepo{storage: storage}
+}
+
+// Notify sends a Postgres notification to every instance listening on the
+// channel, this one included.
+func (r *NotifyRepo) Notify(channel, payload string) error {
+	const op = "repo.notify.Notify"
+
+	if _, err := r.storage.Exec(`SELECT pg_notify($1, $2)`, channel, payload); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,249 @@
+package repo
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"pull-request-assigner/internal/apperrors"
+	"pull-request-assigner/internal/domain/models"
+	"time"
+)
+
+type PolicyRepo struct {
+	storage *sqlx.DB
+}
+
+func NewPolicyRepo(storage *sqlx.DB) *PolicyRepo {
+	return &PolicyRepo{storage: storage}
+}
+
+func (r *PolicyRepo) GetOrgPolicy() (*models.OrgPolicy, error) {
+	const op = "repo.policy.GetOrgPolicy"
+
+	query := `
+		SELECT
+			hold_until_ci_green,
+			min_reviewers,
+			reviewers_per_pr,
+			default_priority,
+			default_labels,
+			default_required_skills,
+			exclude_co_authors,
+			exclude_pairing_session,
+			diversify_reviewer_pairs,
+			updated_at
+		FROM org_policy
+	`
+
+	var row struct {
+		HoldUntilCIGreen      bool           `db:"hold_until_ci_green"`
+		MinReviewers          int            `db:"min_reviewers"`
+		ReviewersPerPR        int            `db:"reviewers_per_pr"`
+		DefaultPriority       sql.NullString `db:"default_priority"`
+		DefaultLabels         pq.StringArray `db:"default_labels"`
+		DefaultRequiredSkills pq.StringArray `db:"default_required_skills"`
+		ExcludeCoAuthors      bool           `db:"exclude_co_authors"`
+		ExcludePairingSession bool           `db:"exclude_pairing_session"`
+		DiversifyPairs        bool           `db:"diversify_reviewer_pairs"`
+		UpdatedAt             time.Time      `db:"updated_at"`
+	}
+
+	if err := r.storage.Get(&row, query); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &models.OrgPolicy{
+		HoldUntilCIGreen:      row.HoldUntilCIGreen,
+		MinReviewers:          row.MinReviewers,
+		ReviewersPerPR:        row.ReviewersPerPR,
+		DefaultPriority:       row.DefaultPriority.String,
+		DefaultLabels:         []string(row.DefaultLabels),
+		DefaultRequiredSkills: []string(row.DefaultRequiredSkills),
+		ExcludeCoAuthors:      row.ExcludeCoAuthors,
+		ExcludePairingSession: row.ExcludePairingSession,
+		DiversifyPairs:        row.DiversifyPairs,
+		UpdatedAt:             row.UpdatedAt,
+	}, nil
+}
+
+func (r *PolicyRepo) UpdateOrgPolicy(policy models.OrgPolicy) error {
+	const op = "repo.policy.UpdateOrgPolicy"
+
+	query := `
+		UPDATE org_policy
+		SET hold_until_ci_green = $1,
+			min_reviewers = $2,
+			default_priority = NULLIF($3, ''),
+			default_labels = $4,
+			default_required_skills = $5,
+			exclude_co_authors = $6,
+			exclude_pairing_session = $7,
+			diversify_reviewer_pairs = $8,
+			reviewers_per_pr = $9,
+			updated_at = NOW()
+	`
+
+	_, err := r.storage.Exec(query,
+		policy.HoldUntilCIGreen,
+		policy.MinReviewers,
+		policy.DefaultPriority,
+		pq.Array(nonNilTags(policy.DefaultLabels)),
+		pq.Array(nonNilTags(policy.DefaultRequiredSkills)),
+		policy.ExcludeCoAuthors,
+		policy.ExcludePairingSession,
+		policy.DiversifyPairs,
+		policy.ReviewersPerPR,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *PolicyRepo) GetPolicyLayers(teamName string) (*models.OrgPolicy, *models.TeamPolicyOverrides, error) {
+	const op = "repo.policy.GetPolicyLayers"
+
+	org, team, err := getPolicyLayers(r.storage, teamName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return org, team, nil
+}
+
+// getPolicyLayers loads the org defaults and the team's own policy columns,
+// where NULL means the field is inherited.
+func getPolicyLayers(q sqlx.Queryer, teamName string) (*models.OrgPolicy, *models.TeamPolicyOverrides, error) {
+	query := `
+		SELECT
+			t.team_name,
+			t.hold_until_ci_green,
+			t.min_reviewers,
+			t.reviewers_per_pr,
+			t.default_priority,
+			t.default_labels,
+			t.default_required_skills,
+			t.review_labels,
+			t.exclude_co_authors,
+			t.exclude_pairing_session,
+			t.diversify_reviewer_pairs,
+			o.hold_until_ci_green AS org_hold_until_ci_green,
+			o.min_reviewers AS org_min_reviewers,
+			o.reviewers_per_pr AS org_reviewers_per_pr,
+			o.default_priority AS org_default_priority,
+			o.default_labels AS org_default_labels,
+			o.default_required_skills AS org_default_required_skills,
+			o.exclude_co_authors AS org_exclude_co_authors,
+			o.exclude_pairing_session AS org_exclude_pairing_session,
+			o.diversify_reviewer_pairs AS org_diversify_reviewer_pairs,
+			o.updated_at AS org_updated_at
+		FROM teams t
+		CROSS JOIN org_policy o
+		WHERE t.team_name = $1
+	`
+
+	var row struct {
+		TeamName              string         `db:"team_name"`
+		HoldUntilCIGreen      sql.NullBool   `db:"hold_until_ci_green"`
+		MinReviewers          sql.NullInt64  `db:"min_reviewers"`
+		ReviewersPerPR        sql.NullInt64  `db:"reviewers_per_pr"`
+		DefaultPriority       sql.NullString `db:"default_priority"`
+		DefaultLabels         pq.StringArray `db:"default_labels"`
+		DefaultRequiredSkills pq.StringArray `db:"default_required_skills"`
+		ReviewLabels          pq.StringArray `db:"review_labels"`
+		ExcludeCoAuthors      sql.NullBool   `db:"exclude_co_authors"`
+		ExcludePairingSession sql.NullBool   `db:"exclude_pairing_session"`
+		DiversifyPairs        sql.NullBool   `db:"diversify_reviewer_pairs"`
+
+		OrgHoldUntilCIGreen      bool           `db:"org_hold_until_ci_green"`
+		OrgMinReviewers          int            `db:"org_min_reviewers"`
+		OrgReviewersPerPR        int            `db:"org_reviewers_per_pr"`
+		OrgDefaultPriority       sql.NullString `db:"org_default_priority"`
+		OrgDefaultLabels         pq.StringArray `db:"org_default_labels"`
+		OrgDefaultRequiredSkills pq.StringArray `db:"org_default_required_skills"`
+		OrgExcludeCoAuthors      bool           `db:"org_exclude_co_authors"`
+		OrgExcludePairingSession bool           `db:"org_exclude_pairing_session"`
+		OrgDiversifyPairs        bool           `db:"org_diversify_reviewer_pairs"`
+		OrgUpdatedAt             time.Time      `db:"org_updated_at"`
+	}
+
+	if err := sqlx.Get(q, &row, query, teamName); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil, apperrors.ErrTeamNotFound
+		}
+		return nil, nil, err
+	}
+
+	org := &models.OrgPolicy{
+		HoldUntilCIGreen:      row.OrgHoldUntilCIGreen,
+		MinReviewers:          row.OrgMinReviewers,
+		ReviewersPerPR:        row.OrgReviewersPerPR,
+		DefaultPriority:       row.OrgDefaultPriority.String,
+		DefaultLabels:         []string(row.OrgDefaultLabels),
+		DefaultRequiredSkills: []string(row.OrgDefaultRequiredSkills),
+		ExcludeCoAuthors:      row.OrgExcludeCoAuthors,
+		ExcludePairingSession: row.OrgExcludePairingSession,
+		DiversifyPairs:        row.OrgDiversifyPairs,
+		UpdatedAt:             row.OrgUpdatedAt,
+	}
+
+	team := &models.TeamPolicyOverrides{
+		TeamName:              row.TeamName,
+		DefaultLabels:         nullableTags(row.DefaultLabels),
+		DefaultRequiredSkills: nullableTags(row.DefaultRequiredSkills),
+		ReviewLabels:          []string(row.ReviewLabels),
+	}
+
+	if row.HoldUntilCIGreen.Valid {
+		team.HoldUntilCIGreen = &row.HoldUntilCIGreen.Bool
+	}
+
+	if row.MinReviewers.Valid {
+		minReviewers := int(row.MinReviewers.Int64)
+		team.MinReviewers = &minReviewers
+	}
+
+	if row.ReviewersPerPR.Valid {
+		reviewersPerPR := int(row.ReviewersPerPR.Int64)
+		team.ReviewersPerPR = &reviewersPerPR
+	}
+
+	if row.DefaultPriority.Valid {
+		team.DefaultPriority = &row.DefaultPriority.String
+	}
+
+	if row.ExcludeCoAuthors.Valid {
+		team.ExcludeCoAuthors = &row.ExcludeCoAuthors.Bool
+	}
+
+	if row.ExcludePairingSession.Valid {
+		team.ExcludePairingSession = &row.ExcludePairingSession.Bool
+	}
+
+	if row.DiversifyPairs.Valid {
+		team.DiversifyPairs = &row.DiversifyPairs.Bool
+	}
+
+	return org, team, nil
+}
+
+// nullableTags maps a NULL array to nil and keeps an empty one as an
+// explicit empty override.
+func nullableTags(tags pq.StringArray) *[]string {
+	if tags == nil {
+		return nil
+	}
+	list := []string(tags)
+	return &list
+}
+
+// tagsOverride is the column value for an optional tag list: NULL when
+// the list is inherited.
+func tagsOverride(tags *[]string) interface{} {
+	if tags == nil {
+		return nil
+	}
+	return pq.Array(nonNilTags(*tags))
+}
@@ -0,0 +1,311 @@
+package repo
+
+import (
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"pull-request-assigner/internal/apperrors"
+	"pull-request-assigner/internal/domain/models"
+)
+
+type PoolRepo struct {
+	storage *sqlx.DB
+}
+
+func NewPoolRepo(storage *sqlx.DB) *PoolRepo {
+	return &PoolRepo{storage: storage}
+}
+
+func (r *PoolRepo) CreatePool(poolName string, reviewersPerPR int, userIDs []int) (*models.ReviewerPool, error) {
+	const op = "repo.pool.CreatePool"
+
+	tx, err := r.storage.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`INSERT INTO reviewer_pools (pool_name, reviewers_per_pr) VALUES ($1, $2)`, poolName, reviewersPerPR)
+	if err != nil {
+		if isDuplicateKeyError(err) {
+			return nil, fmt.Errorf("%s: %w", op, apperrors.ErrPoolExists)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := addPoolMembers(tx, poolName, userIDs); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: failed to commit transaction: %w", op, err)
+	}
+
+	return r.GetPool(poolName)
+}
+
+func (r *PoolRepo) GetPool(poolName string) (*models.ReviewerPool, error) {
+	const op = "repo.pool.GetPool"
+
+	var pool models.ReviewerPool
+	err := r.storage.Get(&pool, `SELECT pool_name, reviewers_per_pr, created_at FROM reviewer_pools WHERE pool_name = $1`, poolName)
+	if err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return nil, fmt.Errorf("%s: %w", op, apperrors.ErrPoolNotFound)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	query := `
+		SELECT 'u' || user_id
+		FROM reviewer_pool_members
+		WHERE pool_name = $1
+		ORDER BY user_id
+	`
+
+	pool.Members = make([]string, 0)
+	if err := r.storage.Select(&pool.Members, query, poolName); err != nil {
+		return nil, fmt.Errorf("%s: failed to get members: %w", op, err)
+	}
+
+	return &pool, nil
+}
+
+// ListPools returns every pool without its members.
+func (r *PoolRepo) ListPools() ([]models.ReviewerPool, error) {
+	const op = "repo.pool.ListPools"
+
+	pools := make([]models.ReviewerPool, 0)
+	err := r.storage.Select(&pools, `SELECT pool_name, reviewers_per_pr, created_at FROM reviewer_pools ORDER BY pool_name`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return pools, nil
+}
+
+func (r *PoolRepo) UpdatePool(poolName string, reviewersPerPR int) error {
+	const op = "repo.pool.UpdatePool"
+
+	result, err := r.storage.Exec(`UPDATE reviewer_pools SET reviewers_per_pr = $2 WHERE pool_name = $1`, poolName, reviewersPerPR)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("%s: %w", op, apperrors.ErrPoolNotFound)
+	}
+
+	return nil
+}
+
+// DeletePool removes the pool and its memberships. PRs that targeted it keep
+// their reviewers and fall back to the author's team on reassignment.
+func (r *PoolRepo) DeletePool(poolName string) error {
+	const op = "repo.pool.DeletePool"
+
+	result, err := r.storage.Exec(`DELETE FROM reviewer_pools WHERE pool_name = $1`, poolName)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("%s: %w", op, apperrors.ErrPoolNotFound)
+	}
+
+	return nil
+}
+
+// AddPoolMembers adds the users to the pool; users already in it are kept.
+func (r *PoolRepo) AddPoolMembers(poolName string, userIDs []int) error {
+	const op = "repo.pool.AddPoolMembers"
+
+	tx, err := r.storage.Beginx()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	// The share lock keeps the pool from being deleted while members are
+	// added, so a foreign key error can only mean an unknown user.
+	var locked string
+	if err := tx.Get(&locked, `SELECT pool_name FROM reviewer_pools WHERE pool_name = $1 FOR SHARE`, poolName); err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return fmt.Errorf("%s: %w", op, apperrors.ErrPoolNotFound)
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := addPoolMembers(tx, poolName, userIDs); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: failed to commit transaction: %w", op, err)
+	}
+
+	return nil
+}
+
+func addPoolMembers(tx *sqlx.Tx, poolName string, userIDs []int) error {
+	query := `
+		INSERT INTO reviewer_pool_members (pool_name, user_id)
+		VALUES ($1, $2)
+		ON CONFLICT (pool_name, user_id) DO NOTHING
+	`
+
+	for _, userID := range userIDs {
+		if _, err := tx.Exec(query, poolName, userID); err != nil {
+			if isForeignKeyError(err) {
+				return fmt.Errorf("%w: %s", apperrors.ErrUserNotFound, models.UserID(userID))
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RemovePoolMembers removes the users from the pool and returns how many of
+// them were members.
+func (r *PoolRepo) RemovePoolMembers(poolName string, userIDs []int) (int, error) {
+	const op = "repo.pool.RemovePoolMembers"
+
+	ids := make([]int64, 0, len(userIDs))
+	for _, userID := range userIDs {
+		ids = append(ids, int64(userID))
+	}
+
+	result, err := r.storage.Exec(`DELETE FROM reviewer_pool_members WHERE pool_name = $1 AND user_id = ANY($2)`, poolName, pq.Array(ids))
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	removed, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(removed), nil
+}
+
+// RemoveUserFromPools removes the user from every pool and returns the names
+// of the pools they left.
+func (r *PoolRepo) RemoveUserFromPools(userID int) ([]string, error) {
+	const op = "repo.pool.RemoveUserFromPools"
+
+	query := `
+		WITH removed AS (
+			DELETE FROM reviewer_pool_members WHERE user_id = $1 RETURNING pool_name
+		)
+		SELECT pool_name FROM removed ORDER BY pool_name`
+
+	pools := make([]string, 0)
+	if err := r.storage.Select(&pools, query, userID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return pools, nil
+}
+
+// GetActivePoolMembers returns active members of the pool, except the
+// excluded ones.
+func (r *PoolRepo) GetActivePoolMembers(poolName string, excludeUserIDs []string) ([]string, error) {
+	const op = "repo.pool.GetActivePoolMembers"
+
+	exclude := make([]int64, 0, len(excludeUserIDs))
+	for _, userID := range excludeUserIDs {
+		id, err := extractUserID(userID)
+		if err != nil {
+			continue
+		}
+		exclude = append(exclude, int64(id))
+	}
+
+	query := `
+		SELECT 'u' || u.user_id
+		FROM reviewer_pool_members m
+		JOIN users u ON u.user_id = m.user_id
+		WHERE m.pool_name = $1
+		  AND u.is_active
+		  AND ` + reviewerEligible + `
+		  AND NOT (u.user_id = ANY($2))
+		ORDER BY u.user_id
+	`
+
+	members := make([]string, 0)
+	if err := r.storage.Select(&members, query, poolName, pq.Array(exclude)); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return members, nil
+}
+
+func (r *PoolRepo) IsPoolMember(poolName string, userID string) (bool, error) {
+	const op = "repo.pool.IsPoolMember"
+
+	id, err := extractUserID(userID)
+	if err != nil {
+		return false, nil
+	}
+
+	var member bool
+	query := `SELECT EXISTS(SELECT 1 FROM reviewer_pool_members WHERE pool_name = $1 AND user_id = $2)`
+	if err := r.storage.Get(&member, query, poolName, id); err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return member, nil
+}
+
+// GetPoolStats counts the PRs that targeted the pool and the reviews its
+// members did on them.
+func (r *PoolRepo) GetPoolStats(poolName string) (*models.PoolStats, error) {
+	const op = "repo.pool.GetPoolStats"
+
+	stats := models.PoolStats{PoolName: poolName}
+
+	prQuery := `
+		SELECT
+			COUNT(*) FILTER (WHERE status = 'OPEN') AS open_prs,
+			COUNT(*) FILTER (WHERE status = 'MERGED') AS merged_prs
+		FROM pull_requests
+		WHERE reviewer_pool = $1
+	`
+
+	if err := r.storage.Get(&stats, prQuery, poolName); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	membersQuery := `
+		SELECT
+			'u' || u.user_id AS user_id,
+			u.is_active,
+			COUNT(pr.pull_request_id) FILTER (WHERE pr.status = 'OPEN') AS open_reviews,
+			COUNT(pr.pull_request_id) AS total_reviews
+		FROM reviewer_pool_members m
+		JOIN users u ON u.user_id = m.user_id
+		LEFT JOIN pr_reviewers r ON r.reviewer_id = m.user_id
+		LEFT JOIN pull_requests pr ON pr.pull_request_id = r.pull_request_id AND pr.reviewer_pool = m.pool_name
+		WHERE m.pool_name = $1
+		GROUP BY u.user_id, u.is_active
+		ORDER BY u.user_id
+	`
+
+	stats.Members = make([]models.PoolMemberStats, 0)
+	if err := r.storage.Select(&stats.Members, membersQuery, poolName); err != nil {
+		return nil, fmt.Errorf("%s: failed to get member stats: %w", op, err)
+	}
+
+	return &stats, nil
+}
@@ -0,0 +1,226 @@
+package repo
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"pull-request-assigner/internal/apperrors"
+	"pull-request-assigner/internal/domain/models"
+)
+
+type PRStatusRepo struct {
+	storage *sqlx.DB
+}
+
+func NewPRStatusRepo(storage *sqlx.DB) *PRStatusRepo {
+	return &PRStatusRepo{storage: storage}
+}
+
+func (r *PRStatusRepo) GetStatuses() ([]models.PRStatus, error) {
+	const op = "repo.prStatus.GetStatuses"
+
+	query := `SELECT status, is_terminal FROM pr_statuses ORDER BY status`
+
+	var statuses []models.PRStatus
+	err := r.storage.Select(&statuses, query)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return statuses, nil
+}
+
+// GetTransitions returns the transitions the team's PRs follow: its own
+// when it set any, the org's otherwise. own reports which. An empty team
+// name asks for the org's.
+func (r *PRStatusRepo) GetTransitions(teamName string) ([]models.PRStatusTransition, bool, error) {
+	const op = "repo.prStatus.GetTransitions"
+
+	query := `
+		SELECT from_status, to_status FROM team_pr_status_transitions
+		WHERE team_name = $1
+		ORDER BY from_status, to_status`
+
+	transitions := make([]models.PRStatusTransition, 0)
+	if teamName != "" {
+		if err := r.storage.Select(&transitions, query, teamName); err != nil {
+			return nil, false, fmt.Errorf("%s: %w", op, err)
+		}
+		if len(transitions) > 0 {
+			return transitions, true, nil
+		}
+	}
+
+	query = `SELECT from_status, to_status FROM pr_status_transitions ORDER BY from_status, to_status`
+
+	if err := r.storage.Select(&transitions, query); err != nil {
+		return nil, false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return transitions, false, nil
+}
+
+func (r *PRStatusRepo) StatusExists(status string) (bool, error) {
+	const op = "repo.prStatus.StatusExists"
+
+	query := `SELECT EXISTS(SELECT 1 FROM pr_statuses WHERE status = $1)`
+
+	var exists bool
+	err := r.storage.Get(&exists, query, status)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return exists, nil
+}
+
+// TransitionAllowed checks the transition against the team's own transitions
+// when it set any, and against the org's otherwise.
+func (r *PRStatusRepo) TransitionAllowed(teamName string, fromStatus string, toStatus string) (bool, error) {
+	const op = "repo.prStatus.TransitionAllowed"
+
+	query := `
+		SELECT CASE
+			WHEN EXISTS(SELECT 1 FROM team_pr_status_transitions WHERE team_name = $1)
+				THEN EXISTS(SELECT 1 FROM team_pr_status_transitions
+					WHERE team_name = $1 AND from_status = $2 AND to_status = $3)
+			ELSE EXISTS(SELECT 1 FROM pr_status_transitions WHERE from_status = $2 AND to_status = $3)
+		END`
+
+	var allowed bool
+	err := r.storage.Get(&allowed, query, teamName, fromStatus, toStatus)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return allowed, nil
+}
+
+// IsTerminal reports whether PRs in the status are closed. Unknown statuses
+// are reported as ErrUnknownPRStatus.
+func (r *PRStatusRepo) IsTerminal(status string) (bool, error) {
+	const op = "repo.prStatus.IsTerminal"
+
+	query := `SELECT is_terminal FROM pr_statuses WHERE status = $1`
+
+	var terminal bool
+	if err := r.storage.Get(&terminal, query, status); err != nil {
+		if err == sql.ErrNoRows {
+			return false, fmt.Errorf("%s: %w", op, apperrors.ErrUnknownPRStatus)
+		}
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return terminal, nil
+}
+
+// SaveStatus adds the status or changes whether it is terminal. A status
+// some transition leaves cannot become terminal.
+func (r *PRStatusRepo) SaveStatus(status models.PRStatus) error {
+	const op = "repo.prStatus.SaveStatus"
+
+	query := `
+		INSERT INTO pr_statuses (status, is_terminal)
+		VALUES ($1, $2)
+		ON CONFLICT (status) DO UPDATE SET is_terminal = EXCLUDED.is_terminal
+		WHERE NOT EXCLUDED.is_terminal
+			OR NOT (EXISTS(SELECT 1 FROM pr_status_transitions WHERE from_status = $1)
+				OR EXISTS(SELECT 1 FROM team_pr_status_transitions WHERE from_status = $1))`
+
+	result, err := r.storage.Exec(query, status.Status, status.IsTerminal)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("%s: %w", op, apperrors.ErrInvalidStatusMachine)
+	}
+
+	return nil
+}
+
+// DeleteStatus removes the status. A status some PR is in or some
+// transition, org or team, uses cannot be removed: dropping its transitions
+// could leave MERGED unreachable.
+func (r *PRStatusRepo) DeleteStatus(status string) error {
+	const op = "repo.prStatus.DeleteStatus"
+
+	query := `
+		DELETE FROM pr_statuses
+		WHERE status = $1
+			AND NOT EXISTS(SELECT 1 FROM pr_status_transitions WHERE from_status = $1 OR to_status = $1)
+			AND NOT EXISTS(SELECT 1 FROM team_pr_status_transitions WHERE from_status = $1 OR to_status = $1)`
+
+	result, err := r.storage.Exec(query, status)
+	if err != nil {
+		if isForeignKeyError(err) {
+			return fmt.Errorf("%s: %w", op, apperrors.ErrPRStatusInUse)
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if rows > 0 {
+		return nil
+	}
+
+	exists, err := r.StatusExists(status)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if exists {
+		return fmt.Errorf("%s: %w", op, apperrors.ErrPRStatusInUse)
+	}
+
+	return fmt.Errorf("%s: %w", op, apperrors.ErrUnknownPRStatus)
+}
+
+// SetTransitions replaces the team's transitions, or the org's for an empty
+// team name. A team left without transitions follows the org's again.
+func (r *PRStatusRepo) SetTransitions(teamName string, transitions []models.PRStatusTransition) error {
+	const op = "repo.prStatus.SetTransitions"
+
+	tx, err := r.storage.Beginx()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if teamName == "" {
+		_, err = tx.Exec(`DELETE FROM pr_status_transitions`)
+	} else {
+		_, err = tx.Exec(`DELETE FROM team_pr_status_transitions WHERE team_name = $1`, teamName)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	for _, transition := range transitions {
+		if teamName == "" {
+			_, err = tx.Exec(`INSERT INTO pr_status_transitions (from_status, to_status) VALUES ($1, $2)`,
+				transition.FromStatus, transition.ToStatus)
+		} else {
+			_, err = tx.Exec(`INSERT INTO team_pr_status_transitions (team_name, from_status, to_status) VALUES ($1, $2, $3)`,
+				teamName, transition.FromStatus, transition.ToStatus)
+		}
+		if err != nil {
+			if isForeignKeyError(err) {
+				return fmt.Errorf("%s: %w", op, apperrors.ErrTeamNotFound)
+			}
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: failed to commit transaction: %w", op, err)
+	}
+
+	return nil
+}
@@ -4,11 +4,17 @@ import (
 	"database/sql"
 	"fmt"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"pull-request-assigner/internal/apperrors"
 	"pull-request-assigner/internal/domain/models"
+	"strings"
 	"time"
 )
 
+// likeEscaper quotes the LIKE wildcards of a search term; backslash is the
+// default LIKE escape character.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
 type PullRequestRepo struct {
 	storage *sqlx.DB
 }
@@ -21,8 +27,9 @@ func (r *PullRequestRepo) CreatePR(pr models.PullRequest) error {
 	const op = "repo.pullrequest.CreatePR"
 
 	query := `
-		INSERT INTO pull_requests (pull_request_id, pull_request_name, author_id, status, created_at)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO pull_requests (pull_request_id, pull_request_name, author_id, status, ci_status, priority, labels, required_skills, changed_paths, required_certifications, co_authors, pairing_session, created_at, auto_merge, auto_merge_approvals, assignment_queued, reviewer_pool, author_team)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, NULLIF($17, ''), NULLIF($18, ''))
+		ON CONFLICT (pull_request_id) DO NOTHING
 	`
 
 	authorID, err := extractUserID(pr.AuthorID)
@@ -30,29 +37,71 @@ func (r *PullRequestRepo) CreatePR(pr models.PullRequest) error {
 		return fmt.Errorf("%s: %w", op, apperrors.ErrAuthorRequired)
 	}
 
-	_, err = r.storage.Exec(query, pr.PullRequestId, pr.PullRequestName, authorID, pr.Status, pr.CreatedAt)
+	ciStatus := pr.CIStatus
+	if ciStatus == "" {
+		ciStatus = models.CIStatusUnknown
+	}
+
+	priority := pr.Priority
+	if priority == "" {
+		priority = models.PriorityNormal
+	}
+
+	autoMergeApprovals := pr.AutoMergeApprovals
+	if autoMergeApprovals < 1 {
+		autoMergeApprovals = 1
+	}
+
+	tx, err := r.storage.Beginx()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(query, pr.PullRequestId, pr.PullRequestName, authorID, pr.Status, ciStatus, priority,
+		pq.Array(nonNilTags(pr.Labels)), pq.Array(nonNilTags(pr.RequiredSkills)), pq.Array(nonNilTags(pr.ChangedPaths)),
+		pq.Array(nonNilTags(pr.RequiredCertifications)), pq.Array(nonNilTags(pr.CoAuthors)), pq.Array(nonNilTags(pr.PairingSession)),
+		pr.CreatedAt, pr.AutoMerge, autoMergeApprovals, pr.AssignmentQueued, pr.ReviewerPool, pr.AuthorTeam)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		if isDuplicateKeyError(err) {
-			return fmt.Errorf("%s: %w", op, apperrors.ErrPRExists)
-		}
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
+	if rowsAffected == 0 {
+		return fmt.Errorf("%s: %w", op, apperrors.ErrPRExists)
+	}
+
+	teamQuery := `INSERT INTO pr_review_teams (pull_request_id, team_name, reviewers) VALUES ($1, $2, $3)`
+
+	for _, quota := range pr.ReviewerTeams {
+		if _, err := tx.Exec(teamQuery, pr.PullRequestId, quota.TeamName, quota.Reviewers); err != nil {
+			return fmt.Errorf("%s: failed to add reviewer team %s: %w", op, quota.TeamName, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: failed to commit transaction: %w", op, err)
+	}
+
 	return nil
 }
 
 func (r *PullRequestRepo) PRExists(prID string) (bool, error) {
 	const op = "repo.pullRequest.PRExists"
 
-	query := `SELECT COUNT(*) FROM pull_requests WHERE pull_request_id = $1`
+	query := `SELECT EXISTS(SELECT 1 FROM pull_requests WHERE pull_request_id = $1)`
 
-	var count int
-	err := r.storage.Get(&count, query, prID)
+	var exists bool
+	err := r.storage.Get(&exists, query, prID)
 	if err != nil {
 		return false, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return count > 0, nil
+	return exists, nil
 }
 
 func (r *PullRequestRepo) GetPR(prID string) (*models.PullRequest, error) {
@@ -64,19 +113,49 @@ func (r *PullRequestRepo) GetPR(prID string) (*models.PullRequest, error) {
 			pull_request_name,
 			author_id,
 			status,
+			ci_status,
+			priority,
+			labels,
+			required_skills,
+			changed_paths,
+			required_certifications,
+			co_authors,
+			pairing_session,
 			created_at,
-			merged_at
+			merged_at,
+			merged_by,
+			auto_merge,
+			auto_merge_approvals,
+			assignment_queued,
+			under_reviewed,
+			COALESCE(reviewer_pool, '') AS reviewer_pool,
+			COALESCE(author_team, '') AS author_team
 		FROM pull_requests 
 		WHERE pull_request_id = $1
 	`
 
 	var pr struct {
-		PullRequestId   string       `db:"pull_request_id"`
-		PullRequestName string       `db:"pull_request_name"`
-		AuthorID        int          `db:"author_id"`
-		Status          string       `db:"status"`
-		CreatedAt       time.Time    `db:"created_at"`
-		MergedAt        sql.NullTime `db:"merged_at"`
+		PullRequestId      string         `db:"pull_request_id"`
+		PullRequestName    string         `db:"pull_request_name"`
+		AuthorID           int            `db:"author_id"`
+		Status             string         `db:"status"`
+		CIStatus           string         `db:"ci_status"`
+		Priority           string         `db:"priority"`
+		Labels             pq.StringArray `db:"labels"`
+		RequiredSkills     pq.StringArray `db:"required_skills"`
+		ChangedPaths       pq.StringArray `db:"changed_paths"`
+		RequiredCerts      pq.StringArray `db:"required_certifications"`
+		CoAuthors          pq.StringArray `db:"co_authors"`
+		PairingSession     pq.StringArray `db:"pairing_session"`
+		CreatedAt          time.Time      `db:"created_at"`
+		MergedAt           sql.NullTime   `db:"merged_at"`
+		MergedBy           sql.NullInt64  `db:"merged_by"`
+		AutoMerge          bool           `db:"auto_merge"`
+		AutoMergeApprovals int            `db:"auto_merge_approvals"`
+		AssignmentQueued   bool           `db:"assignment_queued"`
+		UnderReviewed      bool           `db:"under_reviewed"`
+		ReviewerPool       string         `db:"reviewer_pool"`
+		AuthorTeam         string         `db:"author_team"`
 	}
 
 	err := r.storage.Get(&pr, query, prID)
@@ -87,18 +166,57 @@ func (r *PullRequestRepo) GetPR(prID string) (*models.PullRequest, error) {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
+	reviewerTeams := make([]models.ReviewerTeamQuota, 0)
+	teamsQuery := `
+		SELECT team_name, reviewers
+		FROM pr_review_teams
+		WHERE pull_request_id = $1
+		ORDER BY team_name
+	`
+
+	if err := r.storage.Select(&reviewerTeams, teamsQuery, prID); err != nil {
+		return nil, fmt.Errorf("%s: failed to get reviewer teams: %w", op, err)
+	}
+
 	result := &models.PullRequest{
-		PullRequestId:   pr.PullRequestId,
-		PullRequestName: pr.PullRequestName,
-		AuthorID:        fmt.Sprintf("u%d", pr.AuthorID),
-		Status:          pr.Status,
-		CreatedAt:       pr.CreatedAt,
-		MergedAt:        pr.MergedAt,
+		PullRequestId:          pr.PullRequestId,
+		PullRequestName:        pr.PullRequestName,
+		AuthorID:               models.UserID(pr.AuthorID).String(),
+		Status:                 pr.Status,
+		CIStatus:               pr.CIStatus,
+		Priority:               pr.Priority,
+		Labels:                 []string(pr.Labels),
+		RequiredSkills:         []string(pr.RequiredSkills),
+		ChangedPaths:           []string(pr.ChangedPaths),
+		RequiredCertifications: []string(pr.RequiredCerts),
+		CoAuthors:              []string(pr.CoAuthors),
+		PairingSession:         []string(pr.PairingSession),
+		ReviewerTeams:          reviewerTeams,
+		CreatedAt:              pr.CreatedAt,
+		MergedAt:               pr.MergedAt,
+		AutoMerge:              pr.AutoMerge,
+		AutoMergeApprovals:     pr.AutoMergeApprovals,
+		AssignmentQueued:       pr.AssignmentQueued,
+		UnderReviewed:          pr.UnderReviewed,
+		ReviewerPool:           pr.ReviewerPool,
+		AuthorTeam:             pr.AuthorTeam,
+	}
+
+	if pr.MergedBy.Valid {
+		result.MergedBy = models.UserID(pr.MergedBy.Int64).String()
 	}
 
 	return result, nil
 }
 
+// prReviewersQuery lists the reviewer IDs of a PR. It is one of the
+// hotQueries checked by /admin/dbcheck.
+const prReviewersQuery = `
+	SELECT reviewer_id
+	FROM pr_reviewers
+	WHERE pull_request_id = $1
+`
+
 func (r *PullRequestRepo) GetPRWithReviewers(prID string) (*models.PullRequest, []string, error) {
 	const op = "repo.pullRequest.GetPRWithReviewers"
 
@@ -107,21 +225,15 @@ func (r *PullRequestRepo) GetPRWithReviewers(prID string) (*models.PullRequest,
 		return nil, nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	reviewersQuery := `
-		SELECT reviewer_id 
-		FROM pr_reviewers 
-		WHERE pull_request_id = $1
-	`
-
 	var reviewerIDs []int
-	err = r.storage.Select(&reviewerIDs, reviewersQuery, prID)
+	err = r.storage.Select(&reviewerIDs, prReviewersQuery, prID)
 	if err != nil {
 		return nil, nil, fmt.Errorf("%s: failed to get reviewers: %w", op, err)
 	}
 
 	reviewerStrs := make([]string, len(reviewerIDs))
 	for i, id := range reviewerIDs {
-		reviewerStrs[i] = fmt.Sprintf("u%d", id)
+		reviewerStrs[i] = models.UserID(id).String()
 	}
 
 	return pr, reviewerStrs, nil
@@ -148,6 +260,64 @@ func (r *PullRequestRepo) AddPRReviewers(prID string, reviewerIDs []string) erro
 		if err != nil {
 			return fmt.Errorf("%s: failed to add reviewer %s: %w", op, reviewerID, err)
 		}
+
+		if err := recordAssignment(tx, prID, reviewerIDInt, models.AssignmentActionAuto, "", ""); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: failed to commit transaction: %w", op, err)
+	}
+
+	return nil
+}
+
+// AddReviewersToEmptyPR assigns reviewers to a PR that has none. The PR row
+// stays locked from the check to the insert, so two paths assigning the same
+// PR cannot both add reviewers; the later one gets ErrPRHasReviewers.
+func (r *PullRequestRepo) AddReviewersToEmptyPR(prID string, reviewerIDs []string) error {
+	const op = "repo.pullRequest.AddReviewersToEmptyPR"
+
+	tx, err := r.storage.Beginx()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	var locked string
+	err = tx.Get(&locked, `SELECT pull_request_id FROM pull_requests WHERE pull_request_id = $1 FOR UPDATE`, prID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("%s: %w", op, apperrors.ErrPRNotFound)
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	var hasReviewers bool
+	err = tx.Get(&hasReviewers, `SELECT EXISTS (SELECT 1 FROM pr_reviewers WHERE pull_request_id = $1)`, prID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if hasReviewers {
+		return fmt.Errorf("%s: %w", op, apperrors.ErrPRHasReviewers)
+	}
+
+	query := `INSERT INTO pr_reviewers (pull_request_id, reviewer_id) VALUES ($1, $2)`
+
+	for _, reviewerID := range reviewerIDs {
+		reviewerIDInt, err := extractUserID(reviewerID)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, apperrors.ErrAuthorRequired)
+		}
+
+		if _, err := tx.Exec(query, prID, reviewerIDInt); err != nil {
+			return fmt.Errorf("%s: failed to add reviewer %s: %w", op, reviewerID, err)
+		}
+
+		if err := recordAssignment(tx, prID, reviewerIDInt, models.AssignmentActionAuto, "", ""); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
 	}
 
 	if err := tx.Commit(); err != nil {
@@ -157,17 +327,31 @@ func (r *PullRequestRepo) AddPRReviewers(prID string, reviewerIDs []string) erro
 	return nil
 }
 
-func (r *PullRequestRepo) MergePR(prID string) error {
+// MergePR marks the PR merged. An empty mergedBy leaves the merge
+// unattributed.
+func (r *PullRequestRepo) MergePR(prID string, mergedBy string) error {
 	const op = "repo.pullRequest.MergePR"
 
+	var mergedByID sql.NullInt64
+	if mergedBy != "" {
+		id, err := extractUserID(mergedBy)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, apperrors.ErrInvalidUserID)
+		}
+		mergedByID = sql.NullInt64{Int64: int64(id), Valid: true}
+	}
+
 	query := `
 		UPDATE pull_requests 
-		SET status = 'MERGED', merged_at = $1
-		WHERE pull_request_id = $2 AND status != 'MERGED'
+		SET status = 'MERGED', merged_at = $1, merged_by = $2
+		WHERE pull_request_id = $3 AND status != 'MERGED'
 	`
 
-	result, err := r.storage.Exec(query, time.Now(), prID)
+	result, err := r.storage.Exec(query, time.Now(), mergedByID, prID)
 	if err != nil {
+		if isForeignKeyError(err) {
+			return fmt.Errorf("%s: %w", op, apperrors.ErrUserNotFound)
+		}
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
@@ -182,8 +366,152 @@ func (r *PullRequestRepo) MergePR(prID string) error {
 			return fmt.Errorf("%s: %w", op, err)
 		}
 		if exists {
-			return nil
+			return fmt.Errorf("%s: %w", op, apperrors.ErrPRAlreadyMerged)
+		}
+		return fmt.Errorf("%s: %w", op, apperrors.ErrPRNotFound)
+	}
+
+	return nil
+}
+
+func (r *PullRequestRepo) SetStatus(prID string, status string) error {
+	const op = "repo.pullRequest.SetStatus"
+
+	query := `UPDATE pull_requests SET status = $1 WHERE pull_request_id = $2`
+
+	result, err := r.storage.Exec(query, status, prID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("%s: %w", op, apperrors.ErrPRNotFound)
+	}
+
+	return nil
+}
+
+// ReconcileStatuses compares each claim with the stored PR under a row lock
+// and writes the differences in one transaction; with dryRun nothing is
+// written. A claimed MERGED status without merged_at keeps the stored merge
+// time, or takes the current time for a PR that was not merged. Leaving
+// MERGED clears merged_at and merged_by.
+func (r *PullRequestRepo) ReconcileStatuses(claims []models.PRStatusClaim, dryRun bool) ([]models.ReconcileResult, error) {
+	const op = "repo.pullRequest.ReconcileStatuses"
+
+	tx, err := r.storage.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	results := make([]models.ReconcileResult, 0, len(claims))
+
+	for _, claim := range claims {
+		var stored struct {
+			Status   string       `db:"status"`
+			MergedAt sql.NullTime `db:"merged_at"`
+		}
+		err := tx.Get(&stored, `SELECT status, merged_at FROM pull_requests WHERE pull_request_id = $1 FOR UPDATE`, claim.PullRequestID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				results = append(results, models.ReconcileResult{
+					PullRequestID: claim.PullRequestID,
+					Outcome:       models.ReconcileNotFound,
+				})
+				continue
+			}
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		var mergedAt *time.Time
+		if claim.Status == models.PRStatusMerged {
+			switch {
+			case claim.MergedAt != nil:
+				at := claim.MergedAt.UTC().Truncate(time.Microsecond)
+				mergedAt = &at
+			case stored.MergedAt.Valid:
+				at := stored.MergedAt.Time
+				mergedAt = &at
+			default:
+				mergedAt = &now
+			}
+		}
+
+		var previousMergedAt *time.Time
+		if stored.MergedAt.Valid {
+			at := stored.MergedAt.Time
+			previousMergedAt = &at
 		}
+
+		result := models.ReconcileResult{
+			PullRequestID:    claim.PullRequestID,
+			Outcome:          models.ReconcileUnchanged,
+			PreviousStatus:   stored.Status,
+			Status:           claim.Status,
+			PreviousMergedAt: previousMergedAt,
+			MergedAt:         mergedAt,
+		}
+
+		sameMergedAt := (previousMergedAt == nil) == (mergedAt == nil) &&
+			(mergedAt == nil || previousMergedAt.Equal(*mergedAt))
+		if stored.Status == claim.Status && sameMergedAt {
+			results = append(results, result)
+			continue
+		}
+
+		result.Outcome = models.ReconcileCorrected
+		results = append(results, result)
+
+		if dryRun {
+			continue
+		}
+
+		_, err = tx.Exec(`
+			UPDATE pull_requests
+			SET status = $1,
+				merged_at = $2,
+				merged_by = CASE WHEN $3 THEN merged_by END
+			WHERE pull_request_id = $4`,
+			claim.Status, mergedAt, claim.Status == models.PRStatusMerged, claim.PullRequestID)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if dryRun {
+		return results, nil
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: failed to commit transaction: %w", op, err)
+	}
+
+	return results, nil
+}
+
+func (r *PullRequestRepo) UpdateCIStatus(prID string, ciStatus string) error {
+	const op = "repo.pullRequest.UpdateCIStatus"
+
+	query := `UPDATE pull_requests SET ci_status = $1 WHERE pull_request_id = $2`
+
+	result, err := r.storage.Exec(query, ciStatus, prID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if rowsAffected == 0 {
 		return fmt.Errorf("%s: %w", op, apperrors.ErrPRNotFound)
 	}
 
@@ -212,17 +540,81 @@ func (r *PullRequestRepo) GetAuthorTeam(authorID string) (string, error) {
 	return teamName, nil
 }
 
-func (r *PullRequestRepo) GetActiveTeamMembers(teamName string, excludeUserIDs []string) ([]string, error) {
-	const op = "repo.pullRequest.GetActiveTeamMembers"
+// GetUserTeams returns the teams of the user, the primary one from
+// users.team_name first and then the secondary memberships by name.
+func (r *PullRequestRepo) GetUserTeams(userID string) ([]string, error) {
+	const op = "repo.pullRequest.GetUserTeams"
+
+	userIDInt, err := extractUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, apperrors.ErrAuthorRequired)
+	}
 
 	query := `
-		SELECT user_id 
-		FROM users 
-		WHERE team_name = $1 AND is_active = true
+		SELECT team_name FROM (
+			SELECT u.team_name, 0 AS rank
+			FROM users u
+			WHERE u.user_id = $1
+			UNION
+			SELECT tm.team_name, 1 AS rank
+			FROM team_members tm
+			JOIN users u ON u.user_id = tm.user_id
+			WHERE tm.user_id = $1 AND NOT tm.is_primary AND tm.team_name <> u.team_name
+		) teams
+		ORDER BY rank, team_name
 	`
 
+	var teams []string
+	if err := r.storage.Select(&teams, query, userIDInt); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(teams) == 0 {
+		return nil, fmt.Errorf("%s: %w", op, apperrors.ErrPRAuthorNotFound)
+	}
+
+	return teams, nil
+}
+
+// IsAuthorOnly reports whether the user is in an author-only period.
+func (r *PullRequestRepo) IsAuthorOnly(userID string) (bool, error) {
+	const op = "repo.pullRequest.IsAuthorOnly"
+
+	userIDInt, err := extractUserID(userID)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var authorOnly bool
+	err = r.storage.Get(&authorOnly, `SELECT NOT (`+reviewerEligible+`) FROM users u WHERE u.user_id = $1`, userIDInt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return authorOnly, nil
+}
+
+// activeTeamMembersQuery lists the active, reviewer-eligible members of a
+// team, secondary memberships included. It is one of the hotQueries checked
+// by /admin/dbcheck.
+const activeTeamMembersQuery = `
+	SELECT u.user_id
+	FROM users u
+	WHERE u.is_active = true AND ` + reviewerEligible + ` AND (u.team_name = $1 OR EXISTS (
+		SELECT 1 FROM team_members tm
+		WHERE tm.user_id = u.user_id AND tm.team_name = $1 AND NOT tm.is_primary))
+`
+
+// GetActiveTeamMembers returns the active, non author-only users whose
+// primary team is the team and those who are secondary members of it.
+func (r *PullRequestRepo) GetActiveTeamMembers(teamName string, excludeUserIDs []string) ([]string, error) {
+	const op = "repo.pullRequest.GetActiveTeamMembers"
+
 	var userIDs []int
-	err := r.storage.Select(&userIDs, query, teamName)
+	err := r.storage.Select(&userIDs, activeTeamMembersQuery, teamName)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
@@ -234,7 +626,7 @@ func (r *PullRequestRepo) GetActiveTeamMembers(teamName string, excludeUserIDs [
 	}
 
 	for _, id := range userIDs {
-		userIDStr := fmt.Sprintf("u%d", id)
+		userIDStr := models.UserID(id).String()
 		if !excludeMap[userIDStr] {
 			result = append(result, userIDStr)
 		}
@@ -243,7 +635,9 @@ func (r *PullRequestRepo) GetActiveTeamMembers(teamName string, excludeUserIDs [
 	return result, nil
 }
 
-func (r *PullRequestRepo) ReplaceReviewer(prID string, oldReviewerID string, newReviewerID string) error {
+// ReplaceReviewer swaps the reviewer and records the reassignment together
+// with the hand-off note for the new reviewer; an empty note stores none.
+func (r *PullRequestRepo) ReplaceReviewer(prID string, oldReviewerID string, newReviewerID string, reason string, note string) error {
 	const op = "repo.pullRequest.ReplaceReviewer"
 
 	tx, err := r.storage.Beginx()
@@ -252,15 +646,15 @@ func (r *PullRequestRepo) ReplaceReviewer(prID string, oldReviewerID string, new
 	}
 	defer tx.Rollback()
 
-	checkQuery := `SELECT COUNT(*) FROM pr_reviewers WHERE pull_request_id = $1 AND reviewer_id = $2`
-	var count int
+	checkQuery := `SELECT EXISTS(SELECT 1 FROM pr_reviewers WHERE pull_request_id = $1 AND reviewer_id = $2)`
+	var assigned bool
 	oldReviewerIDInt, _ := extractUserID(oldReviewerID)
-	err = tx.Get(&count, checkQuery, prID, oldReviewerIDInt)
+	err = tx.Get(&assigned, checkQuery, prID, oldReviewerIDInt)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
-	if count == 0 {
+	if !assigned {
 		return fmt.Errorf("%s: %w", op, apperrors.ErrReviewerNotAssigned)
 	}
 
@@ -277,6 +671,23 @@ func (r *PullRequestRepo) ReplaceReviewer(prID string, oldReviewerID string, new
 		return fmt.Errorf("%s: failed to add new reviewer: %w", op, err)
 	}
 
+	if err := recordAssignment(tx, prID, newReviewerIDInt, models.AssignmentActionReassign, "", reason); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if note != "" {
+		noteQuery := `
+			UPDATE assignment_history SET note = $3
+			WHERE id = (
+				SELECT MAX(id) FROM assignment_history
+				WHERE pull_request_id = $1 AND reviewer_id = $2
+			)
+		`
+		if _, err := tx.Exec(noteQuery, prID, newReviewerIDInt, note); err != nil {
+			return fmt.Errorf("%s: failed to store hand-off note: %w", op, err)
+		}
+	}
+
 	if err := tx.Commit(); err != nil {
 		return fmt.Errorf("%s: failed to commit transaction: %w", op, err)
 	}
@@ -284,11 +695,699 @@ func (r *PullRequestRepo) ReplaceReviewer(prID string, oldReviewerID string, new
 	return nil
 }
 
-func extractUserID(userIDStr string) (int, error) {
-	var userID int
-	_, err := fmt.Sscanf(userIDStr, "u%d", &userID)
+// AssignReviewer adds the reviewer to the PR, optionally removing replaceReviewerID
+// in the same transaction, and records the change as MANUAL in assignment history.
+func (r *PullRequestRepo) AssignReviewer(prID string, reviewerID string, replaceReviewerID string, actorID string) error {
+	const op = "repo.pullRequest.AssignReviewer"
+
+	reviewerIDInt, err := extractUserID(reviewerID)
 	if err != nil {
-		return 0, apperrors.ErrAuthorRequired
+		return fmt.Errorf("%s: %w", op, apperrors.ErrInvalidUserID)
+	}
+
+	tx, err := r.storage.Beginx()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if replaceReviewerID != "" {
+		replaceIDInt, err := extractUserID(replaceReviewerID)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, apperrors.ErrInvalidUserID)
+		}
+
+		deleteQuery := `DELETE FROM pr_reviewers WHERE pull_request_id = $1 AND reviewer_id = $2`
+		result, err := tx.Exec(deleteQuery, prID, replaceIDInt)
+		if err != nil {
+			return fmt.Errorf("%s: failed to remove old reviewer: %w", op, err)
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if rowsAffected == 0 {
+			return fmt.Errorf("%s: %w", op, apperrors.ErrReviewerNotAssigned)
+		}
+	}
+
+	insertQuery := `INSERT INTO pr_reviewers (pull_request_id, reviewer_id) VALUES ($1, $2)`
+	_, err = tx.Exec(insertQuery, prID, reviewerIDInt)
+	if err != nil {
+		if isDuplicateKeyError(err) {
+			return fmt.Errorf("%s: %w", op, apperrors.ErrReviewerAssigned)
+		}
+		return fmt.Errorf("%s: failed to add reviewer: %w", op, err)
+	}
+
+	if err := recordAssignment(tx, prID, reviewerIDInt, models.AssignmentActionManual, actorID, ""); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
 	}
-	return userID, nil
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: failed to commit transaction: %w", op, err)
+	}
+
+	return nil
+}
+
+// DelegateReviewer hands the reviewer's assignment over to delegateID in one
+// transaction and records it as DELEGATE, with the original reviewer as actor.
+func (r *PullRequestRepo) DelegateReviewer(prID string, reviewerID string, delegateID string) error {
+	const op = "repo.pullRequest.DelegateReviewer"
+
+	reviewerIDInt, err := extractUserID(reviewerID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, apperrors.ErrInvalidUserID)
+	}
+
+	delegateIDInt, err := extractUserID(delegateID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, apperrors.ErrInvalidUserID)
+	}
+
+	tx, err := r.storage.Beginx()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	deleteQuery := `DELETE FROM pr_reviewers WHERE pull_request_id = $1 AND reviewer_id = $2`
+	result, err := tx.Exec(deleteQuery, prID, reviewerIDInt)
+	if err != nil {
+		return fmt.Errorf("%s: failed to remove reviewer: %w", op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("%s: %w", op, apperrors.ErrReviewerNotAssigned)
+	}
+
+	insertQuery := `INSERT INTO pr_reviewers (pull_request_id, reviewer_id) VALUES ($1, $2)`
+	_, err = tx.Exec(insertQuery, prID, delegateIDInt)
+	if err != nil {
+		if isDuplicateKeyError(err) {
+			return fmt.Errorf("%s: %w", op, apperrors.ErrReviewerAssigned)
+		}
+		return fmt.Errorf("%s: failed to add delegate: %w", op, err)
+	}
+
+	if err := recordAssignment(tx, prID, delegateIDInt, models.AssignmentActionDelegate, reviewerID, ""); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: failed to commit transaction: %w", op, err)
+	}
+
+	return nil
+}
+
+// RemoveReviewer drops the reviewer from the PR without a replacement and records
+// the change as UNASSIGN in assignment history.
+func (r *PullRequestRepo) RemoveReviewer(prID string, reviewerID string, actorID string) error {
+	const op = "repo.pullRequest.RemoveReviewer"
+
+	reviewerIDInt, err := extractUserID(reviewerID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, apperrors.ErrInvalidUserID)
+	}
+
+	tx, err := r.storage.Beginx()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	deleteQuery := `DELETE FROM pr_reviewers WHERE pull_request_id = $1 AND reviewer_id = $2`
+	result, err := tx.Exec(deleteQuery, prID, reviewerIDInt)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("%s: %w", op, apperrors.ErrReviewerNotAssigned)
+	}
+
+	if err := recordAssignment(tx, prID, reviewerIDInt, models.AssignmentActionUnassign, actorID, ""); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: failed to commit transaction: %w", op, err)
+	}
+
+	return nil
+}
+
+// GetReviewersByRecency returns the current reviewers of the PR, the most
+// recently assigned first. Reviewers without assignment history come last.
+func (r *PullRequestRepo) GetReviewersByRecency(prID string) ([]string, error) {
+	const op = "repo.pullRequest.GetReviewersByRecency"
+
+	query := `
+		SELECT pr.reviewer_id
+		FROM pr_reviewers pr
+		LEFT JOIN assignment_history h
+			ON h.pull_request_id = pr.pull_request_id
+			AND h.reviewer_id = pr.reviewer_id
+			AND h.action <> $2
+		WHERE pr.pull_request_id = $1
+		GROUP BY pr.reviewer_id
+		ORDER BY MAX(h.id) DESC NULLS LAST, pr.reviewer_id DESC
+	`
+
+	var reviewerIDs []int
+	if err := r.storage.Select(&reviewerIDs, query, prID, models.AssignmentActionUnassign); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	result := make([]string, 0, len(reviewerIDs))
+	for _, id := range reviewerIDs {
+		result = append(result, models.UserID(id).String())
+	}
+
+	return result, nil
+}
+
+// ResizeReviewers adds and removes reviewers of the PR in one transaction.
+// Added reviewers are recorded as AUTO, removed ones as UNASSIGN, both
+// attributed to actorID.
+func (r *PullRequestRepo) ResizeReviewers(prID string, added []string, removed []string, actorID string) error {
+	const op = "repo.pullRequest.ResizeReviewers"
+
+	tx, err := r.storage.Beginx()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	for _, reviewerID := range removed {
+		reviewerIDInt, err := extractUserID(reviewerID)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, apperrors.ErrInvalidUserID)
+		}
+
+		result, err := tx.Exec(`DELETE FROM pr_reviewers WHERE pull_request_id = $1 AND reviewer_id = $2`, prID, reviewerIDInt)
+		if err != nil {
+			return fmt.Errorf("%s: failed to remove reviewer %s: %w", op, reviewerID, err)
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if rowsAffected == 0 {
+			return fmt.Errorf("%s: %w", op, apperrors.ErrReviewerNotAssigned)
+		}
+
+		if err := recordAssignment(tx, prID, reviewerIDInt, models.AssignmentActionUnassign, actorID, ""); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	for _, reviewerID := range added {
+		reviewerIDInt, err := extractUserID(reviewerID)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, apperrors.ErrInvalidUserID)
+		}
+
+		_, err = tx.Exec(`INSERT INTO pr_reviewers (pull_request_id, reviewer_id) VALUES ($1, $2)`, prID, reviewerIDInt)
+		if err != nil {
+			if isDuplicateKeyError(err) {
+				return fmt.Errorf("%s: %w", op, apperrors.ErrReviewerAssigned)
+			}
+			return fmt.Errorf("%s: failed to add reviewer %s: %w", op, reviewerID, err)
+		}
+
+		if err := recordAssignment(tx, prID, reviewerIDInt, models.AssignmentActionAuto, actorID, ""); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: failed to commit transaction: %w", op, err)
+	}
+
+	return nil
+}
+
+// DeclineReview drops the reviewer who declined the PR and marks the PR
+// under-reviewed. It is used when no replacement is available; the decline is
+// recorded as UNASSIGN by the reviewer with reason DECLINED.
+func (r *PullRequestRepo) DeclineReview(prID string, reviewerID string) error {
+	const op = "repo.pullRequest.DeclineReview"
+
+	reviewerIDInt, err := extractUserID(reviewerID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, apperrors.ErrInvalidUserID)
+	}
+
+	tx, err := r.storage.Beginx()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`DELETE FROM pr_reviewers WHERE pull_request_id = $1 AND reviewer_id = $2`, prID, reviewerIDInt)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("%s: %w", op, apperrors.ErrReviewerNotAssigned)
+	}
+
+	if err := recordAssignment(tx, prID, reviewerIDInt, models.AssignmentActionUnassign, reviewerID, models.ReassignReasonDeclined); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := tx.Exec(`UPDATE pull_requests SET under_reviewed = TRUE WHERE pull_request_id = $1`, prID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: failed to commit transaction: %w", op, err)
+	}
+
+	return nil
+}
+
+// StartReview marks the reviewer's review of the PR as in progress. Repeated
+// calls keep the original start time.
+func (r *PullRequestRepo) StartReview(prID string, reviewerID string) (time.Time, error) {
+	const op = "repo.pullRequest.StartReview"
+
+	reviewerIDInt, err := extractUserID(reviewerID)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s: %w", op, apperrors.ErrInvalidUserID)
+	}
+
+	query := `
+		UPDATE pr_reviewers
+		SET review_started_at = COALESCE(review_started_at, NOW())
+		WHERE pull_request_id = $1 AND reviewer_id = $2
+		RETURNING review_started_at
+	`
+
+	var startedAt time.Time
+	err = r.storage.Get(&startedAt, query, prID, reviewerIDInt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return time.Time{}, fmt.Errorf("%s: %w", op, apperrors.ErrReviewerNotAssigned)
+		}
+		return time.Time{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return startedAt, nil
+}
+
+// CompleteReview marks the reviewer's own review done, starting it as well if
+// it was never started. Completing twice keeps the first completion time.
+func (r *PullRequestRepo) CompleteReview(prID string, reviewerID string) (time.Time, error) {
+	const op = "repo.pullRequest.CompleteReview"
+
+	reviewerIDInt, err := extractUserID(reviewerID)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s: %w", op, apperrors.ErrInvalidUserID)
+	}
+
+	query := `
+		UPDATE pr_reviewers
+		SET review_started_at = COALESCE(review_started_at, NOW()),
+			review_completed_at = COALESCE(review_completed_at, NOW())
+		WHERE pull_request_id = $1 AND reviewer_id = $2
+		RETURNING review_completed_at
+	`
+
+	var completedAt time.Time
+	err = r.storage.Get(&completedAt, query, prID, reviewerIDInt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return time.Time{}, fmt.Errorf("%s: %w", op, apperrors.ErrReviewerNotAssigned)
+		}
+		return time.Time{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return completedAt, nil
+}
+
+// ApprovePR records the approval of an assigned reviewer. Approving twice keeps
+// the first approval time.
+func (r *PullRequestRepo) ApprovePR(prID string, reviewerID string) (time.Time, error) {
+	const op = "repo.pullRequest.ApprovePR"
+
+	reviewerIDInt, err := extractUserID(reviewerID)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s: %w", op, apperrors.ErrInvalidUserID)
+	}
+
+	query := `
+		INSERT INTO pr_approvals (pull_request_id, reviewer_id)
+		SELECT pull_request_id, reviewer_id
+		FROM pr_reviewers
+		WHERE pull_request_id = $1 AND reviewer_id = $2
+		ON CONFLICT (pull_request_id, reviewer_id) DO UPDATE SET approved_at = pr_approvals.approved_at
+		RETURNING approved_at
+	`
+
+	var approvedAt time.Time
+	err = r.storage.Get(&approvedAt, query, prID, reviewerIDInt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return time.Time{}, fmt.Errorf("%s: %w", op, apperrors.ErrReviewerNotAssigned)
+		}
+		return time.Time{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return approvedAt, nil
+}
+
+// GetApprovers returns reviewers who approved the PR and are still assigned.
+func (r *PullRequestRepo) GetApprovers(prID string) ([]string, error) {
+	const op = "repo.pullRequest.GetApprovers"
+
+	query := `
+		SELECT 'u' || a.reviewer_id
+		FROM pr_approvals a
+		JOIN pr_reviewers prr ON prr.pull_request_id = a.pull_request_id AND prr.reviewer_id = a.reviewer_id
+		WHERE a.pull_request_id = $1
+		ORDER BY a.approved_at
+	`
+
+	approvers := make([]string, 0)
+	if err := r.storage.Select(&approvers, query, prID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return approvers, nil
+}
+
+// recordAssignment appends to assignment history; reason is set only for
+// reassignments and declines. A reviewer added to the PR clears its
+// under-reviewed mark.
+func recordAssignment(tx *sqlx.Tx, prID string, reviewerID int, action string, actorID string, reason string) error {
+	var actor sql.NullInt64
+	if id, err := extractUserID(actorID); err == nil {
+		actor = sql.NullInt64{Int64: int64(id), Valid: true}
+	}
+
+	query := `
+		INSERT INTO assignment_history (pull_request_id, reviewer_id, action, actor_id, reason)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''))
+	`
+
+	if _, err := tx.Exec(query, prID, reviewerID, action, actor, reason); err != nil {
+		return fmt.Errorf("failed to record assignment history: %w", err)
+	}
+
+	if action == models.AssignmentActionUnassign {
+		return nil
+	}
+
+	if action == models.AssignmentActionAuto || action == models.AssignmentActionManual {
+		_, err := tx.Exec(`UPDATE pull_requests SET under_reviewed = FALSE WHERE pull_request_id = $1 AND under_reviewed`, prID)
+		if err != nil {
+			return fmt.Errorf("failed to clear under-reviewed mark: %w", err)
+		}
+	}
+
+	// Every new assignment counts towards the reviewer's daily cap, also the
+	// manual ones the cap does not block.
+	counterQuery := `
+		INSERT INTO reviewer_daily_assignments (user_id, day, assignments)
+		VALUES ($1, (NOW() AT TIME ZONE 'UTC')::date, 1)
+		ON CONFLICT (user_id, day) DO UPDATE SET assignments = reviewer_daily_assignments.assignments + 1
+	`
+
+	if _, err := tx.Exec(counterQuery, reviewerID); err != nil {
+		return fmt.Errorf("failed to count daily assignment: %w", err)
+	}
+
+	return nil
+}
+
+// GetDailyCappedUsers returns the users who already got as many assignments
+// today (UTC) as their daily cap allows.
+func (r *PullRequestRepo) GetDailyCappedUsers() ([]string, error) {
+	const op = "repo.pullRequest.GetDailyCappedUsers"
+
+	query := `
+		SELECT u.user_id
+		FROM users u
+		JOIN reviewer_daily_assignments d ON d.user_id = u.user_id
+		WHERE u.max_daily_assignments > 0
+			AND d.day = (NOW() AT TIME ZONE 'UTC')::date
+			AND d.assignments >= u.max_daily_assignments
+		ORDER BY u.user_id
+	`
+
+	var userIDs []int
+	if err := r.storage.Select(&userIDs, query); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	result := make([]string, 0, len(userIDs))
+	for _, id := range userIDs {
+		result = append(result, models.UserID(id).String())
+	}
+
+	return result, nil
+}
+
+// GetPRExportPage returns up to limit PRs ordered by (created_at, pull_request_id)
+// strictly after the given cursor. A zero cursor starts from the beginning.
+func (r *PullRequestRepo) GetPRExportPage(search models.PRSearch, afterCreatedAt time.Time, afterID string, limit int) ([]models.PullRequestExport, error) {
+	const op = "repo.pullRequest.GetPRExportPage"
+
+	query := `
+		SELECT
+			pr.pull_request_id,
+			pr.pull_request_name,
+			'u' || pr.author_id as author_id,
+			pr.status,
+			pr.ci_status,
+			pr.priority,
+			pr.created_at,
+			pr.merged_at,
+			ARRAY(
+				SELECT 'u' || prr.reviewer_id
+				FROM pr_reviewers prr
+				WHERE prr.pull_request_id = pr.pull_request_id
+				ORDER BY prr.reviewer_id
+			) as reviewers
+		FROM pull_requests pr
+		WHERE (pr.created_at, pr.pull_request_id) > ($1, $2)
+	`
+	args := []interface{}{afterCreatedAt, afterID}
+
+	if search.Name != "" {
+		args = append(args, "%"+likeEscaper.Replace(search.Name)+"%")
+		query += fmt.Sprintf(" AND pr.pull_request_name ILIKE $%d", len(args))
+	}
+	for _, bound := range []struct {
+		at        *time.Time
+		condition string
+	}{
+		{search.CreatedFrom, "pr.created_at >= $%d"},
+		{search.CreatedTo, "pr.created_at < $%d"},
+		{search.MergedFrom, "pr.merged_at >= $%d"},
+		{search.MergedTo, "pr.merged_at < $%d"},
+	} {
+		if bound.at != nil {
+			args = append(args, bound.at.UTC())
+			query += " AND " + fmt.Sprintf(bound.condition, len(args))
+		}
+	}
+	if search.ReviewerID != "" {
+		reviewerID, err := extractUserID(search.ReviewerID)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, apperrors.ErrInvalidUserID)
+		}
+		args = append(args, reviewerID)
+		query += fmt.Sprintf(` AND EXISTS (
+			SELECT 1 FROM pr_reviewers prr
+			WHERE prr.pull_request_id = pr.pull_request_id AND prr.reviewer_id = $%d)`, len(args))
+	}
+
+	args = append(args, limit)
+	query += fmt.Sprintf(" ORDER BY pr.created_at, pr.pull_request_id LIMIT $%d", len(args))
+
+	rows, err := r.storage.Queryx(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	page := make([]models.PullRequestExport, 0, limit)
+	for rows.Next() {
+		var pr models.PullRequestExport
+		var mergedAt sql.NullTime
+		var reviewers pq.StringArray
+
+		err := rows.Scan(&pr.PullRequestId, &pr.PullRequestName, &pr.AuthorID, &pr.Status,
+			&pr.CIStatus, &pr.Priority, &pr.CreatedAt, &mergedAt, &reviewers)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if mergedAt.Valid {
+			pr.MergedAt = &mergedAt.Time
+		}
+		pr.Reviewers = reviewers
+		page = append(page, pr)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return page, nil
+}
+
+// GetCandidateStats returns active, non author-only members of the team except
+// the excluded ones together with their current open review load and how often
+// they reviewed the author's PRs since the given moment.
+func (r *PullRequestRepo) GetCandidateStats(teamName string, authorID string, excludeUserIDs []string, since time.Time) ([]models.ReviewerCandidate, error) {
+	const op = "repo.pullRequest.GetCandidateStats"
+
+	authorIDInt, err := extractUserID(authorID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, apperrors.ErrAuthorRequired)
+	}
+
+	exclude := make([]int, 0, len(excludeUserIDs))
+	for _, id := range excludeUserIDs {
+		idInt, err := extractUserID(id)
+		if err != nil {
+			continue
+		}
+		exclude = append(exclude, idInt)
+	}
+
+	query := `
+		SELECT
+			'u' || u.user_id as user_id,
+			u.username,
+			(SELECT COUNT(*)
+				FROM pr_reviewers prr
+				JOIN pull_requests pr ON pr.pull_request_id = prr.pull_request_id
+				JOIN pr_statuses ps ON ps.status = pr.status
+				WHERE prr.reviewer_id = u.user_id AND ps.is_terminal = false
+					AND prr.review_completed_at IS NULL) as open_reviews,
+			(SELECT COUNT(*)
+				FROM pr_reviewers prr
+				JOIN pull_requests pr ON pr.pull_request_id = prr.pull_request_id
+				JOIN pr_statuses ps ON ps.status = pr.status
+				WHERE prr.reviewer_id = u.user_id AND ps.is_terminal = false
+					AND prr.review_started_at IS NOT NULL
+					AND prr.review_completed_at IS NULL) as in_progress,
+			(SELECT COUNT(*)
+				FROM pr_reviewers prr
+				JOIN pull_requests pr ON pr.pull_request_id = prr.pull_request_id
+				WHERE prr.reviewer_id = u.user_id AND pr.author_id = $2 AND pr.created_at >= $3) as recent_pairings
+		FROM users u
+		WHERE u.is_active = true AND ` + reviewerEligible + ` AND NOT (u.user_id = ANY($4)) AND (u.team_name = $1 OR EXISTS (
+			SELECT 1 FROM team_members tm
+			WHERE tm.user_id = u.user_id AND tm.team_name = $1 AND NOT tm.is_primary))
+		ORDER BY u.user_id
+	`
+
+	candidates := make([]models.ReviewerCandidate, 0)
+	err = r.storage.Select(&candidates, query, teamName, authorIDInt, since, pq.Array(exclude))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return candidates, nil
+}
+
+// GetReviewerPairs counts how often any two of the given users reviewed a PR
+// created since the given time together.
+func (r *PullRequestRepo) GetReviewerPairs(userIDs []string, since time.Time) ([]models.ReviewerPair, error) {
+	const op = "repo.pullRequest.GetReviewerPairs"
+
+	ids := make([]int, 0, len(userIDs))
+	for _, id := range userIDs {
+		idInt, err := extractUserID(id)
+		if err != nil {
+			continue
+		}
+		ids = append(ids, idInt)
+	}
+
+	query := `
+		SELECT
+			'u' || a.reviewer_id AS first_id,
+			'u' || b.reviewer_id AS second_id,
+			COUNT(*) AS co_reviews
+		FROM pr_reviewers a
+		JOIN pr_reviewers b ON b.pull_request_id = a.pull_request_id AND b.reviewer_id > a.reviewer_id
+		JOIN pull_requests pr ON pr.pull_request_id = a.pull_request_id
+		WHERE a.reviewer_id = ANY($1) AND b.reviewer_id = ANY($1) AND pr.created_at >= $2
+		GROUP BY a.reviewer_id, b.reviewer_id
+	`
+
+	pairs := make([]models.ReviewerPair, 0)
+	if err := r.storage.Select(&pairs, query, pq.Array(ids), since); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return pairs, nil
+}
+
+func nonNilTags(tags []string) []string {
+	if tags == nil {
+		return []string{}
+	}
+	return tags
+}
+
+func extractUserID(userIDStr string) (int, error) {
+	userID, err := models.ParseUserID(userIDStr)
+	if err != nil {
+		return 0, apperrors.ErrAuthorRequired
+	}
+	return userID.Int(), nil
+}
+
+// GetAutoMergeReady returns open PRs with auto-merge enabled that have enough
+// approvals and green or untracked CI, oldest first.
+func (r *PullRequestRepo) GetAutoMergeReady() ([]string, error) {
+	const op = "repo.pullRequest.GetAutoMergeReady"
+
+	query := `
+		SELECT pr.pull_request_id
+		FROM pull_requests pr
+		JOIN pr_statuses ps ON ps.status = pr.status
+		WHERE pr.auto_merge
+			AND ps.is_terminal = false
+			AND pr.ci_status IN ('UNKNOWN', 'SUCCESS')
+			AND (SELECT COUNT(*) FROM pr_approvals pa WHERE pa.pull_request_id = pr.pull_request_id) >= pr.auto_merge_approvals
+		ORDER BY pr.created_at, pr.pull_request_id
+	`
+
+	prIDs := make([]string, 0)
+	if err := r.storage.Select(&prIDs, query); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return prIDs, nil
 }
@@ -0,0 +1,50 @@
+package repo
+
+import (
+	"fmt"
+	"pull-request-assigner/internal/domain/models"
+)
+
+// GetTeamWorkload returns the team's members with their open review load and
+// the open assignments they hold that could still move to someone else.
+func (r *PullRequestRepo) GetTeamWorkload(teamName string) ([]models.MemberWorkload, []models.MovableAssignment, error) {
+	const op = "repo.pullRequest.GetTeamWorkload"
+
+	var members []models.MemberWorkload
+	err := r.storage.Select(&members, `
+		SELECT
+			'u' || u.user_id as user_id,
+			u.is_active AND `+reviewerEligible+` AS is_active,
+			(SELECT COUNT(*)
+				FROM pr_reviewers prr
+				JOIN pull_requests pr ON pr.pull_request_id = prr.pull_request_id
+				JOIN pr_statuses ps ON ps.status = pr.status
+				WHERE prr.reviewer_id = u.user_id AND ps.is_terminal = false
+					AND prr.review_completed_at IS NULL) as open_reviews
+		FROM users u
+		WHERE u.team_name = $1
+		ORDER BY u.user_id`, teamName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var assignments []models.MovableAssignment
+	err = r.storage.Select(&assignments, `
+		SELECT prr.pull_request_id, 'u' || prr.reviewer_id as reviewer_id
+		FROM pr_reviewers prr
+		JOIN users u ON u.user_id = prr.reviewer_id
+		JOIN pull_requests pr ON pr.pull_request_id = prr.pull_request_id
+		JOIN pr_statuses ps ON ps.status = pr.status
+		WHERE u.team_name = $1 AND ps.is_terminal = false
+			AND prr.review_started_at IS NULL
+			AND prr.review_completed_at IS NULL
+			AND NOT EXISTS (
+				SELECT 1 FROM pr_approvals pa
+				WHERE pa.pull_request_id = prr.pull_request_id AND pa.reviewer_id = prr.reviewer_id)
+		ORDER BY pr.created_at, prr.pull_request_id`, teamName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return members, assignments, nil
+}
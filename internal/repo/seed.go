@@ -0,0 +1,143 @@
+package repo
+
+import (
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"pull-request-assigner/internal/domain/models"
+)
+
+type SeedRepo struct {
+	storage *sqlx.DB
+}
+
+func NewSeedRepo(storage *sqlx.DB) *SeedRepo {
+	return &SeedRepo{storage: storage}
+}
+
+// Apply writes the dataset in one transaction. Existing teams and users are
+// refreshed; PRs that already exist are left untouched and counted as skipped,
+// so seeding twice is safe.
+func (r *SeedRepo) Apply(dataset models.SeedDataset) (models.SeedSummary, error) {
+	const op = "repo.seed.Apply"
+
+	var summary models.SeedSummary
+
+	tx, err := r.storage.Beginx()
+	if err != nil {
+		return summary, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	for _, team := range dataset.Teams {
+		if _, err := tx.Exec(`INSERT INTO teams (team_name) VALUES ($1) ON CONFLICT DO NOTHING`, team.Name); err != nil {
+			return summary, fmt.Errorf("%s: failed to add team %s: %w", op, team.Name, err)
+		}
+		summary.Teams++
+
+		for _, member := range team.Members {
+			userID, err := extractUserID(member.UserID)
+			if err != nil {
+				return summary, fmt.Errorf("%s: invalid user id %s: %w", op, member.UserID, err)
+			}
+
+			userQuery := `
+				INSERT INTO users (user_id, username, team_name, is_active)
+				VALUES ($1, $2, $3, $4)
+				ON CONFLICT (user_id) DO UPDATE SET
+					username = EXCLUDED.username,
+					team_name = EXCLUDED.team_name,
+					is_active = EXCLUDED.is_active
+			`
+			if _, err := tx.Exec(userQuery, userID, member.Username, team.Name, member.IsActive); err != nil {
+				return summary, fmt.Errorf("%s: failed to add user %s: %w", op, member.UserID, err)
+			}
+
+			memberQuery := `INSERT INTO team_members (team_name, user_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`
+			if _, err := tx.Exec(memberQuery, team.Name, userID); err != nil {
+				return summary, fmt.Errorf("%s: failed to add member %s: %w", op, member.UserID, err)
+			}
+			summary.Users++
+		}
+	}
+
+	for _, pr := range dataset.PullRequests {
+		inserted, err := insertSeedPR(tx, pr)
+		if err != nil {
+			return summary, fmt.Errorf("%s: %w", op, err)
+		}
+		if !inserted {
+			summary.Skipped++
+			continue
+		}
+		summary.PullRequests++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return summary, fmt.Errorf("%s: failed to commit transaction: %w", op, err)
+	}
+
+	return summary, nil
+}
+
+func insertSeedPR(tx *sqlx.Tx, pr models.SeedPullRequest) (bool, error) {
+	authorID, err := extractUserID(pr.AuthorID)
+	if err != nil {
+		return false, fmt.Errorf("invalid author id %s: %w", pr.AuthorID, err)
+	}
+
+	var mergedBy *int
+	if pr.MergedBy != "" {
+		id, err := extractUserID(pr.MergedBy)
+		if err != nil {
+			return false, fmt.Errorf("invalid merged_by %s: %w", pr.MergedBy, err)
+		}
+		mergedBy = &id
+	}
+
+	prQuery := `
+		INSERT INTO pull_requests (pull_request_id, pull_request_name, author_id, status, ci_status, priority,
+			labels, created_at, merged_at, merged_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (pull_request_id) DO NOTHING
+	`
+
+	result, err := tx.Exec(prQuery, pr.PullRequestId, pr.PullRequestName, authorID, pr.Status, pr.CIStatus,
+		pr.Priority, pq.Array(nonNilTags(pr.Labels)), pr.CreatedAt, pr.MergedAt, mergedBy)
+	if err != nil {
+		return false, fmt.Errorf("failed to add PR %s: %w", pr.PullRequestId, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if rowsAffected == 0 {
+		return false, nil
+	}
+
+	for _, review := range pr.Reviews {
+		reviewerID, err := extractUserID(review.ReviewerID)
+		if err != nil {
+			return false, fmt.Errorf("invalid reviewer id %s: %w", review.ReviewerID, err)
+		}
+
+		reviewerQuery := `
+			INSERT INTO pr_reviewers (pull_request_id, reviewer_id, review_started_at, review_completed_at)
+			VALUES ($1, $2, $3, $4)
+		`
+		if _, err := tx.Exec(reviewerQuery, pr.PullRequestId, reviewerID, review.StartedAt, review.CompletedAt); err != nil {
+			return false, fmt.Errorf("failed to add reviewer %s to %s: %w", review.ReviewerID, pr.PullRequestId, err)
+		}
+
+		historyQuery := `
+			INSERT INTO assignment_history (pull_request_id, reviewer_id, action, created_at)
+			VALUES ($1, $2, $3, $4)
+		`
+		if _, err := tx.Exec(historyQuery, pr.PullRequestId, reviewerID, models.AssignmentActionAuto, review.AssignedAt); err != nil {
+			return false, fmt.Errorf("failed to record assignment of %s: %w", pr.PullRequestId, err)
+		}
+	}
+
+	return true, nil
+}
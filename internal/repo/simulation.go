@@ -0,0 +1,100 @@
+package repo
+
+import (
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"pull-request-assigner/internal/domain/models"
+	"time"
+)
+
+type SimulationRepo struct {
+	storage *sqlx.DB
+}
+
+func NewSimulationRepo(storage *sqlx.DB) *SimulationRepo {
+	return &SimulationRepo{storage: storage}
+}
+
+// GetPRHistory returns the PRs created since the given moment, oldest first.
+func (r *SimulationRepo) GetPRHistory(teamName string, since time.Time) ([]models.SimulationPR, error) {
+	const op = "repo.simulation.GetPRHistory"
+
+	query := `
+		SELECT
+			pr.pull_request_id,
+			'u' || pr.author_id as author_id,
+			u.team_name,
+			pr.created_at,
+			pr.merged_at
+		FROM pull_requests pr
+		JOIN users u ON u.user_id = pr.author_id
+		WHERE ($1 = '' OR u.team_name = $1) AND pr.created_at >= $2
+		ORDER BY pr.created_at, pr.pull_request_id
+	`
+
+	history := make([]models.SimulationPR, 0)
+	err := r.storage.Select(&history, query, teamName, since)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return history, nil
+}
+
+func (r *SimulationRepo) GetActiveMembersByTeam(teamName string) (map[string][]string, error) {
+	const op = "repo.simulation.GetActiveMembersByTeam"
+
+	query := `
+		SELECT u.team_name, 'u' || u.user_id as user_id
+		FROM users u
+		WHERE u.is_active = true AND ` + reviewerEligible + ` AND ($1 = '' OR u.team_name = $1)
+		ORDER BY u.team_name, u.user_id
+	`
+
+	var rows []struct {
+		TeamName string `db:"team_name"`
+		UserID   string `db:"user_id"`
+	}
+	err := r.storage.Select(&rows, query, teamName)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	members := make(map[string][]string)
+	for _, row := range rows {
+		members[row.TeamName] = append(members[row.TeamName], row.UserID)
+	}
+
+	return members, nil
+}
+
+// GetActualAssignmentCounts counts the current reviewers of the PRs created
+// since the given moment.
+func (r *SimulationRepo) GetActualAssignmentCounts(teamName string, since time.Time) (map[string]int, error) {
+	const op = "repo.simulation.GetActualAssignmentCounts"
+
+	query := `
+		SELECT 'u' || prr.reviewer_id as user_id, COUNT(*) as count
+		FROM pr_reviewers prr
+		JOIN users u ON u.user_id = prr.reviewer_id
+		JOIN pull_requests pr ON pr.pull_request_id = prr.pull_request_id
+		WHERE ($1 = '' OR u.team_name = $1) AND pr.created_at >= $2
+		GROUP BY prr.reviewer_id
+	`
+
+	var rows []struct {
+		UserID string `db:"user_id"`
+		Count  int    `db:"count"`
+	}
+	err := r.storage.Select(&rows, query, teamName, since)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	counts := make(map[string]int, len(rows))
+	for _, row := range rows {
+		counts[row.UserID] = row.Count
+	}
+
+	return counts, nil
+}
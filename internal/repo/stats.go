@@ -3,7 +3,9 @@ package repo
 import (
 	"fmt"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"pull-request-assigner/internal/domain/models"
+	"time"
 )
 
 type StatsRepo struct {
@@ -52,10 +54,417 @@ func (r *StatsRepo) GetPRStats() (*models.PRStats, error) {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
+	byStatusQuery := `
+		SELECT ps.status, COUNT(pr.pull_request_id) as count
+		FROM pr_statuses ps
+		LEFT JOIN pull_requests pr ON pr.status = ps.status
+		GROUP BY ps.status
+	`
+
+	var statusCounts []struct {
+		Status string `db:"status"`
+		Count  int    `db:"count"`
+	}
+	err = r.storage.Select(&statusCounts, byStatusQuery)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	byStatus := make(map[string]int, len(statusCounts))
+	for _, sc := range statusCounts {
+		byStatus[sc.Status] = sc.Count
+	}
+
+	byActionQuery := `
+		SELECT action, COUNT(*) as count
+		FROM assignment_history
+		GROUP BY action
+	`
+
+	var actionCounts []struct {
+		Action string `db:"action"`
+		Count  int    `db:"count"`
+	}
+	err = r.storage.Select(&actionCounts, byActionQuery)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	byAction := make(map[string]int, len(actionCounts))
+	for _, ac := range actionCounts {
+		byAction[ac.Action] = ac.Count
+	}
+
+	byReasonQuery := `
+		SELECT reason, COUNT(*) as count
+		FROM assignment_history
+		WHERE action = 'REASSIGN' AND reason IS NOT NULL
+		GROUP BY reason
+	`
+
+	var reasonCounts []struct {
+		Reason string `db:"reason"`
+		Count  int    `db:"count"`
+	}
+	err = r.storage.Select(&reasonCounts, byReasonQuery)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	byReason := make(map[string]int, len(reasonCounts))
+	for _, rc := range reasonCounts {
+		byReason[rc.Reason] = rc.Count
+	}
+
+	byMergerQuery := `
+		SELECT 'u' || merged_by AS user_id, COUNT(*) as count
+		FROM pull_requests
+		WHERE merged_by IS NOT NULL
+		GROUP BY merged_by
+	`
+
+	var mergerCounts []struct {
+		UserID string `db:"user_id"`
+		Count  int    `db:"count"`
+	}
+	err = r.storage.Select(&mergerCounts, byMergerQuery)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	byMerger := make(map[string]int, len(mergerCounts))
+	for _, mc := range mergerCounts {
+		byMerger[mc.UserID] = mc.Count
+	}
+
 	return &models.PRStats{
-		TotalPRs:          prStats.TotalPRs,
-		OpenPRs:           prStats.OpenPRs,
-		MergedPRs:         prStats.MergedPRs,
-		AvgReviewersPerPR: avgReviewers,
+		TotalPRs:              prStats.TotalPRs,
+		OpenPRs:               prStats.OpenPRs,
+		MergedPRs:             prStats.MergedPRs,
+		AvgReviewersPerPR:     avgReviewers,
+		ByStatus:              byStatus,
+		AssignmentsByAction:   byAction,
+		ReassignmentsByReason: byReason,
+		MergesByUser:          byMerger,
 	}, nil
 }
+
+func (r *StatsRepo) GetTeamsPRStats(teamNames []string) ([]models.TeamPRStats, error) {
+	const op = "repo.stats.GetTeamsPRStats"
+
+	query := `
+		SELECT
+			t.team_name,
+			COUNT(pr.pull_request_id) as total_prs,
+			COUNT(pr.pull_request_id) FILTER (WHERE pr.status = 'OPEN') as open_prs,
+			COUNT(pr.pull_request_id) FILTER (WHERE pr.status = 'MERGED') as merged_prs,
+			COALESCE(AVG(COALESCE(rc.reviewers, 0)) FILTER (WHERE pr.pull_request_id IS NOT NULL), 0) as avg_reviewers_per_pr
+		FROM unnest($1::text[]) AS t(team_name)
+		LEFT JOIN users u ON u.team_name = t.team_name
+		LEFT JOIN pull_requests pr ON pr.author_id = u.user_id
+		LEFT JOIN (
+			SELECT pull_request_id, COUNT(*) as reviewers
+			FROM pr_reviewers
+			GROUP BY pull_request_id
+		) rc ON rc.pull_request_id = pr.pull_request_id
+		GROUP BY t.team_name
+		ORDER BY t.team_name
+	`
+
+	stats := make([]models.TeamPRStats, 0, len(teamNames))
+	err := r.storage.Select(&stats, query, pq.Array(teamNames))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return stats, nil
+}
+
+// GetReviewerShares counts assignments per reviewer since the given moment,
+// together with the total for the reviewer's current team and its active
+// member count.
+func (r *StatsRepo) GetReviewerShares(since time.Time) ([]models.ReviewerShare, error) {
+	const op = "repo.stats.GetReviewerShares"
+
+	query := `
+		SELECT
+			u.team_name,
+			'u' || u.user_id AS user_id,
+			COUNT(*) AS assignments,
+			SUM(COUNT(*)) OVER (PARTITION BY u.team_name) AS team_assignments,
+			(SELECT COUNT(*) FROM users m WHERE m.team_name = u.team_name AND m.is_active) AS team_members
+		FROM assignment_history ah
+		JOIN users u ON u.user_id = ah.reviewer_id
+		WHERE ah.created_at >= $1
+		  AND ah.action IN ('AUTO', 'MANUAL', 'REASSIGN')
+		GROUP BY u.team_name, u.user_id
+		ORDER BY u.team_name, assignments DESC
+	`
+
+	shares := make([]models.ReviewerShare, 0)
+	err := r.storage.Select(&shares, query, since)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return shares, nil
+}
+
+// GetCycleTimes returns created-to-merged percentiles of PRs merged since the
+// given moment, overall (empty team name) and per author's current team.
+func (r *StatsRepo) GetCycleTimes(since time.Time) ([]models.CycleTime, error) {
+	const op = "repo.stats.GetCycleTimes"
+
+	query := `
+		SELECT
+			COALESCE(u.team_name, '') AS team_name,
+			COUNT(*) AS merged_prs,
+			COALESCE(percentile_cont(0.5) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM pr.merged_at - pr.created_at)), 0) AS p50,
+			COALESCE(percentile_cont(0.9) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM pr.merged_at - pr.created_at)), 0) AS p90,
+			COALESCE(percentile_cont(0.99) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM pr.merged_at - pr.created_at)), 0) AS p99
+		FROM pull_requests pr
+		JOIN users u ON u.user_id = pr.author_id
+		WHERE pr.merged_at IS NOT NULL AND pr.merged_at >= $1
+		GROUP BY GROUPING SETS ((), (u.team_name))
+		ORDER BY GROUPING(u.team_name) DESC, u.team_name
+	`
+
+	cycleTimes := make([]models.CycleTime, 0)
+	err := r.storage.Select(&cycleTimes, query, since)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return cycleTimes, nil
+}
+
+// taggedPRs yields a row per label and per required skill of every PR
+// created since $1.
+const taggedPRs = `
+	WITH tagged AS (
+		SELECT 'label' AS kind, t.tag, pr.pull_request_id, pr.status, pr.created_at, pr.merged_at
+		FROM pull_requests pr
+		CROSS JOIN LATERAL unnest(pr.labels) AS t(tag)
+		WHERE pr.created_at >= $1
+		UNION ALL
+		SELECT 'skill' AS kind, t.tag, pr.pull_request_id, pr.status, pr.created_at, pr.merged_at
+		FROM pull_requests pr
+		CROSS JOIN LATERAL unnest(pr.required_skills) AS t(tag)
+		WHERE pr.created_at >= $1
+	)
+`
+
+// GetTagStats returns PR volume and merge time percentiles per label and
+// required skill of the PRs created since the given moment.
+func (r *StatsRepo) GetTagStats(since time.Time) ([]models.TagStats, error) {
+	const op = "repo.stats.GetTagStats"
+
+	query := taggedPRs + `
+		SELECT
+			t.kind,
+			t.tag,
+			COUNT(*) AS total_prs,
+			COUNT(*) FILTER (WHERE NOT ps.is_terminal) AS open_prs,
+			COUNT(t.merged_at) AS merged_prs,
+			COALESCE(percentile_cont(0.5) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM t.merged_at - t.created_at)), 0) AS merge_p50,
+			COALESCE(percentile_cont(0.9) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM t.merged_at - t.created_at)), 0) AS merge_p90
+		FROM tagged t
+		JOIN pr_statuses ps ON ps.status = t.status
+		GROUP BY t.kind, t.tag
+		ORDER BY t.kind, total_prs DESC, t.tag
+	`
+
+	stats := make([]models.TagStats, 0)
+	err := r.storage.Select(&stats, query, since)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return stats, nil
+}
+
+// GetTagReviewLoads counts the current reviewers per label and required
+// skill of the PRs created since the given moment.
+func (r *StatsRepo) GetTagReviewLoads(since time.Time) ([]models.TagReviewLoad, error) {
+	const op = "repo.stats.GetTagReviewLoads"
+
+	query := taggedPRs + `
+		SELECT
+			t.kind,
+			t.tag,
+			'u' || prr.reviewer_id AS user_id,
+			COUNT(*) AS reviews
+		FROM tagged t
+		JOIN pr_reviewers prr ON prr.pull_request_id = t.pull_request_id
+		GROUP BY t.kind, t.tag, prr.reviewer_id
+		ORDER BY t.kind, t.tag, reviews DESC, prr.reviewer_id
+	`
+
+	loads := make([]models.TagReviewLoad, 0)
+	err := r.storage.Select(&loads, query, since)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return loads, nil
+}
+
+// SnapshotStats stores today's service-wide and per-team stats in
+// stats_history. Running it again on the same day overwrites the snapshot.
+func (r *StatsRepo) SnapshotStats() (int, error) {
+	const op = "repo.stats.SnapshotStats"
+
+	query := `
+		WITH pr_stats AS (
+			SELECT
+				u.team_name,
+				pr.status,
+				ps.is_terminal,
+				(SELECT COUNT(*) FROM pr_reviewers prr WHERE prr.pull_request_id = pr.pull_request_id) AS reviewers
+			FROM pull_requests pr
+			JOIN pr_statuses ps ON ps.status = pr.status
+			JOIN users u ON u.user_id = pr.author_id
+		),
+		open_reviews AS (
+			SELECT u.team_name, COUNT(*) AS open_reviews
+			FROM pr_reviewers prr
+			JOIN pull_requests pr ON pr.pull_request_id = prr.pull_request_id
+			JOIN pr_statuses ps ON ps.status = pr.status
+			JOIN users u ON u.user_id = prr.reviewer_id
+			WHERE ps.is_terminal = false AND prr.review_completed_at IS NULL
+			GROUP BY u.team_name
+		),
+		snapshot AS (
+			SELECT
+				t.team_name,
+				(SELECT COUNT(*) FROM pr_stats s WHERE s.team_name = t.team_name) AS total_prs,
+				(SELECT COUNT(*) FROM pr_stats s WHERE s.team_name = t.team_name AND s.status = 'OPEN') AS open_prs,
+				(SELECT COUNT(*) FROM pr_stats s WHERE s.team_name = t.team_name AND s.status = 'MERGED') AS merged_prs,
+				(SELECT COALESCE(AVG(s.reviewers), 0) FROM pr_stats s WHERE s.team_name = t.team_name) AS avg_reviewers_per_pr,
+				COALESCE(o.open_reviews, 0) AS open_reviews,
+				(SELECT COUNT(*) FROM users m WHERE m.team_name = t.team_name AND m.is_active) AS active_members
+			FROM teams t
+			LEFT JOIN open_reviews o ON o.team_name = t.team_name
+			WHERE t.archived_at IS NULL
+			UNION ALL
+			SELECT
+				'',
+				(SELECT COUNT(*) FROM pull_requests),
+				(SELECT COUNT(*) FROM pull_requests WHERE status = 'OPEN'),
+				(SELECT COUNT(*) FROM pull_requests WHERE status = 'MERGED'),
+				(SELECT COALESCE(AVG(s.reviewers), 0) FROM pr_stats s),
+				(SELECT COALESCE(SUM(o.open_reviews), 0) FROM open_reviews o),
+				(SELECT COUNT(*) FROM users WHERE is_active)
+		)
+		INSERT INTO stats_history (snapshot_date, team_name, total_prs, open_prs, merged_prs,
+			avg_reviewers_per_pr, open_reviews, active_members)
+		SELECT CURRENT_DATE, team_name, total_prs, open_prs, merged_prs,
+			avg_reviewers_per_pr, open_reviews, active_members
+		FROM snapshot
+		ON CONFLICT (team_name, snapshot_date) DO UPDATE SET
+			total_prs = EXCLUDED.total_prs,
+			open_prs = EXCLUDED.open_prs,
+			merged_prs = EXCLUDED.merged_prs,
+			avg_reviewers_per_pr = EXCLUDED.avg_reviewers_per_pr,
+			open_reviews = EXCLUDED.open_reviews,
+			active_members = EXCLUDED.active_members,
+			created_at = NOW()
+	`
+
+	result, err := r.storage.Exec(query)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(rows), nil
+}
+
+// GetStatsHistory returns the snapshots of the team, or the service-wide
+// ones when teamName is empty, taken on or after since, oldest first.
+func (r *StatsRepo) GetStatsHistory(teamName string, since time.Time) ([]models.StatsSnapshot, error) {
+	const op = "repo.stats.GetStatsHistory"
+
+	query := `
+		SELECT snapshot_date, team_name, total_prs, open_prs, merged_prs,
+			avg_reviewers_per_pr, open_reviews, active_members
+		FROM stats_history
+		WHERE team_name = $1 AND snapshot_date >= $2::date
+		ORDER BY snapshot_date
+	`
+
+	snapshots := make([]models.StatsSnapshot, 0)
+	err := r.storage.Select(&snapshots, query, teamName, since)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return snapshots, nil
+}
+
+// GetMemberCapacity returns the open and completed review counts of every
+// member of the team, or of all non-archived teams when teamName is empty.
+// CompletedRecent counts reviews completed since the given moment.
+func (r *StatsRepo) GetMemberCapacity(teamName string, since time.Time) ([]models.MemberCapacity, error) {
+	const op = "repo.stats.GetMemberCapacity"
+
+	query := `
+		SELECT
+			u.team_name,
+			'u' || u.user_id AS user_id,
+			u.username,
+			u.is_active,
+			COUNT(*) FILTER (WHERE ps.is_terminal = false AND prr.review_completed_at IS NULL) AS open_reviews,
+			COUNT(prr.review_completed_at) AS completed_reviews,
+			COUNT(*) FILTER (WHERE prr.review_completed_at >= $2) AS completed_recent
+		FROM users u
+		JOIN teams t ON t.team_name = u.team_name
+		LEFT JOIN pr_reviewers prr ON prr.reviewer_id = u.user_id
+		LEFT JOIN pull_requests pr ON pr.pull_request_id = prr.pull_request_id
+		LEFT JOIN pr_statuses ps ON ps.status = pr.status
+		WHERE t.archived_at IS NULL AND ($1 = '' OR u.team_name = $1)
+		GROUP BY u.team_name, u.user_id, u.username, u.is_active
+		ORDER BY u.team_name, u.user_id
+	`
+
+	members := make([]models.MemberCapacity, 0)
+	err := r.storage.Select(&members, query, teamName, since)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return members, nil
+}
+
+// GetReviewerPairs counts how often two current members of the team reviewed
+// a PR created since the given time together. Pairs that never did are left
+// out.
+func (r *StatsRepo) GetReviewerPairs(teamName string, since time.Time) ([]models.ReviewerPair, error) {
+	const op = "repo.stats.GetReviewerPairs"
+
+	query := `
+		SELECT
+			'u' || a.reviewer_id AS first_id,
+			'u' || b.reviewer_id AS second_id,
+			COUNT(*) AS co_reviews
+		FROM pr_reviewers a
+		JOIN pr_reviewers b ON b.pull_request_id = a.pull_request_id AND b.reviewer_id > a.reviewer_id
+		JOIN pull_requests pr ON pr.pull_request_id = a.pull_request_id
+		JOIN users ua ON ua.user_id = a.reviewer_id
+		JOIN users ub ON ub.user_id = b.reviewer_id
+		WHERE ua.team_name = $1 AND ub.team_name = $1 AND pr.created_at >= $2
+		GROUP BY a.reviewer_id, b.reviewer_id
+		ORDER BY co_reviews DESC, a.reviewer_id, b.reviewer_id
+	`
+
+	pairs := make([]models.ReviewerPair, 0)
+	if err := r.storage.Select(&pairs, query, teamName, since); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return pairs, nil
+}
@@ -1,11 +1,18 @@
 package repo
 
 import (
+	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"pull-request-assigner/internal/apperrors"
 	"pull-request-assigner/internal/domain/models"
+	"reflect"
+	"slices"
 	"strconv"
+	"time"
 )
 
 type TeamRepo struct {
@@ -16,12 +23,21 @@ func NewTeamRepo(storage *sqlx.DB) *TeamRepo {
 	return &TeamRepo{storage: storage}
 }
 
-func (r *TeamRepo) CreateTeam(teamName string) error {
-	const op = "repo.team.CreateTeam"
+// CreateTeamWithMembers creates the team and upserts its members in one
+// transaction. The team name's primary key decides concurrent creations:
+// the loser gets ErrTeamExists and writes nothing.
+func (r *TeamRepo) CreateTeamWithMembers(team models.Team) error {
+	const op = "repo.team.CreateTeamWithMembers"
 
-	query := `INSERT INTO teams (team_name) VALUES ($1)`
+	tx, err := r.storage.Beginx()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO teams (team_name) VALUES ($1) ON CONFLICT (team_name) DO NOTHING`
 
-	_, err := r.storage.Exec(query, teamName)
+	result, err := tx.Exec(query, team.TeamName)
 	if err != nil {
 		if isDuplicateKeyError(err) {
 			return fmt.Errorf("%s: %w", op, apperrors.ErrTeamExists)
@@ -29,77 +45,123 @@ func (r *TeamRepo) CreateTeam(teamName string) error {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("%s: %w", op, apperrors.ErrTeamExists)
+	}
+
+	if err := upsertTeamMembers(tx, team.TeamName, team.Members); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: failed to commit transaction: %w", op, err)
+	}
+
 	return nil
 }
 
 func (r *TeamRepo) TeamExists(teamName string) (bool, error) {
 	const op = "repo.team.TeamExists"
 
-	query := `SELECT COUNT(*) FROM teams WHERE team_name = $1`
+	query := `SELECT EXISTS(SELECT 1 FROM teams WHERE team_name = $1)`
 
-	var count int
-	err := r.storage.Get(&count, query, teamName)
+	var exists bool
+	err := r.storage.Get(&exists, query, teamName)
 	if err != nil {
 		return false, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return count > 0, nil
+	return exists, nil
 }
 
-func (r *TeamRepo) AddTeamMembers(teamName string, members []models.User) error {
-	const op = "repo.team.AddTeamMembers"
-
-	tx, err := r.storage.Beginx()
-	if err != nil {
-		return fmt.Errorf("%s: %w", op, err)
+// upsertTeamMembers moves the members into the team, creating unknown users.
+// Rows are written in user ID order so that concurrent calls sharing
+// members lock them in the same order instead of deadlocking.
+func upsertTeamMembers(tx *sqlx.Tx, teamName string, members []models.User) error {
+	userIDs := make([]int, 0, len(members))
+	byID := make(map[int]models.User, len(members))
+	for _, member := range members {
+		userID, err := models.ParseUserID(member.UserID)
+		if err != nil {
+			return err
+		}
+		if _, ok := byID[userID.Int()]; !ok {
+			userIDs = append(userIDs, userID.Int())
+		}
+		byID[userID.Int()] = member
 	}
-	defer tx.Rollback()
+	slices.Sort(userIDs)
 
 	userQuery := `
-		INSERT INTO users (user_id, username, team_name, is_active) 
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO users (user_id, username, team_name, is_active, display_name, email, avatar_url, locale)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), NULLIF($6, ''), NULLIF($7, ''), NULLIF($8, ''))
 		ON CONFLICT (user_id) 
 		DO UPDATE SET 
 			username = EXCLUDED.username,
 			team_name = EXCLUDED.team_name,
-			is_active = EXCLUDED.is_active
+			is_active = EXCLUDED.is_active,
+			display_name = COALESCE(EXCLUDED.display_name, users.display_name),
+			email = COALESCE(EXCLUDED.email, users.email),
+			avatar_url = COALESCE(EXCLUDED.avatar_url, users.avatar_url),
+			locale = COALESCE(EXCLUDED.locale, users.locale)
 	`
 
-	for _, member := range members {
-		var userIDInt int
-		_, err := fmt.Sscanf(member.UserID, "u%d", &userIDInt)
+	for _, userID := range userIDs {
+		member := byID[userID]
+		_, err := tx.Exec(userQuery, userID, member.Username, teamName, member.IsActive,
+			member.DisplayName, member.Email, member.AvatarURL, member.Locale)
 		if err != nil {
-			return fmt.Errorf("%s: %w", op, apperrors.ErrInvalidUserID)
-		}
-
-		_, err = tx.Exec(userQuery, userIDInt, member.Username, teamName, member.IsActive)
-		if err != nil {
-			return fmt.Errorf("%s: failed to upsert user %s: %w", op, member.UserID, err)
+			return fmt.Errorf("failed to upsert user %s: %w", member.UserID, err)
 		}
 	}
 
-	memberQuery := `INSERT INTO team_members (team_name, user_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`
+	// A user moving teams must leave their old primary team_members row
+	// behind, or team_members drifts from users.team_name. Secondary
+	// memberships stay; one in the new team becomes the primary one.
+	leaveQuery := `DELETE FROM team_members WHERE user_id = $1 AND team_name <> $2 AND is_primary`
+	memberQuery := `
+		INSERT INTO team_members (team_name, user_id, is_primary) VALUES ($1, $2, TRUE)
+		ON CONFLICT (team_name, user_id) DO UPDATE SET is_primary = TRUE
+	`
 
-	for _, member := range members {
-		var userIDInt int
-		_, err := fmt.Sscanf(member.UserID, "u%d", &userIDInt)
-		if err != nil {
-			return fmt.Errorf("%s: %w", op, apperrors.ErrInvalidUserID)
+	for _, userID := range userIDs {
+		member := byID[userID]
+		if _, err := tx.Exec(leaveQuery, userID, teamName); err != nil {
+			return fmt.Errorf("failed to remove previous membership of %s: %w", member.UserID, err)
 		}
 
-		_, err = tx.Exec(memberQuery, teamName, userIDInt)
-		if err != nil {
-			return fmt.Errorf("%s: failed to add team member %s: %w", op, member.UserID, err)
+		if _, err := tx.Exec(memberQuery, teamName, userID); err != nil {
+			return fmt.Errorf("failed to add team member %s: %w", member.UserID, err)
 		}
 	}
 
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("%s: failed to commit transaction: %w", op, err)
-	}
-
 	return nil
 }
 
+// teamMembersQuery lists the members of a team through team_members. It is
+// one of the hotQueries checked by /admin/dbcheck.
+const teamMembersQuery = `
+	SELECT 
+		u.user_id,
+		u.username,
+		u.team_name,
+		u.is_active,
+		NOT (` + reviewerEligible + `) AS author_only,
+		CASE WHEN u.author_only AND u.author_only_until > NOW() THEN u.author_only_until END AS author_only_until,
+		COALESCE(u.display_name, '') AS display_name,
+		COALESCE(u.email, '') AS email,
+		COALESCE(u.avatar_url, '') AS avatar_url,
+		COALESCE(u.locale, '') AS locale
+	FROM users u
+	JOIN team_members tm ON u.user_id = tm.user_id
+	WHERE tm.team_name = $1
+`
+
 func (r *TeamRepo) GetTeamWithMembers(teamName string) (*models.Team, error) {
 	const op = "repo.team.GetTeamWithMembers"
 
@@ -111,26 +173,15 @@ func (r *TeamRepo) GetTeamWithMembers(teamName string) (*models.Team, error) {
 		return nil, fmt.Errorf("%s: %w", op, apperrors.ErrTeamNotFound)
 	}
 
-	query := `
-		SELECT 
-			u.user_id,
-			u.username,
-			u.team_name,
-			u.is_active
-		FROM users u
-		JOIN team_members tm ON u.user_id = tm.user_id
-		WHERE tm.team_name = $1
-	`
-
 	var members []models.User
-	err = r.storage.Select(&members, query, teamName)
+	err = r.storage.Select(&members, teamMembersQuery, teamName)
 	if err != nil {
 		return nil, fmt.Errorf("%s: failed to get team members: %w", op, err)
 	}
 
 	for i := range members {
 		id, _ := strconv.Atoi(members[i].UserID)
-		members[i].UserID = fmt.Sprintf("u%d", id)
+		members[i].UserID = models.UserID(id).String()
 	}
 
 	team := &models.Team{
@@ -163,9 +214,328 @@ func (r *TeamRepo) DeactivateTeamUsers(teamName string) (int, error) {
 	return int(rowsAffected), nil
 }
 
-func isDuplicateKeyError(err error) bool {
-	if err.Error() == "pq: duplicate key value violates unique constraint" {
-		return true
+func (r *TeamRepo) ArchiveTeam(teamName string) (int, error) {
+	const op = "repo.team.ArchiveTeam"
+
+	tx, err := r.storage.Beginx()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	archiveQuery := `UPDATE teams SET archived_at = NOW() WHERE team_name = $1 AND archived_at IS NULL`
+	_, err = tx.Exec(archiveQuery, teamName)
+	if err != nil {
+		return 0, fmt.Errorf("%s: failed to archive team: %w", op, err)
+	}
+
+	deactivateQuery := `UPDATE users SET is_active = false WHERE team_name = $1 AND is_active = true`
+	result, err := tx.Exec(deactivateQuery, teamName)
+	if err != nil {
+		return 0, fmt.Errorf("%s: failed to deactivate members: %w", op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: failed to commit transaction: %w", op, err)
+	}
+
+	return int(rowsAffected), nil
+}
+
+// AddSecondaryMember makes the user a member of the team besides their
+// primary one and reports whether anything changed.
+func (r *TeamRepo) AddSecondaryMember(teamName string, userID int) (bool, error) {
+	const op = "repo.team.AddSecondaryMember"
+
+	tx, err := r.storage.Beginx()
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	var primaryTeam string
+	err = tx.Get(&primaryTeam, `SELECT team_name FROM users WHERE user_id = $1 FOR UPDATE`, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, fmt.Errorf("%s: %w", op, apperrors.ErrUserNotFound)
+		}
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if primaryTeam == teamName {
+		return false, fmt.Errorf("%s: %w", op, apperrors.ErrPrimaryTeam)
+	}
+
+	// A primary row left in the team by drift becomes the secondary one.
+	query := `
+		INSERT INTO team_members (team_name, user_id, is_primary) VALUES ($1, $2, FALSE)
+		ON CONFLICT (team_name, user_id) DO UPDATE SET is_primary = FALSE WHERE team_members.is_primary
+	`
+
+	result, err := tx.Exec(query, teamName, userID)
+	if err != nil {
+		if isForeignKeyError(err) {
+			return false, fmt.Errorf("%s: %w", op, apperrors.ErrTeamNotFound)
+		}
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("%s: failed to commit transaction: %w", op, err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// RemoveSecondaryMember drops the user's secondary membership of the team and
+// reports whether there was one. The primary team can only change through
+// /team/add.
+func (r *TeamRepo) RemoveSecondaryMember(teamName string, userID int) (bool, error) {
+	const op = "repo.team.RemoveSecondaryMember"
+
+	var primaryTeam string
+	err := r.storage.Get(&primaryTeam, `SELECT team_name FROM users WHERE user_id = $1`, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, fmt.Errorf("%s: %w", op, apperrors.ErrUserNotFound)
+		}
+		return false, fmt.Errorf("%s: %w", op, err)
 	}
-	return false
+
+	if primaryTeam == teamName {
+		return false, fmt.Errorf("%s: %w", op, apperrors.ErrPrimaryTeam)
+	}
+
+	query := `DELETE FROM team_members WHERE team_name = $1 AND user_id = $2 AND NOT is_primary`
+
+	result, err := r.storage.Exec(query, teamName, userID)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// GetTeamPolicy returns the team policy resolved against the org defaults.
+func (r *TeamRepo) GetTeamPolicy(teamName string) (*models.TeamPolicy, error) {
+	const op = "repo.team.GetTeamPolicy"
+
+	org, team, err := getPolicyLayers(r.storage, teamName)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	policy := models.ResolvePolicy(*org, *team).Policy
+	return &policy, nil
+}
+
+func (r *TeamRepo) GetPolicyLayers(teamName string) (*models.OrgPolicy, *models.TeamPolicyOverrides, error) {
+	const op = "repo.team.GetPolicyLayers"
+
+	org, team, err := getPolicyLayers(r.storage, teamName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return org, team, nil
+}
+
+// GetLabelReviewTeams returns teams whose review_labels overlap the given labels.
+func (r *TeamRepo) GetLabelReviewTeams(labels []string) ([]string, error) {
+	const op = "repo.team.GetLabelReviewTeams"
+
+	query := `
+		SELECT team_name
+		FROM teams
+		WHERE review_labels && $1::text[]
+		  AND archived_at IS NULL
+		ORDER BY team_name
+	`
+
+	teams := make([]string, 0)
+	if err := r.storage.Select(&teams, query, pq.Array(nonNilTags(labels))); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return teams, nil
+}
+
+// UpdateTeamPolicy stores the team overrides and, when there are changes,
+// appends the resolved policy to policy_versions in the same transaction.
+// With expected set, the write only happens while the stored overrides still
+// equal it.
+func (r *TeamRepo) UpdateTeamPolicy(expected *models.TeamPolicyOverrides, overrides models.TeamPolicyOverrides, policy models.TeamPolicy, changes []string, actorID string) error {
+	const op = "repo.team.UpdateTeamPolicy"
+
+	tx, err := r.storage.Beginx()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if expected != nil {
+		var locked string
+		if err := tx.Get(&locked, `SELECT team_name FROM teams WHERE team_name = $1 FOR UPDATE`, overrides.TeamName); err != nil {
+			if err == sql.ErrNoRows {
+				return fmt.Errorf("%s: %w", op, apperrors.ErrTeamNotFound)
+			}
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		_, current, err := getPolicyLayers(tx, overrides.TeamName)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if !reflect.DeepEqual(*current, *expected) {
+			return fmt.Errorf("%s: %w", op, apperrors.ErrPreconditionFailed)
+		}
+	}
+
+	query := `
+		UPDATE teams
+		SET hold_until_ci_green = $1,
+			min_reviewers = $2,
+			default_priority = $3,
+			default_labels = $4,
+			default_required_skills = $5,
+			review_labels = $6,
+			exclude_co_authors = $7,
+			exclude_pairing_session = $8,
+			diversify_reviewer_pairs = $9,
+			reviewers_per_pr = $10
+		WHERE team_name = $11
+	`
+
+	result, err := tx.Exec(query,
+		overrides.HoldUntilCIGreen,
+		overrides.MinReviewers,
+		overrides.DefaultPriority,
+		tagsOverride(overrides.DefaultLabels),
+		tagsOverride(overrides.DefaultRequiredSkills),
+		pq.Array(nonNilTags(overrides.ReviewLabels)),
+		overrides.ExcludeCoAuthors,
+		overrides.ExcludePairingSession,
+		overrides.DiversifyPairs,
+		overrides.ReviewersPerPR,
+		overrides.TeamName,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("%s: %w", op, apperrors.ErrTeamNotFound)
+	}
+
+	if len(changes) > 0 {
+		snapshot, err := json.Marshal(policy)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		var actor sql.NullInt64
+		if id, err := extractUserID(actorID); err == nil {
+			actor = sql.NullInt64{Int64: int64(id), Valid: true}
+		}
+
+		versionQuery := `
+			INSERT INTO policy_versions (team_name, version, policy, changes, actor_id)
+			SELECT $1, COALESCE(MAX(version), 0) + 1, $2, $3, $4
+			FROM policy_versions
+			WHERE team_name = $1
+		`
+
+		if _, err := tx.Exec(versionQuery, policy.TeamName, snapshot, pq.Array(changes), actor); err != nil {
+			return fmt.Errorf("%s: failed to record policy version: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: failed to commit transaction: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *TeamRepo) GetPolicyVersions(teamName string) ([]models.PolicyVersion, error) {
+	const op = "repo.team.GetPolicyVersions"
+
+	query := `
+		SELECT team_name, version, policy, changes, actor_id, created_at
+		FROM policy_versions
+		WHERE team_name = $1
+		ORDER BY version
+	`
+
+	var rows []struct {
+		TeamName  string         `db:"team_name"`
+		Version   int            `db:"version"`
+		Policy    []byte         `db:"policy"`
+		Changes   pq.StringArray `db:"changes"`
+		ActorID   sql.NullInt64  `db:"actor_id"`
+		CreatedAt time.Time      `db:"created_at"`
+	}
+
+	if err := r.storage.Select(&rows, query, teamName); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	versions := make([]models.PolicyVersion, 0, len(rows))
+	for _, row := range rows {
+		version := models.PolicyVersion{
+			TeamName:  row.TeamName,
+			Version:   row.Version,
+			Changes:   []string(row.Changes),
+			CreatedAt: row.CreatedAt,
+		}
+
+		if err := json.Unmarshal(row.Policy, &version.Policy); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if row.ActorID.Valid {
+			actorID := models.UserID(row.ActorID.Int64).String()
+			version.ActorID = &actorID
+		}
+
+		versions = append(versions, version)
+	}
+
+	return versions, nil
+}
+
+const (
+	uniqueViolation     = "23505"
+	foreignKeyViolation = "23503"
+)
+
+func isDuplicateKeyError(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == uniqueViolation
+}
+
+func isForeignKeyError(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == foreignKeyViolation
 }
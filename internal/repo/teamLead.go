@@ -0,0 +1,98 @@
+package repo
+
+import (
+	"fmt"
+	"github.com/lib/pq"
+	"pull-request-assigner/internal/apperrors"
+	"pull-request-assigner/internal/domain/models"
+)
+
+// AddTeamLead makes the user a lead of the team. The user does not have to
+// be a member: one lead may look after several teams.
+func (r *TeamRepo) AddTeamLead(teamName string, userID int) (bool, error) {
+	const op = "repo.team.AddTeamLead"
+
+	exists, err := r.TeamExists(teamName)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+	if !exists {
+		return false, fmt.Errorf("%s: %w", op, apperrors.ErrTeamNotFound)
+	}
+
+	query := `INSERT INTO team_leads (team_name, user_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`
+
+	result, err := r.storage.Exec(query, teamName, userID)
+	if err != nil {
+		if isForeignKeyError(err) {
+			return false, fmt.Errorf("%s: %w", op, apperrors.ErrUserNotFound)
+		}
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	added, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return added > 0, nil
+}
+
+func (r *TeamRepo) RemoveTeamLead(teamName string, userID int) (bool, error) {
+	const op = "repo.team.RemoveTeamLead"
+
+	result, err := r.storage.Exec(`DELETE FROM team_leads WHERE team_name = $1 AND user_id = $2`, teamName, userID)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	removed, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return removed > 0, nil
+}
+
+// GetTeamLeads returns the leads of every team, ordered by team.
+func (r *TeamRepo) GetTeamLeads() ([]models.TeamLead, error) {
+	const op = "repo.team.GetTeamLeads"
+
+	query := `
+		SELECT tl.team_name, 'u' || tl.user_id AS user_id, u.username, tl.created_at
+		FROM team_leads tl
+		JOIN users u ON u.user_id = tl.user_id
+		ORDER BY tl.team_name, tl.user_id`
+
+	leads := make([]models.TeamLead, 0)
+	if err := r.storage.Select(&leads, query); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return leads, nil
+}
+
+func (r *TeamRepo) GetLedTeams(userID int) ([]string, error) {
+	const op = "repo.team.GetLedTeams"
+
+	teams := make([]string, 0)
+	err := r.storage.Select(&teams, `SELECT team_name FROM team_leads WHERE user_id = $1 ORDER BY team_name`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return teams, nil
+}
+
+// GetTeamMemberIDs returns the users whose team is one of teamNames.
+func (r *TeamRepo) GetTeamMemberIDs(teamNames []string) ([]string, error) {
+	const op = "repo.team.GetTeamMemberIDs"
+
+	userIDs := make([]string, 0)
+	err := r.storage.Select(&userIDs, `SELECT 'u' || user_id FROM users WHERE team_name = ANY($1)`, pq.Array(teamNames))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return userIDs, nil
+}
@@ -0,0 +1,280 @@
+package repo
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"pull-request-assigner/internal/apperrors"
+	"pull-request-assigner/internal/domain/models"
+	"time"
+)
+
+type TokenRepo struct {
+	storage *sqlx.DB
+}
+
+func NewTokenRepo(storage *sqlx.DB) *TokenRepo {
+	return &TokenRepo{storage: storage}
+}
+
+type tokenRow struct {
+	TokenID    int64          `db:"token_id"`
+	Name       string         `db:"name"`
+	UserID     sql.NullString `db:"user_id"`
+	Scopes     pq.StringArray `db:"scopes"`
+	Quota      sql.NullInt64  `db:"hourly_quota"`
+	ExpiresAt  sql.NullTime   `db:"expires_at"`
+	RevokedAt  sql.NullTime   `db:"revoked_at"`
+	LastUsedAt sql.NullTime   `db:"last_used_at"`
+	CreatedAt  time.Time      `db:"created_at"`
+	RotatedAt  sql.NullTime   `db:"rotated_at"`
+}
+
+const tokenColumns = `token_id, name, 'u' || user_id AS user_id, scopes, hourly_quota, expires_at, revoked_at, last_used_at, created_at, rotated_at`
+
+func (r *TokenRepo) CreateToken(name string, userID int, tokenHash string, scopes []string, hourlyQuota *int, expiresAt *time.Time) (*models.APIToken, error) {
+	const op = "repo.token.CreateToken"
+
+	query := `
+		INSERT INTO api_tokens (name, user_id, token_hash, scopes, hourly_quota, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING ` + tokenColumns
+
+	var row tokenRow
+	if err := r.storage.Get(&row, query, name, userID, tokenHash, pq.Array(scopes), hourlyQuota, expiresAt); err != nil {
+		if isForeignKeyError(err) {
+			return nil, fmt.Errorf("%s: %w", op, apperrors.ErrUserNotFound)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return row.toModel(), nil
+}
+
+// CreateBootstrapToken creates an admin token for the user unless an active
+// admin token already exists, in which case it returns ErrBootstrapClosed.
+// The table is locked so that concurrent bootstraps cannot both succeed.
+func (r *TokenRepo) CreateBootstrapToken(name string, userID int, tokenHash string) (*models.APIToken, error) {
+	const op = "repo.token.CreateBootstrapToken"
+
+	tx, err := r.storage.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`LOCK TABLE api_tokens IN EXCLUSIVE MODE`); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var exists bool
+	err = tx.Get(&exists, `
+		SELECT EXISTS (
+			SELECT 1 FROM api_tokens
+			WHERE $1 = ANY(scopes)
+			  AND revoked_at IS NULL
+			  AND (expires_at IS NULL OR expires_at > NOW())
+		)`, models.TokenScopeAdmin)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if exists {
+		return nil, fmt.Errorf("%s: %w", op, apperrors.ErrBootstrapClosed)
+	}
+
+	query := `
+		INSERT INTO api_tokens (name, user_id, token_hash, scopes)
+		VALUES ($1, $2, $3, $4)
+		RETURNING ` + tokenColumns
+
+	var row tokenRow
+	if err := tx.Get(&row, query, name, userID, tokenHash, pq.Array([]string{models.TokenScopeAdmin})); err != nil {
+		if isForeignKeyError(err) {
+			return nil, fmt.Errorf("%s: %w", op, apperrors.ErrUserNotFound)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: failed to commit transaction: %w", op, err)
+	}
+
+	return row.toModel(), nil
+}
+
+func (r *TokenRepo) ListTokens() ([]models.APIToken, error) {
+	const op = "repo.token.ListTokens"
+
+	query := `SELECT ` + tokenColumns + ` FROM api_tokens ORDER BY token_id`
+
+	var rows []tokenRow
+	if err := r.storage.Select(&rows, query); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	tokens := make([]models.APIToken, 0, len(rows))
+	for _, row := range rows {
+		tokens = append(tokens, *row.toModel())
+	}
+
+	return tokens, nil
+}
+
+// RotateToken replaces the hash of a token that is not revoked, keeping its
+// name, scopes and expiry.
+func (r *TokenRepo) RotateToken(tokenID int64, tokenHash string) (*models.APIToken, error) {
+	const op = "repo.token.RotateToken"
+
+	tx, err := r.storage.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	var revokedAt sql.NullTime
+	err = tx.Get(&revokedAt, `SELECT revoked_at FROM api_tokens WHERE token_id = $1 FOR UPDATE`, tokenID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%s: %w", op, apperrors.ErrTokenNotFound)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if revokedAt.Valid {
+		return nil, fmt.Errorf("%s: %w", op, apperrors.ErrTokenRevoked)
+	}
+
+	query := `
+		UPDATE api_tokens
+		SET token_hash = $2, rotated_at = NOW()
+		WHERE token_id = $1
+		RETURNING ` + tokenColumns
+
+	var row tokenRow
+	if err := tx.Get(&row, query, tokenID, tokenHash); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: failed to commit transaction: %w", op, err)
+	}
+
+	return row.toModel(), nil
+}
+
+// RevokeToken marks the token as revoked. Revoking an already revoked token
+// keeps the original revocation time.
+func (r *TokenRepo) RevokeToken(tokenID int64) (*models.APIToken, error) {
+	const op = "repo.token.RevokeToken"
+
+	query := `
+		UPDATE api_tokens
+		SET revoked_at = COALESCE(revoked_at, NOW())
+		WHERE token_id = $1
+		RETURNING ` + tokenColumns
+
+	var row tokenRow
+	if err := r.storage.Get(&row, query, tokenID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%s: %w", op, apperrors.ErrTokenNotFound)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return row.toModel(), nil
+}
+
+// SetTokenQuota changes the token's hourly quota; nil makes it follow the
+// default quota again.
+func (r *TokenRepo) SetTokenQuota(tokenID int64, hourlyQuota *int) (*models.APIToken, error) {
+	const op = "repo.token.SetTokenQuota"
+
+	query := `
+		UPDATE api_tokens
+		SET hourly_quota = $2
+		WHERE token_id = $1
+		RETURNING ` + tokenColumns
+
+	var row tokenRow
+	if err := r.storage.Get(&row, query, tokenID, hourlyQuota); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%s: %w", op, apperrors.ErrTokenNotFound)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return row.toModel(), nil
+}
+
+// RevokeUserTokens revokes every token of the user that is not revoked yet
+// and returns their IDs.
+func (r *TokenRepo) RevokeUserTokens(userID int) ([]int64, error) {
+	const op = "repo.token.RevokeUserTokens"
+
+	query := `
+		UPDATE api_tokens
+		SET revoked_at = NOW()
+		WHERE user_id = $1 AND revoked_at IS NULL
+		RETURNING token_id`
+
+	revoked := make([]int64, 0)
+	if err := r.storage.Select(&revoked, query, userID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return revoked, nil
+}
+
+// TouchToken looks a token up by hash and records its use. ownerActive is
+// false when the token's user is deactivated; tokens without a user count
+// as active.
+func (r *TokenRepo) TouchToken(tokenHash string) (token *models.APIToken, ownerActive bool, err error) {
+	const op = "repo.token.TouchToken"
+
+	query := `
+		UPDATE api_tokens
+		SET last_used_at = NOW()
+		WHERE token_hash = $1
+		RETURNING ` + tokenColumns + `,
+			COALESCE((SELECT is_active FROM users WHERE users.user_id = api_tokens.user_id), TRUE) AS owner_active`
+
+	var row struct {
+		tokenRow
+		OwnerActive bool `db:"owner_active"`
+	}
+	if err := r.storage.Get(&row, query, tokenHash); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, false, fmt.Errorf("%s: %w", op, apperrors.ErrTokenNotFound)
+		}
+		return nil, false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return row.toModel(), row.OwnerActive, nil
+}
+
+func (row tokenRow) toModel() *models.APIToken {
+	token := &models.APIToken{
+		TokenID:    row.TokenID,
+		Name:       row.Name,
+		UserID:     row.UserID.String,
+		Scopes:     []string(row.Scopes),
+		ExpiresAt:  nullTimePtr(row.ExpiresAt),
+		RevokedAt:  nullTimePtr(row.RevokedAt),
+		LastUsedAt: nullTimePtr(row.LastUsedAt),
+		CreatedAt:  row.CreatedAt,
+		RotatedAt:  nullTimePtr(row.RotatedAt),
+	}
+	if row.Quota.Valid {
+		quota := int(row.Quota.Int64)
+		token.HourlyQuota = &quota
+	}
+	return token
+}
+
+func nullTimePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
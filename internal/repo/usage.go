@@ -0,0 +1,109 @@
+package repo
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"pull-request-assigner/internal/domain/models"
+	"time"
+)
+
+type UsageRepo struct {
+	storage *sqlx.DB
+}
+
+func NewUsageRepo(storage *sqlx.DB) *UsageRepo {
+	return &UsageRepo{storage: storage}
+}
+
+// AddUsage adds the given counters to the stored hourly buckets.
+func (r *UsageRepo) AddUsage(records []models.UsageRecord) error {
+	const op = "repo.usage.AddUsage"
+
+	tx, err := r.storage.Beginx()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO api_usage (client_id, bucket, requests, mutations)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (client_id, bucket) DO UPDATE
+		SET requests = api_usage.requests + EXCLUDED.requests,
+			mutations = api_usage.mutations + EXCLUDED.mutations
+	`
+
+	for _, record := range records {
+		if _, err := tx.Exec(query, record.ClientID, record.Bucket, record.Requests, record.Mutations); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: failed to commit transaction: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *UsageRepo) GetUsage(from time.Time, to time.Time, bucket string) ([]models.UsageRecord, error) {
+	const op = "repo.usage.GetUsage"
+
+	query := `
+		SELECT
+			client_id,
+			date_trunc($1, bucket) as bucket,
+			SUM(requests)::BIGINT as requests,
+			SUM(mutations)::BIGINT as mutations
+		FROM api_usage
+		WHERE bucket >= $2 AND bucket < $3
+		GROUP BY client_id, date_trunc($1, bucket)
+		ORDER BY bucket, client_id
+	`
+
+	records := make([]models.UsageRecord, 0)
+	if err := r.storage.Select(&records, query, bucket, from, to); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return records, nil
+}
+
+// TakeQuota counts a request of the client in the hourly window starting at
+// windowStart. It returns false without counting when the client already made
+// quota requests in the window. The counter is shared by every replica.
+func (r *UsageRepo) TakeQuota(clientID string, windowStart time.Time, quota int) (bool, error) {
+	const op = "repo.usage.TakeQuota"
+
+	query := `
+		INSERT INTO api_quota_counters (client_id, window_start, requests)
+		VALUES ($1, $2, 1)
+		ON CONFLICT (client_id, window_start) DO UPDATE
+		SET requests = api_quota_counters.requests + 1
+		WHERE api_quota_counters.requests < $3
+		RETURNING requests
+	`
+
+	var requests int64
+	if err := r.storage.Get(&requests, query, clientID, windowStart, quota); err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return true, nil
+}
+
+// PruneQuotaCounters drops the counters of windows that started before the
+// given time.
+func (r *UsageRepo) PruneQuotaCounters(before time.Time) error {
+	const op = "repo.usage.PruneQuotaCounters"
+
+	if _, err := r.storage.Exec(`DELETE FROM api_quota_counters WHERE window_start < $1`, before); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
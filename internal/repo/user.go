@@ -4,9 +4,11 @@ import (
 	"database/sql"
 	"fmt"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"pull-request-assigner/internal/apperrors"
 	"pull-request-assigner/internal/domain/models"
 	"strconv"
+	"time"
 )
 
 type UserRepo struct {
@@ -17,12 +19,23 @@ func NewUserRepo(storage *sqlx.DB) *UserRepo {
 	return &UserRepo{storage: storage}
 }
 
+// userColumns selects a models.User; unset profile fields read as empty and
+// an author-only period that ran out reads as not author-only.
+const userColumns = `user_id, username, team_name, is_active, max_daily_assignments,
+	author_only AND (author_only_until IS NULL OR author_only_until > NOW()) AS author_only,
+	CASE WHEN author_only AND author_only_until > NOW() THEN author_only_until END AS author_only_until,
+	COALESCE(display_name, '') AS display_name, COALESCE(email, '') AS email,
+	COALESCE(avatar_url, '') AS avatar_url, COALESCE(locale, '') AS locale`
+
+// reviewerEligible holds for the users u that may be picked as reviewers
+// beyond being active: those not in an author-only period.
+const reviewerEligible = `NOT (u.author_only AND (u.author_only_until IS NULL OR u.author_only_until > NOW()))`
+
 func (r *UserRepo) SetIsActive(isActive bool, userID int) (models.User, error) {
 	const op = "repo.user.SetIsActive"
 
-	query := `UPDATE users SET is_active = $1 WHERE user_id = $2 
-        RETURNING user_id, username, team_name, is_active
-    `
+	query := `UPDATE users SET is_active = $1 WHERE user_id = $2
+        RETURNING ` + userColumns
 
 	var user models.User
 	err := r.storage.Get(&user, query, isActive, userID)
@@ -34,27 +47,70 @@ func (r *UserRepo) SetIsActive(isActive bool, userID int) (models.User, error) {
 	}
 
 	id, _ := strconv.Atoi(user.UserID)
-	user.UserID = fmt.Sprintf("u%d", id)
+	user.UserID = models.UserID(id).String()
+
+	return user, nil
+}
+
+// SetAuthorOnly marks the user author-only until the given time, or until it
+// is cleared when until is nil.
+func (r *UserRepo) SetAuthorOnly(authorOnly bool, until *time.Time, userID int) (models.User, error) {
+	const op = "repo.user.SetAuthorOnly"
+
+	if !authorOnly {
+		until = nil
+	}
+
+	query := `UPDATE users SET author_only = $1, author_only_until = $2 WHERE user_id = $3
+        RETURNING ` + userColumns
+
+	var user models.User
+	err := r.storage.Get(&user, query, authorOnly, until, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return models.User{}, apperrors.ErrUserNotFound
+		}
+		return models.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	id, _ := strconv.Atoi(user.UserID)
+	user.UserID = models.UserID(id).String()
 
 	return user, nil
 }
 
+// reviewsByReviewerQuery lists the PRs a user reviews with the state of each
+// review. It is one of the hotQueries checked by /admin/dbcheck.
+const reviewsByReviewerQuery = `
+    SELECT 
+        pr.pull_request_id,
+        pr.pull_request_name, 
+        pr.author_id,
+        pr.status,
+        CASE
+            WHEN prr.review_completed_at IS NOT NULL THEN 'COMPLETED'
+            WHEN prr.review_started_at IS NOT NULL THEN 'IN_PROGRESS'
+            ELSE 'ASSIGNED'
+        END as review_state,
+        prr.review_started_at,
+        prr.review_completed_at,
+        COALESCE((
+            SELECT h.note
+            FROM assignment_history h
+            WHERE h.pull_request_id = prr.pull_request_id AND h.reviewer_id = prr.reviewer_id
+            ORDER BY h.id DESC
+            LIMIT 1
+        ), '') as handoff_note
+    FROM pull_requests pr
+    JOIN pr_reviewers prr ON pr.pull_request_id = prr.pull_request_id
+    WHERE prr.reviewer_id = $1`
+
 func (r *UserRepo) GetReview(userID int) ([]models.PullRequestShort, error) {
 	const op = "repo.user.GetReview"
 
-	query := `
-        SELECT 
-            pr.pull_request_id,
-            pr.pull_request_name, 
-            pr.author_id,
-            pr.status
-        FROM pull_requests pr
-        JOIN pr_reviewers prr ON pr.pull_request_id = prr.pull_request_id
-        WHERE prr.reviewer_id = $1`
-
 	var prs []models.PullRequestShort
 
-	err := r.storage.Select(&prs, query, userID)
+	err := r.storage.Select(&prs, reviewsByReviewerQuery, userID)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return []models.PullRequestShort{}, nil
@@ -67,8 +123,324 @@ func (r *UserRepo) GetReview(userID int) ([]models.PullRequestShort, error) {
 		if err != nil {
 			continue
 		}
-		prs[i].AuthorID = fmt.Sprintf("u%d", authorIDInt)
+		prs[i].AuthorID = models.UserID(authorIDInt).String()
 	}
 
 	return prs, nil
 }
+
+func (r *UserRepo) GetUser(userID int) (models.User, error) {
+	const op = "repo.user.GetUser"
+
+	query := `SELECT ` + userColumns + ` FROM users WHERE user_id = $1`
+
+	var user models.User
+	err := r.storage.Get(&user, query, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return models.User{}, apperrors.ErrUserNotFound
+		}
+		return models.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	id, _ := strconv.Atoi(user.UserID)
+	user.UserID = models.UserID(id).String()
+
+	return user, nil
+}
+
+func (r *UserRepo) GetUserSummary(userID int) (models.UserSummary, error) {
+	const op = "repo.user.GetUserSummary"
+
+	query := `
+		SELECT ` + userColumns + `,
+			(SELECT COUNT(*)
+				FROM pr_reviewers prr
+				JOIN pull_requests pr ON pr.pull_request_id = prr.pull_request_id
+				JOIN pr_statuses ps ON ps.status = pr.status
+				WHERE prr.reviewer_id = users.user_id AND ps.is_terminal = false
+					AND prr.review_completed_at IS NULL) AS open_reviews,
+			(SELECT COUNT(*)
+				FROM pr_reviewers prr
+				WHERE prr.reviewer_id = users.user_id) AS total_reviews
+		FROM users
+		WHERE user_id = $1`
+
+	var summary models.UserSummary
+	err := r.storage.Get(&summary, query, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return models.UserSummary{}, apperrors.ErrUserNotFound
+		}
+		return models.UserSummary{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	id, _ := strconv.Atoi(summary.UserID)
+	summary.UserID = models.UserID(id).String()
+
+	return summary, nil
+}
+
+// UpdateUserSettings writes the settings only while the stored ones still
+// equal expected, so a concurrent change is never overwritten.
+func (r *UserRepo) UpdateUserSettings(userID int, expected models.UserSettings, settings models.UserSettings) (models.User, error) {
+	const op = "repo.user.UpdateUserSettings"
+
+	query := `
+		UPDATE users SET
+			username = $1, is_active = $2,
+			display_name = NULLIF($3, ''), email = NULLIF($4, ''),
+			avatar_url = NULLIF($5, ''), locale = NULLIF($6, ''),
+			max_daily_assignments = $7
+		WHERE user_id = $8 AND username = $9 AND is_active = $10
+			AND COALESCE(display_name, '') = $11 AND COALESCE(email, '') = $12
+			AND COALESCE(avatar_url, '') = $13 AND COALESCE(locale, '') = $14
+			AND max_daily_assignments = $15
+		RETURNING ` + userColumns
+
+	var user models.User
+	err := r.storage.Get(&user, query,
+		settings.Username, settings.IsActive,
+		settings.DisplayName, settings.Email, settings.AvatarURL, settings.Locale,
+		settings.MaxDailyAssignments,
+		userID, expected.Username, expected.IsActive,
+		expected.DisplayName, expected.Email, expected.AvatarURL, expected.Locale,
+		expected.MaxDailyAssignments)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return models.User{}, fmt.Errorf("%s: %w", op, apperrors.ErrPreconditionFailed)
+		}
+		return models.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	id, _ := strconv.Atoi(user.UserID)
+	user.UserID = models.UserID(id).String()
+
+	return user, nil
+}
+
+// FindUserByUsername matches the username case-insensitively, preferring an
+// active user when several share it. Anonymized users never match.
+func (r *UserRepo) FindUserByUsername(username string) (models.User, error) {
+	const op = "repo.user.FindUserByUsername"
+
+	query := `SELECT ` + userColumns + ` FROM users
+		WHERE lower(username) = lower($1) AND anonymized_at IS NULL
+		ORDER BY is_active DESC, user_id
+		LIMIT 1`
+
+	var user models.User
+	err := r.storage.Get(&user, query, username)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return models.User{}, apperrors.ErrUserNotFound
+		}
+		return models.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	id, _ := strconv.Atoi(user.UserID)
+	user.UserID = models.UserID(id).String()
+
+	return user, nil
+}
+
+func (r *UserRepo) IsAnonymized(userID int) (bool, error) {
+	const op = "repo.user.IsAnonymized"
+
+	query := `SELECT anonymized_at IS NOT NULL FROM users WHERE user_id = $1`
+
+	var anonymized bool
+	err := r.storage.Get(&anonymized, query, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, apperrors.ErrUserNotFound
+		}
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return anonymized, nil
+}
+
+// AnonymizeUser replaces the user's profile with the pseudonym and deletes
+// the stored webhook deliveries that mention the user, whose bodies carry
+// the old profile. It returns how many deliveries were deleted.
+func (r *UserRepo) AnonymizeUser(userID int, pseudonym string) (int, error) {
+	const op = "repo.user.AnonymizeUser"
+
+	tx, err := r.storage.Beginx()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE users
+		SET username = $1, anonymized_at = NOW(),
+			display_name = NULL, email = NULL, avatar_url = NULL, locale = NULL
+		WHERE user_id = $2 AND anonymized_at IS NULL
+	`
+
+	result, err := tx.Exec(query, pseudonym, userID)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if rowsAffected == 0 {
+		return 0, fmt.Errorf("%s: %w", op, apperrors.ErrUserAnonymized)
+	}
+
+	result, err = tx.Exec(`DELETE FROM webhook_deliveries WHERE $1 = ANY(user_ids)`, userID)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	purged, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: failed to commit transaction: %w", op, err)
+	}
+
+	return int(purged), nil
+}
+
+func (r *UserRepo) SetIsActiveBatch(isActive bool, userIDs []int) ([]models.User, error) {
+	const op = "repo.user.SetIsActiveBatch"
+
+	query := `UPDATE users SET is_active = $1 WHERE user_id = ANY($2)
+        RETURNING ` + userColumns
+
+	users := make([]models.User, 0, len(userIDs))
+	err := r.storage.Select(&users, query, isActive, pq.Array(userIDs))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	for i := range users {
+		id, _ := strconv.Atoi(users[i].UserID)
+		users[i].UserID = models.UserID(id).String()
+	}
+
+	return users, nil
+}
+
+// openReviewsByReviewerQuery lists the unfinished reviews of a user on
+// non-terminal PRs. It is one of the hotQueries checked by /admin/dbcheck.
+const openReviewsByReviewerQuery = `
+    SELECT
+        pr.pull_request_id,
+        pr.pull_request_name,
+        'u' || pr.author_id as author_id,
+        pr.status,
+        pr.priority,
+        pr.created_at,
+        prr.queue_position
+    FROM pull_requests pr
+    JOIN pr_reviewers prr ON pr.pull_request_id = prr.pull_request_id
+    JOIN pr_statuses ps ON ps.status = pr.status
+    WHERE prr.reviewer_id = $1 AND ps.is_terminal = false
+        AND prr.review_completed_at IS NULL`
+
+func (r *UserRepo) GetOpenReviews(userID int) ([]models.ReviewAssignment, error) {
+	const op = "repo.user.GetOpenReviews"
+
+	reviews := make([]models.ReviewAssignment, 0)
+	err := r.storage.Select(&reviews, openReviewsByReviewerQuery, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return reviews, nil
+}
+
+// SetQueueOrder places the given open reviews of the user at the head of
+// their queue in that order and clears the position of every other one.
+func (r *UserRepo) SetQueueOrder(userID int, prIDs []string) error {
+	const op = "repo.user.SetQueueOrder"
+
+	tx, err := r.storage.Beginx()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`UPDATE pr_reviewers SET queue_position = NULL
+		WHERE reviewer_id = $1 AND queue_position IS NOT NULL`, userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	query := `
+		UPDATE pr_reviewers prr
+		SET queue_position = o.position
+		FROM unnest($2::text[]) WITH ORDINALITY AS o(pull_request_id, position),
+			pull_requests pr, pr_statuses ps
+		WHERE prr.reviewer_id = $1 AND prr.pull_request_id = o.pull_request_id
+			AND pr.pull_request_id = prr.pull_request_id AND ps.status = pr.status
+			AND ps.is_terminal = false AND prr.review_completed_at IS NULL`
+
+	result, err := tx.Exec(query, userID, pq.Array(prIDs))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if int(updated) != len(prIDs) {
+		return fmt.Errorf("%s: %w", op, apperrors.ErrReviewerNotAssigned)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: failed to commit transaction: %w", op, err)
+	}
+
+	return nil
+}
+
+// GetForecastInputs collects the user's open reviews and the activity of
+// their team since the given time.
+func (r *UserRepo) GetForecastInputs(userID int, since time.Time) (models.ForecastInputs, error) {
+	const op = "repo.user.GetForecastInputs"
+
+	query := `
+		SELECT
+			u.team_name,
+			u.is_active AND ` + reviewerEligible + ` AS is_active,
+			(SELECT COUNT(*)
+				FROM pr_reviewers prr
+				JOIN pull_requests pr ON pr.pull_request_id = prr.pull_request_id
+				JOIN pr_statuses ps ON ps.status = pr.status
+				WHERE prr.reviewer_id = u.user_id AND ps.is_terminal = false
+					AND prr.review_completed_at IS NULL) AS open_reviews,
+			(SELECT COUNT(*)
+				FROM pull_requests pr
+				JOIN users a ON a.user_id = pr.author_id
+				WHERE a.team_name = u.team_name AND a.user_id <> u.user_id
+					AND pr.created_at >= $2) AS team_prs,
+			(SELECT COUNT(*) FROM users m WHERE m.team_name = u.team_name AND m.is_active = true
+				AND NOT (m.author_only AND (m.author_only_until IS NULL OR m.author_only_until > NOW()))) AS active_members,
+			COALESCE(t.reviewers_per_pr, o.reviewers_per_pr) AS reviewers_per_pr
+		FROM users u
+		JOIN teams t ON t.team_name = u.team_name
+		CROSS JOIN org_policy o
+		WHERE u.user_id = $1`
+
+	var inputs models.ForecastInputs
+	if err := r.storage.Get(&inputs, query, userID, since); err != nil {
+		if err == sql.ErrNoRows {
+			return models.ForecastInputs{}, apperrors.ErrUserNotFound
+		}
+		return models.ForecastInputs{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return inputs, nil
+}
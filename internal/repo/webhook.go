@@ -0,0 +1,430 @@
+package repo
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"pull-request-assigner/internal/apperrors"
+	"pull-request-assigner/internal/domain/models"
+	"strconv"
+	"time"
+)
+
+type WebhookRepo struct {
+	storage *sqlx.DB
+}
+
+func NewWebhookRepo(storage *sqlx.DB) *WebhookRepo {
+	return &WebhookRepo{storage: storage}
+}
+
+const webhookColumns = `
+	w.id, w.team_name, w.url, w.secret, w.events, w.is_active, w.created_at,
+	w.last_delivery_at, w.last_status, w.last_error
+`
+
+type webhookRow struct {
+	models.TeamWebhook
+	Events pq.StringArray `db:"events"`
+}
+
+func (row webhookRow) toModel() models.TeamWebhook {
+	hook := row.TeamWebhook
+	hook.Events = nonNilTags(row.Events)
+	return hook
+}
+
+func (r *WebhookRepo) CreateWebhook(hook models.TeamWebhook) (*models.TeamWebhook, error) {
+	const op = "repo.webhook.CreateWebhook"
+
+	query := `
+		INSERT INTO team_webhooks (team_name, url, secret, events, is_active)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`
+
+	var id int64
+	err := r.storage.Get(&id, query, hook.TeamName, hook.URL, hook.Secret, pq.Array(nonNilTags(hook.Events)), hook.IsActive)
+	if err != nil {
+		switch {
+		case isDuplicateKeyError(err):
+			return nil, fmt.Errorf("%s: %w", op, apperrors.ErrWebhookExists)
+		case isForeignKeyError(err):
+			return nil, fmt.Errorf("%s: %w", op, apperrors.ErrTeamNotFound)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return r.GetWebhook(id)
+}
+
+func (r *WebhookRepo) GetWebhook(id int64) (*models.TeamWebhook, error) {
+	const op = "repo.webhook.GetWebhook"
+
+	var row webhookRow
+	err := r.storage.Get(&row, `SELECT `+webhookColumns+` FROM team_webhooks w WHERE w.id = $1`, id)
+	if err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return nil, fmt.Errorf("%s: %w", op, apperrors.ErrWebhookNotFound)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	hook := row.toModel()
+	return &hook, nil
+}
+
+func (r *WebhookRepo) ListWebhooks(teamName string) ([]models.TeamWebhook, error) {
+	const op = "repo.webhook.ListWebhooks"
+
+	var exists bool
+	if err := r.storage.Get(&exists, `SELECT EXISTS(SELECT 1 FROM teams WHERE team_name = $1)`, teamName); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("%s: %w", op, apperrors.ErrTeamNotFound)
+	}
+
+	var rows []webhookRow
+	err := r.storage.Select(&rows, `SELECT `+webhookColumns+` FROM team_webhooks w WHERE w.team_name = $1 ORDER BY w.id`, teamName)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	hooks := make([]models.TeamWebhook, 0, len(rows))
+	for _, row := range rows {
+		hooks = append(hooks, row.toModel())
+	}
+
+	return hooks, nil
+}
+
+func (r *WebhookRepo) UpdateWebhook(hook models.TeamWebhook) error {
+	const op = "repo.webhook.UpdateWebhook"
+
+	query := `
+		UPDATE team_webhooks
+		SET url = $1, secret = $2, events = $3, is_active = $4
+		WHERE id = $5
+	`
+
+	result, err := r.storage.Exec(query, hook.URL, hook.Secret, pq.Array(nonNilTags(hook.Events)), hook.IsActive, hook.ID)
+	if err != nil {
+		if isDuplicateKeyError(err) {
+			return fmt.Errorf("%s: %w", op, apperrors.ErrWebhookExists)
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("%s: %w", op, apperrors.ErrWebhookNotFound)
+	}
+
+	return nil
+}
+
+func (r *WebhookRepo) DeleteWebhook(id int64) error {
+	const op = "repo.webhook.DeleteWebhook"
+
+	result, err := r.storage.Exec(`DELETE FROM team_webhooks WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("%s: %w", op, apperrors.ErrWebhookNotFound)
+	}
+
+	return nil
+}
+
+// GetPRWebhooks returns the active webhooks of the team the PR author
+// belongs to.
+func (r *WebhookRepo) GetPRWebhooks(prID string) ([]models.TeamWebhook, error) {
+	const op = "repo.webhook.GetPRWebhooks"
+
+	query := `
+		SELECT ` + webhookColumns + `
+		FROM pull_requests pr
+		JOIN users u ON u.user_id = pr.author_id
+		JOIN team_webhooks w ON w.team_name = u.team_name
+		WHERE pr.pull_request_id = $1 AND w.is_active
+		ORDER BY w.id
+	`
+
+	var rows []webhookRow
+	if err := r.storage.Select(&rows, query, prID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	hooks := make([]models.TeamWebhook, 0, len(rows))
+	for _, row := range rows {
+		hooks = append(hooks, row.toModel())
+	}
+
+	return hooks, nil
+}
+
+// RecordDelivery stores the outcome of the latest delivery attempt and adds
+// it to the PR's activity feed. A zero status or empty error is stored as NULL.
+func (r *WebhookRepo) RecordDelivery(id int64, prID string, event string, status int, deliveryErr string) error {
+	const op = "repo.webhook.RecordDelivery"
+
+	tx, err := r.storage.Beginx()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE team_webhooks
+		SET last_delivery_at = NOW(), last_status = NULLIF($1, 0), last_error = NULLIF($2, '')
+		WHERE id = $3
+	`
+
+	if _, err := tx.Exec(query, status, deliveryErr, id); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	kind, details := models.ActivityNotificationSent, fmt.Sprintf("%s via webhook %d: %d", event, id, status)
+	if deliveryErr != "" {
+		kind, details = models.ActivityNotificationFailed, fmt.Sprintf("%s via webhook %d: %s", event, id, deliveryErr)
+	}
+
+	_, err = tx.Exec(`INSERT INTO pr_events (pull_request_id, kind, details) VALUES ($1, $2, $3)`, prID, kind, details)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: failed to commit transaction: %w", op, err)
+	}
+
+	return nil
+}
+
+// GetUsers returns the users with the given IDs, skipping unknown ones.
+func (r *WebhookRepo) GetUsers(userIDs []int) ([]models.User, error) {
+	const op = "repo.webhook.GetUsers"
+
+	users := make([]models.User, 0, len(userIDs))
+	err := r.storage.Select(&users, `SELECT `+userColumns+` FROM users WHERE user_id = ANY($1)`, pq.Array(userIDs))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	for i := range users {
+		id, _ := strconv.Atoi(users[i].UserID)
+		users[i].UserID = models.UserID(id).String()
+	}
+
+	return users, nil
+}
+
+// GetNotificationData returns the PR fields notification templates refer
+// to. DueAt is left to the caller, which knows the review SLA.
+func (r *WebhookRepo) GetNotificationData(prID string) (*models.NotificationTemplateData, error) {
+	const op = "repo.webhook.GetNotificationData"
+
+	query := `
+		SELECT p.pull_request_id, p.pull_request_name, p.author_id, u.username, p.created_at
+		FROM pull_requests p
+		JOIN users u ON u.user_id = p.author_id
+		WHERE p.pull_request_id = $1
+	`
+
+	var row struct {
+		PullRequestID   string    `db:"pull_request_id"`
+		PullRequestName string    `db:"pull_request_name"`
+		AuthorID        int       `db:"author_id"`
+		Username        string    `db:"username"`
+		CreatedAt       time.Time `db:"created_at"`
+	}
+	if err := r.storage.Get(&row, query, prID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%s: %w", op, apperrors.ErrPRNotFound)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &models.NotificationTemplateData{
+		PullRequestID:   row.PullRequestID,
+		PullRequestName: row.PullRequestName,
+		AuthorID:        models.UserID(row.AuthorID).String(),
+		AuthorName:      row.Username,
+		CreatedAt:       row.CreatedAt,
+	}, nil
+}
+
+const webhookDeliveryColumns = `
+	d.id, d.webhook_id, w.team_name, w.url, d.event, d.pull_request_id, d.body,
+	d.attempt, d.status, d.next_attempt_at, d.last_status, d.last_error,
+	d.created_at, d.updated_at
+`
+
+// SaveDelivery stores a delivery that did not succeed: a new one when ID is
+// zero, otherwise the row is updated in place. It returns the row's ID.
+func (r *WebhookRepo) SaveDelivery(delivery models.StoredWebhookDelivery) (int64, error) {
+	const op = "repo.webhook.SaveDelivery"
+
+	if delivery.ID == 0 {
+		query := `
+			INSERT INTO webhook_deliveries
+				(webhook_id, event, pull_request_id, body, user_ids, attempt, status, next_attempt_at, last_status, last_error)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+			RETURNING id
+		`
+
+		userIDs := delivery.UserIDs
+		if userIDs == nil {
+			// A nil slice would be stored as NULL.
+			userIDs = []int{}
+		}
+
+		var id int64
+		err := r.storage.Get(&id, query, delivery.WebhookID, delivery.Event, delivery.PullRequestID, delivery.Body,
+			pq.Array(userIDs), delivery.Attempt, delivery.Status, delivery.NextAttemptAt, delivery.LastStatus, delivery.LastError)
+		if err != nil {
+			if isForeignKeyError(err) {
+				return 0, fmt.Errorf("%s: %w", op, apperrors.ErrWebhookNotFound)
+			}
+			return 0, fmt.Errorf("%s: %w", op, err)
+		}
+
+		return id, nil
+	}
+
+	query := `
+		UPDATE webhook_deliveries
+		SET attempt = $1, status = $2, next_attempt_at = $3, last_status = $4, last_error = $5, updated_at = NOW()
+		WHERE id = $6
+	`
+
+	result, err := r.storage.Exec(query, delivery.Attempt, delivery.Status, delivery.NextAttemptAt,
+		delivery.LastStatus, delivery.LastError, delivery.ID)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return 0, fmt.Errorf("%s: %w", op, apperrors.ErrWebhookNotFound)
+	}
+
+	return delivery.ID, nil
+}
+
+// DeleteDelivery forgets a stored delivery. A missing row is not an error:
+// deleting its webhook already removed it.
+func (r *WebhookRepo) DeleteDelivery(id int64) error {
+	const op = "repo.webhook.DeleteDelivery"
+
+	if _, err := r.storage.Exec(`DELETE FROM webhook_deliveries WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// DeleteUserPendingDeliveries drops the pending deliveries that mention the
+// user and returns how many there were.
+func (r *WebhookRepo) DeleteUserPendingDeliveries(userID int) (int, error) {
+	const op = "repo.webhook.DeleteUserPendingDeliveries"
+
+	result, err := r.storage.Exec(`DELETE FROM webhook_deliveries WHERE status = 'PENDING' AND $1 = ANY(user_ids)`, userID)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(deleted), nil
+}
+
+// DeleteFailedDeliveriesOlderThan drops failed deliveries last attempted
+// more than age ago and returns how many there were.
+func (r *WebhookRepo) DeleteFailedDeliveriesOlderThan(age time.Duration) (int64, error) {
+	const op = "repo.webhook.DeleteFailedDeliveriesOlderThan"
+
+	query := `DELETE FROM webhook_deliveries WHERE status = 'FAILED' AND updated_at < NOW() - $1 * INTERVAL '1 second'`
+
+	result, err := r.storage.Exec(query, age.Seconds())
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return deleted, nil
+}
+
+// ClaimDueDeliveries returns up to limit pending deliveries whose next
+// attempt is due and pushes that attempt lease into the future, so other
+// instances skip them. A delivery whose claimer dies is due again once the
+// lease runs out.
+func (r *WebhookRepo) ClaimDueDeliveries(limit int, lease time.Duration) ([]models.StoredWebhookDelivery, error) {
+	const op = "repo.webhook.ClaimDueDeliveries"
+
+	query := `
+		WITH d AS (
+			UPDATE webhook_deliveries
+			SET next_attempt_at = NOW() + make_interval(secs => $2)
+			WHERE id IN (
+				SELECT id FROM webhook_deliveries
+				WHERE status = 'PENDING' AND next_attempt_at <= NOW()
+				ORDER BY next_attempt_at
+				LIMIT $1
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING *
+		)
+		SELECT ` + webhookDeliveryColumns + ` FROM d
+		JOIN team_webhooks w ON w.id = d.webhook_id
+		ORDER BY d.id
+	`
+
+	deliveries := make([]models.StoredWebhookDelivery, 0)
+	if err := r.storage.Select(&deliveries, query, limit, lease.Seconds()); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return deliveries, nil
+}
+
+// ListFailedDeliveries returns failed deliveries, latest first, of one team
+// or of every team when teamName is empty.
+func (r *WebhookRepo) ListFailedDeliveries(teamName string, limit int) ([]models.StoredWebhookDelivery, error) {
+	const op = "repo.webhook.ListFailedDeliveries"
+
+	query := `
+		SELECT ` + webhookDeliveryColumns + `
+		FROM webhook_deliveries d
+		JOIN team_webhooks w ON w.id = d.webhook_id
+		WHERE d.status = 'FAILED' AND ($1 = '' OR w.team_name = $1)
+		ORDER BY d.updated_at DESC, d.id DESC
+		LIMIT $2
+	`
+
+	deliveries := make([]models.StoredWebhookDelivery, 0)
+	if err := r.storage.Select(&deliveries, query, teamName, limit); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return deliveries, nil
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"pull-request-assigner/internal/apperrors"
+	"pull-request-assigner/internal/domain/events"
+	"pull-request-assigner/internal/domain/models"
+	"pull-request-assigner/internal/lib/actor"
+	"pull-request-assigner/internal/lib/logger/sl"
+	"strconv"
+)
+
+type ActivityService struct {
+	log          *slog.Logger
+	activityRepo ActivityStore
+}
+
+type ActivityStore interface {
+	GetPRActivity(prID string) ([]models.PRActivity, error)
+}
+
+type PREventRecorder interface {
+	RecordPREvent(prID string, kind string, actorID string, details string) error
+}
+
+func NewActivityService(log *slog.Logger, activityRepo ActivityStore) *ActivityService {
+	return &ActivityService{
+		log:          log,
+		activityRepo: activityRepo,
+	}
+}
+
+func (s *ActivityService) GetPRActivity(ctx context.Context, prID string) ([]models.PRActivity, error) {
+	const op = "service.activity.GetPRActivity"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("pull_request_id", prID),
+	)
+
+	activity, err := s.activityRepo.GetPRActivity(prID)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrPRNotFound) {
+			log.Warn("pull request not found")
+			return nil, apperrors.ErrPRNotFound
+		}
+		log.Error("failed to get PR activity", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return activity, nil
+}
+
+// NewActivitySink returns the bus subscriber storing the PR events the feed
+// cannot rebuild from other tables. Like audit it is best effort.
+func NewActivitySink(log *slog.Logger, recorder PREventRecorder) events.Handler {
+	const op = "service.activity.Sink"
+
+	log = log.With(slog.String("op", op))
+
+	return func(ctx context.Context, event events.Event) {
+		var prID, kind, actorID, details string
+
+		switch e := event.(type) {
+		case events.PullRequestStatusChanged:
+			prID, kind, details = e.PullRequestID, models.ActivityStatusChanged, e.Status
+			actorID, _ = actor.UserID(ctx)
+		case events.PullRequestAutoMerged:
+			prID, kind, details = e.PullRequestID, models.ActivityAutoMerged, strconv.Itoa(e.Approvals)+" approvals"
+		case events.PullRequestMergedExternally:
+			prID, kind, details = e.PullRequestID, models.ActivityMergedExternally, e.MergedByLogin
+		default:
+			return
+		}
+
+		if err := recorder.RecordPREvent(prID, kind, actorID, details); err != nil {
+			log.Error("failed to record PR event",
+				slog.String("pull_request_id", prID),
+				slog.String("kind", kind),
+				sl.Err(err))
+		}
+	}
+}
@@ -0,0 +1,390 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"pull-request-assigner/internal/apperrors"
+	"pull-request-assigner/internal/domain/events"
+	"pull-request-assigner/internal/domain/models"
+	"pull-request-assigner/internal/lib/logger/sl"
+	"pull-request-assigner/internal/lib/migrator"
+)
+
+type AdminService struct {
+	log            *slog.Logger
+	archiveRepo    ArchiveProvider
+	userRepo       AnonymizationProvider
+	simulationRepo SimulationProvider
+	dbCheckRepo    DBCheckProvider
+	jobRepo        JobReader
+	publisher      events.Publisher
+	secret         string
+}
+
+type JobReader interface {
+	GetJobs() ([]models.Job, error)
+}
+
+type ArchiveProvider interface {
+	GetArchivedTeams() ([]models.ArchivedTeam, error)
+	GetDeactivatedUsers() ([]models.ArchivedUser, error)
+	RestoreTeam(teamName string) error
+	RestoreUser(userID int) error
+}
+
+type DBCheckProvider interface {
+	ExplainHotQueries() ([]models.QueryPlanCheck, error)
+	GetSchemaVersion() (uint, bool, error)
+	FindMembershipDrift() ([]models.MembershipDrift, error)
+	RepairMembershipDrift() ([]models.MembershipDrift, error)
+}
+
+type AnonymizationProvider interface {
+	GetUser(userID int) (models.User, error)
+	IsAnonymized(userID int) (bool, error)
+	AnonymizeUser(userID int, pseudonym string) (int, error)
+}
+
+func NewAdminService(
+	log *slog.Logger,
+	archiveRepo ArchiveProvider,
+	userRepo AnonymizationProvider,
+	simulationRepo SimulationProvider,
+	dbCheckRepo DBCheckProvider,
+	jobRepo JobReader,
+	publisher events.Publisher,
+	secret string) *AdminService {
+	return &AdminService{
+		log:            log,
+		archiveRepo:    archiveRepo,
+		userRepo:       userRepo,
+		simulationRepo: simulationRepo,
+		dbCheckRepo:    dbCheckRepo,
+		jobRepo:        jobRepo,
+		publisher:      publisher,
+		secret:         secret,
+	}
+}
+
+func (s *AdminService) GetArchive(ctx context.Context) ([]models.ArchivedTeam, []models.ArchivedUser, error) {
+	const op = "service.admin.GetArchive"
+
+	log := s.log.With(slog.String("op", op))
+
+	log.Info("getting archive")
+
+	teams, err := s.archiveRepo.GetArchivedTeams()
+	if err != nil {
+		log.Error("failed to get archived teams", sl.Err(err))
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	users, err := s.archiveRepo.GetDeactivatedUsers()
+	if err != nil {
+		log.Error("failed to get deactivated users", sl.Err(err))
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("archive retrieved successfully",
+		slog.Int("team_count", len(teams)),
+		slog.Int("user_count", len(users)))
+
+	return teams, users, nil
+}
+
+func (s *AdminService) RestoreTeam(ctx context.Context, teamName string) error {
+	const op = "service.admin.RestoreTeam"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("team_name", teamName),
+	)
+
+	log.Info("attempting to restore team")
+
+	if teamName == "" {
+		log.Error("team name is required")
+		return apperrors.ErrTeamNameRequired
+	}
+
+	err := s.archiveRepo.RestoreTeam(teamName)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrTeamNotArchived) {
+			log.Warn("team is not archived")
+			return apperrors.ErrTeamNotArchived
+		}
+		log.Error("failed to restore team", sl.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	recordAudit(ctx, s.publisher, models.AuditEvent{
+		TeamName: teamName,
+		Action:   models.AuditTeamRestored,
+	})
+
+	log.Info("team restored successfully")
+	return nil
+}
+
+func (s *AdminService) RestoreUser(ctx context.Context, userID string) error {
+	const op = "service.admin.RestoreUser"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("userID", userID),
+	)
+
+	log.Info("attempting to restore user")
+
+	uid, err := models.ParseUserID(userID)
+	if err != nil {
+		log.Error("invalid user ID format", sl.Err(err))
+		return apperrors.ErrInvalidUserID
+	}
+
+	err = s.archiveRepo.RestoreUser(uid.Int())
+	if err != nil {
+		if errors.Is(err, apperrors.ErrUserNotFound) {
+			log.Warn("user not found")
+			return apperrors.ErrUserNotFound
+		}
+		log.Error("failed to restore user", sl.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if user, err := s.userRepo.GetUser(uid.Int()); err != nil {
+		log.Warn("failed to load restored user for audit", sl.Err(err))
+	} else {
+		recordAudit(ctx, s.publisher, activityAuditEvent(user, true))
+	}
+
+	log.Info("user restored successfully")
+	return nil
+}
+
+// AnonymizeUser is a two-step operation: a call without a confirmation token
+// only returns the token, a second call with that token replaces the username
+// with a random pseudonym, so the user cannot be traced back from it, and
+// deletes the stored webhook deliveries that carry the old profile. The
+// token is bound to the current username, so it cannot be replayed once the
+// user is anonymized.
+func (s *AdminService) AnonymizeUser(ctx context.Context, userID string, confirmationToken string) (*models.AnonymizationResult, error) {
+	const op = "service.admin.AnonymizeUser"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("userID", userID),
+	)
+
+	log.Info("attempting to anonymize user")
+
+	uid, err := models.ParseUserID(userID)
+	if err != nil {
+		log.Error("invalid user ID format", sl.Err(err))
+		return nil, apperrors.ErrInvalidUserID
+	}
+
+	user, err := s.userRepo.GetUser(uid.Int())
+	if err != nil {
+		if errors.Is(err, apperrors.ErrUserNotFound) {
+			log.Warn("user not found")
+			return nil, apperrors.ErrUserNotFound
+		}
+		log.Error("failed to get user", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	anonymized, err := s.userRepo.IsAnonymized(uid.Int())
+	if err != nil {
+		log.Error("failed to check anonymization state", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if anonymized {
+		log.Warn("user is already anonymized")
+		return nil, apperrors.ErrUserAnonymized
+	}
+
+	if user.IsActive {
+		log.Warn("refusing to anonymize active user")
+		return nil, apperrors.ErrUserActive
+	}
+
+	expectedToken := s.sign("anonymize:" + user.UserID + ":" + user.Username)
+
+	if confirmationToken == "" {
+		log.Info("anonymization confirmation token issued")
+		return &models.AnonymizationResult{
+			UserID:            user.UserID,
+			ConfirmationToken: expectedToken,
+		}, nil
+	}
+
+	if !hmac.Equal([]byte(confirmationToken), []byte(expectedToken)) {
+		log.Warn("invalid anonymization confirmation token")
+		return nil, apperrors.ErrInvalidConfirmation
+	}
+
+	pseudonym, err := newPseudonym()
+	if err != nil {
+		log.Error("failed to generate pseudonym", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	purged, err := s.userRepo.AnonymizeUser(uid.Int(), pseudonym)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrUserAnonymized) {
+			log.Warn("user is already anonymized")
+			return nil, apperrors.ErrUserAnonymized
+		}
+		log.Error("failed to anonymize user", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// The old username must not survive in the audit trail, so only the
+	// subject ID is recorded.
+	recordAudit(ctx, s.publisher, models.AuditEvent{
+		TeamName:  user.TeamName,
+		Action:    models.AuditUserAnonymized,
+		SubjectID: user.UserID,
+	})
+
+	log.Info("user anonymized successfully", slog.Int("purged_deliveries", purged))
+
+	return &models.AnonymizationResult{
+		UserID:           user.UserID,
+		Pseudonym:        pseudonym,
+		Anonymized:       true,
+		PurgedDeliveries: purged,
+	}, nil
+}
+
+func (s *AdminService) CheckDB(ctx context.Context) ([]models.QueryPlanCheck, error) {
+	const op = "service.admin.CheckDB"
+
+	log := s.log.With(slog.String("op", op))
+
+	log.Info("explaining hot queries")
+
+	checks, err := s.dbCheckRepo.ExplainHotQueries()
+	if err != nil {
+		log.Error("failed to explain hot queries", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	for _, check := range checks {
+		if check.Flagged {
+			log.Warn("sequential scan in hot query",
+				slog.String("query", check.Name),
+				slog.Any("tables", check.SeqScanTables))
+		}
+	}
+
+	return checks, nil
+}
+
+func (s *AdminService) GetMigrationStatus(ctx context.Context) (*models.MigrationStatus, error) {
+	const op = "service.admin.GetMigrationStatus"
+
+	log := s.log.With(slog.String("op", op))
+
+	version, dirty, err := s.dbCheckRepo.GetSchemaVersion()
+	if err != nil {
+		log.Error("failed to get schema version", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	latest, err := migrator.LatestVersion()
+	if err != nil {
+		log.Error("failed to get latest migration version", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	status := &models.MigrationStatus{
+		Version:       version,
+		Dirty:         dirty,
+		Latest:        latest,
+		MinCompatible: migrator.MinCompatibleVersion,
+		Compatible:    migrator.CheckCompatibility(version, dirty, latest) == nil,
+	}
+	if latest > version {
+		status.Pending = int(latest - version)
+	}
+
+	if dirty {
+		log.Warn("schema is dirty", slog.Uint64("version", uint64(version)))
+	}
+
+	return status, nil
+}
+
+// CheckMembership reports where team_members disagrees with users.team_name
+// and, when repair is set, converges team_members onto users.team_name.
+func (s *AdminService) CheckMembership(ctx context.Context, repair bool) (*models.MembershipReport, error) {
+	const op = "service.admin.CheckMembership"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Bool("repair", repair),
+	)
+
+	var (
+		drift []models.MembershipDrift
+		err   error
+	)
+	if repair {
+		drift, err = s.dbCheckRepo.RepairMembershipDrift()
+	} else {
+		drift, err = s.dbCheckRepo.FindMembershipDrift()
+	}
+	if err != nil {
+		log.Error("failed to check team membership", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(drift) > 0 {
+		log.Warn("team membership drift detected", slog.Int("entries", len(drift)))
+	}
+
+	return &models.MembershipReport{Drift: drift, Repaired: repair}, nil
+}
+
+// RepairMembership is the scheduled form of CheckMembership with repair.
+func (s *AdminService) RepairMembership(ctx context.Context) error {
+	_, err := s.CheckMembership(ctx, true)
+	return err
+}
+
+func (s *AdminService) sign(payload string) string {
+	mac := hmac.New(sha256.New, []byte(s.secret))
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func newPseudonym() (string, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return "anonymous-" + hex.EncodeToString(buf), nil
+}
+
+func (s *AdminService) GetJobs(ctx context.Context) ([]models.Job, error) {
+	const op = "service.admin.GetJobs"
+
+	log := s.log.With(slog.String("op", op))
+
+	jobs, err := s.jobRepo.GetJobs()
+	if err != nil {
+		log.Error("failed to get jobs", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return jobs, nil
+}
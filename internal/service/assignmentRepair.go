@@ -0,0 +1,274 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"log/slog"
+	"pull-request-assigner/internal/apperrors"
+	"pull-request-assigner/internal/domain/events"
+	"pull-request-assigner/internal/domain/models"
+	"pull-request-assigner/internal/lib/logger/sl"
+	"time"
+)
+
+const (
+	assignmentRepairBatch      = 100
+	maxAssignmentRepairBackoff = time.Hour
+
+	// assignmentRepairLease hides a claimed repair from other runs until
+	// it is settled or its runner is presumed dead.
+	assignmentRepairLease = 5 * time.Minute
+)
+
+var (
+	assignmentRepairsQueued  = expvar.NewInt("assignment_repairs_queued_total")
+	assignmentRepairsDone    = expvar.NewInt("assignment_repairs_done_total")
+	assignmentRepairFailures = expvar.NewInt("assignment_repair_failures_total")
+)
+
+type AssignmentRepairQueue interface {
+	QueueRepair(prID string, lastError string) error
+}
+
+type AssignmentRepairStore interface {
+	PurgeSettledRepairs() (int, error)
+	ClaimDueRepairs(limit int, lease time.Duration) ([]models.AssignmentRepair, error)
+	GetRepairs() ([]models.AssignmentRepair, error)
+	RecordRepairFailure(prID string, lastError string, backoff time.Duration) (models.AssignmentRepair, error)
+	MarkRepairAlerted(prID string) error
+	DeleteRepair(prID string) error
+}
+
+// AssignmentRepairService retries reviewer assignment for PRs stored without
+// their reviewers. A failed repair is due again after Backoff, doubling up to
+// an hour; admins are alerted once a repair failed alertAfter times.
+type AssignmentRepairService struct {
+	log        *slog.Logger
+	repairs    AssignmentRepairStore
+	prService  *PullRequestService
+	backoff    time.Duration
+	alertAfter int
+	publisher  events.Publisher
+}
+
+func NewAssignmentRepairService(
+	log *slog.Logger,
+	repairs AssignmentRepairStore,
+	prService *PullRequestService,
+	backoff time.Duration,
+	alertAfter int,
+	publisher events.Publisher) *AssignmentRepairService {
+	return &AssignmentRepairService{
+		log:        log,
+		repairs:    repairs,
+		prService:  prService,
+		backoff:    backoff,
+		alertAfter: max(alertAfter, 1),
+		publisher:  publisher,
+	}
+}
+
+// RepairAssignments is the scheduler job running due repairs.
+func (s *AssignmentRepairService) RepairAssignments(ctx context.Context) error {
+	_, err := s.RunRepairs(ctx)
+	return err
+}
+
+// RunRepairs retries every due repair once. Repairs of PRs closed or given
+// reviewers meanwhile are dropped first. Each due repair is claimed, so
+// concurrent runs on other instances or from the admin route skip it.
+func (s *AssignmentRepairService) RunRepairs(ctx context.Context) (*models.AssignmentRepairReport, error) {
+	const op = "service.assignmentRepair.RunRepairs"
+
+	log := s.log.With(slog.String("op", op))
+
+	purged, err := s.repairs.PurgeSettledRepairs()
+	if err != nil {
+		log.Error("failed to purge settled repairs", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	due, err := s.repairs.ClaimDueRepairs(assignmentRepairBatch, assignmentRepairLease)
+	if err != nil {
+		log.Error("failed to claim due repairs", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	report := &models.AssignmentRepairReport{
+		Repaired: make([]string, 0, len(due)),
+		Failed:   make([]models.AssignmentRepair, 0),
+	}
+	for _, repair := range due {
+		if ctx.Err() != nil {
+			return report, ctx.Err()
+		}
+
+		failed, err := s.repair(ctx, repair)
+		if err != nil {
+			return report, fmt.Errorf("%s: %w", op, err)
+		}
+		if failed != nil {
+			report.Failed = append(report.Failed, *failed)
+			continue
+		}
+		report.Repaired = append(report.Repaired, repair.PullRequestID)
+	}
+
+	if purged > 0 || len(due) > 0 {
+		log.Info("assignment repairs run",
+			slog.Int("purged", purged),
+			slog.Int("repaired", len(report.Repaired)),
+			slog.Int("failed", len(report.Failed)))
+	}
+
+	return report, nil
+}
+
+// repair retries one repair. A failed attempt is recorded and returned; the
+// error is only set when the repair queue itself could not be updated.
+func (s *AssignmentRepairService) repair(ctx context.Context, repair models.AssignmentRepair) (*models.AssignmentRepair, error) {
+	const op = "service.assignmentRepair.repair"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("pr_id", repair.PullRequestID),
+		slog.Int("attempts", repair.Attempts),
+	)
+
+	reviewers, repairErr := s.prService.repairAssignment(ctx, repair.PullRequestID, log)
+	if repairErr == nil || errors.Is(repairErr, apperrors.ErrPRNotFound) {
+		if err := s.repairs.DeleteRepair(repair.PullRequestID); err != nil {
+			log.Error("failed to delete assignment repair", sl.Err(err))
+			return nil, err
+		}
+		assignmentRepairsDone.Add(1)
+		log.Info("PR assignment repaired", slog.Int("reviewer_count", len(reviewers)))
+		return nil, nil
+	}
+
+	assignmentRepairFailures.Add(1)
+	log.Warn("assignment repair failed", sl.Err(repairErr))
+
+	failed, err := s.repairs.RecordRepairFailure(repair.PullRequestID, repairErr.Error(), s.nextBackoff(repair.Attempts))
+	if err != nil {
+		if errors.Is(err, apperrors.ErrPRNotFound) {
+			return nil, nil
+		}
+		log.Error("failed to record assignment repair failure", sl.Err(err))
+		return nil, err
+	}
+
+	if failed.Attempts >= s.alertAfter && failed.AlertedAt == nil {
+		s.alert(ctx, log, failed)
+	}
+
+	return &failed, nil
+}
+
+func (s *AssignmentRepairService) nextBackoff(attempts int) time.Duration {
+	wait := s.backoff
+	for i := 0; i < attempts && wait < maxAssignmentRepairBackoff; i++ {
+		wait *= 2
+	}
+	return min(wait, maxAssignmentRepairBackoff)
+}
+
+// alert tells admins that a PR keeps failing to get reviewers, through the
+// error log and the audit log. Each repair alerts once.
+func (s *AssignmentRepairService) alert(ctx context.Context, log *slog.Logger, repair models.AssignmentRepair) {
+	log.Error("assignment repair keeps failing, PR has no reviewers",
+		slog.Int("attempts", repair.Attempts),
+		slog.String("last_error", repair.LastError))
+
+	recordAudit(ctx, s.publisher, models.AuditEvent{
+		TeamName: repair.TeamName,
+		Action:   models.AuditAssignmentRepairFailing,
+		Details: fmt.Sprintf("%s: %d failed attempts, last: %s",
+			repair.PullRequestID, repair.Attempts, repair.LastError),
+	})
+
+	if err := s.repairs.MarkRepairAlerted(repair.PullRequestID); err != nil {
+		log.Error("failed to mark assignment repair alerted", sl.Err(err))
+	}
+}
+
+func (s *AssignmentRepairService) GetRepairs(ctx context.Context) ([]models.AssignmentRepair, error) {
+	const op = "service.assignmentRepair.GetRepairs"
+
+	log := s.log.With(slog.String("op", op))
+
+	repairs, err := s.repairs.GetRepairs()
+	if err != nil {
+		log.Error("failed to get assignment repairs", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return repairs, nil
+}
+
+// queueRepair hands a PR stored without its reviewers to the repair worker
+// and reports whether it was queued.
+func (s *PullRequestService) queueRepair(log *slog.Logger, prID string, cause error) bool {
+	if err := s.repairs.QueueRepair(prID, cause.Error()); err != nil {
+		log.Error("failed to queue assignment repair", sl.Err(err))
+		return false
+	}
+
+	assignmentRepairsQueued.Add(1)
+	log.Warn("PR queued for assignment repair")
+	return true
+}
+
+// repairAssignment assigns reviewers to a PR stored without them. A PR whose
+// team holds assignment until green CI, or is frozen, is left to those
+// mechanisms instead. Finding nobody to assign fails the repair. The
+// reviewers are checked again under the PR's row lock when assigning, so a
+// PR given reviewers since the check keeps them.
+func (s *PullRequestService) repairAssignment(ctx context.Context, prID string, log *slog.Logger) ([]string, error) {
+	pr, reviewers, err := s.prRepo.GetPRWithReviewers(prID)
+	if err != nil {
+		return nil, err
+	}
+	if len(reviewers) > 0 {
+		return reviewers, nil
+	}
+
+	teamName, err := s.authorTeam(pr)
+	if err != nil {
+		return nil, err
+	}
+
+	policy, err := s.teamRepo.GetTeamPolicy(teamName)
+	if err != nil {
+		return nil, err
+	}
+
+	if policy.HoldUntilCIGreen && pr.CIStatus != models.CIStatusSuccess {
+		log.Info("reviewer assignment deferred until CI is green", slog.String("ci_status", pr.CIStatus))
+		return nil, nil
+	}
+
+	frozen, err := s.freezeRepo.IsFrozen(teamName)
+	if err != nil {
+		return nil, err
+	}
+	if frozen {
+		if err := s.freezeRepo.SetAssignmentQueued(prID, true); err != nil {
+			return nil, err
+		}
+		log.Info("reviewer assignment frozen, PR queued", slog.String("team_name", teamName))
+		return nil, nil
+	}
+
+	assigned, err := s.assignHeldReviewers(ctx, pr, teamName, log)
+	if err != nil {
+		return nil, err
+	}
+	if len(assigned) == 0 {
+		return nil, apperrors.ErrNoReviewerCandidates
+	}
+
+	return assigned, nil
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"pull-request-assigner/internal/domain/events"
+	"pull-request-assigner/internal/domain/models"
+	"pull-request-assigner/internal/lib/actor"
+	"pull-request-assigner/internal/lib/logger/sl"
+)
+
+type AuditRecorder interface {
+	RecordEvent(event models.AuditEvent) error
+}
+
+// recordAudit publishes an audit entry attributed to the caller from ctx.
+// The actor is resolved here because subscribers may not see the request ctx.
+func recordAudit(ctx context.Context, publisher events.Publisher, event models.AuditEvent) {
+	if event.ActorID == "" {
+		event.ActorID, _ = actor.UserID(ctx)
+	}
+
+	publisher.Publish(ctx, events.AuditRecorded{Entry: event})
+}
+
+// NewAuditSink returns the bus subscriber storing published audit entries.
+// Audit is best effort: a failure is logged but never fails the operation
+// itself.
+func NewAuditSink(log *slog.Logger, recorder AuditRecorder) events.Handler {
+	const op = "service.audit.Sink"
+
+	log = log.With(slog.String("op", op))
+
+	return func(_ context.Context, event events.Event) {
+		recorded, ok := event.(events.AuditRecorded)
+		if !ok {
+			return
+		}
+
+		if err := recorder.RecordEvent(recorded.Entry); err != nil {
+			log.Error("failed to record audit event",
+				slog.String("action", recorded.Entry.Action),
+				sl.Err(err))
+		}
+	}
+}
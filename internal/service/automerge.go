@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"pull-request-assigner/internal/apperrors"
+	"pull-request-assigner/internal/domain/events"
+	"pull-request-assigner/internal/lib/logger/sl"
+)
+
+// MaxAutoMergeApprovals bounds the approvals a PR may wait for before it is
+// merged automatically.
+const MaxAutoMergeApprovals = 10
+
+// AutoMerge merges every auto-merge PR that has reached its approval
+// threshold with green or untracked CI. PRs still blocked by the status
+// workflow, the security review gate or a closed merge window are left for a
+// later run.
+func (s *PullRequestService) AutoMerge(ctx context.Context) error {
+	const op = "service.pullRequest.AutoMerge"
+
+	log := s.log.With(slog.String("op", op))
+
+	prIDs, err := s.prRepo.GetAutoMergeReady()
+	if err != nil {
+		log.Error("failed to get PRs ready for auto-merge", sl.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	merged := 0
+	var failed []error
+	for _, prID := range prIDs {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		pr, _, alreadyMerged, _, err := s.MergePR(ctx, prID, false, "", false)
+		if err != nil {
+			if errors.Is(err, apperrors.ErrInvalidPRTransition) || errors.Is(err, apperrors.ErrSecurityApprovalRequired) ||
+				errors.Is(err, apperrors.ErrMergeWindowClosed) {
+				log.Info("auto-merge postponed", slog.String("pr_id", prID), sl.Err(err))
+				continue
+			}
+			failed = append(failed, err)
+			continue
+		}
+		if alreadyMerged {
+			continue
+		}
+
+		merged++
+		s.publisher.Publish(ctx, events.PullRequestAutoMerged{
+			PullRequestID: pr.PullRequestId,
+			AuthorID:      pr.AuthorID,
+			Approvals:     pr.AutoMergeApprovals,
+			MergedAt:      pr.MergedAt.Time,
+		})
+	}
+
+	if merged > 0 {
+		log.Info("PRs auto-merged", slog.Int("merged", merged))
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("%s: %w", op, errors.Join(failed...))
+	}
+
+	return nil
+}
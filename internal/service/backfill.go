@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"pull-request-assigner/internal/apperrors"
+	"pull-request-assigner/internal/domain/models"
+	"pull-request-assigner/internal/lib/forge"
+	"pull-request-assigner/internal/lib/logger/sl"
+)
+
+const (
+	BackfillSkipDraft         = "draft"
+	BackfillSkipUnknownAuthor = "author is not a known user"
+)
+
+// backfillSkipErrors are the creation failures that leave a single PR out of
+// a backfill instead of aborting it; the error text is the skip reason.
+var backfillSkipErrors = []error{
+	apperrors.ErrPRNameRequired,
+	apperrors.ErrPRAuthorNotFound,
+	apperrors.ErrPRTeamNotFound,
+	apperrors.ErrNoReviewerCandidates,
+	apperrors.ErrNoSecurityReviewer,
+	apperrors.ErrNoCertifiedReviewer,
+	apperrors.ErrInvalidReviewerTeams,
+	apperrors.ErrReviewerTeamNotFound,
+}
+
+type ForgeClient interface {
+	OpenPullRequests(ctx context.Context) ([]forge.PullRequest, error)
+}
+
+type BackfillUserProvider interface {
+	FindUserByUsername(username string) (models.User, error)
+}
+
+type BackfillPRCreator interface {
+	CreatePRWithReviewers(ctx context.Context, pr models.PullRequest) (*models.PullRequest, []string, error)
+}
+
+// BackfillService imports the open PRs of the configured forge, so a new
+// deployment starts with the reviews already in flight.
+type BackfillService struct {
+	log       *slog.Logger
+	forge     ForgeClient
+	userRepo  BackfillUserProvider
+	prService BackfillPRCreator
+	health    *IntegrationTracker
+}
+
+// NewBackfillService takes a nil forge client when no forge is configured.
+func NewBackfillService(
+	log *slog.Logger,
+	forge ForgeClient,
+	userRepo BackfillUserProvider,
+	prService BackfillPRCreator,
+	health *IntegrationTracker) *BackfillService {
+	return &BackfillService{
+		log:       log,
+		forge:     forge,
+		userRepo:  userRepo,
+		prService: prService,
+		health:    health,
+	}
+}
+
+// Backfill fetches every open PR before creating any, so a forge failure
+// imports nothing. Authors are matched to users by username; PRs that are
+// already known are counted as existing, which makes repeated runs safe.
+func (s *BackfillService) Backfill(ctx context.Context) (*models.BackfillResult, error) {
+	const op = "service.backfill.Backfill"
+
+	log := s.log.With(slog.String("op", op))
+
+	if s.forge == nil {
+		log.Warn("forge is not configured")
+		return nil, apperrors.ErrForgeNotConfigured
+	}
+
+	prs, err := s.forge.OpenPullRequests(ctx)
+	s.health.Observe(err)
+	if err != nil {
+		log.Error("failed to fetch open PRs from forge", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w: %w", op, apperrors.ErrForgeUnavailable, err)
+	}
+
+	result := &models.BackfillResult{
+		Fetched: len(prs),
+		Created: make([]string, 0),
+		Skipped: make([]models.BackfillSkip, 0),
+	}
+
+	skip := func(prID string, reason string) {
+		result.Skipped = append(result.Skipped, models.BackfillSkip{PullRequestId: prID, Reason: reason})
+	}
+
+	for _, imported := range prs {
+		if imported.Draft {
+			skip(imported.ID, BackfillSkipDraft)
+			continue
+		}
+
+		author, err := s.userRepo.FindUserByUsername(imported.AuthorLogin)
+		if err != nil {
+			if errors.Is(err, apperrors.ErrUserNotFound) {
+				skip(imported.ID, BackfillSkipUnknownAuthor)
+				continue
+			}
+			log.Error("failed to find PR author", sl.Err(err))
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		_, _, err = s.prService.CreatePRWithReviewers(ctx, models.PullRequest{
+			PullRequestId:   imported.ID,
+			PullRequestName: imported.Title,
+			AuthorID:        author.UserID,
+			Labels:          imported.Labels,
+			CreatedAt:       imported.CreatedAt.UTC(),
+		})
+		if err != nil {
+			if errors.Is(err, apperrors.ErrPRExists) {
+				result.Existing++
+				continue
+			}
+			if reason, ok := backfillSkipReason(err); ok {
+				skip(imported.ID, reason)
+				continue
+			}
+			log.Error("failed to create imported PR", slog.String("pr_id", imported.ID), sl.Err(err))
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		result.Created = append(result.Created, imported.ID)
+	}
+
+	log.Info("forge backfill finished",
+		slog.Int("fetched", result.Fetched),
+		slog.Int("created", len(result.Created)),
+		slog.Int("existing", result.Existing),
+		slog.Int("skipped", len(result.Skipped)))
+
+	return result, nil
+}
+
+func backfillSkipReason(err error) (string, bool) {
+	for _, skipErr := range backfillSkipErrors {
+		if errors.Is(err, skipErr) {
+			return skipErr.Error(), true
+		}
+	}
+
+	return "", false
+}
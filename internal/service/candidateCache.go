@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"expvar"
+	"log/slog"
+	"pull-request-assigner/internal/domain/events"
+	"pull-request-assigner/internal/domain/models"
+	"pull-request-assigner/internal/lib/logger/sl"
+	"slices"
+	"sync"
+	"time"
+)
+
+// CandidateCacheChannel is the Postgres notification channel on which
+// instances tell each other to drop their candidate caches.
+const CandidateCacheChannel = "candidate_cache_invalidated"
+
+var (
+	candidateCacheHits   = expvar.NewInt("candidate_cache_hits_total")
+	candidateCacheMisses = expvar.NewInt("candidate_cache_misses_total")
+)
+
+// membershipActions are the audit actions after which a cached member list
+// may be wrong. A member moving between teams is only recorded against the
+// new team, so any of them drops every entry.
+var membershipActions = []string{
+	models.AuditTeamCreated,
+	models.AuditTeamDeactivated,
+	models.AuditTeamArchived,
+	models.AuditTeamRestored,
+	models.AuditMemberAdded,
+	models.AuditMemberRemoved,
+	models.AuditMemberActivated,
+	models.AuditMemberDeactivated,
+	models.AuditMemberAuthorOnly,
+	models.AuditMemberReviewing,
+}
+
+type CacheNotifier interface {
+	Notify(channel, payload string) error
+}
+
+// CandidateCache keeps the active members of every team for a short TTL, so
+// bursts of PR creation do not query them again for each PR. Membership
+// changes published on the bus drop the cache at once and are announced on
+// CandidateCacheChannel, so the other instances drop theirs as well. Changes
+// made without an event, such as seeding, membership repair or an
+// author-only period running out, and notifications an instance missed are
+// picked up when the entry expires. A zero TTL disables caching.
+type CandidateCache struct {
+	log      *slog.Logger
+	ttl      time.Duration
+	notifier CacheNotifier
+	// instanceID marks the notifications of this instance, which has
+	// already dropped its own cache.
+	instanceID string
+
+	mu      sync.Mutex
+	entries map[string]candidateEntry
+	// generation changes on every invalidation, so a load that raced with
+	// one is not stored.
+	generation uint64
+}
+
+type candidateEntry struct {
+	members   []string
+	expiresAt time.Time
+}
+
+func NewCandidateCache(log *slog.Logger, ttl time.Duration, notifier CacheNotifier) *CandidateCache {
+	// Without an ID the instance merely drops its cache a second time on
+	// its own notifications.
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		buf = nil
+	}
+
+	return &CandidateCache{
+		log:        log,
+		ttl:        ttl,
+		notifier:   notifier,
+		instanceID: hex.EncodeToString(buf),
+		entries:    make(map[string]candidateEntry),
+	}
+}
+
+// Members returns the cached active members of the team, calling load on a
+// miss. The caller gets its own copy and may reorder it.
+func (c *CandidateCache) Members(teamName string, load func(teamName string) ([]string, error)) ([]string, error) {
+	if c == nil || c.ttl <= 0 {
+		return load(teamName)
+	}
+
+	now := time.Now()
+
+	c.mu.Lock()
+	entry, ok := c.entries[teamName]
+	generation := c.generation
+	c.mu.Unlock()
+
+	if ok && now.Before(entry.expiresAt) {
+		candidateCacheHits.Add(1)
+		return slices.Clone(entry.members), nil
+	}
+
+	candidateCacheMisses.Add(1)
+
+	members, err := load(teamName)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	if c.generation == generation {
+		c.entries[teamName] = candidateEntry{members: slices.Clone(members), expiresAt: now.Add(c.ttl)}
+	}
+	c.mu.Unlock()
+
+	return members, nil
+}
+
+func (c *CandidateCache) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	clear(c.entries)
+	c.generation++
+}
+
+// Handle is the bus subscriber dropping the cache on membership changes and
+// telling the other instances to do the same.
+func (c *CandidateCache) Handle(_ context.Context, event events.Event) {
+	const op = "service.candidateCache.Handle"
+
+	recorded, ok := event.(events.AuditRecorded)
+	if !ok || !slices.Contains(membershipActions, recorded.Entry.Action) {
+		return
+	}
+
+	c.Invalidate()
+
+	if err := c.notifier.Notify(CandidateCacheChannel, c.instanceID); err != nil {
+		// The other instances catch up when their entries expire.
+		c.log.Error("failed to notify other instances", slog.String("op", op), sl.Err(err))
+	}
+}
+
+// HandleNotification drops the cache on a notification from another
+// instance. An empty payload means notifications may have been missed.
+func (c *CandidateCache) HandleNotification(payload string) {
+	if payload == c.instanceID {
+		return
+	}
+
+	c.Invalidate()
+}
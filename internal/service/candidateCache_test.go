@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"pull-request-assigner/internal/domain/events"
+	"pull-request-assigner/internal/domain/models"
+	"testing"
+	"time"
+)
+
+// notifierFake records the payloads sent to other instances.
+type notifierFake struct {
+	payloads []string
+}
+
+func (f *notifierFake) Notify(channel, payload string) error {
+	f.payloads = append(f.payloads, payload)
+	return nil
+}
+
+func TestCandidateCacheNotifications(t *testing.T) {
+	notifier := &notifierFake{}
+	cache := NewCandidateCache(slog.New(slog.NewTextHandler(io.Discard, nil)), time.Minute, notifier)
+
+	loads := 0
+	load := func(teamName string) ([]string, error) {
+		loads++
+		return []string{"u1"}, nil
+	}
+	lookup := func() int {
+		t.Helper()
+		if _, err := cache.Members("Backend", load); err != nil {
+			t.Fatalf("failed to get members: %v", err)
+		}
+		return loads
+	}
+
+	lookup()
+	cache.Handle(context.Background(), events.AuditRecorded{Entry: models.AuditEvent{Action: models.AuditMemberDeactivated}})
+	if lookup() != 2 {
+		t.Fatalf("expected a membership change to drop the cache, got %d loads", loads)
+	}
+	if len(notifier.payloads) != 1 || notifier.payloads[0] != cache.instanceID {
+		t.Fatalf("expected one notification carrying the instance ID, got %v", notifier.payloads)
+	}
+
+	tests := []struct {
+		name    string
+		payload string
+		dropped bool
+	}{
+		{name: "own notification", payload: cache.instanceID},
+		{name: "other instance", payload: "0123456789abcdef", dropped: true},
+		{name: "reconnect", payload: "", dropped: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := lookup()
+			cache.HandleNotification(tt.payload)
+			if dropped := lookup() > before; dropped != tt.dropped {
+				t.Fatalf("expected dropped=%v, got %v", tt.dropped, dropped)
+			}
+		})
+	}
+}
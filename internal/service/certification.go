@@ -0,0 +1,239 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"pull-request-assigner/internal/apperrors"
+	"pull-request-assigner/internal/domain/models"
+	"pull-request-assigner/internal/lib/logger/sl"
+	"slices"
+	"strings"
+)
+
+type CertificationService struct {
+	log               *slog.Logger
+	certificationRepo CertificationStore
+}
+
+type CertificationProvider interface {
+	GetCertifiedAreas(userIDs []string) ([]models.Certification, error)
+	GetCertifiedReviewers(area string, excludeUserIDs []string) ([]string, error)
+}
+
+type CertificationStore interface {
+	GrantCertification(userID int, area string) (*models.Certification, error)
+	RevokeCertification(userID int, area string) error
+	ListCertifications(userID int, area string) ([]models.Certification, error)
+}
+
+func NewCertificationService(
+	log *slog.Logger,
+	certificationRepo CertificationStore) *CertificationService {
+	return &CertificationService{
+		log:               log,
+		certificationRepo: certificationRepo,
+	}
+}
+
+func (s *CertificationService) GrantCertification(ctx context.Context, userID string, area string) (*models.Certification, error) {
+	const op = "service.certification.GrantCertification"
+
+	area = normalizeArea(area)
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("user_id", userID),
+		slog.String("area", area),
+	)
+
+	log.Info("attempting to grant certification")
+
+	uid, err := models.ParseUserID(userID)
+	if err != nil {
+		log.Error("invalid user ID format", sl.Err(err))
+		return nil, apperrors.ErrInvalidUserID
+	}
+
+	if area == "" {
+		log.Error("area is required")
+		return nil, apperrors.ErrAreaRequired
+	}
+
+	certification, err := s.certificationRepo.GrantCertification(uid.Int(), area)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrUserNotFound) {
+			log.Warn("user not found")
+			return nil, apperrors.ErrUserNotFound
+		}
+		log.Error("failed to grant certification", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("certification granted")
+	return certification, nil
+}
+
+func (s *CertificationService) RevokeCertification(ctx context.Context, userID string, area string) error {
+	const op = "service.certification.RevokeCertification"
+
+	area = normalizeArea(area)
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("user_id", userID),
+		slog.String("area", area),
+	)
+
+	log.Info("attempting to revoke certification")
+
+	uid, err := models.ParseUserID(userID)
+	if err != nil {
+		log.Error("invalid user ID format", sl.Err(err))
+		return apperrors.ErrInvalidUserID
+	}
+
+	if area == "" {
+		log.Error("area is required")
+		return apperrors.ErrAreaRequired
+	}
+
+	err = s.certificationRepo.RevokeCertification(uid.Int(), area)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrCertificationNotFound) {
+			log.Warn("certification not found")
+			return apperrors.ErrCertificationNotFound
+		}
+		log.Error("failed to revoke certification", sl.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("certification revoked")
+	return nil
+}
+
+func (s *CertificationService) ListCertifications(ctx context.Context, userID string, area string) ([]models.Certification, error) {
+	const op = "service.certification.ListCertifications"
+
+	area = normalizeArea(area)
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("user_id", userID),
+		slog.String("area", area),
+	)
+
+	var uid models.UserID
+	if userID != "" {
+		var err error
+		uid, err = models.ParseUserID(userID)
+		if err != nil {
+			log.Error("invalid user ID format", sl.Err(err))
+			return nil, apperrors.ErrInvalidUserID
+		}
+	}
+
+	certifications, err := s.certificationRepo.ListCertifications(uid.Int(), area)
+	if err != nil {
+		log.Error("failed to list certifications", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("certifications listed", slog.Int("count", len(certifications)))
+	return certifications, nil
+}
+
+func normalizeArea(area string) string {
+	return strings.ToLower(strings.TrimSpace(area))
+}
+
+func normalizeAreas(areas []string) []string {
+	normalized := make([]string, 0, len(areas))
+	for _, area := range areas {
+		normalized = append(normalized, normalizeArea(area))
+	}
+	return models.MergeTags(nil, normalized)
+}
+
+// missingCertifications returns the PR's required areas that none of the
+// reviewers is certified for.
+func (s *PullRequestService) missingCertifications(pr *models.PullRequest, reviewers []string) ([]string, error) {
+	if len(pr.RequiredCertifications) == 0 {
+		return nil, nil
+	}
+
+	certifications, err := s.certRepo.GetCertifiedAreas(reviewers)
+	if err != nil {
+		return nil, err
+	}
+
+	covered := make(map[string]bool, len(certifications))
+	for _, certification := range certifications {
+		covered[certification.Area] = true
+	}
+
+	missing := make([]string, 0)
+	for _, area := range pr.RequiredCertifications {
+		if !covered[area] {
+			missing = append(missing, area)
+		}
+	}
+
+	return missing, nil
+}
+
+// addCertifiedReviewers extends reviewers with one random active certified
+// user for every required area they do not cover yet.
+func (s *PullRequestService) addCertifiedReviewers(ctx context.Context, pr *models.PullRequest, reviewers []string) ([]string, error) {
+	for {
+		missing, err := s.missingCertifications(pr, reviewers)
+		if err != nil {
+			return nil, err
+		}
+		if len(missing) == 0 {
+			return reviewers, nil
+		}
+
+		exclude := append([]string{pr.AuthorID}, reviewers...)
+		candidates, err := s.certRepo.GetCertifiedReviewers(missing[0], exclude)
+		if err != nil {
+			return nil, err
+		}
+		if len(candidates) == 0 {
+			return nil, fmt.Errorf("%w: %s", apperrors.ErrNoCertifiedReviewer, missing[0])
+		}
+
+		pool := slices.Clone(candidates)
+		picked := s.selectRandomReviewer(candidates)
+		traceCertifiedPick(ctx, missing[0], exclude, pool, picked)
+
+		reviewers = append(reviewers, picked)
+	}
+}
+
+// lostCertification returns a required area that the before reviewers cover
+// and the after reviewers do not, or an empty string.
+func (s *PullRequestService) lostCertification(pr *models.PullRequest, before []string, after []string) (string, error) {
+	if len(pr.RequiredCertifications) == 0 {
+		return "", nil
+	}
+
+	missingBefore, err := s.missingCertifications(pr, before)
+	if err != nil {
+		return "", err
+	}
+
+	missingAfter, err := s.missingCertifications(pr, after)
+	if err != nil {
+		return "", err
+	}
+
+	for _, area := range missingAfter {
+		if !slices.Contains(missingBefore, area) {
+			return area, nil
+		}
+	}
+
+	return "", nil
+}
@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"pull-request-assigner/internal/apperrors"
+	"pull-request-assigner/internal/domain/events"
+	"pull-request-assigner/internal/domain/models"
+	"pull-request-assigner/internal/lib/logger/sl"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultDashboardLimit = 100
+	MaxDashboardLimit     = 500
+)
+
+type DashboardStore interface {
+	ProjectPR(prID string) error
+	Rebuild() (int, error)
+	ListDashboard(filter models.DashboardFilter, overdueBefore *time.Time, after *models.DashboardCursor, limit int) ([]models.DashboardPR, error)
+}
+
+// DashboardService serves the dashboard from the dashboard_prs read model.
+// PR events on the bus refresh the row of their PR; changes made without an
+// event, such as renames, team moves or status reconciliation, are picked up
+// by the periodic Rebuild.
+type DashboardService struct {
+	log       *slog.Logger
+	store     DashboardStore
+	reviewSLA time.Duration
+}
+
+func NewDashboardService(log *slog.Logger, store DashboardStore, reviewSLA time.Duration) *DashboardService {
+	return &DashboardService{
+		log:       log,
+		store:     store,
+		reviewSLA: reviewSLA,
+	}
+}
+
+// Handle is the bus subscriber keeping the read model current. Like audit it
+// is best effort: a failed refresh is logged and fixed by the next Rebuild.
+func (s *DashboardService) Handle(_ context.Context, event events.Event) {
+	const op = "service.dashboard.Handle"
+
+	prID := dashboardPRID(event)
+	if prID == "" {
+		return
+	}
+
+	if err := s.store.ProjectPR(prID); err != nil {
+		s.log.Error("failed to refresh dashboard row",
+			slog.String("op", op),
+			slog.String("event", event.Name()),
+			slog.String("pull_request_id", prID),
+			sl.Err(err))
+	}
+}
+
+func (s *DashboardService) Rebuild(_ context.Context) error {
+	const op = "service.dashboard.Rebuild"
+
+	projected, err := s.store.Rebuild()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.log.Info("dashboard rebuilt", slog.String("op", op), slog.Int("rows", projected))
+	return nil
+}
+
+// ListDashboard returns a page of the dashboard ordered by due time and the
+// cursor of the next page, empty on the last one.
+func (s *DashboardService) ListDashboard(ctx context.Context, filter models.DashboardFilter, cursor string, limit int) ([]models.DashboardPR, string, error) {
+	const op = "service.dashboard.ListDashboard"
+
+	log := s.log.With(slog.String("op", op))
+
+	if limit == 0 {
+		limit = DefaultDashboardLimit
+	}
+	if limit < 0 || limit > MaxDashboardLimit {
+		log.Warn("invalid dashboard limit", slog.Int("limit", limit))
+		return nil, "", apperrors.ErrInvalidDashboardPage
+	}
+
+	var after *models.DashboardCursor
+	if cursor != "" {
+		decoded, err := decodeDashboardCursor(cursor)
+		if err != nil {
+			log.Warn("invalid dashboard cursor", sl.Err(err))
+			return nil, "", apperrors.ErrInvalidDashboardPage
+		}
+		after = &decoded
+	}
+
+	now := time.Now()
+
+	var overdueBefore *time.Time
+	if filter.Overdue {
+		createdBefore := now.Add(-s.reviewSLA)
+		overdueBefore = &createdBefore
+	}
+
+	page, err := s.store.ListDashboard(filter, overdueBefore, after, limit)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrInvalidUserID) {
+			log.Warn("invalid reviewer id", slog.String("reviewer_id", filter.ReviewerID))
+			return nil, "", apperrors.ErrInvalidUserID
+		}
+		log.Error("failed to list dashboard", sl.Err(err))
+		return nil, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	for i := range page {
+		if !page[i].Open {
+			continue
+		}
+		dueAt := page[i].CreatedAt.Add(s.reviewSLA)
+		page[i].DueAt = &dueAt
+		page[i].Overdue = now.After(dueAt)
+	}
+
+	next := ""
+	if len(page) == limit {
+		last := page[len(page)-1]
+		next = encodeDashboardCursor(models.DashboardCursor{CreatedAt: last.CreatedAt, PullRequestID: last.PullRequestId})
+	}
+
+	return page, next, nil
+}
+
+// dashboardPRID returns the PR an event changed, or "" for events that do not
+// touch a dashboard row.
+func dashboardPRID(event events.Event) string {
+	switch e := event.(type) {
+	case events.PullRequestCreated:
+		return e.PullRequestID
+	case events.PullRequestMerged:
+		return e.PullRequestID
+	case events.PullRequestStatusChanged:
+		return e.PullRequestID
+	case events.PullRequestCIStatusChanged:
+		return e.PullRequestID
+	case events.ReviewersReleased:
+		return e.PullRequestID
+	case events.ReviewerAssigned:
+		return e.PullRequestID
+	case events.ReviewerReassigned:
+		return e.PullRequestID
+	case events.ReviewerDelegated:
+		return e.PullRequestID
+	case events.ReviewerUnassigned:
+		return e.PullRequestID
+	case events.ReviewApproved:
+		return e.PullRequestID
+	}
+
+	return ""
+}
+
+func encodeDashboardCursor(cursor models.DashboardCursor) string {
+	raw := strconv.FormatInt(cursor.CreatedAt.UnixNano(), 10) + ":" + cursor.PullRequestID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeDashboardCursor(cursor string) (models.DashboardCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return models.DashboardCursor{}, err
+	}
+
+	nanos, prID, ok := strings.Cut(string(raw), ":")
+	if !ok || prID == "" {
+		return models.DashboardCursor{}, errors.New("malformed cursor")
+	}
+
+	unixNano, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return models.DashboardCursor{}, err
+	}
+
+	return models.DashboardCursor{CreatedAt: time.Unix(0, unixNano).UTC(), PullRequestID: prID}, nil
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"pull-request-assigner/internal/domain/models"
+	"pull-request-assigner/internal/lib/trace"
+	"slices"
+)
+
+// exclusionRule names users who must not review pr under the author team's
+// policy; reason is reported for them in assignment traces.
+type exclusionRule struct {
+	reason  string
+	exclude func(pr *models.PullRequest, policy *models.TeamPolicy) []string
+}
+
+var exclusionRules = []exclusionRule{
+	{reason: models.ExclusionAuthor, exclude: excludeAuthor},
+	{reason: models.ExclusionCoAuthor, exclude: excludeCoAuthors},
+	{reason: models.ExclusionPairingSession, exclude: excludePairingSession},
+}
+
+func excludeAuthor(pr *models.PullRequest, _ *models.TeamPolicy) []string {
+	return []string{pr.AuthorID}
+}
+
+func excludeCoAuthors(pr *models.PullRequest, policy *models.TeamPolicy) []string {
+	if !policy.ExcludeCoAuthors {
+		return nil
+	}
+	return pr.CoAuthors
+}
+
+func excludePairingSession(pr *models.PullRequest, policy *models.TeamPolicy) []string {
+	if !policy.ExcludePairingSession {
+		return nil
+	}
+	return pr.PairingSession
+}
+
+// applyExclusionRules returns the deduplicated users excluded from reviewing
+// pr. Current reviewers are not included; callers add them where relevant.
+func applyExclusionRules(ctx context.Context, pr *models.PullRequest, policy *models.TeamPolicy) []string {
+	t := trace.Assignment(ctx)
+	seen := make(map[string]bool)
+	excluded := make([]string, 0, 1)
+
+	for _, rule := range exclusionRules {
+		for _, userID := range rule.exclude(pr, policy) {
+			if userID == "" || seen[userID] {
+				continue
+			}
+			seen[userID] = true
+			excluded = append(excluded, userID)
+
+			if t != nil {
+				t.Exclusions = append(t.Exclusions, models.ReviewerExclusion{UserID: userID, Reason: rule.reason})
+			}
+		}
+	}
+
+	return excluded
+}
+
+// excludedReviewers loads the policy of the author's team and applies the
+// exclusion rules to pr. The policy is returned for the pick that follows.
+func (s *PullRequestService) excludedReviewers(ctx context.Context, pr *models.PullRequest, authorTeam string) ([]string, *models.TeamPolicy, error) {
+	const op = "service.pullRequest.excludedReviewers"
+
+	policy, err := s.teamRepo.GetTeamPolicy(authorTeam)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	excluded, err := s.excludeDailyCapped(ctx, applyExclusionRules(ctx, pr, policy))
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return excluded, policy, nil
+}
+
+// excludeDailyCapped adds the users who reached their daily assignment cap to
+// excluded. Users excluded already keep their first reason in the trace.
+func (s *PullRequestService) excludeDailyCapped(ctx context.Context, excluded []string) ([]string, error) {
+	capped, err := s.prRepo.GetDailyCappedUsers()
+	if err != nil {
+		return nil, err
+	}
+
+	t := trace.Assignment(ctx)
+	for _, userID := range capped {
+		if slices.Contains(excluded, userID) {
+			continue
+		}
+		excluded = append(excluded, userID)
+
+		if t != nil {
+			t.Exclusions = append(t.Exclusions, models.ReviewerExclusion{UserID: userID, Reason: models.ExclusionDailyCap})
+		}
+	}
+
+	return excluded, nil
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"pull-request-assigner/internal/domain/events"
+	"pull-request-assigner/internal/domain/models"
+	"pull-request-assigner/internal/lib/logger/sl"
+	"time"
+)
+
+type ReviewerShareProvider interface {
+	GetReviewerShares(since time.Time) ([]models.ReviewerShare, error)
+}
+
+// FairnessService watches how evenly reviews are spread inside each team and
+// raises an ASSIGNMENT_SKEW audit event when a single member takes more than
+// the configured share of the team's assignments.
+type FairnessService struct {
+	log            *slog.Logger
+	statsRepo      ReviewerShareProvider
+	publisher      events.Publisher
+	window         time.Duration
+	threshold      float64
+	minAssignments int
+}
+
+func NewFairnessService(
+	log *slog.Logger,
+	statsRepo ReviewerShareProvider,
+	publisher events.Publisher,
+	window time.Duration,
+	threshold float64,
+	minAssignments int) *FairnessService {
+	return &FairnessService{
+		log:            log,
+		statsRepo:      statsRepo,
+		publisher:      publisher,
+		window:         window,
+		threshold:      threshold,
+		minAssignments: minAssignments,
+	}
+}
+
+func (s *FairnessService) CheckAssignmentSkew(ctx context.Context) error {
+	const op = "service.fairness.CheckAssignmentSkew"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Duration("window", s.window),
+	)
+
+	shares, err := s.statsRepo.GetReviewerShares(time.Now().Add(-s.window))
+	if err != nil {
+		log.Error("failed to get reviewer shares", sl.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	skewed := 0
+	for _, share := range shares {
+		if share.TeamMembers < 2 || share.TeamAssignments < s.minAssignments {
+			continue
+		}
+
+		share.Share = float64(share.Assignments) / float64(share.TeamAssignments)
+		if share.Share <= s.threshold {
+			continue
+		}
+
+		skewed++
+		log.Warn("assignment skew detected",
+			slog.String("team_name", share.TeamName),
+			slog.String("user_id", share.UserID),
+			slog.Int("assignments", share.Assignments),
+			slog.Int("team_assignments", share.TeamAssignments),
+			slog.Float64("share", share.Share))
+
+		recordAudit(ctx, s.publisher, models.AuditEvent{
+			TeamName:  share.TeamName,
+			Action:    models.AuditAssignmentSkew,
+			SubjectID: share.UserID,
+			Details: fmt.Sprintf("%d of %d assignments (%.0f%%) over the last %s",
+				share.Assignments, share.TeamAssignments, share.Share*100, s.window),
+		})
+	}
+
+	log.Debug("assignment skew checked", slog.Int("skewed", skewed))
+
+	return nil
+}
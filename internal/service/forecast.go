@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"pull-request-assigner/internal/apperrors"
+	"pull-request-assigner/internal/domain/models"
+	"pull-request-assigner/internal/lib/logger/sl"
+	"time"
+)
+
+// forecastHistory is how far back the team PR creation rate is measured.
+const forecastHistory = 28 * 24 * time.Hour
+
+// GetReviewForecast estimates the user's review load for the coming week:
+// the reviews already open plus the PRs their team is expected to create,
+// each picking the user with the probability of the random team strategy.
+// Pools, label teams and exclusion rules are not modelled.
+func (s *UserService) GetReviewForecast(ctx context.Context, userID string) (*models.ReviewForecast, error) {
+	const op = "service.user.GetReviewForecast"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("userID", userID),
+	)
+
+	id, err := models.ParseUserID(userID)
+	if err != nil {
+		log.Error("invalid user ID format", sl.Err(err))
+		return nil, err
+	}
+
+	inputs, err := s.userProvider.GetForecastInputs(id.Int(), time.Now().Add(-forecastHistory))
+	if err != nil {
+		if errors.Is(err, apperrors.ErrUserNotFound) {
+			log.Warn("user not found")
+			return nil, apperrors.ErrUserNotFound
+		}
+		log.Error("failed to get forecast inputs", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	weeks := forecastHistory.Hours() / (7 * 24)
+	prsPerWeek := float64(inputs.TeamPRs) / weeks
+	probability := selectionProbability(inputs)
+	expected := prsPerWeek * probability
+
+	return &models.ReviewForecast{
+		UserID:               id.String(),
+		TeamName:             inputs.TeamName,
+		HistoryDays:          int(forecastHistory.Hours() / 24),
+		OpenReviews:          inputs.OpenReviews,
+		TeamPRsPerWeek:       roundForecast(prsPerWeek),
+		SelectionProbability: roundForecast(probability),
+		ExpectedNewReviews:   roundForecast(expected),
+		ExpectedLoad:         roundForecast(float64(inputs.OpenReviews) + expected),
+	}, nil
+}
+
+// selectionProbability is the chance a teammate's PR picks the user: the
+// team's reviewers per PR drawn from the active members but the author.
+func selectionProbability(inputs models.ForecastInputs) float64 {
+	candidates := inputs.ActiveMembers - 1
+	if !inputs.IsActive || candidates <= 0 {
+		return 0
+	}
+	return math.Min(1, float64(inputs.ReviewersPerPR)/float64(candidates))
+}
+
+func roundForecast(value float64) float64 {
+	return math.Round(value*100) / 100
+}
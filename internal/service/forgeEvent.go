@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"pull-request-assigner/internal/apperrors"
+	"pull-request-assigner/internal/domain/models"
+	"pull-request-assigner/internal/lib/forge"
+	"pull-request-assigner/internal/lib/logger/sl"
+)
+
+// Reasons a forge webhook delivery is acknowledged without a change.
+const (
+	ForgeIgnoreNotMerge  = "not a merge"
+	ForgeIgnoreUnknownPR = "pull request is not tracked"
+)
+
+type ExternalMerger interface {
+	MergeExternally(ctx context.Context, prID string, mergedBy string, mergedByLogin string) (*models.PullRequest, bool, error)
+}
+
+// ForgeEventService applies the webhooks of the configured forge. Only
+// merges are acted on: a PR merged on the forge goes through the regular
+// merge flow, so its reviewers are released and subscribers are notified.
+type ForgeEventService struct {
+	log      *slog.Logger
+	kind     string
+	secret   string
+	userRepo BackfillUserProvider
+	merger   ExternalMerger
+}
+
+// NewForgeEventService takes an empty secret when forge webhooks are not
+// set up; every delivery is then refused.
+func NewForgeEventService(
+	log *slog.Logger,
+	kind string,
+	secret string,
+	userRepo BackfillUserProvider,
+	merger ExternalMerger) *ForgeEventService {
+	return &ForgeEventService{
+		log:      log,
+		kind:     kind,
+		secret:   secret,
+		userRepo: userRepo,
+		merger:   merger,
+	}
+}
+
+func (s *ForgeEventService) HandleEvent(ctx context.Context, header http.Header, body []byte) (*models.ForgeEventResult, error) {
+	const op = "service.forgeEvent.HandleEvent"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("forge", s.kind),
+	)
+
+	if s.kind == "" || s.secret == "" {
+		log.Warn("forge webhooks are not configured")
+		return nil, apperrors.ErrForgeNotConfigured
+	}
+
+	event, err := forge.ParseMergeEvent(s.kind, s.secret, header, body)
+	if err != nil {
+		switch {
+		case errors.Is(err, forge.ErrInvalidSignature):
+			log.Warn("forge webhook signature does not match")
+			return nil, apperrors.ErrInvalidSignature
+		case errors.Is(err, forge.ErrMalformedEvent):
+			log.Warn("malformed forge webhook", sl.Err(err))
+			return nil, fmt.Errorf("%w: %w", apperrors.ErrInvalidForgeEvent, err)
+		default:
+			log.Error("failed to parse forge webhook", sl.Err(err))
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if event == nil {
+		log.Debug("forge webhook is not a merge")
+		return &models.ForgeEventResult{Action: models.ForgeEventIgnored, Reason: ForgeIgnoreNotMerge}, nil
+	}
+
+	log = log.With(slog.String("pr_id", event.PullRequestID))
+
+	// Forge accounts without a user leave merged_by empty.
+	mergedBy := ""
+	if event.MergedByLogin != "" {
+		user, err := s.userRepo.FindUserByUsername(event.MergedByLogin)
+		switch {
+		case err == nil:
+			mergedBy = user.UserID
+		case errors.Is(err, apperrors.ErrUserNotFound):
+			log.Info("forge merger has no user", slog.String("login", event.MergedByLogin))
+		default:
+			log.Error("failed to find forge merger", sl.Err(err))
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	pr, alreadyMerged, err := s.merger.MergeExternally(ctx, event.PullRequestID, mergedBy, event.MergedByLogin)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrPRNotFound) {
+			log.Info("merged PR is not tracked")
+			return &models.ForgeEventResult{
+				Action:        models.ForgeEventIgnored,
+				PullRequestID: event.PullRequestID,
+				Reason:        ForgeIgnoreUnknownPR,
+			}, nil
+		}
+		log.Error("failed to record external merge", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	result := &models.ForgeEventResult{
+		Action:        models.ForgeEventMerged,
+		PullRequestID: pr.PullRequestId,
+		MergedBy:      pr.MergedBy,
+	}
+	if alreadyMerged {
+		result.Action = models.ForgeEventAlreadyMerged
+	}
+
+	log.Info("forge merge applied", slog.String("action", result.Action))
+	return result, nil
+}
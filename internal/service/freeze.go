@@ -0,0 +1,299 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"pull-request-assigner/internal/apperrors"
+	"pull-request-assigner/internal/domain/events"
+	"pull-request-assigner/internal/domain/models"
+	"pull-request-assigner/internal/lib/logger/sl"
+	"strings"
+	"time"
+)
+
+type FreezeProvider interface {
+	CreateFreeze(teamName string, reason string, startsAt time.Time, endsAt *time.Time) (*models.AssignmentFreeze, error)
+	LiftFreezes(teamName string) (int, error)
+	GetFreezes() ([]models.AssignmentFreeze, error)
+	IsFrozen(teamName string) (bool, error)
+	SetAssignmentQueued(prID string, queued bool) error
+	GetReleasableQueued() ([]string, error)
+	GetPendingAssignments(teamName string) ([]models.PendingAssignment, error)
+}
+
+// FreezeAssignments schedules an assignment freeze for the team, or for all
+// teams when teamName is empty. A zero startsAt starts it immediately; a nil
+// endsAt keeps it until it is lifted.
+func (s *PullRequestService) FreezeAssignments(ctx context.Context, teamName string, reason string, startsAt time.Time, endsAt *time.Time) (*models.AssignmentFreeze, error) {
+	const op = "service.pullRequest.FreezeAssignments"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("team_name", teamName),
+	)
+
+	now := time.Now()
+	if startsAt.IsZero() {
+		startsAt = now
+	}
+
+	if endsAt != nil && (!endsAt.After(startsAt) || !endsAt.After(now)) {
+		log.Warn("freeze must end after it starts and in the future")
+		return nil, apperrors.ErrInvalidFreeze
+	}
+
+	startsAt = startsAt.UTC()
+	if endsAt != nil {
+		utc := endsAt.UTC()
+		endsAt = &utc
+	}
+
+	freeze, err := s.freezeRepo.CreateFreeze(teamName, strings.TrimSpace(reason), startsAt, endsAt)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrTeamNotFound) {
+			log.Warn("team not found")
+			return nil, apperrors.ErrTeamNotFound
+		}
+		log.Error("failed to create freeze", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	recordAudit(ctx, s.publisher, models.AuditEvent{
+		TeamName: teamName,
+		Action:   models.AuditAssignmentFrozen,
+		Details:  freeze.Reason,
+	})
+
+	log.Info("assignment freeze created", slog.Int64("freeze_id", freeze.FreezeID))
+	return freeze, nil
+}
+
+// UnfreezeAssignments lifts the team's freezes, or all freezes when teamName
+// is empty, and assigns reviewers to the queued PRs that are no longer frozen.
+func (s *PullRequestService) UnfreezeAssignments(ctx context.Context, teamName string) (*models.UnfreezeResult, error) {
+	const op = "service.pullRequest.UnfreezeAssignments"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("team_name", teamName),
+	)
+
+	lifted, err := s.freezeRepo.LiftFreezes(teamName)
+	if err != nil {
+		log.Error("failed to lift freezes", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if lifted > 0 {
+		recordAudit(ctx, s.publisher, models.AuditEvent{
+			TeamName: teamName,
+			Action:   models.AuditAssignmentUnfrozen,
+		})
+	}
+
+	released, err := s.releaseQueued(ctx)
+	if err != nil {
+		log.Error("failed to release queued PRs", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("assignment freezes lifted",
+		slog.Int("lifted", lifted),
+		slog.Int("released", len(released)))
+
+	return &models.UnfreezeResult{Lifted: lifted, Released: released}, nil
+}
+
+func (s *PullRequestService) GetFreezes(ctx context.Context) ([]models.AssignmentFreeze, error) {
+	const op = "service.pullRequest.GetFreezes"
+
+	freezes, err := s.freezeRepo.GetFreezes()
+	if err != nil {
+		s.log.Error("failed to get freezes", slog.String("op", op), sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return freezes, nil
+}
+
+// GetPendingAssignments lists the PRs queued by assignment freezes for the
+// team, or for every team when teamName is empty. The estimated wait runs to
+// the end of the freezes; PRs no longer frozen wait for the next release run.
+func (s *PullRequestService) GetPendingAssignments(ctx context.Context, teamName string) ([]models.PendingAssignment, error) {
+	const op = "service.pullRequest.GetPendingAssignments"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("team_name", teamName),
+	)
+
+	if teamName != "" {
+		exists, err := s.teamRepo.TeamExists(teamName)
+		if err != nil {
+			log.Error("failed to check team", sl.Err(err))
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		if !exists {
+			log.Warn("team not found")
+			return nil, apperrors.ErrTeamNotFound
+		}
+	}
+
+	pending, err := s.freezeRepo.GetPendingAssignments(teamName)
+	if err != nil {
+		log.Error("failed to get pending assignments", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	now := time.Now()
+	for i := range pending {
+		switch {
+		case !pending[i].Frozen:
+			wait := int64(0)
+			pending[i].EstimatedWait = &wait
+		case pending[i].FrozenUntil != nil:
+			wait := int64(max(pending[i].FrozenUntil.Sub(now), 0).Seconds())
+			pending[i].EstimatedWait = &wait
+		}
+	}
+
+	log.Info("pending assignments retrieved", slog.Int("count", len(pending)))
+
+	return pending, nil
+}
+
+// ReleaseQueued assigns reviewers to PRs queued by a freeze that has since
+// ended or been lifted.
+func (s *PullRequestService) ReleaseQueued(ctx context.Context) error {
+	const op = "service.pullRequest.ReleaseQueued"
+
+	released, err := s.releaseQueued(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(released) > 0 {
+		s.log.Info("queued PRs released", slog.String("op", op), slog.Int("released", len(released)))
+	}
+
+	return nil
+}
+
+func (s *PullRequestService) releaseQueued(ctx context.Context) ([]string, error) {
+	prIDs, err := s.freezeRepo.GetReleasableQueued()
+	if err != nil {
+		return nil, err
+	}
+
+	released := make([]string, 0, len(prIDs))
+	var failed []error
+	for _, prID := range prIDs {
+		if ctx.Err() != nil {
+			return released, ctx.Err()
+		}
+
+		assigned, err := s.releaseQueuedPR(ctx, prID)
+		if err != nil {
+			failed = append(failed, err)
+			continue
+		}
+		if assigned {
+			released = append(released, prID)
+		}
+	}
+
+	return released, errors.Join(failed...)
+}
+
+// releaseQueuedPR takes the PR off the queue and assigns its reviewers,
+// unless the team still holds assignment until CI is green; the CI update
+// assigns them then.
+func (s *PullRequestService) releaseQueuedPR(ctx context.Context, prID string) (bool, error) {
+	const op = "service.pullRequest.releaseQueuedPR"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("pr_id", prID),
+	)
+
+	pr, reviewers, err := s.prRepo.GetPRWithReviewers(prID)
+	if err != nil {
+		log.Error("failed to get PR", sl.Err(err))
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	teamName, err := s.authorTeam(pr)
+	if err != nil {
+		log.Error("failed to get author team", sl.Err(err))
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	policy, err := s.teamRepo.GetTeamPolicy(teamName)
+	if err != nil {
+		log.Error("failed to get team policy", sl.Err(err))
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var assigned []string
+	if len(reviewers) == 0 && (!policy.HoldUntilCIGreen || pr.CIStatus == models.CIStatusSuccess) {
+		assigned, err = s.assignHeldReviewers(ctx, pr, teamName, log)
+		if err != nil {
+			return false, fmt.Errorf("%s: %w", op, err)
+		}
+		if len(assigned) == 0 {
+			log.Warn("freeze is over but no active team members available for review")
+		}
+	}
+
+	if err := s.freezeRepo.SetAssignmentQueued(prID, false); err != nil {
+		log.Error("failed to dequeue PR", sl.Err(err))
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return len(assigned) > 0, nil
+}
+
+// assignHeldReviewers picks and assigns reviewers for a PR created without
+// them, either held until green CI or queued by a freeze. Finding nobody is
+// not an error: the PR is left without reviewers. A PR given reviewers by
+// another path meanwhile keeps them, and they are returned.
+func (s *PullRequestService) assignHeldReviewers(ctx context.Context, pr *models.PullRequest, teamName string, log *slog.Logger) ([]string, error) {
+	excluded, policy, err := s.excludedReviewers(ctx, pr, teamName)
+	if err != nil {
+		log.Error("failed to apply exclusion rules", sl.Err(err))
+		return nil, err
+	}
+
+	held, err := s.selectTeamReviewers(ctx, pr, policy, excluded, teamName, log)
+	if err != nil && !errors.Is(err, apperrors.ErrNoReviewerCandidates) {
+		log.Error("failed to select reviewers", sl.Err(err))
+		return nil, err
+	}
+
+	if len(held) == 0 {
+		return nil, nil
+	}
+
+	withCertified, err := s.addCertifiedReviewers(ctx, pr, held)
+	if err != nil {
+		log.Warn("failed to add certified reviewers", sl.Err(err))
+	} else {
+		held = withCertified
+	}
+
+	if err := s.prRepo.AddReviewersToEmptyPR(pr.PullRequestId, held); err != nil {
+		if errors.Is(err, apperrors.ErrPRHasReviewers) {
+			log.Info("PR got reviewers meanwhile, held reviewers not assigned")
+			_, current, err := s.prRepo.GetPRWithReviewers(pr.PullRequestId)
+			return current, err
+		}
+		log.Error("failed to add PR reviewers", sl.Err(err))
+		return nil, err
+	}
+
+	s.publisher.Publish(ctx, events.ReviewersReleased{PullRequestID: pr.PullRequestId, Reviewers: held})
+
+	return held, nil
+}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/url"
+	"pull-request-assigner/internal/domain/models"
+	"pull-request-assigner/internal/lib/logger/sl"
+	"sync"
+	"time"
+)
+
+// Names of the integrations reported by the health check.
+const (
+	IntegrationDatabase = "database"
+	IntegrationForge    = "forge"
+	IntegrationSIEM     = "siem"
+	IntegrationWebhooks = "webhooks"
+)
+
+const databasePingTimeout = 2 * time.Second
+
+// IntegrationTracker remembers the outcome of the calls made to one external
+// dependency since the service started. A nil tracker ignores observations,
+// so callers do not need to check whether health tracking is wired.
+type IntegrationTracker struct {
+	name       string
+	configured bool
+
+	mu            sync.Mutex
+	lastSuccessAt time.Time
+	lastFailureAt time.Time
+	lastError     string
+	failures      int
+	dropped       int64
+}
+
+func NewIntegrationTracker(name string, configured bool) *IntegrationTracker {
+	return &IntegrationTracker{
+		name:       name,
+		configured: configured,
+	}
+}
+
+// Observe records the result of one call: nil is a success and resets the
+// failure streak.
+func (t *IntegrationTracker) Observe(err error) {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	if err == nil {
+		t.lastSuccessAt = now
+		t.failures = 0
+		return
+	}
+
+	t.lastFailureAt = now
+	t.lastError = integrationError(err)
+	t.failures++
+}
+
+// ObserveDropped records n items the integration gave up on.
+func (t *IntegrationTracker) ObserveDropped(n int) {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.dropped += int64(n)
+}
+
+// integrationError drops the URL from transport errors: webhook URLs often
+// carry credentials and the report is readable with a read key.
+func integrationError(err error) string {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Op + ": " + urlErr.Err.Error()
+	}
+	return err.Error()
+}
+
+func (t *IntegrationTracker) snapshot() models.IntegrationHealth {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	health := models.IntegrationHealth{
+		Name:                t.name,
+		LastError:           t.lastError,
+		ConsecutiveFailures: t.failures,
+		Dropped:             t.dropped,
+	}
+	if !t.lastSuccessAt.IsZero() {
+		at := t.lastSuccessAt
+		health.LastSuccessAt = &at
+	}
+	if !t.lastFailureAt.IsZero() {
+		at := t.lastFailureAt
+		health.LastFailureAt = &at
+	}
+
+	switch {
+	case !t.configured:
+		health.Status = models.HealthNotConfigured
+	case t.failures > 0:
+		health.Status = models.HealthFailing
+	case t.lastSuccessAt.IsZero():
+		health.Status = models.HealthUnknown
+	default:
+		health.Status = models.HealthOK
+	}
+
+	return health
+}
+
+type DatabasePinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// HealthService reports the state of the external dependencies. The
+// database is pinged on every check; the other integrations are only seen
+// through the calls the service makes to them anyway.
+type HealthService struct {
+	log      *slog.Logger
+	db       DatabasePinger
+	database *IntegrationTracker
+	trackers []*IntegrationTracker
+}
+
+func NewHealthService(log *slog.Logger, db DatabasePinger, trackers ...*IntegrationTracker) *HealthService {
+	database := NewIntegrationTracker(IntegrationDatabase, true)
+
+	return &HealthService{
+		log:      log,
+		db:       db,
+		database: database,
+		trackers: append([]*IntegrationTracker{database}, trackers...),
+	}
+}
+
+// CheckIntegrations reports every integration. The overall status is
+// degraded when any configured integration is failing; integrations that
+// were not called yet do not count against it.
+func (s *HealthService) CheckIntegrations(ctx context.Context) *models.IntegrationsReport {
+	const op = "service.health.CheckIntegrations"
+
+	log := s.log.With(slog.String("op", op))
+
+	pingCtx, cancel := context.WithTimeout(ctx, databasePingTimeout)
+	defer cancel()
+
+	err := s.db.PingContext(pingCtx)
+	if err != nil {
+		log.Error("database ping failed", sl.Err(err))
+	}
+	s.database.Observe(err)
+
+	report := &models.IntegrationsReport{
+		Status:       models.HealthOK,
+		CheckedAt:    time.Now(),
+		Integrations: make([]models.IntegrationHealth, 0, len(s.trackers)),
+	}
+
+	for _, tracker := range s.trackers {
+		health := tracker.snapshot()
+		if health.Status == models.HealthFailing {
+			report.Status = models.HealthDegraded
+		}
+		report.Integrations = append(report.Integrations, health)
+	}
+
+	return report
+}
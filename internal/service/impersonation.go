@@ -0,0 +1,205 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"pull-request-assigner/internal/apperrors"
+	"pull-request-assigner/internal/domain/events"
+	"pull-request-assigner/internal/domain/models"
+	"pull-request-assigner/internal/lib/actor"
+	"pull-request-assigner/internal/lib/logger/sl"
+	"strings"
+	"time"
+)
+
+// impersonationPrefix marks impersonation keys so they are not mistaken for
+// API keys.
+const impersonationPrefix = "imp_"
+
+type ImpersonationProvider interface {
+	CreateSession(sessionHash string, adminID int, userID int, reason string, expiresAt time.Time) (*models.ImpersonationSession, error)
+	EndSession(sessionID int64) (*models.ImpersonationSession, error)
+	GetActiveSession(sessionHash string) (*models.ImpersonationSession, error)
+}
+
+type UserGetter interface {
+	GetUser(userID int) (models.User, error)
+}
+
+// ImpersonationService lets admins see the service as a given user for a
+// limited time. Every session start and end is recorded in the audit log.
+type ImpersonationService struct {
+	log         *slog.Logger
+	sessionRepo ImpersonationProvider
+	userRepo    UserGetter
+	publisher   events.Publisher
+	ttl         time.Duration
+}
+
+func NewImpersonationService(
+	log *slog.Logger,
+	sessionRepo ImpersonationProvider,
+	userRepo UserGetter,
+	publisher events.Publisher,
+	ttl time.Duration) *ImpersonationService {
+	return &ImpersonationService{
+		log:         log,
+		sessionRepo: sessionRepo,
+		userRepo:    userRepo,
+		publisher:   publisher,
+		ttl:         ttl,
+	}
+}
+
+// StartImpersonation opens a session in which the calling admin acts as
+// userID. The returned key is shown only once; only its hash is stored.
+func (s *ImpersonationService) StartImpersonation(ctx context.Context, userID string, reason string) (*models.ImpersonationSession, string, error) {
+	const op = "service.impersonation.StartImpersonation"
+
+	adminID, _ := actor.UserID(ctx)
+	reason = strings.TrimSpace(reason)
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("admin_id", adminID),
+		slog.String("user_id", userID),
+	)
+
+	log.Info("attempting to start impersonation")
+
+	if adminID == "" {
+		log.Error("admin identity is required")
+		return nil, "", apperrors.ErrImpersonatorRequired
+	}
+
+	adminUID, err := models.ParseUserID(adminID)
+	if err != nil {
+		log.Error("invalid admin ID format", sl.Err(err))
+		return nil, "", apperrors.ErrImpersonatorRequired
+	}
+
+	uid, err := models.ParseUserID(userID)
+	if err != nil {
+		log.Error("invalid user ID format", sl.Err(err))
+		return nil, "", apperrors.ErrInvalidUserID
+	}
+
+	if reason == "" {
+		log.Error("impersonation reason is required")
+		return nil, "", apperrors.ErrImpersonationReasonRequired
+	}
+
+	if adminUID == uid {
+		log.Warn("admin tried to impersonate themselves")
+		return nil, "", apperrors.ErrSelfImpersonation
+	}
+
+	user, err := s.userRepo.GetUser(uid.Int())
+	if err != nil {
+		if errors.Is(err, apperrors.ErrUserNotFound) {
+			log.Warn("user not found")
+			return nil, "", apperrors.ErrUserNotFound
+		}
+		log.Error("failed to get user", sl.Err(err))
+		return nil, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := s.userRepo.GetUser(adminUID.Int()); err != nil {
+		if errors.Is(err, apperrors.ErrUserNotFound) {
+			log.Warn("admin not found")
+			return nil, "", apperrors.ErrImpersonatorRequired
+		}
+		log.Error("failed to get admin", sl.Err(err))
+		return nil, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	key, err := generateImpersonationKey()
+	if err != nil {
+		log.Error("failed to generate impersonation key", sl.Err(err))
+		return nil, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	session, err := s.sessionRepo.CreateSession(hashTokenKey(key), adminUID.Int(), uid.Int(), reason, time.Now().Add(s.ttl))
+	if err != nil {
+		log.Error("failed to create impersonation session", sl.Err(err))
+		return nil, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	recordAudit(ctx, s.publisher, models.AuditEvent{
+		TeamName:  user.TeamName,
+		Action:    models.AuditImpersonationStarted,
+		SubjectID: user.UserID,
+		Details:   fmt.Sprintf("session %d: %s", session.SessionID, reason),
+	})
+
+	log.Info("impersonation started", slog.Int64("session_id", session.SessionID))
+	return session, key, nil
+}
+
+func (s *ImpersonationService) EndImpersonation(ctx context.Context, sessionID int64) (*models.ImpersonationSession, error) {
+	const op = "service.impersonation.EndImpersonation"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("session_id", sessionID),
+	)
+
+	log.Info("attempting to end impersonation")
+
+	session, err := s.sessionRepo.EndSession(sessionID)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrImpersonationNotFound) {
+			log.Warn("impersonation session not found")
+			return nil, apperrors.ErrImpersonationNotFound
+		}
+		log.Error("failed to end impersonation session", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	teamName := ""
+	if uid, err := models.ParseUserID(session.UserID); err == nil {
+		if user, err := s.userRepo.GetUser(uid.Int()); err == nil {
+			teamName = user.TeamName
+		}
+	}
+
+	recordAudit(ctx, s.publisher, models.AuditEvent{
+		TeamName:  teamName,
+		Action:    models.AuditImpersonationEnded,
+		SubjectID: session.UserID,
+		Details:   fmt.Sprintf("session %d", session.SessionID),
+	})
+
+	log.Info("impersonation ended")
+	return session, nil
+}
+
+// ResolveSession returns the active session for the key. Unknown, ended and
+// expired keys are all reported as ErrInvalidImpersonation.
+func (s *ImpersonationService) ResolveSession(ctx context.Context, key string) (*models.ImpersonationSession, error) {
+	const op = "service.impersonation.ResolveSession"
+
+	log := s.log.With(slog.String("op", op))
+
+	session, err := s.sessionRepo.GetActiveSession(hashTokenKey(key))
+	if err != nil {
+		if errors.Is(err, apperrors.ErrImpersonationNotFound) {
+			log.Warn("unknown or expired impersonation key")
+			return nil, apperrors.ErrInvalidImpersonation
+		}
+		log.Error("failed to look up impersonation session", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return session, nil
+}
+
+func generateImpersonationKey() (string, error) {
+	key, err := generateTokenKey()
+	if err != nil {
+		return "", err
+	}
+	return impersonationPrefix + strings.TrimPrefix(key, tokenPrefix), nil
+}
@@ -0,0 +1,257 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"pull-request-assigner/internal/apperrors"
+	"pull-request-assigner/internal/domain/models"
+	"pull-request-assigner/internal/lib/actor"
+	"pull-request-assigner/internal/lib/logger/sl"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+type MergeWindowProvider interface {
+	SetMergeWindow(window models.MergeWindow) (*models.MergeWindow, error)
+	GetMergeWindow(teamName string) (*models.MergeWindow, error)
+	GetMergeWindows() ([]models.MergeWindow, error)
+}
+
+const maxMergeWarnMinutes = 24 * 60
+
+var mergeWeekdays = map[string]time.Weekday{
+	"SUNDAY":    time.Sunday,
+	"MONDAY":    time.Monday,
+	"TUESDAY":   time.Tuesday,
+	"WEDNESDAY": time.Wednesday,
+	"THURSDAY":  time.Thursday,
+	"FRIDAY":    time.Friday,
+	"SATURDAY":  time.Saturday,
+}
+
+// SetMergeWindow replaces the team's merge window. An empty time zone means
+// UTC; a window without blocks never blocks merging.
+func (s *PullRequestService) SetMergeWindow(ctx context.Context, window models.MergeWindow) (*models.MergeWindow, error) {
+	const op = "service.pullRequest.SetMergeWindow"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("team_name", window.TeamName),
+	)
+
+	if window.TeamName == "" {
+		log.Warn("team name is required")
+		return nil, apperrors.ErrTeamNameRequired
+	}
+
+	normalized, err := normalizeMergeWindow(window)
+	if err != nil {
+		log.Warn("invalid merge window", sl.Err(err))
+		return nil, apperrors.ErrInvalidMergeWindow
+	}
+
+	stored, err := s.mergeWindows.SetMergeWindow(normalized)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrTeamNotFound) {
+			log.Warn("team not found")
+			return nil, apperrors.ErrTeamNotFound
+		}
+		log.Error("failed to set merge window", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	recordAudit(ctx, s.publisher, models.AuditEvent{
+		TeamName: stored.TeamName,
+		Action:   models.AuditMergeWindowChanged,
+	})
+
+	log.Info("merge window set", slog.Int("blocks", len(stored.Blocks)))
+	return stored, nil
+}
+
+func (s *PullRequestService) GetMergeWindows(ctx context.Context) ([]models.MergeWindow, error) {
+	const op = "service.pullRequest.GetMergeWindows"
+
+	log := s.log.With(slog.String("op", op))
+
+	windows, err := s.mergeWindows.GetMergeWindows()
+	if err != nil {
+		log.Error("failed to get merge windows", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return windows, nil
+}
+
+func normalizeMergeWindow(window models.MergeWindow) (models.MergeWindow, error) {
+	window.TimeZone = strings.TrimSpace(window.TimeZone)
+	if window.TimeZone == "" {
+		window.TimeZone = "UTC"
+	}
+	if _, err := time.LoadLocation(window.TimeZone); err != nil {
+		return window, err
+	}
+
+	if window.WarnMinutes < 0 || window.WarnMinutes > maxMergeWarnMinutes {
+		return window, fmt.Errorf("warn_minutes %d is out of range", window.WarnMinutes)
+	}
+
+	blocks := make([]models.MergeBlock, 0, len(window.Blocks))
+	for _, block := range window.Blocks {
+		weekday := strings.ToUpper(strings.TrimSpace(block.Weekday))
+		if _, ok := mergeWeekdays[weekday]; !ok {
+			return window, fmt.Errorf("unknown weekday %q", block.Weekday)
+		}
+		start, err := parseClock(block.Start)
+		if err != nil {
+			return window, err
+		}
+		end, err := parseClock(block.End)
+		if err != nil {
+			return window, err
+		}
+		if start >= end {
+			return window, fmt.Errorf("block %s %s-%s ends before it starts", weekday, block.Start, block.End)
+		}
+		blocks = append(blocks, models.MergeBlock{
+			Weekday: weekday,
+			Start:   formatClock(start),
+			End:     formatClock(end),
+		})
+	}
+	window.Blocks = blocks
+
+	return window, nil
+}
+
+// parseClock turns HH:MM into minutes since midnight; 24:00 is the end of
+// the day.
+func parseClock(clock string) (int, error) {
+	var hours, minutes int
+	if _, err := fmt.Sscanf(strings.TrimSpace(clock), "%d:%d", &hours, &minutes); err != nil {
+		return 0, fmt.Errorf("invalid time %q", clock)
+	}
+	total := hours*60 + minutes
+	if hours < 0 || minutes < 0 || minutes > 59 || total > 24*60 {
+		return 0, fmt.Errorf("invalid time %q", clock)
+	}
+	return total, nil
+}
+
+func formatClock(minutes int) string {
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
+
+type mergeSpan struct {
+	start, end time.Time
+}
+
+// blockedSpans lays the window's weekly blocks out on the days around now,
+// joining blocks that touch into one span.
+func blockedSpans(window *models.MergeWindow, loc *time.Location, now time.Time) []mergeSpan {
+	local := now.In(loc)
+
+	var spans []mergeSpan
+	for offset := -7; offset <= 7; offset++ {
+		day := time.Date(local.Year(), local.Month(), local.Day()+offset, 0, 0, 0, 0, loc)
+		for _, block := range window.Blocks {
+			if mergeWeekdays[block.Weekday] != day.Weekday() {
+				continue
+			}
+			start, _ := parseClock(block.Start)
+			end, _ := parseClock(block.End)
+			spans = append(spans, mergeSpan{
+				start: time.Date(day.Year(), day.Month(), day.Day(), 0, start, 0, 0, loc),
+				end:   time.Date(day.Year(), day.Month(), day.Day(), 0, end, 0, 0, loc),
+			})
+		}
+	}
+
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start.Before(spans[j].start) })
+
+	merged := make([]mergeSpan, 0, len(spans))
+	for _, span := range spans {
+		if last := len(merged) - 1; last >= 0 && !span.start.After(merged[last].end) {
+			if span.end.After(merged[last].end) {
+				merged[last].end = span.end
+			}
+			continue
+		}
+		merged = append(merged, span)
+	}
+
+	return merged
+}
+
+// checkMergeWindow applies the PR's team merge window to a merge made now. It
+// fails while the window is closed unless an admin overrides it, and warns
+// about merges close to a closed period.
+func (s *PullRequestService) checkMergeWindow(ctx context.Context, pr *models.PullRequest, override bool) (string, *models.MergeWarning, error) {
+	teamName, err := s.authorTeam(pr)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrPRAuthorNotFound) {
+			return "", nil, nil
+		}
+		return "", nil, err
+	}
+
+	window, err := s.mergeWindows.GetMergeWindow(teamName)
+	if err != nil || window == nil {
+		return teamName, nil, err
+	}
+
+	loc, err := time.LoadLocation(window.TimeZone)
+	if err != nil {
+		return teamName, nil, err
+	}
+
+	now := time.Now()
+	warn := time.Duration(window.WarnMinutes) * time.Minute
+
+	var warning *models.MergeWarning
+	for _, span := range blockedSpans(window, loc, now) {
+		switch {
+		case !now.Before(span.start) && now.Before(span.end):
+			if !override {
+				return teamName, nil, apperrors.ErrMergeWindowClosed
+			}
+			if !callerIsAdmin(ctx) {
+				return teamName, nil, apperrors.ErrMergeOverrideDenied
+			}
+			return teamName, &models.MergeWarning{
+				Code:    models.MergeWarningOverridden,
+				Message: "merged while the merge window is closed, by admin override",
+				At:      span.end.UTC(),
+			}, nil
+		case warn > 0 && span.start.After(now) && span.start.Sub(now) <= warn:
+			warning = &models.MergeWarning{
+				Code:    models.MergeWarningClosingSoon,
+				Message: "merged shortly before the merge window closes",
+				At:      span.start.UTC(),
+			}
+		case warn > 0 && !span.end.After(now) && now.Sub(span.end) <= warn:
+			warning = &models.MergeWarning{
+				Code:    models.MergeWarningJustOpened,
+				Message: "merged shortly after the merge window reopened",
+				At:      span.end.UTC(),
+			}
+		}
+	}
+
+	return teamName, warning, nil
+}
+
+// callerIsAdmin reports whether the request was made with an admin API key,
+// or without a key while authentication is optional. An admin impersonating a
+// user acts as that user.
+func callerIsAdmin(ctx context.Context) bool {
+	if _, impersonated := actor.Impersonator(ctx); impersonated {
+		return false
+	}
+	scopes, authenticated := actor.Scopes(ctx)
+	return !authenticated || slices.Contains(scopes, models.TokenScopeAdmin)
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"pull-request-assigner/internal/domain/models"
+	"pull-request-assigner/internal/lib/logger/sl"
+	"strings"
+)
+
+type StatusLister interface {
+	GetStatuses() ([]models.PRStatus, error)
+}
+
+// staticEnums are the enumerations fixed in code, in display order.
+var staticEnums = []models.Enum{
+	{Name: models.EnumPriority, Values: []models.EnumValue{
+		{Value: models.PriorityLow, Label: "Low"},
+		{Value: models.PriorityNormal, Label: "Normal"},
+		{Value: models.PriorityHigh, Label: "High"},
+		{Value: models.PriorityCritical, Label: "Critical"},
+	}},
+	{Name: models.EnumCIStatus, Values: []models.EnumValue{
+		{Value: models.CIStatusUnknown, Label: "Unknown"},
+		{Value: models.CIStatusPending, Label: "Pending"},
+		{Value: models.CIStatusSuccess, Label: "Passed"},
+		{Value: models.CIStatusFailure, Label: "Failed"},
+	}},
+	{Name: models.EnumReviewState, Values: []models.EnumValue{
+		{Value: models.ReviewStateAssigned, Label: "Assigned"},
+		{Value: models.ReviewStateInProgress, Label: "In progress"},
+		{Value: models.ReviewStateCompleted, Label: "Completed"},
+	}},
+	{Name: models.EnumAssignmentAction, Values: []models.EnumValue{
+		{Value: models.AssignmentActionAuto, Label: "Assigned automatically"},
+		{Value: models.AssignmentActionManual, Label: "Assigned manually"},
+		{Value: models.AssignmentActionReassign, Label: "Reassigned"},
+		{Value: models.AssignmentActionUnassign, Label: "Unassigned"},
+		{Value: models.AssignmentActionDelegate, Label: "Delegated"},
+	}},
+	{Name: models.EnumReassignReason, Values: []models.EnumValue{
+		{Value: models.ReassignReasonVacation, Label: "Vacation"},
+		{Value: models.ReassignReasonOverloaded, Label: "Overloaded"},
+		{Value: models.ReassignReasonConflict, Label: "Conflict of interest"},
+		{Value: models.ReassignReasonDeclined, Label: "Declined"},
+		{Value: models.ReassignReasonManual, Label: "Manual"},
+		{Value: models.ReassignReasonOffboarded, Label: "Offboarded"},
+		{Value: models.ReassignReasonDeactivated, Label: "Deactivated"},
+	}},
+	{Name: models.EnumActivityKind, Values: []models.EnumValue{
+		{Value: models.ActivityPRCreated, Label: "PR created"},
+		{Value: models.ActivityReviewerAssigned, Label: "Reviewer assigned"},
+		{Value: models.ActivityReviewerUnassigned, Label: "Reviewer unassigned"},
+		{Value: models.ActivityReviewStarted, Label: "Review started"},
+		{Value: models.ActivityReviewCompleted, Label: "Review completed"},
+		{Value: models.ActivityApproved, Label: "Approved"},
+		{Value: models.ActivityStatusChanged, Label: "Status changed"},
+		{Value: models.ActivityMerged, Label: "Merged"},
+		{Value: models.ActivityAutoMerged, Label: "Merged automatically"},
+		{Value: models.ActivityMergedExternally, Label: "Merged in forge"},
+		{Value: models.ActivityNotificationSent, Label: "Notification sent"},
+		{Value: models.ActivityNotificationFailed, Label: "Notification failed"},
+	}},
+}
+
+// statusLabels names the statuses shipped with the schema. Statuses added
+// later fall back to their humanized value.
+var statusLabels = map[string]string{
+	models.PRStatusOpen:   "Open",
+	models.PRStatusMerged: "Merged",
+}
+
+// MetaService describes the enumerations the API returns, so clients can
+// render them without hard-coding the values.
+type MetaService struct {
+	log      *slog.Logger
+	statuses StatusLister
+}
+
+func NewMetaService(log *slog.Logger, statuses StatusLister) *MetaService {
+	return &MetaService{
+		log:      log,
+		statuses: statuses,
+	}
+}
+
+// ListEnums returns the PR statuses configured in the database followed by
+// the enumerations fixed in code. Labels are in English.
+func (s *MetaService) ListEnums(ctx context.Context) ([]models.Enum, error) {
+	const op = "service.meta.ListEnums"
+
+	log := s.log.With(slog.String("op", op))
+
+	statuses, err := s.statuses.GetStatuses()
+	if err != nil {
+		log.Error("failed to get PR statuses", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	statusEnum := models.Enum{Name: models.EnumPRStatus, Values: make([]models.EnumValue, 0, len(statuses))}
+	for _, status := range statuses {
+		label, ok := statusLabels[status.Status]
+		if !ok {
+			label = humanizeEnumValue(status.Status)
+		}
+		statusEnum.Values = append(statusEnum.Values, models.EnumValue{Value: status.Status, Label: label})
+	}
+
+	enums := make([]models.Enum, 0, len(staticEnums)+1)
+	enums = append(enums, statusEnum)
+	for _, enum := range staticEnums {
+		enums = append(enums, models.Enum{Name: enum.Name, Values: append([]models.EnumValue(nil), enum.Values...)})
+	}
+
+	return enums, nil
+}
+
+// humanizeEnumValue turns ON_HOLD into "On hold".
+func humanizeEnumValue(value string) string {
+	words := strings.ToLower(strings.ReplaceAll(value, "_", " "))
+	if words == "" {
+		return words
+	}
+	return strings.ToUpper(words[:1]) + words[1:]
+}
@@ -0,0 +1,319 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"pull-request-assigner/internal/apperrors"
+	"pull-request-assigner/internal/domain/events"
+	"pull-request-assigner/internal/domain/models"
+	"pull-request-assigner/internal/lib/actor"
+	"pull-request-assigner/internal/lib/logger/sl"
+	"slices"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+)
+
+const maxTemplateLength = 4000
+
+// templateActions are the audit actions after which the compiled templates
+// are stale.
+var templateActions = []string{
+	models.AuditTemplateSaved,
+	models.AuditTemplateDeleted,
+}
+
+// sampleEvents are rendered at save time, so a template referring to a field
+// or payload key its event does not have is rejected before it is used.
+var sampleEvents = map[string]events.Event{
+	events.NamePullRequestCreated: events.PullRequestCreated{PullRequestID: "pr-1", AuthorID: "u1", Reviewers: []string{"u2", "u3"}},
+	events.NamePullRequestMerged:  events.PullRequestMerged{PullRequestID: "pr-1", Reviewers: []string{"u2", "u3"}, MergedBy: "u1"},
+	events.NameReviewersReleased:  events.ReviewersReleased{PullRequestID: "pr-1", Reviewers: []string{"u2", "u3"}},
+	events.NameReviewerAssigned:   events.ReviewerAssigned{PullRequestID: "pr-1", ReviewerID: "u2", ActorID: "u1"},
+	events.NameReviewerReassigned: events.ReviewerReassigned{PullRequestID: "pr-1", OldReviewerID: "u2", NewReviewerID: "u3", Reason: models.ReassignReasonVacation, Note: "files A and B are reviewed"},
+	events.NameReviewerDelegated:  events.ReviewerDelegated{PullRequestID: "pr-1", ReviewerID: "u2", DelegateID: "u3"},
+	events.NameReviewerUnassigned: events.ReviewerUnassigned{PullRequestID: "pr-1", ReviewerID: "u2", ActorID: "u1"},
+}
+
+// TemplateError explains why a notification template was rejected.
+type TemplateError struct {
+	Reason string
+}
+
+func (e *TemplateError) Error() string {
+	return apperrors.ErrInvalidTemplate.Error() + ": " + e.Reason
+}
+
+func (e *TemplateError) Unwrap() error {
+	return apperrors.ErrInvalidTemplate
+}
+
+type NotificationTemplateStore interface {
+	ListTemplates() ([]models.NotificationTemplate, error)
+	SaveTemplate(tpl models.NotificationTemplate, updatedBy string) (*models.NotificationTemplate, error)
+	DeleteTemplate(event string, channel string) error
+}
+
+type templateKey struct {
+	event   string
+	channel string
+}
+
+// NotificationTemplates keeps the stored templates compiled for the
+// dispatcher. Changes made on this instance drop them at once through the
+// audit events on the bus; changes made on other instances are picked up by
+// the periodic Reload.
+type NotificationTemplates struct {
+	log   *slog.Logger
+	store NotificationTemplateStore
+
+	mu       sync.Mutex
+	compiled map[templateKey]*template.Template
+	loaded   bool
+	// generation changes on every invalidation, so a load that raced with
+	// one is not stored.
+	generation uint64
+}
+
+func NewNotificationTemplates(log *slog.Logger, store NotificationTemplateStore) *NotificationTemplates {
+	return &NotificationTemplates{
+		log:      log,
+		store:    store,
+		compiled: make(map[templateKey]*template.Template),
+	}
+}
+
+// Get returns the compiled template of the event and channel, or nil when
+// none is stored. A nil registry has no templates.
+func (t *NotificationTemplates) Get(event string, channel string) (*template.Template, error) {
+	if t == nil {
+		return nil, nil
+	}
+
+	t.mu.Lock()
+	loaded := t.loaded
+	t.mu.Unlock()
+
+	if !loaded {
+		if err := t.Reload(context.Background()); err != nil {
+			return nil, err
+		}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.compiled[templateKey{event: event, channel: channel}], nil
+}
+
+// Reload compiles every stored template. One that no longer compiles is
+// skipped, so its notifications go out without text.
+func (t *NotificationTemplates) Reload(_ context.Context) error {
+	const op = "service.notificationTemplate.Reload"
+
+	t.mu.Lock()
+	generation := t.generation
+	t.mu.Unlock()
+
+	stored, err := t.store.ListTemplates()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	compiled := make(map[templateKey]*template.Template, len(stored))
+	for _, tpl := range stored {
+		parsed, err := parseTemplate(tpl)
+		if err != nil {
+			t.log.Warn("skipping stored notification template",
+				slog.String("op", op),
+				slog.String("event", tpl.Event),
+				slog.String("channel", tpl.Channel),
+				sl.Err(err))
+			continue
+		}
+		compiled[templateKey{event: tpl.Event, channel: tpl.Channel}] = parsed
+	}
+
+	t.mu.Lock()
+	if t.generation == generation {
+		t.compiled = compiled
+		t.loaded = true
+	}
+	t.mu.Unlock()
+
+	return nil
+}
+
+func (t *NotificationTemplates) Invalidate() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.loaded = false
+	t.generation++
+}
+
+// Handle is the bus subscriber dropping the templates on template changes.
+func (t *NotificationTemplates) Handle(_ context.Context, event events.Event) {
+	recorded, ok := event.(events.AuditRecorded)
+	if !ok || !slices.Contains(templateActions, recorded.Entry.Action) {
+		return
+	}
+
+	t.Invalidate()
+}
+
+type NotificationTemplateService struct {
+	log       *slog.Logger
+	store     NotificationTemplateStore
+	publisher events.Publisher
+}
+
+func NewNotificationTemplateService(
+	log *slog.Logger,
+	store NotificationTemplateStore,
+	publisher events.Publisher) *NotificationTemplateService {
+	return &NotificationTemplateService{
+		log:       log,
+		store:     store,
+		publisher: publisher,
+	}
+}
+
+func (s *NotificationTemplateService) ListTemplates(ctx context.Context) ([]models.NotificationTemplate, error) {
+	const op = "service.notificationTemplate.ListTemplates"
+
+	log := s.log.With(slog.String("op", op))
+
+	templates, err := s.store.ListTemplates()
+	if err != nil {
+		log.Error("failed to list notification templates", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return templates, nil
+}
+
+// SaveTemplate creates or replaces the template of the event and channel. The
+// body must compile and render against a sample of the event.
+func (s *NotificationTemplateService) SaveTemplate(ctx context.Context, event string, channel string, body string) (*models.NotificationTemplate, error) {
+	const op = "service.notificationTemplate.SaveTemplate"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("event", event),
+		slog.String("channel", channel),
+	)
+
+	log.Info("attempting to save notification template")
+
+	tpl := models.NotificationTemplate{
+		Event:   strings.TrimSpace(event),
+		Channel: strings.ToLower(strings.TrimSpace(channel)),
+		Body:    body,
+	}
+
+	if err := validateTemplate(tpl); err != nil {
+		log.Warn("invalid notification template", sl.Err(err))
+		return nil, err
+	}
+
+	actorID, _ := actor.UserID(ctx)
+
+	saved, err := s.store.SaveTemplate(tpl, actorID)
+	if err != nil {
+		log.Error("failed to save notification template", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	recordAudit(ctx, s.publisher, models.AuditEvent{
+		Action:  models.AuditTemplateSaved,
+		Details: saved.Event + "/" + saved.Channel,
+	})
+
+	log.Info("notification template saved")
+	return saved, nil
+}
+
+func (s *NotificationTemplateService) DeleteTemplate(ctx context.Context, event string, channel string) error {
+	const op = "service.notificationTemplate.DeleteTemplate"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("event", event),
+		slog.String("channel", channel),
+	)
+
+	channel = strings.ToLower(strings.TrimSpace(channel))
+
+	if err := s.store.DeleteTemplate(strings.TrimSpace(event), channel); err != nil {
+		if errors.Is(err, apperrors.ErrTemplateNotFound) {
+			log.Warn("notification template not found")
+			return apperrors.ErrTemplateNotFound
+		}
+		log.Error("failed to delete notification template", sl.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	recordAudit(ctx, s.publisher, models.AuditEvent{
+		Action:  models.AuditTemplateDeleted,
+		Details: strings.TrimSpace(event) + "/" + channel,
+	})
+
+	log.Info("notification template deleted")
+	return nil
+}
+
+func parseTemplate(tpl models.NotificationTemplate) (*template.Template, error) {
+	return template.New(tpl.Event + "/" + tpl.Channel).Option("missingkey=error").Parse(tpl.Body)
+}
+
+func validateTemplate(tpl models.NotificationTemplate) error {
+	sample, ok := sampleEvents[tpl.Event]
+	if !ok {
+		return &TemplateError{Reason: fmt.Sprintf("unknown event %q", tpl.Event)}
+	}
+
+	if !slices.Contains(models.NotificationChannels, tpl.Channel) {
+		return &TemplateError{Reason: fmt.Sprintf("unknown channel %q", tpl.Channel)}
+	}
+
+	if strings.TrimSpace(tpl.Body) == "" {
+		return &TemplateError{Reason: "body is required"}
+	}
+
+	if len(tpl.Body) > maxTemplateLength {
+		return &TemplateError{Reason: fmt.Sprintf("body is longer than %d bytes", maxTemplateLength)}
+	}
+
+	parsed, err := parseTemplate(tpl)
+	if err != nil {
+		return &TemplateError{Reason: err.Error()}
+	}
+
+	prID, data := webhookPayload(sample)
+	now := time.Now()
+	err = parsed.Execute(io.Discard, models.NotificationTemplateData{
+		Event:           tpl.Event,
+		PullRequestID:   prID,
+		PullRequestName: "Sample pull request",
+		AuthorID:        "u1",
+		AuthorName:      "alice",
+		CreatedAt:       now,
+		DueAt:           now.Add(24 * time.Hour),
+		Data:            data,
+		Users: map[string]models.User{
+			"u1": {UserID: "u1", Username: "alice"},
+			"u2": {UserID: "u2", Username: "bob"},
+			"u3": {UserID: "u3", Username: "carol"},
+		},
+	})
+	if err != nil {
+		return &TemplateError{Reason: err.Error()}
+	}
+
+	return nil
+}
@@ -0,0 +1,248 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"pull-request-assigner/internal/apperrors"
+	"pull-request-assigner/internal/domain/events"
+	"pull-request-assigner/internal/domain/models"
+	"pull-request-assigner/internal/lib/logger/sl"
+	"strings"
+)
+
+// offboardingKeepErrors are the reassignment failures that leave a review
+// with the departing user instead of aborting the offboarding; the error
+// text is reported as the reason.
+var offboardingKeepErrors = []error{
+	apperrors.ErrNoReviewerCandidates,
+	apperrors.ErrPRAuthorNotFound,
+}
+
+type OffboardingUserProvider interface {
+	GetUser(userID int) (models.User, error)
+	GetOpenReviews(userID int) ([]models.ReviewAssignment, error)
+}
+
+type OffboardingPoolProvider interface {
+	RemoveUserFromPools(userID int) ([]string, error)
+}
+
+type OffboardingTokenRevoker interface {
+	RevokeUserTokens(userID int) ([]int64, error)
+}
+
+type OffboardingDeliveryCanceller interface {
+	DeleteUserPendingDeliveries(userID int) (int, error)
+}
+
+type UserDeactivator interface {
+	SetUserActiveStatus(ctx context.Context, isActive bool, userID string, reassignOpenReviews bool) (models.User, *models.ReviewHandover, error)
+}
+
+type ReviewReassigner interface {
+	ReassignReviewer(ctx context.Context, prID string, oldReviewerID string, reason string, note string) (*models.PullRequest, []string, string, error)
+}
+
+type UserAnonymizer interface {
+	AnonymizeUser(ctx context.Context, userID string, confirmationToken string) (*models.AnonymizationResult, error)
+}
+
+// OffboardingService takes a departing user out of review in one call.
+type OffboardingService struct {
+	log        *slog.Logger
+	userRepo   OffboardingUserProvider
+	poolRepo   OffboardingPoolProvider
+	tokenRepo  OffboardingTokenRevoker
+	deliveries OffboardingDeliveryCanceller
+	users      UserDeactivator
+	reviews    ReviewReassigner
+	anonymizer UserAnonymizer
+	publisher  events.Publisher
+}
+
+func NewOffboardingService(
+	log *slog.Logger,
+	userRepo OffboardingUserProvider,
+	poolRepo OffboardingPoolProvider,
+	tokenRepo OffboardingTokenRevoker,
+	deliveries OffboardingDeliveryCanceller,
+	users UserDeactivator,
+	reviews ReviewReassigner,
+	anonymizer UserAnonymizer,
+	publisher events.Publisher) *OffboardingService {
+	return &OffboardingService{
+		log:        log,
+		userRepo:   userRepo,
+		poolRepo:   poolRepo,
+		tokenRepo:  tokenRepo,
+		deliveries: deliveries,
+		users:      users,
+		reviews:    reviews,
+		anonymizer: anonymizer,
+		publisher:  publisher,
+	}
+}
+
+// OffboardUser deactivates the user, revokes their API tokens, hands each of
+// their open reviews to another reviewer, takes them out of every reviewer
+// pool, cancels the pending webhook deliveries about them and, when asked,
+// anonymizes them. Reviews are reassigned while the user is still a pool
+// member, so a pool reviewer is replaced from the pool. Anonymization keeps
+// its confirmation step: without confirmationToken the report carries the
+// token to repeat the call with. Every step is idempotent, so repeating the
+// call is safe.
+func (s *OffboardingService) OffboardUser(ctx context.Context, userID string, anonymize bool, confirmationToken string) (*models.OffboardingReport, error) {
+	const op = "service.offboarding.OffboardUser"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("user_id", userID),
+	)
+
+	id, err := models.ParseUserID(userID)
+	if err != nil {
+		log.Warn("invalid user ID format", sl.Err(err))
+		return nil, apperrors.ErrInvalidUserID
+	}
+
+	user, err := s.userRepo.GetUser(id.Int())
+	if err != nil {
+		if errors.Is(err, apperrors.ErrUserNotFound) {
+			log.Warn("user not found")
+			return nil, apperrors.ErrUserNotFound
+		}
+		log.Error("failed to get user", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	report := &models.OffboardingReport{
+		UserID:       user.UserID,
+		Reassigned:   make([]models.OffboardedReview, 0),
+		Unreassigned: make([]models.OffboardedReview, 0),
+	}
+
+	if user.IsActive {
+		if _, _, err := s.users.SetUserActiveStatus(ctx, false, user.UserID, false); err != nil {
+			log.Error("failed to deactivate user", sl.Err(err))
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		report.Deactivated = true
+	}
+
+	report.RevokedTokens, err = s.tokenRepo.RevokeUserTokens(id.Int())
+	if err != nil {
+		log.Error("failed to revoke API tokens", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	reviews, err := s.userRepo.GetOpenReviews(id.Int())
+	if err != nil {
+		log.Error("failed to get open reviews", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	note := fmt.Sprintf("%s was offboarded before finishing this review", user.Username)
+
+	report.Reassigned, report.Unreassigned, err = handOverReviews(ctx, log, s.reviews, reviews, user.UserID, models.ReassignReasonOffboarded, note)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	report.RemovedFromPools, err = s.poolRepo.RemoveUserFromPools(id.Int())
+	if err != nil {
+		log.Error("failed to remove user from reviewer pools", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	report.CancelledDeliveries, err = s.deliveries.DeleteUserPendingDeliveries(id.Int())
+	if err != nil {
+		log.Error("failed to cancel pending webhook deliveries", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	recordAudit(ctx, s.publisher, models.AuditEvent{
+		TeamName:  user.TeamName,
+		Action:    models.AuditMemberOffboarded,
+		SubjectID: user.UserID,
+		Details: fmt.Sprintf("reassigned %d, unreassigned %d, pools: %s, revoked tokens: %d, cancelled deliveries: %d",
+			len(report.Reassigned), len(report.Unreassigned), strings.Join(report.RemovedFromPools, ", "),
+			len(report.RevokedTokens), report.CancelledDeliveries),
+	})
+
+	if anonymize {
+		report.Anonymization, err = s.anonymizer.AnonymizeUser(ctx, user.UserID, confirmationToken)
+		if err != nil {
+			if !errors.Is(err, apperrors.ErrUserAnonymized) {
+				log.Error("failed to anonymize user", sl.Err(err))
+				return nil, fmt.Errorf("%s: %w", op, err)
+			}
+			report.Anonymization = &models.AnonymizationResult{UserID: user.UserID, Anonymized: true}
+		}
+	}
+
+	log.Info("user offboarded",
+		slog.Bool("deactivated", report.Deactivated),
+		slog.Int("reassigned", len(report.Reassigned)),
+		slog.Int("unreassigned", len(report.Unreassigned)),
+		slog.Int("pools", len(report.RemovedFromPools)),
+		slog.Int("revoked_tokens", len(report.RevokedTokens)),
+		slog.Int("cancelled_deliveries", report.CancelledDeliveries))
+
+	return report, nil
+}
+
+// handOverReviews reassigns each of the reviews away from userID with the
+// given reason and note. Reviews no one could take over are returned with
+// the reason; reviews merged or moved on meanwhile are skipped.
+func handOverReviews(
+	ctx context.Context,
+	log *slog.Logger,
+	reassigner ReviewReassigner,
+	reviews []models.ReviewAssignment,
+	userID string,
+	reason string,
+	note string) ([]models.OffboardedReview, []models.OffboardedReview, error) {
+	reassigned := make([]models.OffboardedReview, 0, len(reviews))
+	unreassigned := make([]models.OffboardedReview, 0)
+
+	for _, review := range reviews {
+		_, _, newReviewer, err := reassigner.ReassignReviewer(ctx, review.PullRequestId, userID, reason, note)
+		if err != nil {
+			if errors.Is(err, apperrors.ErrPRAlreadyMerged) || errors.Is(err, apperrors.ErrPRClosed) ||
+				errors.Is(err, apperrors.ErrReviewerNotAssigned) {
+				// The PR was closed or the review moved on since it was listed.
+				continue
+			}
+			if keepReason, ok := offboardingKeepReason(err); ok {
+				log.Warn("review could not be reassigned",
+					slog.String("pr_id", review.PullRequestId), sl.Err(err))
+				unreassigned = append(unreassigned, models.OffboardedReview{
+					PullRequestId: review.PullRequestId,
+					Reason:        keepReason,
+				})
+				continue
+			}
+			log.Error("failed to reassign review", slog.String("pr_id", review.PullRequestId), sl.Err(err))
+			return nil, nil, err
+		}
+
+		reassigned = append(reassigned, models.OffboardedReview{
+			PullRequestId: review.PullRequestId,
+			NewReviewerID: newReviewer,
+		})
+	}
+
+	return reassigned, unreassigned, nil
+}
+
+func offboardingKeepReason(err error) (string, bool) {
+	for _, keepErr := range offboardingKeepErrors {
+		if errors.Is(err, keepErr) {
+			return keepErr.Error(), true
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"pull-request-assigner/internal/apperrors"
+	"pull-request-assigner/internal/domain/events"
+	"pull-request-assigner/internal/domain/models"
+	"pull-request-assigner/internal/lib/logger/sl"
+	"slices"
+	"strings"
+)
+
+type PolicyService struct {
+	log        *slog.Logger
+	policyRepo PolicyStore
+	publisher  events.Publisher
+}
+
+type PolicyStore interface {
+	GetOrgPolicy() (*models.OrgPolicy, error)
+	UpdateOrgPolicy(policy models.OrgPolicy) error
+	GetPolicyLayers(teamName string) (*models.OrgPolicy, *models.TeamPolicyOverrides, error)
+}
+
+func NewPolicyService(
+	log *slog.Logger,
+	policyRepo PolicyStore,
+	publisher events.Publisher) *PolicyService {
+	return &PolicyService{
+		log:        log,
+		policyRepo: policyRepo,
+		publisher:  publisher,
+	}
+}
+
+func (s *PolicyService) GetOrgPolicy(ctx context.Context) (*models.OrgPolicy, error) {
+	const op = "service.policy.GetOrgPolicy"
+
+	log := s.log.With(slog.String("op", op))
+
+	policy, err := s.policyRepo.GetOrgPolicy()
+	if err != nil {
+		log.Error("failed to get org policy", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return policy, nil
+}
+
+// UpdateOrgPolicy changes the org defaults. Every team that does not
+// override a changed field picks up the new value right away.
+func (s *PolicyService) UpdateOrgPolicy(ctx context.Context, update models.OrgPolicyUpdate, actorID string) (*models.OrgPolicy, error) {
+	const op = "service.policy.UpdateOrgPolicy"
+
+	log := s.log.With(slog.String("op", op))
+
+	log.Info("attempting to update org policy")
+
+	if update.MinReviewers != nil && *update.MinReviewers < 0 {
+		log.Error("min reviewers must not be negative", slog.Int("min_reviewers", *update.MinReviewers))
+		return nil, apperrors.ErrInvalidPolicy
+	}
+
+	if update.ReviewersPerPR != nil && (*update.ReviewersPerPR < 1 || *update.ReviewersPerPR > maxReviewersPerTeam) {
+		log.Error("invalid reviewers per PR", slog.Int("reviewers_per_pr", *update.ReviewersPerPR))
+		return nil, apperrors.ErrInvalidPolicy
+	}
+
+	if update.DefaultPriority != nil && *update.DefaultPriority != "" && !models.IsValidPriority(*update.DefaultPriority) {
+		log.Error("invalid default priority", slog.String("default_priority", *update.DefaultPriority))
+		return nil, apperrors.ErrInvalidPolicy
+	}
+
+	policy, err := s.policyRepo.GetOrgPolicy()
+	if err != nil {
+		log.Error("failed to get org policy", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	previous := *policy
+
+	if update.HoldUntilCIGreen != nil {
+		policy.HoldUntilCIGreen = *update.HoldUntilCIGreen
+	}
+
+	if update.MinReviewers != nil {
+		policy.MinReviewers = *update.MinReviewers
+	}
+
+	if update.ReviewersPerPR != nil {
+		policy.ReviewersPerPR = *update.ReviewersPerPR
+	}
+
+	if update.DefaultPriority != nil {
+		policy.DefaultPriority = *update.DefaultPriority
+	}
+
+	if update.DefaultLabels != nil {
+		policy.DefaultLabels = models.MergeTags(nil, *update.DefaultLabels)
+	}
+
+	if update.DefaultRequiredSkills != nil {
+		policy.DefaultRequiredSkills = models.MergeTags(nil, *update.DefaultRequiredSkills)
+	}
+
+	if update.ExcludeCoAuthors != nil {
+		policy.ExcludeCoAuthors = *update.ExcludeCoAuthors
+	}
+
+	if update.ExcludePairingSession != nil {
+		policy.ExcludePairingSession = *update.ExcludePairingSession
+	}
+
+	if update.DiversifyPairs != nil {
+		policy.DiversifyPairs = *update.DiversifyPairs
+	}
+
+	if err := s.policyRepo.UpdateOrgPolicy(*policy); err != nil {
+		log.Error("failed to update org policy", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	changes := describeOrgPolicyChanges(previous, *policy)
+	if len(changes) > 0 {
+		log.Info("org policy changed",
+			slog.String("actor_id", actorID),
+			slog.String("changes", strings.Join(changes, "; ")))
+
+		recordAudit(ctx, s.publisher, models.AuditEvent{
+			Action:  models.AuditOrgPolicyChanged,
+			ActorID: actorID,
+			Details: strings.Join(changes, "; "),
+		})
+	}
+
+	updated, err := s.policyRepo.GetOrgPolicy()
+	if err != nil {
+		log.Error("failed to get updated org policy", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("org policy updated successfully")
+	return updated, nil
+}
+
+// GetEffectivePolicy resolves the team policy against the org defaults and
+// reports which layer every inheritable field came from.
+func (s *PolicyService) GetEffectivePolicy(ctx context.Context, teamName string) (*models.EffectivePolicy, error) {
+	const op = "service.policy.GetEffectivePolicy"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("team_name", teamName),
+	)
+
+	if teamName == "" {
+		log.Error("team name is required")
+		return nil, apperrors.ErrTeamNameRequired
+	}
+
+	org, overrides, err := s.policyRepo.GetPolicyLayers(teamName)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrTeamNotFound) {
+			log.Warn("team not found")
+			return nil, apperrors.ErrTeamNotFound
+		}
+		log.Error("failed to get policy layers", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	effective := models.ResolvePolicy(*org, *overrides)
+	return &effective, nil
+}
+
+func describeOrgPolicyChanges(before models.OrgPolicy, after models.OrgPolicy) []string {
+	changes := make([]string, 0)
+
+	if before.HoldUntilCIGreen != after.HoldUntilCIGreen {
+		changes = append(changes, fmt.Sprintf("hold_until_ci_green: %t -> %t", before.HoldUntilCIGreen, after.HoldUntilCIGreen))
+	}
+
+	if before.MinReviewers != after.MinReviewers {
+		changes = append(changes, fmt.Sprintf("min_reviewers: %d -> %d", before.MinReviewers, after.MinReviewers))
+	}
+
+	if before.ReviewersPerPR != after.ReviewersPerPR {
+		changes = append(changes, fmt.Sprintf("reviewers_per_pr: %d -> %d", before.ReviewersPerPR, after.ReviewersPerPR))
+	}
+
+	if before.DefaultPriority != after.DefaultPriority {
+		changes = append(changes, fmt.Sprintf("default_priority: %q -> %q", before.DefaultPriority, after.DefaultPriority))
+	}
+
+	if !slices.Equal(before.DefaultLabels, after.DefaultLabels) {
+		changes = append(changes, fmt.Sprintf("default_labels: %v -> %v", before.DefaultLabels, after.DefaultLabels))
+	}
+
+	if !slices.Equal(before.DefaultRequiredSkills, after.DefaultRequiredSkills) {
+		changes = append(changes, fmt.Sprintf("default_required_skills: %v -> %v", before.DefaultRequiredSkills, after.DefaultRequiredSkills))
+	}
+
+	if before.ExcludeCoAuthors != after.ExcludeCoAuthors {
+		changes = append(changes, fmt.Sprintf("exclude_co_authors: %t -> %t", before.ExcludeCoAuthors, after.ExcludeCoAuthors))
+	}
+
+	if before.ExcludePairingSession != after.ExcludePairingSession {
+		changes = append(changes, fmt.Sprintf("exclude_pairing_session: %t -> %t", before.ExcludePairingSession, after.ExcludePairingSession))
+	}
+
+	if before.DiversifyPairs != after.DiversifyPairs {
+		changes = append(changes, fmt.Sprintf("diversify_reviewer_pairs: %t -> %t", before.DiversifyPairs, after.DiversifyPairs))
+	}
+
+	return changes
+}
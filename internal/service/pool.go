@@ -0,0 +1,347 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"pull-request-assigner/internal/apperrors"
+	"pull-request-assigner/internal/domain/models"
+	"pull-request-assigner/internal/lib/logger/sl"
+	"slices"
+)
+
+type PoolService struct {
+	log      *slog.Logger
+	poolRepo PoolStore
+}
+
+type PoolProvider interface {
+	GetPool(poolName string) (*models.ReviewerPool, error)
+	GetActivePoolMembers(poolName string, excludeUserIDs []string) ([]string, error)
+	IsPoolMember(poolName string, userID string) (bool, error)
+}
+
+type PoolStore interface {
+	CreatePool(poolName string, reviewersPerPR int, userIDs []int) (*models.ReviewerPool, error)
+	GetPool(poolName string) (*models.ReviewerPool, error)
+	ListPools() ([]models.ReviewerPool, error)
+	UpdatePool(poolName string, reviewersPerPR int) error
+	DeletePool(poolName string) error
+	AddPoolMembers(poolName string, userIDs []int) error
+	RemovePoolMembers(poolName string, userIDs []int) (int, error)
+	GetPoolStats(poolName string) (*models.PoolStats, error)
+}
+
+func NewPoolService(
+	log *slog.Logger,
+	poolRepo PoolStore) *PoolService {
+	return &PoolService{
+		log:      log,
+		poolRepo: poolRepo,
+	}
+}
+
+// CreatePool creates a pool with the given members. Zero reviewersPerPR
+// means the default of two reviewers.
+func (s *PoolService) CreatePool(ctx context.Context, poolName string, reviewersPerPR int, members []string) (*models.ReviewerPool, error) {
+	const op = "service.pool.CreatePool"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("pool_name", poolName),
+	)
+
+	log.Info("attempting to create reviewer pool")
+
+	if poolName == "" {
+		log.Error("pool name is required")
+		return nil, apperrors.ErrPoolNameRequired
+	}
+
+	if reviewersPerPR == 0 {
+		reviewersPerPR = defaultReviewers
+	}
+	if reviewersPerPR < 1 || reviewersPerPR > maxReviewersPerTeam {
+		log.Error("invalid reviewers per PR", slog.Int("reviewers_per_pr", reviewersPerPR))
+		return nil, apperrors.ErrInvalidPoolPolicy
+	}
+
+	userIDs, err := parseUserIDs(members)
+	if err != nil {
+		log.Error("invalid user ID format", sl.Err(err))
+		return nil, apperrors.ErrInvalidUserID
+	}
+
+	pool, err := s.poolRepo.CreatePool(poolName, reviewersPerPR, userIDs)
+	if err != nil {
+		switch {
+		case errors.Is(err, apperrors.ErrPoolExists):
+			log.Warn("reviewer pool already exists")
+			return nil, apperrors.ErrPoolExists
+		case errors.Is(err, apperrors.ErrUserNotFound):
+			log.Warn("pool member not found", sl.Err(err))
+			return nil, apperrors.ErrUserNotFound
+		}
+		log.Error("failed to create reviewer pool", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("reviewer pool created", slog.Int("member_count", len(pool.Members)))
+	return pool, nil
+}
+
+func (s *PoolService) GetPool(ctx context.Context, poolName string) (*models.ReviewerPool, error) {
+	const op = "service.pool.GetPool"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("pool_name", poolName),
+	)
+
+	if poolName == "" {
+		log.Error("pool name is required")
+		return nil, apperrors.ErrPoolNameRequired
+	}
+
+	pool, err := s.poolRepo.GetPool(poolName)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrPoolNotFound) {
+			log.Warn("reviewer pool not found")
+			return nil, apperrors.ErrPoolNotFound
+		}
+		log.Error("failed to get reviewer pool", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return pool, nil
+}
+
+func (s *PoolService) ListPools(ctx context.Context) ([]models.ReviewerPool, error) {
+	const op = "service.pool.ListPools"
+
+	log := s.log.With(slog.String("op", op))
+
+	pools, err := s.poolRepo.ListPools()
+	if err != nil {
+		log.Error("failed to list reviewer pools", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return pools, nil
+}
+
+func (s *PoolService) UpdatePool(ctx context.Context, poolName string, reviewersPerPR int) (*models.ReviewerPool, error) {
+	const op = "service.pool.UpdatePool"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("pool_name", poolName),
+		slog.Int("reviewers_per_pr", reviewersPerPR),
+	)
+
+	log.Info("attempting to update reviewer pool")
+
+	if poolName == "" {
+		log.Error("pool name is required")
+		return nil, apperrors.ErrPoolNameRequired
+	}
+
+	if reviewersPerPR < 1 || reviewersPerPR > maxReviewersPerTeam {
+		log.Error("invalid reviewers per PR")
+		return nil, apperrors.ErrInvalidPoolPolicy
+	}
+
+	if err := s.poolRepo.UpdatePool(poolName, reviewersPerPR); err != nil {
+		if errors.Is(err, apperrors.ErrPoolNotFound) {
+			log.Warn("reviewer pool not found")
+			return nil, apperrors.ErrPoolNotFound
+		}
+		log.Error("failed to update reviewer pool", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	pool, err := s.poolRepo.GetPool(poolName)
+	if err != nil {
+		log.Error("failed to get updated reviewer pool", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("reviewer pool updated")
+	return pool, nil
+}
+
+func (s *PoolService) DeletePool(ctx context.Context, poolName string) error {
+	const op = "service.pool.DeletePool"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("pool_name", poolName),
+	)
+
+	log.Info("attempting to delete reviewer pool")
+
+	if poolName == "" {
+		log.Error("pool name is required")
+		return apperrors.ErrPoolNameRequired
+	}
+
+	if err := s.poolRepo.DeletePool(poolName); err != nil {
+		if errors.Is(err, apperrors.ErrPoolNotFound) {
+			log.Warn("reviewer pool not found")
+			return apperrors.ErrPoolNotFound
+		}
+		log.Error("failed to delete reviewer pool", sl.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("reviewer pool deleted")
+	return nil
+}
+
+func (s *PoolService) AddPoolMembers(ctx context.Context, poolName string, members []string) (*models.ReviewerPool, error) {
+	const op = "service.pool.AddPoolMembers"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("pool_name", poolName),
+		slog.Int("member_count", len(members)),
+	)
+
+	log.Info("attempting to add reviewer pool members")
+
+	if poolName == "" {
+		log.Error("pool name is required")
+		return nil, apperrors.ErrPoolNameRequired
+	}
+
+	userIDs, err := parseUserIDs(members)
+	if err != nil {
+		log.Error("invalid user ID format", sl.Err(err))
+		return nil, apperrors.ErrInvalidUserID
+	}
+
+	if err := s.poolRepo.AddPoolMembers(poolName, userIDs); err != nil {
+		switch {
+		case errors.Is(err, apperrors.ErrPoolNotFound):
+			log.Warn("reviewer pool not found")
+			return nil, apperrors.ErrPoolNotFound
+		case errors.Is(err, apperrors.ErrUserNotFound):
+			log.Warn("pool member not found", sl.Err(err))
+			return nil, apperrors.ErrUserNotFound
+		}
+		log.Error("failed to add reviewer pool members", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	pool, err := s.poolRepo.GetPool(poolName)
+	if err != nil {
+		log.Error("failed to get updated reviewer pool", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("reviewer pool members added")
+	return pool, nil
+}
+
+func (s *PoolService) RemovePoolMembers(ctx context.Context, poolName string, members []string) (*models.ReviewerPool, error) {
+	const op = "service.pool.RemovePoolMembers"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("pool_name", poolName),
+		slog.Int("member_count", len(members)),
+	)
+
+	log.Info("attempting to remove reviewer pool members")
+
+	if poolName == "" {
+		log.Error("pool name is required")
+		return nil, apperrors.ErrPoolNameRequired
+	}
+
+	userIDs, err := parseUserIDs(members)
+	if err != nil {
+		log.Error("invalid user ID format", sl.Err(err))
+		return nil, apperrors.ErrInvalidUserID
+	}
+
+	removed, err := s.poolRepo.RemovePoolMembers(poolName, userIDs)
+	if err != nil {
+		log.Error("failed to remove reviewer pool members", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	pool, err := s.poolRepo.GetPool(poolName)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrPoolNotFound) {
+			log.Warn("reviewer pool not found")
+			return nil, apperrors.ErrPoolNotFound
+		}
+		log.Error("failed to get updated reviewer pool", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("reviewer pool members removed", slog.Int("removed", removed))
+	return pool, nil
+}
+
+func (s *PoolService) GetPoolStats(ctx context.Context, poolName string) (*models.PoolStats, error) {
+	const op = "service.pool.GetPoolStats"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("pool_name", poolName),
+	)
+
+	if _, err := s.GetPool(ctx, poolName); err != nil {
+		return nil, err
+	}
+
+	stats, err := s.poolRepo.GetPoolStats(poolName)
+	if err != nil {
+		log.Error("failed to get reviewer pool stats", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return stats, nil
+}
+
+func parseUserIDs(userIDs []string) ([]int, error) {
+	ids := make([]int, 0, len(userIDs))
+	for _, userID := range userIDs {
+		uid, err := models.ParseUserID(userID)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, uid.Int())
+	}
+	return ids, nil
+}
+
+// selectPoolReviewers picks the pool's reviewers-per-PR quota of random
+// active pool members.
+func (s *PullRequestService) selectPoolReviewers(ctx context.Context, pr *models.PullRequest, policy *models.TeamPolicy, excluded []string) ([]string, error) {
+	pool, err := s.poolRepo.GetPool(pr.ReviewerPool)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := s.poolRepo.GetActivePoolMembers(pool.PoolName, excluded)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(members) == 0 {
+		return nil, apperrors.ErrNoReviewerCandidates
+	}
+
+	candidates := slices.Clone(members)
+	picked, err := s.pickReviewers(policy, members, pool.ReviewersPerPR, nil)
+	if err != nil {
+		return nil, err
+	}
+	tracePoolPick(ctx, pool.PoolName, pool.ReviewersPerPR, excluded, candidates, picked)
+
+	return picked, nil
+}
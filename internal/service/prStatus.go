@@ -0,0 +1,234 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"pull-request-assigner/internal/apperrors"
+	"pull-request-assigner/internal/domain/models"
+	"pull-request-assigner/internal/lib/logger/sl"
+	"strings"
+)
+
+const maxPRStatusLength = 50
+
+// SaveStatus adds a PR status to the org's statuses or changes whether it is
+// terminal. PRs in a terminal status are closed: their reviewers no longer
+// count as busy and they accept no further review changes, so a status that
+// transitions lead out of cannot become terminal. OPEN and MERGED are built
+// in and cannot be changed.
+func (s *PullRequestService) SaveStatus(ctx context.Context, status models.PRStatus) (*models.PRStatus, error) {
+	const op = "service.pullRequest.SaveStatus"
+
+	status.Status = strings.ToUpper(strings.TrimSpace(status.Status))
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("status", status.Status),
+		slog.Bool("is_terminal", status.IsTerminal),
+	)
+
+	if status.Status == "" {
+		log.Warn("status is required")
+		return nil, apperrors.ErrPRStatusRequired
+	}
+	if len(status.Status) > maxPRStatusLength {
+		log.Warn("status is too long")
+		return nil, apperrors.ErrInvalidStatusMachine
+	}
+	if isBuiltinPRStatus(status.Status) {
+		log.Warn("built-in status cannot be changed")
+		return nil, apperrors.ErrBuiltinPRStatus
+	}
+
+	if err := s.statusRepo.SaveStatus(status); err != nil {
+		if errors.Is(err, apperrors.ErrInvalidStatusMachine) {
+			log.Warn("status with outgoing transitions cannot become terminal")
+			return nil, apperrors.ErrInvalidStatusMachine
+		}
+		log.Error("failed to save PR status", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.recordStatusAudit(ctx, "", fmt.Sprintf("status %s saved, terminal: %t", status.Status, status.IsTerminal))
+
+	log.Info("PR status saved")
+	return &status, nil
+}
+
+// DeleteStatus removes a PR status no PR is in and no transition, org or
+// team, uses.
+func (s *PullRequestService) DeleteStatus(ctx context.Context, status string) error {
+	const op = "service.pullRequest.DeleteStatus"
+
+	status = strings.ToUpper(strings.TrimSpace(status))
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("status", status),
+	)
+
+	if status == "" {
+		log.Warn("status is required")
+		return apperrors.ErrPRStatusRequired
+	}
+	if isBuiltinPRStatus(status) {
+		log.Warn("built-in status cannot be deleted")
+		return apperrors.ErrBuiltinPRStatus
+	}
+
+	if err := s.statusRepo.DeleteStatus(status); err != nil {
+		switch {
+		case errors.Is(err, apperrors.ErrUnknownPRStatus):
+			log.Warn("unknown PR status")
+			return apperrors.ErrUnknownPRStatus
+		case errors.Is(err, apperrors.ErrPRStatusInUse):
+			log.Warn("PR status is in use")
+			return apperrors.ErrPRStatusInUse
+		}
+		log.Error("failed to delete PR status", sl.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.recordStatusAudit(ctx, "", fmt.Sprintf("status %s deleted", status))
+
+	log.Info("PR status deleted")
+	return nil
+}
+
+// SetTransitions replaces the transitions the team's PRs follow, or the
+// org's when teamName is empty. Clearing a team's transitions makes it follow
+// the org's again. Transitions must keep MERGED reachable from OPEN and may
+// not leave a terminal status.
+func (s *PullRequestService) SetTransitions(ctx context.Context, teamName string, transitions []models.PRStatusTransition) (*models.PRStatusMachine, error) {
+	const op = "service.pullRequest.SetTransitions"
+
+	teamName = strings.TrimSpace(teamName)
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("team_name", teamName),
+		slog.Int("transitions", len(transitions)),
+	)
+
+	log.Info("attempting to set PR status transitions")
+
+	statuses, err := s.statusRepo.GetStatuses()
+	if err != nil {
+		log.Error("failed to get PR statuses", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	transitions, err = normalizeTransitions(statuses, transitions, teamName != "")
+	if err != nil {
+		log.Warn("invalid PR status transitions", sl.Err(err))
+		return nil, err
+	}
+
+	if err := s.statusRepo.SetTransitions(teamName, transitions); err != nil {
+		if errors.Is(err, apperrors.ErrTeamNotFound) {
+			log.Warn("team not found")
+			return nil, apperrors.ErrTeamNotFound
+		}
+		log.Error("failed to set PR status transitions", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.recordStatusAudit(ctx, teamName, fmt.Sprintf("%d transitions set", len(transitions)))
+
+	log.Info("PR status transitions set")
+	return s.ListStatuses(ctx, teamName)
+}
+
+func (s *PullRequestService) recordStatusAudit(ctx context.Context, teamName string, details string) {
+	recordAudit(ctx, s.publisher, models.AuditEvent{
+		TeamName: teamName,
+		Action:   models.AuditPRStatusesChanged,
+		Details:  details,
+	})
+}
+
+// isClosed reports whether the PR is in a terminal status.
+func (s *PullRequestService) isClosed(pr *models.PullRequest) (bool, error) {
+	return s.statusRepo.IsTerminal(pr.Status)
+}
+
+// closedPRError is the error for changing a PR in a terminal status: merged
+// PRs keep reporting ErrPRAlreadyMerged.
+func closedPRError(status string) error {
+	if status == models.PRStatusMerged {
+		return apperrors.ErrPRAlreadyMerged
+	}
+	return apperrors.ErrPRClosed
+}
+
+func isBuiltinPRStatus(status string) bool {
+	return status == models.PRStatusOpen || status == models.PRStatusMerged
+}
+
+// normalizeTransitions upper-cases and deduplicates the transitions and
+// checks them against the known statuses. Only a team may clear its
+// transitions, falling back to the org's.
+func normalizeTransitions(statuses []models.PRStatus, transitions []models.PRStatusTransition, forTeam bool) ([]models.PRStatusTransition, error) {
+	terminal := make(map[string]bool, len(statuses))
+	for _, status := range statuses {
+		terminal[status.Status] = status.IsTerminal
+	}
+
+	if len(transitions) == 0 {
+		if forTeam {
+			return transitions, nil
+		}
+		return nil, apperrors.ErrInvalidStatusMachine
+	}
+
+	seen := make(map[models.PRStatusTransition]bool, len(transitions))
+	result := make([]models.PRStatusTransition, 0, len(transitions))
+	next := make(map[string][]string)
+	for _, transition := range transitions {
+		transition.FromStatus = strings.ToUpper(strings.TrimSpace(transition.FromStatus))
+		transition.ToStatus = strings.ToUpper(strings.TrimSpace(transition.ToStatus))
+
+		fromTerminal, fromKnown := terminal[transition.FromStatus]
+		_, toKnown := terminal[transition.ToStatus]
+		if !fromKnown || !toKnown {
+			return nil, apperrors.ErrUnknownPRStatus
+		}
+		if fromTerminal || transition.FromStatus == transition.ToStatus {
+			return nil, apperrors.ErrInvalidStatusMachine
+		}
+
+		if seen[transition] {
+			continue
+		}
+		seen[transition] = true
+		result = append(result, transition)
+		next[transition.FromStatus] = append(next[transition.FromStatus], transition.ToStatus)
+	}
+
+	if !reachable(next, models.PRStatusOpen, models.PRStatusMerged) {
+		return nil, apperrors.ErrInvalidStatusMachine
+	}
+
+	return result, nil
+}
+
+func reachable(next map[string][]string, from string, to string) bool {
+	visited := map[string]bool{from: true}
+	queue := []string{from}
+	for len(queue) > 0 {
+		status := queue[0]
+		queue = queue[1:]
+		if status == to {
+			return true
+		}
+		for _, candidate := range next[status] {
+			if !visited[candidate] {
+				visited[candidate] = true
+				queue = append(queue, candidate)
+			}
+		}
+	}
+	return false
+}
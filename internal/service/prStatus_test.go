@@ -0,0 +1,292 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"pull-request-assigner/internal/apperrors"
+	"pull-request-assigner/internal/domain/events"
+	"pull-request-assigner/internal/domain/models"
+	"slices"
+	"testing"
+)
+
+// statusRepoFake keeps the status machine in memory, with the org's
+// transitions under the empty team name.
+type statusRepoFake struct {
+	PRStatusProvider
+
+	statuses    []models.PRStatus
+	transitions map[string][]models.PRStatusTransition
+}
+
+func newStatusRepoFake() *statusRepoFake {
+	return &statusRepoFake{
+		statuses: []models.PRStatus{
+			{Status: models.PRStatusOpen},
+			{Status: "IN_REVIEW"},
+			{Status: models.PRStatusMerged, IsTerminal: true},
+			{Status: "CLOSED", IsTerminal: true},
+		},
+		transitions: map[string][]models.PRStatusTransition{
+			"": {
+				{FromStatus: models.PRStatusOpen, ToStatus: "IN_REVIEW"},
+				{FromStatus: models.PRStatusOpen, ToStatus: models.PRStatusMerged},
+				{FromStatus: models.PRStatusOpen, ToStatus: "CLOSED"},
+				{FromStatus: "IN_REVIEW", ToStatus: models.PRStatusMerged},
+			},
+		},
+	}
+}
+
+func (f *statusRepoFake) GetStatuses() ([]models.PRStatus, error) {
+	return f.statuses, nil
+}
+
+func (f *statusRepoFake) StatusExists(status string) (bool, error) {
+	return slices.ContainsFunc(f.statuses, func(s models.PRStatus) bool { return s.Status == status }), nil
+}
+
+func (f *statusRepoFake) IsTerminal(status string) (bool, error) {
+	for _, s := range f.statuses {
+		if s.Status == status {
+			return s.IsTerminal, nil
+		}
+	}
+	return false, apperrors.ErrUnknownPRStatus
+}
+
+func (f *statusRepoFake) GetTransitions(teamName string) ([]models.PRStatusTransition, bool, error) {
+	if transitions, ok := f.transitions[teamName]; ok && teamName != "" {
+		return transitions, true, nil
+	}
+	return f.transitions[""], false, nil
+}
+
+func (f *statusRepoFake) TransitionAllowed(teamName string, fromStatus string, toStatus string) (bool, error) {
+	transitions, _, _ := f.GetTransitions(teamName)
+	return slices.Contains(transitions, models.PRStatusTransition{FromStatus: fromStatus, ToStatus: toStatus}), nil
+}
+
+func (f *statusRepoFake) SetTransitions(teamName string, transitions []models.PRStatusTransition) error {
+	if len(transitions) == 0 {
+		delete(f.transitions, teamName)
+		return nil
+	}
+	f.transitions[teamName] = transitions
+	return nil
+}
+
+// prRepoFake serves a single PR and records status changes.
+type prRepoFake struct {
+	PullRequestProvider
+
+	pr       models.PullRequest
+	ciStatus string
+}
+
+func (f *prRepoFake) GetPR(prID string) (*models.PullRequest, error) {
+	if prID != f.pr.PullRequestId {
+		return nil, apperrors.ErrPRNotFound
+	}
+	pr := f.pr
+	return &pr, nil
+}
+
+func (f *prRepoFake) GetPRWithReviewers(prID string) (*models.PullRequest, []string, error) {
+	pr, err := f.GetPR(prID)
+	return pr, nil, err
+}
+
+func (f *prRepoFake) SetStatus(prID string, status string) error {
+	f.pr.Status = status
+	return nil
+}
+
+func (f *prRepoFake) UpdateCIStatus(prID string, ciStatus string) error {
+	f.ciStatus = ciStatus
+	return nil
+}
+
+type publisherFake struct{ published []events.Event }
+
+func (p *publisherFake) Publish(ctx context.Context, event events.Event) {
+	p.published = append(p.published, event)
+}
+
+func newStatusTestService(status string, team string) (*PullRequestService, *prRepoFake, *statusRepoFake) {
+	prRepo := &prRepoFake{pr: models.PullRequest{
+		PullRequestId: "pr-1",
+		AuthorID:      "u1",
+		AuthorTeam:    team,
+		Status:        status,
+	}}
+	statusRepo := newStatusRepoFake()
+
+	s := &PullRequestService{
+		log:        slog.New(slog.NewTextHandler(io.Discard, nil)),
+		prRepo:     prRepo,
+		statusRepo: statusRepo,
+		publisher:  &publisherFake{},
+	}
+	return s, prRepo, statusRepo
+}
+
+func TestSetStatusFollowsTeamTransitions(t *testing.T) {
+	ctx := context.Background()
+
+	s, prRepo, statusRepo := newStatusTestService(models.PRStatusOpen, "Backend")
+
+	if _, err := s.SetTransitions(ctx, "Backend", []models.PRStatusTransition{
+		{FromStatus: "open", ToStatus: "in_review"},
+		{FromStatus: "IN_REVIEW", ToStatus: "MERGED"},
+	}); err != nil {
+		t.Fatalf("failed to set team transitions: %v", err)
+	}
+
+	if _, _, err := s.SetStatus(ctx, "pr-1", "CLOSED"); !errors.Is(err, apperrors.ErrInvalidPRTransition) {
+		t.Fatalf("expected ErrInvalidPRTransition for a transition only the org allows, got %v", err)
+	}
+	if prRepo.pr.Status != models.PRStatusOpen {
+		t.Fatalf("rejected transition changed the status to %s", prRepo.pr.Status)
+	}
+
+	if _, _, err := s.SetStatus(ctx, "pr-1", "IN_REVIEW"); err != nil {
+		t.Fatalf("expected the team transition to be allowed, got %v", err)
+	}
+	if prRepo.pr.Status != "IN_REVIEW" {
+		t.Fatalf("expected status IN_REVIEW, got %s", prRepo.pr.Status)
+	}
+
+	machine, err := s.ListStatuses(ctx, "Backend")
+	if err != nil {
+		t.Fatalf("failed to list statuses: %v", err)
+	}
+	if machine.Source != models.PolicySourceTeam || len(machine.Transitions) != 2 {
+		t.Fatalf("expected the team's 2 transitions, got %s with %d", machine.Source, len(machine.Transitions))
+	}
+
+	if _, err := s.SetTransitions(ctx, "Backend", nil); err != nil {
+		t.Fatalf("failed to clear team transitions: %v", err)
+	}
+	if _, ok := statusRepo.transitions["Backend"]; ok {
+		t.Fatal("expected clearing to drop the team's transitions")
+	}
+}
+
+func TestSetStatusRejectedByOrgTransitions(t *testing.T) {
+	s, prRepo, _ := newStatusTestService("IN_REVIEW", "QA")
+
+	if _, _, err := s.SetStatus(context.Background(), "pr-1", "CLOSED"); !errors.Is(err, apperrors.ErrInvalidPRTransition) {
+		t.Fatalf("expected ErrInvalidPRTransition, got %v", err)
+	}
+	if prRepo.pr.Status != "IN_REVIEW" {
+		t.Fatalf("rejected transition changed the status to %s", prRepo.pr.Status)
+	}
+}
+
+func TestCustomTerminalStatusClosesPR(t *testing.T) {
+	ctx := context.Background()
+
+	s, prRepo, _ := newStatusTestService(models.PRStatusOpen, "Backend")
+
+	if _, _, err := s.SetStatus(ctx, "pr-1", "CLOSED"); err != nil {
+		t.Fatalf("failed to close PR: %v", err)
+	}
+
+	if _, _, err := s.UpdateCIStatus(ctx, "pr-1", models.CIStatusSuccess); !errors.Is(err, apperrors.ErrPRClosed) {
+		t.Fatalf("expected ErrPRClosed from UpdateCIStatus, got %v", err)
+	}
+	if prRepo.ciStatus != "" {
+		t.Fatalf("closed PR got CI status %s", prRepo.ciStatus)
+	}
+
+	if _, _, _, _, err := s.MergePR(ctx, "pr-1", false, "u1", false); !errors.Is(err, apperrors.ErrPRClosed) {
+		t.Fatalf("expected ErrPRClosed from MergePR, got %v", err)
+	}
+
+	if _, _, err := s.SetStatus(ctx, "pr-1", models.PRStatusOpen); !errors.Is(err, apperrors.ErrInvalidPRTransition) {
+		t.Fatalf("expected a closed PR to stay closed, got %v", err)
+	}
+}
+
+func TestMergedPRStillReportsAlreadyMerged(t *testing.T) {
+	s, _, _ := newStatusTestService(models.PRStatusMerged, "Backend")
+
+	if _, _, err := s.UpdateCIStatus(context.Background(), "pr-1", models.CIStatusSuccess); !errors.Is(err, apperrors.ErrPRAlreadyMerged) {
+		t.Fatalf("expected ErrPRAlreadyMerged, got %v", err)
+	}
+}
+
+func TestNormalizeTransitions(t *testing.T) {
+	statuses := newStatusRepoFake().statuses
+
+	tests := []struct {
+		name        string
+		transitions []models.PRStatusTransition
+		forTeam     bool
+		want        int
+		err         error
+	}{
+		{
+			name: "lower case and duplicates",
+			transitions: []models.PRStatusTransition{
+				{FromStatus: "open", ToStatus: "merged"},
+				{FromStatus: " OPEN ", ToStatus: "MERGED"},
+			},
+			want: 1,
+		},
+		{
+			name: "unknown status",
+			transitions: []models.PRStatusTransition{
+				{FromStatus: models.PRStatusOpen, ToStatus: "APPROVED"},
+			},
+			err: apperrors.ErrUnknownPRStatus,
+		},
+		{
+			name: "leaves terminal status",
+			transitions: []models.PRStatusTransition{
+				{FromStatus: models.PRStatusOpen, ToStatus: models.PRStatusMerged},
+				{FromStatus: "CLOSED", ToStatus: models.PRStatusOpen},
+			},
+			err: apperrors.ErrInvalidStatusMachine,
+		},
+		{
+			name: "self transition",
+			transitions: []models.PRStatusTransition{
+				{FromStatus: models.PRStatusOpen, ToStatus: models.PRStatusMerged},
+				{FromStatus: "IN_REVIEW", ToStatus: "IN_REVIEW"},
+			},
+			err: apperrors.ErrInvalidStatusMachine,
+		},
+		{
+			name: "merged unreachable",
+			transitions: []models.PRStatusTransition{
+				{FromStatus: models.PRStatusOpen, ToStatus: "IN_REVIEW"},
+				{FromStatus: models.PRStatusOpen, ToStatus: "CLOSED"},
+			},
+			err: apperrors.ErrInvalidStatusMachine,
+		},
+		{
+			name:    "empty for team",
+			forTeam: true,
+		},
+		{
+			name: "empty for org",
+			err:  apperrors.ErrInvalidStatusMachine,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeTransitions(statuses, tt.transitions, tt.forTeam)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected error %v, got %v", tt.err, err)
+			}
+			if len(got) != tt.want {
+				t.Fatalf("expected %d transitions, got %d", tt.want, len(got))
+			}
+		})
+	}
+}
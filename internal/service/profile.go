@@ -0,0 +1,67 @@
+package service
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"pull-request-assigner/internal/apperrors"
+	"pull-request-assigner/internal/domain/models"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const maxDisplayNameLength = 255
+
+// localePattern accepts a BCP 47 language tag with an optional script and
+// region, such as "ru", "en-US" or "sr-Latn-RS".
+var localePattern = regexp.MustCompile(`^([a-zA-Z]{2,3})(?:[-_]([a-zA-Z]{4}))?(?:[-_]([a-zA-Z]{2}|[0-9]{3}))?$`)
+
+// normalizeProfile trims the profile fields, checks them and brings the
+// locale to its canonical casing. Empty fields stay unset.
+func normalizeProfile(profile models.UserProfile) (models.UserProfile, error) {
+	profile.DisplayName = strings.TrimSpace(profile.DisplayName)
+	profile.Email = strings.TrimSpace(profile.Email)
+	profile.AvatarURL = strings.TrimSpace(profile.AvatarURL)
+	profile.Locale = strings.TrimSpace(profile.Locale)
+
+	if name := profile.DisplayName; name != "" {
+		if !utf8.ValidString(name) || utf8.RuneCountInString(name) > maxDisplayNameLength ||
+			strings.ContainsFunc(name, unicode.IsControl) {
+			return models.UserProfile{}, fmt.Errorf("%w: display_name must be at most %d printable characters", apperrors.ErrInvalidProfile, maxDisplayNameLength)
+		}
+	}
+
+	if email := profile.Email; email != "" {
+		address, err := mail.ParseAddress(email)
+		if err != nil || address.Name != "" || address.Address != email {
+			return models.UserProfile{}, fmt.Errorf("%w: email must be a bare address", apperrors.ErrInvalidProfile)
+		}
+	}
+
+	if avatar := profile.AvatarURL; avatar != "" {
+		parsed, err := url.Parse(avatar)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return models.UserProfile{}, fmt.Errorf("%w: avatar_url must be an absolute http(s) URL", apperrors.ErrInvalidProfile)
+		}
+	}
+
+	if profile.Locale != "" {
+		parts := localePattern.FindStringSubmatch(profile.Locale)
+		if parts == nil {
+			return models.UserProfile{}, fmt.Errorf("%w: locale must be a language tag such as en-US", apperrors.ErrInvalidProfile)
+		}
+
+		locale := strings.ToLower(parts[1])
+		if script := parts[2]; script != "" {
+			locale += "-" + strings.ToUpper(script[:1]) + strings.ToLower(script[1:])
+		}
+		if region := parts[3]; region != "" {
+			locale += "-" + strings.ToUpper(region)
+		}
+		profile.Locale = locale
+	}
+
+	return profile, nil
+}
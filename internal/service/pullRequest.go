@@ -3,41 +3,131 @@ package service
 import (
 	"context"
 	"errors"
+	"expvar"
 	"fmt"
 	"log/slog"
 	"math/rand"
 	"pull-request-assigner/internal/apperrors"
+	"pull-request-assigner/internal/domain/events"
 	"pull-request-assigner/internal/domain/models"
+	"pull-request-assigner/internal/lib/actor"
 	"pull-request-assigner/internal/lib/logger/sl"
+	"slices"
+	"sort"
+	"strings"
 	"time"
+	"unicode/utf8"
 )
 
 type PullRequestService struct {
-	log      *slog.Logger
-	prRepo   PullRequestProvider
-	teamRepo TeamProvider
+	log          *slog.Logger
+	prRepo       PullRequestProvider
+	teamRepo     TeamProvider
+	statusRepo   PRStatusProvider
+	certRepo     CertificationProvider
+	freezeRepo   FreezeProvider
+	poolRepo     PoolProvider
+	mergeWindows MergeWindowProvider
+	repairs      AssignmentRepairQueue
+	security     SecurityReviewPolicy
+	publisher    events.Publisher
+	candidates   *CandidateCache
+
+	maxOpenReviews int
+	latencyBudget  time.Duration
 }
 
 type PullRequestProvider interface {
 	CreatePR(pr models.PullRequest) error
-	PRExists(prID string) (bool, error)
 	GetPR(prID string) (*models.PullRequest, error)
 	GetPRWithReviewers(prID string) (*models.PullRequest, []string, error)
 	AddPRReviewers(prID string, reviewerIDs []string) error
-	MergePR(prID string) error
+	AddReviewersToEmptyPR(prID string, reviewerIDs []string) error
+	MergePR(prID string, mergedBy string) error
 	GetAuthorTeam(authorID string) (string, error)
+	GetUserTeams(userID string) ([]string, error)
 	GetActiveTeamMembers(teamName string, excludeUserIDs []string) ([]string, error)
-	ReplaceReviewer(prID string, oldReviewerID string, newReviewerID string) error
+	IsAuthorOnly(userID string) (bool, error)
+	ReplaceReviewer(prID string, oldReviewerID string, newReviewerID string, reason string, note string) error
+	UpdateCIStatus(prID string, ciStatus string) error
+	SetStatus(prID string, status string) error
+	GetPRExportPage(search models.PRSearch, afterCreatedAt time.Time, afterID string, limit int) ([]models.PullRequestExport, error)
+	GetCandidateStats(teamName string, authorID string, excludeUserIDs []string, since time.Time) ([]models.ReviewerCandidate, error)
+	AssignReviewer(prID string, reviewerID string, replaceReviewerID string, actorID string) error
+	DelegateReviewer(prID string, reviewerID string, delegateID string) error
+	RemoveReviewer(prID string, reviewerID string, actorID string) error
+	DeclineReview(prID string, reviewerID string) error
+	GetReviewersByRecency(prID string) ([]string, error)
+	ResizeReviewers(prID string, added []string, removed []string, actorID string) error
+	StartReview(prID string, reviewerID string) (time.Time, error)
+	CompleteReview(prID string, reviewerID string) (time.Time, error)
+	ApprovePR(prID string, reviewerID string) (time.Time, error)
+	GetApprovers(prID string) ([]string, error)
+	GetTeamWorkload(teamName string) ([]models.MemberWorkload, []models.MovableAssignment, error)
+	GetAutoMergeReady() ([]string, error)
+	GetDailyCappedUsers() ([]string, error)
+	GetReviewerPairs(userIDs []string, since time.Time) ([]models.ReviewerPair, error)
+	ReconcileStatuses(claims []models.PRStatusClaim, dryRun bool) ([]models.ReconcileResult, error)
+}
+
+const pairingWindow = 30 * 24 * time.Hour
+
+const (
+	defaultReviewers    = 2
+	maxReviewersPerTeam = 5
+	maxReviewersPerPR   = 10
+)
+
+const exportPageSize = 500
+
+// Create-and-assign timings, exposed on /debug/vars.
+var (
+	createPRTotal      = expvar.NewInt("create_pr_total")
+	createPRDurationUS = expvar.NewInt("create_pr_duration_us_total")
+	createPROverBudget = expvar.NewInt("create_pr_over_budget_total")
+)
+
+type PRStatusProvider interface {
+	GetStatuses() ([]models.PRStatus, error)
+	GetTransitions(teamName string) ([]models.PRStatusTransition, bool, error)
+	StatusExists(status string) (bool, error)
+	IsTerminal(status string) (bool, error)
+	TransitionAllowed(teamName string, fromStatus string, toStatus string) (bool, error)
+	SaveStatus(status models.PRStatus) error
+	DeleteStatus(status string) error
+	SetTransitions(teamName string, transitions []models.PRStatusTransition) error
 }
 
 func NewPullRequestService(
 	log *slog.Logger,
 	prRepo PullRequestProvider,
-	teamRepo TeamProvider) *PullRequestService {
+	teamRepo TeamProvider,
+	statusRepo PRStatusProvider,
+	certRepo CertificationProvider,
+	freezeRepo FreezeProvider,
+	poolRepo PoolProvider,
+	mergeWindows MergeWindowProvider,
+	repairs AssignmentRepairQueue,
+	security SecurityReviewPolicy,
+	maxOpenReviews int,
+	candidates *CandidateCache,
+	latencyBudget time.Duration,
+	publisher events.Publisher) *PullRequestService {
 	return &PullRequestService{
-		log:      log,
-		prRepo:   prRepo,
-		teamRepo: teamRepo,
+		log:            log,
+		prRepo:         prRepo,
+		teamRepo:       teamRepo,
+		statusRepo:     statusRepo,
+		certRepo:       certRepo,
+		freezeRepo:     freezeRepo,
+		poolRepo:       poolRepo,
+		mergeWindows:   mergeWindows,
+		repairs:        repairs,
+		security:       security,
+		maxOpenReviews: maxOpenReviews,
+		candidates:     candidates,
+		latencyBudget:  latencyBudget,
+		publisher:      publisher,
 	}
 }
 
@@ -52,6 +142,12 @@ func (s *PullRequestService) CreatePRWithReviewers(ctx context.Context, pr model
 
 	log.Info("attempting to create PR with reviewers")
 
+	started := time.Now()
+	var selection time.Duration
+	defer func() {
+		s.observeCreateLatency(log, time.Since(started), selection)
+	}()
+
 	if pr.PullRequestId == "" {
 		log.Error("pull request id is required")
 		return nil, nil, apperrors.ErrPRIDRequired
@@ -67,54 +163,168 @@ func (s *PullRequestService) CreatePRWithReviewers(ctx context.Context, pr model
 		return nil, nil, apperrors.ErrAuthorRequired
 	}
 
-	exists, err := s.prRepo.PRExists(pr.PullRequestId)
-	if err != nil {
-		log.Error("failed to check PR existence", sl.Err(err))
-		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	if pr.CIStatus == "" {
+		pr.CIStatus = models.CIStatusUnknown
+	}
+
+	if !models.IsValidCIStatus(pr.CIStatus) {
+		log.Error("invalid ci status", slog.String("ci_status", pr.CIStatus))
+		return nil, nil, apperrors.ErrInvalidCIStatus
+	}
+
+	if pr.Priority != "" && !models.IsValidPriority(pr.Priority) {
+		log.Error("invalid priority", slog.String("priority", pr.Priority))
+		return nil, nil, apperrors.ErrInvalidPriority
 	}
 
-	if exists {
-		log.Warn("PR already exists", slog.String("pr_id", pr.PullRequestId))
-		return nil, nil, apperrors.ErrPRExists
+	if pr.AutoMergeApprovals < 0 || pr.AutoMergeApprovals > MaxAutoMergeApprovals {
+		log.Error("invalid auto-merge approvals", slog.Int("auto_merge_approvals", pr.AutoMergeApprovals))
+		return nil, nil, apperrors.ErrInvalidAutoMerge
 	}
 
-	teamName, err := s.prRepo.GetAuthorTeam(pr.AuthorID)
+	authorTeams, err := s.prRepo.GetUserTeams(pr.AuthorID)
 	if err != nil {
 		if errors.Is(err, apperrors.ErrPRAuthorNotFound) {
 			log.Warn("author not found", slog.String("author_id", pr.AuthorID))
 			return nil, nil, apperrors.ErrPRAuthorNotFound
 		}
-		log.Error("failed to get author team", sl.Err(err))
+		log.Error("failed to get author teams", sl.Err(err))
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// An author in several teams names the team the PR is for; without a
+	// hint it goes to their primary team.
+	teamName := authorTeams[0]
+	if pr.AuthorTeam != "" {
+		if !slices.Contains(authorTeams, pr.AuthorTeam) {
+			log.Warn("author is not a member of the requested team", slog.String("team_name", pr.AuthorTeam))
+			return nil, nil, apperrors.ErrAuthorNotInTeam
+		}
+		teamName = pr.AuthorTeam
+	}
+	pr.AuthorTeam = teamName
+
+	policy, err := s.teamRepo.GetTeamPolicy(teamName)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrTeamNotFound) {
+			log.Warn("author team not found", slog.String("team_name", teamName))
+			return nil, nil, apperrors.ErrPRTeamNotFound
+		}
+		log.Error("failed to get team policy", sl.Err(err))
 		return nil, nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	teamMembers, err := s.prRepo.GetActiveTeamMembers(teamName, []string{pr.AuthorID})
+	applyTeamTemplate(&pr, policy)
+	pr.RequiredCertifications = normalizeAreas(pr.RequiredCertifications)
+
+	pr.ReviewerTeams, err = s.resolveReviewerTeams(teamName, policy.ReviewersPerPR, pr.ReviewerTeams, pr.Labels)
 	if err != nil {
-		log.Error("failed to get team members", sl.Err(err))
+		if errors.Is(err, apperrors.ErrInvalidReviewerTeams) || errors.Is(err, apperrors.ErrReviewerTeamNotFound) {
+			log.Warn("invalid reviewer teams", sl.Err(err))
+			return nil, nil, err
+		}
+		log.Error("failed to resolve reviewer teams", sl.Err(err))
 		return nil, nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	if len(teamMembers) == 0 {
-		log.Warn("no active team members available for review")
-		return nil, nil, apperrors.ErrNoReviewerCandidates
+	if pr.ReviewerPool != "" {
+		if _, err := s.poolRepo.GetPool(pr.ReviewerPool); err != nil {
+			if errors.Is(err, apperrors.ErrPoolNotFound) {
+				log.Warn("reviewer pool not found", slog.String("reviewer_pool", pr.ReviewerPool))
+				return nil, nil, apperrors.ErrPoolNotFound
+			}
+			log.Error("failed to get reviewer pool", sl.Err(err))
+			return nil, nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	securityReview := s.security.Requires(&pr)
+	if securityReview && !hasReviewerTeam(&pr, s.security.TeamName) {
+		pr.ReviewerTeams = append(pr.ReviewerTeams, models.ReviewerTeamQuota{TeamName: s.security.TeamName, Reviewers: 1})
+	}
+
+	frozen, err := s.freezeRepo.IsFrozen(teamName)
+	if err != nil {
+		log.Error("failed to check assignment freeze", sl.Err(err))
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	reviewers := s.selectRandomReviewers(teamMembers, 2)
+	var reviewers []string
+	if policy.HoldUntilCIGreen && pr.CIStatus != models.CIStatusSuccess {
+		log.Info("reviewer assignment deferred until CI is green",
+			slog.String("ci_status", pr.CIStatus))
+	} else if frozen {
+		pr.AssignmentQueued = true
+		log.Info("reviewer assignment frozen, PR queued", slog.String("team_name", teamName))
+	} else {
+		selectionStarted := time.Now()
+		var excluded []string
+		excluded, err = s.excludeDailyCapped(ctx, applyExclusionRules(ctx, &pr, policy))
+		if err != nil {
+			log.Error("failed to check daily assignment caps", sl.Err(err))
+			return nil, nil, fmt.Errorf("%s: %w", op, err)
+		}
+		reviewers, err = s.selectTeamReviewers(ctx, &pr, policy, excluded, teamName, log)
+		selection = time.Since(selectionStarted)
+		if err != nil {
+			if errors.Is(err, apperrors.ErrNoReviewerCandidates) {
+				log.Warn("no active team members available for review")
+				return nil, nil, apperrors.ErrNoReviewerCandidates
+			}
+			log.Error("failed to select reviewers", sl.Err(err))
+			return nil, nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if securityReview {
+			members, err := s.securityMembers(reviewers)
+			if err != nil {
+				log.Error("failed to check security reviewers", sl.Err(err))
+				return nil, nil, fmt.Errorf("%s: %w", op, err)
+			}
+			if len(members) == 0 {
+				log.Warn("no security reviewer available", slog.String("security_team", s.security.TeamName))
+				return nil, nil, apperrors.ErrNoSecurityReviewer
+			}
+		}
+
+		reviewers, err = s.addCertifiedReviewers(ctx, &pr, reviewers)
+		if err != nil {
+			if errors.Is(err, apperrors.ErrNoCertifiedReviewer) {
+				log.Warn("no certified reviewer available", sl.Err(err))
+				return nil, nil, err
+			}
+			log.Error("failed to select certified reviewers", sl.Err(err))
+			return nil, nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
 
-	pr.Status = "OPEN"
-	pr.CreatedAt = time.Now()
+	// An imported PR keeps the creation time it has on the forge.
+	pr.Status = models.PRStatusOpen
+	if pr.CreatedAt.IsZero() {
+		pr.CreatedAt = time.Now()
+	}
 
 	err = s.prRepo.CreatePR(pr)
 	if err != nil {
+		if errors.Is(err, apperrors.ErrPRExists) {
+			log.Warn("PR already exists", slog.String("pr_id", pr.PullRequestId))
+			return nil, nil, apperrors.ErrPRExists
+		}
 		log.Error("failed to create PR", sl.Err(err))
 		return nil, nil, fmt.Errorf("%s: %w", op, err)
 	}
 
+	// A PR stored without its reviewers is still created: the repair
+	// worker assigns them, and the response says so.
+	assignmentPending := false
 	if len(reviewers) > 0 {
 		err = s.prRepo.AddPRReviewers(pr.PullRequestId, reviewers)
 		if err != nil {
 			log.Error("failed to add PR reviewers", sl.Err(err))
-			return nil, nil, fmt.Errorf("%s: %w", op, err)
+			if !s.queueRepair(log, pr.PullRequestId, err) {
+				return nil, nil, fmt.Errorf("%s: %w", op, err)
+			}
+			assignmentPending = true
 		}
 	}
 
@@ -123,6 +333,14 @@ func (s *PullRequestService) CreatePRWithReviewers(ctx context.Context, pr model
 		log.Error("failed to get created PR", sl.Err(err))
 		return nil, nil, fmt.Errorf("%s: %w", op, err)
 	}
+	createdPR.AssignmentPending = assignmentPending
+
+	s.publisher.Publish(ctx, events.PullRequestCreated{
+		PullRequestID: createdPR.PullRequestId,
+		AuthorID:      createdPR.AuthorID,
+		Reviewers:     assignedReviewers,
+		CreatedAt:     createdPR.CreatedAt,
+	})
 
 	log.Info("PR created successfully",
 		slog.Int("reviewer_count", len(assignedReviewers)))
@@ -130,147 +348,2013 @@ func (s *PullRequestService) CreatePRWithReviewers(ctx context.Context, pr model
 	return createdPR, assignedReviewers, nil
 }
 
-func (s *PullRequestService) MergePR(ctx context.Context, prID string) (*models.PullRequest, []string, error) {
+// MergePR is idempotent: merging an already merged PR returns it unchanged
+// with alreadyMerged set, unless strict asks to fail with ErrPRAlreadyMerged.
+// An empty mergedBy attributes the merge to the caller from ctx, if any.
+// While the team's merge window is closed only an admin may merge, by setting
+// override; the returned warning reports that and merges close to a closed
+// period.
+func (s *PullRequestService) MergePR(ctx context.Context, prID string, strict bool, mergedBy string, override bool) (*models.PullRequest, []string, bool, *models.MergeWarning, error) {
 	const op = "service.pullRequest.MergePR"
 
 	log := s.log.With(
 		slog.String("op", op),
 		slog.String("pr_id", prID),
+		slog.Bool("strict", strict),
 	)
 
+	if mergedBy == "" {
+		mergedBy, _ = actor.UserID(ctx)
+	}
+	log = log.With(slog.String("merged_by", mergedBy))
+
 	log.Info("attempting to merge PR")
 
 	if prID == "" {
 		log.Error("pull request id is required")
-		return nil, nil, apperrors.ErrPRIDRequired
+		return nil, nil, false, nil, apperrors.ErrPRIDRequired
 	}
 
-	err := s.prRepo.MergePR(prID)
+	pr, err := s.prRepo.GetPR(prID)
 	if err != nil {
 		if errors.Is(err, apperrors.ErrPRNotFound) {
 			log.Warn("PR not found", slog.String("pr_id", prID))
-			return nil, nil, apperrors.ErrPRNotFound
+			return nil, nil, false, nil, apperrors.ErrPRNotFound
+		}
+		log.Error("failed to get PR", sl.Err(err))
+		return nil, nil, false, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	alreadyMerged := pr.Status == models.PRStatusMerged
+
+	var (
+		teamName string
+		warning  *models.MergeWarning
+	)
+	if !alreadyMerged {
+		closed, err := s.isClosed(pr)
+		if err != nil {
+			log.Error("failed to check PR status", sl.Err(err))
+			return nil, nil, false, nil, fmt.Errorf("%s: %w", op, err)
+		}
+		if closed {
+			log.Warn("cannot merge closed PR", slog.String("status", pr.Status))
+			return nil, nil, false, nil, apperrors.ErrPRClosed
+		}
+
+		prTeam, err := s.authorTeam(pr)
+		if err != nil {
+			log.Error("failed to get author team", sl.Err(err))
+			return nil, nil, false, nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		allowed, err := s.statusRepo.TransitionAllowed(prTeam, pr.Status, models.PRStatusMerged)
+		if err != nil {
+			log.Error("failed to check status transition", sl.Err(err))
+			return nil, nil, false, nil, fmt.Errorf("%s: %w", op, err)
+		}
+		if !allowed {
+			log.Warn("merge is not allowed from current status", slog.String("status", pr.Status))
+			return nil, nil, false, nil, apperrors.ErrInvalidPRTransition
+		}
+
+		if s.security.Requires(pr) {
+			approvers, err := s.prRepo.GetApprovers(prID)
+			if err != nil {
+				log.Error("failed to get approvers", sl.Err(err))
+				return nil, nil, false, nil, fmt.Errorf("%s: %w", op, err)
+			}
+
+			securityApprovers, err := s.securityMembers(approvers)
+			if err != nil {
+				log.Error("failed to check security approvers", sl.Err(err))
+				return nil, nil, false, nil, fmt.Errorf("%s: %w", op, err)
+			}
+
+			if len(securityApprovers) == 0 {
+				log.Warn("merge requires security team approval")
+				return nil, nil, false, nil, apperrors.ErrSecurityApprovalRequired
+			}
+		}
+
+		teamName, warning, err = s.checkMergeWindow(ctx, pr, override)
+		if err != nil {
+			if errors.Is(err, apperrors.ErrMergeWindowClosed) || errors.Is(err, apperrors.ErrMergeOverrideDenied) {
+				log.Warn("merge window is closed", sl.Err(err))
+				return nil, nil, false, nil, err
+			}
+			log.Error("failed to check merge window", sl.Err(err))
+			return nil, nil, false, nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		alreadyMerged, err = s.storeMerge(log, op, prID, mergedBy)
+		if err != nil {
+			return nil, nil, false, nil, err
 		}
-		log.Error("failed to merge PR", sl.Err(err))
-		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if alreadyMerged && strict {
+		log.Warn("PR is already merged")
+		return nil, nil, false, nil, apperrors.ErrPRAlreadyMerged
 	}
 
 	mergedPR, reviewers, err := s.prRepo.GetPRWithReviewers(prID)
 	if err != nil {
 		log.Error("failed to get merged PR", sl.Err(err))
-		return nil, nil, fmt.Errorf("%s: %w", op, err)
+		return nil, nil, false, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if alreadyMerged {
+		log.Info("PR was already merged")
+		return mergedPR, reviewers, true, nil, nil
+	}
+
+	if warning != nil && warning.Code == models.MergeWarningOverridden {
+		recordAudit(ctx, s.publisher, models.AuditEvent{
+			TeamName:  teamName,
+			Action:    models.AuditMergeWindowOverridden,
+			SubjectID: prID,
+		})
 	}
 
+	s.publisher.Publish(ctx, events.PullRequestMerged{
+		PullRequestID: prID,
+		Reviewers:     reviewers,
+		MergedBy:      mergedPR.MergedBy,
+		MergedAt:      mergedPR.MergedAt.Time,
+	})
+
 	log.Info("PR merged successfully")
-	return mergedPR, reviewers, nil
+	return mergedPR, reviewers, false, warning, nil
 }
 
-func (s *PullRequestService) ReassignReviewer(ctx context.Context, prID string, oldReviewerID string) (*models.PullRequest, []string, string, error) {
-	const op = "service.pullRequest.ReassignReviewer"
+// MergeExternally records a merge done on the forge. The merge has already
+// happened, so neither the status transition nor the security approval is
+// checked; otherwise it is the MergePR flow, releasing the reviewers and
+// publishing PullRequestMerged.
+func (s *PullRequestService) MergeExternally(ctx context.Context, prID string, mergedBy string, mergedByLogin string) (*models.PullRequest, bool, error) {
+	const op = "service.pullRequest.MergeExternally"
 
 	log := s.log.With(
 		slog.String("op", op),
 		slog.String("pr_id", prID),
-		slog.String("old_reviewer_id", oldReviewerID),
+		slog.String("merged_by", mergedBy),
 	)
 
-	log.Info("attempting to reassign reviewer")
+	pr, err := s.prRepo.GetPR(prID)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrPRNotFound) {
+			log.Warn("PR not found")
+			return nil, false, apperrors.ErrPRNotFound
+		}
+		log.Error("failed to get PR", sl.Err(err))
+		return nil, false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	alreadyMerged := pr.Status == models.PRStatusMerged
+	if !alreadyMerged {
+		if pr.Status != models.PRStatusOpen {
+			log.Warn("PR merged on the forge from a non-open status", slog.String("status", pr.Status))
+		}
+
+		alreadyMerged, err = s.storeMerge(log, op, prID, mergedBy)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	mergedPR, reviewers, err := s.prRepo.GetPRWithReviewers(prID)
+	if err != nil {
+		log.Error("failed to get merged PR", sl.Err(err))
+		return nil, false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if alreadyMerged {
+		log.Info("PR was already merged")
+		return mergedPR, true, nil
+	}
+
+	s.publisher.Publish(ctx, events.PullRequestMerged{
+		PullRequestID: prID,
+		Reviewers:     reviewers,
+		MergedBy:      mergedPR.MergedBy,
+		MergedAt:      mergedPR.MergedAt.Time,
+	})
+	s.publisher.Publish(ctx, events.PullRequestMergedExternally{
+		PullRequestID: prID,
+		MergedByLogin: mergedByLogin,
+	})
+
+	log.Info("external merge recorded")
+	return mergedPR, false, nil
+}
+
+// storeMerge marks the PR merged for the caller's op. Losing the race to a
+// concurrent merge is reported as already merged rather than as an error.
+func (s *PullRequestService) storeMerge(log *slog.Logger, op string, prID string, mergedBy string) (bool, error) {
+	err := s.prRepo.MergePR(prID, mergedBy)
+	switch {
+	case err == nil:
+		return false, nil
+	case errors.Is(err, apperrors.ErrPRNotFound):
+		log.Warn("PR not found")
+		return false, apperrors.ErrPRNotFound
+	case errors.Is(err, apperrors.ErrInvalidUserID):
+		log.Warn("invalid merged_by format")
+		return false, apperrors.ErrInvalidUserID
+	case errors.Is(err, apperrors.ErrUserNotFound):
+		log.Warn("merged_by user not found")
+		return false, apperrors.ErrUserNotFound
+	case errors.Is(err, apperrors.ErrPRAlreadyMerged):
+		return true, nil
+	default:
+		log.Error("failed to merge PR", sl.Err(err))
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+}
+
+// ListStatuses returns the state machine the team's PRs follow, or the org's
+// when teamName is empty.
+func (s *PullRequestService) ListStatuses(ctx context.Context, teamName string) (*models.PRStatusMachine, error) {
+	const op = "service.pullRequest.ListStatuses"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("team_name", teamName),
+	)
+
+	statuses, err := s.statusRepo.GetStatuses()
+	if err != nil {
+		log.Error("failed to get PR statuses", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	transitions, own, err := s.statusRepo.GetTransitions(teamName)
+	if err != nil {
+		log.Error("failed to get PR status transitions", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	machine := &models.PRStatusMachine{
+		TeamName:    teamName,
+		Source:      models.PolicySourceOrg,
+		Statuses:    statuses,
+		Transitions: transitions,
+	}
+	if own {
+		machine.Source = models.PolicySourceTeam
+	}
+
+	return machine, nil
+}
+
+func (s *PullRequestService) SetStatus(ctx context.Context, prID string, status string) (*models.PullRequest, []string, error) {
+	const op = "service.pullRequest.SetStatus"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("pr_id", prID),
+		slog.String("status", status),
+	)
+
+	log.Info("attempting to change PR status")
 
 	if prID == "" {
 		log.Error("pull request id is required")
-		return nil, nil, "", apperrors.ErrPRIDRequired
+		return nil, nil, apperrors.ErrPRIDRequired
 	}
 
-	if oldReviewerID == "" {
-		log.Error("old reviewer id is required")
-		return nil, nil, "", apperrors.ErrOldReviewerRequired
+	if status == "" {
+		log.Error("pull request status is required")
+		return nil, nil, apperrors.ErrPRStatusRequired
 	}
 
-	pr, reviewers, err := s.prRepo.GetPRWithReviewers(prID)
+	if status == models.PRStatusMerged {
+		mergedPR, reviewers, _, _, err := s.MergePR(ctx, prID, false, "", false)
+		return mergedPR, reviewers, err
+	}
+
+	known, err := s.statusRepo.StatusExists(status)
+	if err != nil {
+		log.Error("failed to check status existence", sl.Err(err))
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !known {
+		log.Warn("unknown PR status")
+		return nil, nil, apperrors.ErrUnknownPRStatus
+	}
+
+	pr, err := s.prRepo.GetPR(prID)
 	if err != nil {
 		if errors.Is(err, apperrors.ErrPRNotFound) {
 			log.Warn("PR not found", slog.String("pr_id", prID))
-			return nil, nil, "", apperrors.ErrPRNotFound
+			return nil, nil, apperrors.ErrPRNotFound
 		}
 		log.Error("failed to get PR", sl.Err(err))
-		return nil, nil, "", fmt.Errorf("%s: %w", op, err)
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	if pr.Status == "MERGED" {
-		log.Warn("cannot reassign reviewer on merged PR", slog.String("pr_id", prID))
-		return nil, nil, "", apperrors.ErrPRAlreadyMerged
-	}
+	if pr.Status != status {
+		teamName, err := s.authorTeam(pr)
+		if err != nil {
+			log.Error("failed to get author team", sl.Err(err))
+			return nil, nil, fmt.Errorf("%s: %w", op, err)
+		}
 
-	oldReviewerAssigned := false
-	for _, reviewer := range reviewers {
-		if reviewer == oldReviewerID {
-			oldReviewerAssigned = true
-			break
+		allowed, err := s.statusRepo.TransitionAllowed(teamName, pr.Status, status)
+		if err != nil {
+			log.Error("failed to check status transition", sl.Err(err))
+			return nil, nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if !allowed {
+			log.Warn("status transition is not allowed", slog.String("from_status", pr.Status))
+			return nil, nil, apperrors.ErrInvalidPRTransition
+		}
+
+		err = s.prRepo.SetStatus(prID, status)
+		if err != nil {
+			log.Error("failed to set PR status", sl.Err(err))
+			return nil, nil, fmt.Errorf("%s: %w", op, err)
 		}
 	}
 
-	if !oldReviewerAssigned {
-		log.Warn("reviewer not assigned to this PR", slog.String("reviewer_id", oldReviewerID))
-		return nil, nil, "", apperrors.ErrReviewerNotAssigned
+	updatedPR, reviewers, err := s.prRepo.GetPRWithReviewers(prID)
+	if err != nil {
+		log.Error("failed to get updated PR", sl.Err(err))
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.publisher.Publish(ctx, events.PullRequestStatusChanged{
+		PullRequestID: prID,
+		Status:        updatedPR.Status,
+		Reviewers:     reviewers,
+	})
+
+	log.Info("PR status changed successfully")
+	return updatedPR, reviewers, nil
+}
+
+func (s *PullRequestService) UpdateCIStatus(ctx context.Context, prID string, ciStatus string) (*models.PullRequest, []string, error) {
+	const op = "service.pullRequest.UpdateCIStatus"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("pr_id", prID),
+		slog.String("ci_status", ciStatus),
+	)
+
+	log.Info("attempting to update PR CI status")
+
+	if prID == "" {
+		log.Error("pull request id is required")
+		return nil, nil, apperrors.ErrPRIDRequired
 	}
 
-	teamName, err := s.prRepo.GetAuthorTeam(pr.AuthorID)
+	if !models.IsValidCIStatus(ciStatus) {
+		log.Error("invalid ci status")
+		return nil, nil, apperrors.ErrInvalidCIStatus
+	}
+
+	pr, reviewers, err := s.prRepo.GetPRWithReviewers(prID)
 	if err != nil {
-		if errors.Is(err, apperrors.ErrPRAuthorNotFound) {
-			log.Warn("author not found", slog.String("author_id", pr.AuthorID))
-			return nil, nil, "", apperrors.ErrPRAuthorNotFound
+		if errors.Is(err, apperrors.ErrPRNotFound) {
+			log.Warn("PR not found", slog.String("pr_id", prID))
+			return nil, nil, apperrors.ErrPRNotFound
 		}
-		log.Error("failed to get author team", sl.Err(err))
-		return nil, nil, "", fmt.Errorf("%s: %w", op, err)
+		log.Error("failed to get PR", sl.Err(err))
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	exclude := append(reviewers, pr.AuthorID)
-	availableMembers, err := s.prRepo.GetActiveTeamMembers(teamName, exclude)
+	closed, err := s.isClosed(pr)
 	if err != nil {
-		log.Error("failed to get available team members", sl.Err(err))
-		return nil, nil, "", fmt.Errorf("%s: %w", op, err)
+		log.Error("failed to check PR status", sl.Err(err))
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if closed {
+		log.Warn("cannot update CI status on closed PR", slog.String("status", pr.Status))
+		return nil, nil, closedPRError(pr.Status)
 	}
 
-	if len(availableMembers) == 0 {
-		log.Warn("no available replacement candidates in team")
-		return nil, nil, "", apperrors.ErrNoReviewerCandidates
+	err = s.prRepo.UpdateCIStatus(prID, ciStatus)
+	if err != nil {
+		log.Error("failed to update CI status", sl.Err(err))
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	newReviewer := s.selectRandomReviewer(availableMembers)
+	s.publisher.Publish(ctx, events.PullRequestCIStatusChanged{
+		PullRequestID: prID,
+		CIStatus:      ciStatus,
+	})
+
+	if ciStatus == models.CIStatusSuccess && len(reviewers) == 0 && !pr.AssignmentQueued {
+		teamName, err := s.authorTeam(pr)
+		if err != nil {
+			log.Error("failed to get author team", sl.Err(err))
+			return nil, nil, fmt.Errorf("%s: %w", op, err)
+		}
 
-	err = s.prRepo.ReplaceReviewer(prID, oldReviewerID, newReviewer)
-	if err != nil {
-		log.Error("failed to replace reviewer", sl.Err(err))
-		return nil, nil, "", fmt.Errorf("%s: %w", op, err)
+		frozen, err := s.freezeRepo.IsFrozen(teamName)
+		if err != nil {
+			log.Error("failed to check assignment freeze", sl.Err(err))
+			return nil, nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if frozen {
+			if err := s.freezeRepo.SetAssignmentQueued(prID, true); err != nil {
+				log.Error("failed to queue reviewer assignment", sl.Err(err))
+				return nil, nil, fmt.Errorf("%s: %w", op, err)
+			}
+			log.Info("CI is green but reviewer assignment is frozen, PR queued")
+		} else {
+			held, err := s.assignHeldReviewers(ctx, pr, teamName, log)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s: %w", op, err)
+			}
+			if len(held) == 0 {
+				log.Warn("CI is green but no active team members available for review")
+			} else {
+				log.Info("held reviewers assigned after green CI",
+					slog.Int("reviewer_count", len(held)))
+			}
+		}
 	}
 
 	updatedPR, updatedReviewers, err := s.prRepo.GetPRWithReviewers(prID)
 	if err != nil {
 		log.Error("failed to get updated PR", sl.Err(err))
-		return nil, nil, "", fmt.Errorf("%s: %w", op, err)
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	log.Info("reviewer reassigned successfully",
-		slog.String("new_reviewer", newReviewer))
-
-	return updatedPR, updatedReviewers, newReviewer, nil
+	log.Info("PR CI status updated successfully")
+	return updatedPR, updatedReviewers, nil
 }
 
-func (s *PullRequestService) selectRandomReviewers(members []string, max int) []string {
-	if len(members) <= max {
-		shuffled := make([]string, len(members))
-		copy(shuffled, members)
-		rand.New(rand.NewSource(time.Now().UnixNano())).Shuffle(len(shuffled), func(i, j int) {
-			shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
-		})
-		return shuffled
-	}
+// ReassignReviewer replaces oldReviewerID with a random candidate. reason is
+// one of the reassignment reasons, matched case-insensitively, and is kept
+// in assignment history. The optional note is handed off to the new
+// reviewer: it is stored with the assignment, shown in their review list and
+// sent with review.reassigned.
+func (s *PullRequestService) ReassignReviewer(ctx context.Context, prID string, oldReviewerID string, reason string, note string) (*models.PullRequest, []string, string, error) {
+	const op = "service.pullRequest.ReassignReviewer"
 
-	selected := make([]string, max)
-	available := make([]string, len(members))
-	copy(available, members)
+	reason = strings.ToUpper(strings.TrimSpace(reason))
+	note = strings.TrimSpace(note)
 
-	rand.New(rand.NewSource(time.Now().UnixNano())).Shuffle(len(available), func(i, j int) {
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("pr_id", prID),
+		slog.String("old_reviewer_id", oldReviewerID),
+		slog.String("reason", reason),
+	)
+
+	log.Info("attempting to reassign reviewer")
+
+	if prID == "" {
+		log.Error("pull request id is required")
+		return nil, nil, "", apperrors.ErrPRIDRequired
+	}
+
+	if oldReviewerID == "" {
+		log.Error("old reviewer id is required")
+		return nil, nil, "", apperrors.ErrOldReviewerRequired
+	}
+
+	if reason == "" {
+		log.Error("reassignment reason is required")
+		return nil, nil, "", apperrors.ErrReasonRequired
+	}
+
+	if !models.IsValidReassignReason(reason) {
+		log.Error("invalid reassignment reason")
+		return nil, nil, "", apperrors.ErrInvalidReason
+	}
+
+	if utf8.RuneCountInString(note) > models.MaxHandoffNoteLength {
+		log.Error("hand-off note is too long")
+		return nil, nil, "", apperrors.ErrHandoffNoteTooLong
+	}
+
+	pr, reviewers, err := s.prRepo.GetPRWithReviewers(prID)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrPRNotFound) {
+			log.Warn("PR not found", slog.String("pr_id", prID))
+			return nil, nil, "", apperrors.ErrPRNotFound
+		}
+		log.Error("failed to get PR", sl.Err(err))
+		return nil, nil, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	closed, err := s.isClosed(pr)
+	if err != nil {
+		log.Error("failed to check PR status", sl.Err(err))
+		return nil, nil, "", fmt.Errorf("%s: %w", op, err)
+	}
+	if closed {
+		log.Warn("cannot reassign reviewer on closed PR", slog.String("status", pr.Status))
+		return nil, nil, "", closedPRError(pr.Status)
+	}
+
+	oldReviewerAssigned := false
+	for _, reviewer := range reviewers {
+		if reviewer == oldReviewerID {
+			oldReviewerAssigned = true
+			break
+		}
+	}
+
+	if !oldReviewerAssigned {
+		log.Warn("reviewer not assigned to this PR", slog.String("reviewer_id", oldReviewerID))
+		return nil, nil, "", apperrors.ErrReviewerNotAssigned
+	}
+
+	newReviewer, err := s.pickReplacement(ctx, pr, reviewers, oldReviewerID, log)
+	if err != nil {
+		switch {
+		case errors.Is(err, apperrors.ErrPRAuthorNotFound):
+			log.Warn("author not found", slog.String("author_id", pr.AuthorID))
+			return nil, nil, "", apperrors.ErrPRAuthorNotFound
+		case errors.Is(err, apperrors.ErrNoReviewerCandidates):
+			log.Warn("no available replacement candidates in team")
+			return nil, nil, "", apperrors.ErrNoReviewerCandidates
+		}
+		log.Error("failed to pick replacement reviewer", sl.Err(err))
+		return nil, nil, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = s.prRepo.ReplaceReviewer(prID, oldReviewerID, newReviewer, reason, note)
+	if err != nil {
+		log.Error("failed to replace reviewer", sl.Err(err))
+		return nil, nil, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	updatedPR, updatedReviewers, err := s.prRepo.GetPRWithReviewers(prID)
+	if err != nil {
+		log.Error("failed to get updated PR", sl.Err(err))
+		return nil, nil, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.publisher.Publish(ctx, events.ReviewerReassigned{
+		PullRequestID: prID,
+		OldReviewerID: oldReviewerID,
+		NewReviewerID: newReviewer,
+		Reason:        reason,
+		Note:          note,
+	})
+
+	log.Info("reviewer reassigned successfully",
+		slog.String("new_reviewer", newReviewer))
+
+	return updatedPR, updatedReviewers, newReviewer, nil
+}
+
+// AssignReviewer lets a human pick a specific reviewer, either in addition to the
+// current ones or replacing replaceReviewerID. The reviewer must be an active
+// member of the author's team, of one of the PR's reviewer teams or of the
+// PR's reviewer pool.
+func (s *PullRequestService) AssignReviewer(ctx context.Context, prID string, reviewerID string, replaceReviewerID string, actorID string) (*models.PullRequest, []string, error) {
+	const op = "service.pullRequest.AssignReviewer"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("pr_id", prID),
+		slog.String("reviewer_id", reviewerID),
+		slog.String("replace_reviewer_id", replaceReviewerID),
+		slog.String("actor_id", actorID),
+	)
+
+	log.Info("attempting to assign reviewer manually")
+
+	if prID == "" {
+		log.Error("pull request id is required")
+		return nil, nil, apperrors.ErrPRIDRequired
+	}
+
+	if reviewerID == "" {
+		log.Error("reviewer id is required")
+		return nil, nil, apperrors.ErrReviewerRequired
+	}
+
+	pr, reviewers, err := s.prRepo.GetPRWithReviewers(prID)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrPRNotFound) {
+			log.Warn("PR not found")
+			return nil, nil, apperrors.ErrPRNotFound
+		}
+		log.Error("failed to get PR", sl.Err(err))
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	closed, err := s.isClosed(pr)
+	if err != nil {
+		log.Error("failed to check PR status", sl.Err(err))
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if closed {
+		log.Warn("cannot assign reviewer on closed PR", slog.String("status", pr.Status))
+		return nil, nil, closedPRError(pr.Status)
+	}
+
+	if reviewerID == pr.AuthorID {
+		log.Warn("author cannot be assigned as reviewer")
+		return nil, nil, apperrors.ErrReviewerIsAuthor
+	}
+
+	replaceAssigned := replaceReviewerID == ""
+	for _, reviewer := range reviewers {
+		if reviewer == reviewerID {
+			log.Warn("reviewer already assigned")
+			return nil, nil, apperrors.ErrReviewerAssigned
+		}
+		if reviewer == replaceReviewerID {
+			replaceAssigned = true
+		}
+	}
+
+	if !replaceAssigned {
+		log.Warn("reviewer to replace is not assigned")
+		return nil, nil, apperrors.ErrReviewerNotAssigned
+	}
+
+	authorTeam, err := s.authorTeam(pr)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrPRAuthorNotFound) {
+			log.Warn("author not found", slog.String("author_id", pr.AuthorID))
+			return nil, nil, apperrors.ErrPRAuthorNotFound
+		}
+		log.Error("failed to get author team", sl.Err(err))
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	reviewerTeam, inTeam, err := s.reviewerTeamFor(pr, reviewerID, authorTeam)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrPRAuthorNotFound) || errors.Is(err, apperrors.ErrAuthorRequired) {
+			log.Warn("reviewer not found")
+			return nil, nil, apperrors.ErrUserNotFound
+		}
+		log.Error("failed to get reviewer team", sl.Err(err))
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	inPool := false
+	if pr.ReviewerPool != "" {
+		inPool, err = s.poolRepo.IsPoolMember(pr.ReviewerPool, reviewerID)
+		if err != nil {
+			log.Error("failed to check reviewer pool membership", sl.Err(err))
+			return nil, nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if !inTeam && !inPool {
+		log.Warn("reviewer is not in the author's team", slog.String("reviewer_team", reviewerTeam))
+		return nil, nil, apperrors.ErrReviewerNotInTeam
+	}
+
+	activeMembers, err := s.prRepo.GetActiveTeamMembers(reviewerTeam, nil)
+	if err != nil {
+		log.Error("failed to get active team members", sl.Err(err))
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	isActive := false
+	for _, member := range activeMembers {
+		if member == reviewerID {
+			isActive = true
+			break
+		}
+	}
+
+	if !isActive {
+		authorOnly, err := s.prRepo.IsAuthorOnly(reviewerID)
+		if err != nil {
+			log.Error("failed to check author-only status", sl.Err(err))
+			return nil, nil, fmt.Errorf("%s: %w", op, err)
+		}
+		if authorOnly {
+			log.Warn("reviewer is author-only")
+			return nil, nil, apperrors.ErrReviewerAuthorOnly
+		}
+		log.Warn("reviewer is inactive")
+		return nil, nil, apperrors.ErrReviewerInactive
+	}
+
+	if replaceReviewerID != "" {
+		keeps, err := s.keepsSecurityReviewer(pr, append(withoutReviewer(reviewers, replaceReviewerID), reviewerID))
+		if err != nil {
+			log.Error("failed to check security reviewers", sl.Err(err))
+			return nil, nil, fmt.Errorf("%s: %w", op, err)
+		}
+		if !keeps {
+			log.Warn("replacement would drop the last security reviewer")
+			return nil, nil, apperrors.ErrSecurityReviewerRequired
+		}
+
+		lostArea, err := s.lostCertification(pr, reviewers, append(withoutReviewer(reviewers, replaceReviewerID), reviewerID))
+		if err != nil {
+			log.Error("failed to check certified reviewers", sl.Err(err))
+			return nil, nil, fmt.Errorf("%s: %w", op, err)
+		}
+		if lostArea != "" {
+			log.Warn("replacement would drop the last certified reviewer")
+			return nil, nil, apperrors.ErrCertifiedReviewerRequired
+		}
+	}
+
+	err = s.prRepo.AssignReviewer(prID, reviewerID, replaceReviewerID, actorID)
+	if err != nil {
+		switch {
+		case errors.Is(err, apperrors.ErrReviewerAssigned):
+			log.Warn("reviewer already assigned")
+			return nil, nil, apperrors.ErrReviewerAssigned
+		case errors.Is(err, apperrors.ErrReviewerNotAssigned):
+			log.Warn("reviewer to replace is not assigned")
+			return nil, nil, apperrors.ErrReviewerNotAssigned
+		}
+		log.Error("failed to assign reviewer", sl.Err(err))
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	updatedPR, updatedReviewers, err := s.prRepo.GetPRWithReviewers(prID)
+	if err != nil {
+		log.Error("failed to get updated PR", sl.Err(err))
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.publisher.Publish(ctx, events.ReviewerAssigned{
+		PullRequestID:     prID,
+		ReviewerID:        reviewerID,
+		ReplaceReviewerID: replaceReviewerID,
+		ActorID:           actorID,
+	})
+
+	log.Info("reviewer assigned manually")
+	return updatedPR, updatedReviewers, nil
+}
+
+// DelegateReview hands the reviewer's assignment to a teammate of their choice.
+// The delegate must be active, below the open review limit and keep the PR's
+// security and certification requirements satisfied.
+func (s *PullRequestService) DelegateReview(ctx context.Context, prID string, reviewerID string, delegateID string) (*models.PullRequest, []string, error) {
+	const op = "service.pullRequest.DelegateReview"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("pr_id", prID),
+		slog.String("reviewer_id", reviewerID),
+		slog.String("delegate_id", delegateID),
+	)
+
+	log.Info("attempting to delegate review")
+
+	if prID == "" {
+		log.Error("pull request id is required")
+		return nil, nil, apperrors.ErrPRIDRequired
+	}
+
+	if reviewerID == "" {
+		log.Error("reviewer id is required")
+		return nil, nil, apperrors.ErrReviewerRequired
+	}
+
+	if delegateID == "" {
+		log.Error("delegate id is required")
+		return nil, nil, apperrors.ErrDelegateRequired
+	}
+
+	pr, reviewers, err := s.prRepo.GetPRWithReviewers(prID)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrPRNotFound) {
+			log.Warn("PR not found")
+			return nil, nil, apperrors.ErrPRNotFound
+		}
+		log.Error("failed to get PR", sl.Err(err))
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	closed, err := s.isClosed(pr)
+	if err != nil {
+		log.Error("failed to check PR status", sl.Err(err))
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if closed {
+		log.Warn("cannot delegate review on closed PR", slog.String("status", pr.Status))
+		return nil, nil, closedPRError(pr.Status)
+	}
+
+	if delegateID == pr.AuthorID {
+		log.Warn("author cannot be assigned as reviewer")
+		return nil, nil, apperrors.ErrReviewerIsAuthor
+	}
+
+	assigned := false
+	for _, reviewer := range reviewers {
+		if reviewer == delegateID {
+			log.Warn("delegate already assigned")
+			return nil, nil, apperrors.ErrReviewerAssigned
+		}
+		if reviewer == reviewerID {
+			assigned = true
+		}
+	}
+
+	if !assigned {
+		log.Warn("reviewer not assigned to this PR")
+		return nil, nil, apperrors.ErrReviewerNotAssigned
+	}
+
+	reviewerTeams, err := s.prRepo.GetUserTeams(reviewerID)
+	if err != nil {
+		log.Error("failed to get reviewer teams", sl.Err(err))
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	delegateTeams, err := s.prRepo.GetUserTeams(delegateID)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrPRAuthorNotFound) || errors.Is(err, apperrors.ErrAuthorRequired) {
+			log.Warn("delegate not found")
+			return nil, nil, apperrors.ErrUserNotFound
+		}
+		log.Error("failed to get delegate teams", sl.Err(err))
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// The delegate must share a team with the reviewer; the reviewer's
+	// primary team wins when they share several.
+	reviewerTeam := ""
+	for _, team := range reviewerTeams {
+		if slices.Contains(delegateTeams, team) {
+			reviewerTeam = team
+			break
+		}
+	}
+
+	if reviewerTeam == "" {
+		log.Warn("delegate is not in the reviewer's team", slog.Any("delegate_teams", delegateTeams))
+		return nil, nil, apperrors.ErrDelegateNotTeammate
+	}
+
+	candidates, err := s.prRepo.GetCandidateStats(reviewerTeam, pr.AuthorID, nil, time.Now().Add(-pairingWindow))
+	if err != nil {
+		log.Error("failed to get candidate stats", sl.Err(err))
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var delegate *models.ReviewerCandidate
+	for i := range candidates {
+		if candidates[i].UserID == delegateID {
+			delegate = &candidates[i]
+			break
+		}
+	}
+
+	if delegate == nil {
+		authorOnly, err := s.prRepo.IsAuthorOnly(delegateID)
+		if err != nil {
+			log.Error("failed to check author-only status", sl.Err(err))
+			return nil, nil, fmt.Errorf("%s: %w", op, err)
+		}
+		if authorOnly {
+			log.Warn("delegate is author-only")
+			return nil, nil, apperrors.ErrReviewerAuthorOnly
+		}
+		log.Warn("delegate is inactive")
+		return nil, nil, apperrors.ErrReviewerInactive
+	}
+
+	if s.maxOpenReviews > 0 && delegate.OpenReviews >= s.maxOpenReviews {
+		log.Warn("delegate is at capacity",
+			slog.Int("open_reviews", delegate.OpenReviews),
+			slog.Int("max_open_reviews", s.maxOpenReviews))
+		return nil, nil, apperrors.ErrDelegateAtCapacity
+	}
+
+	updated := append(withoutReviewer(reviewers, reviewerID), delegateID)
+
+	keeps, err := s.keepsSecurityReviewer(pr, updated)
+	if err != nil {
+		log.Error("failed to check security reviewers", sl.Err(err))
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !keeps {
+		log.Warn("delegation would drop the last security reviewer")
+		return nil, nil, apperrors.ErrSecurityReviewerRequired
+	}
+
+	lostArea, err := s.lostCertification(pr, reviewers, updated)
+	if err != nil {
+		log.Error("failed to check certified reviewers", sl.Err(err))
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if lostArea != "" {
+		log.Warn("delegation would drop the last certified reviewer", slog.String("area", lostArea))
+		return nil, nil, apperrors.ErrCertifiedReviewerRequired
+	}
+
+	err = s.prRepo.DelegateReviewer(prID, reviewerID, delegateID)
+	if err != nil {
+		switch {
+		case errors.Is(err, apperrors.ErrReviewerAssigned):
+			log.Warn("delegate already assigned")
+			return nil, nil, apperrors.ErrReviewerAssigned
+		case errors.Is(err, apperrors.ErrReviewerNotAssigned):
+			log.Warn("reviewer not assigned to this PR")
+			return nil, nil, apperrors.ErrReviewerNotAssigned
+		}
+		log.Error("failed to delegate review", sl.Err(err))
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	updatedPR, updatedReviewers, err := s.prRepo.GetPRWithReviewers(prID)
+	if err != nil {
+		log.Error("failed to get updated PR", sl.Err(err))
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.publisher.Publish(ctx, events.ReviewerDelegated{
+		PullRequestID: prID,
+		ReviewerID:    reviewerID,
+		DelegateID:    delegateID,
+	})
+
+	log.Info("review delegated successfully")
+	return updatedPR, updatedReviewers, nil
+}
+
+// DeclineReview lets an assigned reviewer step down. A replacement is picked
+// as on reassignment; when there is none the reviewer still leaves and the PR
+// is marked under-reviewed. The replacement is empty in that case.
+func (s *PullRequestService) DeclineReview(ctx context.Context, prID string, reviewerID string) (*models.PullRequest, []string, string, error) {
+	const op = "service.pullRequest.DeclineReview"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("pr_id", prID),
+		slog.String("reviewer_id", reviewerID),
+	)
+
+	log.Info("attempting to decline review")
+
+	if prID == "" {
+		log.Error("pull request id is required")
+		return nil, nil, "", apperrors.ErrPRIDRequired
+	}
+
+	if reviewerID == "" {
+		log.Error("reviewer id is required")
+		return nil, nil, "", apperrors.ErrReviewerRequired
+	}
+
+	pr, reviewers, err := s.prRepo.GetPRWithReviewers(prID)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrPRNotFound) {
+			log.Warn("PR not found")
+			return nil, nil, "", apperrors.ErrPRNotFound
+		}
+		log.Error("failed to get PR", sl.Err(err))
+		return nil, nil, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	closed, err := s.isClosed(pr)
+	if err != nil {
+		log.Error("failed to check PR status", sl.Err(err))
+		return nil, nil, "", fmt.Errorf("%s: %w", op, err)
+	}
+	if closed {
+		log.Warn("cannot decline review on closed PR", slog.String("status", pr.Status))
+		return nil, nil, "", closedPRError(pr.Status)
+	}
+
+	if !slices.Contains(reviewers, reviewerID) {
+		log.Warn("reviewer not assigned to this PR")
+		return nil, nil, "", apperrors.ErrReviewerNotAssigned
+	}
+
+	newReviewer, err := s.pickReplacement(ctx, pr, reviewers, reviewerID, log)
+	switch {
+	case err == nil:
+		err = s.prRepo.ReplaceReviewer(prID, reviewerID, newReviewer, models.ReassignReasonDeclined, "")
+	case errors.Is(err, apperrors.ErrNoReviewerCandidates):
+		log.Warn("no replacement available, PR left under-reviewed")
+		err = s.prRepo.DeclineReview(prID, reviewerID)
+	case errors.Is(err, apperrors.ErrPRAuthorNotFound):
+		log.Warn("author not found", slog.String("author_id", pr.AuthorID))
+		return nil, nil, "", apperrors.ErrPRAuthorNotFound
+	default:
+		log.Error("failed to pick replacement reviewer", sl.Err(err))
+		return nil, nil, "", fmt.Errorf("%s: %w", op, err)
+	}
+	if err != nil {
+		if errors.Is(err, apperrors.ErrReviewerNotAssigned) {
+			log.Warn("reviewer not assigned to this PR")
+			return nil, nil, "", apperrors.ErrReviewerNotAssigned
+		}
+		log.Error("failed to decline review", sl.Err(err))
+		return nil, nil, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	updatedPR, updatedReviewers, err := s.prRepo.GetPRWithReviewers(prID)
+	if err != nil {
+		log.Error("failed to get updated PR", sl.Err(err))
+		return nil, nil, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if newReviewer != "" {
+		s.publisher.Publish(ctx, events.ReviewerReassigned{
+			PullRequestID: prID,
+			OldReviewerID: reviewerID,
+			NewReviewerID: newReviewer,
+			Reason:        models.ReassignReasonDeclined,
+		})
+	} else {
+		s.publisher.Publish(ctx, events.ReviewerUnassigned{
+			PullRequestID: prID,
+			ReviewerID:    reviewerID,
+			ActorID:       reviewerID,
+		})
+	}
+
+	log.Info("review declined successfully", slog.String("new_reviewer", newReviewer))
+	return updatedPR, updatedReviewers, newReviewer, nil
+}
+
+// UnassignReviewer removes a reviewer without picking a replacement, as long as
+// the PR keeps at least the team's minimum number of reviewers.
+func (s *PullRequestService) UnassignReviewer(ctx context.Context, prID string, reviewerID string, actorID string) (*models.PullRequest, []string, error) {
+	const op = "service.pullRequest.UnassignReviewer"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("pr_id", prID),
+		slog.String("reviewer_id", reviewerID),
+		slog.String("actor_id", actorID),
+	)
+
+	log.Info("attempting to unassign reviewer")
+
+	if prID == "" {
+		log.Error("pull request id is required")
+		return nil, nil, apperrors.ErrPRIDRequired
+	}
+
+	if reviewerID == "" {
+		log.Error("reviewer id is required")
+		return nil, nil, apperrors.ErrReviewerRequired
+	}
+
+	pr, reviewers, err := s.prRepo.GetPRWithReviewers(prID)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrPRNotFound) {
+			log.Warn("PR not found")
+			return nil, nil, apperrors.ErrPRNotFound
+		}
+		log.Error("failed to get PR", sl.Err(err))
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	closed, err := s.isClosed(pr)
+	if err != nil {
+		log.Error("failed to check PR status", sl.Err(err))
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if closed {
+		log.Warn("cannot unassign reviewer on closed PR", slog.String("status", pr.Status))
+		return nil, nil, closedPRError(pr.Status)
+	}
+
+	assigned := false
+	for _, reviewer := range reviewers {
+		if reviewer == reviewerID {
+			assigned = true
+			break
+		}
+	}
+
+	if !assigned {
+		log.Warn("reviewer not assigned to this PR")
+		return nil, nil, apperrors.ErrReviewerNotAssigned
+	}
+
+	teamName, err := s.authorTeam(pr)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrPRAuthorNotFound) {
+			log.Warn("author not found", slog.String("author_id", pr.AuthorID))
+			return nil, nil, apperrors.ErrPRAuthorNotFound
+		}
+		log.Error("failed to get author team", sl.Err(err))
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	policy, err := s.teamRepo.GetTeamPolicy(teamName)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrTeamNotFound) {
+			log.Warn("author team not found", slog.String("team_name", teamName))
+			return nil, nil, apperrors.ErrPRTeamNotFound
+		}
+		log.Error("failed to get team policy", sl.Err(err))
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(reviewers)-1 < policy.MinReviewers {
+		log.Warn("unassign would violate team minimum",
+			slog.Int("reviewers", len(reviewers)),
+			slog.Int("min_reviewers", policy.MinReviewers))
+		return nil, nil, apperrors.ErrBelowMinReviewers
+	}
+
+	keeps, err := s.keepsSecurityReviewer(pr, withoutReviewer(reviewers, reviewerID))
+	if err != nil {
+		log.Error("failed to check security reviewers", sl.Err(err))
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !keeps {
+		log.Warn("unassign would drop the last security reviewer")
+		return nil, nil, apperrors.ErrSecurityReviewerRequired
+	}
+
+	lostArea, err := s.lostCertification(pr, reviewers, withoutReviewer(reviewers, reviewerID))
+	if err != nil {
+		log.Error("failed to check certified reviewers", sl.Err(err))
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if lostArea != "" {
+		log.Warn("unassign would drop the last certified reviewer")
+		return nil, nil, apperrors.ErrCertifiedReviewerRequired
+	}
+
+	err = s.prRepo.RemoveReviewer(prID, reviewerID, actorID)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrReviewerNotAssigned) {
+			log.Warn("reviewer not assigned to this PR")
+			return nil, nil, apperrors.ErrReviewerNotAssigned
+		}
+		log.Error("failed to remove reviewer", sl.Err(err))
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	updatedPR, updatedReviewers, err := s.prRepo.GetPRWithReviewers(prID)
+	if err != nil {
+		log.Error("failed to get updated PR", sl.Err(err))
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.publisher.Publish(ctx, events.ReviewerUnassigned{
+		PullRequestID: prID,
+		ReviewerID:    reviewerID,
+		ActorID:       actorID,
+	})
+
+	log.Info("reviewer unassigned successfully")
+	return updatedPR, updatedReviewers, nil
+}
+
+// SetReviewerCount grows or shrinks the reviewer set of an open PR to count.
+// Extra reviewers are picked like on creation, from the PR's pool or the
+// author's team; shrinking removes the most recently assigned reviewers that
+// neither the security nor the certification requirements keep on the PR.
+func (s *PullRequestService) SetReviewerCount(ctx context.Context, prID string, count int, actorID string) (*models.PullRequest, []string, []string, []string, error) {
+	const op = "service.pullRequest.SetReviewerCount"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("pr_id", prID),
+		slog.Int("count", count),
+		slog.String("actor_id", actorID),
+	)
+
+	log.Info("attempting to set reviewer count")
+
+	if prID == "" {
+		log.Error("pull request id is required")
+		return nil, nil, nil, nil, apperrors.ErrPRIDRequired
+	}
+
+	if count < 1 || count > maxReviewersPerPR {
+		log.Error("invalid reviewer count")
+		return nil, nil, nil, nil, apperrors.ErrInvalidReviewerCount
+	}
+
+	pr, reviewers, err := s.prRepo.GetPRWithReviewers(prID)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrPRNotFound) {
+			log.Warn("PR not found")
+			return nil, nil, nil, nil, apperrors.ErrPRNotFound
+		}
+		log.Error("failed to get PR", sl.Err(err))
+		return nil, nil, nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	closed, err := s.isClosed(pr)
+	if err != nil {
+		log.Error("failed to check PR status", sl.Err(err))
+		return nil, nil, nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if closed {
+		log.Warn("cannot change reviewer count on closed PR", slog.String("status", pr.Status))
+		return nil, nil, nil, nil, closedPRError(pr.Status)
+	}
+
+	if count == len(reviewers) {
+		log.Info("reviewer count unchanged")
+		return pr, reviewers, []string{}, []string{}, nil
+	}
+
+	teamName, err := s.authorTeam(pr)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrPRAuthorNotFound) {
+			log.Warn("author not found", slog.String("author_id", pr.AuthorID))
+			return nil, nil, nil, nil, apperrors.ErrPRAuthorNotFound
+		}
+		log.Error("failed to get author team", sl.Err(err))
+		return nil, nil, nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	excluded, policy, err := s.excludedReviewers(ctx, pr, teamName)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrTeamNotFound) {
+			log.Warn("author team not found", slog.String("team_name", teamName))
+			return nil, nil, nil, nil, apperrors.ErrPRTeamNotFound
+		}
+		log.Error("failed to apply exclusion rules", sl.Err(err))
+		return nil, nil, nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	added := []string{}
+	removed := []string{}
+
+	if count > len(reviewers) {
+		exclude := append(slices.Clone(reviewers), excluded...)
+
+		var members []string
+		if pr.ReviewerPool != "" {
+			members, err = s.poolRepo.GetActivePoolMembers(pr.ReviewerPool, exclude)
+		} else {
+			members, err = s.prRepo.GetActiveTeamMembers(teamName, exclude)
+		}
+		if err != nil {
+			log.Error("failed to get available members", sl.Err(err))
+			return nil, nil, nil, nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if len(members) == 0 {
+			log.Warn("no available candidates for extra reviewers")
+			return nil, nil, nil, nil, apperrors.ErrNoReviewerCandidates
+		}
+
+		pool := slices.Clone(members)
+		added, err = s.pickReviewers(policy, members, count-len(reviewers), reviewers)
+		if err != nil {
+			log.Error("failed to pick extra reviewers", sl.Err(err))
+			return nil, nil, nil, nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		traceAssigned(ctx, reviewers)
+		if pr.ReviewerPool != "" {
+			tracePoolPick(ctx, pr.ReviewerPool, count-len(reviewers), exclude, pool, added)
+		} else {
+			s.traceTeamPick(ctx, pr.AuthorID, models.ReviewerTeamQuota{TeamName: teamName, Reviewers: count - len(reviewers)}, exclude, pool, added, log)
+		}
+	} else {
+		if count < policy.MinReviewers {
+			log.Warn("reviewer count below team minimum", slog.Int("min_reviewers", policy.MinReviewers))
+			return nil, nil, nil, nil, apperrors.ErrBelowMinReviewers
+		}
+
+		byRecency, err := s.prRepo.GetReviewersByRecency(prID)
+		if err != nil {
+			log.Error("failed to get reviewers by recency", sl.Err(err))
+			return nil, nil, nil, nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		remaining := slices.Clone(reviewers)
+		var blocked error
+		for _, reviewerID := range byRecency {
+			if len(remaining) == count {
+				break
+			}
+
+			after := withoutReviewer(remaining, reviewerID)
+
+			keeps, err := s.keepsSecurityReviewer(pr, after)
+			if err != nil {
+				log.Error("failed to check security reviewers", sl.Err(err))
+				return nil, nil, nil, nil, fmt.Errorf("%s: %w", op, err)
+			}
+			if !keeps {
+				blocked = apperrors.ErrSecurityReviewerRequired
+				continue
+			}
+
+			lostArea, err := s.lostCertification(pr, remaining, after)
+			if err != nil {
+				log.Error("failed to check certified reviewers", sl.Err(err))
+				return nil, nil, nil, nil, fmt.Errorf("%s: %w", op, err)
+			}
+			if lostArea != "" {
+				blocked = apperrors.ErrCertifiedReviewerRequired
+				continue
+			}
+
+			remaining = after
+			removed = append(removed, reviewerID)
+		}
+
+		if len(remaining) > count {
+			log.Warn("remaining reviewers are required on the PR", sl.Err(blocked))
+			return nil, nil, nil, nil, blocked
+		}
+	}
+
+	err = s.prRepo.ResizeReviewers(prID, added, removed, actorID)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrReviewerAssigned) || errors.Is(err, apperrors.ErrReviewerNotAssigned) {
+			log.Warn("reviewers changed concurrently", sl.Err(err))
+			return nil, nil, nil, nil, err
+		}
+		log.Error("failed to resize reviewers", sl.Err(err))
+		return nil, nil, nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	updatedPR, updatedReviewers, err := s.prRepo.GetPRWithReviewers(prID)
+	if err != nil {
+		log.Error("failed to get updated PR", sl.Err(err))
+		return nil, nil, nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	for _, reviewerID := range removed {
+		s.publisher.Publish(ctx, events.ReviewerUnassigned{
+			PullRequestID: prID,
+			ReviewerID:    reviewerID,
+			ActorID:       actorID,
+		})
+	}
+	for _, reviewerID := range added {
+		s.publisher.Publish(ctx, events.ReviewerAssigned{
+			PullRequestID: prID,
+			ReviewerID:    reviewerID,
+			ActorID:       actorID,
+		})
+	}
+
+	log.Info("reviewer count set successfully",
+		slog.Int("added", len(added)),
+		slog.Int("removed", len(removed)))
+
+	return updatedPR, updatedReviewers, added, removed, nil
+}
+
+func (s *PullRequestService) StartReview(ctx context.Context, prID string, reviewerID string) (time.Time, error) {
+	const op = "service.pullRequest.StartReview"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("pr_id", prID),
+		slog.String("reviewer_id", reviewerID),
+	)
+
+	log.Info("attempting to start review")
+
+	if prID == "" {
+		log.Error("pull request id is required")
+		return time.Time{}, apperrors.ErrPRIDRequired
+	}
+
+	if reviewerID == "" {
+		log.Error("reviewer id is required")
+		return time.Time{}, apperrors.ErrReviewerRequired
+	}
+
+	pr, err := s.prRepo.GetPR(prID)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrPRNotFound) {
+			log.Warn("PR not found")
+			return time.Time{}, apperrors.ErrPRNotFound
+		}
+		log.Error("failed to get PR", sl.Err(err))
+		return time.Time{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	closed, err := s.isClosed(pr)
+	if err != nil {
+		log.Error("failed to check PR status", sl.Err(err))
+		return time.Time{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if closed {
+		log.Warn("cannot start review on closed PR", slog.String("status", pr.Status))
+		return time.Time{}, closedPRError(pr.Status)
+	}
+
+	startedAt, err := s.prRepo.StartReview(prID, reviewerID)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrReviewerNotAssigned) || errors.Is(err, apperrors.ErrInvalidUserID) {
+			log.Warn("reviewer not assigned to this PR")
+			return time.Time{}, apperrors.ErrReviewerNotAssigned
+		}
+		log.Error("failed to start review", sl.Err(err))
+		return time.Time{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.publisher.Publish(ctx, events.ReviewStarted{
+		PullRequestID: prID,
+		ReviewerID:    reviewerID,
+		StartedAt:     startedAt,
+	})
+
+	log.Info("review started", slog.Time("started_at", startedAt))
+	return startedAt, nil
+}
+
+// CompleteReview marks the reviewer's own review done so it no longer counts
+// towards their workload, without waiting for the PR to be merged.
+func (s *PullRequestService) CompleteReview(ctx context.Context, prID string, reviewerID string) (time.Time, error) {
+	const op = "service.pullRequest.CompleteReview"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("pr_id", prID),
+		slog.String("reviewer_id", reviewerID),
+	)
+
+	log.Info("attempting to complete review")
+
+	if prID == "" {
+		log.Error("pull request id is required")
+		return time.Time{}, apperrors.ErrPRIDRequired
+	}
+
+	if reviewerID == "" {
+		log.Error("reviewer id is required")
+		return time.Time{}, apperrors.ErrReviewerRequired
+	}
+
+	pr, err := s.prRepo.GetPR(prID)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrPRNotFound) {
+			log.Warn("PR not found")
+			return time.Time{}, apperrors.ErrPRNotFound
+		}
+		log.Error("failed to get PR", sl.Err(err))
+		return time.Time{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	closed, err := s.isClosed(pr)
+	if err != nil {
+		log.Error("failed to check PR status", sl.Err(err))
+		return time.Time{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if closed {
+		log.Warn("cannot complete review on closed PR", slog.String("status", pr.Status))
+		return time.Time{}, closedPRError(pr.Status)
+	}
+
+	completedAt, err := s.prRepo.CompleteReview(prID, reviewerID)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrReviewerNotAssigned) || errors.Is(err, apperrors.ErrInvalidUserID) {
+			log.Warn("reviewer not assigned to this PR")
+			return time.Time{}, apperrors.ErrReviewerNotAssigned
+		}
+		log.Error("failed to complete review", sl.Err(err))
+		return time.Time{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.publisher.Publish(ctx, events.ReviewCompleted{
+		PullRequestID: prID,
+		ReviewerID:    reviewerID,
+		CompletedAt:   completedAt,
+	})
+
+	log.Info("review completed", slog.Time("completed_at", completedAt))
+	return completedAt, nil
+}
+
+// ApprovePR records an approval by an assigned reviewer. Approvals from the
+// security team unlock merging of PRs under the security review gate.
+func (s *PullRequestService) ApprovePR(ctx context.Context, prID string, reviewerID string) (time.Time, error) {
+	const op = "service.pullRequest.ApprovePR"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("pr_id", prID),
+		slog.String("reviewer_id", reviewerID),
+	)
+
+	log.Info("attempting to approve PR")
+
+	if prID == "" {
+		log.Error("pull request id is required")
+		return time.Time{}, apperrors.ErrPRIDRequired
+	}
+
+	if reviewerID == "" {
+		log.Error("reviewer id is required")
+		return time.Time{}, apperrors.ErrReviewerRequired
+	}
+
+	pr, err := s.prRepo.GetPR(prID)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrPRNotFound) {
+			log.Warn("PR not found")
+			return time.Time{}, apperrors.ErrPRNotFound
+		}
+		log.Error("failed to get PR", sl.Err(err))
+		return time.Time{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	closed, err := s.isClosed(pr)
+	if err != nil {
+		log.Error("failed to check PR status", sl.Err(err))
+		return time.Time{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if closed {
+		log.Warn("cannot approve closed PR", slog.String("status", pr.Status))
+		return time.Time{}, closedPRError(pr.Status)
+	}
+
+	approvedAt, err := s.prRepo.ApprovePR(prID, reviewerID)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrReviewerNotAssigned) || errors.Is(err, apperrors.ErrInvalidUserID) {
+			log.Warn("reviewer not assigned to this PR")
+			return time.Time{}, apperrors.ErrReviewerNotAssigned
+		}
+		log.Error("failed to approve PR", sl.Err(err))
+		return time.Time{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.publisher.Publish(ctx, events.ReviewApproved{
+		PullRequestID: prID,
+		ReviewerID:    reviewerID,
+		ApprovedAt:    approvedAt,
+	})
+
+	log.Info("PR approved", slog.Time("approved_at", approvedAt))
+	return approvedAt, nil
+}
+
+// ExportPRs walks the PRs matching search with keyset pagination and hands
+// every page to emit, so callers can stream arbitrarily large histories with
+// bounded memory.
+func (s *PullRequestService) ExportPRs(ctx context.Context, search models.PRSearch, emit func(page []models.PullRequestExport) error) (int, error) {
+	const op = "service.pullRequest.ExportPRs"
+
+	log := s.log.With(slog.String("op", op))
+
+	if err := validatePRSearch(search); err != nil {
+		log.Warn("invalid PR search", sl.Err(err))
+		return 0, err
+	}
+
+	log.Info("starting PR export")
+
+	var (
+		afterCreatedAt time.Time
+		afterID        string
+		exported       int
+	)
+
+	for {
+		if err := ctx.Err(); err != nil {
+			log.Warn("PR export cancelled", slog.Int("exported", exported))
+			return exported, fmt.Errorf("%s: %w", op, err)
+		}
+
+		page, err := s.prRepo.GetPRExportPage(search, afterCreatedAt, afterID, exportPageSize)
+		if err != nil {
+			log.Error("failed to get export page", sl.Err(err))
+			return exported, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if len(page) == 0 {
+			break
+		}
+
+		if err := emit(page); err != nil {
+			log.Error("failed to emit export page", sl.Err(err))
+			return exported, fmt.Errorf("%s: %w", op, err)
+		}
+
+		exported += len(page)
+		last := page[len(page)-1]
+		afterCreatedAt, afterID = last.CreatedAt, last.PullRequestId
+
+		if len(page) < exportPageSize {
+			break
+		}
+	}
+
+	log.Info("PR export finished", slog.Int("exported", exported))
+	return exported, nil
+}
+
+// GetCandidates ranks possible replacement reviewers for the PR without
+// changing anything. Candidates with fewer open reviews and fewer recent
+// reviews of the same author score higher.
+func (s *PullRequestService) GetCandidates(ctx context.Context, prID string) ([]models.ReviewerCandidate, error) {
+	const op = "service.pullRequest.GetCandidates"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("pr_id", prID),
+	)
+
+	log.Info("attempting to rank reviewer candidates")
+
+	if prID == "" {
+		log.Error("pull request id is required")
+		return nil, apperrors.ErrPRIDRequired
+	}
+
+	pr, reviewers, err := s.prRepo.GetPRWithReviewers(prID)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrPRNotFound) {
+			log.Warn("PR not found", slog.String("pr_id", prID))
+			return nil, apperrors.ErrPRNotFound
+		}
+		log.Error("failed to get PR", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	closed, err := s.isClosed(pr)
+	if err != nil {
+		log.Error("failed to check PR status", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if closed {
+		log.Warn("PR is closed", slog.String("status", pr.Status))
+		return nil, closedPRError(pr.Status)
+	}
+
+	teamName, err := s.authorTeam(pr)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrPRAuthorNotFound) {
+			log.Warn("author not found", slog.String("author_id", pr.AuthorID))
+			return nil, apperrors.ErrPRAuthorNotFound
+		}
+		log.Error("failed to get author team", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	excluded, _, err := s.excludedReviewers(ctx, pr, teamName)
+	if err != nil {
+		log.Error("failed to apply exclusion rules", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	exclude := append(excluded, reviewers...)
+	candidates, err := s.prRepo.GetCandidateStats(teamName, pr.AuthorID, exclude, time.Now().Add(-pairingWindow))
+	if err != nil {
+		log.Error("failed to get candidate stats", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	for i := range candidates {
+		candidates[i].Score = candidateScore(candidates[i])
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Score > candidates[j].Score
+	})
+
+	log.Info("reviewer candidates ranked", slog.Int("candidate_count", len(candidates)))
+
+	return candidates, nil
+}
+
+// applyTeamTemplate fills the PR with the team's defaults: explicit priority
+// wins over the default one, labels and skills are merged.
+func applyTeamTemplate(pr *models.PullRequest, policy *models.TeamPolicy) {
+	if pr.Priority == "" {
+		pr.Priority = policy.DefaultPriority
+	}
+	if pr.Priority == "" {
+		pr.Priority = models.PriorityNormal
+	}
+
+	pr.Labels = models.MergeTags(policy.DefaultLabels, pr.Labels)
+	pr.RequiredSkills = models.MergeTags(policy.DefaultRequiredSkills, pr.RequiredSkills)
+}
+
+// resolveReviewerTeams builds the reviewer quotas of a new PR: the author's
+// team first with its reviewers-per-PR policy, then explicitly requested
+// teams, then teams reviewing one of the PR labels with a single reviewer
+// each.
+func (s *PullRequestService) resolveReviewerTeams(authorTeam string, reviewersPerPR int, requested []models.ReviewerTeamQuota, labels []string) ([]models.ReviewerTeamQuota, error) {
+	quotas := []models.ReviewerTeamQuota{{TeamName: authorTeam, Reviewers: reviewersPerPR}}
+	index := map[string]int{authorTeam: 0}
+
+	for _, quota := range requested {
+		if quota.TeamName == "" || quota.Reviewers < 1 || quota.Reviewers > maxReviewersPerTeam {
+			return nil, apperrors.ErrInvalidReviewerTeams
+		}
+
+		if i, ok := index[quota.TeamName]; ok {
+			if i != 0 {
+				return nil, apperrors.ErrInvalidReviewerTeams
+			}
+			quotas[0].Reviewers = quota.Reviewers
+			continue
+		}
+
+		exists, err := s.teamRepo.TeamExists(quota.TeamName)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, fmt.Errorf("%w: %s", apperrors.ErrReviewerTeamNotFound, quota.TeamName)
+		}
+
+		index[quota.TeamName] = len(quotas)
+		quotas = append(quotas, quota)
+	}
+
+	if len(labels) > 0 {
+		labelTeams, err := s.teamRepo.GetLabelReviewTeams(labels)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, team := range labelTeams {
+			if _, ok := index[team]; ok {
+				continue
+			}
+			index[team] = len(quotas)
+			quotas = append(quotas, models.ReviewerTeamQuota{TeamName: team, Reviewers: 1})
+		}
+	}
+
+	return quotas, nil
+}
+
+// selectTeamReviewers fills every quota with random active members of the
+// team. Only an empty author team is an error: other teams that have nobody
+// available are skipped. A PR targeting a reviewer pool gets the pool's
+// reviewers in place of the author team's quota.
+func (s *PullRequestService) selectTeamReviewers(ctx context.Context, pr *models.PullRequest, policy *models.TeamPolicy, excluded []string, authorTeam string, log *slog.Logger) ([]string, error) {
+	quotas := pr.ReviewerTeams
+	if len(quotas) == 0 {
+		quotas = []models.ReviewerTeamQuota{{TeamName: authorTeam, Reviewers: policy.ReviewersPerPR}}
+	}
+
+	selected := make([]string, 0)
+	if pr.ReviewerPool != "" {
+		picked, err := s.selectPoolReviewers(ctx, pr, policy, excluded)
+		if err != nil {
+			return nil, err
+		}
+		selected = append(selected, picked...)
+	}
+
+	for _, quota := range quotas {
+		if pr.ReviewerPool != "" && quota.TeamName == authorTeam {
+			continue
+		}
+
+		exclude := append(slices.Clone(excluded), selected...)
+		members, err := s.candidates.Members(quota.TeamName, func(teamName string) ([]string, error) {
+			return s.prRepo.GetActiveTeamMembers(teamName, nil)
+		})
+		if err != nil {
+			return nil, err
+		}
+		members = slices.DeleteFunc(members, func(member string) bool {
+			return slices.Contains(exclude, member)
+		})
+
+		if len(members) == 0 {
+			if quota.TeamName == authorTeam {
+				return nil, apperrors.ErrNoReviewerCandidates
+			}
+			log.Warn("no active members available in reviewer team",
+				slog.String("reviewer_team", quota.TeamName))
+			s.traceTeamPick(ctx, pr.AuthorID, quota, exclude, members, nil, log)
+			continue
+		}
+
+		picked, err := s.pickReviewers(policy, members, quota.Reviewers, selected)
+		if err != nil {
+			return nil, err
+		}
+		s.traceTeamPick(ctx, pr.AuthorID, quota, exclude, members, picked, log)
+		selected = append(selected, picked...)
+	}
+
+	return selected, nil
+}
+
+// pickReplacement picks who takes over the review of oldReviewerID. A reviewer
+// requested from another team is replaced from that same team, a reviewer from
+// the PR's pool from the pool, and one carrying a required certification by a
+// user certified for that area.
+func (s *PullRequestService) pickReplacement(ctx context.Context, pr *models.PullRequest, reviewers []string, oldReviewerID string, log *slog.Logger) (string, error) {
+	const op = "service.pullRequest.pickReplacement"
+
+	teamName, err := s.authorTeam(pr)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	excluded, policy, err := s.excludedReviewers(ctx, pr, teamName)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+	traceAssigned(ctx, reviewers)
+
+	fromPool := false
+	if pr.ReviewerPool != "" {
+		fromPool, err = s.poolRepo.IsPoolMember(pr.ReviewerPool, oldReviewerID)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", op, err)
+		}
+	}
+	if oldReviewerTeam, inTeam, err := s.reviewerTeamFor(pr, oldReviewerID, teamName); err == nil && inTeam && !fromPool {
+		teamName = oldReviewerTeam
+	}
+
+	lostArea, err := s.lostCertification(pr, reviewers, withoutReviewer(reviewers, oldReviewerID))
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	exclude := append(slices.Clone(reviewers), excluded...)
+
+	var availableMembers []string
+	if lostArea != "" {
+		availableMembers, err = s.certRepo.GetCertifiedReviewers(lostArea, exclude)
+	} else if fromPool {
+		availableMembers, err = s.poolRepo.GetActivePoolMembers(pr.ReviewerPool, exclude)
+	} else {
+		availableMembers, err = s.prRepo.GetActiveTeamMembers(teamName, exclude)
+	}
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(availableMembers) == 0 {
+		return "", apperrors.ErrNoReviewerCandidates
+	}
+
+	// A certified replacement is forced by the area; otherwise the new
+	// reviewer pairs with the ones staying on the PR.
+	pool := slices.Clone(availableMembers)
+	var newReviewer string
+	if lostArea != "" {
+		newReviewer = s.selectRandomReviewer(availableMembers)
+	} else {
+		picked, err := s.pickReviewers(policy, availableMembers, 1, withoutReviewer(reviewers, oldReviewerID))
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", op, err)
+		}
+		newReviewer = picked[0]
+	}
+
+	if lostArea != "" {
+		traceCertifiedPick(ctx, lostArea, exclude, pool, newReviewer)
+	} else if fromPool {
+		tracePoolPick(ctx, pr.ReviewerPool, 1, exclude, pool, []string{newReviewer})
+	} else {
+		s.traceTeamPick(ctx, pr.AuthorID, models.ReviewerTeamQuota{TeamName: teamName, Reviewers: 1}, exclude, pool, []string{newReviewer}, log)
+	}
+
+	return newReviewer, nil
+}
+
+// observeCreateLatency records one create-and-assign call and warns when it
+// went over the latency budget. A zero budget only records.
+func (s *PullRequestService) observeCreateLatency(log *slog.Logger, elapsed time.Duration, selection time.Duration) {
+	createPRTotal.Add(1)
+	createPRDurationUS.Add(elapsed.Microseconds())
+
+	if s.latencyBudget <= 0 || elapsed <= s.latencyBudget {
+		return
+	}
+
+	createPROverBudget.Add(1)
+
+	log.Warn("create-and-assign exceeded latency budget",
+		slog.Duration("duration", elapsed),
+		slog.Duration("selection", selection),
+		slog.Duration("budget", s.latencyBudget))
+}
+
+func withoutReviewer(reviewers []string, reviewerID string) []string {
+	remaining := make([]string, 0, len(reviewers))
+	for _, reviewer := range reviewers {
+		if reviewer != reviewerID {
+			remaining = append(remaining, reviewer)
+		}
+	}
+	return remaining
+}
+
+// authorTeam returns the team the PR was opened for, falling back to the
+// author's primary team for PRs that did not record it.
+func (s *PullRequestService) authorTeam(pr *models.PullRequest) (string, error) {
+	if pr.AuthorTeam != "" {
+		return pr.AuthorTeam, nil
+	}
+	return s.prRepo.GetAuthorTeam(pr.AuthorID)
+}
+
+// reviewerTeamFor returns the team the user reviews the PR for: the PR's own
+// team when they are in it, otherwise the first of their teams the PR
+// requested reviewers from. When they are in neither, inTeam is false and the
+// team is their primary one.
+func (s *PullRequestService) reviewerTeamFor(pr *models.PullRequest, userID string, authorTeam string) (string, bool, error) {
+	teams, err := s.prRepo.GetUserTeams(userID)
+	if err != nil {
+		return "", false, err
+	}
+
+	if slices.Contains(teams, authorTeam) {
+		return authorTeam, true, nil
+	}
+
+	for _, team := range teams {
+		if hasReviewerTeam(pr, team) {
+			return team, true, nil
+		}
+	}
+
+	return teams[0], false, nil
+}
+
+func hasReviewerTeam(pr *models.PullRequest, teamName string) bool {
+	for _, quota := range pr.ReviewerTeams {
+		if quota.TeamName == teamName {
+			return true
+		}
+	}
+	return false
+}
+
+// candidateScore prefers reviewers with little open work. Reviews already in
+// progress count twice: those reviewers are busy right now.
+func candidateScore(c models.ReviewerCandidate) float64 {
+	load := float64(c.OpenReviews + c.InProgress)
+	return 1 / (1 + load) / (1 + 0.5*float64(c.RecentPairings))
+}
+
+// pickReviewers picks count random members. Under a pair diversity policy
+// every pick is instead the member who reviewed least often together with
+// partners and the members picked before it, with random tie-breaks.
+func (s *PullRequestService) pickReviewers(policy *models.TeamPolicy, members []string, count int, partners []string) ([]string, error) {
+	if !policy.DiversifyPairs || (len(partners) == 0 && count < 2) {
+		return s.selectRandomReviewers(members, count), nil
+	}
+
+	pairs, err := s.prRepo.GetReviewerPairs(append(slices.Clone(members), partners...), time.Now().Add(-pairingWindow))
+	if err != nil {
+		return nil, err
+	}
+
+	type pair struct{ a, b string }
+	coReviews := make(map[pair]int, 2*len(pairs))
+	for _, p := range pairs {
+		coReviews[pair{p.FirstID, p.SecondID}] = p.CoReviews
+		coReviews[pair{p.SecondID, p.FirstID}] = p.CoReviews
+	}
+
+	available := s.selectRandomReviewers(members, len(members))
+	chosen := slices.Clone(partners)
+	picked := make([]string, 0, count)
+	for len(picked) < count && len(available) > 0 {
+		best, bestScore := 0, -1
+		for i, member := range available {
+			score := 0
+			for _, other := range chosen {
+				score += coReviews[pair{member, other}]
+			}
+			if bestScore < 0 || score < bestScore {
+				best, bestScore = i, score
+			}
+		}
+
+		picked = append(picked, available[best])
+		chosen = append(chosen, available[best])
+		available = slices.Delete(available, best, best+1)
+	}
+
+	return picked, nil
+}
+
+func (s *PullRequestService) selectRandomReviewers(members []string, max int) []string {
+	if len(members) <= max {
+		shuffled := make([]string, len(members))
+		copy(shuffled, members)
+		rand.New(rand.NewSource(time.Now().UnixNano())).Shuffle(len(shuffled), func(i, j int) {
+			shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+		})
+		return shuffled
+	}
+
+	selected := make([]string, max)
+	available := make([]string, len(members))
+	copy(available, members)
+
+	rand.New(rand.NewSource(time.Now().UnixNano())).Shuffle(len(available), func(i, j int) {
 		available[i], available[j] = available[j], available[i]
 	})
 
@@ -287,3 +2371,20 @@ func (s *PullRequestService) selectRandomReviewer(members []string) string {
 	})
 	return members[0]
 }
+
+func validatePRSearch(search models.PRSearch) error {
+	if search.ReviewerID != "" {
+		if _, err := models.ParseUserID(search.ReviewerID); err != nil {
+			return apperrors.ErrInvalidUserID
+		}
+	}
+
+	if search.CreatedFrom != nil && search.CreatedTo != nil && !search.CreatedFrom.Before(*search.CreatedTo) {
+		return fmt.Errorf("%w: created_from must be before created_to", apperrors.ErrInvalidPRSearch)
+	}
+	if search.MergedFrom != nil && search.MergedTo != nil && !search.MergedFrom.Before(*search.MergedTo) {
+		return fmt.Errorf("%w: merged_from must be before merged_to", apperrors.ErrInvalidPRSearch)
+	}
+
+	return nil
+}
@@ -0,0 +1,231 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"pull-request-assigner/internal/apperrors"
+	"pull-request-assigner/internal/domain/events"
+	"pull-request-assigner/internal/domain/models"
+	"pull-request-assigner/internal/lib/logger/sl"
+	"slices"
+	"sort"
+)
+
+// rebalancePR is the state of one PR while moves are being planned.
+type rebalancePR struct {
+	pr        *models.PullRequest
+	reviewers []string
+	excluded  []string
+}
+
+// RebalanceTeam moves open, untouched assignments within the team so that no
+// member carries two or more reviews more than another. Inactive members hand
+// over everything they can; nobody is given a review beyond the open review
+// cap, one excluded by the PR's exclusion rules, or one that would leave a
+// required certification uncovered. With dryRun the moves are only proposed.
+func (s *PullRequestService) RebalanceTeam(ctx context.Context, teamName string, dryRun bool) (*models.RebalancePlan, error) {
+	const op = "service.pullRequest.RebalanceTeam"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("team_name", teamName),
+		slog.Bool("dry_run", dryRun),
+	)
+
+	log.Info("attempting to rebalance team workload")
+
+	if teamName == "" {
+		log.Error("team name is required")
+		return nil, apperrors.ErrTeamNameRequired
+	}
+
+	if _, err := s.teamRepo.GetTeamPolicy(teamName); err != nil {
+		if errors.Is(err, apperrors.ErrTeamNotFound) {
+			log.Warn("team not found")
+			return nil, apperrors.ErrTeamNotFound
+		}
+		log.Error("failed to get team policy", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	members, assignments, err := s.prRepo.GetTeamWorkload(teamName)
+	if err != nil {
+		log.Error("failed to get team workload", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	load := make(map[string]int, len(members))
+	active := make(map[string]bool, len(members))
+	for _, member := range members {
+		if member.IsActive || member.OpenReviews > 0 {
+			load[member.UserID] = member.OpenReviews
+		}
+		active[member.UserID] = member.IsActive
+	}
+
+	plan := &models.RebalancePlan{
+		TeamName:   teamName,
+		DryRun:     dryRun,
+		Moves:      make([]models.RebalanceMove, 0),
+		LoadBefore: make(map[string]int, len(load)),
+	}
+	for userID, n := range load {
+		plan.LoadBefore[userID] = n
+	}
+
+	prs := make(map[string]*rebalancePR)
+	for {
+		move, index, err := s.nextRebalanceMove(ctx, assignments, load, active, prs)
+		if err != nil {
+			log.Error("failed to plan rebalance", sl.Err(err))
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		if move == nil {
+			break
+		}
+
+		state := prs[move.PullRequestId]
+		state.reviewers = append(withoutReviewer(state.reviewers, move.FromReviewerID), move.ToReviewerID)
+		load[move.FromReviewerID]--
+		load[move.ToReviewerID]++
+		assignments = slices.Delete(assignments, index, index+1)
+
+		plan.Moves = append(plan.Moves, *move)
+	}
+
+	plan.LoadAfter = load
+
+	if dryRun {
+		log.Info("rebalance planned", slog.Int("moves", len(plan.Moves)))
+		return plan, nil
+	}
+
+	// Rebalancing only ever moves reviews off overloaded reviewers.
+	for _, move := range plan.Moves {
+		if err := s.prRepo.ReplaceReviewer(move.PullRequestId, move.FromReviewerID, move.ToReviewerID, models.ReassignReasonOverloaded, ""); err != nil {
+			log.Error("failed to apply rebalance move",
+				slog.String("pr_id", move.PullRequestId),
+				slog.String("from_reviewer_id", move.FromReviewerID),
+				slog.String("to_reviewer_id", move.ToReviewerID),
+				sl.Err(err))
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		s.publisher.Publish(ctx, events.ReviewerReassigned{
+			PullRequestID: move.PullRequestId,
+			OldReviewerID: move.FromReviewerID,
+			NewReviewerID: move.ToReviewerID,
+			Reason:        models.ReassignReasonOverloaded,
+		})
+	}
+
+	log.Info("team workload rebalanced", slog.Int("moves", len(plan.Moves)))
+
+	return plan, nil
+}
+
+// nextRebalanceMove picks the assignment to move next: inactive holders
+// first, then the most loaded member, each giving it to the least loaded
+// eligible member. It returns nil when no move improves the balance.
+func (s *PullRequestService) nextRebalanceMove(
+	ctx context.Context,
+	assignments []models.MovableAssignment,
+	load map[string]int,
+	active map[string]bool,
+	prs map[string]*rebalancePR,
+) (*models.RebalanceMove, int, error) {
+	order := make([]int, len(assignments))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := assignments[order[i]].ReviewerID, assignments[order[j]].ReviewerID
+		if active[a] != active[b] {
+			return !active[a]
+		}
+		return load[a] > load[b]
+	})
+
+	targets := make([]string, 0, len(active))
+	for userID, isActive := range active {
+		if isActive {
+			targets = append(targets, userID)
+		}
+	}
+	sort.Slice(targets, func(i, j int) bool {
+		if load[targets[i]] != load[targets[j]] {
+			return load[targets[i]] < load[targets[j]]
+		}
+		return targets[i] < targets[j]
+	})
+
+	for _, index := range order {
+		assignment := assignments[index]
+		from := assignment.ReviewerID
+
+		state, err := s.rebalanceState(ctx, prs, assignment.PullRequestId)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		for _, to := range targets {
+			if to == from {
+				continue
+			}
+			if active[from] && load[from]-load[to] < 2 {
+				// targets are sorted by load, so nobody further is lighter.
+				break
+			}
+			if s.maxOpenReviews > 0 && load[to] >= s.maxOpenReviews {
+				break
+			}
+			if slices.Contains(state.reviewers, to) || slices.Contains(state.excluded, to) {
+				continue
+			}
+
+			replaced := append(withoutReviewer(state.reviewers, from), to)
+			lost, err := s.lostCertification(state.pr, state.reviewers, replaced)
+			if err != nil {
+				return nil, 0, err
+			}
+			if lost != "" {
+				continue
+			}
+
+			return &models.RebalanceMove{
+				PullRequestId:  assignment.PullRequestId,
+				FromReviewerID: from,
+				ToReviewerID:   to,
+			}, index, nil
+		}
+	}
+
+	return nil, 0, nil
+}
+
+func (s *PullRequestService) rebalanceState(ctx context.Context, prs map[string]*rebalancePR, prID string) (*rebalancePR, error) {
+	if state, ok := prs[prID]; ok {
+		return state, nil
+	}
+
+	pr, reviewers, err := s.prRepo.GetPRWithReviewers(prID)
+	if err != nil {
+		return nil, err
+	}
+
+	authorTeam, err := s.authorTeam(pr)
+	if err != nil {
+		return nil, err
+	}
+
+	excluded, _, err := s.excludedReviewers(ctx, pr, authorTeam)
+	if err != nil {
+		return nil, err
+	}
+
+	state := &rebalancePR{pr: pr, reviewers: reviewers, excluded: excluded}
+	prs[prID] = state
+	return state, nil
+}
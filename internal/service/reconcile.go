@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"pull-request-assigner/internal/apperrors"
+	"pull-request-assigner/internal/domain/events"
+	"pull-request-assigner/internal/domain/models"
+	"pull-request-assigner/internal/lib/logger/sl"
+)
+
+const maxReconcileClaims = 1000
+
+// ReconcileStatuses brings stored PR statuses in line with an external
+// source of truth, such as the forge after missed webhooks. The claims win
+// over the local state, so status transitions are not checked; all
+// corrections are written in one transaction, and then published like the
+// regular status changes and merges so subscribers catch up as well.
+func (s *PullRequestService) ReconcileStatuses(ctx context.Context, claims []models.PRStatusClaim, dryRun bool) (*models.ReconcileReport, error) {
+	const op = "service.pullRequest.ReconcileStatuses"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int("claims", len(claims)),
+		slog.Bool("dry_run", dryRun),
+	)
+
+	if err := s.validateClaims(claims); err != nil {
+		if errors.Is(err, apperrors.ErrInvalidReconcile) {
+			log.Warn("invalid reconciliation request", sl.Err(err))
+			return nil, err
+		}
+		log.Error("failed to validate reconciliation request", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	results, err := s.prRepo.ReconcileStatuses(claims, dryRun)
+	if err != nil {
+		log.Error("failed to reconcile PR statuses", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	report := &models.ReconcileReport{
+		DryRun:  dryRun,
+		Checked: len(results),
+		Results: results,
+	}
+	for _, result := range results {
+		switch result.Outcome {
+		case models.ReconcileCorrected:
+			report.Corrected++
+		case models.ReconcileUnchanged:
+			report.Unchanged++
+		case models.ReconcileNotFound:
+			report.NotFound++
+		}
+	}
+
+	if dryRun {
+		log.Info("reconciliation planned", slog.Int("corrected", report.Corrected))
+		return report, nil
+	}
+
+	for _, result := range results {
+		if result.Outcome != models.ReconcileCorrected {
+			continue
+		}
+
+		recordAudit(ctx, s.publisher, models.AuditEvent{
+			Action:  models.AuditPRStatusReconciled,
+			Details: result.PullRequestID + ": " + result.PreviousStatus + " -> " + result.Status,
+		})
+
+		if result.PreviousStatus == result.Status {
+			// Only merged_at was corrected.
+			continue
+		}
+
+		pr, reviewers, err := s.prRepo.GetPRWithReviewers(result.PullRequestID)
+		if err != nil {
+			// The corrections are committed; only the notifications are lost.
+			log.Error("failed to load reconciled PR", slog.String("pr_id", result.PullRequestID), sl.Err(err))
+			continue
+		}
+
+		if result.Status == models.PRStatusMerged {
+			s.publisher.Publish(ctx, events.PullRequestMerged{
+				PullRequestID: result.PullRequestID,
+				Reviewers:     reviewers,
+				MergedBy:      pr.MergedBy,
+				MergedAt:      pr.MergedAt.Time,
+			})
+			continue
+		}
+
+		s.publisher.Publish(ctx, events.PullRequestStatusChanged{
+			PullRequestID: result.PullRequestID,
+			Status:        result.Status,
+			Reviewers:     reviewers,
+		})
+	}
+
+	log.Info("PR statuses reconciled",
+		slog.Int("corrected", report.Corrected),
+		slog.Int("not_found", report.NotFound))
+
+	return report, nil
+}
+
+func (s *PullRequestService) validateClaims(claims []models.PRStatusClaim) error {
+	if len(claims) == 0 {
+		return fmt.Errorf("%w: at least one pull request is required", apperrors.ErrInvalidReconcile)
+	}
+	if len(claims) > maxReconcileClaims {
+		return fmt.Errorf("%w: at most %d pull requests per request", apperrors.ErrInvalidReconcile, maxReconcileClaims)
+	}
+
+	seen := make(map[string]bool, len(claims))
+	known := make(map[string]bool)
+	for _, claim := range claims {
+		if claim.PullRequestID == "" {
+			return fmt.Errorf("%w: pull_request_id is required", apperrors.ErrInvalidReconcile)
+		}
+		if seen[claim.PullRequestID] {
+			return fmt.Errorf("%w: %s is listed twice", apperrors.ErrInvalidReconcile, claim.PullRequestID)
+		}
+		seen[claim.PullRequestID] = true
+
+		if claim.Status == "" {
+			return fmt.Errorf("%w: status of %s is required", apperrors.ErrInvalidReconcile, claim.PullRequestID)
+		}
+		if claim.MergedAt != nil && claim.Status != models.PRStatusMerged {
+			return fmt.Errorf("%w: merged_at of %s needs the MERGED status", apperrors.ErrInvalidReconcile, claim.PullRequestID)
+		}
+
+		if _, checked := known[claim.Status]; !checked {
+			exists, err := s.statusRepo.StatusExists(claim.Status)
+			if err != nil {
+				return err
+			}
+			known[claim.Status] = exists
+		}
+		if !known[claim.Status] {
+			return fmt.Errorf("%w: unknown status %s of %s", apperrors.ErrInvalidReconcile, claim.Status, claim.PullRequestID)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"pull-request-assigner/internal/domain/events"
+	"sync"
+)
+
+// ReviewWatcher wakes up callers waiting for a change of a user's review set.
+// It only signals that something changed; waiters re-read the set themselves.
+type ReviewWatcher struct {
+	mu       sync.Mutex
+	nextID   int
+	watchers map[string]map[int]chan struct{}
+}
+
+func NewReviewWatcher() *ReviewWatcher {
+	return &ReviewWatcher{
+		watchers: make(map[string]map[int]chan struct{}),
+	}
+}
+
+// Watch returns a channel that is closed on the next change of the user's
+// review set, and a function releasing the watch if it is no longer needed.
+func (w *ReviewWatcher) Watch(userID string) (<-chan struct{}, func()) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	id := w.nextID
+	w.nextID++
+
+	ch := make(chan struct{})
+	if w.watchers[userID] == nil {
+		w.watchers[userID] = make(map[int]chan struct{})
+	}
+	w.watchers[userID][id] = ch
+
+	return ch, func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+
+		delete(w.watchers[userID], id)
+		if len(w.watchers[userID]) == 0 {
+			delete(w.watchers, userID)
+		}
+	}
+}
+
+// Notify wakes up everyone watching the given users. A nil watcher ignores
+// notifications.
+func (w *ReviewWatcher) Notify(userIDs ...string) {
+	if w == nil {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, userID := range userIDs {
+		for id, ch := range w.watchers[userID] {
+			close(ch)
+			delete(w.watchers[userID], id)
+		}
+		delete(w.watchers, userID)
+	}
+}
+
+// Handle is the bus subscriber waking up users whose review set an event
+// touched.
+func (w *ReviewWatcher) Handle(_ context.Context, event events.Event) {
+	switch e := event.(type) {
+	case events.PullRequestCreated:
+		w.Notify(e.Reviewers...)
+	case events.PullRequestMerged:
+		w.Notify(e.Reviewers...)
+	case events.PullRequestStatusChanged:
+		w.Notify(e.Reviewers...)
+	case events.ReviewersReleased:
+		w.Notify(e.Reviewers...)
+	case events.ReviewerReassigned:
+		w.Notify(e.OldReviewerID, e.NewReviewerID)
+	case events.ReviewerAssigned:
+		w.Notify(e.ReviewerID, e.ReplaceReviewerID)
+	case events.ReviewerDelegated:
+		w.Notify(e.ReviewerID, e.DelegateID)
+	case events.ReviewerUnassigned:
+		w.Notify(e.ReviewerID)
+	case events.ReviewStarted:
+		w.Notify(e.ReviewerID)
+	case events.ReviewCompleted:
+		w.Notify(e.ReviewerID)
+	}
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"pull-request-assigner/internal/domain/models"
+	"pull-request-assigner/internal/lib/logger/sl"
+	"sync"
+	"time"
+)
+
+type JobProvider interface {
+	RegisterJob(name string, interval time.Duration, nextRunAt time.Time) error
+	ClaimRun(name string, interval time.Duration, early time.Duration) (bool, error)
+	RecordRun(run models.JobRun) error
+}
+
+// jobClaimSlack is the share of the interval a run may be claimed early by,
+// so an instance whose ticker fires just before next_run_at does not skip a
+// whole interval.
+const jobClaimSlack = 10
+
+type scheduledJob struct {
+	name     string
+	interval time.Duration
+	run      func(ctx context.Context) error
+}
+
+// Scheduler runs background jobs on fixed intervals and records every run in
+// the jobs table so operators can check automation health via /admin/jobs.
+// Every instance ticks every job, but a tick runs the job only after
+// claiming it in the jobs table, so each run happens on one instance.
+type Scheduler struct {
+	log     *slog.Logger
+	jobRepo JobProvider
+	jobs    []scheduledJob
+}
+
+func NewScheduler(
+	log *slog.Logger,
+	jobRepo JobProvider) *Scheduler {
+	return &Scheduler{
+		log:     log,
+		jobRepo: jobRepo,
+	}
+}
+
+// Register adds a job. It must be called before Run; a non-positive interval
+// disables the job.
+func (s *Scheduler) Register(name string, interval time.Duration, run func(ctx context.Context) error) {
+	if interval <= 0 {
+		s.log.Info("job disabled", slog.String("job", name))
+		return
+	}
+
+	s.jobs = append(s.jobs, scheduledJob{name: name, interval: interval, run: run})
+}
+
+// Run starts every registered job and blocks until ctx is cancelled and all
+// in-flight runs have finished.
+func (s *Scheduler) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+
+	for _, job := range s.jobs {
+		wg.Add(1)
+		go func(job scheduledJob) {
+			defer wg.Done()
+			s.loop(ctx, job)
+		}(job)
+	}
+
+	wg.Wait()
+}
+
+func (s *Scheduler) loop(ctx context.Context, job scheduledJob) {
+	const op = "service.scheduler.loop"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("job", job.name),
+	)
+
+	if err := s.jobRepo.RegisterJob(job.name, job.interval, time.Now().Add(job.interval)); err != nil {
+		log.Error("failed to register job", sl.Err(err))
+	}
+
+	ticker := time.NewTicker(job.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.runIfClaimed(ctx, job, log)
+		}
+	}
+}
+
+// runIfClaimed runs the job unless another instance has claimed this run.
+// A failed claim skips the run: the jobs need the database anyway.
+func (s *Scheduler) runIfClaimed(ctx context.Context, job scheduledJob, log *slog.Logger) {
+	claimed, err := s.jobRepo.ClaimRun(job.name, job.interval, job.interval/jobClaimSlack)
+	if err != nil {
+		log.Error("failed to claim job run", sl.Err(err))
+		return
+	}
+
+	if !claimed {
+		log.Debug("job run claimed by another instance")
+		return
+	}
+
+	s.runOnce(ctx, job, log)
+}
+
+func (s *Scheduler) runOnce(ctx context.Context, job scheduledJob, log *slog.Logger) {
+	started := time.Now()
+	err := job.run(ctx)
+	duration := time.Since(started)
+
+	run := models.JobRun{
+		Name:      job.name,
+		Interval:  job.interval,
+		StartedAt: started,
+		Duration:  duration,
+		Outcome:   models.JobOutcomeSuccess,
+		NextRunAt: started.Add(job.interval),
+	}
+
+	if err != nil {
+		run.Outcome = models.JobOutcomeFailure
+		run.Error = err.Error()
+		log.Error("job failed", sl.Err(err), slog.Duration("duration", duration))
+	} else {
+		log.Debug("job finished", slog.Duration("duration", duration))
+	}
+
+	if err := s.jobRepo.RecordRun(run); err != nil {
+		log.Error("failed to record job run", sl.Err(err))
+	}
+}
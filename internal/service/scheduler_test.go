@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"pull-request-assigner/internal/domain/models"
+	"sync"
+	"testing"
+	"time"
+)
+
+// jobRepoFake is the jobs table shared by the schedulers of several
+// instances, on a clock the test moves.
+type jobRepoFake struct {
+	mu        sync.Mutex
+	now       time.Time
+	nextRunAt map[string]time.Time
+	runs      []models.JobRun
+}
+
+func newJobRepoFake() *jobRepoFake {
+	return &jobRepoFake{
+		now:       time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC),
+		nextRunAt: make(map[string]time.Time),
+	}
+}
+
+func (f *jobRepoFake) RegisterJob(name string, interval time.Duration, nextRunAt time.Time) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.nextRunAt[name]; !ok {
+		f.nextRunAt[name] = nextRunAt
+	}
+	return nil
+}
+
+func (f *jobRepoFake) ClaimRun(name string, interval time.Duration, early time.Duration) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if next, ok := f.nextRunAt[name]; ok && next.After(f.now.Add(early)) {
+		return false, nil
+	}
+	f.nextRunAt[name] = f.now.Add(interval)
+	return true, nil
+}
+
+func (f *jobRepoFake) RecordRun(run models.JobRun) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.runs = append(f.runs, run)
+	return nil
+}
+
+func (f *jobRepoFake) advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.now = f.now.Add(d)
+}
+
+func newTestScheduler(jobRepo JobProvider) *Scheduler {
+	return NewScheduler(slog.New(slog.NewTextHandler(io.Discard, nil)), jobRepo)
+}
+
+func TestSchedulerRunsEachRunOnOneInstance(t *testing.T) {
+	ctx := context.Background()
+	jobRepo := newJobRepoFake()
+
+	var runs int
+	job := scheduledJob{name: "usage_flush", interval: time.Minute, run: func(ctx context.Context) error {
+		runs++
+		return nil
+	}}
+
+	instances := []*Scheduler{newTestScheduler(jobRepo), newTestScheduler(jobRepo), newTestScheduler(jobRepo)}
+	for range instances {
+		if err := jobRepo.RegisterJob(job.name, job.interval, jobRepo.now.Add(job.interval)); err != nil {
+			t.Fatalf("failed to register job: %v", err)
+		}
+	}
+
+	tick := func() {
+		for _, s := range instances {
+			s.runIfClaimed(ctx, job, s.log)
+		}
+	}
+
+	tick()
+	if runs != 0 {
+		t.Fatalf("expected no run before next_run_at, got %d", runs)
+	}
+
+	for i := 1; i <= 5; i++ {
+		jobRepo.advance(time.Minute)
+		tick()
+		if runs != i {
+			t.Fatalf("after %d intervals expected %d runs, got %d", i, i, runs)
+		}
+	}
+
+	if len(jobRepo.runs) != 5 {
+		t.Fatalf("expected 5 recorded runs, got %d", len(jobRepo.runs))
+	}
+}
+
+func TestSchedulerClaimsSlightlyEarlyTick(t *testing.T) {
+	ctx := context.Background()
+	jobRepo := newJobRepoFake()
+	s := newTestScheduler(jobRepo)
+
+	var runs int
+	job := scheduledJob{name: "auto_merge", interval: time.Minute, run: func(ctx context.Context) error {
+		runs++
+		return nil
+	}}
+
+	if err := jobRepo.RegisterJob(job.name, job.interval, jobRepo.now.Add(job.interval)); err != nil {
+		t.Fatalf("failed to register job: %v", err)
+	}
+
+	jobRepo.advance(time.Minute - time.Second)
+	s.runIfClaimed(ctx, job, s.log)
+	if runs != 1 {
+		t.Fatalf("expected a tick a second early to run the job, got %d runs", runs)
+	}
+
+	jobRepo.advance(30 * time.Second)
+	s.runIfClaimed(ctx, job, s.log)
+	if runs != 1 {
+		t.Fatalf("expected a tick half an interval early to be skipped, got %d runs", runs)
+	}
+}
+
+func TestSchedulerRecordsFailure(t *testing.T) {
+	jobRepo := newJobRepoFake()
+	s := newTestScheduler(jobRepo)
+
+	job := scheduledJob{name: "stats_snapshot", interval: time.Minute, run: func(ctx context.Context) error {
+		return errors.New("snapshot failed")
+	}}
+
+	s.runIfClaimed(context.Background(), job, s.log)
+
+	if len(jobRepo.runs) != 1 {
+		t.Fatalf("expected 1 recorded run, got %d", len(jobRepo.runs))
+	}
+	if run := jobRepo.runs[0]; run.Outcome != models.JobOutcomeFailure || run.Error != "snapshot failed" {
+		t.Fatalf("expected a recorded failure, got %+v", run)
+	}
+}
+
+func TestSchedulerRunStopsOnCancel(t *testing.T) {
+	jobRepo := newJobRepoFake()
+	s := newTestScheduler(jobRepo)
+
+	ran := make(chan struct{}, 1)
+	s.Register("disabled", 0, func(ctx context.Context) error {
+		t.Error("a job with a non-positive interval must not run")
+		return nil
+	})
+	s.Register("dashboard_rebuild", 5*time.Millisecond, func(ctx context.Context) error {
+		select {
+		case ran <- struct{}{}:
+		default:
+		}
+		return nil
+	})
+
+	if len(s.jobs) != 1 {
+		t.Fatalf("expected only the enabled job to be registered, got %d", len(s.jobs))
+	}
+
+	// The fake clock stands still, so only the first tick finds the run due.
+	jobRepo.nextRunAt["dashboard_rebuild"] = jobRepo.now
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-ran:
+	case <-time.After(time.Second):
+		t.Fatal("job did not run")
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run did not return after cancel")
+	}
+}
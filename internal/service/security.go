@@ -0,0 +1,71 @@
+package service
+
+import (
+	"pull-request-assigner/internal/domain/models"
+	"slices"
+	"strings"
+)
+
+// SecurityReviewPolicy describes PRs that need a reviewer from the security
+// team: those carrying one of Labels or touching a path under PathPrefixes.
+// An empty TeamName disables the gate.
+type SecurityReviewPolicy struct {
+	TeamName     string
+	Labels       []string
+	PathPrefixes []string
+}
+
+func (p SecurityReviewPolicy) Requires(pr *models.PullRequest) bool {
+	if p.TeamName == "" {
+		return false
+	}
+
+	for _, label := range pr.Labels {
+		for _, sensitive := range p.Labels {
+			if strings.EqualFold(strings.TrimSpace(sensitive), label) {
+				return true
+			}
+		}
+	}
+
+	for _, path := range pr.ChangedPaths {
+		for _, prefix := range p.PathPrefixes {
+			prefix = strings.TrimSpace(prefix)
+			if prefix != "" && strings.HasPrefix(path, prefix) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// securityMembers returns those of userIDs who belong to the security team.
+func (s *PullRequestService) securityMembers(userIDs []string) ([]string, error) {
+	members := make([]string, 0)
+	for _, userID := range userIDs {
+		teams, err := s.prRepo.GetUserTeams(userID)
+		if err != nil {
+			return nil, err
+		}
+		if slices.Contains(teams, s.security.TeamName) {
+			members = append(members, userID)
+		}
+	}
+	return members, nil
+}
+
+// keepsSecurityReviewer reports whether reviewers still include a security
+// team member, for PRs that require one.
+func (s *PullRequestService) keepsSecurityReviewer(pr *models.PullRequest, reviewers []string) (bool, error) {
+	if !s.security.Requires(pr) {
+		return true, nil
+	}
+
+	members, err := s.securityMembers(reviewers)
+	if err != nil {
+		return false, err
+	}
+
+	return len(members) > 0, nil
+}
@@ -0,0 +1,228 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"log/slog"
+	"pull-request-assigner/internal/domain/events"
+	"pull-request-assigner/internal/domain/models"
+	"pull-request-assigner/internal/lib/logger/sl"
+	"pull-request-assigner/internal/lib/siem"
+	"time"
+)
+
+const (
+	siemQueueSize    = 1024
+	siemDrainTimeout = 5 * time.Second
+)
+
+var (
+	siemForwarded = expvar.NewInt("siem_forwarded_total")
+	siemFailures  = expvar.NewInt("siem_failures_total")
+	siemDropped   = expvar.NewInt("siem_dropped_total")
+)
+
+// SIEMBatching sets when queued audit entries are shipped: as soon as Size
+// of them are waiting, and at least every Interval otherwise.
+type SIEMBatching struct {
+	Size     int
+	Interval time.Duration
+}
+
+// SIEMForwarder copies published audit entries to a SIEM collector next to
+// the local audit log. Entries are shipped in order, one batch at a time; a
+// failed batch is retried as WebhookRetry allows before it is dropped. While
+// a batch is being retried the queue is still read, and what arrives is held
+// for the following batches, up to siemQueueSize entries.
+type SIEMForwarder struct {
+	log      *slog.Logger
+	shipper  siem.Shipper
+	queue    chan models.AuditEvent
+	batching SIEMBatching
+	retry    WebhookRetry
+	health   *IntegrationTracker
+}
+
+func NewSIEMForwarder(
+	log *slog.Logger,
+	shipper siem.Shipper,
+	batching SIEMBatching,
+	retry WebhookRetry,
+	health *IntegrationTracker) *SIEMForwarder {
+	if batching.Size < 1 {
+		batching.Size = 1
+	}
+	if batching.Interval <= 0 {
+		batching.Interval = time.Second
+	}
+
+	return &SIEMForwarder{
+		log:      log,
+		shipper:  shipper,
+		queue:    make(chan models.AuditEvent, siemQueueSize),
+		batching: batching,
+		retry:    retry,
+		health:   health,
+	}
+}
+
+// Handle is the bus subscriber queueing audit entries for the SIEM. It never
+// blocks the publisher: with the queue full the entry is dropped.
+func (f *SIEMForwarder) Handle(_ context.Context, event events.Event) {
+	recorded, ok := event.(events.AuditRecorded)
+	if !ok {
+		return
+	}
+
+	entry := recorded.Entry
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now().UTC()
+	}
+
+	select {
+	case f.queue <- entry:
+	default:
+		f.dropped(1)
+		f.log.Warn("siem queue is full, audit entry dropped", slog.String("action", entry.Action))
+	}
+}
+
+// Run ships queued entries until ctx is done, then makes one last attempt
+// to ship what is still queued. Reading the queue and shipping run apart, so
+// a batch waiting for its retry does not stop Handle from queueing.
+func (f *SIEMForwarder) Run(ctx context.Context) {
+	batches := make(chan []models.AuditEvent)
+	var unsent []models.AuditEvent
+	shipped := make(chan struct{})
+	go func() {
+		defer close(shipped)
+		for batch := range batches {
+			if left := f.ship(ctx, batch); left != nil {
+				unsent = append(unsent, left...)
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(f.batching.Interval)
+	defer ticker.Stop()
+
+	var pending []models.AuditEvent
+	flush := false
+	for {
+		// The next batch is offered once Size entries are pending or the
+		// interval has passed, and taken when the last one is done.
+		var out chan<- []models.AuditEvent
+		var next []models.AuditEvent
+		if len(pending) > 0 && (flush || len(pending) >= f.batching.Size) {
+			n := min(len(pending), f.batching.Size)
+			out, next = batches, pending[:n:n]
+		}
+
+		select {
+		case <-ctx.Done():
+			close(batches)
+			<-shipped
+			f.drain(append(unsent, pending...))
+			return
+		case entry := <-f.queue:
+			if len(pending) >= siemQueueSize {
+				f.dropped(1)
+				f.log.Warn("siem backlog is full, audit entry dropped", slog.String("action", entry.Action))
+				continue
+			}
+			pending = append(pending, entry)
+		case <-ticker.C:
+			flush = len(pending) > 0
+		case out <- next:
+			pending = pending[len(next):]
+			flush = flush && len(pending) > 0
+		}
+	}
+}
+
+// ship sends the batch, waiting Backoff before the second attempt and
+// doubling the wait after each further failure. A batch the collector
+// rejected is not sent again. When ctx is done before the batch is shipped
+// it is returned, for drain to send.
+func (f *SIEMForwarder) ship(ctx context.Context, batch []models.AuditEvent) []models.AuditEvent {
+	const op = "service.siem.ship"
+
+	log := f.log.With(
+		slog.String("op", op),
+		slog.Int("entries", len(batch)),
+	)
+
+	backoff := f.retry.Backoff
+	for attempt := 1; ; attempt++ {
+		if ctx.Err() != nil {
+			return batch
+		}
+
+		err := f.shipper.Ship(ctx, batch)
+		if err == nil {
+			f.health.Observe(nil)
+			siemForwarded.Add(int64(len(batch)))
+			return nil
+		}
+		if ctx.Err() != nil {
+			return batch
+		}
+
+		f.health.Observe(err)
+		siemFailures.Add(1)
+		log.Warn("failed to ship audit entries", slog.Int("attempt", attempt), sl.Err(err))
+
+		if errors.Is(err, siem.ErrRejected) || attempt >= f.retry.MaxAttempts {
+			f.dropped(len(batch))
+			log.Error("audit entries dropped", slog.Int("attempts", attempt))
+			return nil
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return batch
+		case <-timer.C:
+		}
+		backoff *= 2
+	}
+}
+
+// drain ships the batch together with everything still queued, once and
+// within siemDrainTimeout, as the service shuts down.
+func (f *SIEMForwarder) drain(batch []models.AuditEvent) {
+	const op = "service.siem.drain"
+
+	for len(f.queue) > 0 {
+		batch = append(batch, <-f.queue)
+	}
+
+	if len(batch) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), siemDrainTimeout)
+	defer cancel()
+
+	err := f.shipper.Ship(ctx, batch)
+	f.health.Observe(err)
+	if err != nil {
+		f.dropped(len(batch))
+		f.log.Error("failed to ship audit entries on shutdown",
+			slog.String("op", op),
+			slog.Int("entries", len(batch)),
+			sl.Err(err))
+		return
+	}
+
+	siemForwarded.Add(int64(len(batch)))
+}
+
+// dropped counts n entries given up on, in siem_dropped_total and in the
+// SIEM integration health.
+func (f *SIEMForwarder) dropped(n int) {
+	siemDropped.Add(int64(n))
+	f.health.ObserveDropped(n)
+}
@@ -0,0 +1,230 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"pull-request-assigner/internal/domain/events"
+	"pull-request-assigner/internal/domain/models"
+	"pull-request-assigner/internal/lib/siem"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// shipperFake answers the calls with errs in turn, then nil, and reports
+// every batch it is given.
+type shipperFake struct {
+	mu    sync.Mutex
+	errs  []error
+	calls int
+
+	shipped chan []models.AuditEvent
+}
+
+func newShipperFake(errs ...error) *shipperFake {
+	return &shipperFake{errs: errs, shipped: make(chan []models.AuditEvent, 16)}
+}
+
+func (f *shipperFake) Ship(ctx context.Context, entries []models.AuditEvent) error {
+	f.mu.Lock()
+	f.calls++
+	var err error
+	if f.calls <= len(f.errs) {
+		err = f.errs[f.calls-1]
+	}
+	f.mu.Unlock()
+
+	f.shipped <- slices.Clone(entries)
+	return err
+}
+
+func (f *shipperFake) callCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.calls
+}
+
+// next waits for the next batch given to the shipper.
+func (f *shipperFake) next(t *testing.T) []models.AuditEvent {
+	t.Helper()
+
+	select {
+	case batch := <-f.shipped:
+		return batch
+	case <-time.After(time.Second):
+		t.Fatal("no batch was shipped")
+		return nil
+	}
+}
+
+func newTestSIEMForwarder(shipper siem.Shipper, batching SIEMBatching, retry WebhookRetry) (*SIEMForwarder, *IntegrationTracker) {
+	health := NewIntegrationTracker(IntegrationSIEM, true)
+	f := NewSIEMForwarder(slog.New(slog.NewTextHandler(io.Discard, nil)), shipper, batching, retry, health)
+	return f, health
+}
+
+// runForwarder runs f until the returned stop is called.
+func runForwarder(f *SIEMForwarder) (stop func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		f.Run(ctx)
+		close(done)
+	}()
+
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+func auditRecorded(action string) events.AuditRecorded {
+	return events.AuditRecorded{Entry: models.AuditEvent{Action: action}}
+}
+
+func actions(batch []models.AuditEvent) []string {
+	names := make([]string, 0, len(batch))
+	for _, entry := range batch {
+		names = append(names, entry.Action)
+	}
+	return names
+}
+
+func TestSIEMFlushesOnSize(t *testing.T) {
+	shipper := newShipperFake()
+	f, _ := newTestSIEMForwarder(shipper, SIEMBatching{Size: 2, Interval: time.Hour}, WebhookRetry{MaxAttempts: 1})
+	stop := runForwarder(f)
+	defer stop()
+
+	for _, action := range []string{"a", "b", "c"} {
+		f.Handle(context.Background(), auditRecorded(action))
+	}
+
+	if got := actions(shipper.next(t)); !slices.Equal(got, []string{"a", "b"}) {
+		t.Fatalf("expected a full batch of a, b, got %v", got)
+	}
+
+	select {
+	case batch := <-shipper.shipped:
+		t.Fatalf("a partial batch must wait for the interval, got %v", actions(batch))
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestSIEMFlushesOnTick(t *testing.T) {
+	shipper := newShipperFake()
+	f, _ := newTestSIEMForwarder(shipper, SIEMBatching{Size: 100, Interval: 10 * time.Millisecond}, WebhookRetry{MaxAttempts: 1})
+	stop := runForwarder(f)
+	defer stop()
+
+	f.Handle(context.Background(), auditRecorded("a"))
+	f.Handle(context.Background(), auditRecorded("b"))
+
+	if got := actions(shipper.next(t)); !slices.Equal(got, []string{"a", "b"}) {
+		t.Fatalf("expected the interval to ship a, b, got %v", got)
+	}
+}
+
+func TestSIEMShipRetries(t *testing.T) {
+	batch := []models.AuditEvent{{Action: "a"}, {Action: "b"}}
+	unavailable := errors.New("siem: unexpected status 503")
+
+	tests := []struct {
+		name      string
+		errs      []error
+		wantCalls int
+		dropped   int64
+	}{
+		{
+			name:      "succeeds after retry",
+			errs:      []error{unavailable},
+			wantCalls: 2,
+		},
+		{
+			name:      "exhausted",
+			errs:      []error{unavailable, unavailable, unavailable},
+			wantCalls: 3,
+			dropped:   2,
+		},
+		{
+			name:      "rejected",
+			errs:      []error{fmt.Errorf("%w: status 400", siem.ErrRejected)},
+			wantCalls: 1,
+			dropped:   2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shipper := newShipperFake(tt.errs...)
+			f, health := newTestSIEMForwarder(shipper, SIEMBatching{}, WebhookRetry{MaxAttempts: 3, Backoff: time.Millisecond})
+
+			if left := f.ship(context.Background(), batch); left != nil {
+				t.Fatalf("expected the batch to be settled, got %v back", actions(left))
+			}
+			if calls := shipper.callCount(); calls != tt.wantCalls {
+				t.Fatalf("expected %d calls, got %d", tt.wantCalls, calls)
+			}
+			if got := health.snapshot().Dropped; got != tt.dropped {
+				t.Fatalf("expected %d dropped entries, got %d", tt.dropped, got)
+			}
+		})
+	}
+}
+
+func TestSIEMKeepsReadingWhileRetrying(t *testing.T) {
+	shipper := newShipperFake(errors.New("siem: unexpected status 503"))
+	f, health := newTestSIEMForwarder(shipper, SIEMBatching{Size: 1, Interval: time.Hour}, WebhookRetry{MaxAttempts: 3, Backoff: time.Hour})
+	stop := runForwarder(f)
+
+	f.Handle(context.Background(), auditRecorded("a"))
+	if got := actions(shipper.next(t)); !slices.Equal(got, []string{"a"}) {
+		t.Fatalf("expected a to be shipped, got %v", got)
+	}
+
+	// a now waits an hour for its retry; the queue must still be read.
+	for i := 0; i < 100; i++ {
+		f.Handle(context.Background(), auditRecorded("b"))
+	}
+	deadline := time.Now().Add(time.Second)
+	for len(f.queue) > 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if len(f.queue) > 0 {
+		t.Fatalf("expected the queue to be read during the backoff, %d entries left", len(f.queue))
+	}
+
+	stop()
+
+	drained := shipper.next(t)
+	if len(drained) != 101 || drained[0].Action != "a" {
+		t.Fatalf("expected the drain to ship a first and every queued entry, got %d entries starting with %v",
+			len(drained), actions(drained[:1]))
+	}
+	if got := health.snapshot().Dropped; got != 0 {
+		t.Fatalf("expected no dropped entries, got %d", got)
+	}
+}
+
+func TestSIEMDrainsOnCancel(t *testing.T) {
+	shipper := newShipperFake()
+	f, _ := newTestSIEMForwarder(shipper, SIEMBatching{Size: 100, Interval: time.Hour}, WebhookRetry{MaxAttempts: 1})
+	stop := runForwarder(f)
+
+	for _, action := range []string{"a", "b", "c"} {
+		f.Handle(context.Background(), auditRecorded(action))
+	}
+	stop()
+
+	if got := actions(shipper.next(t)); !slices.Equal(got, []string{"a", "b", "c"}) {
+		t.Fatalf("expected the drain to ship a, b, c, got %v", got)
+	}
+	if calls := shipper.callCount(); calls != 1 {
+		t.Fatalf("expected one call, got %d", calls)
+	}
+}
@@ -0,0 +1,246 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"math/rand"
+	"pull-request-assigner/internal/apperrors"
+	"pull-request-assigner/internal/domain/models"
+	"pull-request-assigner/internal/lib/logger/sl"
+	"sort"
+	"time"
+)
+
+type SimulationProvider interface {
+	GetPRHistory(teamName string, since time.Time) ([]models.SimulationPR, error)
+	GetActiveMembersByTeam(teamName string) (map[string][]string, error)
+	GetActualAssignmentCounts(teamName string, since time.Time) (map[string]int, error)
+}
+
+const DefaultSimulationWindowDays = 90
+
+// Simulate replays the PRs created in the last windowDays days in
+// chronological order against the current team membership and reports the
+// load distribution the chosen strategy would have produced, next to the
+// actual assignments on the same PRs. Nothing is written to the database.
+func (s *AdminService) Simulate(ctx context.Context, strategy string, teamName string, reviewersPerPR int, windowDays int, seed int64) (*models.SimulationReport, error) {
+	const op = "service.admin.Simulate"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("strategy", strategy),
+		slog.String("team_name", teamName),
+		slog.Int("window_days", windowDays),
+	)
+
+	log.Info("starting assignment simulation")
+
+	if strategy != models.StrategyRandom && strategy != models.StrategyLeastLoaded {
+		log.Error("unknown strategy")
+		return nil, apperrors.ErrUnknownStrategy
+	}
+
+	if windowDays == 0 {
+		windowDays = DefaultSimulationWindowDays
+	}
+
+	if windowDays < 1 || windowDays > MaxStatsWindowDays {
+		log.Error("invalid simulation window")
+		return nil, apperrors.ErrInvalidStatsWindow
+	}
+
+	if reviewersPerPR <= 0 {
+		reviewersPerPR = 2
+	}
+
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	since := time.Now().AddDate(0, 0, -windowDays)
+
+	history, err := s.simulationRepo.GetPRHistory(teamName, since)
+	if err != nil {
+		log.Error("failed to get PR history", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	members, err := s.simulationRepo.GetActiveMembersByTeam(teamName)
+	if err != nil {
+		log.Error("failed to get team members", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	actual, err := s.simulationRepo.GetActualAssignmentCounts(teamName, since)
+	if err != nil {
+		log.Error("failed to get actual assignment counts", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	sim := newSimulator(strategy, reviewersPerPR, rand.New(rand.NewSource(seed)))
+	for _, pr := range history {
+		sim.replay(pr, members[pr.TeamName])
+	}
+
+	report := sim.report(members, actual)
+	report.WindowDays = windowDays
+
+	log.Info("assignment simulation finished",
+		slog.Int("total_prs", report.TotalPRs),
+		slog.Int("unassigned_prs", report.UnassignedPRs))
+
+	return report, nil
+}
+
+type simulatedAssignment struct {
+	reviewers []string
+	mergedAt  time.Time
+}
+
+type simulator struct {
+	strategy       string
+	reviewersPerPR int
+	rnd            *rand.Rand
+	openLoad       map[string]int
+	total          map[string]int
+	open           []simulatedAssignment
+	totalPRs       int
+	unassigned     int
+}
+
+func newSimulator(strategy string, reviewersPerPR int, rnd *rand.Rand) *simulator {
+	return &simulator{
+		strategy:       strategy,
+		reviewersPerPR: reviewersPerPR,
+		rnd:            rnd,
+		openLoad:       make(map[string]int),
+		total:          make(map[string]int),
+	}
+}
+
+func (s *simulator) replay(pr models.SimulationPR, teamMembers []string) {
+	s.release(pr.CreatedAt)
+	s.totalPRs++
+
+	candidates := make([]string, 0, len(teamMembers))
+	for _, member := range teamMembers {
+		if member != pr.AuthorID {
+			candidates = append(candidates, member)
+		}
+	}
+
+	if len(candidates) == 0 {
+		s.unassigned++
+		return
+	}
+
+	picked := s.pick(candidates)
+	for _, reviewer := range picked {
+		s.openLoad[reviewer]++
+		s.total[reviewer]++
+	}
+
+	if pr.MergedAt.Valid {
+		s.open = append(s.open, simulatedAssignment{reviewers: picked, mergedAt: pr.MergedAt.Time})
+	}
+}
+
+// release frees the simulated load of PRs merged before the given moment.
+func (s *simulator) release(now time.Time) {
+	remaining := s.open[:0]
+	for _, a := range s.open {
+		if !a.mergedAt.After(now) {
+			for _, reviewer := range a.reviewers {
+				s.openLoad[reviewer]--
+			}
+			continue
+		}
+		remaining = append(remaining, a)
+	}
+	s.open = remaining
+}
+
+func (s *simulator) pick(candidates []string) []string {
+	shuffled := make([]string, len(candidates))
+	copy(shuffled, candidates)
+	s.rnd.Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+
+	if s.strategy == models.StrategyLeastLoaded {
+		sort.SliceStable(shuffled, func(i, j int) bool {
+			return s.openLoad[shuffled[i]] < s.openLoad[shuffled[j]]
+		})
+	}
+
+	if len(shuffled) > s.reviewersPerPR {
+		shuffled = shuffled[:s.reviewersPerPR]
+	}
+	return shuffled
+}
+
+func (s *simulator) report(members map[string][]string, actual map[string]int) *models.SimulationReport {
+	report := &models.SimulationReport{
+		Strategy:       s.strategy,
+		ReviewersPerPR: s.reviewersPerPR,
+		TotalPRs:       s.totalPRs,
+		UnassignedPRs:  s.unassigned,
+		Reviewers:      make([]models.ReviewerLoad, 0),
+	}
+
+	var simulated, real []float64
+	for teamName, teamMembers := range members {
+		for _, userID := range teamMembers {
+			report.Reviewers = append(report.Reviewers, models.ReviewerLoad{
+				UserID:    userID,
+				TeamName:  teamName,
+				Simulated: s.total[userID],
+				Actual:    actual[userID],
+			})
+			simulated = append(simulated, float64(s.total[userID]))
+			real = append(real, float64(actual[userID]))
+		}
+	}
+
+	sort.Slice(report.Reviewers, func(i, j int) bool {
+		if report.Reviewers[i].TeamName != report.Reviewers[j].TeamName {
+			return report.Reviewers[i].TeamName < report.Reviewers[j].TeamName
+		}
+		return report.Reviewers[i].UserID < report.Reviewers[j].UserID
+	})
+
+	for i, load := range report.Reviewers {
+		if i == 0 || load.Simulated > report.MaxLoad {
+			report.MaxLoad = load.Simulated
+		}
+		if i == 0 || load.Simulated < report.MinLoad {
+			report.MinLoad = load.Simulated
+		}
+	}
+
+	report.StdDev = stdDev(simulated)
+	report.ActualStdDev = stdDev(real)
+
+	return report
+}
+
+func stdDev(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	mean := sum / float64(len(values))
+
+	var variance float64
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+
+	return math.Sqrt(variance / float64(len(values)))
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"log/slog"
+	"maps"
+	"pull-request-assigner/internal/apperrors"
+	"pull-request-assigner/internal/domain/models"
+	"testing"
+	"time"
+)
+
+// simulationRepoFake serves a fixed history and remembers the window asked
+// for.
+type simulationRepoFake struct {
+	history []models.SimulationPR
+	members map[string][]string
+	actual  map[string]int
+
+	since time.Time
+}
+
+func (f *simulationRepoFake) GetPRHistory(teamName string, since time.Time) ([]models.SimulationPR, error) {
+	f.since = since
+	return f.history, nil
+}
+
+func (f *simulationRepoFake) GetActiveMembersByTeam(teamName string) (map[string][]string, error) {
+	return f.members, nil
+}
+
+func (f *simulationRepoFake) GetActualAssignmentCounts(teamName string, since time.Time) (map[string]int, error) {
+	return f.actual, nil
+}
+
+var simulationStart = time.Date(2026, 1, 5, 10, 0, 0, 0, time.UTC)
+
+// simulationHistory returns n PRs by author, an hour apart; each is merged
+// after mergeAfter, or stays open when mergeAfter is zero.
+func simulationHistory(n int, author string, mergeAfter time.Duration) []models.SimulationPR {
+	history := make([]models.SimulationPR, 0, n)
+	for i := 0; i < n; i++ {
+		pr := models.SimulationPR{
+			AuthorID:  author,
+			TeamName:  "Backend",
+			CreatedAt: simulationStart.Add(time.Duration(i) * time.Hour),
+		}
+		if mergeAfter > 0 {
+			pr.MergedAt = sql.NullTime{Time: pr.CreatedAt.Add(mergeAfter), Valid: true}
+		}
+		history = append(history, pr)
+	}
+	return history
+}
+
+func simulatedLoads(report *models.SimulationReport) map[string]int {
+	loads := make(map[string]int, len(report.Reviewers))
+	for _, reviewer := range report.Reviewers {
+		loads[reviewer.UserID] = reviewer.Simulated
+	}
+	return loads
+}
+
+func TestSimulateDistribution(t *testing.T) {
+	backend := map[string][]string{"Backend": {"u1", "u2", "u3", "u4"}}
+
+	tests := []struct {
+		name           string
+		strategy       string
+		reviewersPerPR int
+		history        []models.SimulationPR
+		members        map[string][]string
+		want           map[string]int
+		unassigned     int
+	}{
+		{
+			name:           "least loaded spreads open reviews evenly",
+			strategy:       models.StrategyLeastLoaded,
+			reviewersPerPR: 2,
+			history:        simulationHistory(6, "u1", 0),
+			members:        backend,
+			want:           map[string]int{"u1": 0, "u2": 4, "u3": 4, "u4": 4},
+		},
+		{
+			name:           "random with a fixed seed",
+			strategy:       models.StrategyRandom,
+			reviewersPerPR: 1,
+			history:        simulationHistory(12, "u1", 0),
+			members:        backend,
+			want:           map[string]int{"u1": 0, "u2": 3, "u3": 5, "u4": 4},
+		},
+		{
+			name:           "author alone in the team",
+			strategy:       models.StrategyRandom,
+			reviewersPerPR: 2,
+			history:        simulationHistory(3, "u1", 0),
+			members:        map[string][]string{"Backend": {"u1"}},
+			want:           map[string]int{"u1": 0},
+			unassigned:     3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &AdminService{
+				log:            slog.New(slog.NewTextHandler(io.Discard, nil)),
+				simulationRepo: &simulationRepoFake{history: tt.history, members: tt.members},
+			}
+
+			report, err := s.Simulate(context.Background(), tt.strategy, "Backend", tt.reviewersPerPR, 0, 42)
+			if err != nil {
+				t.Fatalf("failed to simulate: %v", err)
+			}
+
+			if got := simulatedLoads(report); !maps.Equal(got, tt.want) {
+				t.Fatalf("expected loads %v, got %v", tt.want, got)
+			}
+			if report.TotalPRs != len(tt.history) || report.UnassignedPRs != tt.unassigned {
+				t.Fatalf("expected %d PRs with %d unassigned, got %d with %d",
+					len(tt.history), tt.unassigned, report.TotalPRs, report.UnassignedPRs)
+			}
+		})
+	}
+}
+
+func TestSimulateLeastLoadedFreesMergedReviews(t *testing.T) {
+	// Every PR is merged before the next one is created, so least_loaded
+	// always sees equal loads and picks as random does with the same seed.
+	history := simulationHistory(12, "u1", 30*time.Minute)
+	members := map[string][]string{"Backend": {"u1", "u2", "u3", "u4"}}
+
+	loads := make(map[string]map[string]int)
+	for _, strategy := range []string{models.StrategyRandom, models.StrategyLeastLoaded} {
+		s := &AdminService{
+			log:            slog.New(slog.NewTextHandler(io.Discard, nil)),
+			simulationRepo: &simulationRepoFake{history: history, members: members},
+		}
+
+		report, err := s.Simulate(context.Background(), strategy, "Backend", 1, 0, 42)
+		if err != nil {
+			t.Fatalf("failed to simulate %s: %v", strategy, err)
+		}
+		loads[strategy] = simulatedLoads(report)
+	}
+
+	if !maps.Equal(loads[models.StrategyRandom], loads[models.StrategyLeastLoaded]) {
+		t.Fatalf("expected merged reviews to be freed, got %v for least_loaded and %v for random",
+			loads[models.StrategyLeastLoaded], loads[models.StrategyRandom])
+	}
+}
+
+func TestSimulateWindow(t *testing.T) {
+	repo := &simulationRepoFake{}
+	s := &AdminService{log: slog.New(slog.NewTextHandler(io.Discard, nil)), simulationRepo: repo}
+
+	report, err := s.Simulate(context.Background(), models.StrategyRandom, "", 2, 0, 1)
+	if err != nil {
+		t.Fatalf("failed to simulate: %v", err)
+	}
+	if report.WindowDays != DefaultSimulationWindowDays {
+		t.Fatalf("expected the default window of %d days, got %d", DefaultSimulationWindowDays, report.WindowDays)
+	}
+	want := time.Now().AddDate(0, 0, -DefaultSimulationWindowDays)
+	if d := repo.since.Sub(want); d < -time.Minute || d > time.Minute {
+		t.Fatalf("expected the history since %v, got %v", want, repo.since)
+	}
+
+	for _, windowDays := range []int{-1, MaxStatsWindowDays + 1} {
+		if _, err := s.Simulate(context.Background(), models.StrategyRandom, "", 2, windowDays, 1); !errors.Is(err, apperrors.ErrInvalidStatsWindow) {
+			t.Fatalf("window of %d days: expected ErrInvalidStatsWindow, got %v", windowDays, err)
+		}
+	}
+}
@@ -4,26 +4,101 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"maps"
+	"pull-request-assigner/internal/apperrors"
 	"pull-request-assigner/internal/domain/models"
+	"pull-request-assigner/internal/lib/actor"
 	"pull-request-assigner/internal/lib/logger/sl"
+	"slices"
+	"time"
 )
 
 type StatsService struct {
-	log       *slog.Logger
-	statsRepo StatsProvider
+	log        *slog.Logger
+	statsRepo  StatsProvider
+	visibility StatsVisibilityProvider
 }
 
 type StatsProvider interface {
 	GetPRStats() (*models.PRStats, error)
+	GetTeamsPRStats(teamNames []string) ([]models.TeamPRStats, error)
+	GetCycleTimes(since time.Time) ([]models.CycleTime, error)
+	SnapshotStats() (int, error)
+	GetStatsHistory(teamName string, since time.Time) ([]models.StatsSnapshot, error)
+	GetMemberCapacity(teamName string, since time.Time) ([]models.MemberCapacity, error)
+	GetTagStats(since time.Time) ([]models.TagStats, error)
+	GetTagReviewLoads(since time.Time) ([]models.TagReviewLoad, error)
+	GetReviewerPairs(teamName string, since time.Time) ([]models.ReviewerPair, error)
 }
 
+type StatsVisibilityProvider interface {
+	GetLedTeams(userID int) ([]string, error)
+	GetTeamMemberIDs(teamNames []string) ([]string, error)
+}
+
+const MaxTeamsPerStatsRequest = 100
+
+const (
+	DefaultCycleTimeWindowDays = 30
+	DefaultHistoryWindowDays   = 30
+	DefaultTagStatsWindowDays  = 30
+	DefaultPairingWindowDays   = 30
+	MaxStatsWindowDays         = 365
+)
+
+// CapacityRecentDays is the window of the recent completed reviews column in
+// capacity plans.
+const CapacityRecentDays = 30
+
 func NewStatsService(
 	log *slog.Logger,
-	statsRepo StatsProvider) *StatsService {
+	statsRepo StatsProvider,
+	visibility StatsVisibilityProvider) *StatsService {
 	return &StatsService{
-		log:       log,
-		statsRepo: statsRepo,
+		log:        log,
+		statsRepo:  statsRepo,
+		visibility: visibility,
+	}
+}
+
+// callerVisibility resolves which team and user level stats the caller may
+// see. Admin keys see everything. Other keys, and admins impersonating a
+// user, see the teams led by that user. X-User-ID is not trusted here, so a
+// request without a key sees service-wide totals only.
+func (s *StatsService) callerVisibility(ctx context.Context) (models.StatsVisibility, error) {
+	_, authenticated := actor.Scopes(ctx)
+	_, impersonated := actor.Impersonator(ctx)
+	if !authenticated && !impersonated {
+		return models.StatsVisibility{}, nil
+	}
+
+	if callerIsAdmin(ctx) {
+		return models.StatsVisibility{All: true}, nil
+	}
+
+	userID, ok := actor.UserID(ctx)
+	if !ok {
+		return models.StatsVisibility{}, nil
+	}
+	uid, err := models.ParseUserID(userID)
+	if err != nil {
+		return models.StatsVisibility{}, nil
+	}
+
+	teams, err := s.visibility.GetLedTeams(uid.Int())
+	if err != nil {
+		return models.StatsVisibility{}, err
+	}
+	if len(teams) == 0 {
+		return models.StatsVisibility{}, nil
+	}
+
+	members, err := s.visibility.GetTeamMemberIDs(teams)
+	if err != nil {
+		return models.StatsVisibility{}, err
 	}
+
+	return models.StatsVisibility{Teams: teams, Members: members}, nil
 }
 
 func (s *StatsService) GetPRStats(ctx context.Context) (*models.PRStats, error) {
@@ -33,12 +108,22 @@ func (s *StatsService) GetPRStats(ctx context.Context) (*models.PRStats, error)
 
 	log.Info("getting PR statistics")
 
+	visibility, err := s.callerVisibility(ctx)
+	if err != nil {
+		log.Error("failed to resolve stats visibility", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
 	stats, err := s.statsRepo.GetPRStats()
 	if err != nil {
 		log.Error("failed to get PR stats", sl.Err(err))
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
+	maps.DeleteFunc(stats.MergesByUser, func(userID string, _ int) bool {
+		return !visibility.AllowsUser(userID)
+	})
+
 	log.Info("PR statistics retrieved successfully",
 		slog.Int("total_prs", stats.TotalPRs),
 		slog.Int("open_prs", stats.OpenPRs),
@@ -46,3 +131,395 @@ func (s *StatsService) GetPRStats(ctx context.Context) (*models.PRStats, error)
 
 	return stats, nil
 }
+
+func (s *StatsService) GetTeamsPRStats(ctx context.Context, teamNames []string) ([]models.TeamPRStats, error) {
+	const op = "service.stats.GetTeamsPRStats"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int("team_count", len(teamNames)),
+	)
+
+	log.Info("getting PR statistics for teams")
+
+	if len(teamNames) == 0 {
+		log.Error("at least one team name is required")
+		return nil, apperrors.ErrTeamNameRequired
+	}
+
+	if len(teamNames) > MaxTeamsPerStatsRequest {
+		log.Error("too many teams requested")
+		return nil, apperrors.ErrTooManyTeams
+	}
+
+	unique := make([]string, 0, len(teamNames))
+	seen := make(map[string]bool, len(teamNames))
+	for _, name := range teamNames {
+		if name == "" {
+			log.Error("empty team name in request")
+			return nil, apperrors.ErrTeamNameRequired
+		}
+		if !seen[name] {
+			seen[name] = true
+			unique = append(unique, name)
+		}
+	}
+
+	visibility, err := s.callerVisibility(ctx)
+	if err != nil {
+		log.Error("failed to resolve stats visibility", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	for _, name := range unique {
+		if !visibility.AllowsTeam(name) {
+			log.Warn("team stats are not visible to the caller", slog.String("team_name", name))
+			return nil, apperrors.ErrStatsForbidden
+		}
+	}
+
+	stats, err := s.statsRepo.GetTeamsPRStats(unique)
+	if err != nil {
+		log.Error("failed to get teams PR stats", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("teams PR statistics retrieved successfully")
+
+	return stats, nil
+}
+
+// GetCycleTime returns created-to-merged percentiles for PRs merged within
+// the last windowDays days; zero selects DefaultCycleTimeWindowDays.
+func (s *StatsService) GetCycleTime(ctx context.Context, windowDays int) (*models.CycleTimeStats, error) {
+	const op = "service.stats.GetCycleTime"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int("window_days", windowDays),
+	)
+
+	if windowDays == 0 {
+		windowDays = DefaultCycleTimeWindowDays
+	}
+
+	if windowDays < 1 || windowDays > MaxStatsWindowDays {
+		log.Error("invalid cycle time window")
+		return nil, apperrors.ErrInvalidStatsWindow
+	}
+
+	visibility, err := s.callerVisibility(ctx)
+	if err != nil {
+		log.Error("failed to resolve stats visibility", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	since := time.Now().UTC().Add(-time.Duration(windowDays) * 24 * time.Hour)
+
+	cycleTimes, err := s.statsRepo.GetCycleTimes(since)
+	if err != nil {
+		log.Error("failed to get cycle times", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	stats := &models.CycleTimeStats{
+		WindowDays: windowDays,
+		Since:      since,
+		Teams:      make([]models.CycleTime, 0, len(cycleTimes)),
+	}
+	for _, cycleTime := range cycleTimes {
+		if cycleTime.TeamName == "" {
+			stats.Overall = cycleTime
+			continue
+		}
+		if visibility.AllowsTeam(cycleTime.TeamName) {
+			stats.Teams = append(stats.Teams, cycleTime)
+		}
+	}
+
+	log.Info("cycle time retrieved successfully", slog.Int("merged_prs", stats.Overall.MergedPRs))
+
+	return stats, nil
+}
+
+// SnapshotStats records today's stats into the history. It runs as a
+// scheduled job.
+func (s *StatsService) SnapshotStats(ctx context.Context) error {
+	const op = "service.stats.SnapshotStats"
+
+	log := s.log.With(slog.String("op", op))
+
+	rows, err := s.statsRepo.SnapshotStats()
+	if err != nil {
+		log.Error("failed to snapshot stats", sl.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("stats snapshot recorded", slog.Int("rows", rows))
+
+	return nil
+}
+
+// GetStatsHistory returns daily snapshots of the team, or service-wide ones
+// when teamName is empty, for the last windowDays days; zero selects
+// DefaultHistoryWindowDays.
+func (s *StatsService) GetStatsHistory(ctx context.Context, teamName string, windowDays int) ([]models.StatsSnapshot, error) {
+	const op = "service.stats.GetStatsHistory"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("team_name", teamName),
+		slog.Int("window_days", windowDays),
+	)
+
+	if windowDays == 0 {
+		windowDays = DefaultHistoryWindowDays
+	}
+
+	if windowDays < 1 || windowDays > MaxStatsWindowDays {
+		log.Error("invalid history window")
+		return nil, apperrors.ErrInvalidStatsWindow
+	}
+
+	if teamName != "" {
+		visibility, err := s.callerVisibility(ctx)
+		if err != nil {
+			log.Error("failed to resolve stats visibility", sl.Err(err))
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		if !visibility.AllowsTeam(teamName) {
+			log.Warn("team stats are not visible to the caller")
+			return nil, apperrors.ErrStatsForbidden
+		}
+	}
+
+	since := time.Now().UTC().AddDate(0, 0, -(windowDays - 1))
+
+	snapshots, err := s.statsRepo.GetStatsHistory(teamName, since)
+	if err != nil {
+		log.Error("failed to get stats history", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return snapshots, nil
+}
+
+// GetCapacityPlan returns the team's members, or members of all teams when
+// teamName is empty, with their review load and the days of next month.
+func (s *StatsService) GetCapacityPlan(ctx context.Context, teamName string) (*models.CapacityPlan, error) {
+	const op = "service.stats.GetCapacityPlan"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("team_name", teamName),
+	)
+
+	visibility, err := s.callerVisibility(ctx)
+	if err != nil {
+		log.Error("failed to resolve stats visibility", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if teamName != "" && !visibility.AllowsTeam(teamName) {
+		log.Warn("team stats are not visible to the caller")
+		return nil, apperrors.ErrStatsForbidden
+	}
+
+	now := time.Now().UTC()
+
+	members, err := s.statsRepo.GetMemberCapacity(teamName, now.AddDate(0, 0, -CapacityRecentDays))
+	if err != nil {
+		log.Error("failed to get member capacity", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	members = slices.DeleteFunc(members, func(member models.MemberCapacity) bool {
+		return !visibility.AllowsTeam(member.TeamName)
+	})
+
+	if teamName != "" && len(members) == 0 {
+		log.Warn("team not found")
+		return nil, apperrors.ErrTeamNotFound
+	}
+
+	monthStart := time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+	plan := &models.CapacityPlan{
+		MonthStart: monthStart,
+		Members:    members,
+	}
+	for day := monthStart; day.Month() == monthStart.Month(); day = day.AddDate(0, 0, 1) {
+		plan.Days = append(plan.Days, day)
+	}
+
+	log.Info("capacity plan built", slog.Int("members", len(members)))
+
+	return plan, nil
+}
+
+// GetTagStats breaks down the PRs created within the last windowDays days
+// per label and required skill; zero selects DefaultTagStatsWindowDays.
+func (s *StatsService) GetTagStats(ctx context.Context, windowDays int) (*models.TagStatsReport, error) {
+	const op = "service.stats.GetTagStats"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int("window_days", windowDays),
+	)
+
+	if windowDays == 0 {
+		windowDays = DefaultTagStatsWindowDays
+	}
+
+	if windowDays < 1 || windowDays > MaxStatsWindowDays {
+		log.Error("invalid tag stats window")
+		return nil, apperrors.ErrInvalidStatsWindow
+	}
+
+	visibility, err := s.callerVisibility(ctx)
+	if err != nil {
+		log.Error("failed to resolve stats visibility", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	since := time.Now().UTC().Add(-time.Duration(windowDays) * 24 * time.Hour)
+
+	stats, err := s.statsRepo.GetTagStats(since)
+	if err != nil {
+		log.Error("failed to get tag stats", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	loads, err := s.statsRepo.GetTagReviewLoads(since)
+	if err != nil {
+		log.Error("failed to get tag review loads", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	type tagKey struct{ kind, tag string }
+	reviewers := make(map[tagKey][]models.TagReviewLoad)
+	for _, load := range loads {
+		key := tagKey{load.Kind, load.Tag}
+		reviewers[key] = append(reviewers[key], load)
+	}
+
+	report := &models.TagStatsReport{
+		WindowDays: windowDays,
+		Since:      since,
+		Labels:     make([]models.TagStats, 0),
+		Skills:     make([]models.TagStats, 0),
+	}
+	for _, tag := range stats {
+		tag.Reviewers = reviewers[tagKey{tag.Kind, tag.Tag}]
+		if tag.Reviewers == nil {
+			tag.Reviewers = []models.TagReviewLoad{}
+		}
+
+		total := 0
+		for _, load := range tag.Reviewers {
+			total += load.Reviews
+		}
+		// Loads come busiest first.
+		if total > 0 {
+			tag.TopReviewerShare = float64(tag.Reviewers[0].Reviews) / float64(total)
+		}
+		tag.Reviewers = slices.DeleteFunc(tag.Reviewers, func(load models.TagReviewLoad) bool {
+			return !visibility.AllowsUser(load.UserID)
+		})
+
+		if tag.Kind == models.TagKindSkill {
+			report.Skills = append(report.Skills, tag)
+		} else {
+			report.Labels = append(report.Labels, tag)
+		}
+	}
+
+	log.Info("tag stats retrieved successfully",
+		slog.Int("labels", len(report.Labels)),
+		slog.Int("skills", len(report.Skills)))
+
+	return report, nil
+}
+
+// GetPairingMatrix shows how often members of the team reviewed PRs created
+// within the last windowDays days together; zero selects
+// DefaultPairingWindowDays.
+func (s *StatsService) GetPairingMatrix(ctx context.Context, teamName string, windowDays int) (*models.PairingMatrix, error) {
+	const op = "service.stats.GetPairingMatrix"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("team_name", teamName),
+		slog.Int("window_days", windowDays),
+	)
+
+	if teamName == "" {
+		log.Error("team name is required")
+		return nil, apperrors.ErrTeamNameRequired
+	}
+
+	if windowDays == 0 {
+		windowDays = DefaultPairingWindowDays
+	}
+
+	if windowDays < 1 || windowDays > MaxStatsWindowDays {
+		log.Error("invalid pairing window")
+		return nil, apperrors.ErrInvalidStatsWindow
+	}
+
+	visibility, err := s.callerVisibility(ctx)
+	if err != nil {
+		log.Error("failed to resolve stats visibility", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if !visibility.AllowsTeam(teamName) {
+		log.Warn("team stats are not visible to the caller")
+		return nil, apperrors.ErrStatsForbidden
+	}
+
+	members, err := s.visibility.GetTeamMemberIDs([]string{teamName})
+	if err != nil {
+		log.Error("failed to get team members", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if len(members) == 0 {
+		log.Warn("team not found")
+		return nil, apperrors.ErrTeamNotFound
+	}
+	slices.SortFunc(members, func(a, b string) int {
+		idA, _ := models.ParseUserID(a)
+		idB, _ := models.ParseUserID(b)
+		return idA.Int() - idB.Int()
+	})
+
+	since := time.Now().UTC().Add(-time.Duration(windowDays) * 24 * time.Hour)
+
+	pairs, err := s.statsRepo.GetReviewerPairs(teamName, since)
+	if err != nil {
+		log.Error("failed to get reviewer pairs", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	index := make(map[string]int, len(members))
+	matrix := make([][]int, len(members))
+	for i, member := range members {
+		index[member] = i
+		matrix[i] = make([]int, len(members))
+	}
+	for _, pair := range pairs {
+		first, okFirst := index[pair.FirstID]
+		second, okSecond := index[pair.SecondID]
+		if !okFirst || !okSecond {
+			continue
+		}
+		matrix[first][second] = pair.CoReviews
+		matrix[second][first] = pair.CoReviews
+	}
+
+	return &models.PairingMatrix{
+		TeamName:   teamName,
+		WindowDays: windowDays,
+		Members:    members,
+		Matrix:     matrix,
+		Pairs:      pairs,
+	}, nil
+}
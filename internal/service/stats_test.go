@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"pull-request-assigner/internal/domain/models"
+	"pull-request-assigner/internal/lib/actor"
+	"slices"
+	"testing"
+)
+
+// statsVisibilityFake makes u1 the lead of Backend.
+type statsVisibilityFake struct{}
+
+func (statsVisibilityFake) GetLedTeams(userID int) ([]string, error) {
+	if userID == 1 {
+		return []string{"Backend"}, nil
+	}
+	return nil, nil
+}
+
+func (statsVisibilityFake) GetTeamMemberIDs(teamNames []string) ([]string, error) {
+	return []string{"u1", "u2"}, nil
+}
+
+func TestCallerVisibility(t *testing.T) {
+	s := &StatsService{
+		log:        slog.New(slog.NewTextHandler(io.Discard, nil)),
+		visibility: statsVisibilityFake{},
+	}
+
+	withKey := func(userID string, scope string) context.Context {
+		ctx := actor.WithUserID(context.Background(), userID)
+		return actor.WithScopes(ctx, []string{scope})
+	}
+
+	tests := []struct {
+		name  string
+		ctx   context.Context
+		all   bool
+		teams []string
+	}{
+		{name: "keyless", ctx: context.Background()},
+		{name: "keyless claiming a lead", ctx: actor.WithUserID(context.Background(), "u1")},
+		{name: "admin key", ctx: withKey("u2", models.TokenScopeAdmin), all: true},
+		{name: "lead key", ctx: withKey("u1", models.TokenScopeRead), teams: []string{"Backend"}},
+		{name: "member key", ctx: withKey("u2", models.TokenScopeRead)},
+		{
+			name:  "admin impersonating a lead",
+			ctx:   actor.WithImpersonator(withKey("u1", models.TokenScopeAdmin), "u10"),
+			teams: []string{"Backend"},
+		},
+		{
+			name:  "impersonation without a key",
+			ctx:   actor.WithImpersonator(actor.WithUserID(context.Background(), "u1"), "u10"),
+			teams: []string{"Backend"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			visibility, err := s.callerVisibility(tt.ctx)
+			if err != nil {
+				t.Fatalf("failed to resolve visibility: %v", err)
+			}
+			if visibility.All != tt.all || !slices.Equal(visibility.Teams, tt.teams) {
+				t.Fatalf("expected all=%v teams=%v, got all=%v teams=%v", tt.all, tt.teams, visibility.All, visibility.Teams)
+			}
+		})
+	}
+}
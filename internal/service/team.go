@@ -6,29 +6,53 @@ import (
 	"fmt"
 	"log/slog"
 	"pull-request-assigner/internal/apperrors"
+	"pull-request-assigner/internal/domain/events"
 	"pull-request-assigner/internal/domain/models"
 	"pull-request-assigner/internal/lib/logger/sl"
+	"pull-request-assigner/internal/lib/mergepatch"
+	"slices"
+	"strings"
 )
 
 type TeamService struct {
-	log      *slog.Logger
-	teamRepo TeamProvider
+	log       *slog.Logger
+	teamRepo  TeamProvider
+	auditRepo AuditProvider
+	publisher events.Publisher
 }
 
 type TeamProvider interface {
-	CreateTeam(teamName string) error
+	CreateTeamWithMembers(team models.Team) error
 	TeamExists(teamName string) (bool, error)
-	AddTeamMembers(teamName string, members []models.User) error
 	GetTeamWithMembers(teamName string) (*models.Team, error)
 	DeactivateTeamUsers(teamName string) (int, error)
+	GetTeamPolicy(teamName string) (*models.TeamPolicy, error)
+	GetPolicyLayers(teamName string) (*models.OrgPolicy, *models.TeamPolicyOverrides, error)
+	UpdateTeamPolicy(expected *models.TeamPolicyOverrides, overrides models.TeamPolicyOverrides, policy models.TeamPolicy, changes []string, actorID string) error
+	GetPolicyVersions(teamName string) ([]models.PolicyVersion, error)
+	GetLabelReviewTeams(labels []string) ([]string, error)
+	ArchiveTeam(teamName string) (int, error)
+	AddTeamLead(teamName string, userID int) (bool, error)
+	RemoveTeamLead(teamName string, userID int) (bool, error)
+	GetTeamLeads() ([]models.TeamLead, error)
+	AddSecondaryMember(teamName string, userID int) (bool, error)
+	RemoveSecondaryMember(teamName string, userID int) (bool, error)
+}
+
+type AuditProvider interface {
+	GetTeamEvents(teamName string) ([]models.AuditEvent, error)
 }
 
 func NewTeamService(
 	log *slog.Logger,
-	teamRepo TeamProvider) *TeamService {
+	teamRepo TeamProvider,
+	auditRepo AuditProvider,
+	publisher events.Publisher) *TeamService {
 	return &TeamService{
-		log:      log,
-		teamRepo: teamRepo,
+		log:       log,
+		teamRepo:  teamRepo,
+		auditRepo: auditRepo,
+		publisher: publisher,
 	}
 }
 
@@ -59,29 +83,42 @@ func (s *TeamService) CreateTeamWithMembers(ctx context.Context, team models.Tea
 		if member.Username == "" {
 			return nil, fmt.Errorf("%s: username is required for member at index %d", op, i)
 		}
-	}
 
-	exists, err := s.teamRepo.TeamExists(team.TeamName)
-	if err != nil {
-		log.Error("failed to check team existence", sl.Err(err))
-		return nil, fmt.Errorf("%s: %w", op, err)
+		profile, err := normalizeProfile(member.UserProfile)
+		if err != nil {
+			log.Warn("invalid member profile", slog.String("user_id", member.UserID), sl.Err(err))
+			return nil, err
+		}
+		team.Members[i].UserProfile = profile
 	}
 
-	if exists {
-		log.Warn("team already exists", slog.String("team_name", team.TeamName))
-		return nil, apperrors.ErrTeamExists
+	for _, member := range team.Members {
+		if _, err := models.ParseUserID(member.UserID); err != nil {
+			log.Warn("invalid member user ID", slog.String("user_id", member.UserID))
+			return nil, apperrors.ErrInvalidUserID
+		}
 	}
 
-	err = s.teamRepo.CreateTeam(team.TeamName)
+	err := s.teamRepo.CreateTeamWithMembers(team)
 	if err != nil {
+		if errors.Is(err, apperrors.ErrTeamExists) {
+			log.Warn("team already exists", slog.String("team_name", team.TeamName))
+			return nil, apperrors.ErrTeamExists
+		}
 		log.Error("failed to create team", sl.Err(err))
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	err = s.teamRepo.AddTeamMembers(team.TeamName, team.Members)
-	if err != nil {
-		log.Error("failed to add team members", sl.Err(err))
-		return nil, fmt.Errorf("%s: %w", op, err)
+	recordAudit(ctx, s.publisher, models.AuditEvent{
+		TeamName: team.TeamName,
+		Action:   models.AuditTeamCreated,
+	})
+	for _, member := range team.Members {
+		recordAudit(ctx, s.publisher, models.AuditEvent{
+			TeamName:  team.TeamName,
+			Action:    models.AuditMemberAdded,
+			SubjectID: member.UserID,
+		})
 	}
 
 	createdTeam, err := s.teamRepo.GetTeamWithMembers(team.TeamName)
@@ -159,8 +196,469 @@ func (s *TeamService) DeactivateTeamUsers(ctx context.Context, teamName string)
 		return 0, fmt.Errorf("%s: %w", op, err)
 	}
 
+	recordAudit(ctx, s.publisher, models.AuditEvent{
+		TeamName: teamName,
+		Action:   models.AuditTeamDeactivated,
+		Details:  fmt.Sprintf("deactivated %d users", deactivatedCount),
+	})
+
 	log.Info("team users deactivated successfully",
 		slog.Int("deactivated_count", deactivatedCount))
 
 	return deactivatedCount, nil
 }
+
+func (s *TeamService) UpdateTeamPolicy(ctx context.Context, teamName string, update models.TeamPolicyUpdate, actorID string) (*models.TeamPolicy, error) {
+	const op = "service.team.UpdateTeamPolicy"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("team_name", teamName),
+	)
+
+	log.Info("attempting to update team policy")
+
+	if teamName == "" {
+		log.Error("team name is required")
+		return nil, apperrors.ErrTeamNameRequired
+	}
+
+	for _, field := range update.Inherit {
+		if !slices.Contains(models.InheritablePolicyFields, field) {
+			log.Error("policy field cannot be inherited", slog.String("field", field))
+			return nil, apperrors.ErrInvalidPolicy
+		}
+	}
+
+	org, overrides, err := s.getPolicyLayers(log, teamName)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := *overrides
+	applyPolicyUpdate(overrides, update)
+
+	policy, err := s.saveTeamPolicy(ctx, log, *org, previous, *overrides, nil, actorID)
+	if err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+// GetTeamSettings returns the team's own policy fields, where null means the
+// org default applies, and their entity tag.
+func (s *TeamService) GetTeamSettings(ctx context.Context, teamName string) (*models.TeamPolicyOverrides, string, error) {
+	const op = "service.team.GetTeamSettings"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("team_name", teamName),
+	)
+
+	if teamName == "" {
+		log.Error("team name is required")
+		return nil, "", apperrors.ErrTeamNameRequired
+	}
+
+	_, overrides, err := s.getPolicyLayers(log, teamName)
+	if err != nil {
+		return nil, "", err
+	}
+
+	etag, err := mergepatch.ETag(overrides)
+	if err != nil {
+		log.Error("failed to compute entity tag", sl.Err(err))
+		return nil, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return overrides, etag, nil
+}
+
+// PatchTeamSettings applies a JSON merge patch to the team's own policy
+// fields; null resets a field to the org default. A non-empty ifMatch must
+// match the current entity tag.
+func (s *TeamService) PatchTeamSettings(ctx context.Context, teamName string, patch []byte, ifMatch string, actorID string) (*models.TeamPolicyOverrides, string, error) {
+	const op = "service.team.PatchTeamSettings"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("team_name", teamName),
+	)
+
+	log.Info("attempting to patch team settings")
+
+	if teamName == "" {
+		log.Error("team name is required")
+		return nil, "", apperrors.ErrTeamNameRequired
+	}
+
+	org, current, err := s.getPolicyLayers(log, teamName)
+	if err != nil {
+		return nil, "", err
+	}
+
+	etag, err := mergepatch.ETag(current)
+	if err != nil {
+		log.Error("failed to compute entity tag", sl.Err(err))
+		return nil, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !mergepatch.Matches(ifMatch, etag) {
+		log.Warn("team settings changed since they were read", slog.String("if_match", ifMatch))
+		return nil, "", apperrors.ErrPreconditionFailed
+	}
+
+	patched := *current
+	if err := mergepatch.Apply(&patched, patch); err != nil {
+		log.Warn("invalid merge patch", sl.Err(err))
+		return nil, "", err
+	}
+
+	if patched.DefaultLabels != nil {
+		labels := models.MergeTags(nil, *patched.DefaultLabels)
+		patched.DefaultLabels = &labels
+	}
+
+	if patched.DefaultRequiredSkills != nil {
+		skills := models.MergeTags(nil, *patched.DefaultRequiredSkills)
+		patched.DefaultRequiredSkills = &skills
+	}
+
+	patched.ReviewLabels = models.MergeTags(nil, patched.ReviewLabels)
+
+	if _, err := s.saveTeamPolicy(ctx, log, *org, *current, patched, current, actorID); err != nil {
+		return nil, "", err
+	}
+
+	return s.GetTeamSettings(ctx, teamName)
+}
+
+func (s *TeamService) getPolicyLayers(log *slog.Logger, teamName string) (*models.OrgPolicy, *models.TeamPolicyOverrides, error) {
+	const op = "service.team.getPolicyLayers"
+
+	org, overrides, err := s.teamRepo.GetPolicyLayers(teamName)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrTeamNotFound) {
+			log.Warn("team not found", slog.String("team_name", teamName))
+			return nil, nil, apperrors.ErrTeamNotFound
+		}
+		log.Error("failed to get team policy", sl.Err(err))
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return org, overrides, nil
+}
+
+// saveTeamPolicy validates and stores the new overrides, versioning and
+// auditing the change. With expected set the write fails with
+// ErrPreconditionFailed if the stored overrides no longer equal it.
+func (s *TeamService) saveTeamPolicy(
+	ctx context.Context,
+	log *slog.Logger,
+	org models.OrgPolicy,
+	previous models.TeamPolicyOverrides,
+	overrides models.TeamPolicyOverrides,
+	expected *models.TeamPolicyOverrides,
+	actorID string,
+) (*models.TeamPolicy, error) {
+	const op = "service.team.saveTeamPolicy"
+
+	if overrides.MinReviewers != nil && *overrides.MinReviewers < 0 {
+		log.Error("min reviewers must not be negative", slog.Int("min_reviewers", *overrides.MinReviewers))
+		return nil, apperrors.ErrInvalidPolicy
+	}
+
+	if overrides.ReviewersPerPR != nil && (*overrides.ReviewersPerPR < 1 || *overrides.ReviewersPerPR > maxReviewersPerTeam) {
+		log.Error("invalid reviewers per PR", slog.Int("reviewers_per_pr", *overrides.ReviewersPerPR))
+		return nil, apperrors.ErrInvalidPolicy
+	}
+
+	if overrides.DefaultPriority != nil && *overrides.DefaultPriority != "" && !models.IsValidPriority(*overrides.DefaultPriority) {
+		log.Error("invalid default priority", slog.String("default_priority", *overrides.DefaultPriority))
+		return nil, apperrors.ErrInvalidPolicy
+	}
+
+	effective := models.ResolvePolicy(org, overrides)
+	policy := &effective.Policy
+
+	changes := describePolicyChanges(models.ResolvePolicy(org, previous), effective)
+
+	err := s.teamRepo.UpdateTeamPolicy(expected, overrides, *policy, changes, actorID)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrPreconditionFailed) {
+			log.Warn("team policy changed concurrently")
+			return nil, apperrors.ErrPreconditionFailed
+		}
+		log.Error("failed to update team policy", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(changes) > 0 {
+		log.Info("team policy changed",
+			slog.String("actor_id", actorID),
+			slog.String("changes", strings.Join(changes, "; ")))
+
+		recordAudit(ctx, s.publisher, models.AuditEvent{
+			TeamName: overrides.TeamName,
+			Action:   models.AuditPolicyChanged,
+			ActorID:  actorID,
+			Details:  strings.Join(changes, "; "),
+		})
+	}
+
+	log.Info("team policy updated successfully",
+		slog.Bool("hold_until_ci_green", policy.HoldUntilCIGreen),
+		slog.Int("min_reviewers", policy.MinReviewers))
+
+	return policy, nil
+}
+
+func (s *TeamService) ArchiveTeam(ctx context.Context, teamName string) (int, error) {
+	const op = "service.team.ArchiveTeam"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("team_name", teamName),
+	)
+
+	log.Info("attempting to archive team")
+
+	if teamName == "" {
+		log.Error("team name is required")
+		return 0, apperrors.ErrTeamNameRequired
+	}
+
+	exists, err := s.teamRepo.TeamExists(teamName)
+	if err != nil {
+		log.Error("failed to check team existence", sl.Err(err))
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !exists {
+		log.Warn("team not found", slog.String("team_name", teamName))
+		return 0, apperrors.ErrTeamNotFound
+	}
+
+	deactivatedCount, err := s.teamRepo.ArchiveTeam(teamName)
+	if err != nil {
+		log.Error("failed to archive team", sl.Err(err))
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	recordAudit(ctx, s.publisher, models.AuditEvent{
+		TeamName: teamName,
+		Action:   models.AuditTeamArchived,
+		Details:  fmt.Sprintf("deactivated %d users", deactivatedCount),
+	})
+
+	log.Info("team archived successfully",
+		slog.Int("deactivated_count", deactivatedCount))
+
+	return deactivatedCount, nil
+}
+
+func (s *TeamService) GetPolicyHistory(ctx context.Context, teamName string) ([]models.PolicyVersion, error) {
+	const op = "service.team.GetPolicyHistory"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("team_name", teamName),
+	)
+
+	log.Info("attempting to get team policy history")
+
+	if teamName == "" {
+		log.Error("team name is required")
+		return nil, apperrors.ErrTeamNameRequired
+	}
+
+	exists, err := s.teamRepo.TeamExists(teamName)
+	if err != nil {
+		log.Error("failed to check team existence", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !exists {
+		log.Warn("team not found")
+		return nil, apperrors.ErrTeamNotFound
+	}
+
+	versions, err := s.teamRepo.GetPolicyVersions(teamName)
+	if err != nil {
+		log.Error("failed to get policy versions", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("team policy history retrieved", slog.Int("versions", len(versions)))
+
+	return versions, nil
+}
+
+func (s *TeamService) GetTeamChanges(ctx context.Context, teamName string) ([]models.AuditEvent, error) {
+	const op = "service.team.GetTeamChanges"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("team_name", teamName),
+	)
+
+	log.Info("attempting to get team changes")
+
+	if teamName == "" {
+		log.Error("team name is required")
+		return nil, apperrors.ErrTeamNameRequired
+	}
+
+	exists, err := s.teamRepo.TeamExists(teamName)
+	if err != nil {
+		log.Error("failed to check team existence", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !exists {
+		log.Warn("team not found")
+		return nil, apperrors.ErrTeamNotFound
+	}
+
+	events, err := s.auditRepo.GetTeamEvents(teamName)
+	if err != nil {
+		log.Error("failed to get team audit events", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("team changes retrieved", slog.Int("events", len(events)))
+
+	return events, nil
+}
+
+// describePolicyChanges returns a human readable line per changed policy field.
+func describePolicyChanges(beforeEffective models.EffectivePolicy, afterEffective models.EffectivePolicy) []string {
+	changes := make([]string, 0)
+	before, after := beforeEffective.Policy, afterEffective.Policy
+
+	if before.HoldUntilCIGreen != after.HoldUntilCIGreen {
+		changes = append(changes, fmt.Sprintf("hold_until_ci_green: %t -> %t", before.HoldUntilCIGreen, after.HoldUntilCIGreen))
+	}
+
+	if before.MinReviewers != after.MinReviewers {
+		changes = append(changes, fmt.Sprintf("min_reviewers: %d -> %d", before.MinReviewers, after.MinReviewers))
+	}
+
+	if before.ReviewersPerPR != after.ReviewersPerPR {
+		changes = append(changes, fmt.Sprintf("reviewers_per_pr: %d -> %d", before.ReviewersPerPR, after.ReviewersPerPR))
+	}
+
+	if before.DefaultPriority != after.DefaultPriority {
+		changes = append(changes, fmt.Sprintf("default_priority: %q -> %q", before.DefaultPriority, after.DefaultPriority))
+	}
+
+	if !slices.Equal(before.DefaultLabels, after.DefaultLabels) {
+		changes = append(changes, fmt.Sprintf("default_labels: %v -> %v", before.DefaultLabels, after.DefaultLabels))
+	}
+
+	if !slices.Equal(before.DefaultRequiredSkills, after.DefaultRequiredSkills) {
+		changes = append(changes, fmt.Sprintf("default_required_skills: %v -> %v", before.DefaultRequiredSkills, after.DefaultRequiredSkills))
+	}
+
+	if !slices.Equal(before.ReviewLabels, after.ReviewLabels) {
+		changes = append(changes, fmt.Sprintf("review_labels: %v -> %v", before.ReviewLabels, after.ReviewLabels))
+	}
+
+	if before.ExcludeCoAuthors != after.ExcludeCoAuthors {
+		changes = append(changes, fmt.Sprintf("exclude_co_authors: %t -> %t", before.ExcludeCoAuthors, after.ExcludeCoAuthors))
+	}
+
+	if before.ExcludePairingSession != after.ExcludePairingSession {
+		changes = append(changes, fmt.Sprintf("exclude_pairing_session: %t -> %t", before.ExcludePairingSession, after.ExcludePairingSession))
+	}
+
+	if before.DiversifyPairs != after.DiversifyPairs {
+		changes = append(changes, fmt.Sprintf("diversify_reviewer_pairs: %t -> %t", before.DiversifyPairs, after.DiversifyPairs))
+	}
+
+	// A field that switched layers without changing its value still gets a
+	// line, since it now follows a different source.
+	for _, field := range models.InheritablePolicyFields {
+		if beforeEffective.Sources[field] == afterEffective.Sources[field] {
+			continue
+		}
+		valueChanged := slices.ContainsFunc(changes, func(change string) bool {
+			return strings.HasPrefix(change, field+":")
+		})
+		if !valueChanged {
+			changes = append(changes, fmt.Sprintf("%s: source %s -> %s", field, beforeEffective.Sources[field], afterEffective.Sources[field]))
+		}
+	}
+
+	return changes
+}
+
+// applyPolicyUpdate sets the team overrides present in the update and drops
+// the ones it resets to the org default.
+func applyPolicyUpdate(overrides *models.TeamPolicyOverrides, update models.TeamPolicyUpdate) {
+	if update.HoldUntilCIGreen != nil {
+		overrides.HoldUntilCIGreen = update.HoldUntilCIGreen
+	}
+
+	if update.MinReviewers != nil {
+		overrides.MinReviewers = update.MinReviewers
+	}
+
+	if update.ReviewersPerPR != nil {
+		overrides.ReviewersPerPR = update.ReviewersPerPR
+	}
+
+	if update.DefaultPriority != nil {
+		overrides.DefaultPriority = update.DefaultPriority
+	}
+
+	if update.DefaultLabels != nil {
+		labels := models.MergeTags(nil, *update.DefaultLabels)
+		overrides.DefaultLabels = &labels
+	}
+
+	if update.DefaultRequiredSkills != nil {
+		skills := models.MergeTags(nil, *update.DefaultRequiredSkills)
+		overrides.DefaultRequiredSkills = &skills
+	}
+
+	if update.ReviewLabels != nil {
+		overrides.ReviewLabels = models.MergeTags(nil, *update.ReviewLabels)
+	}
+
+	if update.ExcludeCoAuthors != nil {
+		overrides.ExcludeCoAuthors = update.ExcludeCoAuthors
+	}
+
+	if update.ExcludePairingSession != nil {
+		overrides.ExcludePairingSession = update.ExcludePairingSession
+	}
+
+	if update.DiversifyPairs != nil {
+		overrides.DiversifyPairs = update.DiversifyPairs
+	}
+
+	for _, field := range update.Inherit {
+		switch field {
+		case models.PolicyFieldHoldUntilCIGreen:
+			overrides.HoldUntilCIGreen = nil
+		case models.PolicyFieldMinReviewers:
+			overrides.MinReviewers = nil
+		case models.PolicyFieldReviewersPerPR:
+			overrides.ReviewersPerPR = nil
+		case models.PolicyFieldDefaultPriority:
+			overrides.DefaultPriority = nil
+		case models.PolicyFieldDefaultLabels:
+			overrides.DefaultLabels = nil
+		case models.PolicyFieldDefaultRequiredSkills:
+			overrides.DefaultRequiredSkills = nil
+		case models.PolicyFieldExcludeCoAuthors:
+			overrides.ExcludeCoAuthors = nil
+		case models.PolicyFieldExcludePairingSession:
+			overrides.ExcludePairingSession = nil
+		case models.PolicyFieldDiversifyPairs:
+			overrides.DiversifyPairs = nil
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"pull-request-assigner/internal/apperrors"
+	"pull-request-assigner/internal/domain/models"
+	"pull-request-assigner/internal/lib/logger/sl"
+)
+
+// SetTeamLead adds or removes the user as a lead of the team, which makes
+// the team's stats visible to them. Repeating a change is a no-op.
+func (s *TeamService) SetTeamLead(ctx context.Context, teamName string, userID string, isLead bool) error {
+	const op = "service.team.SetTeamLead"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("team_name", teamName),
+		slog.String("user_id", userID),
+		slog.Bool("is_lead", isLead),
+	)
+
+	if teamName == "" {
+		log.Error("team name is required")
+		return apperrors.ErrTeamNameRequired
+	}
+
+	uid, err := models.ParseUserID(userID)
+	if err != nil {
+		log.Error("invalid user ID format", sl.Err(err))
+		return apperrors.ErrInvalidUserID
+	}
+
+	var changed bool
+	action := models.AuditTeamLeadAdded
+	if isLead {
+		changed, err = s.teamRepo.AddTeamLead(teamName, uid.Int())
+	} else {
+		action = models.AuditTeamLeadRemoved
+		changed, err = s.teamRepo.RemoveTeamLead(teamName, uid.Int())
+	}
+	if err != nil {
+		if errors.Is(err, apperrors.ErrTeamNotFound) || errors.Is(err, apperrors.ErrUserNotFound) {
+			log.Warn("team or user not found", sl.Err(err))
+			return err
+		}
+		log.Error("failed to update team lead", sl.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !changed {
+		return nil
+	}
+
+	recordAudit(ctx, s.publisher, models.AuditEvent{
+		TeamName:  teamName,
+		Action:    action,
+		SubjectID: uid.String(),
+	})
+
+	log.Info("team lead updated")
+
+	return nil
+}
+
+func (s *TeamService) GetTeamLeads(ctx context.Context) ([]models.TeamLead, error) {
+	const op = "service.team.GetTeamLeads"
+
+	log := s.log.With(slog.String("op", op))
+
+	leads, err := s.teamRepo.GetTeamLeads()
+	if err != nil {
+		log.Error("failed to get team leads", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return leads, nil
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"pull-request-assigner/internal/apperrors"
+	"pull-request-assigner/internal/domain/models"
+	"pull-request-assigner/internal/lib/logger/sl"
+)
+
+// SetSecondaryMember adds or removes the user as a secondary member of the
+// team. Secondary members are reviewer candidates of the team and may open
+// PRs for it, while users.team_name keeps their primary team. Repeating a
+// change is a no-op.
+func (s *TeamService) SetSecondaryMember(ctx context.Context, teamName string, userID string, isMember bool) (*models.Team, error) {
+	const op = "service.team.SetSecondaryMember"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("team_name", teamName),
+		slog.String("user_id", userID),
+		slog.Bool("is_member", isMember),
+	)
+
+	if teamName == "" {
+		log.Error("team name is required")
+		return nil, apperrors.ErrTeamNameRequired
+	}
+
+	uid, err := models.ParseUserID(userID)
+	if err != nil {
+		log.Error("invalid user ID format", sl.Err(err))
+		return nil, apperrors.ErrInvalidUserID
+	}
+
+	var changed bool
+	action := models.AuditMemberAdded
+	if isMember {
+		changed, err = s.teamRepo.AddSecondaryMember(teamName, uid.Int())
+	} else {
+		action = models.AuditMemberRemoved
+		changed, err = s.teamRepo.RemoveSecondaryMember(teamName, uid.Int())
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, apperrors.ErrTeamNotFound), errors.Is(err, apperrors.ErrUserNotFound):
+			log.Warn("team or user not found", sl.Err(err))
+			return nil, err
+		case errors.Is(err, apperrors.ErrPrimaryTeam):
+			log.Warn("team is the user's primary team")
+			return nil, apperrors.ErrPrimaryTeam
+		}
+		log.Error("failed to update secondary membership", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if changed {
+		recordAudit(ctx, s.publisher, models.AuditEvent{
+			TeamName:  teamName,
+			Action:    action,
+			SubjectID: uid.String(),
+			Details:   "secondary",
+		})
+		log.Info("secondary membership updated")
+	}
+
+	team, err := s.teamRepo.GetTeamWithMembers(teamName)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrTeamNotFound) {
+			log.Warn("team not found")
+			return nil, apperrors.ErrTeamNotFound
+		}
+		log.Error("failed to get team", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return team, nil
+}
@@ -0,0 +1,358 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"pull-request-assigner/internal/apperrors"
+	"pull-request-assigner/internal/domain/models"
+	"pull-request-assigner/internal/lib/logger/sl"
+	"strings"
+	"time"
+)
+
+// tokenPrefix marks issued API keys so they are easy to recognise in configs
+// and secret scanners.
+const tokenPrefix = "pra_"
+
+type TokenStore interface {
+	CreateToken(name string, userID int, tokenHash string, scopes []string, hourlyQuota *int, expiresAt *time.Time) (*models.APIToken, error)
+	CreateBootstrapToken(name string, userID int, tokenHash string) (*models.APIToken, error)
+	ListTokens() ([]models.APIToken, error)
+	RotateToken(tokenID int64, tokenHash string) (*models.APIToken, error)
+	RevokeToken(tokenID int64) (*models.APIToken, error)
+	SetTokenQuota(tokenID int64, hourlyQuota *int) (*models.APIToken, error)
+	TouchToken(tokenHash string) (token *models.APIToken, ownerActive bool, err error)
+}
+
+type TokenService struct {
+	log       *slog.Logger
+	tokenRepo TokenStore
+	// bootstrapSecret authenticates BootstrapToken; empty disables it.
+	bootstrapSecret string
+}
+
+func NewTokenService(
+	log *slog.Logger,
+	tokenRepo TokenStore,
+	bootstrapSecret string) *TokenService {
+	return &TokenService{
+		log:             log,
+		tokenRepo:       tokenRepo,
+		bootstrapSecret: bootstrapSecret,
+	}
+}
+
+// IssueToken creates a token for the user and returns it together with the
+// raw key, which is shown only once; only its hash is stored. Requests made
+// with the key act as that user. A nil hourlyQuota makes the key follow the
+// default quota.
+func (s *TokenService) IssueToken(ctx context.Context, name string, userID string, scopes []string, hourlyQuota *int, expiresAt *time.Time) (*models.APIToken, string, error) {
+	const op = "service.token.IssueToken"
+
+	name = strings.TrimSpace(name)
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("name", name),
+		slog.String("user_id", userID),
+		slog.Any("scopes", scopes),
+	)
+
+	log.Info("attempting to issue API token")
+
+	if name == "" {
+		log.Error("token name is required")
+		return nil, "", apperrors.ErrTokenNameRequired
+	}
+
+	if userID == "" {
+		log.Warn("token user is required")
+		return nil, "", apperrors.ErrTokenUserRequired
+	}
+
+	uid, err := models.ParseUserID(userID)
+	if err != nil {
+		log.Warn("invalid user ID format", sl.Err(err))
+		return nil, "", err
+	}
+
+	scopes, err = normalizeScopes(scopes)
+	if err != nil {
+		log.Warn("invalid token scopes", sl.Err(err))
+		return nil, "", err
+	}
+
+	if hourlyQuota != nil && *hourlyQuota < 0 {
+		log.Warn("token quota is negative", slog.Int("hourly_quota", *hourlyQuota))
+		return nil, "", apperrors.ErrInvalidTokenQuota
+	}
+
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		log.Warn("token expiry is in the past", slog.Time("expires_at", *expiresAt))
+		return nil, "", apperrors.ErrTokenExpiryInPast
+	}
+
+	key, err := generateTokenKey()
+	if err != nil {
+		log.Error("failed to generate token key", sl.Err(err))
+		return nil, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	token, err := s.tokenRepo.CreateToken(name, uid.Int(), hashTokenKey(key), scopes, hourlyQuota, expiresAt)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrUserNotFound) {
+			log.Warn("token user not found")
+			return nil, "", apperrors.ErrUserNotFound
+		}
+		log.Error("failed to create token", sl.Err(err))
+		return nil, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("API token issued", slog.Int64("token_id", token.TokenID))
+	return token, key, nil
+}
+
+// BootstrapToken issues the first admin token for the user. The caller proves
+// itself with the configured secret instead of an API key, and the call only
+// works while no active admin token exists; after that admin tokens are
+// issued with IssueToken by an admin.
+func (s *TokenService) BootstrapToken(ctx context.Context, secret string, name string, userID string) (*models.APIToken, string, error) {
+	const op = "service.token.BootstrapToken"
+
+	name = strings.TrimSpace(name)
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("name", name),
+		slog.String("user_id", userID),
+	)
+
+	log.Info("attempting to bootstrap admin API token")
+
+	if s.bootstrapSecret == "" || !hmac.Equal([]byte(secret), []byte(s.bootstrapSecret)) {
+		log.Warn("invalid bootstrap secret")
+		return nil, "", apperrors.ErrInvalidBootstrap
+	}
+
+	if name == "" {
+		log.Error("token name is required")
+		return nil, "", apperrors.ErrTokenNameRequired
+	}
+
+	if userID == "" {
+		log.Warn("token user is required")
+		return nil, "", apperrors.ErrTokenUserRequired
+	}
+
+	uid, err := models.ParseUserID(userID)
+	if err != nil {
+		log.Warn("invalid user ID format", sl.Err(err))
+		return nil, "", err
+	}
+
+	key, err := generateTokenKey()
+	if err != nil {
+		log.Error("failed to generate token key", sl.Err(err))
+		return nil, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	token, err := s.tokenRepo.CreateBootstrapToken(name, uid.Int(), hashTokenKey(key))
+	if err != nil {
+		switch {
+		case errors.Is(err, apperrors.ErrBootstrapClosed):
+			log.Warn("an admin token already exists")
+			return nil, "", apperrors.ErrBootstrapClosed
+		case errors.Is(err, apperrors.ErrUserNotFound):
+			log.Warn("token user not found")
+			return nil, "", apperrors.ErrUserNotFound
+		}
+		log.Error("failed to create bootstrap token", sl.Err(err))
+		return nil, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("admin API token bootstrapped", slog.Int64("token_id", token.TokenID))
+	return token, key, nil
+}
+
+func (s *TokenService) ListTokens(ctx context.Context) ([]models.APIToken, error) {
+	const op = "service.token.ListTokens"
+
+	log := s.log.With(slog.String("op", op))
+
+	tokens, err := s.tokenRepo.ListTokens()
+	if err != nil {
+		log.Error("failed to list tokens", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return tokens, nil
+}
+
+// RotateToken issues a new key for the token; the previous key stops working
+// immediately.
+func (s *TokenService) RotateToken(ctx context.Context, tokenID int64) (*models.APIToken, string, error) {
+	const op = "service.token.RotateToken"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("token_id", tokenID),
+	)
+
+	log.Info("attempting to rotate API token")
+
+	key, err := generateTokenKey()
+	if err != nil {
+		log.Error("failed to generate token key", sl.Err(err))
+		return nil, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	token, err := s.tokenRepo.RotateToken(tokenID, hashTokenKey(key))
+	if err != nil {
+		switch {
+		case errors.Is(err, apperrors.ErrTokenNotFound):
+			log.Warn("token not found")
+			return nil, "", apperrors.ErrTokenNotFound
+		case errors.Is(err, apperrors.ErrTokenRevoked):
+			log.Warn("cannot rotate revoked token")
+			return nil, "", apperrors.ErrTokenRevoked
+		}
+		log.Error("failed to rotate token", sl.Err(err))
+		return nil, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("API token rotated")
+	return token, key, nil
+}
+
+func (s *TokenService) RevokeToken(ctx context.Context, tokenID int64) (*models.APIToken, error) {
+	const op = "service.token.RevokeToken"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("token_id", tokenID),
+	)
+
+	log.Info("attempting to revoke API token")
+
+	token, err := s.tokenRepo.RevokeToken(tokenID)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrTokenNotFound) {
+			log.Warn("token not found")
+			return nil, apperrors.ErrTokenNotFound
+		}
+		log.Error("failed to revoke token", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("API token revoked")
+	return token, nil
+}
+
+// SetTokenQuota changes how many requests per hour the token may make; 0
+// lifts the limit and nil makes it follow the default quota. Requests
+// already counted this hour stay counted.
+func (s *TokenService) SetTokenQuota(ctx context.Context, tokenID int64, hourlyQuota *int) (*models.APIToken, error) {
+	const op = "service.token.SetTokenQuota"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("token_id", tokenID),
+	)
+
+	log.Info("attempting to set API token quota")
+
+	if hourlyQuota != nil && *hourlyQuota < 0 {
+		log.Warn("token quota is negative", slog.Int("hourly_quota", *hourlyQuota))
+		return nil, apperrors.ErrInvalidTokenQuota
+	}
+
+	token, err := s.tokenRepo.SetTokenQuota(tokenID, hourlyQuota)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrTokenNotFound) {
+			log.Warn("token not found")
+			return nil, apperrors.ErrTokenNotFound
+		}
+		log.Error("failed to set token quota", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("API token quota set")
+	return token, nil
+}
+
+// Authenticate resolves a raw API key to its token. Unknown, revoked and
+// expired keys, and keys of deactivated users, are all reported as
+// ErrInvalidToken.
+func (s *TokenService) Authenticate(ctx context.Context, key string) (*models.APIToken, error) {
+	const op = "service.token.Authenticate"
+
+	log := s.log.With(slog.String("op", op))
+
+	token, ownerActive, err := s.tokenRepo.TouchToken(hashTokenKey(key))
+	if err != nil {
+		if errors.Is(err, apperrors.ErrTokenNotFound) {
+			log.Warn("unknown API key")
+			return nil, apperrors.ErrInvalidToken
+		}
+		log.Error("failed to look up token", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if token.RevokedAt != nil {
+		log.Warn("revoked API key", slog.Int64("token_id", token.TokenID))
+		return nil, apperrors.ErrInvalidToken
+	}
+
+	if token.ExpiresAt != nil && !token.ExpiresAt.After(time.Now()) {
+		log.Warn("expired API key", slog.Int64("token_id", token.TokenID))
+		return nil, apperrors.ErrInvalidToken
+	}
+
+	if !ownerActive {
+		log.Warn("API key of an inactive user",
+			slog.Int64("token_id", token.TokenID),
+			slog.String("user_id", token.UserID))
+		return nil, apperrors.ErrInvalidToken
+	}
+
+	return token, nil
+}
+
+func normalizeScopes(scopes []string) ([]string, error) {
+	lowered := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		lowered = append(lowered, strings.ToLower(scope))
+	}
+
+	result := models.MergeTags(nil, lowered)
+	if len(result) == 0 {
+		return nil, apperrors.ErrTokenScopeRequired
+	}
+
+	for _, scope := range result {
+		if !models.IsValidTokenScope(scope) {
+			return nil, apperrors.ErrInvalidTokenScope
+		}
+	}
+
+	return result, nil
+}
+
+func generateTokenKey() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return tokenPrefix + hex.EncodeToString(buf), nil
+}
+
+func hashTokenKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"pull-request-assigner/internal/domain/models"
+	"pull-request-assigner/internal/lib/logger/sl"
+	"pull-request-assigner/internal/lib/trace"
+	"slices"
+	"sort"
+	"time"
+)
+
+// traceTeamPick records a pick from a team's active members in the request's
+// assignment trace, if any. Failing to score the candidates only leaves the
+// scores out of the trace.
+func (s *PullRequestService) traceTeamPick(ctx context.Context, authorID string, quota models.ReviewerTeamQuota, exclude []string, pool []string, picked []string, log *slog.Logger) {
+	t := trace.Assignment(ctx)
+	if t == nil {
+		return
+	}
+
+	step := newTraceStep(models.TraceSourceTeam, quota.Reviewers, exclude, pool, picked)
+	step.TeamName = quota.TeamName
+
+	candidates, err := s.prRepo.GetCandidateStats(quota.TeamName, authorID, exclude, time.Now().Add(-pairingWindow))
+	if err != nil {
+		log.Warn("failed to score candidates for assignment trace", sl.Err(err))
+	} else {
+		for i := range candidates {
+			candidates[i].Score = candidateScore(candidates[i])
+		}
+		sort.SliceStable(candidates, func(i, j int) bool {
+			return candidates[i].Score > candidates[j].Score
+		})
+		step.Candidates = candidates
+	}
+
+	t.Steps = append(t.Steps, step)
+	t.Picks = append(t.Picks, picked...)
+}
+
+// tracePoolPick records a pick from a reviewer pool's active members.
+func tracePoolPick(ctx context.Context, poolName string, wanted int, exclude []string, pool []string, picked []string) {
+	t := trace.Assignment(ctx)
+	if t == nil {
+		return
+	}
+
+	step := newTraceStep(models.TraceSourcePool, wanted, exclude, pool, picked)
+	step.PoolName = poolName
+
+	t.Steps = append(t.Steps, step)
+	t.Picks = append(t.Picks, picked...)
+}
+
+// traceCertifiedPick records a pick from the users certified for area.
+func traceCertifiedPick(ctx context.Context, area string, exclude []string, pool []string, picked string) {
+	t := trace.Assignment(ctx)
+	if t == nil {
+		return
+	}
+
+	step := newTraceStep(models.TraceSourceCertification, 1, exclude, pool, []string{picked})
+	step.Area = area
+
+	t.Steps = append(t.Steps, step)
+	t.Picks = append(t.Picks, picked)
+}
+
+// traceAssigned marks the PR's current reviewers as excluded from a pick.
+func traceAssigned(ctx context.Context, reviewers []string) {
+	t := trace.Assignment(ctx)
+	if t == nil {
+		return
+	}
+
+	for _, reviewer := range reviewers {
+		t.Exclusions = append(t.Exclusions, models.ReviewerExclusion{UserID: reviewer, Reason: models.ExclusionAlreadyAssigned})
+	}
+}
+
+func newTraceStep(source string, wanted int, exclude []string, pool []string, picked []string) models.AssignmentTraceStep {
+	step := models.AssignmentTraceStep{
+		Source:   source,
+		Wanted:   wanted,
+		Excluded: slices.Clone(exclude),
+		Pool:     slices.Clone(pool),
+		Picked:   slices.Clone(picked),
+	}
+	if step.Excluded == nil {
+		step.Excluded = []string{}
+	}
+	if step.Pool == nil {
+		step.Pool = []string{}
+	}
+	if step.Picked == nil {
+		step.Picked = []string{}
+	}
+	return step
+}
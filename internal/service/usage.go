@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"pull-request-assigner/internal/apperrors"
+	"pull-request-assigner/internal/domain/models"
+	"pull-request-assigner/internal/lib/actor"
+	"pull-request-assigner/internal/lib/logger/sl"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// MaxUsageWindow bounds the time range of a single usage query.
+const MaxUsageWindow = 31 * 24 * time.Hour
+
+type UsageProvider interface {
+	AddUsage(records []models.UsageRecord) error
+	GetUsage(from time.Time, to time.Time, bucket string) ([]models.UsageRecord, error)
+	TakeQuota(clientID string, windowStart time.Time, quota int) (bool, error)
+	PruneQuotaCounters(before time.Time) error
+}
+
+type usageKey struct {
+	clientID string
+	bucket   time.Time
+}
+
+// UsageService counts requests per client in memory and periodically flushes
+// the counters to the usage table. Hourly quotas are enforced on counters in
+// the database, so every replica sees the same count: per API key, with the
+// key's own quota or the default one, and per client for requests without a
+// key.
+type UsageService struct {
+	log         *slog.Logger
+	usageRepo   UsageProvider
+	hourlyQuota int
+
+	mu      sync.Mutex
+	pending map[usageKey]*models.UsageRecord
+}
+
+func NewUsageService(
+	log *slog.Logger,
+	usageRepo UsageProvider,
+	hourlyQuota int) *UsageService {
+	return &UsageService{
+		log:         log,
+		usageRepo:   usageRepo,
+		hourlyQuota: hourlyQuota,
+		pending:     make(map[usageKey]*models.UsageRecord),
+	}
+}
+
+// Record counts a request of the client. It returns false together with the
+// time until the quota resets when the client is over its hourly quota.
+func (s *UsageService) Record(ctx context.Context, clientID string, mutation bool) (bool, time.Duration) {
+	return s.record(ctx, clientID, mutation, time.Now().UTC())
+}
+
+func (s *UsageService) record(ctx context.Context, clientID string, mutation bool, now time.Time) (bool, time.Duration) {
+	const op = "service.usage.Record"
+
+	hour := now.Truncate(time.Hour)
+
+	quotaClient, quota := s.quotaFor(ctx, clientID)
+	if quota > 0 {
+		allowed, err := s.usageRepo.TakeQuota(quotaClient, hour, quota)
+		if err != nil {
+			// Failing open: a database hiccup should not reject every request.
+			s.log.Error("failed to check hourly quota",
+				slog.String("op", op),
+				slog.String("client_id", quotaClient),
+				sl.Err(err))
+		} else if !allowed {
+			return false, hour.Add(time.Hour).Sub(now)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := usageKey{clientID: clientID, bucket: hour}
+	record, ok := s.pending[key]
+	if !ok {
+		record = &models.UsageRecord{ClientID: clientID, Bucket: hour}
+		s.pending[key] = record
+	}
+
+	record.Requests++
+	if mutation {
+		record.Mutations++
+	}
+
+	return true, 0
+}
+
+// quotaFor returns the counter and the hourly quota of the request: the API
+// key's when it has one, otherwise the client's with the default quota.
+func (s *UsageService) quotaFor(ctx context.Context, clientID string) (string, int) {
+	tokenID, tokenQuota, ok := actor.Token(ctx)
+	if !ok {
+		return clientID, s.hourlyQuota
+	}
+
+	quota := s.hourlyQuota
+	if tokenQuota != nil {
+		quota = *tokenQuota
+	}
+	return "token:" + strconv.FormatInt(tokenID, 10), quota
+}
+
+// Flush writes the counters collected since the previous flush and drops the
+// quota counters of past hours. On failure the counters are put back so they
+// are retried on the next flush.
+func (s *UsageService) Flush(ctx context.Context) error {
+	const op = "service.usage.Flush"
+
+	if err := s.usageRepo.PruneQuotaCounters(time.Now().UTC().Truncate(time.Hour)); err != nil {
+		s.log.Warn("failed to prune quota counters", slog.String("op", op), sl.Err(err))
+	}
+
+	s.mu.Lock()
+	pending := s.pending
+	s.pending = make(map[usageKey]*models.UsageRecord)
+	s.mu.Unlock()
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	records := make([]models.UsageRecord, 0, len(pending))
+	for _, record := range pending {
+		records = append(records, *record)
+	}
+
+	if err := s.usageRepo.AddUsage(records); err != nil {
+		s.mu.Lock()
+		for key, record := range pending {
+			if existing, ok := s.pending[key]; ok {
+				existing.Requests += record.Requests
+				existing.Mutations += record.Mutations
+			} else {
+				s.pending[key] = record
+			}
+		}
+		s.mu.Unlock()
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *UsageService) GetUsage(ctx context.Context, from time.Time, to time.Time, bucket string) ([]models.UsageRecord, error) {
+	const op = "service.usage.GetUsage"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Time("from", from),
+		slog.Time("to", to),
+		slog.String("bucket", bucket),
+	)
+
+	log.Info("getting API usage")
+
+	if bucket != models.UsageBucketHour && bucket != models.UsageBucketDay {
+		log.Error("invalid usage bucket")
+		return nil, apperrors.ErrInvalidUsageQuery
+	}
+
+	if !to.After(from) || to.Sub(from) > MaxUsageWindow {
+		log.Error("invalid usage window")
+		return nil, apperrors.ErrInvalidUsageQuery
+	}
+
+	if err := s.Flush(ctx); err != nil {
+		log.Warn("failed to flush pending usage", sl.Err(err))
+	}
+
+	records, err := s.usageRepo.GetUsage(from, to, bucket)
+	if err != nil {
+		log.Error("failed to get usage", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("API usage retrieved", slog.Int("records", len(records)))
+
+	return records, nil
+}
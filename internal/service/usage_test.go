@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"pull-request-assigner/internal/lib/actor"
+	"sync"
+	"testing"
+	"time"
+)
+
+type quotaCounter struct {
+	clientID    string
+	windowStart time.Time
+}
+
+// usageRepoFake stands in for the shared counter table; several services
+// using one fake behave like replicas sharing a database.
+type usageRepoFake struct {
+	UsageProvider
+
+	mu       sync.Mutex
+	counters map[quotaCounter]int
+	err      error
+}
+
+func newUsageRepoFake() *usageRepoFake {
+	return &usageRepoFake{counters: make(map[quotaCounter]int)}
+}
+
+func (f *usageRepoFake) TakeQuota(clientID string, windowStart time.Time, quota int) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.err != nil {
+		return false, f.err
+	}
+
+	key := quotaCounter{clientID: clientID, windowStart: windowStart}
+	if f.counters[key] >= quota {
+		return false, nil
+	}
+	f.counters[key]++
+	return true, nil
+}
+
+func newUsageTestService(repo *usageRepoFake, hourlyQuota int) *UsageService {
+	return NewUsageService(slog.New(slog.NewTextHandler(io.Discard, nil)), repo, hourlyQuota)
+}
+
+func withToken(tokenID int64, hourlyQuota *int) context.Context {
+	return actor.WithToken(context.Background(), tokenID, hourlyQuota)
+}
+
+func quota(n int) *int {
+	return &n
+}
+
+func TestUsageTokenQuota(t *testing.T) {
+	s := newUsageTestService(newUsageRepoFake(), 100)
+	now := time.Date(2026, 3, 2, 10, 45, 0, 0, time.UTC)
+	ctx := withToken(1, quota(3))
+
+	for i := 0; i < 3; i++ {
+		if allowed, _ := s.record(ctx, "key:a", false, now); !allowed {
+			t.Fatalf("request %d within the token quota was rejected", i+1)
+		}
+	}
+
+	allowed, retryAfter := s.record(ctx, "key:a", false, now)
+	if allowed {
+		t.Fatal("expected the request over the token quota to be rejected")
+	}
+	if retryAfter != 15*time.Minute {
+		t.Fatalf("expected retry after 15m, got %s", retryAfter)
+	}
+
+	if allowed, _ := s.record(withToken(2, quota(3)), "key:b", false, now); !allowed {
+		t.Fatal("expected another token to keep its own quota")
+	}
+}
+
+func TestUsageQuotaResetsEveryHour(t *testing.T) {
+	s := newUsageTestService(newUsageRepoFake(), 0)
+	ctx := withToken(1, quota(1))
+	now := time.Date(2026, 3, 2, 10, 59, 59, 0, time.UTC)
+
+	if allowed, _ := s.record(ctx, "key:a", false, now); !allowed {
+		t.Fatal("first request was rejected")
+	}
+	if allowed, _ := s.record(ctx, "key:a", false, now); allowed {
+		t.Fatal("expected the second request of the hour to be rejected")
+	}
+	if allowed, _ := s.record(ctx, "key:a", false, now.Add(time.Second)); !allowed {
+		t.Fatal("expected the quota to reset with the next hour")
+	}
+}
+
+func TestUsageQuotaSharedAcrossReplicas(t *testing.T) {
+	repo := newUsageRepoFake()
+	replicas := []*UsageService{newUsageTestService(repo, 0), newUsageTestService(repo, 0)}
+	ctx := withToken(1, quota(4))
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+
+	accepted := 0
+	for i := 0; i < 10; i++ {
+		if allowed, _ := replicas[i%2].record(ctx, "key:a", false, now); allowed {
+			accepted++
+		}
+	}
+	if accepted != 4 {
+		t.Fatalf("expected 4 requests accepted across replicas, got %d", accepted)
+	}
+}
+
+func TestUsageDefaultQuota(t *testing.T) {
+	s := newUsageTestService(newUsageRepoFake(), 1)
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		clientID string
+		second   bool
+	}{
+		{name: "token without quota follows the default", ctx: withToken(1, nil), clientID: "key:a", second: false},
+		{name: "token with quota 0 is unlimited", ctx: withToken(2, quota(0)), clientID: "key:b", second: true},
+		{name: "caller without key follows the default", ctx: context.Background(), clientID: "ip:1", second: false},
+		{name: "callers without key are counted apart", ctx: context.Background(), clientID: "ip:2", second: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if allowed, _ := s.record(tt.ctx, tt.clientID, false, now); !allowed {
+				t.Fatal("first request was rejected")
+			}
+			if allowed, _ := s.record(tt.ctx, tt.clientID, false, now); allowed != tt.second {
+				t.Fatalf("expected second request allowed=%t, got %t", tt.second, allowed)
+			}
+		})
+	}
+}
+
+func TestUsageQuotaFailsOpen(t *testing.T) {
+	repo := newUsageRepoFake()
+	repo.err = errors.New("connection refused")
+	s := newUsageTestService(repo, 1)
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 2; i++ {
+		if allowed, _ := s.record(withToken(1, nil), "key:a", true, now); !allowed {
+			t.Fatal("expected requests to pass while the counter store is down")
+		}
+	}
+
+	record := s.pending[usageKey{clientID: "key:a", bucket: now}]
+	if record == nil || record.Requests != 2 || record.Mutations != 2 {
+		t.Fatalf("expected usage to be counted, got %+v", record)
+	}
+}
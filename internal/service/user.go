@@ -6,31 +6,71 @@ import (
 	"fmt"
 	"log/slog"
 	"pull-request-assigner/internal/apperrors"
+	"pull-request-assigner/internal/domain/events"
 	"pull-request-assigner/internal/domain/models"
 	"pull-request-assigner/internal/lib/logger/sl"
-	"strconv"
+	"pull-request-assigner/internal/lib/mergepatch"
+	"sort"
+	"strings"
+	"time"
 )
 
 type UserService struct {
-	log          *slog.Logger
-	userProvider UserProvider
+	log            *slog.Logger
+	userProvider   UserProvider
+	publisher      events.Publisher
+	reviewSLA      time.Duration
+	prLinkTemplate string
+	watcher        *ReviewWatcher
+	reviews        ReviewReassigner
 }
 
 type UserProvider interface {
 	SetIsActive(isActive bool, userID int) (models.User, error)
+	SetAuthorOnly(authorOnly bool, until *time.Time, userID int) (models.User, error)
 	GetReview(userID int) ([]models.PullRequestShort, error)
+	SetIsActiveBatch(isActive bool, userIDs []int) ([]models.User, error)
+	GetOpenReviews(userID int) ([]models.ReviewAssignment, error)
+	SetQueueOrder(userID int, prIDs []string) error
+	GetUser(userID int) (models.User, error)
+	GetUserSummary(userID int) (models.UserSummary, error)
+	IsAnonymized(userID int) (bool, error)
+	UpdateUserSettings(userID int, expected models.UserSettings, settings models.UserSettings) (models.User, error)
+	GetForecastInputs(userID int, since time.Time) (models.ForecastInputs, error)
 }
 
+const MaxUsersPerBatch = 1000
+
+// MaxReviewWait bounds how long a long-poll for review changes may hold.
+const MaxReviewWait = 60 * time.Second
+
+// maxDailyAssignments bounds the per-user daily assignment cap.
+const maxDailyAssignments = 100
+
 func NewUserService(
 	log *slog.Logger,
-	userProvider UserProvider) *UserService {
+	userProvider UserProvider,
+	publisher events.Publisher,
+	reviewSLA time.Duration,
+	prLinkTemplate string,
+	watcher *ReviewWatcher,
+	reviews ReviewReassigner) *UserService {
 	return &UserService{
-		log:          log,
-		userProvider: userProvider,
+		log:            log,
+		userProvider:   userProvider,
+		publisher:      publisher,
+		reviewSLA:      reviewSLA,
+		prLinkTemplate: prLinkTemplate,
+		watcher:        watcher,
+		reviews:        reviews,
 	}
 }
 
-func (s *UserService) SetUserActiveStatus(ctx context.Context, isActive bool, userID string) (models.User, error) {
+// SetUserActiveStatus activates or deactivates the user. Deactivating with
+// reassignOpenReviews set also hands each of the user's open reviews to
+// another reviewer, picked the way a manual reassignment picks one, and
+// returns what happened to them; otherwise the handover is nil.
+func (s *UserService) SetUserActiveStatus(ctx context.Context, isActive bool, userID string, reassignOpenReviews bool) (models.User, *models.ReviewHandover, error) {
 	const op = "service.user.SetUserActiveStatus"
 
 	log := s.log.With(
@@ -41,32 +81,279 @@ func (s *UserService) SetUserActiveStatus(ctx context.Context, isActive bool, us
 
 	log.Info("attempting to change user active status")
 
-	userIDInt, err := strconv.Atoi(userID[1:])
+	id, err := models.ParseUserID(userID)
 	if err != nil {
 		log.Error("invalid user ID format", sl.Err(err))
-		return models.User{}, apperrors.ErrInvalidUserID
+		return models.User{}, nil, err
 	}
 
-	user, err := s.userProvider.SetIsActive(isActive, userIDInt)
+	user, err := s.userProvider.SetIsActive(isActive, id.Int())
 	if err != nil {
 		log.Error("failed to set user active status", sl.Err(err))
 
 		if errors.Is(err, apperrors.ErrUserNotFound) {
-			return models.User{}, apperrors.ErrUserNotFound
+			return models.User{}, nil, apperrors.ErrUserNotFound
 		}
 
-		return models.User{}, fmt.Errorf("%s: %w", op, err)
+		return models.User{}, nil, fmt.Errorf("%s: %w", op, err)
 	}
 
+	recordAudit(ctx, s.publisher, activityAuditEvent(user, isActive))
+
 	status := "active"
 	if !isActive {
 		status = "not active"
 	}
 	log.Info("user status changed successfully", slog.String("status", status))
 
+	if isActive || !reassignOpenReviews {
+		return user, nil, nil
+	}
+
+	reviews, err := s.userProvider.GetOpenReviews(id.Int())
+	if err != nil {
+		log.Error("failed to get open reviews", sl.Err(err))
+		return models.User{}, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	note := fmt.Sprintf("%s was deactivated before finishing this review", user.Username)
+
+	handover := &models.ReviewHandover{}
+	handover.Reassigned, handover.Unreassigned, err = handOverReviews(ctx, log, s.reviews, reviews, user.UserID, models.ReassignReasonDeactivated, note)
+	if err != nil {
+		return models.User{}, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("open reviews handed over",
+		slog.Int("reassigned", len(handover.Reassigned)),
+		slog.Int("unreassigned", len(handover.Unreassigned)))
+
+	return user, handover, nil
+}
+
+// SetAuthorOnly keeps the user from being picked as a reviewer until the given
+// time, or until it is cleared when until is nil. The user stays active, so
+// they still author PRs and show up in teams and stats.
+func (s *UserService) SetAuthorOnly(ctx context.Context, userID string, authorOnly bool, until *time.Time) (models.User, error) {
+	const op = "service.user.SetAuthorOnly"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("userID", userID),
+		slog.Bool("authorOnly", authorOnly),
+	)
+
+	id, err := models.ParseUserID(userID)
+	if err != nil {
+		log.Error("invalid user ID format", sl.Err(err))
+		return models.User{}, err
+	}
+
+	if authorOnly && until != nil {
+		if !until.After(time.Now()) {
+			log.Warn("author-only period must end in the future")
+			return models.User{}, apperrors.ErrInvalidAuthorOnly
+		}
+		utc := until.UTC()
+		until = &utc
+	}
+
+	user, err := s.userProvider.SetAuthorOnly(authorOnly, until, id.Int())
+	if err != nil {
+		if errors.Is(err, apperrors.ErrUserNotFound) {
+			log.Warn("user not found")
+			return models.User{}, apperrors.ErrUserNotFound
+		}
+		log.Error("failed to set author-only", sl.Err(err))
+		return models.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	recordAudit(ctx, s.publisher, authorOnlyAuditEvent(user))
+
+	log.Info("author-only status changed")
 	return user, nil
 }
 
+func authorOnlyAuditEvent(user models.User) models.AuditEvent {
+	event := models.AuditEvent{
+		TeamName:  user.TeamName,
+		Action:    models.AuditMemberReviewing,
+		SubjectID: user.UserID,
+	}
+	if user.AuthorOnly {
+		event.Action = models.AuditMemberAuthorOnly
+		if user.AuthorOnlyUntil != nil {
+			event.Details = "until " + user.AuthorOnlyUntil.Format(time.RFC3339)
+		}
+	}
+	return event
+}
+
+func (s *UserService) GetUserSummary(ctx context.Context, userID string) (*models.UserSummary, error) {
+	const op = "service.user.GetUserSummary"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("userID", userID),
+	)
+
+	id, err := models.ParseUserID(userID)
+	if err != nil {
+		log.Error("invalid user ID format", sl.Err(err))
+		return nil, err
+	}
+
+	summary, err := s.userProvider.GetUserSummary(id.Int())
+	if err != nil {
+		if errors.Is(err, apperrors.ErrUserNotFound) {
+			log.Warn("user not found")
+			return nil, apperrors.ErrUserNotFound
+		}
+		log.Error("failed to get user", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &summary, nil
+}
+
+// GetUserSettings returns the user's settings and their entity tag.
+func (s *UserService) GetUserSettings(ctx context.Context, userID string) (*models.UserSettings, string, error) {
+	const op = "service.user.GetUserSettings"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("userID", userID),
+	)
+
+	id, err := models.ParseUserID(userID)
+	if err != nil {
+		log.Error("invalid user ID format", sl.Err(err))
+		return nil, "", err
+	}
+
+	user, err := s.userProvider.GetUser(id.Int())
+	if err != nil {
+		if errors.Is(err, apperrors.ErrUserNotFound) {
+			log.Warn("user not found")
+			return nil, "", apperrors.ErrUserNotFound
+		}
+		log.Error("failed to get user", sl.Err(err))
+		return nil, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	settings := &models.UserSettings{
+		Username:            user.Username,
+		IsActive:            user.IsActive,
+		MaxDailyAssignments: user.MaxDailyAssignments,
+		DisplayName:         user.DisplayName,
+		Email:               user.Email,
+		AvatarURL:           user.AvatarURL,
+		Locale:              user.Locale,
+	}
+
+	etag, err := mergepatch.ETag(settings)
+	if err != nil {
+		log.Error("failed to compute entity tag", sl.Err(err))
+		return nil, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return settings, etag, nil
+}
+
+// PatchUserSettings applies a JSON merge patch to the user's settings. A
+// non-empty ifMatch must match the current entity tag.
+func (s *UserService) PatchUserSettings(ctx context.Context, userID string, patch []byte, ifMatch string) (*models.UserSettings, string, error) {
+	const op = "service.user.PatchUserSettings"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("userID", userID),
+	)
+
+	log.Info("attempting to patch user settings")
+
+	current, etag, err := s.GetUserSettings(ctx, userID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if !mergepatch.Matches(ifMatch, etag) {
+		log.Warn("user settings changed since they were read", slog.String("if_match", ifMatch))
+		return nil, "", apperrors.ErrPreconditionFailed
+	}
+
+	patched := *current
+	if err := mergepatch.Apply(&patched, patch); err != nil {
+		log.Warn("invalid merge patch", sl.Err(err))
+		return nil, "", err
+	}
+
+	patched.Username = strings.TrimSpace(patched.Username)
+	if patched.Username == "" {
+		log.Error("username is required")
+		return nil, "", apperrors.ErrUsernameRequired
+	}
+
+	if patched.MaxDailyAssignments < 0 || patched.MaxDailyAssignments > maxDailyAssignments {
+		log.Warn("invalid daily assignment cap", slog.Int("max_daily_assignments", patched.MaxDailyAssignments))
+		return nil, "", apperrors.ErrInvalidDailyCap
+	}
+
+	profile, err := normalizeProfile(patched.Profile())
+	if err != nil {
+		log.Warn("invalid user profile", sl.Err(err))
+		return nil, "", err
+	}
+	patched.DisplayName = profile.DisplayName
+	patched.Email = profile.Email
+	patched.AvatarURL = profile.AvatarURL
+	patched.Locale = profile.Locale
+
+	if patched == *current {
+		return current, etag, nil
+	}
+
+	id, _ := models.ParseUserID(userID)
+
+	// Renaming an anonymized user or giving it a profile would put a real
+	// identity back on its history.
+	if patched.Username != current.Username || patched.Profile() != current.Profile() {
+		anonymized, err := s.userProvider.IsAnonymized(id.Int())
+		if err != nil {
+			log.Error("failed to check anonymization", sl.Err(err))
+			return nil, "", fmt.Errorf("%s: %w", op, err)
+		}
+		if anonymized {
+			log.Warn("anonymized user cannot be renamed or given a profile")
+			return nil, "", apperrors.ErrUserAnonymized
+		}
+	}
+
+	user, err := s.userProvider.UpdateUserSettings(id.Int(), *current, patched)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrPreconditionFailed) {
+			log.Warn("user settings changed concurrently")
+			return nil, "", apperrors.ErrPreconditionFailed
+		}
+		log.Error("failed to update user settings", sl.Err(err))
+		return nil, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if patched.IsActive != current.IsActive {
+		recordAudit(ctx, s.publisher, activityAuditEvent(user, patched.IsActive))
+	}
+
+	etag, err = mergepatch.ETag(patched)
+	if err != nil {
+		log.Error("failed to compute entity tag", sl.Err(err))
+		return nil, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("user settings patched successfully")
+
+	return &patched, etag, nil
+}
+
 func (s *UserService) GetUserReview(ctx context.Context, userID string) ([]models.PullRequestShort, error) {
 	const op = "service.user.GetUserReviews"
 
@@ -77,13 +364,13 @@ func (s *UserService) GetUserReview(ctx context.Context, userID string) ([]model
 
 	log.Info("attempting to get user reviews")
 
-	userIDInt, err := strconv.Atoi(userID[1:])
+	id, err := models.ParseUserID(userID)
 	if err != nil {
 		log.Error("invalid user ID format", sl.Err(err))
-		return nil, apperrors.ErrInvalidUserID
+		return nil, err
 	}
 
-	prs, err := s.userProvider.GetReview(userIDInt)
+	prs, err := s.userProvider.GetReview(id.Int())
 	if err != nil {
 		log.Error("failed to get reviews", sl.Err(err))
 		return nil, fmt.Errorf("%s: %w", op, err)
@@ -94,3 +381,238 @@ func (s *UserService) GetUserReview(ctx context.Context, userID string) ([]model
 
 	return prs, nil
 }
+
+// WaitUserReview holds until the user's review set changes, wait elapses or
+// ctx is done, and then returns the current set. changed reports whether a
+// change ended the wait.
+func (s *UserService) WaitUserReview(ctx context.Context, userID string, wait time.Duration) ([]models.PullRequestShort, bool, error) {
+	const op = "service.user.WaitUserReview"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("userID", userID),
+		slog.Duration("wait", wait),
+	)
+
+	if wait <= 0 || wait > MaxReviewWait {
+		log.Warn("invalid wait duration")
+		return nil, false, apperrors.ErrInvalidWait
+	}
+
+	if _, err := models.ParseUserID(userID); err != nil {
+		log.Error("invalid user ID format", sl.Err(err))
+		return nil, false, err
+	}
+
+	changes, release := s.watcher.Watch(userID)
+	defer release()
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	changed := false
+	select {
+	case <-changes:
+		changed = true
+	case <-timer.C:
+	case <-ctx.Done():
+		log.Info("client gave up waiting for review changes")
+		return nil, false, ctx.Err()
+	}
+
+	prs, err := s.GetUserReview(ctx, userID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return prs, changed, nil
+}
+
+// SetUsersActiveStatus updates all well-formed user IDs in a single statement
+// and reports malformed or unknown IDs individually instead of failing the
+// whole batch.
+func (s *UserService) SetUsersActiveStatus(ctx context.Context, isActive bool, userIDs []string) (*models.BatchActiveResult, error) {
+	const op = "service.user.SetUsersActiveStatus"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int("batch_size", len(userIDs)),
+		slog.Bool("isActive", isActive),
+	)
+
+	log.Info("attempting to change active status for users batch")
+
+	if len(userIDs) == 0 {
+		log.Error("user ids are required")
+		return nil, apperrors.ErrUserIDsRequired
+	}
+
+	if len(userIDs) > MaxUsersPerBatch {
+		log.Error("batch is too large")
+		return nil, apperrors.ErrBatchTooLarge
+	}
+
+	result := &models.BatchActiveResult{
+		Updated: make([]models.User, 0, len(userIDs)),
+		Failed:  make([]models.BatchFailure, 0),
+	}
+
+	ids := make([]int, 0, len(userIDs))
+	seen := make(map[string]bool, len(userIDs))
+	for _, userID := range userIDs {
+		if seen[userID] {
+			continue
+		}
+		seen[userID] = true
+
+		id, err := models.ParseUserID(userID)
+		if err != nil {
+			result.Failed = append(result.Failed, models.BatchFailure{
+				UserID: userID, Code: "INVALID_USER_ID", Message: "invalid user_id format",
+			})
+			continue
+		}
+		ids = append(ids, id.Int())
+	}
+
+	if len(ids) > 0 {
+		updated, err := s.userProvider.SetIsActiveBatch(isActive, ids)
+		if err != nil {
+			log.Error("failed to set users active status", sl.Err(err))
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		result.Updated = updated
+	}
+
+	for _, user := range result.Updated {
+		recordAudit(ctx, s.publisher, activityAuditEvent(user, isActive))
+	}
+
+	found := make(map[string]bool, len(result.Updated))
+	for _, user := range result.Updated {
+		found[user.UserID] = true
+	}
+	for _, id := range ids {
+		userID := models.UserID(id).String()
+		if !found[userID] {
+			result.Failed = append(result.Failed, models.BatchFailure{
+				UserID: userID, Code: "NOT_FOUND", Message: "user not found",
+			})
+		}
+	}
+
+	log.Info("users batch status changed",
+		slog.Int("updated", len(result.Updated)),
+		slog.Int("failed", len(result.Failed)))
+
+	return result, nil
+}
+
+func activityAuditEvent(user models.User, isActive bool) models.AuditEvent {
+	action := models.AuditMemberActivated
+	if !isActive {
+		action = models.AuditMemberDeactivated
+	}
+	return models.AuditEvent{
+		TeamName:  user.TeamName,
+		Action:    action,
+		SubjectID: user.UserID,
+	}
+}
+
+// ReorderQueue sets the user's own order of their open reviews: the listed
+// PRs lead the queue in the given order, an empty list restores the default
+// order. It returns the reordered queue.
+func (s *UserService) ReorderQueue(ctx context.Context, userID string, prIDs []string) ([]models.ReviewAssignment, error) {
+	const op = "service.user.ReorderQueue"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("userID", userID),
+		slog.Int("pullRequestCount", len(prIDs)),
+	)
+
+	id, err := models.ParseUserID(userID)
+	if err != nil {
+		log.Error("invalid user ID format", sl.Err(err))
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(prIDs))
+	for _, prID := range prIDs {
+		if prID == "" || seen[prID] {
+			log.Warn("empty or repeated pull request ID in queue order", slog.String("pr_id", prID))
+			return nil, apperrors.ErrInvalidQueueOrder
+		}
+		seen[prID] = true
+	}
+
+	if err := s.userProvider.SetQueueOrder(id.Int(), prIDs); err != nil {
+		if errors.Is(err, apperrors.ErrReviewerNotAssigned) {
+			log.Warn("queue order lists a PR outside the user's open reviews")
+			return nil, apperrors.ErrReviewerNotAssigned
+		}
+		log.Error("failed to set queue order", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("review queue reordered")
+	return s.GetMyReviews(ctx, userID)
+}
+
+// GetMyReviews returns the user's open assignments enriched with SLA data.
+// Reviews the user ordered come first in their order; the rest follow by
+// urgency: overdue first, then by priority, then by due date.
+func (s *UserService) GetMyReviews(ctx context.Context, userID string) ([]models.ReviewAssignment, error) {
+	const op = "service.user.GetMyReviews"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("userID", userID),
+	)
+
+	log.Info("attempting to get user review queue")
+
+	id, err := models.ParseUserID(userID)
+	if err != nil {
+		log.Error("invalid user ID format", sl.Err(err))
+		return nil, err
+	}
+
+	reviews, err := s.userProvider.GetOpenReviews(id.Int())
+	if err != nil {
+		log.Error("failed to get open reviews", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	now := time.Now()
+	for i := range reviews {
+		reviews[i].DueAt = reviews[i].CreatedAt.Add(s.reviewSLA)
+		reviews[i].Overdue = now.After(reviews[i].DueAt)
+		if s.prLinkTemplate != "" {
+			reviews[i].Link = strings.ReplaceAll(s.prLinkTemplate, "{pull_request_id}", reviews[i].PullRequestId)
+		}
+	}
+
+	sort.SliceStable(reviews, func(i, j int) bool {
+		a, b := reviews[i], reviews[j]
+		if (a.QueuePosition != nil) != (b.QueuePosition != nil) {
+			return a.QueuePosition != nil
+		}
+		if a.QueuePosition != nil && *a.QueuePosition != *b.QueuePosition {
+			return *a.QueuePosition < *b.QueuePosition
+		}
+		if a.Overdue != b.Overdue {
+			return a.Overdue
+		}
+		if models.PriorityRank(a.Priority) != models.PriorityRank(b.Priority) {
+			return models.PriorityRank(a.Priority) > models.PriorityRank(b.Priority)
+		}
+		return a.DueAt.Before(b.DueAt)
+	})
+
+	log.Info("user review queue retrieved successfully",
+		slog.Int("pullRequestCount", len(reviews)))
+
+	return reviews, nil
+}
@@ -0,0 +1,756 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"pull-request-assigner/internal/apperrors"
+	"pull-request-assigner/internal/domain/events"
+	"pull-request-assigner/internal/domain/models"
+	"pull-request-assigner/internal/lib/logger/sl"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WebhookEvents are the events a team webhook can subscribe to, under the
+// names receivers see in deliveries.
+var WebhookEvents = []string{
+	"pr.created",
+	"pr.merged",
+	"pr.reviewers_released",
+	"reviewer.assigned",
+	"reviewer.reassigned",
+	"reviewer.delegated",
+	"reviewer.unassigned",
+}
+
+// webhookEventNames maps the bus events delivered to webhooks onto their
+// WebhookEvents names. Subscriptions still accept the bus names.
+var webhookEventNames = map[string]string{
+	events.NamePullRequestCreated: "pr.created",
+	events.NamePullRequestMerged:  "pr.merged",
+	events.NameReviewersReleased:  "pr.reviewers_released",
+	events.NameReviewerAssigned:   "reviewer.assigned",
+	events.NameReviewerReassigned: "reviewer.reassigned",
+	events.NameReviewerDelegated:  "reviewer.delegated",
+	events.NameReviewerUnassigned: "reviewer.unassigned",
+}
+
+const (
+	webhookQueueSize = 256
+
+	// Pending retries are polled at most webhookRetryPoll apart, in batches
+	// of webhookRetryBatch. A claimed retry is hidden from other instances
+	// for webhookRetryLease.
+	webhookRetryPoll  = time.Second
+	webhookRetryBatch = 50
+	webhookRetryLease = time.Minute
+
+	// webhookFailuresLimit caps ListFailedDeliveries.
+	webhookFailuresLimit = 100
+)
+
+var (
+	webhookDeliveries = expvar.NewInt("webhook_deliveries_total")
+	webhookFailures   = expvar.NewInt("webhook_failures_total")
+	webhookDropped    = expvar.NewInt("webhook_dropped_total")
+	webhookRetries    = expvar.NewInt("webhook_retries_total")
+)
+
+// WebhookRetry bounds redelivery of failed webhook posts. A post is tried at
+// most MaxAttempts times, waiting Backoff before the second attempt and
+// doubling the wait after each further failure.
+type WebhookRetry struct {
+	MaxAttempts int
+	Backoff     time.Duration
+}
+
+// webhookAttempt is one post of an encoded delivery to a webhook. A zero id
+// marks the first attempt, which is not stored yet.
+type webhookAttempt struct {
+	id      int64
+	hook    models.TeamWebhook
+	event   string
+	prID    string
+	body    []byte
+	userIDs []int
+	attempt int
+}
+
+type WebhookService struct {
+	log         *slog.Logger
+	webhookRepo WebhookStore
+	client      *http.Client
+	queue       chan events.Event
+	retry       WebhookRetry
+	health      *IntegrationTracker
+	templates   *NotificationTemplates
+	reviewSLA   time.Duration
+	// failedRetention is how long failed deliveries are kept for admins.
+	failedRetention time.Duration
+}
+
+type WebhookStore interface {
+	CreateWebhook(hook models.TeamWebhook) (*models.TeamWebhook, error)
+	GetWebhook(id int64) (*models.TeamWebhook, error)
+	ListWebhooks(teamName string) ([]models.TeamWebhook, error)
+	UpdateWebhook(hook models.TeamWebhook) error
+	DeleteWebhook(id int64) error
+	GetPRWebhooks(prID string) ([]models.TeamWebhook, error)
+	RecordDelivery(id int64, prID string, event string, status int, deliveryErr string) error
+	SaveDelivery(delivery models.StoredWebhookDelivery) (int64, error)
+	DeleteDelivery(id int64) error
+	ClaimDueDeliveries(limit int, lease time.Duration) ([]models.StoredWebhookDelivery, error)
+	ListFailedDeliveries(teamName string, limit int) ([]models.StoredWebhookDelivery, error)
+	DeleteFailedDeliveriesOlderThan(age time.Duration) (int64, error)
+	GetUsers(userIDs []int) ([]models.User, error)
+	GetNotificationData(prID string) (*models.NotificationTemplateData, error)
+}
+
+func NewWebhookService(
+	log *slog.Logger,
+	webhookRepo WebhookStore,
+	timeout time.Duration,
+	retry WebhookRetry,
+	health *IntegrationTracker,
+	templates *NotificationTemplates,
+	reviewSLA time.Duration,
+	failedRetention time.Duration) *WebhookService {
+	return &WebhookService{
+		log:             log,
+		webhookRepo:     webhookRepo,
+		client:          &http.Client{Timeout: timeout},
+		queue:           make(chan events.Event, webhookQueueSize),
+		retry:           retry,
+		health:          health,
+		templates:       templates,
+		reviewSLA:       reviewSLA,
+		failedRetention: failedRetention,
+	}
+}
+
+func (s *WebhookService) CreateWebhook(ctx context.Context, teamName string, callbackURL string, secret string, eventNames []string) (*models.TeamWebhook, error) {
+	const op = "service.webhook.CreateWebhook"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("team_name", teamName),
+	)
+
+	log.Info("attempting to create team webhook")
+
+	if teamName == "" {
+		log.Error("team name is required")
+		return nil, apperrors.ErrTeamNameRequired
+	}
+
+	hook := models.TeamWebhook{
+		TeamName: teamName,
+		URL:      strings.TrimSpace(callbackURL),
+		Secret:   secret,
+		Events:   models.MergeTags(nil, webhookSubscriptions(eventNames)),
+		IsActive: true,
+	}
+
+	if err := validateWebhook(hook); err != nil {
+		log.Error("invalid team webhook", sl.Err(err))
+		return nil, err
+	}
+
+	created, err := s.webhookRepo.CreateWebhook(hook)
+	if err != nil {
+		switch {
+		case errors.Is(err, apperrors.ErrWebhookExists):
+			log.Warn("team webhook already exists")
+			return nil, apperrors.ErrWebhookExists
+		case errors.Is(err, apperrors.ErrTeamNotFound):
+			log.Warn("team not found")
+			return nil, apperrors.ErrTeamNotFound
+		}
+		log.Error("failed to create team webhook", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("team webhook created", slog.Int64("webhook_id", created.ID))
+	return created, nil
+}
+
+func (s *WebhookService) ListWebhooks(ctx context.Context, teamName string) ([]models.TeamWebhook, error) {
+	const op = "service.webhook.ListWebhooks"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("team_name", teamName),
+	)
+
+	if teamName == "" {
+		log.Error("team name is required")
+		return nil, apperrors.ErrTeamNameRequired
+	}
+
+	hooks, err := s.webhookRepo.ListWebhooks(teamName)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrTeamNotFound) {
+			log.Warn("team not found")
+			return nil, apperrors.ErrTeamNotFound
+		}
+		log.Error("failed to list team webhooks", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return hooks, nil
+}
+
+func (s *WebhookService) UpdateWebhook(ctx context.Context, id int64, update models.TeamWebhookUpdate) (*models.TeamWebhook, error) {
+	const op = "service.webhook.UpdateWebhook"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("webhook_id", id),
+	)
+
+	log.Info("attempting to update team webhook")
+
+	hook, err := s.webhookRepo.GetWebhook(id)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrWebhookNotFound) {
+			log.Warn("team webhook not found")
+			return nil, apperrors.ErrWebhookNotFound
+		}
+		log.Error("failed to get team webhook", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if update.URL != nil {
+		hook.URL = strings.TrimSpace(*update.URL)
+	}
+
+	if update.Secret != nil {
+		hook.Secret = *update.Secret
+	}
+
+	if update.Events != nil {
+		hook.Events = models.MergeTags(nil, webhookSubscriptions(*update.Events))
+	}
+
+	if update.IsActive != nil {
+		hook.IsActive = *update.IsActive
+	}
+
+	if err := validateWebhook(*hook); err != nil {
+		log.Error("invalid team webhook", sl.Err(err))
+		return nil, err
+	}
+
+	if err := s.webhookRepo.UpdateWebhook(*hook); err != nil {
+		switch {
+		case errors.Is(err, apperrors.ErrWebhookExists):
+			log.Warn("team webhook already exists")
+			return nil, apperrors.ErrWebhookExists
+		case errors.Is(err, apperrors.ErrWebhookNotFound):
+			log.Warn("team webhook not found")
+			return nil, apperrors.ErrWebhookNotFound
+		}
+		log.Error("failed to update team webhook", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	updated, err := s.webhookRepo.GetWebhook(id)
+	if err != nil {
+		log.Error("failed to get updated team webhook", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("team webhook updated")
+	return updated, nil
+}
+
+func (s *WebhookService) DeleteWebhook(ctx context.Context, id int64) error {
+	const op = "service.webhook.DeleteWebhook"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("webhook_id", id),
+	)
+
+	if err := s.webhookRepo.DeleteWebhook(id); err != nil {
+		if errors.Is(err, apperrors.ErrWebhookNotFound) {
+			log.Warn("team webhook not found")
+			return apperrors.ErrWebhookNotFound
+		}
+		log.Error("failed to delete team webhook", sl.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("team webhook deleted")
+	return nil
+}
+
+// ListFailedDeliveries returns the latest deliveries that were rejected or
+// ran out of attempts, of one team or of every team when teamName is empty.
+func (s *WebhookService) ListFailedDeliveries(ctx context.Context, teamName string) ([]models.StoredWebhookDelivery, error) {
+	const op = "service.webhook.ListFailedDeliveries"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("team_name", teamName),
+	)
+
+	deliveries, err := s.webhookRepo.ListFailedDeliveries(teamName, webhookFailuresLimit)
+	if err != nil {
+		log.Error("failed to list failed webhook deliveries", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return deliveries, nil
+}
+
+// PurgeFailedDeliveries drops failed deliveries older than the retention.
+// Their bodies carry user profiles, so they are not kept forever.
+func (s *WebhookService) PurgeFailedDeliveries(ctx context.Context) error {
+	const op = "service.webhook.PurgeFailedDeliveries"
+
+	log := s.log.With(slog.String("op", op))
+
+	deleted, err := s.webhookRepo.DeleteFailedDeliveriesOlderThan(s.failedRetention)
+	if err != nil {
+		log.Error("failed to purge failed webhook deliveries", sl.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("failed webhook deliveries purged", slog.Int64("deleted", deleted))
+
+	return nil
+}
+
+// Handle is the bus subscriber queueing assignment events for delivery. It
+// never blocks the publisher: with the queue full the event is dropped.
+func (s *WebhookService) Handle(_ context.Context, event events.Event) {
+	if _, ok := webhookEventNames[event.Name()]; !ok {
+		return
+	}
+
+	select {
+	case s.queue <- event:
+	default:
+		webhookDropped.Add(1)
+		s.log.Warn("webhook queue is full, event dropped", slog.String("event", event.Name()))
+	}
+}
+
+// Run delivers queued events and the retries due in webhook_deliveries until
+// ctx is done.
+func (s *WebhookService) Run(ctx context.Context) {
+	poll := webhookRetryPoll
+	if s.retry.Backoff > 0 && s.retry.Backoff < poll {
+		poll = s.retry.Backoff
+	}
+
+	ticker := time.NewTicker(poll)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event := <-s.queue:
+			s.deliver(ctx, event)
+		case <-ticker.C:
+			s.redeliverDue(ctx)
+		}
+	}
+}
+
+// deliver posts the event to every active webhook of the PR author's team
+// subscribed to it. Each attempt is recorded on the webhook; failed ones are
+// retried as WebhookRetry allows.
+func (s *WebhookService) deliver(ctx context.Context, event events.Event) {
+	const op = "service.webhook.deliver"
+
+	prID, data := webhookPayload(event)
+	eventName := webhookEventNames[event.Name()]
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("event", eventName),
+		slog.String("pr_id", prID),
+	)
+
+	hooks, err := s.webhookRepo.GetPRWebhooks(prID)
+	if err != nil {
+		log.Error("failed to get team webhooks", sl.Err(err))
+		return
+	}
+
+	if len(hooks) == 0 {
+		return
+	}
+
+	userIDs := payloadUserIDs(data)
+	users := s.payloadUsers(log, userIDs)
+	text := s.renderText(log, event.Name(), prID, data, users)
+
+	for _, hook := range hooks {
+		if len(hook.Events) > 0 && !slices.Contains(hook.Events, eventName) {
+			continue
+		}
+
+		body, err := json.Marshal(models.WebhookDelivery{
+			Event:         eventName,
+			TeamName:      hook.TeamName,
+			PullRequestID: prID,
+			Data:          data,
+			Users:         users,
+			Text:          text,
+			SentAt:        time.Now(),
+		})
+		if err != nil {
+			log.Error("failed to encode webhook delivery", sl.Err(err))
+			return
+		}
+
+		s.attempt(ctx, log, webhookAttempt{
+			hook:    hook,
+			event:   eventName,
+			prID:    prID,
+			body:    body,
+			userIDs: userIDs,
+			attempt: 1,
+		})
+	}
+}
+
+// redeliverDue claims the stored retries that are due and attempts each.
+func (s *WebhookService) redeliverDue(ctx context.Context) {
+	const op = "service.webhook.redeliverDue"
+
+	deliveries, err := s.webhookRepo.ClaimDueDeliveries(webhookRetryBatch, webhookRetryLease)
+	if err != nil {
+		s.log.Error("failed to claim webhook retries", slog.String("op", op), sl.Err(err))
+		return
+	}
+
+	for _, delivery := range deliveries {
+		if ctx.Err() != nil {
+			return
+		}
+		s.redeliver(ctx, delivery)
+	}
+}
+
+// redeliver repeats a failed attempt with the current state of the webhook,
+// so a webhook deactivated meanwhile is not called again. Deleting the
+// webhook deletes its stored deliveries.
+func (s *WebhookService) redeliver(ctx context.Context, delivery models.StoredWebhookDelivery) {
+	const op = "service.webhook.redeliver"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("event", delivery.Event),
+		slog.String("pr_id", delivery.PullRequestID),
+		slog.Int64("webhook_id", delivery.WebhookID),
+		slog.Int("attempt", delivery.Attempt),
+	)
+
+	hook, err := s.webhookRepo.GetWebhook(delivery.WebhookID)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrWebhookNotFound) {
+			log.Info("webhook deleted, retry dropped")
+			return
+		}
+		log.Error("failed to get team webhook", sl.Err(err))
+		return
+	}
+
+	if !hook.IsActive {
+		log.Info("webhook deactivated, retry dropped")
+		if err := s.webhookRepo.DeleteDelivery(delivery.ID); err != nil {
+			log.Error("failed to delete webhook retry", sl.Err(err))
+		}
+		return
+	}
+
+	webhookRetries.Add(1)
+	s.attempt(ctx, log, webhookAttempt{
+		id:      delivery.ID,
+		hook:    *hook,
+		event:   delivery.Event,
+		prID:    delivery.PullRequestID,
+		body:    delivery.Body,
+		attempt: delivery.Attempt,
+	})
+}
+
+// attempt posts the delivery once and records the outcome. A failure worth
+// repeating is stored as pending with the time of the next attempt while
+// attempts remain; any other failure is stored as failed, for admins to
+// list. A stored delivery that finally succeeds is deleted.
+func (s *WebhookService) attempt(ctx context.Context, log *slog.Logger, a webhookAttempt) {
+	status, err := s.post(ctx, a.hook, a.event, a.attempt, a.body)
+	s.health.Observe(err)
+
+	webhookDeliveries.Add(1)
+	deliveryErr := ""
+	if err != nil {
+		webhookFailures.Add(1)
+		deliveryErr = err.Error()
+		log.Warn("webhook delivery failed",
+			slog.Int64("webhook_id", a.hook.ID),
+			slog.Int("attempt", a.attempt),
+			slog.Int("status", status),
+			sl.Err(err))
+	}
+
+	if err := s.webhookRepo.RecordDelivery(a.hook.ID, a.prID, a.event, status, deliveryErr); err != nil {
+		log.Error("failed to record webhook delivery", slog.Int64("webhook_id", a.hook.ID), sl.Err(err))
+	}
+
+	if err == nil {
+		if a.id != 0 {
+			if err := s.webhookRepo.DeleteDelivery(a.id); err != nil {
+				log.Error("failed to delete delivered webhook retry", slog.Int64("webhook_id", a.hook.ID), sl.Err(err))
+			}
+		}
+		return
+	}
+
+	delivery := models.StoredWebhookDelivery{
+		ID:            a.id,
+		WebhookID:     a.hook.ID,
+		Event:         a.event,
+		PullRequestID: a.prID,
+		Body:          a.body,
+		UserIDs:       a.userIDs,
+		Attempt:       a.attempt,
+		Status:        models.WebhookDeliveryFailed,
+		LastError:     &deliveryErr,
+	}
+	if status != 0 {
+		delivery.LastStatus = &status
+	}
+
+	if retryableWebhookStatus(status) && a.attempt < s.retry.MaxAttempts {
+		nextAttemptAt := time.Now().Add(s.retry.Backoff << (a.attempt - 1))
+		delivery.Status = models.WebhookDeliveryPending
+		delivery.Attempt = a.attempt + 1
+		delivery.NextAttemptAt = &nextAttemptAt
+	}
+
+	if _, err := s.webhookRepo.SaveDelivery(delivery); err != nil {
+		if errors.Is(err, apperrors.ErrWebhookNotFound) {
+			return
+		}
+		log.Error("failed to store webhook delivery", slog.Int64("webhook_id", a.hook.ID), sl.Err(err))
+	}
+}
+
+// payloadUserIDs returns the users the payload data refers to.
+func payloadUserIDs(data map[string]any) []int {
+	var ids []int
+	for _, value := range data {
+		var candidates []string
+		switch v := value.(type) {
+		case string:
+			candidates = []string{v}
+		case []string:
+			candidates = v
+		}
+		for _, candidate := range candidates {
+			if id, err := models.ParseUserID(candidate); err == nil && !slices.Contains(ids, id.Int()) {
+				ids = append(ids, id.Int())
+			}
+		}
+	}
+
+	return ids
+}
+
+// payloadUsers loads the users with the given IDs. A failed lookup only
+// leaves the profiles out of the delivery.
+func (s *WebhookService) payloadUsers(log *slog.Logger, ids []int) map[string]models.User {
+	users := make(map[string]models.User)
+
+	if len(ids) == 0 {
+		return users
+	}
+
+	found, err := s.webhookRepo.GetUsers(ids)
+	if err != nil {
+		log.Error("failed to get webhook payload users", sl.Err(err))
+		return users
+	}
+
+	for _, user := range found {
+		users[user.UserID] = user
+	}
+
+	return users
+}
+
+// renderText renders the webhook template of the event, if one is saved. A
+// template failing on real data only leaves the text out of the delivery.
+func (s *WebhookService) renderText(log *slog.Logger, eventName string, prID string, data map[string]any, users map[string]models.User) string {
+	tpl, err := s.templates.Get(eventName, models.NotificationChannelWebhook)
+	if err != nil {
+		log.Error("failed to load notification templates", sl.Err(err))
+		return ""
+	}
+
+	if tpl == nil {
+		return ""
+	}
+
+	vars, err := s.webhookRepo.GetNotificationData(prID)
+	if err != nil {
+		log.Error("failed to get notification template data", sl.Err(err))
+		return ""
+	}
+
+	vars.Event = webhookEventNames[eventName]
+	vars.DueAt = vars.CreatedAt.Add(s.reviewSLA)
+	vars.Data = data
+	vars.Users = users
+
+	var text strings.Builder
+	if err := tpl.Execute(&text, vars); err != nil {
+		log.Warn("failed to render notification template", sl.Err(err))
+		return ""
+	}
+
+	return text.String()
+}
+
+func (s *WebhookService) post(ctx context.Context, hook models.TeamWebhook, eventName string, attempt int, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Webhook-Event", eventName)
+	req.Header.Set("X-Webhook-Attempt", strconv.Itoa(attempt))
+	req.Header.Set("X-Webhook-Signature", SignWebhook(hook.Secret, body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// retryableWebhookStatus reports whether a failed post may succeed later: the
+// endpoint was unreachable (status 0), rate limited or failing on its side.
+// Other client errors are answered the same way on every attempt.
+func retryableWebhookStatus(status int) bool {
+	return status == 0 || status == http.StatusTooManyRequests || status >= http.StatusInternalServerError
+}
+
+// SignWebhook returns the X-Webhook-Signature value for body: the hex
+// HMAC-SHA256 under the webhook secret, prefixed with "sha256=".
+func SignWebhook(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func validateWebhook(hook models.TeamWebhook) error {
+	parsed, err := url.Parse(hook.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("%w: url must be an absolute http(s) URL", apperrors.ErrInvalidWebhook)
+	}
+
+	if hook.Secret == "" {
+		return fmt.Errorf("%w: secret is required", apperrors.ErrInvalidWebhook)
+	}
+
+	for _, name := range hook.Events {
+		if !slices.Contains(WebhookEvents, name) {
+			return fmt.Errorf("%w: unknown event %s", apperrors.ErrInvalidWebhook, name)
+		}
+	}
+
+	return nil
+}
+
+// webhookSubscriptions translates the bus names among the subscribed events
+// to their WebhookEvents names and leaves other names to validateWebhook.
+func webhookSubscriptions(eventNames []string) []string {
+	names := make([]string, 0, len(eventNames))
+	for _, name := range eventNames {
+		if documented, ok := webhookEventNames[strings.TrimSpace(name)]; ok {
+			name = documented
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+func webhookPayload(event events.Event) (string, map[string]any) {
+	switch e := event.(type) {
+	case events.PullRequestCreated:
+		return e.PullRequestID, map[string]any{
+			"author_id":  e.AuthorID,
+			"reviewers":  nonNilReviewers(e.Reviewers),
+			"created_at": e.CreatedAt,
+		}
+	case events.PullRequestMerged:
+		return e.PullRequestID, map[string]any{
+			"reviewers": nonNilReviewers(e.Reviewers),
+			"merged_by": e.MergedBy,
+			"merged_at": e.MergedAt,
+		}
+	case events.ReviewersReleased:
+		return e.PullRequestID, map[string]any{
+			"reviewers": nonNilReviewers(e.Reviewers),
+		}
+	case events.ReviewerAssigned:
+		return e.PullRequestID, map[string]any{
+			"reviewer_id":         e.ReviewerID,
+			"replace_reviewer_id": e.ReplaceReviewerID,
+			"actor_id":            e.ActorID,
+		}
+	case events.ReviewerReassigned:
+		return e.PullRequestID, map[string]any{
+			"old_reviewer_id": e.OldReviewerID,
+			"new_reviewer_id": e.NewReviewerID,
+			"reason":          e.Reason,
+			"note":            e.Note,
+		}
+	case events.ReviewerDelegated:
+		return e.PullRequestID, map[string]any{
+			"reviewer_id": e.ReviewerID,
+			"delegate_id": e.DelegateID,
+		}
+	case events.ReviewerUnassigned:
+		return e.PullRequestID, map[string]any{
+			"reviewer_id": e.ReviewerID,
+			"actor_id":    e.ActorID,
+		}
+	}
+
+	return "", map[string]any{}
+}
+
+func nonNilReviewers(reviewers []string) []string {
+	if reviewers == nil {
+		return []string{}
+	}
+	return reviewers
+}
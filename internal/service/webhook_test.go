@@ -0,0 +1,253 @@
+package service
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"pull-request-assigner/internal/apperrors"
+	"pull-request-assigner/internal/domain/events"
+	"pull-request-assigner/internal/domain/models"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// webhookStoreFake serves one webhook and keeps stored deliveries in memory.
+type webhookStoreFake struct {
+	WebhookStore
+
+	hook       models.TeamWebhook
+	deliveries map[int64]models.StoredWebhookDelivery
+	nextID     int64
+	purgedAge  time.Duration
+}
+
+func (f *webhookStoreFake) GetWebhook(id int64) (*models.TeamWebhook, error) {
+	if id != f.hook.ID {
+		return nil, apperrors.ErrWebhookNotFound
+	}
+	hook := f.hook
+	return &hook, nil
+}
+
+func (f *webhookStoreFake) GetPRWebhooks(prID string) ([]models.TeamWebhook, error) {
+	return []models.TeamWebhook{f.hook}, nil
+}
+
+func (f *webhookStoreFake) RecordDelivery(id int64, prID string, event string, status int, deliveryErr string) error {
+	return nil
+}
+
+func (f *webhookStoreFake) GetUsers(userIDs []int) ([]models.User, error) {
+	return nil, nil
+}
+
+func (f *webhookStoreFake) SaveDelivery(delivery models.StoredWebhookDelivery) (int64, error) {
+	if delivery.ID == 0 {
+		f.nextID++
+		delivery.ID = f.nextID
+	}
+	f.deliveries[delivery.ID] = delivery
+	return delivery.ID, nil
+}
+
+func (f *webhookStoreFake) DeleteDelivery(id int64) error {
+	delete(f.deliveries, id)
+	return nil
+}
+
+// DeleteFailedDeliveriesOlderThan ignores updated_at and only records the
+// age asked for.
+func (f *webhookStoreFake) DeleteFailedDeliveriesOlderThan(age time.Duration) (int64, error) {
+	f.purgedAge = age
+	var deleted int64
+	for id, delivery := range f.deliveries {
+		if delivery.Status == models.WebhookDeliveryFailed {
+			delete(f.deliveries, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// ClaimDueDeliveries ignores next_attempt_at, so tests need not wait for
+// the backoff.
+func (f *webhookStoreFake) ClaimDueDeliveries(limit int, lease time.Duration) ([]models.StoredWebhookDelivery, error) {
+	var due []models.StoredWebhookDelivery
+	for _, delivery := range f.deliveries {
+		if delivery.Status == models.WebhookDeliveryPending {
+			due = append(due, delivery)
+		}
+	}
+	return due, nil
+}
+
+// newWebhookTestService points the fake's webhook at a server answering
+// with statuses in turn, then 200. It returns the X-Webhook-Event header of
+// every post.
+func newWebhookTestService(t *testing.T, statuses ...int) (*WebhookService, *webhookStoreFake, func() []string) {
+	t.Helper()
+
+	var calls atomic.Int32
+	received := make(chan string, 16)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- r.Header.Get("X-Webhook-Event")
+		status := http.StatusOK
+		if n := int(calls.Add(1)); n <= len(statuses) {
+			status = statuses[n-1]
+		}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+
+	store := &webhookStoreFake{
+		hook:       models.TeamWebhook{ID: 7, TeamName: "Backend", URL: server.URL, Secret: "s", IsActive: true},
+		deliveries: make(map[int64]models.StoredWebhookDelivery),
+	}
+
+	s := NewWebhookService(slog.New(slog.NewTextHandler(io.Discard, nil)), store, time.Second,
+		WebhookRetry{MaxAttempts: 3, Backoff: time.Minute}, nil, nil, time.Hour, 24*time.Hour)
+
+	posted := func() []string {
+		var names []string
+		for {
+			select {
+			case name := <-received:
+				names = append(names, name)
+			default:
+				return names
+			}
+		}
+	}
+
+	return s, store, posted
+}
+
+func TestWebhookRetryIsStoredUntilDelivered(t *testing.T) {
+	ctx := context.Background()
+	s, store, posted := newWebhookTestService(t, http.StatusServiceUnavailable)
+
+	s.deliver(ctx, events.PullRequestCreated{PullRequestID: "pr-1", AuthorID: "u1"})
+
+	if got := posted(); !slices.Equal(got, []string{"pr.created"}) {
+		t.Fatalf("expected one pr.created post, got %v", got)
+	}
+	if len(store.deliveries) != 1 {
+		t.Fatalf("expected the failed post to be stored, got %d deliveries", len(store.deliveries))
+	}
+	stored := store.deliveries[1]
+	if stored.Status != models.WebhookDeliveryPending || stored.Attempt != 2 || stored.Event != "pr.created" {
+		t.Fatalf("expected a pending pr.created retry as attempt 2, got %+v", stored)
+	}
+	if !slices.Equal(stored.UserIDs, []int{1}) {
+		t.Fatalf("expected the retry to record the author u1, got %v", stored.UserIDs)
+	}
+	if stored.NextAttemptAt == nil || time.Until(*stored.NextAttemptAt) < 50*time.Second {
+		t.Fatalf("expected the retry after the backoff, got %v", stored.NextAttemptAt)
+	}
+
+	s.redeliverDue(ctx)
+
+	if got := posted(); len(got) != 1 {
+		t.Fatalf("expected one retry post, got %v", got)
+	}
+	if len(store.deliveries) != 0 {
+		t.Fatalf("expected the delivered retry to be deleted, got %+v", store.deliveries)
+	}
+}
+
+func TestWebhookFailuresAreKept(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("rejected", func(t *testing.T) {
+		s, store, posted := newWebhookTestService(t, http.StatusBadRequest)
+
+		s.deliver(ctx, events.PullRequestMerged{PullRequestID: "pr-1"})
+
+		if got := posted(); !slices.Equal(got, []string{"pr.merged"}) {
+			t.Fatalf("expected one pr.merged post, got %v", got)
+		}
+		stored := store.deliveries[1]
+		if stored.Status != models.WebhookDeliveryFailed || stored.Attempt != 1 || stored.LastStatus == nil || *stored.LastStatus != http.StatusBadRequest {
+			t.Fatalf("expected a failed delivery with status 400, got %+v", stored)
+		}
+
+		s.redeliverDue(ctx)
+		if got := posted(); len(got) != 0 {
+			t.Fatalf("a failed delivery must not be retried, got %v", got)
+		}
+	})
+
+	t.Run("exhausted", func(t *testing.T) {
+		s, store, posted := newWebhookTestService(t,
+			http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway)
+
+		s.deliver(ctx, events.ReviewerAssigned{PullRequestID: "pr-1", ReviewerID: "u2"})
+		s.redeliverDue(ctx)
+		s.redeliverDue(ctx)
+		s.redeliverDue(ctx)
+
+		if got := posted(); !slices.Equal(got, []string{"reviewer.assigned", "reviewer.assigned", "reviewer.assigned"}) {
+			t.Fatalf("expected 3 reviewer.assigned posts, got %v", got)
+		}
+		stored := store.deliveries[1]
+		if stored.Status != models.WebhookDeliveryFailed || stored.Attempt != 3 || stored.NextAttemptAt != nil {
+			t.Fatalf("expected a failed delivery after attempt 3, got %+v", stored)
+		}
+	})
+
+	t.Run("webhook deactivated", func(t *testing.T) {
+		s, store, posted := newWebhookTestService(t, http.StatusServiceUnavailable)
+
+		s.deliver(ctx, events.PullRequestCreated{PullRequestID: "pr-1"})
+		store.hook.IsActive = false
+		s.redeliverDue(ctx)
+
+		if got := posted(); len(got) != 1 {
+			t.Fatalf("expected no retry to a deactivated webhook, got %v", got)
+		}
+		if len(store.deliveries) != 0 {
+			t.Fatalf("expected the retry to be dropped, got %+v", store.deliveries)
+		}
+	})
+}
+
+func TestWebhookFailuresArePurged(t *testing.T) {
+	ctx := context.Background()
+	s, store, _ := newWebhookTestService(t, http.StatusBadRequest, http.StatusServiceUnavailable)
+
+	s.deliver(ctx, events.PullRequestMerged{PullRequestID: "pr-1"})
+	s.deliver(ctx, events.PullRequestMerged{PullRequestID: "pr-2"})
+
+	if err := s.PurgeFailedDeliveries(ctx); err != nil {
+		t.Fatalf("failed to purge: %v", err)
+	}
+	if store.purgedAge != 24*time.Hour {
+		t.Fatalf("expected the configured retention of 24h, got %v", store.purgedAge)
+	}
+	if len(store.deliveries) != 1 || store.deliveries[2].Status != models.WebhookDeliveryPending {
+		t.Fatalf("expected only the pending retry to stay, got %+v", store.deliveries)
+	}
+}
+
+func TestWebhookSubscriptionsUseDocumentedNames(t *testing.T) {
+	got := webhookSubscriptions([]string{events.NamePullRequestCreated, " review.reassigned ", "pr.merged", "pull_request.exploded"})
+	want := []string{"pr.created", "reviewer.reassigned", "pr.merged", "pull_request.exploded"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	for name, documented := range webhookEventNames {
+		if !slices.Contains(WebhookEvents, documented) {
+			t.Errorf("%s is delivered as %s, which is not in WebhookEvents", name, documented)
+		}
+	}
+
+	err := validateWebhook(models.TeamWebhook{URL: "https://example.com", Secret: "s", Events: want})
+	if err == nil {
+		t.Fatal("expected an unknown event to be rejected")
+	}
+}
@@ -0,0 +1,71 @@
+package postgresql
+
+import (
+	"context"
+	"log/slog"
+	"pull-request-assigner/internal/lib/logger/sl"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// listenerPing is how often an idle listener checks its connection, so a
+// silently dropped one is noticed and re-established.
+const listenerPing = time.Minute
+
+// Listen calls handle with the payload of every NOTIFY on channel until ctx
+// is done. The listener reconnects on its own; notifications sent while it
+// was not connected are lost, so handle is also called with an empty payload
+// once listening starts and after every reconnect.
+func Listen(ctx context.Context, dsn, channel string, log *slog.Logger, handle func(payload string)) {
+	const op = "storage.postgresql.Listen"
+
+	log = log.With(slog.String("op", op), slog.String("channel", channel))
+
+	listener := pq.NewListener(dsn, time.Second, time.Minute, func(event pq.ListenerEventType, err error) {
+		if err != nil {
+			log.Warn("notification listener connection problem", sl.Err(err))
+		}
+	})
+
+	go func() {
+		<-ctx.Done()
+		listener.Close()
+	}()
+
+	if err := listener.Listen(channel); err != nil {
+		if ctx.Err() == nil {
+			log.Error("failed to listen for notifications", sl.Err(err))
+		}
+		return
+	}
+	handle("")
+
+	ticker := time.NewTicker(listenerPing)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case notification, ok := <-listener.Notify:
+			if !ok {
+				return
+			}
+			if notification == nil {
+				handle("")
+				continue
+			}
+			handle(notification.Extra)
+		case <-ticker.C:
+			if err := listener.Ping(); err != nil {
+				log.Warn("notification listener ping failed", sl.Err(err))
+			}
+		}
+	}
+}
+
+// Listen is Listen on the storage's database.
+func (s *Storage) Listen(ctx context.Context, channel string, handle func(payload string)) {
+	Listen(ctx, s.dsn, channel, s.log, handle)
+}
@@ -1,34 +1,47 @@
 package postgresql
 
 import (
+	"database/sql"
+	"database/sql/driver"
 	"fmt"
 	"github.com/jmoiron/sqlx"
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 	"log"
+	"log/slog"
 	"pull-request-assigner/internal/config"
+	"pull-request-assigner/internal/lib/chaos"
 	"runtime/debug"
 )
 
 type Storage struct {
-	db *sqlx.DB
+	db  *sqlx.DB
+	dsn string
+	log *slog.Logger
 }
 
-func Init(cfg config.PostgresConfig) *Storage {
+func Init(cfg config.PostgresConfig, logger *slog.Logger) *Storage {
 	const op = "storage.postgresql.Init"
 
 	connStr := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
 		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.DbName, cfg.SslMode)
 
-	db, err := sqlx.Connect("postgres", connStr)
+	pgConnector, err := pq.NewConnector(connStr)
 	if err != nil {
 		panic(fmt.Sprintf("%s: failed to open db: %v", op, err))
 	}
 
-	if err = db.Ping(); err != nil {
+	var connector driver.Connector = pgConnector
+	if cfg.SlowQueryThreshold > 0 {
+		connector = &slowQueryConnector{dsn: connStr, threshold: cfg.SlowQueryThreshold, log: logger}
+	}
+
+	db := sqlx.NewDb(sql.OpenDB(chaos.Wrap(connector)), "postgres")
+
+	if err := db.Ping(); err != nil {
 		panic(fmt.Sprintf("%s: failed to ping db: %v", op, err))
 	}
 
-	return &Storage{db: db}
+	return &Storage{db: db, dsn: connStr, log: logger}
 }
 
 func (s *Storage) GetDB() *sqlx.DB {
@@ -0,0 +1,148 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql/driver"
+	"expvar"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// slowQueries counts statements that exceeded the configured threshold. It is
+// exposed on /debug/vars.
+var slowQueries = expvar.NewInt("db_slow_queries_total")
+
+// slowQueryConnector opens lib/pq connections wrapped with statement timing.
+type slowQueryConnector struct {
+	dsn       string
+	threshold time.Duration
+	log       *slog.Logger
+}
+
+func (c *slowQueryConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	connector, err := pq.NewConnector(c.dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &slowQueryConn{Conn: conn, threshold: c.threshold, log: c.log}, nil
+}
+
+func (c *slowQueryConnector) Driver() driver.Driver {
+	return pq.Driver{}
+}
+
+type slowQueryConn struct {
+	driver.Conn
+	threshold time.Duration
+	log       *slog.Logger
+}
+
+func (c *slowQueryConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+
+	started := time.Now()
+	rows, err := queryer.QueryContext(ctx, query, args)
+	c.observe(query, args, time.Since(started))
+
+	return rows, err
+}
+
+func (c *slowQueryConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+
+	started := time.Now()
+	result, err := execer.ExecContext(ctx, query, args)
+	c.observe(query, args, time.Since(started))
+
+	return result, err
+}
+
+func (c *slowQueryConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	if preparer, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		return preparer.PrepareContext(ctx, query)
+	}
+	return c.Conn.Prepare(query)
+}
+
+func (c *slowQueryConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if beginner, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return beginner.BeginTx(ctx, opts)
+	}
+	return c.Conn.Begin()
+}
+
+func (c *slowQueryConn) Ping(ctx context.Context) error {
+	if pinger, ok := c.Conn.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *slowQueryConn) ResetSession(ctx context.Context) error {
+	if resetter, ok := c.Conn.(driver.SessionResetter); ok {
+		return resetter.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *slowQueryConn) IsValid() bool {
+	if validator, ok := c.Conn.(driver.Validator); ok {
+		return validator.IsValid()
+	}
+	return true
+}
+
+func (c *slowQueryConn) observe(query string, args []driver.NamedValue, elapsed time.Duration) {
+	if elapsed < c.threshold {
+		return
+	}
+
+	slowQueries.Add(1)
+
+	c.log.Warn("slow query",
+		slog.Duration("duration", elapsed),
+		slog.Duration("threshold", c.threshold),
+		slog.String("query", strings.Join(strings.Fields(query), " ")),
+		slog.String("args", redactArgs(args)))
+}
+
+// redactArgs keeps numbers, booleans and timestamps, which help to reproduce a
+// plan, and hides everything else behind its type and length.
+func redactArgs(args []driver.NamedValue) string {
+	parts := make([]string, 0, len(args))
+
+	for _, arg := range args {
+		switch v := arg.Value.(type) {
+		case nil:
+			parts = append(parts, "NULL")
+		case int64, float64, bool:
+			parts = append(parts, fmt.Sprint(v))
+		case time.Time:
+			parts = append(parts, v.Format(time.RFC3339))
+		case string:
+			parts = append(parts, fmt.Sprintf("<string len=%d>", len(v)))
+		case []byte:
+			parts = append(parts, fmt.Sprintf("<bytes len=%d>", len(v)))
+		default:
+			parts = append(parts, fmt.Sprintf("<%T>", v))
+		}
+	}
+
+	return "[" + strings.Join(parts, ", ") + "]"
+}
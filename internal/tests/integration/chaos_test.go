@@ -0,0 +1,63 @@
+//go:build chaos
+
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"pull-request-assigner/internal/http/httpio"
+	"pull-request-assigner/internal/lib/chaos"
+	"testing"
+	"time"
+)
+
+func TestChaosRepoFailures(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	defer chaos.Configure(chaos.Config{})
+
+	chaos.Configure(chaos.Config{ErrorRate: 1})
+
+	resp := doGet(t, ts, "/team/get?team_name=Backend")
+	var data httpio.ErrorResponse
+	json.NewDecoder(resp.Body).Decode(&data)
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusInternalServerError || data.Error.Code != "INTERNAL_ERROR" {
+		t.Fatalf("expected 500 INTERNAL_ERROR on injected fault, got %d %s", resp.StatusCode, data.Error.Code)
+	}
+
+	resp = doPost(t, ts, "/pullRequest/create",
+		`{"pull_request_id": "PR-CHAOS", "pull_request_name": "Chaos", "author_id": "u1"}`)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusInternalServerError {
+		t.Fatalf("expected 500 on injected fault, got %d", resp.StatusCode)
+	}
+
+	chaos.Configure(chaos.Config{Latency: 50 * time.Millisecond})
+
+	started := time.Now()
+	resp = doGet(t, ts, "/team/get?team_name=Backend")
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200 with added latency only, got %d", resp.StatusCode)
+	}
+	if elapsed := time.Since(started); elapsed < 50*time.Millisecond {
+		t.Fatalf("expected at least 50ms of injected latency, got %s", elapsed)
+	}
+
+	chaos.Configure(chaos.Config{})
+
+	var count int
+	if err := ts.DB.Get(&count, `SELECT COUNT(*) FROM pull_requests WHERE pull_request_id = 'PR-CHAOS'`); err != nil || count != 0 {
+		t.Fatalf("expected the failed create to leave no PR, got %d (err %v)", count, err)
+	}
+}
@@ -1,12 +1,33 @@
 package integration
 
 import (
+	"archive/zip"
+	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"github.com/lib/pq"
 	"io"
+	"log/slog"
+	"math"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"pull-request-assigner/internal/http/httpio"
+	"pull-request-assigner/internal/http/v1/middleware"
+	"pull-request-assigner/internal/repo"
+	"pull-request-assigner/internal/service"
+	"pull-request-assigner/internal/storage/postgresql"
+	"pull-request-assigner/pkg/client"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func TestTeamCreate(t *testing.T) {
@@ -95,6 +116,40 @@ func TestTeamGet(t *testing.T) {
 	}
 }
 
+func TestErrorMessageLocalization(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	req, err := http.NewRequest(http.MethodGet, ts.Server.URL+"/team/get?team_name=Missing", nil)
+	if err != nil {
+		t.Fatalf("failed to build request: %v", err)
+	}
+	req.Header.Set("Accept-Language", "ru-RU,ru;q=0.9,en;q=0.8")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var data httpio.ErrorResponse
+
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if data.Error.Code != "NOT_FOUND" {
+		t.Fatalf("expected stable NOT_FOUND code, got %s", data.Error.Code)
+	}
+
+	if data.Error.Message != "ресурс не найден" {
+		t.Fatalf("expected russian message, got %q", data.Error.Message)
+	}
+}
+
 func TestTeamDeactivate(t *testing.T) {
 	ts, err := NewTestServer()
 	if err != nil {
@@ -128,7 +183,7 @@ func TestTeamDeactivate(t *testing.T) {
 	}
 }
 
-func TestPullRequestCreate(t *testing.T) {
+func TestTeamPolicyHistory(t *testing.T) {
 	ts, err := NewTestServer()
 	if err != nil {
 		t.Fatalf("Failed to create test server: %v", err)
@@ -139,46 +194,47 @@ func TestPullRequestCreate(t *testing.T) {
 		t.Fatalf("Failed to load fixtures: %v", err)
 	}
 
-	body := `{
-		"pull_request_id": "PR-1",
-		"pull_request_name": "Fix API",
-		"author_id": "u1"
-	}`
+	for _, body := range []string{
+		`{"team_name": "Backend", "min_reviewers": 2}`,
+		`{"team_name": "Backend", "min_reviewers": 2}`,
+		`{"team_name": "Backend", "hold_until_ci_green": true}`,
+	} {
+		resp := doPost(t, ts, "/team/update", body)
+		resp.Body.Close()
 
-	resp := doPost(t, ts, "/pullRequest/create", body)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("failed to update team: %d", resp.StatusCode)
+		}
+	}
+
+	resp := doGet(t, ts, "/team/policy/history?team_name=Backend")
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusCreated {
-		body, _ := io.ReadAll(resp.Body)
-		t.Fatalf("expected 201, got %d: %s", resp.StatusCode, string(body))
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
 	}
 
 	var data struct {
-		PR struct {
-			PullRequestID     string   `json:"pull_request_id"`
-			Status            string   `json:"status"`
-			AssignedReviewers []string `json:"assigned_reviewers"`
-		} `json:"pr"`
+		Versions []struct {
+			Version int      `json:"version"`
+			Changes []string `json:"changes"`
+		} `json:"versions"`
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
 		t.Fatalf("failed to decode response: %v", err)
 	}
 
-	if data.PR.PullRequestID != "PR-1" {
-		t.Fatalf("wrong PR id: %s", data.PR.PullRequestID)
-	}
-
-	if data.PR.Status != "OPEN" {
-		t.Fatalf("expected OPEN status, got %s", data.PR.Status)
+	if len(data.Versions) != 2 {
+		t.Fatalf("expected 2 policy versions (no-op update skipped), got %d", len(data.Versions))
 	}
 
-	if len(data.PR.AssignedReviewers) != 2 {
-		t.Fatalf("expected 2 reviewers, got %d", len(data.PR.AssignedReviewers))
+	if data.Versions[1].Version != 2 || len(data.Versions[1].Changes) != 1 {
+		t.Fatalf("unexpected second version: %+v", data.Versions[1])
 	}
 }
 
-func TestPullRequestMerge(t *testing.T) {
+func TestTeamChanges(t *testing.T) {
 	ts, err := NewTestServer()
 	if err != nil {
 		t.Fatalf("Failed to create test server: %v", err)
@@ -189,44 +245,51 @@ func TestPullRequestMerge(t *testing.T) {
 		t.Fatalf("Failed to load fixtures: %v", err)
 	}
 
-	resp := doPost(t, ts, "/pullRequest/create", `{
-		"pull_request_id": "PR-77",
-		"pull_request_name": "Refactor",
-		"author_id": "u1"
-	}`)
-	resp.Body.Close()
+	for _, call := range []struct{ path, body string }{
+		{"/users/setIsActive", `{"user_id": "u2", "is_active": false}`},
+		{"/team/update", `{"team_name": "Backend", "min_reviewers": 2}`},
+	} {
+		resp := doPostAs(t, ts, call.path, call.body, "u1")
+		resp.Body.Close()
 
-	if resp.StatusCode != http.StatusCreated {
-		body, _ := io.ReadAll(resp.Body)
-		t.Fatalf("failed to create PR: %d: %s", resp.StatusCode, string(body))
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("POST %s: expected 200, got %d", call.path, resp.StatusCode)
+		}
 	}
 
-	resp = doPost(t, ts, "/pullRequest/merge", `{
-		"pull_request_id": "PR-77"
-	}`)
+	resp := doGet(t, ts, "/team/changes?team_name=Backend")
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		t.Fatalf("expected 200, got %d: %s", resp.StatusCode, string(body))
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
 	}
 
 	var data struct {
-		PR struct {
-			Status string `json:"status"`
-		} `json:"pr"`
+		Changes []struct {
+			Action    string `json:"action"`
+			SubjectID string `json:"subject_id"`
+			ActorID   string `json:"actor_id"`
+		} `json:"changes"`
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
 		t.Fatalf("failed to decode response: %v", err)
 	}
 
-	if data.PR.Status != "MERGED" {
-		t.Fatalf("expected MERGED status, got %s", data.PR.Status)
+	if len(data.Changes) != 2 {
+		t.Fatalf("expected 2 changes, got %+v", data.Changes)
+	}
+
+	if data.Changes[0].Action != "MEMBER_DEACTIVATED" || data.Changes[0].SubjectID != "u2" || data.Changes[0].ActorID != "u1" {
+		t.Fatalf("unexpected first change: %+v", data.Changes[0])
+	}
+
+	if data.Changes[1].Action != "POLICY_CHANGED" || data.Changes[1].ActorID != "u1" {
+		t.Fatalf("unexpected second change: %+v", data.Changes[1])
 	}
 }
 
-func TestPullRequestReassign(t *testing.T) {
+func TestOrgPolicyInheritance(t *testing.T) {
 	ts, err := NewTestServer()
 	if err != nil {
 		t.Fatalf("Failed to create test server: %v", err)
@@ -237,60 +300,84 @@ func TestPullRequestReassign(t *testing.T) {
 		t.Fatalf("Failed to load fixtures: %v", err)
 	}
 
-	resp := doPost(t, ts, "/pullRequest/create", `{
-		"pull_request_id": "PR-200",
-		"pull_request_name": "Refactor core",
-		"author_id": "u1"
-	}`)
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusCreated {
-		body, _ := io.ReadAll(resp.Body)
-		t.Fatalf("failed to create PR: %d: %s", resp.StatusCode, string(body))
+	type effectivePolicy struct {
+		Policy struct {
+			HoldUntilCIGreen bool     `json:"hold_until_ci_green"`
+			MinReviewers     int      `json:"min_reviewers"`
+			DefaultLabels    []string `json:"default_labels"`
+		} `json:"policy"`
+		Sources map[string]string `json:"sources"`
 	}
 
-	var data struct {
-		PR struct {
-			Reviewers []string `json:"assigned_reviewers"`
-		} `json:"pr"`
-	}
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-		t.Fatalf("failed to decode PR response: %v", err)
-	}
+	getEffective := func() effectivePolicy {
+		t.Helper()
 
-	if len(data.PR.Reviewers) == 0 {
-		t.Fatal("no reviewers assigned")
+		resp := doGet(t, ts, "/policy/effective?team_name=Backend")
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("expected 200, got %d", resp.StatusCode)
+		}
+
+		var data effectivePolicy
+		if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		return data
 	}
 
-	old := data.PR.Reviewers[0]
+	data := getEffective()
+	if data.Policy.MinReviewers != 1 || data.Sources["min_reviewers"] != "ORG" {
+		t.Fatalf("expected inherited min_reviewers 1, got %+v", data)
+	}
 
-	body := fmt.Sprintf(`{
-		"pull_request_id": "PR-200",
-		"old_reviewer_id": "%s"
-	}`, old)
+	for _, call := range []struct{ path, body string }{
+		{"/admin/policy/update", `{"min_reviewers": 2, "default_labels": ["backend"]}`},
+		{"/team/update", `{"team_name": "Backend", "hold_until_ci_green": true, "min_reviewers": 0}`},
+	} {
+		resp := doPost(t, ts, call.path, call.body)
+		resp.Body.Close()
 
-	resp2 := doPost(t, ts, "/pullRequest/reassign", body)
-	defer resp2.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("POST %s: expected 200, got %d", call.path, resp.StatusCode)
+		}
+	}
 
-	if resp2.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp2.Body)
-		t.Fatalf("expected 200, got %d: %s", resp2.StatusCode, string(body))
+	data = getEffective()
+	if data.Policy.MinReviewers != 0 || data.Sources["min_reviewers"] != "TEAM" {
+		t.Fatalf("expected team min_reviewers 0, got %+v", data)
+	}
+	if !data.Policy.HoldUntilCIGreen || data.Sources["hold_until_ci_green"] != "TEAM" {
+		t.Fatalf("expected team hold_until_ci_green, got %+v", data)
+	}
+	if !slices.Equal(data.Policy.DefaultLabels, []string{"backend"}) || data.Sources["default_labels"] != "ORG" {
+		t.Fatalf("expected org default_labels, got %+v", data)
 	}
 
-	var out struct {
-		ReplacedBy string `json:"replaced_by"`
+	resp := doPost(t, ts, "/team/update", `{"team_name": "Backend", "min_reviewers": null}`)
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("failed to reset min_reviewers: %d", resp.StatusCode)
 	}
 
-	if err := json.NewDecoder(resp2.Body).Decode(&out); err != nil {
-		t.Fatalf("failed to decode reassign response: %v", err)
+	data = getEffective()
+	if data.Policy.MinReviewers != 2 || data.Sources["min_reviewers"] != "ORG" {
+		t.Fatalf("expected min_reviewers inherited from org after null, got %+v", data)
+	}
+	if !data.Policy.HoldUntilCIGreen {
+		t.Fatalf("absent field must stay untouched, got %+v", data)
 	}
 
-	if out.ReplacedBy == old {
-		t.Fatalf("reviewer was NOT replaced: %s", out.ReplacedBy)
+	resp = doGet(t, ts, "/policy/effective?team_name=Ghost")
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown team, got %d", resp.StatusCode)
 	}
 }
 
-func TestUserSetIsActive(t *testing.T) {
+func TestSettingsMergePatch(t *testing.T) {
 	ts, err := NewTestServer()
 	if err != nil {
 		t.Fatalf("Failed to create test server: %v", err)
@@ -301,7 +388,15 @@ func TestUserSetIsActive(t *testing.T) {
 		t.Fatalf("Failed to load fixtures: %v", err)
 	}
 
-	resp := doPost(t, ts, "/users/setIsActive", `{"user_id":"u2","is_active":false}`)
+	resp := doGet(t, ts, "/team/settings?team_name=Backend")
+	resp.Body.Close()
+
+	etag := resp.Header.Get("ETag")
+	if resp.StatusCode != http.StatusOK || etag == "" {
+		t.Fatalf("expected 200 with an ETag, got %d %q", resp.StatusCode, etag)
+	}
+
+	resp = doWithHeader(t, ts, http.MethodPatch, "/team/settings?team_name=Backend", `{"min_reviewers": 2}`, "If-Match", etag)
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
@@ -309,23 +404,83 @@ func TestUserSetIsActive(t *testing.T) {
 		t.Fatalf("expected 200, got %d: %s", resp.StatusCode, string(body))
 	}
 
-	var data struct {
-		User struct {
-			UserID   string `json:"user_id"`
-			IsActive bool   `json:"is_active"`
-		} `json:"user"`
+	var settings struct {
+		MinReviewers     *int  `json:"min_reviewers"`
+		HoldUntilCIGreen *bool `json:"hold_until_ci_green"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&settings); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if settings.MinReviewers == nil || *settings.MinReviewers != 2 || settings.HoldUntilCIGreen != nil {
+		t.Fatalf("expected only min_reviewers overridden, got %+v", settings)
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+	resp = doWithHeader(t, ts, http.MethodPatch, "/team/settings?team_name=Backend", `{"min_reviewers": 3}`, "If-Match", etag)
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusPreconditionFailed {
+		t.Fatalf("expected 412 for a stale ETag, got %d", resp.StatusCode)
+	}
+
+	for _, body := range []string{`{"team_name": "QA"}`, `{"review_labels": 1}`, `[]`} {
+		resp = doWithHeader(t, ts, http.MethodPatch, "/team/settings?team_name=Backend", body, "Content-Type", "application/merge-patch+json")
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("expected 400 for patch %s, got %d", body, resp.StatusCode)
+		}
+	}
+
+	resp = doWithHeader(t, ts, http.MethodPatch, "/team/settings?team_name=Backend", `{"min_reviewers": null}`, "Content-Type", "application/merge-patch+json")
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("failed to reset min_reviewers: %d", resp.StatusCode)
+	}
+
+	resp = doGet(t, ts, "/policy/effective?team_name=Backend")
+	defer resp.Body.Close()
+
+	var effective struct {
+		Sources map[string]string `json:"sources"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&effective); err != nil {
 		t.Fatalf("failed to decode response: %v", err)
 	}
+	if effective.Sources["min_reviewers"] != "ORG" {
+		t.Fatalf("expected min_reviewers inherited after null, got %+v", effective)
+	}
 
-	if data.User.IsActive {
-		t.Fatalf("user should be inactive")
+	resp = doWithHeader(t, ts, http.MethodPatch, "/users/settings?user_id=u2", `{"username": " Robert ", "is_active": false}`, "Content-Type", "application/merge-patch+json")
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		t.Fatalf("expected 200, got %d: %s", resp.StatusCode, string(body))
+	}
+
+	var user struct {
+		Username string `json:"username"`
+		IsActive bool   `json:"is_active"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&user); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if user.Username != "Robert" || user.IsActive {
+		t.Fatalf("expected renamed inactive user, got %+v", user)
+	}
+
+	for _, body := range []string{`{"username": ""}`, `{"is_active": null}`} {
+		resp = doWithHeader(t, ts, http.MethodPatch, "/users/settings?user_id=u2", body, "Content-Type", "application/merge-patch+json")
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("expected 400 for patch %s, got %d", body, resp.StatusCode)
+		}
 	}
 }
 
-func TestUserGetReview(t *testing.T) {
+func TestUserProfiles(t *testing.T) {
 	ts, err := NewTestServer()
 	if err != nil {
 		t.Fatalf("Failed to create test server: %v", err)
@@ -336,19 +491,39 @@ func TestUserGetReview(t *testing.T) {
 		t.Fatalf("Failed to load fixtures: %v", err)
 	}
 
-	resp := doPost(t, ts, "/pullRequest/create", `{
-		"pull_request_id": "PR-999",
-		"pull_request_name": "Fix",
-		"author_id": "u1"
-	}`)
+	resp := doPost(t, ts, "/team/add", `{"team_name": "Design", "members": [
+		{"user_id": "u40", "username": "anya", "is_active": true, "display_name": "Аня Петрова", "email": "anya@example.com", "locale": "ru_ru"},
+		{"user_id": "u41", "username": "ken", "is_active": true}
+	]}`)
 	resp.Body.Close()
 
 	if resp.StatusCode != http.StatusCreated {
-		body, _ := io.ReadAll(resp.Body)
-		t.Fatalf("failed to create PR: %d: %s", resp.StatusCode, string(body))
+		t.Fatalf("expected 201, got %d", resp.StatusCode)
 	}
 
-	resp = doGet(t, ts, "/users/getReview?user_id=u2")
+	resp = doGet(t, ts, "/team/get?team_name=Design")
+	defer resp.Body.Close()
+
+	var team struct {
+		Members []map[string]any `json:"members"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&team); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	for _, member := range team.Members {
+		switch member["user_id"] {
+		case "u40":
+			if member["display_name"] != "Аня Петрова" || member["email"] != "anya@example.com" || member["locale"] != "ru-RU" {
+				t.Fatalf("unexpected profile %+v", member)
+			}
+		case "u41":
+			if _, ok := member["display_name"]; ok {
+				t.Fatalf("expected no display_name for u41, got %+v", member)
+			}
+		}
+	}
+
+	resp = doWithHeader(t, ts, http.MethodPatch, "/users/settings?user_id=u40", `{"display_name": null, "avatar_url": "https://cdn.example.com/anya.png"}`, "Content-Type", "application/merge-patch+json")
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
@@ -356,33 +531,7113 @@ func TestUserGetReview(t *testing.T) {
 		t.Fatalf("expected 200, got %d: %s", resp.StatusCode, string(body))
 	}
 
-	var data struct {
-		PullRequests []struct {
-			PullRequestID string `json:"pull_request_id"`
-		} `json:"pull_requests"`
+	var settings map[string]any
+	if err := json.NewDecoder(resp.Body).Decode(&settings); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if _, ok := settings["display_name"]; ok || settings["avatar_url"] != "https://cdn.example.com/anya.png" || settings["locale"] != "ru-RU" {
+		t.Fatalf("expected display_name cleared and avatar set, got %+v", settings)
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+	for _, body := range []string{`{"email": "Anya <anya@example.com>"}`, `{"avatar_url": "javascript:alert(1)"}`, `{"locale": "russian"}`} {
+		resp = doWithHeader(t, ts, http.MethodPatch, "/users/settings?user_id=u40", body, "Content-Type", "application/merge-patch+json")
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("expected 400 for patch %s, got %d", body, resp.StatusCode)
+		}
+	}
+}
+
+func TestTeamWebhooks(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	type delivery struct {
+		header http.Header
+		body   []byte
+	}
+
+	receive := func() (*httptest.Server, chan delivery) {
+		received := make(chan delivery, 4)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			received <- delivery{header: r.Header, body: body}
+		}))
+		return server, received
+	}
+
+	backend, backendDeliveries := receive()
+	defer backend.Close()
+	qa, qaDeliveries := receive()
+	defer qa.Close()
+
+	for _, call := range []struct {
+		body   string
+		status int
+	}{
+		{`{"team_name": "Backend", "url": "` + backend.URL + `", "secret": "backend-secret", "events": ["pull_request.created"]}`, http.StatusCreated},
+		{`{"team_name": "QA", "url": "` + qa.URL + `", "secret": "qa-secret"}`, http.StatusCreated},
+		{`{"team_name": "Backend", "url": "` + backend.URL + `", "secret": "other"}`, http.StatusConflict},
+		{`{"team_name": "Backend", "url": "ftp://example.com", "secret": "s"}`, http.StatusBadRequest},
+		{`{"team_name": "Backend", "url": "https://example.com", "secret": "s", "events": ["pull_request.exploded"]}`, http.StatusBadRequest},
+		{`{"team_name": "Ghost", "url": "https://example.com", "secret": "s"}`, http.StatusNotFound},
+	} {
+		resp := doPost(t, ts, "/team/webhooks/create", call.body)
+		resp.Body.Close()
+
+		if resp.StatusCode != call.status {
+			t.Fatalf("create %s: expected %d, got %d", call.body, call.status, resp.StatusCode)
+		}
+	}
+
+	resp := doPost(t, ts, "/pullRequest/create", `{"pull_request_id": "PR-W1", "pull_request_name": "Hook", "author_id": "u1"}`)
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", resp.StatusCode)
+	}
+
+	var got delivery
+	select {
+	case got = <-backendDeliveries:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("backend webhook was not called")
+	}
+
+	if got.header.Get("X-Webhook-Event") != "pr.created" {
+		t.Fatalf("unexpected event header %q", got.header.Get("X-Webhook-Event"))
+	}
+	if got.header.Get("X-Webhook-Signature") != service.SignWebhook("backend-secret", got.body) {
+		t.Fatalf("signature does not match the body")
+	}
+
+	var payload struct {
+		Event         string `json:"event"`
+		TeamName      string `json:"team_name"`
+		PullRequestID string `json:"pull_request_id"`
+		Data          struct {
+			Reviewers []string `json:"reviewers"`
+		} `json:"data"`
+		Users map[string]struct {
+			Username string `json:"username"`
+		} `json:"users"`
+	}
+	if err := json.Unmarshal(got.body, &payload); err != nil {
+		t.Fatalf("failed to decode delivery: %v", err)
+	}
+	if payload.Event != "pr.created" || payload.TeamName != "Backend" || payload.PullRequestID != "PR-W1" || len(payload.Data.Reviewers) != 2 {
+		t.Fatalf("unexpected delivery %+v", payload)
+	}
+	if len(payload.Users) != 3 || payload.Users["u1"].Username == "" {
+		t.Fatalf("expected the author and both reviewers in users, got %+v", payload.Users)
+	}
+
+	select {
+	case <-qaDeliveries:
+		t.Fatalf("QA webhook must not receive events of Backend PRs")
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	resp = doGet(t, ts, "/team/webhooks?team_name=Backend")
+	defer resp.Body.Close()
+
+	var list struct {
+		Webhooks []map[string]any `json:"webhooks"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
 		t.Fatalf("failed to decode response: %v", err)
 	}
+	if len(list.Webhooks) != 1 {
+		t.Fatalf("expected 1 Backend webhook, got %d", len(list.Webhooks))
+	}
+	if _, ok := list.Webhooks[0]["secret"]; ok {
+		t.Fatalf("webhook secret must not be returned")
+	}
+	if events, _ := list.Webhooks[0]["events"].([]any); len(events) != 1 || events[0] != "pr.created" {
+		t.Fatalf("expected the subscription stored as pr.created, got %v", list.Webhooks[0]["events"])
+	}
 
-	if len(data.PullRequests) == 0 {
-		t.Fatalf("expected at least 1 PR in review list")
+	id := int64(list.Webhooks[0]["id"].(float64))
+	for _, call := range []struct {
+		path, body string
+		status     int
+	}{
+		{"/team/webhooks/update", fmt.Sprintf(`{"id": %d, "is_active": false}`, id), http.StatusOK},
+		{"/team/webhooks/delete", fmt.Sprintf(`{"id": %d}`, id), http.StatusOK},
+		{"/team/webhooks/delete", fmt.Sprintf(`{"id": %d}`, id), http.StatusNotFound},
+	} {
+		resp := doPost(t, ts, call.path, call.body)
+		resp.Body.Close()
+
+		if resp.StatusCode != call.status {
+			t.Fatalf("POST %s: expected %d, got %d", call.path, call.status, resp.StatusCode)
+		}
 	}
 }
 
-func doPost(t *testing.T, ts *TestServer, path string, body string) *http.Response {
-	resp, err := http.Post(ts.Server.URL+path, "application/json", bytes.NewBuffer([]byte(body)))
+func TestAssignmentSkewDetection(t *testing.T) {
+	ts, err := NewTestServer()
 	if err != nil {
-		t.Fatalf("POST %s failed: %v", path, err)
+		t.Fatalf("Failed to create test server: %v", err)
 	}
-	return resp
-}
+	defer ts.Close()
 
-func doGet(t *testing.T, ts *TestServer, path string) *http.Response {
-	resp, err := http.Get(ts.Server.URL + path)
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	resp := doPost(t, ts, "/pullRequest/create", `{"pull_request_id": "PR-1", "pull_request_name": "Fix API", "author_id": "u1"}`)
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("failed to create PR: %d", resp.StatusCode)
+	}
+
+	_, err = ts.DB.Exec(`
+		DELETE FROM assignment_history;
+		INSERT INTO assignment_history (pull_request_id, reviewer_id, action)
+		SELECT 'PR-1', 2, 'AUTO' FROM generate_series(1, 4);
+		INSERT INTO assignment_history (pull_request_id, reviewer_id, action) VALUES ('PR-1', 3, 'AUTO');
+	`)
 	if err != nil {
-		t.Fatalf("GET %s failed: %v", path, err)
+		t.Fatalf("failed to seed assignment history: %v", err)
+	}
+
+	if err := ts.Fairness.CheckAssignmentSkew(context.Background()); err != nil {
+		t.Fatalf("skew check failed: %v", err)
+	}
+
+	resp = doGet(t, ts, "/team/changes?team_name=Backend")
+	defer resp.Body.Close()
+
+	var data struct {
+		Changes []struct {
+			Action    string `json:"action"`
+			SubjectID string `json:"subject_id"`
+		} `json:"changes"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if len(data.Changes) != 1 || data.Changes[0].Action != "ASSIGNMENT_SKEW" || data.Changes[0].SubjectID != "u2" {
+		t.Fatalf("expected a single skew event for u2, got %+v", data.Changes)
+	}
+}
+
+func TestPullRequestReviewerTeams(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	qaMembers := map[string]bool{"u10": true, "u11": true}
+	countQA := func(reviewers []string) int {
+		count := 0
+		for _, reviewer := range reviewers {
+			if qaMembers[reviewer] {
+				count++
+			}
+		}
+		return count
+	}
+
+	type prResponse struct {
+		PR struct {
+			AssignedReviewers []string `json:"assigned_reviewers"`
+		} `json:"pr"`
+	}
+
+	resp := doPost(t, ts, "/pullRequest/create", `{
+		"pull_request_id": "PR-1",
+		"pull_request_name": "Explicit teams",
+		"author_id": "u1",
+		"reviewer_teams": [{"team_name": "QA", "reviewers": 1}]
+	}`)
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", resp.StatusCode)
+	}
+
+	var explicit prResponse
+	if err := json.NewDecoder(resp.Body).Decode(&explicit); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if len(explicit.PR.AssignedReviewers) != 3 || countQA(explicit.PR.AssignedReviewers) != 1 {
+		t.Fatalf("expected 2 Backend and 1 QA reviewer, got %v", explicit.PR.AssignedReviewers)
+	}
+
+	update := doPost(t, ts, "/team/update", `{"team_name": "QA", "review_labels": ["security"]}`)
+	update.Body.Close()
+
+	if update.StatusCode != http.StatusOK {
+		t.Fatalf("failed to update QA review labels: %d", update.StatusCode)
+	}
+
+	labeled := doPost(t, ts, "/pullRequest/create", `{
+		"pull_request_id": "PR-2",
+		"pull_request_name": "Labeled",
+		"author_id": "u1",
+		"labels": ["security"]
+	}`)
+	defer labeled.Body.Close()
+
+	var derived prResponse
+	if err := json.NewDecoder(labeled.Body).Decode(&derived); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if countQA(derived.PR.AssignedReviewers) != 1 {
+		t.Fatalf("expected a QA reviewer derived from label, got %v", derived.PR.AssignedReviewers)
+	}
+
+	unknown := doPost(t, ts, "/pullRequest/create", `{
+		"pull_request_id": "PR-3",
+		"pull_request_name": "Unknown team",
+		"author_id": "u1",
+		"reviewer_teams": [{"team_name": "Nope", "reviewers": 1}]
+	}`)
+	unknown.Body.Close()
+
+	if unknown.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown reviewer team, got %d", unknown.StatusCode)
+	}
+}
+
+func TestPullRequestSecurityGate(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	resp := doPost(t, ts, "/pullRequest/create", `{
+		"pull_request_id": "PR-1",
+		"pull_request_name": "Rework login",
+		"author_id": "u1",
+		"changed_paths": ["internal/auth/login.go"]
+	}`)
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", resp.StatusCode)
+	}
+
+	var created struct {
+		PR struct {
+			AssignedReviewers []string `json:"assigned_reviewers"`
+		} `json:"pr"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	var securityReviewer, teamReviewer string
+	for _, reviewer := range created.PR.AssignedReviewers {
+		if reviewer == "u10" || reviewer == "u11" {
+			securityReviewer = reviewer
+		} else {
+			teamReviewer = reviewer
+		}
+	}
+
+	if securityReviewer == "" {
+		t.Fatalf("expected a security team reviewer, got %v", created.PR.AssignedReviewers)
+	}
+
+	expectStatus := func(path string, body string, want int) {
+		t.Helper()
+		resp := doPost(t, ts, path, body)
+		resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Fatalf("POST %s: expected %d, got %d", path, want, resp.StatusCode)
+		}
+	}
+
+	expectStatus("/pullRequest/unassign",
+		fmt.Sprintf(`{"pull_request_id": "PR-1", "reviewer_id": "%s"}`, securityReviewer), http.StatusConflict)
+	expectStatus("/pullRequest/approve",
+		fmt.Sprintf(`{"pull_request_id": "PR-1", "reviewer_id": "%s"}`, teamReviewer), http.StatusOK)
+	expectStatus("/pullRequest/merge", `{"pull_request_id": "PR-1"}`, http.StatusConflict)
+	expectStatus("/pullRequest/approve",
+		fmt.Sprintf(`{"pull_request_id": "PR-1", "reviewer_id": "%s"}`, securityReviewer), http.StatusOK)
+	expectStatus("/pullRequest/merge", `{"pull_request_id": "PR-1"}`, http.StatusOK)
+}
+
+func TestPullRequestAutoMerge(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	create := func(prID string, body string) []string {
+		t.Helper()
+		resp := doPost(t, ts, "/pullRequest/create", body)
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusCreated {
+			respBody, _ := io.ReadAll(resp.Body)
+			t.Fatalf("failed to create %s: %d: %s", prID, resp.StatusCode, string(respBody))
+		}
+
+		var data struct {
+			PR struct {
+				AutoMerge         bool     `json:"auto_merge"`
+				AssignedReviewers []string `json:"assigned_reviewers"`
+			} `json:"pr"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if !data.PR.AutoMerge || len(data.PR.AssignedReviewers) != 2 {
+			t.Fatalf("expected an auto-merge PR with 2 reviewers, got %+v", data.PR)
+		}
+		return data.PR.AssignedReviewers
+	}
+
+	approve := func(prID, reviewerID string) {
+		t.Helper()
+		resp := doPost(t, ts, "/pullRequest/approve",
+			fmt.Sprintf(`{"pull_request_id": %q, "reviewer_id": %q}`, prID, reviewerID))
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("failed to approve %s: %d", prID, resp.StatusCode)
+		}
+	}
+
+	status := func(prID string) string {
+		t.Helper()
+		var status string
+		if err := ts.DB.Get(&status, `SELECT status FROM pull_requests WHERE pull_request_id = $1`, prID); err != nil {
+			t.Fatalf("failed to get status of %s: %v", prID, err)
+		}
+		return status
+	}
+
+	ciReviewers := create("PR-AM1", `{"pull_request_id": "PR-AM1", "pull_request_name": "CI", "author_id": "u1",
+		"ci_status": "PENDING", "auto_merge": true, "auto_merge_approvals": 2}`)
+	approve("PR-AM1", ciReviewers[0])
+	approve("PR-AM1", ciReviewers[1])
+
+	partialReviewers := create("PR-AM2", `{"pull_request_id": "PR-AM2", "pull_request_name": "Partial", "author_id": "u1",
+		"auto_merge": true, "auto_merge_approvals": 2}`)
+	approve("PR-AM2", partialReviewers[0])
+
+	if err := ts.PullRequests.AutoMerge(context.Background()); err != nil {
+		t.Fatalf("auto-merge failed: %v", err)
+	}
+	if status("PR-AM1") != "OPEN" || status("PR-AM2") != "OPEN" {
+		t.Fatalf("expected both PRs to wait, got %s and %s", status("PR-AM1"), status("PR-AM2"))
+	}
+
+	resp := doPost(t, ts, "/pullRequest/ciStatus", `{"pull_request_id": "PR-AM1", "ci_status": "SUCCESS"}`)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("failed to update CI status: %d", resp.StatusCode)
+	}
+
+	if err := ts.PullRequests.AutoMerge(context.Background()); err != nil {
+		t.Fatalf("auto-merge failed: %v", err)
+	}
+	if status("PR-AM1") != "MERGED" {
+		t.Fatalf("expected PR-AM1 to be auto-merged, got %s", status("PR-AM1"))
+	}
+	if status("PR-AM2") != "OPEN" {
+		t.Fatalf("expected PR-AM2 to wait for a second approval, got %s", status("PR-AM2"))
+	}
+
+	resp = doPost(t, ts, "/pullRequest/create", `{"pull_request_id": "PR-AM3", "pull_request_name": "Bad", "author_id": "u1",
+		"auto_merge": true, "auto_merge_approvals": -1}`)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected 400 for negative approvals, got %d", resp.StatusCode)
+	}
+}
+
+func TestPullRequestCertifiedReviewers(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	grant := doPost(t, ts, "/certifications/grant", `{"user_id": "u3", "area": "Payments"}`)
+	grant.Body.Close()
+
+	if grant.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200 on grant, got %d", grant.StatusCode)
+	}
+
+	resp := doPost(t, ts, "/pullRequest/create", `{
+		"pull_request_id": "PR-1",
+		"pull_request_name": "Refund flow",
+		"author_id": "u1",
+		"required_certifications": ["payments"]
+	}`)
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", resp.StatusCode)
+	}
+
+	var created struct {
+		PR struct {
+			AssignedReviewers []string `json:"assigned_reviewers"`
+		} `json:"pr"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	hasCertified := false
+	for _, reviewer := range created.PR.AssignedReviewers {
+		if reviewer == "u3" {
+			hasCertified = true
+		}
+	}
+	if !hasCertified {
+		t.Fatalf("expected certified reviewer u3, got %v", created.PR.AssignedReviewers)
+	}
+
+	unassign := doPost(t, ts, "/pullRequest/unassign", `{"pull_request_id": "PR-1", "reviewer_id": "u3"}`)
+	unassign.Body.Close()
+
+	if unassign.StatusCode != http.StatusConflict {
+		t.Fatalf("expected 409 when removing the only certified reviewer, got %d", unassign.StatusCode)
+	}
+
+	uncovered := doPost(t, ts, "/pullRequest/create", `{
+		"pull_request_id": "PR-2",
+		"pull_request_name": "Terraform",
+		"author_id": "u1",
+		"required_certifications": ["infra"]
+	}`)
+	uncovered.Body.Close()
+
+	if uncovered.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected 404 without certified reviewers, got %d", uncovered.StatusCode)
+	}
+
+	list := doGet(t, ts, "/certifications/list?area=payments")
+	defer list.Body.Close()
+
+	var listed struct {
+		Certifications []struct {
+			UserID string `json:"user_id"`
+		} `json:"certifications"`
+	}
+	if err := json.NewDecoder(list.Body).Decode(&listed); err != nil {
+		t.Fatalf("failed to decode list response: %v", err)
+	}
+
+	if len(listed.Certifications) != 1 || listed.Certifications[0].UserID != "u3" {
+		t.Fatalf("unexpected certifications: %+v", listed.Certifications)
+	}
+}
+
+func TestPullRequestCreate(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	body := `{
+		"pull_request_id": "PR-1",
+		"pull_request_name": "Fix API",
+		"author_id": "u1"
+	}`
+
+	resp := doPost(t, ts, "/pullRequest/create", body)
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		t.Fatalf("expected 201, got %d: %s", resp.StatusCode, string(body))
+	}
+
+	var data struct {
+		PR struct {
+			PullRequestID     string   `json:"pull_request_id"`
+			Status            string   `json:"status"`
+			AssignedReviewers []string `json:"assigned_reviewers"`
+		} `json:"pr"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if data.PR.PullRequestID != "PR-1" {
+		t.Fatalf("wrong PR id: %s", data.PR.PullRequestID)
+	}
+
+	if data.PR.Status != "OPEN" {
+		t.Fatalf("expected OPEN status, got %s", data.PR.Status)
+	}
+
+	if len(data.PR.AssignedReviewers) != 2 {
+		t.Fatalf("expected 2 reviewers, got %d", len(data.PR.AssignedReviewers))
+	}
+}
+
+func TestPullRequestCreateCandidateCache(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	create := func(prID string) []string {
+		t.Helper()
+
+		resp := doPost(t, ts, "/pullRequest/create", `{"pull_request_id": "`+prID+`", "pull_request_name": "Cache", "author_id": "u1"}`)
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusCreated {
+			body, _ := io.ReadAll(resp.Body)
+			t.Fatalf("expected 201, got %d: %s", resp.StatusCode, string(body))
+		}
+
+		var data struct {
+			PR struct {
+				AssignedReviewers []string `json:"assigned_reviewers"`
+			} `json:"pr"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		return data.PR.AssignedReviewers
+	}
+
+	if reviewers := create("PR-C1"); len(reviewers) != 2 {
+		t.Fatalf("expected 2 reviewers, got %v", reviewers)
+	}
+
+	resp := doPost(t, ts, "/users/setIsActiveBatch", `{"user_ids":["u2","u3","u4"],"is_active":false}`)
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("failed to deactivate members: %d", resp.StatusCode)
+	}
+
+	if reviewers := create("PR-C2"); !slices.Equal(reviewers, []string{"u5"}) {
+		t.Fatalf("deactivated members must not be served from the cache, got %v", reviewers)
+	}
+}
+
+// TestCandidateCacheAcrossInstances checks that a membership change made
+// through one instance drops the candidate cache of another.
+func TestCandidateCacheAcrossInstances(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	other := service.NewCandidateCache(log, time.Minute, repo.NewNotifyRepo(ts.DB))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	listening := make(chan struct{})
+	var once sync.Once
+	go postgresql.Listen(ctx, testDSN, service.CandidateCacheChannel, log, func(payload string) {
+		other.HandleNotification(payload)
+		once.Do(func() { close(listening) })
+	})
+
+	select {
+	case <-listening:
+	case <-time.After(5 * time.Second):
+		t.Fatal("listener did not start")
+	}
+
+	var loads atomic.Int32
+	load := func(teamName string) ([]string, error) {
+		loads.Add(1)
+		return []string{"u1", "u2"}, nil
+	}
+
+	other.Members("Backend", load)
+	other.Members("Backend", load)
+	if loads.Load() != 1 {
+		t.Fatalf("expected the second lookup to be cached, got %d loads", loads.Load())
+	}
+
+	resp := doPost(t, ts, "/users/setIsActive", `{"user_id": "u2", "is_active": false}`)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("failed to deactivate u2: %d", resp.StatusCode)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for loads.Load() < 2 {
+		if time.Now().After(deadline) {
+			t.Fatal("expected the other instance to drop its cache")
+		}
+		time.Sleep(20 * time.Millisecond)
+		other.Members("Backend", load)
+	}
+}
+
+func TestPullRequestAssignmentTrace(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	type assignmentTrace struct {
+		Exclusions []struct {
+			UserID string `json:"user_id"`
+			Reason string `json:"reason"`
+		} `json:"exclusions"`
+		Steps []struct {
+			Source     string   `json:"source"`
+			TeamName   string   `json:"team_name"`
+			Pool       []string `json:"pool"`
+			Candidates []struct {
+				UserID string  `json:"user_id"`
+				Score  float64 `json:"score"`
+			} `json:"candidates"`
+			Picked []string `json:"picked"`
+		} `json:"steps"`
+		Picks []string `json:"picks"`
+	}
+
+	resp := doPost(t, ts, "/pullRequest/create", `{"pull_request_id": "PR-T0", "pull_request_name": "Plain", "author_id": "u1"}`)
+	var plain map[string]json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&plain); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	resp.Body.Close()
+	if _, ok := plain["trace"]; ok {
+		t.Fatalf("expected no trace without debug")
+	}
+
+	resp = doPost(t, ts, "/pullRequest/create?debug=true", `{"pull_request_id": "PR-T1", "pull_request_name": "Traced", "author_id": "u1"}`)
+	if resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		t.Fatalf("expected 201, got %d: %s", resp.StatusCode, string(body))
+	}
+
+	var created struct {
+		PR struct {
+			AssignedReviewers []string `json:"assigned_reviewers"`
+		} `json:"pr"`
+		Trace *assignmentTrace `json:"trace"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	resp.Body.Close()
+
+	if created.Trace == nil {
+		t.Fatalf("expected a trace with debug=true")
+	}
+	if len(created.Trace.Exclusions) == 0 || created.Trace.Exclusions[0].UserID != "u1" || created.Trace.Exclusions[0].Reason != "AUTHOR" {
+		t.Fatalf("expected the author to be excluded, got %+v", created.Trace.Exclusions)
+	}
+	if len(created.Trace.Steps) != 1 || created.Trace.Steps[0].TeamName != "Backend" {
+		t.Fatalf("expected one Backend step, got %+v", created.Trace.Steps)
+	}
+	step := created.Trace.Steps[0]
+	if len(step.Pool) != 4 || len(step.Candidates) != 4 {
+		t.Fatalf("expected 4 candidates besides the author, got pool %v and %+v", step.Pool, step.Candidates)
+	}
+	for _, candidate := range step.Candidates {
+		if candidate.UserID == "u1" || candidate.Score <= 0 {
+			t.Fatalf("unexpected candidate %+v", candidate)
+		}
+	}
+	if !sameReviewers(created.Trace.Picks, created.PR.AssignedReviewers) {
+		t.Fatalf("expected picks %v to match assigned reviewers %v", created.Trace.Picks, created.PR.AssignedReviewers)
+	}
+
+	old := created.PR.AssignedReviewers[0]
+	resp = doPost(t, ts, "/pullRequest/reassign?debug=true",
+		fmt.Sprintf(`{"pull_request_id": "PR-T1", "old_reviewer_id": %q, "reason": "manual"}`, old))
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		t.Fatalf("expected 200, got %d: %s", resp.StatusCode, string(body))
+	}
+
+	var reassigned struct {
+		ReplacedBy string           `json:"replaced_by"`
+		Trace      *assignmentTrace `json:"trace"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&reassigned); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	resp.Body.Close()
+
+	if reassigned.Trace == nil || len(reassigned.Trace.Picks) != 1 || reassigned.Trace.Picks[0] != reassigned.ReplacedBy {
+		t.Fatalf("expected trace to pick %s, got %+v", reassigned.ReplacedBy, reassigned.Trace)
+	}
+	assigned := false
+	for _, exclusion := range reassigned.Trace.Exclusions {
+		if exclusion.UserID == old && exclusion.Reason == "ALREADY_ASSIGNED" {
+			assigned = true
+		}
+	}
+	if !assigned {
+		t.Fatalf("expected %s to be excluded as already assigned, got %+v", old, reassigned.Trace.Exclusions)
+	}
+
+	resp = doPost(t, ts, "/pullRequest/create?debug=maybe", `{"pull_request_id": "PR-T2", "pull_request_name": "Bad", "author_id": "u1"}`)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected 400 for an invalid debug flag, got %d", resp.StatusCode)
+	}
+}
+
+func sameReviewers(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	seen := make(map[string]int, len(a))
+	for _, id := range a {
+		seen[id]++
+	}
+	for _, id := range b {
+		seen[id]--
+		if seen[id] < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func TestAdminRebalance(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	_, err = ts.DB.Exec(`
+		INSERT INTO pull_requests (pull_request_id, pull_request_name, author_id) VALUES
+			('PR-R1', 'One', 10), ('PR-R2', 'Two', 10), ('PR-R3', 'Three', 10),
+			('PR-R4', 'Four', 10), ('PR-R5', 'Five', 10);
+		INSERT INTO pr_reviewers (pull_request_id, reviewer_id) VALUES
+			('PR-R1', 2), ('PR-R2', 2), ('PR-R3', 2), ('PR-R4', 2), ('PR-R5', 5);
+		UPDATE pr_reviewers SET review_started_at = NOW() WHERE pull_request_id = 'PR-R4';
+		UPDATE users SET is_active = false WHERE user_id = 5;
+	`)
+	if err != nil {
+		t.Fatalf("failed to seed assignments: %v", err)
+	}
+
+	type plan struct {
+		Rebalance struct {
+			DryRun bool `json:"dry_run"`
+			Moves  []struct {
+				PullRequestID  string `json:"pull_request_id"`
+				FromReviewerID string `json:"from_reviewer_id"`
+				ToReviewerID   string `json:"to_reviewer_id"`
+			} `json:"moves"`
+			LoadAfter map[string]int `json:"load_after"`
+		} `json:"rebalance"`
+	}
+
+	rebalance := func(query string) plan {
+		t.Helper()
+		resp := doPost(t, ts, "/admin/rebalance?"+query, `{}`)
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			t.Fatalf("expected 200, got %d: %s", resp.StatusCode, string(body))
+		}
+
+		var data plan
+		if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		return data
+	}
+
+	proposed := rebalance("team_name=Backend&dry_run=true")
+	if !proposed.Rebalance.DryRun || len(proposed.Rebalance.Moves) != 3 {
+		t.Fatalf("expected 3 proposed moves, got %+v", proposed.Rebalance)
+	}
+	for _, move := range proposed.Rebalance.Moves {
+		if move.PullRequestID == "PR-R4" || move.ToReviewerID == "u5" || move.ToReviewerID == "u2" {
+			t.Fatalf("unexpected move %+v", move)
+		}
+	}
+
+	var moved int
+	if err := ts.DB.Get(&moved, `SELECT COUNT(*) FROM pr_reviewers WHERE reviewer_id NOT IN (2, 5)`); err != nil {
+		t.Fatalf("failed to count assignments: %v", err)
+	}
+	if moved != 0 {
+		t.Fatalf("dry run must not move assignments, %d moved", moved)
+	}
+
+	applied := rebalance("team_name=Backend")
+	if applied.Rebalance.DryRun || len(applied.Rebalance.Moves) != 3 {
+		t.Fatalf("expected 3 applied moves, got %+v", applied.Rebalance)
+	}
+
+	var loads []int
+	if err := ts.DB.Select(&loads, `
+		SELECT COUNT(prr.reviewer_id) FROM users u
+		LEFT JOIN pr_reviewers prr ON prr.reviewer_id = u.user_id
+		WHERE u.team_name = 'Backend' AND u.is_active
+		GROUP BY u.user_id`); err != nil {
+		t.Fatalf("failed to count loads: %v", err)
+	}
+	lowest, highest := loads[0], loads[0]
+	for _, n := range loads {
+		lowest, highest = min(lowest, n), max(highest, n)
+	}
+	if highest-lowest > 1 {
+		t.Fatalf("expected an even load, got %v", loads)
+	}
+
+	var inactive int
+	if err := ts.DB.Get(&inactive, `SELECT COUNT(*) FROM pr_reviewers WHERE reviewer_id = 5`); err != nil {
+		t.Fatalf("failed to count assignments: %v", err)
+	}
+	if inactive != 0 {
+		t.Fatalf("expected the inactive member to hand over their review")
+	}
+
+	resp := doPost(t, ts, "/admin/rebalance?team_name=Nope", `{}`)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected 404 for an unknown team, got %d", resp.StatusCode)
+	}
+}
+
+func TestAdminAssignmentFreeze(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	type createdPR struct {
+		PR struct {
+			AssignedReviewers []string `json:"assigned_reviewers"`
+			AssignmentQueued  bool     `json:"assignment_queued"`
+		} `json:"pr"`
+	}
+
+	create := func(prID, authorID string) createdPR {
+		t.Helper()
+		resp := doPost(t, ts, "/pullRequest/create",
+			fmt.Sprintf(`{"pull_request_id": %q, "pull_request_name": "Freeze", "author_id": %q}`, prID, authorID))
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusCreated {
+			body, _ := io.ReadAll(resp.Body)
+			t.Fatalf("failed to create %s: %d: %s", prID, resp.StatusCode, string(body))
+		}
+
+		var data createdPR
+		if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		return data
+	}
+
+	reviewerCount := func(prID string) int {
+		t.Helper()
+		var count int
+		if err := ts.DB.Get(&count, `SELECT COUNT(*) FROM pr_reviewers WHERE pull_request_id = $1`, prID); err != nil {
+			t.Fatalf("failed to count reviewers of %s: %v", prID, err)
+		}
+		return count
+	}
+
+	for _, tc := range []struct {
+		body   string
+		status int
+		code   string
+	}{
+		{`{"team_name": "Nope"}`, http.StatusNotFound, "NOT_FOUND"},
+		{fmt.Sprintf(`{"team_name": "Backend", "ends_at": %q}`, time.Now().Add(-time.Hour).Format(time.RFC3339)), http.StatusBadRequest, "INVALID_FREEZE"},
+	} {
+		resp := doPost(t, ts, "/admin/freeze", tc.body)
+		var errResp httpio.ErrorResponse
+		json.NewDecoder(resp.Body).Decode(&errResp)
+		resp.Body.Close()
+		if resp.StatusCode != tc.status || errResp.Error.Code != tc.code {
+			t.Fatalf("freeze %s: expected %d %s, got %d %s", tc.body, tc.status, tc.code, resp.StatusCode, errResp.Error.Code)
+		}
+	}
+
+	scheduled := fmt.Sprintf(`{"team_name": "Backend", "reason": "release", "starts_at": %q}`,
+		time.Now().Add(time.Hour).Format(time.RFC3339))
+	resp := doPost(t, ts, "/admin/freeze", scheduled)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected 201 for scheduled freeze, got %d", resp.StatusCode)
+	}
+
+	if pr := create("PR-FZ0", "u1"); pr.PR.AssignmentQueued || len(pr.PR.AssignedReviewers) == 0 {
+		t.Fatalf("scheduled freeze must not queue PRs yet, got %+v", pr.PR)
+	}
+
+	resp = doPost(t, ts, "/admin/freeze", `{"team_name": "Backend", "reason": "incident"}`)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected 201 for freeze, got %d", resp.StatusCode)
+	}
+
+	frozen := create("PR-FZ1", "u1")
+	if !frozen.PR.AssignmentQueued || len(frozen.PR.AssignedReviewers) != 0 {
+		t.Fatalf("expected PR-FZ1 to be queued without reviewers, got %+v", frozen.PR)
+	}
+
+	if pr := create("PR-FZ2", "u10"); pr.PR.AssignmentQueued || len(pr.PR.AssignedReviewers) == 0 {
+		t.Fatalf("freeze of Backend must not affect QA, got %+v", pr.PR)
+	}
+
+	resp = doGet(t, ts, "/admin/freezes")
+	var listed struct {
+		Freezes []struct {
+			TeamName string `json:"team_name"`
+			Reason   string `json:"reason"`
+		} `json:"freezes"`
+	}
+	json.NewDecoder(resp.Body).Decode(&listed)
+	resp.Body.Close()
+	if len(listed.Freezes) != 2 {
+		t.Fatalf("expected 2 freezes, got %+v", listed.Freezes)
+	}
+
+	if err := ts.PullRequests.ReleaseQueued(context.Background()); err != nil {
+		t.Fatalf("release failed: %v", err)
+	}
+	if reviewerCount("PR-FZ1") != 0 {
+		t.Fatal("queued PR must stay without reviewers while frozen")
+	}
+
+	resp = doPost(t, ts, "/admin/unfreeze", `{"team_name": "Backend"}`)
+	var unfrozen struct {
+		Unfreeze struct {
+			Lifted   int      `json:"lifted"`
+			Released []string `json:"released_pull_requests"`
+		} `json:"unfreeze"`
+	}
+	json.NewDecoder(resp.Body).Decode(&unfrozen)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200 for unfreeze, got %d", resp.StatusCode)
+	}
+	if unfrozen.Unfreeze.Lifted != 2 || len(unfrozen.Unfreeze.Released) != 1 || unfrozen.Unfreeze.Released[0] != "PR-FZ1" {
+		t.Fatalf("expected 2 lifted freezes and PR-FZ1 released, got %+v", unfrozen.Unfreeze)
+	}
+	if reviewerCount("PR-FZ1") != 2 {
+		t.Fatalf("expected 2 reviewers after unfreeze, got %d", reviewerCount("PR-FZ1"))
+	}
+
+	var queued bool
+	if err := ts.DB.Get(&queued, `SELECT assignment_queued FROM pull_requests WHERE pull_request_id = 'PR-FZ1'`); err != nil || queued {
+		t.Fatalf("expected PR-FZ1 to leave the queue, queued=%v err=%v", queued, err)
+	}
+}
+
+func TestPendingAssignments(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	for _, body := range []string{
+		`{"team_name": "Backend", "reason": "incident"}`,
+		fmt.Sprintf(`{"team_name": "QA", "reason": "release", "ends_at": %q}`, time.Now().Add(time.Hour).Format(time.RFC3339)),
+	} {
+		resp := doPost(t, ts, "/admin/freeze", body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("expected 201 for freeze %s, got %d", body, resp.StatusCode)
+		}
+	}
+
+	for _, pr := range []struct{ id, author string }{{"PR-PA1", "u1"}, {"PR-PA2", "u2"}, {"PR-PA3", "u10"}} {
+		resp := doPost(t, ts, "/pullRequest/create",
+			fmt.Sprintf(`{"pull_request_id": %q, "pull_request_name": "Pending", "author_id": %q}`, pr.id, pr.author))
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("failed to create %s: %d", pr.id, resp.StatusCode)
+		}
+	}
+
+	type pendingResponse struct {
+		PullRequests []struct {
+			PullRequestID string `json:"pull_request_id"`
+			TeamName      string `json:"team_name"`
+			Position      int    `json:"position"`
+			Frozen        bool   `json:"frozen"`
+			EstimatedWait *int64 `json:"estimated_wait_seconds"`
+		} `json:"pull_requests"`
+	}
+
+	get := func(query string) (int, pendingResponse) {
+		t.Helper()
+		resp := doGet(t, ts, "/pullRequest/pendingAssignments"+query)
+		defer resp.Body.Close()
+
+		var data pendingResponse
+		json.NewDecoder(resp.Body).Decode(&data)
+		return resp.StatusCode, data
+	}
+
+	status, backend := get("?team_name=Backend")
+	if status != http.StatusOK || len(backend.PullRequests) != 2 {
+		t.Fatalf("expected 2 queued Backend PRs, got %d %+v", status, backend)
+	}
+	for i, pr := range backend.PullRequests {
+		if pr.Position != i+1 || !pr.Frozen || pr.EstimatedWait != nil {
+			t.Fatalf("expected position %d with unknown wait under an open-ended freeze, got %+v", i+1, pr)
+		}
+	}
+	if backend.PullRequests[0].PullRequestID != "PR-PA1" {
+		t.Fatalf("expected the oldest PR first, got %+v", backend.PullRequests)
+	}
+
+	_, all := get("")
+	if len(all.PullRequests) != 3 {
+		t.Fatalf("expected 3 queued PRs, got %+v", all.PullRequests)
+	}
+	qa := all.PullRequests[2]
+	if qa.TeamName != "QA" || qa.Position != 1 || qa.EstimatedWait == nil || *qa.EstimatedWait <= 0 || *qa.EstimatedWait > 3600 {
+		t.Fatalf("expected QA PR to wait until its freeze ends, got %+v", qa)
+	}
+
+	if status, _ := get("?team_name=Nope"); status != http.StatusNotFound {
+		t.Fatalf("expected 404 for an unknown team, got %d", status)
+	}
+}
+
+func TestPullRequestExclusionRules(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	resp := doPost(t, ts, "/team/update", `{"team_name": "Backend", "exclude_co_authors": true, "exclude_pairing_session": true}`)
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("failed to update policy: %d", resp.StatusCode)
+	}
+
+	for i := 0; i < 5; i++ {
+		resp := doPost(t, ts, "/pullRequest/create", fmt.Sprintf(`{
+			"pull_request_id": "PR-X%d",
+			"pull_request_name": "Pairing",
+			"author_id": "u1",
+			"co_authors": ["u2", "u3"],
+			"pairing_session": ["u4"]
+		}`, i))
+
+		var data struct {
+			PR struct {
+				AssignedReviewers []string `json:"assigned_reviewers"`
+			} `json:"pr"`
+		}
+		err := json.NewDecoder(resp.Body).Decode(&data)
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusCreated || err != nil {
+			t.Fatalf("failed to create PR: %d %v", resp.StatusCode, err)
+		}
+
+		if len(data.PR.AssignedReviewers) != 1 || data.PR.AssignedReviewers[0] != "u5" {
+			t.Fatalf("expected only u5 to be assignable, got %v", data.PR.AssignedReviewers)
+		}
+	}
+
+	reassign := doPost(t, ts, "/pullRequest/reassign", `{"pull_request_id": "PR-X0", "old_reviewer_id": "u5", "reason": "conflict"}`)
+	reassign.Body.Close()
+
+	if reassign.StatusCode != http.StatusConflict {
+		t.Fatalf("expected 409 when only excluded users remain, got %d", reassign.StatusCode)
+	}
+}
+
+func TestPullRequestCreateConcurrent(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	const attempts = 10
+
+	var wg sync.WaitGroup
+	statuses := make(chan int, attempts)
+
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			resp, err := http.Post(ts.Server.URL+"/pullRequest/create", "application/json", strings.NewReader(`{
+				"pull_request_id": "PR-RACE",
+				"pull_request_name": "Race",
+				"author_id": "u1"
+			}`))
+			if err != nil {
+				t.Errorf("request failed: %v", err)
+				return
+			}
+			resp.Body.Close()
+
+			statuses <- resp.StatusCode
+		}()
+	}
+
+	wg.Wait()
+	close(statuses)
+
+	created, conflicts := 0, 0
+	for status := range statuses {
+		switch status {
+		case http.StatusCreated:
+			created++
+		case http.StatusConflict:
+			conflicts++
+		default:
+			t.Errorf("unexpected status %d", status)
+		}
+	}
+
+	if created != 1 || conflicts != attempts-1 {
+		t.Fatalf("expected 1 created and %d conflicts, got %d and %d", attempts-1, created, conflicts)
+	}
+}
+
+func TestPullRequestMerge(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	resp := doPost(t, ts, "/pullRequest/create", `{
+		"pull_request_id": "PR-77",
+		"pull_request_name": "Refactor",
+		"author_id": "u1"
+	}`)
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		t.Fatalf("failed to create PR: %d: %s", resp.StatusCode, string(body))
+	}
+
+	resp = doPost(t, ts, "/pullRequest/merge", `{
+		"pull_request_id": "PR-77"
+	}`)
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		t.Fatalf("expected 200, got %d: %s", resp.StatusCode, string(body))
+	}
+
+	var data struct {
+		PR struct {
+			Status string `json:"status"`
+		} `json:"pr"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if data.PR.Status != "MERGED" {
+		t.Fatalf("expected MERGED status, got %s", data.PR.Status)
+	}
+}
+
+func TestPullRequestMergeRetry(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	resp := doPost(t, ts, "/pullRequest/create", `{"pull_request_id": "PR-M2", "pull_request_name": "Retry", "author_id": "u1"}`)
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("failed to create PR: %d", resp.StatusCode)
+	}
+
+	type mergeResponse struct {
+		PR struct {
+			MergedAt string `json:"mergedAt"`
+		} `json:"pr"`
+		AlreadyMerged bool `json:"already_merged"`
+	}
+
+	merge := func(body string) (int, mergeResponse) {
+		resp := doPost(t, ts, "/pullRequest/merge", body)
+		defer resp.Body.Close()
+
+		var data mergeResponse
+		if resp.StatusCode == http.StatusOK {
+			if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+		}
+		return resp.StatusCode, data
+	}
+
+	status, first := merge(`{"pull_request_id": "PR-M2"}`)
+	if status != http.StatusOK || first.AlreadyMerged {
+		t.Fatalf("expected fresh merge, got %d %+v", status, first)
+	}
+
+	status, retry := merge(`{"pull_request_id": "PR-M2"}`)
+	if status != http.StatusOK || !retry.AlreadyMerged {
+		t.Fatalf("expected already_merged on retry, got %d %+v", status, retry)
+	}
+
+	if retry.PR.MergedAt != first.PR.MergedAt {
+		t.Fatalf("expected original merged_at %s, got %s", first.PR.MergedAt, retry.PR.MergedAt)
+	}
+
+	status, _ = merge(`{"pull_request_id": "PR-M2", "strict": true}`)
+	if status != http.StatusConflict {
+		t.Fatalf("expected 409 in strict mode, got %d", status)
+	}
+}
+
+func TestPullRequestMergeAttribution(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	for _, id := range []string{"PR-A1", "PR-A2", "PR-A3"} {
+		resp := doPost(t, ts, "/pullRequest/create", fmt.Sprintf(`{"pull_request_id": "%s", "pull_request_name": "Attribution", "author_id": "u1"}`, id))
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("failed to create %s: %d", id, resp.StatusCode)
+		}
+	}
+
+	mergedBy := func(resp *http.Response) string {
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			t.Fatalf("expected 200, got %d: %s", resp.StatusCode, string(body))
+		}
+
+		var data struct {
+			PR struct {
+				MergedBy string `json:"merged_by"`
+			} `json:"pr"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		return data.PR.MergedBy
+	}
+
+	if got := mergedBy(doPost(t, ts, "/pullRequest/merge", `{"pull_request_id": "PR-A1", "merged_by": "u3"}`)); got != "u3" {
+		t.Fatalf("expected merged_by u3, got %q", got)
+	}
+
+	if got := mergedBy(doPostAs(t, ts, "/pullRequest/merge", `{"pull_request_id": "PR-A2"}`, "u3")); got != "u3" {
+		t.Fatalf("expected merged_by derived from caller, got %q", got)
+	}
+
+	resp := doPost(t, ts, "/pullRequest/merge", `{"pull_request_id": "PR-A3", "merged_by": "u999"}`)
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown merged_by, got %d", resp.StatusCode)
+	}
+
+	resp = doWithKey(t, ts, http.MethodGet, "/stats/prs", "", ts.AdminKey)
+	defer resp.Body.Close()
+
+	var stats struct {
+		Stats struct {
+			MergesByUser map[string]int `json:"merges_by_user"`
+		} `json:"stats"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&stats); err != nil {
+		t.Fatalf("failed to decode stats: %v", err)
+	}
+
+	if stats.Stats.MergesByUser["u3"] != 2 {
+		t.Fatalf("expected 2 merges by u3, got %v", stats.Stats.MergesByUser)
+	}
+}
+
+func TestPullRequestReassign(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	resp := doPost(t, ts, "/pullRequest/create", `{
+		"pull_request_id": "PR-200",
+		"pull_request_name": "Refactor core",
+		"author_id": "u1"
+	}`)
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		t.Fatalf("failed to create PR: %d: %s", resp.StatusCode, string(body))
+	}
+
+	var data struct {
+		PR struct {
+			Reviewers []string `json:"assigned_reviewers"`
+		} `json:"pr"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		t.Fatalf("failed to decode PR response: %v", err)
+	}
+
+	if len(data.PR.Reviewers) == 0 {
+		t.Fatal("no reviewers assigned")
+	}
+
+	old := data.PR.Reviewers[0]
+
+	for _, reason := range []string{"", "bored"} {
+		rejected := doPost(t, ts, "/pullRequest/reassign", fmt.Sprintf(
+			`{"pull_request_id": "PR-200", "old_reviewer_id": %q, "reason": %q}`, old, reason))
+		rejected.Body.Close()
+
+		if rejected.StatusCode != http.StatusBadRequest {
+			t.Fatalf("expected 400 for reason %q, got %d", reason, rejected.StatusCode)
+		}
+	}
+
+	body := fmt.Sprintf(`{
+		"pull_request_id": "PR-200",
+		"old_reviewer_id": "%s",
+		"reason": "vacation"
+	}`, old)
+
+	resp2 := doPost(t, ts, "/pullRequest/reassign", body)
+	defer resp2.Body.Close()
+
+	if resp2.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp2.Body)
+		t.Fatalf("expected 200, got %d: %s", resp2.StatusCode, string(body))
+	}
+
+	var out struct {
+		ReplacedBy string `json:"replaced_by"`
+	}
+
+	if err := json.NewDecoder(resp2.Body).Decode(&out); err != nil {
+		t.Fatalf("failed to decode reassign response: %v", err)
+	}
+
+	if out.ReplacedBy == old {
+		t.Fatalf("reviewer was NOT replaced: %s", out.ReplacedBy)
+	}
+
+	statsResp := doWithKey(t, ts, http.MethodGet, "/stats/prs", "", ts.AdminKey)
+	defer statsResp.Body.Close()
+
+	var stats struct {
+		Stats struct {
+			ReassignmentsByReason map[string]int `json:"reassignments_by_reason"`
+		} `json:"stats"`
+	}
+
+	if err := json.NewDecoder(statsResp.Body).Decode(&stats); err != nil {
+		t.Fatalf("failed to decode stats: %v", err)
+	}
+
+	if stats.Stats.ReassignmentsByReason["VACATION"] != 1 {
+		t.Fatalf("expected 1 VACATION reassignment, got %v", stats.Stats.ReassignmentsByReason)
+	}
+}
+
+func TestPullRequestHeldUntilCIGreen(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	resp := doPost(t, ts, "/team/update", `{"team_name":"Backend","hold_until_ci_green":true}`)
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("failed to update team policy: %d", resp.StatusCode)
+	}
+
+	resp = doPost(t, ts, "/pullRequest/create", `{
+		"pull_request_id": "PR-CI",
+		"pull_request_name": "Red build",
+		"author_id": "u1",
+		"ci_status": "PENDING"
+	}`)
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		t.Fatalf("expected 201, got %d: %s", resp.StatusCode, string(body))
+	}
+
+	var created struct {
+		PR struct {
+			AssignedReviewers []string `json:"assigned_reviewers"`
+		} `json:"pr"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if len(created.PR.AssignedReviewers) != 0 {
+		t.Fatalf("expected no reviewers before CI is green, got %d", len(created.PR.AssignedReviewers))
+	}
+
+	resp2 := doPost(t, ts, "/pullRequest/ciStatus", `{"pull_request_id":"PR-CI","ci_status":"SUCCESS"}`)
+	defer resp2.Body.Close()
+
+	if resp2.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp2.Body)
+		t.Fatalf("expected 200, got %d: %s", resp2.StatusCode, string(body))
+	}
+
+	var updated struct {
+		PR struct {
+			CIStatus          string   `json:"ci_status"`
+			AssignedReviewers []string `json:"assigned_reviewers"`
+		} `json:"pr"`
+	}
+	if err := json.NewDecoder(resp2.Body).Decode(&updated); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if updated.PR.CIStatus != "SUCCESS" {
+		t.Fatalf("expected SUCCESS ci_status, got %s", updated.PR.CIStatus)
+	}
+
+	if len(updated.PR.AssignedReviewers) != 2 {
+		t.Fatalf("expected 2 reviewers after green CI, got %d", len(updated.PR.AssignedReviewers))
+	}
+}
+
+func TestPullRequestManualAssign(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	resp := doPost(t, ts, "/pullRequest/create", `{
+		"pull_request_id": "PR-M1",
+		"pull_request_name": "Manual",
+		"author_id": "u1"
+	}`)
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("failed to create PR: %d", resp.StatusCode)
+	}
+
+	var created struct {
+		PR struct {
+			AssignedReviewers []string `json:"assigned_reviewers"`
+		} `json:"pr"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	assigned := make(map[string]bool)
+	for _, reviewer := range created.PR.AssignedReviewers {
+		assigned[reviewer] = true
+	}
+
+	var free string
+	for _, candidate := range []string{"u2", "u3", "u4", "u5"} {
+		if !assigned[candidate] {
+			free = candidate
+			break
+		}
+	}
+
+	otherTeam := doPost(t, ts, "/pullRequest/assign", `{"pull_request_id": "PR-M1", "reviewer_id": "u10"}`)
+	otherTeam.Body.Close()
+
+	if otherTeam.StatusCode != http.StatusConflict {
+		t.Fatalf("expected 409 for reviewer from another team, got %d", otherTeam.StatusCode)
+	}
+
+	assignResp := doPost(t, ts, "/pullRequest/assign", fmt.Sprintf(`{
+		"pull_request_id": "PR-M1",
+		"reviewer_id": "%s",
+		"replace_reviewer_id": "%s"
+	}`, free, created.PR.AssignedReviewers[0]))
+	defer assignResp.Body.Close()
+
+	if assignResp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(assignResp.Body)
+		t.Fatalf("expected 200, got %d: %s", assignResp.StatusCode, string(body))
+	}
+
+	var count int
+	err = ts.DB.Get(&count, `SELECT COUNT(*) FROM assignment_history WHERE pull_request_id = 'PR-M1' AND action = 'MANUAL'`)
+	if err != nil {
+		t.Fatalf("failed to query assignment history: %v", err)
+	}
+
+	if count != 1 {
+		t.Fatalf("expected 1 MANUAL history entry, got %d", count)
+	}
+}
+
+func TestPullRequestUnassign(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	resp := doPost(t, ts, "/pullRequest/create", `{
+		"pull_request_id": "PR-U1",
+		"pull_request_name": "Unassign",
+		"author_id": "u1"
+	}`)
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("failed to create PR: %d", resp.StatusCode)
+	}
+
+	var created struct {
+		PR struct {
+			AssignedReviewers []string `json:"assigned_reviewers"`
+		} `json:"pr"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if len(created.PR.AssignedReviewers) != 2 {
+		t.Fatalf("expected 2 reviewers, got %d", len(created.PR.AssignedReviewers))
+	}
+
+	first := doPost(t, ts, "/pullRequest/unassign", fmt.Sprintf(`{
+		"pull_request_id": "PR-U1",
+		"reviewer_id": "%s"
+	}`, created.PR.AssignedReviewers[0]))
+	first.Body.Close()
+
+	if first.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", first.StatusCode)
+	}
+
+	second := doPost(t, ts, "/pullRequest/unassign", fmt.Sprintf(`{
+		"pull_request_id": "PR-U1",
+		"reviewer_id": "%s"
+	}`, created.PR.AssignedReviewers[1]))
+	second.Body.Close()
+
+	if second.StatusCode != http.StatusConflict {
+		t.Fatalf("expected 409 below team minimum, got %d", second.StatusCode)
+	}
+}
+
+func TestPullRequestDelegate(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	resp := doPost(t, ts, "/pullRequest/create", `{
+		"pull_request_id": "PR-D1",
+		"pull_request_name": "Delegate",
+		"author_id": "u1"
+	}`)
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("failed to create PR: %d", resp.StatusCode)
+	}
+
+	var created struct {
+		PR struct {
+			AssignedReviewers []string `json:"assigned_reviewers"`
+		} `json:"pr"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	assigned := make(map[string]bool)
+	for _, reviewer := range created.PR.AssignedReviewers {
+		assigned[reviewer] = true
+	}
+
+	var free []string
+	for _, candidate := range []string{"u2", "u3", "u4", "u5"} {
+		if !assigned[candidate] {
+			free = append(free, candidate)
+		}
+	}
+
+	reviewer := created.PR.AssignedReviewers[0]
+
+	otherTeam := doPostAs(t, ts, "/pullRequest/delegate", `{"pull_request_id": "PR-D1", "delegate_id": "u10"}`, reviewer)
+	otherTeam.Body.Close()
+
+	if otherTeam.StatusCode != http.StatusConflict {
+		t.Fatalf("expected 409 for delegate from another team, got %d", otherTeam.StatusCode)
+	}
+
+	_, err = ts.DB.Exec(`
+		INSERT INTO pull_requests (pull_request_id, pull_request_name, author_id)
+		SELECT 'PR-L' || g, 'Load', 1 FROM generate_series(1, 3) g
+	`)
+	if err != nil {
+		t.Fatalf("failed to seed PRs: %v", err)
+	}
+
+	_, err = ts.DB.Exec(`
+		INSERT INTO pr_reviewers (pull_request_id, reviewer_id)
+		SELECT 'PR-L' || g, CAST(SUBSTRING($1 FROM 2) AS INTEGER) FROM generate_series(1, 3) g
+	`, free[0])
+	if err != nil {
+		t.Fatalf("failed to seed reviews: %v", err)
+	}
+
+	atCapacity := doPostAs(t, ts, "/pullRequest/delegate",
+		fmt.Sprintf(`{"pull_request_id": "PR-D1", "delegate_id": "%s"}`, free[0]), reviewer)
+	atCapacity.Body.Close()
+
+	if atCapacity.StatusCode != http.StatusConflict {
+		t.Fatalf("expected 409 for delegate at capacity, got %d", atCapacity.StatusCode)
+	}
+
+	delegateResp := doPostAs(t, ts, "/pullRequest/delegate",
+		fmt.Sprintf(`{"pull_request_id": "PR-D1", "delegate_id": "%s"}`, free[1]), reviewer)
+	defer delegateResp.Body.Close()
+
+	if delegateResp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(delegateResp.Body)
+		t.Fatalf("expected 200, got %d: %s", delegateResp.StatusCode, string(body))
+	}
+
+	var delegated struct {
+		PR struct {
+			AssignedReviewers []string `json:"assigned_reviewers"`
+		} `json:"pr"`
+		DelegatedTo string `json:"delegated_to"`
+	}
+
+	if err := json.NewDecoder(delegateResp.Body).Decode(&delegated); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	for _, r := range delegated.PR.AssignedReviewers {
+		if r == reviewer {
+			t.Fatalf("expected %s to be replaced, got %v", reviewer, delegated.PR.AssignedReviewers)
+		}
+	}
+
+	if delegated.DelegatedTo != free[1] {
+		t.Fatalf("expected delegated_to %s, got %s", free[1], delegated.DelegatedTo)
+	}
+
+	statsResp := doWithKey(t, ts, http.MethodGet, "/stats/prs", "", ts.AdminKey)
+	defer statsResp.Body.Close()
+
+	var stats struct {
+		Stats struct {
+			AssignmentsByAction map[string]int `json:"assignments_by_action"`
+		} `json:"stats"`
+	}
+
+	if err := json.NewDecoder(statsResp.Body).Decode(&stats); err != nil {
+		t.Fatalf("failed to decode stats: %v", err)
+	}
+
+	byAction := stats.Stats.AssignmentsByAction
+	if byAction["DELEGATE"] != 1 || byAction["REASSIGN"] != 0 {
+		t.Fatalf("expected 1 DELEGATE and no REASSIGN entries, got %v", byAction)
+	}
+}
+
+func TestPullRequestTeamTemplate(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	update := doPost(t, ts, "/team/update", `{
+		"team_name": "Backend",
+		"default_priority": "HIGH",
+		"default_labels": ["backend"],
+		"default_required_skills": ["go"]
+	}`)
+	update.Body.Close()
+
+	if update.StatusCode != http.StatusOK {
+		t.Fatalf("failed to update team: %d", update.StatusCode)
+	}
+
+	resp := doPost(t, ts, "/pullRequest/create", `{
+		"pull_request_id": "PR-T1",
+		"pull_request_name": "Template",
+		"author_id": "u1",
+		"labels": ["security", "backend"]
+	}`)
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		t.Fatalf("expected 201, got %d: %s", resp.StatusCode, string(body))
+	}
+
+	var data struct {
+		PR struct {
+			Priority       string   `json:"priority"`
+			Labels         []string `json:"labels"`
+			RequiredSkills []string `json:"required_skills"`
+		} `json:"pr"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if data.PR.Priority != "HIGH" {
+		t.Fatalf("expected default priority HIGH, got %s", data.PR.Priority)
+	}
+
+	if len(data.PR.Labels) != 2 || data.PR.Labels[0] != "backend" || data.PR.Labels[1] != "security" {
+		t.Fatalf("expected merged labels [backend security], got %v", data.PR.Labels)
+	}
+
+	if len(data.PR.RequiredSkills) != 1 || data.PR.RequiredSkills[0] != "go" {
+		t.Fatalf("expected required skills [go], got %v", data.PR.RequiredSkills)
+	}
+}
+
+func TestPullRequestStartReview(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	resp := doPost(t, ts, "/pullRequest/create", `{
+		"pull_request_id": "PR-S1",
+		"pull_request_name": "Start",
+		"author_id": "u10"
+	}`)
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("failed to create PR: %d", resp.StatusCode)
+	}
+
+	start := doPost(t, ts, "/pullRequest/startReview", `{"pull_request_id": "PR-S1", "reviewer_id": "u11"}`)
+	start.Body.Close()
+
+	if start.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", start.StatusCode)
+	}
+
+	notAssigned := doPost(t, ts, "/pullRequest/startReview", `{"pull_request_id": "PR-S1", "reviewer_id": "u1"}`)
+	notAssigned.Body.Close()
+
+	if notAssigned.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected 404 for non-assigned reviewer, got %d", notAssigned.StatusCode)
+	}
+
+	resp = doGet(t, ts, "/users/getReview?user_id=u11")
+	defer resp.Body.Close()
+
+	var data struct {
+		PullRequests []struct {
+			PullRequestID string `json:"pull_request_id"`
+			ReviewState   string `json:"review_state"`
+		} `json:"pull_requests"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if len(data.PullRequests) != 1 || data.PullRequests[0].ReviewState != "IN_PROGRESS" {
+		t.Fatalf("expected PR-S1 in progress, got %+v", data.PullRequests)
+	}
+}
+
+func TestPullRequestCompleteReview(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	resp := doPost(t, ts, "/pullRequest/create", `{
+		"pull_request_id": "PR-C1",
+		"pull_request_name": "Complete",
+		"author_id": "u10"
+	}`)
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("failed to create PR: %d", resp.StatusCode)
+	}
+
+	complete := doPost(t, ts, "/pullRequest/completeReview", `{"pull_request_id": "PR-C1", "reviewer_id": "u11"}`)
+	defer complete.Body.Close()
+
+	if complete.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", complete.StatusCode)
+	}
+
+	var completed struct {
+		ReviewState string `json:"review_state"`
+	}
+	if err := json.NewDecoder(complete.Body).Decode(&completed); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if completed.ReviewState != "COMPLETED" {
+		t.Fatalf("expected COMPLETED, got %s", completed.ReviewState)
+	}
+
+	review := doGet(t, ts, "/users/getReview?user_id=u11")
+	defer review.Body.Close()
+
+	var reviewData struct {
+		PullRequests []struct {
+			ReviewState string `json:"review_state"`
+		} `json:"pull_requests"`
+	}
+	if err := json.NewDecoder(review.Body).Decode(&reviewData); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if len(reviewData.PullRequests) != 1 || reviewData.PullRequests[0].ReviewState != "COMPLETED" {
+		t.Fatalf("expected PR-C1 completed, got %+v", reviewData.PullRequests)
+	}
+
+	mine := doGetAs(t, ts, "/users/myReviews", "u11")
+	defer mine.Body.Close()
+
+	var mineData struct {
+		Reviews []struct {
+			PullRequestID string `json:"pull_request_id"`
+		} `json:"reviews"`
+	}
+	if err := json.NewDecoder(mine.Body).Decode(&mineData); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if len(mineData.Reviews) != 0 {
+		t.Fatalf("expected completed review to leave the workload, got %+v", mineData.Reviews)
+	}
+}
+
+func TestUserSetIsActive(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	resp := doPost(t, ts, "/users/setIsActive", `{"user_id":"u2","is_active":false}`)
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		t.Fatalf("expected 200, got %d: %s", resp.StatusCode, string(body))
+	}
+
+	var data struct {
+		User struct {
+			UserID   string `json:"user_id"`
+			IsActive bool   `json:"is_active"`
+		} `json:"user"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if data.User.IsActive {
+		t.Fatalf("user should be inactive")
+	}
+}
+
+func TestUserSetIsActiveBatch(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	resp := doPost(t, ts, "/users/setIsActiveBatch", `{"user_ids":["u2","u3","u404","x"],"is_active":false}`)
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		t.Fatalf("expected 200, got %d: %s", resp.StatusCode, string(body))
+	}
+
+	var data struct {
+		Updated []struct {
+			UserID   string `json:"user_id"`
+			IsActive bool   `json:"is_active"`
+		} `json:"updated"`
+		Failed []struct {
+			UserID string `json:"user_id"`
+			Code   string `json:"code"`
+		} `json:"failed"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if len(data.Updated) != 2 {
+		t.Fatalf("expected 2 updated users, got %d", len(data.Updated))
+	}
+
+	for _, user := range data.Updated {
+		if user.IsActive {
+			t.Fatalf("user %s should be inactive", user.UserID)
+		}
+	}
+
+	if len(data.Failed) != 2 {
+		t.Fatalf("expected 2 failures, got %+v", data.Failed)
+	}
+}
+
+func TestUserGetReview(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	resp := doPost(t, ts, "/pullRequest/create", `{
+		"pull_request_id": "PR-999",
+		"pull_request_name": "Fix",
+		"author_id": "u1"
+	}`)
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		t.Fatalf("failed to create PR: %d: %s", resp.StatusCode, string(body))
+	}
+
+	resp = doGet(t, ts, "/users/getReview?user_id=u2")
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		t.Fatalf("expected 200, got %d: %s", resp.StatusCode, string(body))
+	}
+
+	var data struct {
+		PullRequests []struct {
+			PullRequestID string `json:"pull_request_id"`
+		} `json:"pull_requests"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if len(data.PullRequests) == 0 {
+		t.Fatalf("expected at least 1 PR in review list")
+	}
+}
+
+func TestMalformedUserIDs(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	expectInvalid := func(resp *http.Response, what string) {
+		t.Helper()
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusBadRequest {
+			body, _ := io.ReadAll(resp.Body)
+			t.Fatalf("%s: expected 400, got %d: %s", what, resp.StatusCode, string(body))
+		}
+
+		var data httpio.ErrorResponse
+		if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+			t.Fatalf("%s: failed to decode response: %v", what, err)
+		}
+		if data.Error.Code != "INVALID_USER_ID" {
+			t.Fatalf("%s: expected INVALID_USER_ID, got %q", what, data.Error.Code)
+		}
+	}
+
+	for _, userID := range []string{"u", "x", "u1x", "u-1", "u+1", "u99999999999"} {
+		expectInvalid(doGet(t, ts, "/users/getReview?user_id="+url.QueryEscape(userID)), "getReview "+userID)
+		expectInvalid(doGet(t, ts, "/users/getReview?wait=1s&user_id="+url.QueryEscape(userID)), "getReview wait "+userID)
+		expectInvalid(doPost(t, ts, "/users/setIsActive",
+			fmt.Sprintf(`{"user_id":%q,"is_active":false}`, userID)), "setIsActive "+userID)
+	}
+
+	resp := doPost(t, ts, "/users/setIsActiveBatch", `{"user_ids":["u","u2x"],"is_active":false}`)
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		t.Fatalf("expected 200, got %d: %s", resp.StatusCode, string(body))
+	}
+
+	var batch struct {
+		Updated []json.RawMessage `json:"updated"`
+		Failed  []struct {
+			Code string `json:"code"`
+		} `json:"failed"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&batch); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(batch.Updated) != 0 || len(batch.Failed) != 2 {
+		t.Fatalf("expected both ids to fail, got %d updated and %+v", len(batch.Updated), batch.Failed)
+	}
+	for _, failure := range batch.Failed {
+		if failure.Code != "INVALID_USER_ID" {
+			t.Fatalf("expected INVALID_USER_ID, got %q", failure.Code)
+		}
+	}
+}
+
+func TestUserGetReviewLongPoll(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	resp := doPost(t, ts, "/pullRequest/create", `{"pull_request_id": "PR-W1", "pull_request_name": "Wait", "author_id": "u1"}`)
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("failed to create PR: %d", resp.StatusCode)
+	}
+
+	var created struct {
+		PR struct {
+			AssignedReviewers []string `json:"assigned_reviewers"`
+		} `json:"pr"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	reviewer := created.PR.AssignedReviewers[0]
+
+	type pollResult struct {
+		status  int
+		changed bool
+		elapsed time.Duration
+	}
+
+	poll := func(wait string) <-chan pollResult {
+		result := make(chan pollResult, 1)
+		go func() {
+			started := time.Now()
+			resp, err := http.Get(ts.Server.URL + "/users/getReview?user_id=" + reviewer + "&wait=" + wait)
+			if err != nil {
+				result <- pollResult{}
+				return
+			}
+			defer resp.Body.Close()
+
+			var body struct {
+				Changed bool `json:"changed"`
+			}
+			_ = json.NewDecoder(resp.Body).Decode(&body)
+			result <- pollResult{status: resp.StatusCode, changed: body.Changed, elapsed: time.Since(started)}
+		}()
+		return result
+	}
+
+	waiting := poll("10s")
+	time.Sleep(300 * time.Millisecond)
+
+	start := doPost(t, ts, "/pullRequest/startReview", fmt.Sprintf(`{"pull_request_id": "PR-W1", "reviewer_id": "%s"}`, reviewer))
+	start.Body.Close()
+
+	result := <-waiting
+	if result.status != http.StatusOK || !result.changed || result.elapsed >= 10*time.Second {
+		t.Fatalf("expected long-poll to return early with a change, got %+v", result)
+	}
+
+	idle := <-poll("1s")
+	if idle.status != http.StatusOK || idle.changed {
+		t.Fatalf("expected long-poll to time out without a change, got %+v", idle)
+	}
+
+	tooLong := doGet(t, ts, "/users/getReview?user_id="+reviewer+"&wait=5m")
+	tooLong.Body.Close()
+
+	if tooLong.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected 400 for wait above the limit, got %d", tooLong.StatusCode)
+	}
+}
+
+func TestAdminArchiveAndRestore(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	resp := doPost(t, ts, "/team/archive?team_name=QA", `{}`)
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("failed to archive team: %d", resp.StatusCode)
+	}
+
+	resp = doGet(t, ts, "/admin/archive")
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		t.Fatalf("expected 200, got %d: %s", resp.StatusCode, string(body))
+	}
+
+	var data struct {
+		Teams []struct {
+			TeamName    string `json:"team_name"`
+			MemberCount int    `json:"member_count"`
+		} `json:"teams"`
+		Users []struct {
+			UserID string `json:"user_id"`
+		} `json:"users"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if len(data.Teams) != 1 || data.Teams[0].TeamName != "QA" || data.Teams[0].MemberCount != 2 {
+		t.Fatalf("expected archived QA team with 2 members, got %+v", data.Teams)
+	}
+
+	if len(data.Users) != 2 {
+		t.Fatalf("expected 2 deactivated users, got %d", len(data.Users))
+	}
+
+	resp2 := doPost(t, ts, "/admin/restore", `{"team_name":"QA"}`)
+	resp2.Body.Close()
+
+	if resp2.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200 on restore, got %d", resp2.StatusCode)
+	}
+
+	resp3 := doPost(t, ts, "/admin/restore", `{"team_name":"QA"}`)
+	resp3.Body.Close()
+
+	if resp3.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected 404 on second restore, got %d", resp3.StatusCode)
+	}
+}
+
+func TestAdminMigrations(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	resp := doGet(t, ts, "/admin/migrations")
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+
+	var data struct {
+		Migrations struct {
+			Version       uint `json:"version"`
+			Dirty         bool `json:"dirty"`
+			Latest        uint `json:"latest"`
+			Pending       int  `json:"pending"`
+			MinCompatible uint `json:"min_compatible"`
+			Compatible    bool `json:"compatible"`
+		} `json:"migrations"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if data.Migrations.Dirty || data.Migrations.Pending != 0 || data.Migrations.Version != data.Migrations.Latest {
+		t.Fatalf("expected a clean, up to date schema, got %+v", data.Migrations)
+	}
+	if !data.Migrations.Compatible || data.Migrations.MinCompatible > data.Migrations.Version {
+		t.Fatalf("expected schema to be compatible with this build, got %+v", data.Migrations)
+	}
+}
+
+func TestAdminMembershipConsistency(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	_, err = ts.DB.Exec(`
+		DELETE FROM team_members WHERE user_id = 2;
+		INSERT INTO team_members (team_name, user_id) VALUES ('QA', 3);
+	`)
+	if err != nil {
+		t.Fatalf("failed to introduce drift: %v", err)
+	}
+
+	type drift struct {
+		UserID   string `json:"user_id"`
+		TeamName string `json:"team_name"`
+		Kind     string `json:"kind"`
+	}
+
+	check := func(resp *http.Response) []drift {
+		t.Helper()
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			t.Fatalf("expected 200, got %d: %s", resp.StatusCode, string(body))
+		}
+
+		var data struct {
+			Membership struct {
+				Drift []drift `json:"drift"`
+			} `json:"membership"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		return data.Membership.Drift
+	}
+
+	expected := []drift{
+		{UserID: "u2", TeamName: "Backend", Kind: "MISSING_MEMBERSHIP"},
+		{UserID: "u3", TeamName: "QA", Kind: "STALE_MEMBERSHIP"},
+	}
+	matches := func(got []drift) bool {
+		if len(got) != len(expected) {
+			return false
+		}
+		seen := make(map[drift]bool, len(got))
+		for _, d := range got {
+			seen[d] = true
+		}
+		for _, d := range expected {
+			if !seen[d] {
+				return false
+			}
+		}
+		return true
+	}
+
+	if got := check(doGet(t, ts, "/admin/membership")); !matches(got) {
+		t.Fatalf("expected drift %+v, got %+v", expected, got)
+	}
+
+	if got := check(doPost(t, ts, "/admin/membership/repair", `{}`)); !matches(got) {
+		t.Fatalf("expected repair of %+v, got %+v", expected, got)
+	}
+
+	if got := check(doGet(t, ts, "/admin/membership")); len(got) != 0 {
+		t.Fatalf("expected no drift after repair, got %+v", got)
+	}
+}
+
+func TestAPITokens(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	issue := func(body string) (int64, string) {
+		t.Helper()
+		resp := doPost(t, ts, "/admin/tokens/issue", body)
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("expected 201 on issue, got %d", resp.StatusCode)
+		}
+
+		var issued struct {
+			Token struct {
+				TokenID int64 `json:"token_id"`
+			} `json:"token"`
+			Key string `json:"key"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&issued); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		return issued.Token.TokenID, issued.Key
+	}
+
+	expectStatus := func(method string, path string, body string, key string, want int) {
+		t.Helper()
+		resp := doWithKey(t, ts, method, path, body, key)
+		resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Fatalf("%s %s: expected %d, got %d", method, path, want, resp.StatusCode)
+		}
+	}
+
+	readerID, readerKey := issue(`{"name": "dashboard", "user_id": "u1", "scopes": ["read"]}`)
+	_, adminKey := issue(`{"name": "ops", "user_id": "u2", "scopes": ["admin"]}`)
+
+	invalid := doPost(t, ts, "/admin/tokens/issue", `{"name": "bad", "user_id": "u1", "scopes": ["root"]}`)
+	invalid.Body.Close()
+	if invalid.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected 400 for unknown scope, got %d", invalid.StatusCode)
+	}
+
+	for body, want := range map[string]int{
+		`{"name": "nobody", "scopes": ["read"]}`:                   http.StatusBadRequest,
+		`{"name": "ghost", "user_id": "u999", "scopes": ["read"]}`: http.StatusNotFound,
+	} {
+		resp := doPost(t, ts, "/admin/tokens/issue", body)
+		resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Fatalf("expected %d for %s, got %d", want, body, resp.StatusCode)
+		}
+	}
+
+	expectStatus(http.MethodGet, "/team/get?team_name=Backend", "", readerKey, http.StatusOK)
+	expectStatus(http.MethodPost, "/users/setIsActive", `{"user_id": "u2", "is_active": false}`, readerKey, http.StatusForbidden)
+	expectStatus(http.MethodGet, "/admin/tokens/list", "", readerKey, http.StatusForbidden)
+	expectStatus(http.MethodGet, "/admin/tokens/list", "", adminKey, http.StatusOK)
+	expectStatus(http.MethodGet, "/team/get?team_name=Backend", "", "pra_unknown", http.StatusUnauthorized)
+
+	rotate := doWithKey(t, ts, http.MethodPost, "/admin/tokens/rotate", fmt.Sprintf(`{"token_id": %d}`, readerID), adminKey)
+	defer rotate.Body.Close()
+
+	if rotate.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200 on rotate, got %d", rotate.StatusCode)
+	}
+
+	var rotated struct {
+		Key string `json:"key"`
+	}
+	if err := json.NewDecoder(rotate.Body).Decode(&rotated); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	expectStatus(http.MethodGet, "/team/get?team_name=Backend", "", readerKey, http.StatusUnauthorized)
+	expectStatus(http.MethodGet, "/team/get?team_name=Backend", "", rotated.Key, http.StatusOK)
+
+	expectStatus(http.MethodPost, "/admin/tokens/revoke", fmt.Sprintf(`{"token_id": %d}`, readerID), adminKey, http.StatusOK)
+	expectStatus(http.MethodGet, "/team/get?team_name=Backend", "", rotated.Key, http.StatusUnauthorized)
+	expectStatus(http.MethodPost, "/admin/tokens/rotate", fmt.Sprintf(`{"token_id": %d}`, readerID), adminKey, http.StatusConflict)
+
+	// Keys stop working while their user is inactive.
+	_, u3Key := issue(`{"name": "cli", "user_id": "u3", "scopes": ["read"]}`)
+	expectStatus(http.MethodPost, "/users/setIsActive", `{"user_id": "u3", "is_active": false}`, adminKey, http.StatusOK)
+	expectStatus(http.MethodGet, "/team/get?team_name=Backend", "", u3Key, http.StatusUnauthorized)
+	expectStatus(http.MethodPost, "/users/setIsActive", `{"user_id": "u3", "is_active": true}`, adminKey, http.StatusOK)
+	expectStatus(http.MethodGet, "/team/get?team_name=Backend", "", u3Key, http.StatusOK)
+
+	var stored int
+	err = ts.DB.Get(&stored, `SELECT COUNT(*) FROM api_tokens WHERE token_hash = $1`, adminKey)
+	if err != nil {
+		t.Fatalf("failed to query tokens: %v", err)
+	}
+
+	if stored != 0 {
+		t.Fatalf("expected raw keys not to be stored")
+	}
+}
+
+func TestAdminBootstrap(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	bootstrap := func(secret string) (int, string) {
+		t.Helper()
+		resp, err := http.Post(ts.Server.URL+"/admin/tokens/bootstrap", "application/json",
+			strings.NewReader(`{"name": "ops", "user_id": "u1", "secret": "`+secret+`"}`))
+		if err != nil {
+			t.Fatalf("bootstrap failed: %v", err)
+		}
+		defer resp.Body.Close()
+
+		var issued struct {
+			Key string `json:"key"`
+		}
+		if resp.StatusCode == http.StatusCreated {
+			if err := json.NewDecoder(resp.Body).Decode(&issued); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+		}
+		return resp.StatusCode, issued.Key
+	}
+
+	for _, path := range []string{"/admin/tokens/list", "/admin/usage"} {
+		resp, err := http.Get(ts.Server.URL + path)
+		if err != nil {
+			t.Fatalf("GET %s failed: %v", path, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusUnauthorized {
+			t.Fatalf("expected 401 for %s without a key, got %d", path, resp.StatusCode)
+		}
+	}
+
+	resp, err := http.Post(ts.Server.URL+"/users/offboard", "application/json", strings.NewReader(`{"user_id": "u2"}`))
+	if err != nil {
+		t.Fatalf("offboard failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected 401 for offboarding without a key, got %d", resp.StatusCode)
+	}
+
+	if status, _ := bootstrap("test-secret"); status != http.StatusConflict {
+		t.Fatalf("expected 409 while the fixture admin key is active, got %d", status)
+	}
+
+	if _, err := ts.DB.Exec(`UPDATE api_tokens SET revoked_at = NOW()`); err != nil {
+		t.Fatalf("failed to revoke tokens: %v", err)
+	}
+
+	if status, _ := bootstrap("wrong-secret"); status != http.StatusUnauthorized {
+		t.Fatalf("expected 401 for a wrong secret, got %d", status)
+	}
+
+	status, key := bootstrap("test-secret")
+	if status != http.StatusCreated {
+		t.Fatalf("expected 201 on bootstrap, got %d", status)
+	}
+
+	list := doWithKey(t, ts, http.MethodGet, "/admin/tokens/list", "", key)
+	list.Body.Close()
+	if list.StatusCode != http.StatusOK {
+		t.Fatalf("expected the bootstrapped key to be admin, got %d", list.StatusCode)
+	}
+
+	if status, _ := bootstrap("test-secret"); status != http.StatusConflict {
+		t.Fatalf("expected bootstrap to work once, got %d", status)
+	}
+}
+
+func TestAdminImpersonation(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	resp := doPost(t, ts, "/pullRequest/create", `{"pull_request_id": "PR-I1", "pull_request_name": "Impersonate", "author_id": "u1"}`)
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("failed to create PR: %d", resp.StatusCode)
+	}
+
+	var created struct {
+		PR struct {
+			AssignedReviewers []string `json:"assigned_reviewers"`
+		} `json:"pr"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	reviewer := created.PR.AssignedReviewers[0]
+
+	anonymous := doPost(t, ts, "/admin/impersonation/start", fmt.Sprintf(`{"user_id": "%s", "reason": "ticket 42"}`, reviewer))
+	anonymous.Body.Close()
+	if anonymous.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without admin identity, got %d", anonymous.StatusCode)
+	}
+
+	issued := doPost(t, ts, "/admin/tokens/issue", `{"name": "support", "user_id": "u10", "scopes": ["admin"]}`)
+	defer issued.Body.Close()
+
+	var support struct {
+		Key string `json:"key"`
+	}
+	if err := json.NewDecoder(issued.Body).Decode(&support); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	start := doWithKey(t, ts, http.MethodPost, "/admin/impersonation/start", fmt.Sprintf(`{"user_id": "%s", "reason": "ticket 42"}`, reviewer), support.Key)
+	defer start.Body.Close()
+
+	if start.StatusCode != http.StatusCreated {
+		t.Fatalf("expected 201 on start, got %d", start.StatusCode)
+	}
+
+	var started struct {
+		Session struct {
+			SessionID int64 `json:"session_id"`
+		} `json:"session"`
+		Key string `json:"key"`
+	}
+	if err := json.NewDecoder(start.Body).Decode(&started); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	reviews := doWithHeader(t, ts, http.MethodGet, "/users/myReviews", "", "X-Impersonation-Key", started.Key)
+	defer reviews.Body.Close()
+
+	if reviews.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200 on myReviews, got %d", reviews.StatusCode)
+	}
+
+	var queue struct {
+		UserID  string `json:"user_id"`
+		Reviews []struct {
+			PullRequestID string `json:"pull_request_id"`
+		} `json:"reviews"`
+	}
+	if err := json.NewDecoder(reviews.Body).Decode(&queue); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if queue.UserID != reviewer || len(queue.Reviews) != 1 || queue.Reviews[0].PullRequestID != "PR-I1" {
+		t.Fatalf("expected %s's queue with PR-I1, got %+v", reviewer, queue)
+	}
+
+	mutation := doWithHeader(t, ts, http.MethodPost, "/pullRequest/merge", `{"pull_request_id": "PR-I1"}`, "X-Impersonation-Key", started.Key)
+	mutation.Body.Close()
+	if mutation.StatusCode != http.StatusForbidden {
+		t.Fatalf("expected 403 for mutation while impersonating, got %d", mutation.StatusCode)
+	}
+
+	end := doWithKey(t, ts, http.MethodPost, "/admin/impersonation/end", fmt.Sprintf(`{"session_id": %d}`, started.Session.SessionID), support.Key)
+	end.Body.Close()
+	if end.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200 on end, got %d", end.StatusCode)
+	}
+
+	ended := doWithHeader(t, ts, http.MethodGet, "/users/myReviews", "", "X-Impersonation-Key", started.Key)
+	ended.Body.Close()
+	if ended.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected 401 after session end, got %d", ended.StatusCode)
+	}
+
+	var audited int
+	err = ts.DB.Get(&audited, `
+		SELECT COUNT(*) FROM audit_events
+		WHERE action IN ('IMPERSONATION_STARTED', 'IMPERSONATION_ENDED') AND actor_id = 10
+	`)
+	if err != nil {
+		t.Fatalf("failed to query audit events: %v", err)
+	}
+
+	if audited != 2 {
+		t.Fatalf("expected 2 impersonation audit events, got %d", audited)
+	}
+}
+
+func TestStatsTeamsBatch(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	resp := doPost(t, ts, "/pullRequest/create", `{
+		"pull_request_id": "PR-STATS",
+		"pull_request_name": "Stats",
+		"author_id": "u1"
+	}`)
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("failed to create PR: %d", resp.StatusCode)
+	}
+
+	resp = doWithKey(t, ts, http.MethodPost, "/stats/teams", `{"team_names":["QA","Backend","Unknown"]}`, ts.AdminKey)
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		t.Fatalf("expected 200, got %d: %s", resp.StatusCode, string(body))
+	}
+
+	var data struct {
+		Teams []struct {
+			TeamName          string  `json:"team_name"`
+			TotalPRs          int     `json:"total_prs"`
+			OpenPRs           int     `json:"open_prs"`
+			AvgReviewersPerPR float64 `json:"avg_reviewers_per_pr"`
+		} `json:"teams"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if len(data.Teams) != 3 {
+		t.Fatalf("expected 3 teams, got %d", len(data.Teams))
+	}
+
+	for _, team := range data.Teams {
+		switch team.TeamName {
+		case "Backend":
+			if team.TotalPRs != 1 || team.OpenPRs != 1 || team.AvgReviewersPerPR != 2 {
+				t.Fatalf("unexpected Backend stats: %+v", team)
+			}
+		default:
+			if team.TotalPRs != 0 {
+				t.Fatalf("expected no PRs for %s, got %d", team.TeamName, team.TotalPRs)
+			}
+		}
+	}
+}
+
+func TestStatsCycleTime(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	_, err = ts.DB.Exec(`
+		INSERT INTO pull_requests (pull_request_id, pull_request_name, author_id, status, created_at, merged_at) VALUES
+			('PR-CT1', 'One', 1, 'MERGED', NOW() - INTERVAL '2 days', NOW() - INTERVAL '2 days' + INTERVAL '1 hour'),
+			('PR-CT2', 'Two', 2, 'MERGED', NOW() - INTERVAL '2 days', NOW() - INTERVAL '2 days' + INTERVAL '2 hours'),
+			('PR-CT3', 'Three', 3, 'MERGED', NOW() - INTERVAL '2 days', NOW() - INTERVAL '2 days' + INTERVAL '3 hours'),
+			('PR-CT4', 'Four', 10, 'MERGED', NOW() - INTERVAL '2 days', NOW() - INTERVAL '2 days' + INTERVAL '10 hours'),
+			('PR-CT5', 'Old', 1, 'MERGED', NOW() - INTERVAL '100 days', NOW() - INTERVAL '99 days'),
+			('PR-CT6', 'Open', 1, 'OPEN', NOW() - INTERVAL '1 day', NULL);
+	`)
+	if err != nil {
+		t.Fatalf("failed to seed PRs: %v", err)
+	}
+
+	type cycleTime struct {
+		TeamName  string  `json:"team_name"`
+		MergedPRs int     `json:"merged_prs"`
+		P50       float64 `json:"p50_seconds"`
+		P90       float64 `json:"p90_seconds"`
+		P99       float64 `json:"p99_seconds"`
+	}
+
+	type cycleTimeResponse struct {
+		CycleTime struct {
+			WindowDays int         `json:"window_days"`
+			Overall    cycleTime   `json:"overall"`
+			Teams      []cycleTime `json:"teams"`
+		} `json:"cycle_time"`
+		Error struct {
+			Code string `json:"code"`
+		} `json:"error"`
+	}
+
+	get := func(query string) (int, cycleTimeResponse) {
+		t.Helper()
+		resp := doWithKey(t, ts, http.MethodGet, "/stats/cycleTime"+query, "", ts.AdminKey)
+		defer resp.Body.Close()
+
+		var data cycleTimeResponse
+		if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		return resp.StatusCode, data
+	}
+
+	status, data := get("")
+	if status != http.StatusOK {
+		t.Fatalf("expected 200, got %d", status)
+	}
+	if data.CycleTime.WindowDays != 30 || data.CycleTime.Overall.MergedPRs != 4 {
+		t.Fatalf("expected 4 merged PRs in the default window, got %+v", data.CycleTime)
+	}
+	if len(data.CycleTime.Teams) != 2 || data.CycleTime.Teams[0].TeamName != "Backend" || data.CycleTime.Teams[1].TeamName != "QA" {
+		t.Fatalf("expected Backend and QA cycle times, got %+v", data.CycleTime.Teams)
+	}
+
+	backend := data.CycleTime.Teams[0]
+	if backend.MergedPRs != 3 || backend.P50 != 7200 {
+		t.Fatalf("expected Backend p50 of 2h over 3 PRs, got %+v", backend)
+	}
+	if backend.P90 < backend.P50 || backend.P99 < backend.P90 || backend.P99 > 3*3600 {
+		t.Fatalf("expected ordered Backend percentiles within 3h, got %+v", backend)
+	}
+	if qa := data.CycleTime.Teams[1]; qa.MergedPRs != 1 || qa.P50 != 10*3600 {
+		t.Fatalf("expected QA p50 of 10h, got %+v", qa)
+	}
+
+	if _, data := get("?window_days=200"); data.CycleTime.Overall.MergedPRs != 5 {
+		t.Fatalf("expected the old PR within 200 days, got %+v", data.CycleTime.Overall)
+	}
+
+	for _, query := range []string{"?window_days=0", "?window_days=abc", "?window_days=1000"} {
+		if status, data := get(query); status != http.StatusBadRequest || data.Error.Code != "INVALID_WINDOW" {
+			t.Fatalf("%s: expected 400 INVALID_WINDOW, got %d %s", query, status, data.Error.Code)
+		}
+	}
+}
+
+func TestStatsTagBreakdown(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	_, err = ts.DB.Exec(`
+		INSERT INTO pull_requests (pull_request_id, pull_request_name, author_id, status, created_at, merged_at, labels, required_skills) VALUES
+			('PR-L1', 'One', 1, 'MERGED', NOW() - INTERVAL '2 days', NOW() - INTERVAL '2 days' + INTERVAL '1 hour', '{backend}', '{go}'),
+			('PR-L2', 'Two', 1, 'MERGED', NOW() - INTERVAL '2 days', NOW() - INTERVAL '2 days' + INTERVAL '3 hours', '{backend}', '{}'),
+			('PR-L3', 'Three', 2, 'OPEN', NOW() - INTERVAL '1 day', NULL, '{backend,ui}', '{go}'),
+			('PR-L4', 'Old', 1, 'MERGED', NOW() - INTERVAL '100 days', NOW() - INTERVAL '99 days', '{backend}', '{}');
+		INSERT INTO pr_reviewers (pull_request_id, reviewer_id) VALUES
+			('PR-L1', 3), ('PR-L2', 3), ('PR-L3', 3), ('PR-L3', 4);
+	`)
+	if err != nil {
+		t.Fatalf("failed to seed PRs: %v", err)
+	}
+
+	resp := doWithKey(t, ts, http.MethodGet, "/stats/labels", "", ts.AdminKey)
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+
+	type tagStats struct {
+		Tag              string  `json:"tag"`
+		TotalPRs         int     `json:"total_prs"`
+		OpenPRs          int     `json:"open_prs"`
+		MergedPRs        int     `json:"merged_prs"`
+		MergeP50         float64 `json:"merge_p50_seconds"`
+		TopReviewerShare float64 `json:"top_reviewer_share"`
+		Reviewers        []struct {
+			UserID  string `json:"user_id"`
+			Reviews int    `json:"reviews"`
+		} `json:"reviewers"`
+	}
+
+	var data struct {
+		Stats struct {
+			Labels []tagStats `json:"labels"`
+			Skills []tagStats `json:"skills"`
+		} `json:"stats"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if len(data.Stats.Labels) != 2 || len(data.Stats.Skills) != 1 {
+		t.Fatalf("expected labels backend, ui and skill go, got %+v", data.Stats)
+	}
+
+	backend := data.Stats.Labels[0]
+	if backend.Tag != "backend" || backend.TotalPRs != 3 || backend.OpenPRs != 1 || backend.MergedPRs != 2 || backend.MergeP50 != 2*3600 {
+		t.Fatalf("unexpected backend label stats %+v", backend)
+	}
+	if len(backend.Reviewers) != 2 || backend.Reviewers[0].UserID != "u3" || backend.Reviewers[0].Reviews != 3 || backend.TopReviewerShare != 0.75 {
+		t.Fatalf("expected u3 to hold 3 of 4 backend reviews, got %+v", backend)
+	}
+
+	if skill := data.Stats.Skills[0]; skill.Tag != "go" || skill.TotalPRs != 2 {
+		t.Fatalf("unexpected go skill stats %+v", skill)
+	}
+
+	resp = doWithKey(t, ts, http.MethodGet, "/stats/labels?window_days=0", "", ts.AdminKey)
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected 400 for window_days=0, got %d", resp.StatusCode)
+	}
+}
+
+func TestStatsHistory(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	_, err = ts.DB.Exec(`
+		INSERT INTO pull_requests (pull_request_id, pull_request_name, author_id, status) VALUES
+			('PR-H1', 'Open', 1, 'OPEN'),
+			('PR-H2', 'Merged', 10, 'MERGED');
+		INSERT INTO pr_reviewers (pull_request_id, reviewer_id) VALUES
+			('PR-H1', 2), ('PR-H1', 3), ('PR-H2', 11);
+		INSERT INTO stats_history (snapshot_date, team_name, total_prs, open_prs) VALUES
+			(CURRENT_DATE - 3, 'Backend', 7, 7),
+			(CURRENT_DATE - 1, 'Backend', 5, 4);
+	`)
+	if err != nil {
+		t.Fatalf("failed to seed stats: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := ts.Stats.SnapshotStats(context.Background()); err != nil {
+			t.Fatalf("snapshot failed: %v", err)
+		}
+	}
+
+	type snapshot struct {
+		TotalPRs          int     `json:"total_prs"`
+		OpenPRs           int     `json:"open_prs"`
+		MergedPRs         int     `json:"merged_prs"`
+		AvgReviewersPerPR float64 `json:"avg_reviewers_per_pr"`
+		OpenReviews       int     `json:"open_reviews"`
+		ActiveMembers     int     `json:"active_members"`
+	}
+
+	history := func(query string) (int, []snapshot) {
+		t.Helper()
+		resp := doWithKey(t, ts, http.MethodGet, "/stats/history"+query, "", ts.AdminKey)
+		defer resp.Body.Close()
+
+		var data struct {
+			Snapshots []snapshot `json:"snapshots"`
+		}
+		json.NewDecoder(resp.Body).Decode(&data)
+		return resp.StatusCode, data.Snapshots
+	}
+
+	status, backend := history("?team_name=Backend")
+	if status != http.StatusOK || len(backend) != 3 {
+		t.Fatalf("expected 3 Backend snapshots, got %d %+v", status, backend)
+	}
+	if backend[0].TotalPRs != 7 || backend[1].TotalPRs != 5 {
+		t.Fatalf("expected snapshots oldest first, got %+v", backend)
+	}
+	today := backend[2]
+	if today.TotalPRs != 1 || today.OpenPRs != 1 || today.AvgReviewersPerPR != 2 || today.OpenReviews != 2 || today.ActiveMembers != 5 {
+		t.Fatalf("unexpected Backend snapshot %+v", today)
+	}
+
+	if _, recent := history("?team_name=Backend&window_days=2"); len(recent) != 2 {
+		t.Fatalf("expected 2 Backend snapshots within 2 days, got %+v", recent)
+	}
+
+	_, overall := history("")
+	if len(overall) != 1 {
+		t.Fatalf("expected one service-wide snapshot after repeated runs, got %+v", overall)
+	}
+	if overall[0].TotalPRs != 2 || overall[0].MergedPRs != 1 || overall[0].OpenReviews != 2 || overall[0].ActiveMembers != 7 {
+		t.Fatalf("unexpected service-wide snapshot %+v", overall[0])
+	}
+
+	if status, _ := history("?window_days=-1"); status != http.StatusBadRequest {
+		t.Fatalf("expected 400 for invalid window, got %d", status)
+	}
+}
+
+func TestStatsCapacityExport(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	_, err = ts.DB.Exec(`
+		INSERT INTO pull_requests (pull_request_id, pull_request_name, author_id, status) VALUES
+			('PR-CAP1', 'Open', 1, 'OPEN'),
+			('PR-CAP2', 'Merged', 1, 'MERGED');
+		INSERT INTO pr_reviewers (pull_request_id, reviewer_id, review_completed_at) VALUES
+			('PR-CAP1', 2, NULL), ('PR-CAP2', 2, NOW() - INTERVAL '1 day'), ('PR-CAP2', 3, NOW() - INTERVAL '60 days');
+		UPDATE users SET is_active = false WHERE user_id = 5;
+	`)
+	if err != nil {
+		t.Fatalf("failed to seed reviews: %v", err)
+	}
+
+	resp := doWithKey(t, ts, http.MethodGet, "/stats/capacity?team_name=Backend&format=xlsx", "", ts.AdminKey)
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", resp.StatusCode, string(body))
+	}
+	if ct := resp.Header.Get("Content-Type"); !strings.Contains(ct, "spreadsheetml") {
+		t.Fatalf("expected an xlsx content type, got %q", ct)
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+	if err != nil {
+		t.Fatalf("response is not an xlsx archive: %v", err)
+	}
+
+	var sheet string
+	for _, file := range archive.File {
+		if file.Name != "xl/worksheets/sheet1.xml" {
+			continue
+		}
+		rc, err := file.Open()
+		if err != nil {
+			t.Fatalf("failed to open sheet: %v", err)
+		}
+		content, _ := io.ReadAll(rc)
+		rc.Close()
+		sheet = string(content)
+	}
+
+	rows := strings.Split(sheet, "<row>")[1:]
+	if len(rows) != 6 {
+		t.Fatalf("expected a header and 5 member rows, got %d", len(rows))
+	}
+	if !strings.Contains(rows[0], "open_reviews") {
+		t.Fatalf("expected header row, got %s", rows[0])
+	}
+
+	bob := rows[2]
+	if !strings.Contains(bob, ">u2<") || !strings.Contains(bob, "<c><v>1</v></c><c><v>1</v></c><c><v>1</v></c>") {
+		t.Fatalf("expected u2 with 1 open, 1 completed and 1 recent review, got %s", bob)
+	}
+	if eve := rows[5]; !strings.Contains(eve, ">u5<") || !strings.Contains(eve, ">OFF<") {
+		t.Fatalf("expected inactive u5 to be marked OFF, got %s", eve)
+	}
+	if strings.Contains(rows[1], ">OFF<") {
+		t.Fatalf("expected active u1 to have no days off, got %s", rows[1])
+	}
+
+	for path, status := range map[string]int{
+		"/stats/capacity?team_name=Nope":    http.StatusNotFound,
+		"/stats/capacity?format=csv":        http.StatusBadRequest,
+		"/stats/capacity?team_name=QA":      http.StatusOK,
+		"/stats/capacity?team_name=Backend": http.StatusOK,
+	} {
+		resp := doWithKey(t, ts, http.MethodGet, path, "", ts.AdminKey)
+		resp.Body.Close()
+		if resp.StatusCode != status {
+			t.Fatalf("%s: expected %d, got %d", path, status, resp.StatusCode)
+		}
+	}
+}
+
+func TestVersion(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	resp := doGet(t, ts, "/version")
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+
+	var data struct {
+		Commit    string   `json:"commit"`
+		BuildTime string   `json:"build_time"`
+		GoVersion string   `json:"go_version"`
+		Features  []string `json:"features"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if data.Commit == "" || data.BuildTime == "" || !strings.HasPrefix(data.GoVersion, "go") || data.Features == nil {
+		t.Fatalf("expected complete build info, got %+v", data)
+	}
+}
+
+func TestFieldSelection(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	resp := doGet(t, ts, "/team/get?team_name=Backend&fields=team_name,members.user_id")
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+
+	var team map[string]json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&team); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	var members []map[string]any
+	if err := json.Unmarshal(team["members"], &members); err != nil {
+		t.Fatalf("failed to decode members: %v", err)
+	}
+
+	if len(team) != 2 || len(members) != 5 {
+		t.Fatalf("expected team_name and 5 members, got %d keys and %d members", len(team), len(members))
+	}
+
+	for _, member := range members {
+		if len(member) != 1 || member["user_id"] == nil {
+			t.Fatalf("expected only user_id per member, got %v", member)
+		}
+	}
+
+	statsResp := doWithKey(t, ts, http.MethodGet, "/stats/prs?fields=stats.total_prs", "", ts.AdminKey)
+	defer statsResp.Body.Close()
+
+	var stats struct {
+		Stats map[string]any `json:"stats"`
+	}
+	if err := json.NewDecoder(statsResp.Body).Decode(&stats); err != nil {
+		t.Fatalf("failed to decode stats: %v", err)
+	}
+
+	if len(stats.Stats) != 1 || stats.Stats["total_prs"] == nil {
+		t.Fatalf("expected only total_prs, got %v", stats.Stats)
+	}
+
+	unknown := doGet(t, ts, "/team/get?team_name=Backend&fields=members.email")
+	unknown.Body.Close()
+
+	if unknown.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected 400 for unknown field, got %d", unknown.StatusCode)
+	}
+}
+
+func TestPullRequestExport(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	for _, id := range []string{"PR-E1", "PR-E2", "PR-E3"} {
+		resp := doPost(t, ts, "/pullRequest/create", fmt.Sprintf(`{
+			"pull_request_id": "%s",
+			"pull_request_name": "Export",
+			"author_id": "u1"
+		}`, id))
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("failed to create PR %s: %d", id, resp.StatusCode)
+		}
+	}
+
+	resp := doGet(t, ts, "/pullRequest/export")
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		t.Fatalf("expected 200, got %d: %s", resp.StatusCode, string(body))
+	}
+
+	if ct := resp.Header.Get("Content-Type"); ct != "application/x-ndjson" {
+		t.Fatalf("unexpected content type: %s", ct)
+	}
+
+	var ids []string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		var row struct {
+			PullRequestID string `json:"pull_request_id"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &row); err != nil {
+			t.Fatalf("invalid NDJSON line %q: %v", scanner.Text(), err)
+		}
+		ids = append(ids, row.PullRequestID)
+	}
+
+	if len(ids) != 3 {
+		t.Fatalf("expected 3 exported PRs, got %d", len(ids))
+	}
+}
+
+func TestUserMyReviews(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	for _, pr := range []struct{ id, priority string }{{"PR-Q1", "LOW"}, {"PR-Q2", "CRITICAL"}} {
+		resp := doPost(t, ts, "/pullRequest/create", fmt.Sprintf(`{
+			"pull_request_id": "%s",
+			"pull_request_name": "Queue",
+			"author_id": "u10",
+			"priority": "%s"
+		}`, pr.id, pr.priority))
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("failed to create PR %s: %d", pr.id, resp.StatusCode)
+		}
+	}
+
+	resp := doGet(t, ts, "/users/myReviews")
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without identity, got %d", resp.StatusCode)
+	}
+
+	resp = doGetAs(t, ts, "/users/myReviews", "u11")
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		t.Fatalf("expected 200, got %d: %s", resp.StatusCode, string(body))
+	}
+
+	var data struct {
+		Reviews []struct {
+			PullRequestID string `json:"pull_request_id"`
+			Priority      string `json:"priority"`
+			Overdue       bool   `json:"overdue"`
+		} `json:"reviews"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if len(data.Reviews) != 2 {
+		t.Fatalf("expected 2 reviews, got %d", len(data.Reviews))
+	}
+
+	if data.Reviews[0].PullRequestID != "PR-Q2" {
+		t.Fatalf("expected CRITICAL PR first, got %s", data.Reviews[0].PullRequestID)
+	}
+}
+
+func TestReviewerPool(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	create := doPost(t, ts, "/pool/create", `{"pool_name": "api-guild", "reviewers_per_pr": 2, "members": ["u10", "u11"]}`)
+	create.Body.Close()
+
+	if create.StatusCode != http.StatusCreated {
+		t.Fatalf("expected 201 on pool create, got %d", create.StatusCode)
+	}
+
+	duplicate := doPost(t, ts, "/pool/create", `{"pool_name": "api-guild"}`)
+	duplicate.Body.Close()
+
+	if duplicate.StatusCode != http.StatusConflict {
+		t.Fatalf("expected 409 on duplicate pool, got %d", duplicate.StatusCode)
+	}
+
+	unknown := doPost(t, ts, "/pullRequest/create", `{
+		"pull_request_id": "PR-P0",
+		"pull_request_name": "Unknown pool",
+		"author_id": "u1",
+		"reviewer_pool": "web-guild"
+	}`)
+	unknown.Body.Close()
+
+	if unknown.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown pool, got %d", unknown.StatusCode)
+	}
+
+	resp := doPost(t, ts, "/pullRequest/create", `{
+		"pull_request_id": "PR-P1",
+		"pull_request_name": "API change",
+		"author_id": "u1",
+		"reviewer_pool": "api-guild"
+	}`)
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		t.Fatalf("expected 201, got %d: %s", resp.StatusCode, string(body))
+	}
+
+	var data struct {
+		PR struct {
+			Reviewers    []string `json:"assigned_reviewers"`
+			ReviewerPool string   `json:"reviewer_pool"`
+		} `json:"pr"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		t.Fatalf("failed to decode PR response: %v", err)
+	}
+
+	slices.Sort(data.PR.Reviewers)
+	if !slices.Equal(data.PR.Reviewers, []string{"u10", "u11"}) {
+		t.Fatalf("expected pool members u10 and u11 as reviewers, got %v", data.PR.Reviewers)
+	}
+	if data.PR.ReviewerPool != "api-guild" {
+		t.Fatalf("expected reviewer_pool api-guild, got %q", data.PR.ReviewerPool)
+	}
+
+	add := doPost(t, ts, "/pool/members/add", `{"pool_name": "api-guild", "user_ids": ["u2"]}`)
+	add.Body.Close()
+
+	if add.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200 on members add, got %d", add.StatusCode)
+	}
+
+	reassign := doPost(t, ts, "/pullRequest/reassign", `{"pull_request_id": "PR-P1", "old_reviewer_id": "u10", "reason": "overloaded"}`)
+	defer reassign.Body.Close()
+
+	if reassign.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(reassign.Body)
+		t.Fatalf("expected 200 on reassign, got %d: %s", reassign.StatusCode, string(body))
+	}
+
+	var out struct {
+		ReplacedBy string `json:"replaced_by"`
+	}
+
+	if err := json.NewDecoder(reassign.Body).Decode(&out); err != nil {
+		t.Fatalf("failed to decode reassign response: %v", err)
+	}
+
+	if out.ReplacedBy != "u2" {
+		t.Fatalf("expected replacement from the pool (u2), got %s", out.ReplacedBy)
+	}
+
+	statsResp := doGet(t, ts, "/pool/stats?pool_name=api-guild")
+	defer statsResp.Body.Close()
+
+	var stats struct {
+		OpenPRs int `json:"open_prs"`
+		Members []struct {
+			UserID      string `json:"user_id"`
+			OpenReviews int    `json:"open_reviews"`
+		} `json:"members"`
+	}
+
+	if err := json.NewDecoder(statsResp.Body).Decode(&stats); err != nil {
+		t.Fatalf("failed to decode pool stats: %v", err)
+	}
+
+	if stats.OpenPRs != 1 || len(stats.Members) != 3 {
+		t.Fatalf("expected 1 open PR and 3 members, got %+v", stats)
+	}
+
+	for _, member := range stats.Members {
+		want := 1
+		if member.UserID == "u10" {
+			want = 0
+		}
+		if member.OpenReviews != want {
+			t.Fatalf("expected %d open reviews for %s, got %d", want, member.UserID, member.OpenReviews)
+		}
+	}
+}
+
+func TestAdminRequestSignatures(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	signatures := service.NewAdminSignatureService(log, repo.NewNonceRepo(ts.DB), "signing-secret", time.Minute)
+
+	var received []string
+	admin := httptest.NewServer(middleware.AdminSignature(signatures, log)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received = append(received, string(body))
+	})))
+	defer admin.Close()
+
+	send := func(method, timestamp, nonce, signature string) int {
+		t.Helper()
+		req, err := http.NewRequest(method, admin.URL+"/admin/anonymizeUser", strings.NewReader(`{"user_id": "u1"}`))
+		if err != nil {
+			t.Fatalf("failed to build request: %v", err)
+		}
+		req.Header.Set(middleware.AdminTimestampHeader, timestamp)
+		req.Header.Set(middleware.AdminNonceHeader, nonce)
+		req.Header.Set(middleware.AdminSignatureHeader, signature)
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	sign := func(timestamp, nonce string) string {
+		return service.SignAdminRequest("signing-secret", http.MethodPost, "/admin/anonymizeUser", timestamp, nonce, []byte(`{"user_id": "u1"}`))
+	}
+
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	stale := strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10)
+
+	for _, call := range []struct {
+		name             string
+		method           string
+		timestamp, nonce string
+		signature        string
+		status           int
+	}{
+		{"unsigned", http.MethodPost, "", "", "", http.StatusUnauthorized},
+		{"wrong signature", http.MethodPost, now, "n-1", sign(now, "n-other"), http.StatusUnauthorized},
+		{"signed", http.MethodPost, now, "n-1", sign(now, "n-1"), http.StatusOK},
+		{"replayed", http.MethodPost, now, "n-1", sign(now, "n-1"), http.StatusConflict},
+		{"stale", http.MethodPost, stale, "n-2", sign(stale, "n-2"), http.StatusUnauthorized},
+		{"read", http.MethodGet, "", "", "", http.StatusOK},
+	} {
+		if status := send(call.method, call.timestamp, call.nonce, call.signature); status != call.status {
+			t.Fatalf("%s: expected %d, got %d", call.name, call.status, status)
+		}
+	}
+
+	if len(received) != 2 || received[0] != `{"user_id": "u1"}` {
+		t.Fatalf("expected the signed body to reach the handler once, got %q", received)
+	}
+}
+
+func TestForgeBackfill(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	type backfillResponse struct {
+		Backfill struct {
+			Fetched  int      `json:"fetched"`
+			Created  []string `json:"created"`
+			Existing int      `json:"existing"`
+			Skipped  []struct {
+				PullRequestID string `json:"pull_request_id"`
+				Reason        string `json:"reason"`
+			} `json:"skipped"`
+		} `json:"backfill"`
+	}
+
+	backfill := func() backfillResponse {
+		t.Helper()
+		resp := doPost(t, ts, "/admin/backfill", "")
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("expected 200 from backfill, got %d", resp.StatusCode)
+		}
+
+		var data backfillResponse
+		if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+			t.Fatalf("failed to decode backfill response: %v", err)
+		}
+		return data
+	}
+
+	first := backfill().Backfill
+	if ts.Forge.RateLimited.Load() != 1 {
+		t.Fatalf("expected the forge to rate limit the first request once")
+	}
+	if first.Fetched != 4 || first.Existing != 0 {
+		t.Fatalf("expected 4 PRs fetched from the active repository only, got %+v", first)
+	}
+	if !slices.Equal(first.Created, []string{"acme/api#1", "acme/api#4"}) {
+		t.Fatalf("expected acme/api#1 and acme/api#4 to be created, got %+v", first.Created)
+	}
+	if len(first.Skipped) != 2 ||
+		first.Skipped[0].PullRequestID != "acme/api#2" || first.Skipped[0].Reason != "draft" ||
+		first.Skipped[1].PullRequestID != "acme/api#3" || first.Skipped[1].Reason != "author is not a known user" {
+		t.Fatalf("expected the draft and the unknown author to be skipped, got %+v", first.Skipped)
+	}
+
+	var imported struct {
+		AuthorID  int       `db:"author_id"`
+		CreatedAt time.Time `db:"created_at"`
+		Reviewers int       `db:"reviewers"`
+	}
+	err = ts.DB.Get(&imported, `
+		SELECT pr.author_id, pr.created_at,
+			(SELECT COUNT(*) FROM pr_reviewers prr WHERE prr.pull_request_id = pr.pull_request_id) AS reviewers
+		FROM pull_requests pr WHERE pr.pull_request_id = 'acme/api#1'`)
+	if err != nil {
+		t.Fatalf("failed to read imported PR: %v", err)
+	}
+	if imported.AuthorID != 1 || imported.Reviewers != 2 ||
+		!imported.CreatedAt.Equal(time.Date(2026, 1, 5, 10, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected Alice's PR with 2 reviewers and the forge creation time, got %+v", imported)
+	}
+
+	second := backfill().Backfill
+	if len(second.Created) != 0 || second.Existing != 2 {
+		t.Fatalf("expected a repeated backfill to find both PRs existing, got %+v", second)
+	}
+}
+
+func TestGoClient(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	deliveries := make(chan *client.WebhookDelivery, 4)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		delivery, err := client.ParseWebhook(r, "client-secret")
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		deliveries <- delivery
+	}))
+	defer receiver.Close()
+
+	resp := doPost(t, ts, "/team/webhooks/create",
+		fmt.Sprintf(`{"team_name": "Backend", "url": %q, "secret": "client-secret", "events": ["pr.created"]}`, receiver.URL))
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected 201 for webhook, got %d", resp.StatusCode)
+	}
+
+	ctx := context.Background()
+	api := client.New(ts.Server.URL, "", nil)
+
+	pr, err := api.CreatePR(ctx, client.CreatePRRequest{
+		PullRequestID:   "PR-SDK1",
+		PullRequestName: "Client",
+		AuthorID:        "u1",
+		Labels:          []string{"sdk"},
+	})
+	if err != nil {
+		t.Fatalf("CreatePR failed: %v", err)
+	}
+	if pr.PullRequestID != "PR-SDK1" || pr.Status != "OPEN" || len(pr.AssignedReviewers) != 2 {
+		t.Fatalf("expected an open PR with 2 reviewers, got %+v", pr)
+	}
+
+	select {
+	case delivery := <-deliveries:
+		if delivery.Event != client.EventPullRequestCreated || delivery.PullRequestID != "PR-SDK1" {
+			t.Fatalf("unexpected delivery %+v", delivery)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("signed delivery was not accepted by ParseWebhook")
+	}
+
+	_, err = api.CreatePR(ctx, client.CreatePRRequest{PullRequestID: "PR-SDK1", PullRequestName: "Client", AuthorID: "u1"})
+	var apiErr *client.Error
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusConflict || apiErr.Code != "PR_EXISTS" {
+		t.Fatalf("expected a PR_EXISTS API error, got %v", err)
+	}
+
+	old := pr.AssignedReviewers[0]
+	reassigned, replacedBy, err := api.Reassign(ctx, "PR-SDK1", old, client.ReasonVacation)
+	if err != nil {
+		t.Fatalf("Reassign failed: %v", err)
+	}
+	if replacedBy == "" || replacedBy == old || !slices.Contains(reassigned.AssignedReviewers, replacedBy) {
+		t.Fatalf("expected %s to be replaced, got %q in %+v", old, replacedBy, reassigned.AssignedReviewers)
+	}
+
+	reviews, err := api.GetMyReviews(ctx, replacedBy)
+	if err != nil {
+		t.Fatalf("GetMyReviews failed: %v", err)
+	}
+	if len(reviews) != 1 || reviews[0].PullRequestID != "PR-SDK1" || reviews[0].DueAt.IsZero() {
+		t.Fatalf("expected PR-SDK1 in the new reviewer's queue, got %+v", reviews)
+	}
+
+	body := []byte(`{"event":"review.assigned"}`)
+	if err := client.VerifyWebhook("client-secret", body, service.SignWebhook("client-secret", body)); err != nil {
+		t.Fatalf("expected the service signature to verify, got %v", err)
+	}
+	if err := client.VerifyWebhook("other-secret", body, service.SignWebhook("client-secret", body)); !errors.Is(err, client.ErrInvalidWebhookSignature) {
+		t.Fatalf("expected a signature under another secret to fail, got %v", err)
+	}
+}
+
+func TestUserOffboarding(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	createPR := func(id string, author string) []string {
+		t.Helper()
+		resp := doPost(t, ts, "/pullRequest/create",
+			fmt.Sprintf(`{"pull_request_id": %q, "pull_request_name": "Offboarding", "author_id": %q}`, id, author))
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("failed to create %s: %d", id, resp.StatusCode)
+		}
+
+		var created struct {
+			PR struct {
+				AssignedReviewers []string `json:"assigned_reviewers"`
+			} `json:"pr"`
+		}
+		json.NewDecoder(resp.Body).Decode(&created)
+		return created.PR.AssignedReviewers
+	}
+
+	type offboardResponse struct {
+		Offboarding struct {
+			UserID      string `json:"user_id"`
+			Deactivated bool   `json:"deactivated"`
+			Reassigned  []struct {
+				PullRequestID string `json:"pull_request_id"`
+				NewReviewerID string `json:"new_reviewer_id"`
+			} `json:"reassigned"`
+			Unreassigned []struct {
+				PullRequestID string `json:"pull_request_id"`
+				Reason        string `json:"reason"`
+			} `json:"unreassigned"`
+			RemovedFromPools    []string `json:"removed_from_pools"`
+			RevokedTokens       []int64  `json:"revoked_tokens"`
+			CancelledDeliveries int      `json:"cancelled_deliveries"`
+			Anonymization       *struct {
+				ConfirmationToken string `json:"confirmation_token"`
+				Anonymized        bool   `json:"anonymized"`
+			} `json:"anonymization"`
+		} `json:"offboarding"`
+	}
+
+	offboard := func(body string) offboardResponse {
+		t.Helper()
+		resp := doPost(t, ts, "/users/offboard", body)
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("expected 200 for offboard %s, got %d", body, resp.StatusCode)
+		}
+
+		var data offboardResponse
+		json.NewDecoder(resp.Body).Decode(&data)
+		return data
+	}
+
+	leaving := createPR("PR-OB1", "u1")[0]
+
+	issued := doPost(t, ts, "/admin/tokens/issue", fmt.Sprintf(`{"name": "laptop", "user_id": %q, "scopes": ["read"]}`, leaving))
+	var token struct {
+		Key string `json:"key"`
+	}
+	json.NewDecoder(issued.Body).Decode(&token)
+	issued.Body.Close()
+
+	resp := doPost(t, ts, "/pool/create",
+		fmt.Sprintf(`{"pool_name": "oncall", "reviewers_per_pr": 1, "members": [%q, "u10"]}`, leaving))
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected 201 on pool create, got %d", resp.StatusCode)
+	}
+
+	// A pending retry about the leaving user and one about someone else.
+	hook := doPost(t, ts, "/team/webhooks/create",
+		`{"team_name": "Backend", "url": "http://127.0.0.1:1/hook", "secret": "s", "events": ["reviewer.assigned"]}`)
+	var created struct {
+		Webhook struct {
+			ID int64 `json:"id"`
+		} `json:"webhook"`
+	}
+	json.NewDecoder(hook.Body).Decode(&created)
+	hook.Body.Close()
+
+	leavingID, _ := strconv.Atoi(strings.TrimPrefix(leaving, "u"))
+	for _, userID := range []int{leavingID, 10} {
+		_, err := ts.DB.Exec(`
+			INSERT INTO webhook_deliveries (webhook_id, event, pull_request_id, body, user_ids, attempt, status, next_attempt_at)
+			VALUES ($1, 'reviewer.assigned', 'PR-OB1', '{}', $2, 2, 'PENDING', NOW() + INTERVAL '1 hour')`,
+			created.Webhook.ID, pq.Array([]int{userID}))
+		if err != nil {
+			t.Fatalf("failed to seed a pending delivery: %v", err)
+		}
+	}
+
+	report := offboard(fmt.Sprintf(`{"user_id": %q}`, leaving)).Offboarding
+	if !report.Deactivated || len(report.Unreassigned) != 0 || !slices.Equal(report.RemovedFromPools, []string{"oncall"}) {
+		t.Fatalf("expected %s deactivated and removed from oncall, got %+v", leaving, report)
+	}
+	if len(report.Reassigned) != 1 || report.Reassigned[0].PullRequestID != "PR-OB1" ||
+		report.Reassigned[0].NewReviewerID == "" || report.Reassigned[0].NewReviewerID == leaving {
+		t.Fatalf("expected PR-OB1 handed to another reviewer, got %+v", report.Reassigned)
+	}
+	if report.CancelledDeliveries != 1 {
+		t.Fatalf("expected the pending delivery about %s to be cancelled, got %d", leaving, report.CancelledDeliveries)
+	}
+	var pending int
+	if err := ts.DB.Get(&pending, `SELECT COUNT(*) FROM webhook_deliveries WHERE status = 'PENDING'`); err != nil || pending != 1 {
+		t.Fatalf("expected the other user's delivery to stay pending, got %d: %v", pending, err)
+	}
+	if len(report.RevokedTokens) != 1 {
+		t.Fatalf("expected the key of %s to be revoked, got %v", leaving, report.RevokedTokens)
+	}
+
+	keyed := doWithKey(t, ts, http.MethodGet, "/team/get?team_name=Backend", "", token.Key)
+	keyed.Body.Close()
+	if keyed.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected the offboarded user's key to be rejected, got %d", keyed.StatusCode)
+	}
+
+	var reason string
+	err = ts.DB.Get(&reason, `SELECT reason FROM assignment_history WHERE pull_request_id = 'PR-OB1' AND reason IS NOT NULL`)
+	if err != nil || reason != "OFFBOARDED" {
+		t.Fatalf("expected the reassignment to be recorded as OFFBOARDED, got %q: %v", reason, err)
+	}
+
+	if again := offboard(fmt.Sprintf(`{"user_id": %q}`, leaving)).Offboarding; again.Deactivated ||
+		len(again.Reassigned) != 0 || len(again.RemovedFromPools) != 0 {
+		t.Fatalf("expected a repeated offboarding to change nothing, got %+v", again)
+	}
+
+	// Max is Ivan's only possible reviewer, so nobody can take over.
+	createPR("PR-OB2", "u10")
+	stuck := offboard(`{"user_id": "u11", "anonymize": true}`).Offboarding
+	if len(stuck.Unreassigned) != 1 || stuck.Unreassigned[0].PullRequestID != "PR-OB2" || stuck.Unreassigned[0].Reason == "" {
+		t.Fatalf("expected PR-OB2 to stay unreassigned with a reason, got %+v", stuck)
+	}
+	if stuck.Anonymization == nil || stuck.Anonymization.Anonymized || stuck.Anonymization.ConfirmationToken == "" {
+		t.Fatalf("expected a confirmation token before anonymizing, got %+v", stuck.Anonymization)
+	}
+
+	confirmed := offboard(fmt.Sprintf(`{"user_id": "u11", "anonymize": true, "confirmation_token": %q}`,
+		stuck.Anonymization.ConfirmationToken)).Offboarding
+	if confirmed.Deactivated || confirmed.Anonymization == nil || !confirmed.Anonymization.Anonymized {
+		t.Fatalf("expected u11 to be anonymized, got %+v", confirmed)
+	}
+
+	resp = doPost(t, ts, "/users/offboard", `{"user_id": "u999"}`)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected 404 for an unknown user, got %d", resp.StatusCode)
+	}
+}
+
+func TestPRActivity(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	if _, err := ts.DB.Exec(`INSERT INTO pr_statuses (status, is_terminal) VALUES ('ON_HOLD', false) ON CONFLICT DO NOTHING`); err != nil {
+		t.Fatalf("failed to add status: %v", err)
+	}
+
+	delivered := make(chan struct{}, 1)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		delivered <- struct{}{}
+	}))
+	defer receiver.Close()
+
+	expectStatus := func(path string, body string, status int) *http.Response {
+		t.Helper()
+		resp := doPost(t, ts, path, body)
+		if resp.StatusCode != status {
+			resp.Body.Close()
+			t.Fatalf("%s %s: expected %d, got %d", path, body, status, resp.StatusCode)
+		}
+		return resp
+	}
+
+	expectStatus("/team/webhooks/create",
+		`{"team_name": "Backend", "url": "`+receiver.URL+`", "secret": "s", "events": ["pr.created"]}`, http.StatusCreated).Body.Close()
+
+	resp := expectStatus("/pullRequest/create",
+		`{"pull_request_id": "PR-A1", "pull_request_name": "Activity", "author_id": "u1"}`, http.StatusCreated)
+	var created struct {
+		PR struct {
+			AssignedReviewers []string `json:"assigned_reviewers"`
+		} `json:"pr"`
+	}
+	json.NewDecoder(resp.Body).Decode(&created)
+	resp.Body.Close()
+	if len(created.PR.AssignedReviewers) != 2 {
+		t.Fatalf("expected 2 reviewers, got %v", created.PR.AssignedReviewers)
+	}
+
+	select {
+	case <-delivered:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("webhook was not called")
+	}
+
+	reviewer := created.PR.AssignedReviewers[1]
+	expectStatus("/pullRequest/reassign",
+		fmt.Sprintf(`{"pull_request_id": "PR-A1", "old_reviewer_id": %q}`, created.PR.AssignedReviewers[0]), http.StatusOK).Body.Close()
+	expectStatus("/pullRequest/startReview",
+		fmt.Sprintf(`{"pull_request_id": "PR-A1", "reviewer_id": %q}`, reviewer), http.StatusOK).Body.Close()
+	expectStatus("/pullRequest/approve",
+		fmt.Sprintf(`{"pull_request_id": "PR-A1", "reviewer_id": %q}`, reviewer), http.StatusOK).Body.Close()
+	expectStatus("/pullRequest/setStatus", `{"pull_request_id": "PR-A1", "status": "ON_HOLD"}`, http.StatusOK).Body.Close()
+	expectStatus("/pullRequest/setStatus", `{"pull_request_id": "PR-A1", "status": "OPEN"}`, http.StatusOK).Body.Close()
+	expectStatus("/pullRequest/merge", `{"pull_request_id": "PR-A1"}`, http.StatusOK).Body.Close()
+
+	type activityEntry struct {
+		Kind       string    `json:"kind"`
+		OccurredAt time.Time `json:"occurred_at"`
+		UserID     string    `json:"user_id"`
+		Details    string    `json:"details"`
+	}
+	type activityResponse struct {
+		Activity []activityEntry `json:"activity"`
+	}
+
+	// The delivery outcome is stored after the receiver answers.
+	var feed activityResponse
+	for deadline := time.Now().Add(5 * time.Second); ; {
+		resp = doGet(t, ts, "/pullRequest/activity?pull_request_id=PR-A1")
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("expected 200, got %d", resp.StatusCode)
+		}
+		feed = activityResponse{}
+		json.NewDecoder(resp.Body).Decode(&feed)
+		resp.Body.Close()
+
+		sent := slices.ContainsFunc(feed.Activity, func(a activityEntry) bool { return a.Kind == "NOTIFICATION_SENT" })
+		if sent || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	want := []string{"PR_CREATED", "REVIEWER_ASSIGNED", "REVIEWER_ASSIGNED", "NOTIFICATION_SENT", "REVIEWER_ASSIGNED",
+		"REVIEW_STARTED", "APPROVED", "STATUS_CHANGED", "STATUS_CHANGED", "MERGED"}
+	next := 0
+	for i, entry := range feed.Activity {
+		if i > 0 && entry.OccurredAt.Before(feed.Activity[i-1].OccurredAt) {
+			t.Fatalf("expected a chronological feed, got %+v", feed.Activity)
+		}
+		if next < len(want) && entry.Kind == want[next] {
+			next++
+		}
+	}
+	if next != len(want) {
+		t.Fatalf("expected the feed to contain %v in order, got %+v", want, feed.Activity)
+	}
+
+	resp = doGet(t, ts, "/pullRequest/activity?pull_request_id=PR-NONE")
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected 404 for an unknown PR, got %d", resp.StatusCode)
+	}
+}
+
+func TestUserForecast(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	openReviews := 0
+	for i, author := range []string{"u1", "u2", "u3", "u4"} {
+		resp := doPost(t, ts, "/pullRequest/create",
+			fmt.Sprintf(`{"pull_request_id": "PR-F%d", "pull_request_name": "Forecast", "author_id": %q}`, i, author))
+		var created struct {
+			PR struct {
+				AssignedReviewers []string `json:"assigned_reviewers"`
+			} `json:"pr"`
+		}
+		json.NewDecoder(resp.Body).Decode(&created)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("failed to create PR-F%d: %d", i, resp.StatusCode)
+		}
+		if slices.Contains(created.PR.AssignedReviewers, "u1") {
+			openReviews++
+		}
+	}
+
+	type forecastResponse struct {
+		Forecast struct {
+			TeamName             string  `json:"team_name"`
+			OpenReviews          int     `json:"open_reviews"`
+			TeamPRsPerWeek       float64 `json:"team_prs_per_week"`
+			SelectionProbability float64 `json:"selection_probability"`
+			ExpectedNewReviews   float64 `json:"expected_new_reviews"`
+			ExpectedLoad         float64 `json:"expected_load"`
+		} `json:"forecast"`
+	}
+
+	forecast := func(userID string) forecastResponse {
+		t.Helper()
+		resp := doGet(t, ts, "/users/forecast?user_id="+userID)
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("expected 200 for %s, got %d", userID, resp.StatusCode)
+		}
+
+		var data forecastResponse
+		json.NewDecoder(resp.Body).Decode(&data)
+		return data
+	}
+
+	// Three PRs by teammates over four weeks, two reviewers out of four
+	// possible ones per PR.
+	got := forecast("u1").Forecast
+	if got.TeamName != "Backend" || got.OpenReviews != openReviews || got.TeamPRsPerWeek != 0.75 ||
+		got.SelectionProbability != 0.5 || got.ExpectedNewReviews != 0.38 ||
+		math.Abs(got.ExpectedLoad-float64(openReviews)-0.38) > 0.001 {
+		t.Fatalf("unexpected forecast for u1 with %d open reviews: %+v", openReviews, got)
+	}
+
+	resp := doPost(t, ts, "/users/setIsActive", `{"user_id": "u5", "is_active": false}`)
+	resp.Body.Close()
+
+	if inactive := forecast("u5").Forecast; inactive.SelectionProbability != 0 || inactive.ExpectedNewReviews != 0 {
+		t.Fatalf("expected no new reviews for an inactive user, got %+v", inactive)
+	}
+	if got := forecast("u1").Forecast; got.SelectionProbability != 0.67 {
+		t.Fatalf("expected a higher selection probability with fewer active members, got %+v", got)
+	}
+
+	for path, status := range map[string]int{
+		"/users/forecast":             http.StatusBadRequest,
+		"/users/forecast?user_id=x":   http.StatusBadRequest,
+		"/users/forecast?user_id=u99": http.StatusNotFound,
+	} {
+		resp := doGet(t, ts, path)
+		resp.Body.Close()
+		if resp.StatusCode != status {
+			t.Fatalf("%s: expected %d, got %d", path, status, resp.StatusCode)
+		}
+	}
+}
+
+func TestStatsVisibility(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	issue := func(scopes string, userID string) string {
+		t.Helper()
+		resp := doPost(t, ts, "/admin/tokens/issue", `{"name": "stats", "user_id": "`+userID+`", "scopes": [`+scopes+`]}`)
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("expected 201 on issue, got %d", resp.StatusCode)
+		}
+
+		var issued struct {
+			Key string `json:"key"`
+		}
+		json.NewDecoder(resp.Body).Decode(&issued)
+		return issued.Key
+	}
+	adminKey := issue(`"admin"`, "u5")
+	readKey := issue(`"read"`, "u1")
+	memberKey := issue(`"read"`, "u2")
+
+	expectStatus := func(method string, path string, body string, key string, userID string, want int) {
+		t.Helper()
+		req, err := http.NewRequest(method, ts.Server.URL+path, bytes.NewBufferString(body))
+		if err != nil {
+			t.Fatalf("failed to build %s %s: %v", method, path, err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		if key != "" {
+			req.Header.Set("X-API-Key", key)
+		}
+		req.Header.Set("X-User-ID", userID)
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("%s %s failed: %v", method, path, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Fatalf("%s %s as %s: expected %d, got %d", method, path, userID, want, resp.StatusCode)
+		}
+	}
+
+	expectStatus(http.MethodPost, "/admin/teamLeads/set", `{"team_name": "Backend", "user_id": "u1", "is_lead": true}`, readKey, "u1", http.StatusForbidden)
+	expectStatus(http.MethodPost, "/admin/teamLeads/set", `{"team_name": "Ghost", "user_id": "u1", "is_lead": true}`, adminKey, "u2", http.StatusNotFound)
+	expectStatus(http.MethodPost, "/admin/teamLeads/set", `{"team_name": "Backend", "user_id": "u1", "is_lead": true}`, adminKey, "u2", http.StatusOK)
+
+	resp := doWithKey(t, ts, http.MethodGet, "/admin/teamLeads", "", adminKey)
+	var leads struct {
+		Leads []struct {
+			TeamName string `json:"team_name"`
+			UserID   string `json:"user_id"`
+		} `json:"leads"`
+	}
+	json.NewDecoder(resp.Body).Decode(&leads)
+	resp.Body.Close()
+	if len(leads.Leads) != 1 || leads.Leads[0].TeamName != "Backend" || leads.Leads[0].UserID != "u1" {
+		t.Fatalf("expected u1 to lead Backend, got %+v", leads.Leads)
+	}
+
+	backend := `{"team_names": ["Backend"]}`
+	both := `{"team_names": ["Backend", "QA"]}`
+
+	// The lead sees their team only, other members see no team.
+	expectStatus(http.MethodPost, "/stats/teams", backend, readKey, "u1", http.StatusOK)
+	expectStatus(http.MethodPost, "/stats/teams", both, readKey, "u1", http.StatusForbidden)
+	expectStatus(http.MethodGet, "/stats/history?team_name=Backend", "", readKey, "u1", http.StatusOK)
+	expectStatus(http.MethodGet, "/stats/history?team_name=QA", "", readKey, "u1", http.StatusForbidden)
+	expectStatus(http.MethodPost, "/stats/teams", backend, memberKey, "u2", http.StatusForbidden)
+
+	// The identity comes from the key: a member's key claiming to be the lead
+	// through X-User-ID sees nothing more, and the lead's key needs no header.
+	expectStatus(http.MethodPost, "/stats/teams", backend, memberKey, "u1", http.StatusForbidden)
+	expectStatus(http.MethodGet, "/stats/history?team_name=Backend", "", memberKey, "u1", http.StatusForbidden)
+	expectStatus(http.MethodPost, "/stats/teams", backend, readKey, "", http.StatusOK)
+
+	// Admin keys see everything. Requests without a key see service-wide
+	// totals only, whoever X-User-ID claims to be.
+	expectStatus(http.MethodPost, "/stats/teams", both, adminKey, "u2", http.StatusOK)
+	expectStatus(http.MethodPost, "/stats/teams", backend, "", "u1", http.StatusForbidden)
+	expectStatus(http.MethodGet, "/stats/prs", "", "", "u1", http.StatusOK)
+
+	expectStatus(http.MethodPost, "/admin/teamLeads/set", `{"team_name": "Backend", "user_id": "u1", "is_lead": false}`, adminKey, "u2", http.StatusOK)
+	expectStatus(http.MethodPost, "/stats/teams", backend, readKey, "u1", http.StatusForbidden)
+
+	var events int
+	err = ts.DB.Get(&events, `SELECT COUNT(*) FROM audit_events WHERE action IN ('TEAM_LEAD_ADDED', 'TEAM_LEAD_REMOVED')`)
+	if err != nil || events != 2 {
+		t.Fatalf("expected the lead change to be audited twice, got %d: %v", events, err)
+	}
+}
+
+func TestResponseTimezone(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	resp := doPost(t, ts, "/pullRequest/create", `{"pull_request_id": "PR-TZ1", "pull_request_name": "Zones", "author_id": "u10"}`)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", resp.StatusCode)
+	}
+
+	get := func(path string, language string, zone string) (int, []byte) {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodGet, ts.Server.URL+path, nil)
+		if err != nil {
+			t.Fatalf("failed to build GET %s: %v", path, err)
+		}
+		req.Header.Set("X-User-ID", "u11")
+		if language != "" {
+			req.Header.Set("Accept-Language", language)
+		}
+		if zone != "" {
+			req.Header.Set("X-Timezone", zone)
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("GET %s failed: %v", path, err)
+		}
+		defer resp.Body.Close()
+
+		body, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, body
+	}
+
+	dueAt := func(path string, language string, zone string) time.Time {
+		t.Helper()
+		status, body := get(path, language, zone)
+		if status != http.StatusOK {
+			t.Fatalf("%s: expected 200, got %d", path, status)
+		}
+
+		var queue struct {
+			Reviews []struct {
+				DueAt string `json:"due_at"`
+			} `json:"reviews"`
+		}
+		json.Unmarshal(body, &queue)
+		if len(queue.Reviews) != 1 {
+			t.Fatalf("expected one review in %s", body)
+		}
+
+		due, err := time.Parse(time.RFC3339, queue.Reviews[0].DueAt)
+		if err != nil {
+			t.Fatalf("failed to parse due_at: %v", err)
+		}
+		return due
+	}
+
+	utc := dueAt("/users/myReviews", "", "")
+	if _, offset := utc.Zone(); offset != 0 {
+		t.Fatalf("expected UTC timestamps by default, got %s", utc)
+	}
+
+	for _, c := range []struct {
+		path     string
+		language string
+		zone     string
+		offset   int
+	}{
+		{"/users/myReviews?tz=Asia/Tokyo", "", "", 9 * 3600},
+		{"/users/myReviews", "ru-RU,ru;q=0.9", "", 0},
+		{"/users/myReviews", "ru", "Europe/Moscow", 3 * 3600},
+		{"/users/myReviews?tz=UTC", "ru", "Asia/Tokyo", 0},
+		{"/users/myReviews?tz=Asia/Tokyo&fields=reviews.due_at", "", "", 9 * 3600},
+	} {
+		due := dueAt(c.path, c.language, c.zone)
+		if _, offset := due.Zone(); offset != c.offset || !due.Equal(utc) {
+			t.Fatalf("%s (%q, %q): expected %s at offset %d, got %s", c.path, c.language, c.zone, utc, c.offset, due)
+		}
+	}
+
+	status, body := get("/users/myReviews?tz=Mars/Olympus", "", "")
+	if status != http.StatusBadRequest || !strings.Contains(string(body), "INVALID_TIMEZONE") {
+		t.Fatalf("expected 400 INVALID_TIMEZONE, got %d: %s", status, body)
+	}
+}
+
+func TestIntegrationsHealth(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	type integrationsResponse struct {
+		Status       string `json:"status"`
+		Integrations []struct {
+			Name                string     `json:"name"`
+			Status              string     `json:"status"`
+			LastSuccessAt       *time.Time `json:"last_success_at"`
+			LastError           string     `json:"last_error"`
+			ConsecutiveFailures int        `json:"consecutive_failures"`
+		} `json:"integrations"`
+	}
+
+	check := func() (integrationsResponse, map[string]string) {
+		t.Helper()
+		resp := doGet(t, ts, "/healthz/integrations")
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("expected 200, got %d", resp.StatusCode)
+		}
+		var data integrationsResponse
+		if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+			t.Fatalf("failed to decode health response: %v", err)
+		}
+		statuses := make(map[string]string)
+		for _, integration := range data.Integrations {
+			statuses[integration.Name] = integration.Status
+		}
+		return data, statuses
+	}
+
+	data, statuses := check()
+	if data.Status != "ok" || statuses["database"] != "ok" || statuses["forge"] != "unknown" || statuses["webhooks"] != "unknown" {
+		t.Fatalf("expected a healthy database and untried integrations, got %s %v", data.Status, statuses)
+	}
+
+	resp := doPost(t, ts, "/admin/backfill", "")
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200 from backfill, got %d", resp.StatusCode)
+	}
+
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer receiver.Close()
+
+	resp = doPost(t, ts, "/team/webhooks/create",
+		`{"team_name": "Backend", "url": "`+receiver.URL+`/hook?token=secret", "secret": "s", "events": ["pr.created"]}`)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected 201 for webhook, got %d", resp.StatusCode)
+	}
+
+	resp = doPost(t, ts, "/pullRequest/create", `{"pull_request_id": "PR-H1", "pull_request_name": "Health", "author_id": "u1"}`)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected 201 for PR, got %d", resp.StatusCode)
+	}
+
+	// Deliveries are sent in the background.
+	for deadline := time.Now().Add(5 * time.Second); ; {
+		data, statuses = check()
+		if statuses["webhooks"] == "failing" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the failed delivery to be reported, got %v", statuses)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	if data.Status != "degraded" || statuses["forge"] != "ok" || statuses["database"] != "ok" {
+		t.Fatalf("expected a degraded report with a working forge, got %s %v", data.Status, statuses)
+	}
+	for _, integration := range data.Integrations {
+		switch integration.Name {
+		case "forge":
+			if integration.LastSuccessAt == nil {
+				t.Fatalf("expected the backfill to set the forge last success")
+			}
+		case "webhooks":
+			if integration.ConsecutiveFailures != 1 || integration.LastError == "" || strings.Contains(integration.LastError, "token=secret") {
+				t.Fatalf("expected one failure without the webhook URL, got %+v", integration)
+			}
+		}
+	}
+}
+
+func TestForgeMergeEvent(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	resp := doPost(t, ts, "/pullRequest/create", `{"pull_request_id": "acme/api#7", "pull_request_name": "Forge merge", "author_id": "u1"}`)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected 201 for PR, got %d", resp.StatusCode)
+	}
+
+	type forgeEventResponse struct {
+		Action        string `json:"action"`
+		PullRequestID string `json:"pull_request_id"`
+		MergedBy      string `json:"merged_by"`
+		Reason        string `json:"reason"`
+	}
+
+	deliver := func(event string, body string, signature string, status int) forgeEventResponse {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodPost, ts.Server.URL+"/forge/events", strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("failed to build forge event: %v", err)
+		}
+		req.Header.Set("X-GitHub-Event", event)
+		req.Header.Set("X-Hub-Signature-256", signature)
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("forge event failed: %v", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != status {
+			t.Fatalf("expected %d for %s, got %d", status, body, resp.StatusCode)
+		}
+		var data forgeEventResponse
+		json.NewDecoder(resp.Body).Decode(&data)
+		return data
+	}
+
+	pullEvent := func(number int, merged bool) string {
+		return fmt.Sprintf(`{"action": "closed", "pull_request": {"number": %d, "merged": %t, "merged_by": {"login": "bob"}},
+			"repository": {"full_name": "acme/api"}}`, number, merged)
+	}
+
+	closed := pullEvent(7, false)
+	if got := deliver("pull_request", closed, service.SignWebhook(forgeWebhookSecret, []byte(closed)), http.StatusOK); got.Action != "ignored" {
+		t.Fatalf("expected a close without merge to be ignored, got %+v", got)
+	}
+
+	merged := pullEvent(7, true)
+	deliver("pull_request", merged, service.SignWebhook("wrong-secret", []byte(merged)), http.StatusUnauthorized)
+
+	got := deliver("pull_request", merged, service.SignWebhook(forgeWebhookSecret, []byte(merged)), http.StatusOK)
+	if got.Action != "merged" || got.PullRequestID != "acme/api#7" || got.MergedBy != "u2" {
+		t.Fatalf("expected acme/api#7 merged by Bob, got %+v", got)
+	}
+
+	var status string
+	if err := ts.DB.Get(&status, `SELECT status FROM pull_requests WHERE pull_request_id = 'acme/api#7'`); err != nil {
+		t.Fatalf("failed to read PR status: %v", err)
+	}
+	if status != "MERGED" {
+		t.Fatalf("expected the PR to be merged, got %s", status)
+	}
+
+	type activityEntry struct {
+		Kind    string `json:"kind"`
+		Details string `json:"details"`
+	}
+	var feed struct {
+		Activity []activityEntry `json:"activity"`
+	}
+	resp = doGet(t, ts, "/pullRequest/activity?pull_request_id="+url.QueryEscape("acme/api#7"))
+	json.NewDecoder(resp.Body).Decode(&feed)
+	resp.Body.Close()
+	if !slices.Contains(feed.Activity, activityEntry{Kind: "MERGED_EXTERNALLY", Details: "bob"}) {
+		t.Fatalf("expected the external merge in the activity feed, got %+v", feed.Activity)
+	}
+
+	if got := deliver("pull_request", merged, service.SignWebhook(forgeWebhookSecret, []byte(merged)), http.StatusOK); got.Action != "already_merged" {
+		t.Fatalf("expected a redelivery to find the PR merged, got %+v", got)
+	}
+
+	unknown := pullEvent(99, true)
+	if got := deliver("pull_request", unknown, service.SignWebhook(forgeWebhookSecret, []byte(unknown)), http.StatusOK); got.Action != "ignored" || got.Reason == "" {
+		t.Fatalf("expected an untracked PR to be ignored, got %+v", got)
+	}
+}
+
+func TestAdminReconcileStatuses(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	for _, id := range []string{"PR-R1", "PR-R2", "PR-R3"} {
+		resp := doPost(t, ts, "/pullRequest/create", fmt.Sprintf(`{"pull_request_id": %q, "pull_request_name": "Reconcile", "author_id": "u1"}`, id))
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("expected 201 for %s, got %d", id, resp.StatusCode)
+		}
+	}
+	resp := doPost(t, ts, "/pullRequest/merge", `{"pull_request_id": "PR-R3"}`)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200 for merge, got %d", resp.StatusCode)
+	}
+
+	type reconcileResponse struct {
+		Reconcile struct {
+			DryRun    bool `json:"dry_run"`
+			Checked   int  `json:"checked"`
+			Corrected int  `json:"corrected"`
+			Unchanged int  `json:"unchanged"`
+			NotFound  int  `json:"not_found"`
+			Results   []struct {
+				PullRequestID  string `json:"pull_request_id"`
+				Outcome        string `json:"outcome"`
+				PreviousStatus string `json:"previous_status"`
+			} `json:"results"`
+		} `json:"reconcile"`
+	}
+
+	body := `{"pull_requests": [
+		{"pull_request_id": "PR-R1", "status": "MERGED", "merged_at": "2026-03-01T10:00:00Z"},
+		{"pull_request_id": "PR-R2", "status": "OPEN"},
+		{"pull_request_id": "PR-R3", "status": "OPEN"},
+		{"pull_request_id": "PR-GHOST", "status": "OPEN"}
+	]}`
+
+	reconcile := func(path string) reconcileResponse {
+		t.Helper()
+		resp := doPost(t, ts, path, body)
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("expected 200 from %s, got %d", path, resp.StatusCode)
+		}
+		var data reconcileResponse
+		if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+			t.Fatalf("failed to decode reconcile response: %v", err)
+		}
+		return data
+	}
+
+	type storedPR struct {
+		ID       string     `db:"pull_request_id"`
+		Status   string     `db:"status"`
+		MergedAt *time.Time `db:"merged_at"`
+		MergedBy *int       `db:"merged_by"`
+	}
+	stored := func() map[string]storedPR {
+		t.Helper()
+		var rows []storedPR
+		err := ts.DB.Select(&rows, `SELECT pull_request_id, status, merged_at, merged_by FROM pull_requests WHERE pull_request_id LIKE 'PR-R%'`)
+		if err != nil {
+			t.Fatalf("failed to read PRs: %v", err)
+		}
+		prs := make(map[string]storedPR, len(rows))
+		for _, row := range rows {
+			prs[row.ID] = row
+		}
+		return prs
+	}
+
+	planned := reconcile("/admin/reconcile?dry_run=true").Reconcile
+	if !planned.DryRun || planned.Checked != 4 || planned.Corrected != 2 || planned.Unchanged != 1 || planned.NotFound != 1 {
+		t.Fatalf("expected 2 corrections, 1 unchanged and 1 unknown PR, got %+v", planned)
+	}
+	if prs := stored(); prs["PR-R1"].Status != "OPEN" || prs["PR-R3"].Status != "MERGED" {
+		t.Fatalf("expected a dry run to change nothing, got %+v", prs)
+	}
+
+	applied := reconcile("/admin/reconcile").Reconcile
+	if applied.DryRun || applied.Corrected != 2 {
+		t.Fatalf("expected 2 corrections applied, got %+v", applied)
+	}
+	if applied.Results[2].PullRequestID != "PR-R3" || applied.Results[2].Outcome != "CORRECTED" || applied.Results[2].PreviousStatus != "MERGED" {
+		t.Fatalf("expected PR-R3 to be reopened, got %+v", applied.Results[2])
+	}
+
+	prs := stored()
+	if prs["PR-R1"].Status != "MERGED" || prs["PR-R1"].MergedAt == nil ||
+		!prs["PR-R1"].MergedAt.Equal(time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected PR-R1 merged at the claimed time, got %+v", prs["PR-R1"])
+	}
+	if prs["PR-R3"].Status != "OPEN" || prs["PR-R3"].MergedAt != nil || prs["PR-R3"].MergedBy != nil {
+		t.Fatalf("expected PR-R3 reopened without merge data, got %+v", prs["PR-R3"])
+	}
+
+	var audited int
+	if err := ts.DB.Get(&audited, `SELECT COUNT(*) FROM audit_events WHERE action = 'PR_STATUS_RECONCILED'`); err != nil {
+		t.Fatalf("failed to count audit entries: %v", err)
+	}
+	if audited != 2 {
+		t.Fatalf("expected an audit entry per correction, got %d", audited)
+	}
+
+	if again := reconcile("/admin/reconcile").Reconcile; again.Corrected != 0 || again.Unchanged != 3 {
+		t.Fatalf("expected a repeated reconcile to change nothing, got %+v", again)
+	}
+
+	for _, invalid := range []string{
+		`{"pull_requests": []}`,
+		`{"pull_requests": [{"pull_request_id": "PR-R2", "status": "OPEN", "merged_at": "2026-03-01T10:00:00Z"}]}`,
+		`{"pull_requests": [{"pull_request_id": "PR-R2", "status": "SHIPPED"}]}`,
+		`{"pull_requests": [{"pull_request_id": "PR-R2", "status": "OPEN"}, {"pull_request_id": "PR-R2", "status": "MERGED"}]}`,
+	} {
+		resp := doPost(t, ts, "/admin/reconcile", invalid)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("expected 400 for %s, got %d", invalid, resp.StatusCode)
+		}
+	}
+}
+
+func TestPullRequestExportSearch(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	prs := []struct {
+		id, name, createdAt string
+	}{
+		{"PR-S1", "Refactor payments", "2026-02-03T09:00:00Z"},
+		{"PR-S2", "refactor_auth to 100%", "2026-02-10T09:00:00Z"},
+		{"PR-S3", "Fix typo", "2026-02-10T12:00:00Z"},
+	}
+	reviewers := make(map[string][]string)
+	for _, pr := range prs {
+		resp := doPost(t, ts, "/pullRequest/create",
+			fmt.Sprintf(`{"pull_request_id": %q, "pull_request_name": %q, "author_id": "u1"}`, pr.id, pr.name))
+		var created struct {
+			PR struct {
+				AssignedReviewers []string `json:"assigned_reviewers"`
+			} `json:"pr"`
+		}
+		json.NewDecoder(resp.Body).Decode(&created)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("expected 201 for %s, got %d", pr.id, resp.StatusCode)
+		}
+		reviewers[pr.id] = created.PR.AssignedReviewers
+
+		if _, err := ts.DB.Exec(`UPDATE pull_requests SET created_at = $1 WHERE pull_request_id = $2`, pr.createdAt, pr.id); err != nil {
+			t.Fatalf("failed to backdate %s: %v", pr.id, err)
+		}
+	}
+	if _, err := ts.DB.Exec(`UPDATE pull_requests SET status = 'MERGED', merged_at = '2026-02-11T08:00:00' WHERE pull_request_id = 'PR-S2'`); err != nil {
+		t.Fatalf("failed to merge PR-S2: %v", err)
+	}
+
+	search := func(params string) []string {
+		t.Helper()
+		resp := doGet(t, ts, "/pullRequest/export?"+params)
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("expected 200 for %s, got %d", params, resp.StatusCode)
+		}
+
+		ids := make([]string, 0)
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			var pr struct {
+				PullRequestID string `json:"pull_request_id"`
+			}
+			if err := json.Unmarshal(scanner.Bytes(), &pr); err != nil {
+				t.Fatalf("failed to decode export line: %v", err)
+			}
+			ids = append(ids, pr.PullRequestID)
+		}
+		return ids
+	}
+
+	reviewer := reviewers["PR-S3"][0]
+	for _, tc := range []struct {
+		params string
+		want   []string
+	}{
+		{"name=REFACTOR", []string{"PR-S1", "PR-S2"}},
+		{"name=" + url.QueryEscape("100%"), []string{"PR-S2"}},
+		{"name=" + url.QueryEscape("%"), []string{"PR-S2"}},
+		{"name=r_", []string{"PR-S2"}},
+		{"created_from=2026-02-10T00:00:00Z&created_to=2026-02-11T00:00:00Z", []string{"PR-S2", "PR-S3"}},
+		{"created_to=2026-02-10T09:00:00Z", []string{"PR-S1"}},
+		{"merged_from=2026-02-11T00:00:00Z&merged_to=2026-02-12T00:00:00Z", []string{"PR-S2"}},
+		{"name=refactor&created_from=2026-02-10T00:00:00Z", []string{"PR-S2"}},
+	} {
+		if got := search(tc.params); !slices.Equal(got, tc.want) {
+			t.Fatalf("%s: expected %v, got %v", tc.params, tc.want, got)
+		}
+	}
+
+	reviewed := search("reviewer_id=" + reviewer)
+	if !slices.Contains(reviewed, "PR-S3") {
+		t.Fatalf("expected PR-S3 among the PRs reviewed by %s, got %v", reviewer, reviewed)
+	}
+	for _, id := range reviewed {
+		if !slices.Contains(reviewers[id], reviewer) {
+			t.Fatalf("expected only PRs reviewed by %s, got %s", reviewer, id)
+		}
+	}
+
+	for _, params := range []string{
+		"created_from=last-tuesday",
+		"merged_from=2026-02-12T00:00:00Z&merged_to=2026-02-11T00:00:00Z",
+		"reviewer_id=bob",
+	} {
+		resp := doGet(t, ts, "/pullRequest/export?"+params)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("expected 400 for %s, got %d", params, resp.StatusCode)
+		}
+	}
+}
+
+func TestDailyAssignmentCap(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	resp := doWithHeader(t, ts, http.MethodPatch, "/users/settings?user_id=u2", `{"max_daily_assignments": -1}`, "Content-Type", "application/merge-patch+json")
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected 400 for a negative cap, got %d", resp.StatusCode)
+	}
+
+	for _, userID := range []string{"u2", "u3", "u4", "u5"} {
+		resp = doWithHeader(t, ts, http.MethodPatch, "/users/settings?user_id="+userID, `{"max_daily_assignments": 1}`, "Content-Type", "application/merge-patch+json")
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("failed to set daily cap of %s: %d", userID, resp.StatusCode)
+		}
+	}
+
+	var created struct {
+		PR struct {
+			AssignedReviewers []string `json:"assigned_reviewers"`
+		} `json:"pr"`
+		Trace struct {
+			Exclusions []struct {
+				UserID string `json:"user_id"`
+				Reason string `json:"reason"`
+			} `json:"exclusions"`
+		} `json:"trace"`
+	}
+
+	resp = doPost(t, ts, "/pullRequest/create", `{"pull_request_id": "PR-CAP1", "pull_request_name": "First", "author_id": "u1"}`)
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	resp.Body.Close()
+	first := created.PR.AssignedReviewers
+	if len(first) != 2 {
+		t.Fatalf("expected 2 reviewers, got %v", first)
+	}
+
+	resp = doPost(t, ts, "/pullRequest/create?debug=true", `{"pull_request_id": "PR-CAP2", "pull_request_name": "Second", "author_id": "u1"}`)
+	if resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		t.Fatalf("expected 201, got %d: %s", resp.StatusCode, string(body))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	resp.Body.Close()
+
+	for _, reviewer := range created.PR.AssignedReviewers {
+		if slices.Contains(first, reviewer) {
+			t.Fatalf("expected capped reviewer %s to be skipped, got %v", reviewer, created.PR.AssignedReviewers)
+		}
+	}
+
+	capped := make([]string, 0)
+	for _, exclusion := range created.Trace.Exclusions {
+		if exclusion.Reason == "DAILY_CAP" {
+			capped = append(capped, exclusion.UserID)
+		}
+	}
+	if !sameReviewers(capped, first) {
+		t.Fatalf("expected %v excluded as DAILY_CAP, got %+v", first, created.Trace.Exclusions)
+	}
+
+	var assignments int
+	if err := ts.DB.Get(&assignments, `SELECT COALESCE(SUM(assignments), 0) FROM reviewer_daily_assignments`); err != nil {
+		t.Fatalf("failed to count daily assignments: %v", err)
+	}
+	if assignments != 4 {
+		t.Fatalf("expected 4 counted assignments, got %d", assignments)
+	}
+
+	resp = doPost(t, ts, "/pullRequest/create", `{"pull_request_id": "PR-CAP3", "pull_request_name": "Third", "author_id": "u1"}`)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected 404 with every reviewer capped, got %d", resp.StatusCode)
+	}
+}
+
+func TestReviewerPairDiversity(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	_, err = ts.DB.Exec(`
+		INSERT INTO pull_requests (pull_request_id, pull_request_name, author_id, status)
+		SELECT 'PR-PAIR' || g, 'Paired', 1, 'MERGED' FROM generate_series(1, 20) g
+	`)
+	if err != nil {
+		t.Fatalf("failed to seed PRs: %v", err)
+	}
+
+	_, err = ts.DB.Exec(`
+		INSERT INTO pr_reviewers (pull_request_id, reviewer_id)
+		SELECT 'PR-PAIR' || g, r FROM generate_series(1, 20) g CROSS JOIN (VALUES (2), (3)) AS v(r)
+	`)
+	if err != nil {
+		t.Fatalf("failed to seed reviews: %v", err)
+	}
+
+	resp := doWithKey(t, ts, http.MethodGet, "/stats/pairing?team_name=Backend", "", ts.AdminKey)
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		t.Fatalf("expected 200, got %d: %s", resp.StatusCode, string(body))
+	}
+
+	var stats struct {
+		Pairing struct {
+			Members []string `json:"members"`
+			Matrix  [][]int  `json:"matrix"`
+			Pairs   []struct {
+				FirstID   string `json:"first_id"`
+				SecondID  string `json:"second_id"`
+				CoReviews int    `json:"co_reviews"`
+			} `json:"pairs"`
+		} `json:"pairing"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&stats); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	resp.Body.Close()
+
+	pairing := stats.Pairing
+	if !slices.Equal(pairing.Members, []string{"u1", "u2", "u3", "u4", "u5"}) {
+		t.Fatalf("expected the Backend members, got %v", pairing.Members)
+	}
+	if pairing.Matrix[1][2] != 20 || pairing.Matrix[2][1] != 20 || pairing.Matrix[0][1] != 0 {
+		t.Fatalf("expected u2 and u3 to have co-reviewed 20 PRs, got %v", pairing.Matrix)
+	}
+	if len(pairing.Pairs) != 1 || pairing.Pairs[0].FirstID != "u2" || pairing.Pairs[0].SecondID != "u3" {
+		t.Fatalf("expected the single u2-u3 pair, got %+v", pairing.Pairs)
+	}
+
+	resp = doWithKey(t, ts, http.MethodGet, "/stats/pairing?team_name=Ghost", "", ts.AdminKey)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected 404 for an unknown team, got %d", resp.StatusCode)
+	}
+
+	resp = doPost(t, ts, "/team/update", `{"team_name": "Backend", "diversify_reviewer_pairs": true}`)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("failed to enable pair diversity: %d", resp.StatusCode)
+	}
+
+	for i := 1; i <= 5; i++ {
+		resp = doPost(t, ts, "/pullRequest/create",
+			fmt.Sprintf(`{"pull_request_id": "PR-DIV%d", "pull_request_name": "Diverse", "author_id": "u4"}`, i))
+		var created struct {
+			PR struct {
+				AssignedReviewers []string `json:"assigned_reviewers"`
+			} `json:"pr"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		resp.Body.Close()
+
+		reviewers := created.PR.AssignedReviewers
+		if len(reviewers) != 2 {
+			t.Fatalf("expected 2 reviewers, got %v", reviewers)
+		}
+		if slices.Contains(reviewers, "u2") && slices.Contains(reviewers, "u3") {
+			t.Fatalf("expected the frequent u2-u3 pair to be avoided, got %v", reviewers)
+		}
+	}
+}
+
+func TestTeamReviewersPerPR(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	for _, body := range []string{`{"team_name": "Backend", "reviewers_per_pr": 0}`, `{"team_name": "Backend", "reviewers_per_pr": 6}`} {
+		resp := doPost(t, ts, "/team/update", body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("expected 400 for %s, got %d", body, resp.StatusCode)
+		}
+	}
+
+	resp := doPost(t, ts, "/team/update", `{"team_name": "Backend", "reviewers_per_pr": 3}`)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("failed to set reviewers per PR: %d", resp.StatusCode)
+	}
+
+	createPR := func(prID string) []string {
+		t.Helper()
+
+		resp := doPost(t, ts, "/pullRequest/create",
+			fmt.Sprintf(`{"pull_request_id": "%s", "pull_request_name": "Sized", "author_id": "u1"}`, prID))
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusCreated {
+			body, _ := io.ReadAll(resp.Body)
+			t.Fatalf("expected 201, got %d: %s", resp.StatusCode, string(body))
+		}
+
+		var created struct {
+			PR struct {
+				AssignedReviewers []string `json:"assigned_reviewers"`
+			} `json:"pr"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		return created.PR.AssignedReviewers
+	}
+
+	if reviewers := createPR("PR-RPP1"); len(reviewers) != 3 {
+		t.Fatalf("expected 3 reviewers from the team policy, got %v", reviewers)
+	}
+
+	resp = doPost(t, ts, "/team/update", `{"team_name": "Backend", "reviewers_per_pr": null}`)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("failed to reset reviewers per PR: %d", resp.StatusCode)
+	}
+
+	if reviewers := createPR("PR-RPP2"); len(reviewers) != 2 {
+		t.Fatalf("expected the org default of 2 reviewers, got %v", reviewers)
+	}
+}
+
+func TestConcurrentTeamCreation(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	const attempts = 10
+
+	var wg sync.WaitGroup
+	statuses := make(chan int, attempts)
+
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			body := fmt.Sprintf(`{"team_name": "Platform", "members": [{"user_id": "u%d", "username": "Racer%d", "is_active": true}]}`, 100+i, i)
+			resp, err := http.Post(ts.Server.URL+"/team/add", "application/json", strings.NewReader(body))
+			if err != nil {
+				t.Errorf("request failed: %v", err)
+				return
+			}
+			resp.Body.Close()
+
+			statuses <- resp.StatusCode
+		}(i)
+	}
+
+	wg.Wait()
+	close(statuses)
+
+	created, exists := 0, 0
+	for status := range statuses {
+		switch status {
+		case http.StatusCreated:
+			created++
+		case http.StatusBadRequest:
+			exists++
+		default:
+			t.Fatalf("unexpected status %d", status)
+		}
+	}
+	if created != 1 || exists != attempts-1 {
+		t.Fatalf("expected one creation and %d TEAM_EXISTS, got %d and %d", attempts-1, created, exists)
+	}
+
+	var users int
+	if err := ts.DB.Get(&users, `SELECT COUNT(*) FROM users WHERE user_id >= 100`); err != nil {
+		t.Fatalf("failed to count users: %v", err)
+	}
+	if users != 1 {
+		t.Fatalf("expected only the winner's member to be written, got %d users", users)
+	}
+
+	// Teams sharing members in opposite order must not deadlock.
+	statuses = make(chan int, attempts)
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			members := `{"user_id": "u200", "username": "Shared1"}, {"user_id": "u201", "username": "Shared2"}`
+			if i%2 == 1 {
+				members = `{"user_id": "u201", "username": "Shared2"}, {"user_id": "u200", "username": "Shared1"}`
+			}
+			body := fmt.Sprintf(`{"team_name": "Shared%d", "members": [%s]}`, i, members)
+			resp, err := http.Post(ts.Server.URL+"/team/add", "application/json", strings.NewReader(body))
+			if err != nil {
+				t.Errorf("request failed: %v", err)
+				return
+			}
+			resp.Body.Close()
+
+			statuses <- resp.StatusCode
+		}(i)
+	}
+
+	wg.Wait()
+	close(statuses)
+
+	for status := range statuses {
+		if status != http.StatusCreated {
+			t.Fatalf("expected every team with shared members to be created, got %d", status)
+		}
+	}
+}
+
+func TestSetReviewerCount(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	resp := doPost(t, ts, "/pullRequest/create",
+		`{"pull_request_id": "PR-RC1", "pull_request_name": "Resize", "author_id": "u1"}`)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("failed to create PR: %d", resp.StatusCode)
+	}
+
+	type resized struct {
+		PR struct {
+			AssignedReviewers []string `json:"assigned_reviewers"`
+		} `json:"pr"`
+		Added   []string `json:"added"`
+		Removed []string `json:"removed"`
+	}
+
+	setCount := func(count int, wantStatus int) resized {
+		t.Helper()
+
+		resp := doPost(t, ts, "/pullRequest/setReviewerCount",
+			fmt.Sprintf(`{"pull_request_id": "PR-RC1", "reviewers": %d}`, count))
+		defer resp.Body.Close()
+
+		if resp.StatusCode != wantStatus {
+			body, _ := io.ReadAll(resp.Body)
+			t.Fatalf("expected %d for %d reviewers, got %d: %s", wantStatus, count, resp.StatusCode, string(body))
+		}
+
+		var data resized
+		if wantStatus == http.StatusOK {
+			if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+		}
+		return data
+	}
+
+	grown := setCount(4, http.StatusOK)
+	if len(grown.PR.AssignedReviewers) != 4 || len(grown.Added) != 2 || len(grown.Removed) != 0 {
+		t.Fatalf("expected 2 extra reviewers, got %+v", grown)
+	}
+	if slices.Contains(grown.PR.AssignedReviewers, "u1") {
+		t.Fatalf("author assigned as reviewer: %v", grown.PR.AssignedReviewers)
+	}
+
+	setCount(5, http.StatusConflict)
+
+	shrunk := setCount(2, http.StatusOK)
+	if len(shrunk.PR.AssignedReviewers) != 2 || len(shrunk.Removed) != 2 {
+		t.Fatalf("expected 2 reviewers removed, got %+v", shrunk)
+	}
+	for _, reviewerID := range grown.Added {
+		if !slices.Contains(shrunk.Removed, reviewerID) {
+			t.Fatalf("expected the most recently added reviewers %v to go, removed %v", grown.Added, shrunk.Removed)
+		}
+	}
+
+	setCount(0, http.StatusBadRequest)
+
+	resp = doPost(t, ts, "/team/update", `{"team_name": "Backend", "min_reviewers": 2}`)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("failed to set min reviewers: %d", resp.StatusCode)
+	}
+
+	setCount(1, http.StatusConflict)
+}
+
+func TestNotificationTemplates(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	received := make(chan []byte, 4)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- body
+	}))
+	defer receiver.Close()
+
+	resp := doPost(t, ts, "/team/webhooks/create",
+		`{"team_name": "Backend", "url": "`+receiver.URL+`", "secret": "s", "events": ["pr.created"]}`)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("failed to create webhook: %d", resp.StatusCode)
+	}
+
+	for _, body := range []string{
+		`{"event": "pull_request.exploded", "channel": "webhook", "body": "x"}`,
+		`{"event": "pull_request.created", "channel": "pager", "body": "x"}`,
+		`{"event": "pull_request.created", "channel": "webhook", "body": ""}`,
+		`{"event": "pull_request.created", "channel": "webhook", "body": "{{.PullRequestName"}`,
+		`{"event": "pull_request.created", "channel": "webhook", "body": "{{.Assignee}}"}`,
+		`{"event": "pull_request.created", "channel": "webhook", "body": "{{.Data.reviewer_id}}"}`,
+	} {
+		resp := doPost(t, ts, "/admin/templates/save", body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("expected 400 for %s, got %d", body, resp.StatusCode)
+		}
+	}
+
+	resp = doPost(t, ts, "/admin/templates/save", `{
+		"event": "pull_request.created",
+		"channel": "webhook",
+		"body": "{{.PullRequestName}} by {{.AuthorName}}, {{len .Data.reviewers}} reviewers, due {{.DueAt.Format \"2006-01-02\"}}"
+	}`)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("failed to save template: %d", resp.StatusCode)
+	}
+
+	resp = doPost(t, ts, "/pullRequest/create", `{"pull_request_id": "PR-NT1", "pull_request_name": "Templated", "author_id": "u1"}`)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("failed to create PR: %d", resp.StatusCode)
+	}
+
+	var body []byte
+	select {
+	case body = <-received:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("webhook was not called")
+	}
+
+	var delivery struct {
+		Text string `json:"text"`
+	}
+	if err := json.Unmarshal(body, &delivery); err != nil {
+		t.Fatalf("failed to decode delivery: %v", err)
+	}
+
+	due := time.Now().UTC().Add(24 * time.Hour).Format("2006-01-02")
+	if want := "Templated by Alice, 2 reviewers, due " + due; delivery.Text != want {
+		t.Fatalf("expected text %q, got %q", want, delivery.Text)
+	}
+
+	resp = doGet(t, ts, "/admin/templates")
+	defer resp.Body.Close()
+
+	var list struct {
+		Templates []struct {
+			Event   string `json:"event"`
+			Channel string `json:"channel"`
+		} `json:"templates"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(list.Templates) != 1 || list.Templates[0].Event != "pull_request.created" || list.Templates[0].Channel != "webhook" {
+		t.Fatalf("unexpected templates %+v", list.Templates)
+	}
+
+	for _, status := range []int{http.StatusOK, http.StatusNotFound} {
+		resp := doPost(t, ts, "/admin/templates/delete", `{"event": "pull_request.created", "channel": "webhook"}`)
+		resp.Body.Close()
+		if resp.StatusCode != status {
+			t.Fatalf("expected %d on delete, got %d", status, resp.StatusCode)
+		}
+	}
+}
+
+func TestDeclineReview(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	type declined struct {
+		PR struct {
+			AssignedReviewers []string `json:"assigned_reviewers"`
+			UnderReviewed     bool     `json:"under_reviewed"`
+		} `json:"pr"`
+		ReplacedBy string `json:"replaced_by"`
+	}
+
+	decode := func(resp *http.Response) declined {
+		t.Helper()
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+			body, _ := io.ReadAll(resp.Body)
+			t.Fatalf("unexpected status %d: %s", resp.StatusCode, string(body))
+		}
+
+		var data declined
+		if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		return data
+	}
+
+	created := decode(doPost(t, ts, "/pullRequest/create",
+		`{"pull_request_id": "PR-D1", "pull_request_name": "Decline", "author_id": "u1"}`))
+	if len(created.PR.AssignedReviewers) != 2 {
+		t.Fatalf("expected 2 reviewers, got %v", created.PR.AssignedReviewers)
+	}
+	first := created.PR.AssignedReviewers[0]
+
+	replaced := decode(doPostAs(t, ts, "/pullRequest/decline", `{"pull_request_id": "PR-D1"}`, first))
+	if replaced.ReplacedBy == "" || replaced.ReplacedBy == first {
+		t.Fatalf("expected a replacement for %s, got %q", first, replaced.ReplacedBy)
+	}
+	if len(replaced.PR.AssignedReviewers) != 2 || slices.Contains(replaced.PR.AssignedReviewers, first) {
+		t.Errorf("unexpected reviewers after decline: %v", replaced.PR.AssignedReviewers)
+	}
+	if replaced.PR.UnderReviewed {
+		t.Error("PR must not be under-reviewed when a replacement was found")
+	}
+
+	// Leave the backend team without anyone to take over.
+	for _, id := range []string{"u2", "u3", "u4", "u5"} {
+		if slices.Contains(replaced.PR.AssignedReviewers, id) {
+			continue
+		}
+		resp := doPost(t, ts, "/users/setIsActive", fmt.Sprintf(`{"user_id": "%s", "is_active": false}`, id))
+		resp.Body.Close()
+	}
+
+	second := replaced.PR.AssignedReviewers[0]
+	stepped := decode(doPostAs(t, ts, "/pullRequest/decline",
+		fmt.Sprintf(`{"pull_request_id": "PR-D1", "reviewer_id": "%s"}`, second), second))
+	if stepped.ReplacedBy != "" {
+		t.Errorf("expected no replacement, got %s", stepped.ReplacedBy)
+	}
+	if len(stepped.PR.AssignedReviewers) != 1 || !stepped.PR.UnderReviewed {
+		t.Errorf("expected one reviewer left and the PR under-reviewed, got %+v", stepped.PR)
+	}
+
+	again := doPostAs(t, ts, "/pullRequest/decline", `{"pull_request_id": "PR-D1"}`, second)
+	again.Body.Close()
+	if again.StatusCode != http.StatusNotFound {
+		t.Errorf("expected 404 for declining twice, got %d", again.StatusCode)
+	}
+
+	reactivate := doPost(t, ts, "/users/setIsActive", fmt.Sprintf(`{"user_id": "%s", "is_active": true}`, first))
+	reactivate.Body.Close()
+
+	assigned := decode(doPost(t, ts, "/pullRequest/assign",
+		fmt.Sprintf(`{"pull_request_id": "PR-D1", "reviewer_id": "%s"}`, first)))
+	if assigned.PR.UnderReviewed {
+		t.Error("a new assignment must clear the under-reviewed mark")
+	}
+}
+
+func TestDashboard(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	type dashboard struct {
+		PullRequests []struct {
+			PullRequestID     string   `json:"pull_request_id"`
+			PullRequestName   string   `json:"pull_request_name"`
+			AuthorName        string   `json:"author_name"`
+			TeamName          string   `json:"team_name"`
+			AssignedReviewers []string `json:"assigned_reviewers"`
+			Approvers         []string `json:"approvers"`
+			Approvals         int      `json:"approvals"`
+			DueAt             *string  `json:"due_at"`
+			Overdue           bool     `json:"overdue"`
+		} `json:"pull_requests"`
+		NextCursor string `json:"next_cursor"`
+	}
+
+	get := func(path string) dashboard {
+		t.Helper()
+		resp := doGet(t, ts, path)
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			t.Fatalf("GET %s: expected 200, got %d: %s", path, resp.StatusCode, string(body))
+		}
+
+		var data dashboard
+		if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+			t.Fatalf("failed to decode dashboard: %v", err)
+		}
+		return data
+	}
+
+	for _, body := range []string{
+		`{"pull_request_id": "PR-DB1", "pull_request_name": "Backend change", "author_id": "u1"}`,
+		`{"pull_request_id": "PR-DB2", "pull_request_name": "QA change", "author_id": "u10"}`,
+	} {
+		resp := doPost(t, ts, "/pullRequest/create", body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("failed to create PR: %d", resp.StatusCode)
+		}
+	}
+
+	backend := get("/pullRequest/dashboard?team_name=Backend")
+	if len(backend.PullRequests) != 1 || backend.PullRequests[0].PullRequestID != "PR-DB1" {
+		t.Fatalf("expected only PR-DB1 for Backend, got %+v", backend.PullRequests)
+	}
+	row := backend.PullRequests[0]
+	if row.AuthorName != "Alice" || row.TeamName != "Backend" || len(row.AssignedReviewers) != 2 {
+		t.Errorf("unexpected dashboard row: %+v", row)
+	}
+	if row.DueAt == nil || row.Overdue || row.Approvals != 0 {
+		t.Errorf("expected an open PR due later without approvals, got %+v", row)
+	}
+
+	approver := row.AssignedReviewers[0]
+	resp := doPost(t, ts, "/pullRequest/approve",
+		fmt.Sprintf(`{"pull_request_id": "PR-DB1", "reviewer_id": "%s"}`, approver))
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("failed to approve: %d", resp.StatusCode)
+	}
+
+	approved := get("/pullRequest/dashboard?reviewer_id=" + approver)
+	if len(approved.PullRequests) != 1 || approved.PullRequests[0].Approvals != 1 ||
+		!slices.Equal(approved.PullRequests[0].Approvers, []string{approver}) {
+		t.Errorf("expected the approval on the dashboard, got %+v", approved.PullRequests)
+	}
+
+	if overdue := get("/pullRequest/dashboard?overdue=true"); len(overdue.PullRequests) != 0 {
+		t.Errorf("expected no overdue PRs, got %+v", overdue.PullRequests)
+	}
+
+	first := get("/pullRequest/dashboard?limit=1")
+	if len(first.PullRequests) != 1 || first.NextCursor == "" {
+		t.Fatalf("expected one row and a cursor, got %+v", first)
+	}
+	second := get("/pullRequest/dashboard?limit=1&cursor=" + first.NextCursor)
+	if len(second.PullRequests) != 1 || second.PullRequests[0].PullRequestID == first.PullRequests[0].PullRequestID {
+		t.Fatalf("expected the other PR on the second page, got %+v", second.PullRequests)
+	}
+
+	invalid := doGet(t, ts, "/pullRequest/dashboard?cursor=not-a-cursor")
+	invalid.Body.Close()
+	if invalid.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected 400 for an invalid cursor, got %d", invalid.StatusCode)
+	}
+
+	// A change made without an event shows up after the rebuild.
+	if _, err := ts.DB.Exec(`UPDATE pull_requests SET pull_request_name = 'Renamed' WHERE pull_request_id = 'PR-DB2'`); err != nil {
+		t.Fatalf("failed to rename PR: %v", err)
+	}
+	if err := ts.Dashboard.Rebuild(context.Background()); err != nil {
+		t.Fatalf("failed to rebuild dashboard: %v", err)
+	}
+
+	qa := get("/pullRequest/dashboard?team_name=QA")
+	if len(qa.PullRequests) != 1 || qa.PullRequests[0].PullRequestName != "Renamed" {
+		t.Errorf("expected the rebuilt row, got %+v", qa.PullRequests)
+	}
+}
+
+func TestMultiTeamMembership(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	type created struct {
+		PR struct {
+			AuthorTeam        string   `json:"author_team"`
+			AssignedReviewers []string `json:"assigned_reviewers"`
+		} `json:"pr"`
+	}
+
+	decode := func(resp *http.Response) created {
+		t.Helper()
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusCreated {
+			body, _ := io.ReadAll(resp.Body)
+			t.Fatalf("expected 201, got %d: %s", resp.StatusCode, string(body))
+		}
+
+		var data created
+		if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		return data
+	}
+
+	expectStatus := func(resp *http.Response, status int) {
+		t.Helper()
+		defer resp.Body.Close()
+
+		if resp.StatusCode != status {
+			body, _ := io.ReadAll(resp.Body)
+			t.Fatalf("expected %d, got %d: %s", status, resp.StatusCode, string(body))
+		}
+	}
+
+	// Ivan stays in QA and joins Backend as a secondary member.
+	expectStatus(doPost(t, ts, "/team/members/setSecondary",
+		`{"team_name": "Backend", "user_id": "u10", "is_member": true}`), http.StatusOK)
+	expectStatus(doPost(t, ts, "/team/members/setSecondary",
+		`{"team_name": "QA", "user_id": "u10", "is_member": true}`), http.StatusConflict)
+
+	primary := decode(doPost(t, ts, "/pullRequest/create",
+		`{"pull_request_id": "PR-MT1", "pull_request_name": "QA work", "author_id": "u10"}`))
+	if primary.PR.AuthorTeam != "QA" || !slices.Equal(primary.PR.AssignedReviewers, []string{"u11"}) {
+		t.Fatalf("expected the primary team to review, got %+v", primary.PR)
+	}
+
+	hinted := decode(doPost(t, ts, "/pullRequest/create",
+		`{"pull_request_id": "PR-MT2", "pull_request_name": "Backend work", "author_id": "u10", "team_name": "Backend"}`))
+	if hinted.PR.AuthorTeam != "Backend" || len(hinted.PR.AssignedReviewers) != 2 {
+		t.Fatalf("expected two backend reviewers, got %+v", hinted.PR)
+	}
+	for _, id := range hinted.PR.AssignedReviewers {
+		if !slices.Contains([]string{"u1", "u2", "u3", "u4", "u5"}, id) {
+			t.Errorf("reviewer %s is not a backend member", id)
+		}
+	}
+
+	expectStatus(doPost(t, ts, "/pullRequest/create",
+		`{"pull_request_id": "PR-MT3", "pull_request_name": "Elsewhere", "author_id": "u10", "team_name": "Nope"}`),
+		http.StatusBadRequest)
+
+	// With the rest of Backend inactive only the secondary member is left.
+	for _, id := range []string{"u2", "u3", "u4", "u5"} {
+		expectStatus(doPost(t, ts, "/users/setIsActive", fmt.Sprintf(`{"user_id": "%s", "is_active": false}`, id)), http.StatusOK)
+	}
+	backend := decode(doPost(t, ts, "/pullRequest/create",
+		`{"pull_request_id": "PR-MT4", "pull_request_name": "Backend only", "author_id": "u1"}`))
+	if backend.PR.AuthorTeam != "Backend" || !slices.Equal(backend.PR.AssignedReviewers, []string{"u10"}) {
+		t.Fatalf("expected the secondary member to review, got %+v", backend.PR)
+	}
+
+	// Secondary rows are not membership drift.
+	resp := doGet(t, ts, "/admin/membership")
+	defer resp.Body.Close()
+	var membership struct {
+		Membership struct {
+			Drift []json.RawMessage `json:"drift"`
+		} `json:"membership"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&membership); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(membership.Membership.Drift) != 0 {
+		t.Errorf("expected no drift, got %s", membership.Membership.Drift)
+	}
+
+	expectStatus(doPost(t, ts, "/team/members/setSecondary",
+		`{"team_name": "Backend", "user_id": "u10", "is_member": false}`), http.StatusOK)
+	expectStatus(doPost(t, ts, "/pullRequest/create",
+		`{"pull_request_id": "PR-MT5", "pull_request_name": "Left", "author_id": "u10", "team_name": "Backend"}`),
+		http.StatusBadRequest)
+}
+
+func TestWebhookRetries(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	type attempt struct {
+		event  string
+		number string
+		body   []byte
+	}
+
+	receive := func(statuses ...int) (*httptest.Server, chan attempt) {
+		received := make(chan attempt, 8)
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			received <- attempt{event: r.Header.Get("X-Webhook-Event"), number: r.Header.Get("X-Webhook-Attempt"), body: body}
+			status := http.StatusOK
+			if n := int(calls.Add(1)); n <= len(statuses) {
+				status = statuses[n-1]
+			}
+			w.WriteHeader(status)
+		}))
+		return server, received
+	}
+
+	flaky, flakyAttempts := receive(http.StatusServiceUnavailable)
+	defer flaky.Close()
+	rejecting, rejectingAttempts := receive(http.StatusBadRequest, http.StatusBadRequest)
+	defer rejecting.Close()
+
+	for _, url := range []string{flaky.URL, rejecting.URL} {
+		resp := doPost(t, ts, "/webhooks/add",
+			`{"team_name": "Backend", "url": "`+url+`", "secret": "s", "events": ["pr.merged"]}`)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("expected 201, got %d", resp.StatusCode)
+		}
+	}
+
+	for _, call := range []struct{ path, body string }{
+		{"/pullRequest/create", `{"pull_request_id": "PR-WR1", "pull_request_name": "Retry", "author_id": "u1"}`},
+		{"/pullRequest/merge", `{"pull_request_id": "PR-WR1"}`},
+	} {
+		resp := doPost(t, ts, call.path, call.body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+			t.Fatalf("POST %s: unexpected status %d", call.path, resp.StatusCode)
+		}
+	}
+
+	next := func(received chan attempt) attempt {
+		t.Helper()
+		select {
+		case got := <-received:
+			return got
+		case <-time.After(5 * time.Second):
+			t.Fatalf("webhook was not called")
+		}
+		return attempt{}
+	}
+
+	first, second := next(flakyAttempts), next(flakyAttempts)
+	if first.event != "pr.merged" || first.number != "1" || second.number != "2" {
+		t.Fatalf("expected a merged delivery and one retry, got %q/%q and %q", first.event, first.number, second.number)
+	}
+	if !bytes.Equal(first.body, second.body) {
+		t.Errorf("retry must repeat the delivery body")
+	}
+
+	if got := next(rejectingAttempts); got.number != "1" {
+		t.Fatalf("expected the first attempt, got %q", got.number)
+	}
+	select {
+	case got := <-rejectingAttempts:
+		t.Fatalf("a 400 must not be retried, got attempt %q", got.number)
+	case <-time.After(300 * time.Millisecond):
+	}
+
+	resp := doGet(t, ts, "/webhooks/list?team_name=Backend")
+	defer resp.Body.Close()
+
+	var list struct {
+		Webhooks []struct {
+			URL        string `json:"url"`
+			LastStatus *int   `json:"last_status"`
+		} `json:"webhooks"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(list.Webhooks) != 2 {
+		t.Fatalf("expected 2 webhooks, got %+v", list.Webhooks)
+	}
+	for _, hook := range list.Webhooks {
+		want := http.StatusOK
+		if hook.URL == rejecting.URL {
+			want = http.StatusBadRequest
+		}
+		if hook.LastStatus == nil || *hook.LastStatus != want {
+			t.Errorf("webhook %s: expected last status %d, got %v", hook.URL, want, hook.LastStatus)
+		}
+	}
+
+	failuresResp := doGet(t, ts, "/admin/webhooks/failures?team_name=Backend")
+	defer failuresResp.Body.Close()
+
+	var failures struct {
+		Deliveries []struct {
+			URL        string `json:"url"`
+			Event      string `json:"event"`
+			Status     string `json:"status"`
+			Attempt    int    `json:"attempt"`
+			LastStatus *int   `json:"last_status"`
+		} `json:"deliveries"`
+	}
+	if err := json.NewDecoder(failuresResp.Body).Decode(&failures); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(failures.Deliveries) != 1 {
+		t.Fatalf("expected only the rejected delivery to be kept, got %+v", failures.Deliveries)
+	}
+	failed := failures.Deliveries[0]
+	if failed.URL != rejecting.URL || failed.Event != "pr.merged" || failed.Status != "FAILED" || failed.Attempt != 1 ||
+		failed.LastStatus == nil || *failed.LastStatus != http.StatusBadRequest {
+		t.Fatalf("unexpected failed delivery %+v", failed)
+	}
+
+	var pending int
+	if err := ts.DB.Get(&pending, `SELECT COUNT(*) FROM webhook_deliveries WHERE status = 'PENDING'`); err != nil {
+		t.Fatalf("failed to count pending deliveries: %v", err)
+	}
+	if pending != 0 {
+		t.Fatalf("expected the delivered retry to be deleted, got %d pending", pending)
+	}
+}
+
+func TestHandoffNote(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	received := make(chan []byte, 4)
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- body
+	}))
+	defer hook.Close()
+
+	expectStatus := func(resp *http.Response, status int) *http.Response {
+		t.Helper()
+		if resp.StatusCode != status {
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			t.Fatalf("expected %d, got %d: %s", status, resp.StatusCode, string(body))
+		}
+		return resp
+	}
+
+	expectStatus(doPost(t, ts, "/team/webhooks/create",
+		`{"team_name": "Backend", "url": "`+hook.URL+`", "secret": "s", "events": ["reviewer.reassigned"]}`), http.StatusCreated).Body.Close()
+
+	resp := expectStatus(doPost(t, ts, "/pullRequest/create",
+		`{"pull_request_id": "PR-H1", "pull_request_name": "Handoff", "author_id": "u1"}`), http.StatusCreated)
+	var created struct {
+		PR struct {
+			AssignedReviewers []string `json:"assigned_reviewers"`
+		} `json:"pr"`
+	}
+	json.NewDecoder(resp.Body).Decode(&created)
+	resp.Body.Close()
+	old := created.PR.AssignedReviewers[0]
+
+	tooLong := strings.Repeat("x", 1001)
+	resp = expectStatus(doPost(t, ts, "/pullRequest/reassign",
+		fmt.Sprintf(`{"pull_request_id": "PR-H1", "old_reviewer_id": %q, "reason": "vacation", "note": %q}`, old, tooLong)),
+		http.StatusBadRequest)
+	var failure struct {
+		Error struct {
+			Code string `json:"code"`
+		} `json:"error"`
+	}
+	json.NewDecoder(resp.Body).Decode(&failure)
+	resp.Body.Close()
+	if failure.Error.Code != "INVALID_NOTE" {
+		t.Fatalf("expected INVALID_NOTE, got %q", failure.Error.Code)
+	}
+
+	const note = "I reviewed files A and B already"
+	resp = expectStatus(doPost(t, ts, "/pullRequest/reassign",
+		fmt.Sprintf(`{"pull_request_id": "PR-H1", "old_reviewer_id": %q, "reason": "vacation", "note": %q}`, old, note)),
+		http.StatusOK)
+	var reassigned struct {
+		ReplacedBy string `json:"replaced_by"`
+	}
+	json.NewDecoder(resp.Body).Decode(&reassigned)
+	resp.Body.Close()
+
+	resp = expectStatus(doGet(t, ts, "/users/getReview?user_id="+reassigned.ReplacedBy), http.StatusOK)
+	type review struct {
+		PullRequestID string `json:"pull_request_id"`
+		HandoffNote   string `json:"handoff_note"`
+	}
+	var reviews struct {
+		PullRequests []review `json:"pull_requests"`
+	}
+	json.NewDecoder(resp.Body).Decode(&reviews)
+	resp.Body.Close()
+	if !slices.Contains(reviews.PullRequests, review{PullRequestID: "PR-H1", HandoffNote: note}) {
+		t.Fatalf("expected the note in the new reviewer's reviews, got %+v", reviews.PullRequests)
+	}
+
+	select {
+	case body := <-received:
+		var delivery struct {
+			Event string `json:"event"`
+			Data  struct {
+				NewReviewerID string `json:"new_reviewer_id"`
+				Note          string `json:"note"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(body, &delivery); err != nil {
+			t.Fatalf("failed to decode delivery: %v", err)
+		}
+		if delivery.Event != "reviewer.reassigned" || delivery.Data.Note != note || delivery.Data.NewReviewerID != reassigned.ReplacedBy {
+			t.Fatalf("unexpected delivery %s", body)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("webhook was not called")
+	}
+
+	resp = expectStatus(doGet(t, ts, "/pullRequest/activity?pull_request_id=PR-H1"), http.StatusOK)
+	var feed struct {
+		Activity []struct {
+			Details string `json:"details"`
+		} `json:"activity"`
+	}
+	json.NewDecoder(resp.Body).Decode(&feed)
+	resp.Body.Close()
+	found := false
+	for _, entry := range feed.Activity {
+		found = found || entry.Details == "REASSIGN VACATION: "+note
+	}
+	if !found {
+		t.Fatalf("expected the note in the activity feed, got %+v", feed.Activity)
+	}
+}
+
+func TestMetaEnums(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	if _, err := ts.DB.Exec(`INSERT INTO pr_statuses (status, is_terminal) VALUES ('IN_QA', false) ON CONFLICT DO NOTHING`); err != nil {
+		t.Fatalf("failed to add status: %v", err)
+	}
+
+	type enumsResponse struct {
+		Language string `json:"language"`
+		Enums    []struct {
+			Name   string `json:"name"`
+			Values []struct {
+				Value string `json:"value"`
+				Label string `json:"label"`
+			} `json:"values"`
+		} `json:"enums"`
+	}
+
+	fetch := func(language string) map[string]map[string]string {
+		t.Helper()
+
+		req, err := http.NewRequest(http.MethodGet, ts.Server.URL+"/meta/enums", nil)
+		if err != nil {
+			t.Fatalf("failed to build request: %v", err)
+		}
+		req.Header.Set("Accept-Language", language)
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("GET /meta/enums failed: %v", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("expected 200, got %d", resp.StatusCode)
+		}
+
+		var data enumsResponse
+		if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if data.Language != language {
+			t.Fatalf("expected language %s, got %s", language, data.Language)
+		}
+
+		labels := make(map[string]map[string]string)
+		for _, enum := range data.Enums {
+			labels[enum.Name] = make(map[string]string)
+			for _, value := range enum.Values {
+				labels[enum.Name][value.Value] = value.Label
+			}
+		}
+		return labels
+	}
+
+	en := fetch("en")
+	for _, check := range []struct{ enum, value, label string }{
+		{"pr_status", "OPEN", "Open"},
+		{"pr_status", "IN_QA", "In qa"},
+		{"priority", "HIGH", "High"},
+		{"reassign_reason", "OFFBOARDED", "Offboarded"},
+		{"assignment_action", "DELEGATE", "Delegated"},
+	} {
+		if got := en[check.enum][check.value]; got != check.label {
+			t.Errorf("%s %s: expected %q, got %q", check.enum, check.value, check.label, got)
+		}
+	}
+
+	ru := fetch("ru")
+	if got := ru["priority"]["HIGH"]; got != "Высокий" {
+		t.Errorf("expected a Russian priority label, got %q", got)
+	}
+	if got := ru["pr_status"]["IN_QA"]; got != "In qa" {
+		t.Errorf("expected the humanized fallback for an untranslated status, got %q", got)
+	}
+}
+
+func TestAuthorOnly(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	type userResponse struct {
+		User struct {
+			UserID          string     `json:"user_id"`
+			IsActive        bool       `json:"is_active"`
+			AuthorOnly      bool       `json:"author_only"`
+			AuthorOnlyUntil *time.Time `json:"author_only_until"`
+		} `json:"user"`
+	}
+
+	setAuthorOnly := func(body string, status int) userResponse {
+		t.Helper()
+		resp := doPost(t, ts, "/users/setAuthorOnly", body)
+		defer resp.Body.Close()
+
+		if resp.StatusCode != status {
+			respBody, _ := io.ReadAll(resp.Body)
+			t.Fatalf("expected %d, got %d: %s", status, resp.StatusCode, string(respBody))
+		}
+
+		var data userResponse
+		if status == http.StatusOK {
+			if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+		}
+		return data
+	}
+
+	createPR := func(prID string, authorID string) []string {
+		t.Helper()
+		resp := doPost(t, ts, "/pullRequest/create", fmt.Sprintf(
+			`{"pull_request_id": "%s", "pull_request_name": "Author only", "author_id": "%s"}`, prID, authorID))
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusCreated {
+			body, _ := io.ReadAll(resp.Body)
+			t.Fatalf("expected 201, got %d: %s", resp.StatusCode, string(body))
+		}
+
+		var data struct {
+			PR struct {
+				AssignedReviewers []string `json:"assigned_reviewers"`
+			} `json:"pr"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		slices.Sort(data.PR.AssignedReviewers)
+		return data.PR.AssignedReviewers
+	}
+
+	until := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	setAuthorOnly(`{"user_id": "u2", "author_only": true}`, http.StatusOK)
+	setAuthorOnly(`{"user_id": "u3", "author_only": true}`, http.StatusOK)
+	bob := setAuthorOnly(fmt.Sprintf(`{"user_id": "u4", "author_only": true, "until": "%s"}`, until.Format(time.RFC3339)), http.StatusOK)
+	if !bob.User.AuthorOnly || !bob.User.IsActive || bob.User.AuthorOnlyUntil == nil || !bob.User.AuthorOnlyUntil.Equal(until) {
+		t.Fatalf("expected an active author-only user until %s, got %+v", until, bob.User)
+	}
+
+	setAuthorOnly(`{"user_id": "u5", "author_only": true, "until": "2020-01-01T00:00:00Z"}`, http.StatusBadRequest)
+	setAuthorOnly(`{"user_id": "u999", "author_only": true}`, http.StatusNotFound)
+
+	// Only Eve may review Alice's PR; author-only Bob still authors his own.
+	if reviewers := createPR("PR-AO1", "u1"); !slices.Equal(reviewers, []string{"u5"}) {
+		t.Fatalf("expected only u5 to review, got %v", reviewers)
+	}
+	if reviewers := createPR("PR-AO2", "u2"); !slices.Equal(reviewers, []string{"u1", "u5"}) {
+		t.Fatalf("expected u1 and u5 to review, got %v", reviewers)
+	}
+
+	assignResp := doPost(t, ts, "/pullRequest/assign", `{"pull_request_id": "PR-AO1", "reviewer_id": "u3"}`)
+	defer assignResp.Body.Close()
+	if assignResp.StatusCode != http.StatusConflict {
+		t.Fatalf("expected 409, got %d", assignResp.StatusCode)
+	}
+	var errResp struct {
+		Error struct {
+			Code string `json:"code"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(assignResp.Body).Decode(&errResp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if errResp.Error.Code != "REVIEWER_AUTHOR_ONLY" {
+		t.Fatalf("expected REVIEWER_AUTHOR_ONLY, got %s", errResp.Error.Code)
+	}
+
+	// Author-only members stay in their team.
+	teamResp := doGet(t, ts, "/team/get?team_name=Backend")
+	defer teamResp.Body.Close()
+	var team struct {
+		Members []struct {
+			UserID     string `json:"user_id"`
+			IsActive   bool   `json:"is_active"`
+			AuthorOnly bool   `json:"author_only"`
+		} `json:"members"`
+	}
+	if err := json.NewDecoder(teamResp.Body).Decode(&team); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	active, authorOnly := 0, 0
+	for _, member := range team.Members {
+		if member.IsActive {
+			active++
+		}
+		if member.AuthorOnly {
+			authorOnly++
+		}
+	}
+	if active != 5 || authorOnly != 3 {
+		t.Fatalf("expected 5 active backend members, 3 of them author-only, got %+v", team.Members)
+	}
+
+	cleared := setAuthorOnly(`{"user_id": "u2", "author_only": false}`, http.StatusOK)
+	if cleared.User.AuthorOnly || cleared.User.AuthorOnlyUntil != nil {
+		t.Fatalf("expected author-only to be cleared, got %+v", cleared.User)
+	}
+
+	// A period that ran out no longer counts.
+	if _, err := ts.DB.Exec(`UPDATE users SET author_only_until = NOW() - INTERVAL '1 minute' WHERE user_id = 4`); err != nil {
+		t.Fatalf("failed to expire author-only period: %v", err)
+	}
+	resp := doPost(t, ts, "/users/setIsActive", `{"user_id": "u4", "is_active": true}`)
+	defer resp.Body.Close()
+	var expired userResponse
+	if err := json.NewDecoder(resp.Body).Decode(&expired); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if expired.User.AuthorOnly || expired.User.AuthorOnlyUntil != nil {
+		t.Fatalf("expected the expired period to read as cleared, got %+v", expired.User)
+	}
+}
+
+func TestMergeWindow(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	expectStatus := func(resp *http.Response, status int) {
+		t.Helper()
+		defer resp.Body.Close()
+
+		if resp.StatusCode != status {
+			body, _ := io.ReadAll(resp.Body)
+			t.Fatalf("expected %d, got %d: %s", status, resp.StatusCode, string(body))
+		}
+	}
+
+	type mergeResponse struct {
+		PR struct {
+			Status string `json:"status"`
+		} `json:"pr"`
+		Warning *struct {
+			Code string    `json:"code"`
+			At   time.Time `json:"at"`
+		} `json:"warning"`
+	}
+
+	merge := func(resp *http.Response) mergeResponse {
+		t.Helper()
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			t.Fatalf("expected 200, got %d: %s", resp.StatusCode, string(body))
+		}
+
+		var data mergeResponse
+		if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		return data
+	}
+
+	for _, id := range []string{"PR-MW1", "PR-MW2", "PR-MW3"} {
+		expectStatus(doPost(t, ts, "/pullRequest/create", fmt.Sprintf(
+			`{"pull_request_id": "%s", "pull_request_name": "Merge window", "author_id": "u1"}`, id)), http.StatusCreated)
+	}
+
+	// Block today and tomorrow, so the window stays closed across midnight.
+	now := time.Now().UTC()
+	today := strings.ToUpper(now.Weekday().String())
+	tomorrow := strings.ToUpper(now.Add(24 * time.Hour).Weekday().String())
+	expectStatus(doPost(t, ts, "/admin/mergeWindow/set", fmt.Sprintf(`{"team_name": "Backend", "blocks": [
+		{"weekday": "%s", "start": "00:00", "end": "24:00"},
+		{"weekday": "%s", "start": "00:00", "end": "24:00"}]}`, today, tomorrow)), http.StatusOK)
+	expectStatus(doPost(t, ts, "/admin/mergeWindow/set",
+		`{"team_name": "Backend", "blocks": [{"weekday": "someday", "start": "00:00", "end": "24:00"}]}`), http.StatusBadRequest)
+	expectStatus(doPost(t, ts, "/admin/mergeWindow/set",
+		`{"team_name": "Backend", "time_zone": "Mars/Olympus", "blocks": []}`), http.StatusBadRequest)
+
+	expectStatus(doPost(t, ts, "/pullRequest/merge", `{"pull_request_id": "PR-MW1"}`), http.StatusConflict)
+
+	// A read-write key cannot override; an admin key can.
+	issue := func(scopes string) string {
+		t.Helper()
+		resp := doPost(t, ts, "/admin/tokens/issue", `{"name": "merge", "user_id": "u5", "scopes": [`+scopes+`]}`)
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("expected 201 on issue, got %d", resp.StatusCode)
+		}
+
+		var issued struct {
+			Key string `json:"key"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&issued); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		return issued.Key
+	}
+	overrideBody := `{"pull_request_id": "PR-MW1", "override_merge_window": true}`
+	expectStatus(doWithKey(t, ts, http.MethodPost, "/pullRequest/merge", overrideBody, issue(`"read", "write"`)), http.StatusForbidden)
+
+	overridden := merge(doWithKey(t, ts, http.MethodPost, "/pullRequest/merge", overrideBody, issue(`"admin"`)))
+	if overridden.PR.Status != "MERGED" || overridden.Warning == nil || overridden.Warning.Code != "MERGE_WINDOW_OVERRIDDEN" {
+		t.Fatalf("expected an overridden merge, got %+v", overridden)
+	}
+
+	// Merging half an hour before the window closes is allowed with a warning.
+	closesAt := now.Add(30 * time.Minute).Truncate(time.Minute)
+	expectStatus(doPost(t, ts, "/admin/mergeWindow/set", fmt.Sprintf(`{"team_name": "Backend", "warn_minutes": 60, "blocks": [
+		{"weekday": "%s", "start": "%s", "end": "24:00"}]}`,
+		strings.ToUpper(closesAt.Weekday().String()), closesAt.Format("15:04"))), http.StatusOK)
+
+	closing := merge(doPost(t, ts, "/pullRequest/merge", `{"pull_request_id": "PR-MW2"}`))
+	if closing.Warning == nil || closing.Warning.Code != "MERGE_WINDOW_CLOSING_SOON" || !closing.Warning.At.Equal(closesAt) {
+		t.Fatalf("expected a closing soon warning at %s, got %+v", closesAt, closing.Warning)
+	}
+
+	// Without blocks merging is never blocked.
+	expectStatus(doPost(t, ts, "/admin/mergeWindow/set", `{"team_name": "Backend", "blocks": []}`), http.StatusOK)
+	if open := merge(doPost(t, ts, "/pullRequest/merge", `{"pull_request_id": "PR-MW3"}`)); open.Warning != nil {
+		t.Fatalf("expected no warning, got %+v", open.Warning)
+	}
+
+	resp := doGet(t, ts, "/admin/mergeWindows")
+	defer resp.Body.Close()
+	var windows struct {
+		MergeWindows []struct {
+			TeamName string            `json:"team_name"`
+			TimeZone string            `json:"time_zone"`
+			Blocks   []json.RawMessage `json:"blocks"`
+		} `json:"merge_windows"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&windows); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(windows.MergeWindows) != 1 || windows.MergeWindows[0].TimeZone != "UTC" || len(windows.MergeWindows[0].Blocks) != 0 {
+		t.Fatalf("expected Backend's empty UTC window, got %+v", windows.MergeWindows)
+	}
+}
+
+func TestReorderQueue(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	deactivate := doPost(t, ts, "/users/setIsActiveBatch", `{"user_ids":["u3","u4","u5"],"is_active":false}`)
+	deactivate.Body.Close()
+
+	for _, prID := range []string{"PR-Q1", "PR-Q2", "PR-Q3"} {
+		resp := doPost(t, ts, "/pullRequest/create", `{"pull_request_id": "`+prID+`", "pull_request_name": "Queue", "author_id": "u1"}`)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("expected 201 for %s, got %d", prID, resp.StatusCode)
+		}
+	}
+
+	queue := func(resp *http.Response) []string {
+		t.Helper()
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("expected 200, got %d", resp.StatusCode)
+		}
+
+		var data struct {
+			Reviews []struct {
+				PullRequestID string `json:"pull_request_id"`
+			} `json:"reviews"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+
+		ids := make([]string, 0, len(data.Reviews))
+		for _, review := range data.Reviews {
+			ids = append(ids, review.PullRequestID)
+		}
+		return ids
+	}
+
+	reordered := queue(doPostAs(t, ts, "/users/reorderQueue", `{"pull_request_ids":["PR-Q3","PR-Q1"]}`, "u2"))
+	if !slices.Equal(reordered, []string{"PR-Q3", "PR-Q1", "PR-Q2"}) {
+		t.Fatalf("expected PR-Q3, PR-Q1, PR-Q2, got %v", reordered)
+	}
+
+	mine := queue(doGetAs(t, ts, "/users/myReviews", "u2"))
+	if !slices.Equal(mine, reordered) {
+		t.Fatalf("expected myReviews to keep the order %v, got %v", reordered, mine)
+	}
+
+	foreign := doPostAs(t, ts, "/users/reorderQueue", `{"pull_request_ids":["PR-Q1"]}`, "u3")
+	defer foreign.Body.Close()
+	if foreign.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected 404 for a PR outside the queue, got %d", foreign.StatusCode)
+	}
+
+	repeated := doPostAs(t, ts, "/users/reorderQueue", `{"pull_request_ids":["PR-Q1","PR-Q1"]}`, "u2")
+	defer repeated.Body.Close()
+	if repeated.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected 400 for a repeated PR, got %d", repeated.StatusCode)
+	}
+
+	reset := queue(doPostAs(t, ts, "/users/reorderQueue", `{"pull_request_ids":[]}`, "u2"))
+	if len(reset) != 3 || reset[0] == "PR-Q3" {
+		t.Fatalf("expected the default order after reset, got %v", reset)
+	}
+}
+
+func TestGetUser(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	deactivate := doPost(t, ts, "/users/setIsActiveBatch", `{"user_ids":["u3","u4","u5"],"is_active":false}`)
+	deactivate.Body.Close()
+
+	for _, prID := range []string{"PR-G1", "PR-G2"} {
+		resp := doPost(t, ts, "/pullRequest/create", `{"pull_request_id": "`+prID+`", "pull_request_name": "Dashboard", "author_id": "u1"}`)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("expected 201 for %s, got %d", prID, resp.StatusCode)
+		}
+	}
+
+	merge := doPost(t, ts, "/pullRequest/merge", `{"pull_request_id": "PR-G1"}`)
+	merge.Body.Close()
+	if merge.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200 on merge, got %d", merge.StatusCode)
+	}
+
+	resp := doGet(t, ts, "/users/get?user_id=u2")
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+
+	var data struct {
+		User struct {
+			UserID       string `json:"user_id"`
+			TeamName     string `json:"team_name"`
+			IsActive     bool   `json:"is_active"`
+			OpenReviews  int    `json:"open_reviews"`
+			TotalReviews int    `json:"total_reviews"`
+		} `json:"user"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if data.User.UserID != "u2" || data.User.TeamName != "Backend" || !data.User.IsActive {
+		t.Fatalf("unexpected user %+v", data.User)
+	}
+	if data.User.OpenReviews != 1 || data.User.TotalReviews != 2 {
+		t.Fatalf("expected 1 open and 2 total reviews, got %+v", data.User)
+	}
+
+	missing := doGet(t, ts, "/users/get?user_id=u404")
+	defer missing.Body.Close()
+	if missing.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected 404 for an unknown user, got %d", missing.StatusCode)
+	}
+}
+
+func TestAssignmentRepair(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	// PRs stored without reviewers, as a failed create-and-assign leaves them.
+	_, err = ts.DB.Exec(`
+		INSERT INTO pull_requests (pull_request_id, pull_request_name, author_id) VALUES
+			('PR-R1', 'Repairable', 1),
+			('PR-R2', 'Stuck', 10);
+		INSERT INTO assignment_repairs (pull_request_id, last_error) VALUES
+			('PR-R1', 'connection reset'),
+			('PR-R2', 'connection reset');
+		UPDATE users SET is_active = false WHERE user_id = 11;
+	`)
+	if err != nil {
+		t.Fatalf("failed to seed repairs: %v", err)
+	}
+
+	type report struct {
+		Report struct {
+			Repaired []string `json:"repaired_pull_requests"`
+			Failed   []struct {
+				PullRequestID string `json:"pull_request_id"`
+				Attempts      int    `json:"attempts"`
+			} `json:"failed"`
+		} `json:"report"`
+	}
+
+	run := func() report {
+		t.Helper()
+		resp := doPost(t, ts, "/admin/assignmentRepairs/run", `{}`)
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("expected 200, got %d", resp.StatusCode)
+		}
+		var data report
+		if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		return data
+	}
+
+	first := run()
+	if !slices.Equal(first.Report.Repaired, []string{"PR-R1"}) {
+		t.Fatalf("expected PR-R1 repaired, got %v", first.Report.Repaired)
+	}
+	if len(first.Report.Failed) != 1 || first.Report.Failed[0].PullRequestID != "PR-R2" || first.Report.Failed[0].Attempts != 1 {
+		t.Fatalf("expected PR-R2 to fail once, got %+v", first.Report.Failed)
+	}
+
+	var reviewers int
+	if err := ts.DB.Get(&reviewers, `SELECT COUNT(*) FROM pr_reviewers WHERE pull_request_id = 'PR-R1'`); err != nil {
+		t.Fatalf("failed to count reviewers: %v", err)
+	}
+	if reviewers == 0 {
+		t.Fatalf("expected PR-R1 to get reviewers")
+	}
+
+	// A failed repair waits out its backoff before the next attempt.
+	if again := run(); len(again.Report.Failed) != 0 {
+		t.Fatalf("expected PR-R2 to wait for its backoff, got %+v", again.Report.Failed)
+	}
+
+	if _, err := ts.DB.Exec(`UPDATE assignment_repairs SET next_attempt_at = NOW() - INTERVAL '1 second'`); err != nil {
+		t.Fatalf("failed to expire backoff: %v", err)
+	}
+	if second := run(); len(second.Report.Failed) != 1 || second.Report.Failed[0].Attempts != 2 {
+		t.Fatalf("expected PR-R2 to fail twice, got %+v", second.Report.Failed)
+	}
+
+	var alerts int
+	if err := ts.DB.Get(&alerts, `SELECT COUNT(*) FROM audit_events WHERE action = 'ASSIGNMENT_REPAIR_FAILING'`); err != nil {
+		t.Fatalf("failed to count alerts: %v", err)
+	}
+	if alerts != 1 {
+		t.Fatalf("expected one repair alert, got %d", alerts)
+	}
+
+	resp := doGet(t, ts, "/admin/assignmentRepairs")
+	defer resp.Body.Close()
+
+	var listed struct {
+		Repairs []struct {
+			PullRequestID string  `json:"pull_request_id"`
+			TeamName      string  `json:"team_name"`
+			AlertedAt     *string `json:"alerted_at"`
+		} `json:"repairs"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&listed); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(listed.Repairs) != 1 || listed.Repairs[0].PullRequestID != "PR-R2" ||
+		listed.Repairs[0].TeamName != "QA" || listed.Repairs[0].AlertedAt == nil {
+		t.Fatalf("expected only the alerted PR-R2 queued, got %+v", listed.Repairs)
+	}
+}
+
+func TestDeactivateReassignsOpenReviews(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	resp := doPost(t, ts, "/pullRequest/create",
+		`{"pull_request_id": "PR-DA1", "pull_request_name": "Deactivation", "author_id": "u1"}`)
+	var created struct {
+		PR struct {
+			AssignedReviewers []string `json:"assigned_reviewers"`
+		} `json:"pr"`
+	}
+	json.NewDecoder(resp.Body).Decode(&created)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated || len(created.PR.AssignedReviewers) == 0 {
+		t.Fatalf("failed to create PR-DA1: %d", resp.StatusCode)
+	}
+	leaving := created.PR.AssignedReviewers[0]
+
+	type setIsActiveResponse struct {
+		User struct {
+			IsActive bool `json:"is_active"`
+		} `json:"user"`
+		Reassigned []struct {
+			PullRequestID string `json:"pull_request_id"`
+			NewReviewerID string `json:"new_reviewer_id"`
+		} `json:"reassigned"`
+		Unreassigned []struct {
+			PullRequestID string `json:"pull_request_id"`
+		} `json:"unreassigned"`
+	}
+
+	setIsActive := func(body string) setIsActiveResponse {
+		t.Helper()
+		resp := doPost(t, ts, "/users/setIsActive", body)
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("expected 200 for setIsActive %s, got %d", body, resp.StatusCode)
+		}
+
+		var data setIsActiveResponse
+		json.NewDecoder(resp.Body).Decode(&data)
+		return data
+	}
+
+	data := setIsActive(fmt.Sprintf(`{"user_id": %q, "is_active": false, "reassign_open_reviews": true}`, leaving))
+	if data.User.IsActive || len(data.Unreassigned) != 0 {
+		t.Fatalf("expected %s deactivated with nothing left over, got %+v", leaving, data)
+	}
+	if len(data.Reassigned) != 1 || data.Reassigned[0].PullRequestID != "PR-DA1" ||
+		data.Reassigned[0].NewReviewerID == "" || data.Reassigned[0].NewReviewerID == leaving {
+		t.Fatalf("expected PR-DA1 handed to another reviewer, got %+v", data.Reassigned)
+	}
+
+	var reason string
+	err = ts.DB.Get(&reason, `SELECT reason FROM assignment_history WHERE pull_request_id = 'PR-DA1' AND reason IS NOT NULL`)
+	if err != nil || reason != "DEACTIVATED" {
+		t.Fatalf("expected the reassignment to be recorded as DEACTIVATED, got %q: %v", reason, err)
+	}
+
+	// Without the flag the reviews stay with the deactivated user.
+	other := data.Reassigned[0].NewReviewerID
+	data = setIsActive(fmt.Sprintf(`{"user_id": %q, "is_active": false}`, other))
+	if data.Reassigned != nil || data.Unreassigned != nil {
+		t.Fatalf("expected no handover without reassign_open_reviews, got %+v", data)
+	}
+
+	var assigned int
+	err = ts.DB.Get(&assigned, `SELECT COUNT(*) FROM pr_reviewers WHERE pull_request_id = 'PR-DA1' AND reviewer_id = $1`,
+		strings.TrimPrefix(other, "u"))
+	if err != nil || assigned != 1 {
+		t.Fatalf("expected %s to stay assigned to PR-DA1, got %d: %v", other, assigned, err)
+	}
+}
+
+func TestPRStatusMachine(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	// Org transitions outlive the fixtures; drop the ones this test adds.
+	defer ts.DB.Exec(`DELETE FROM pr_status_transitions WHERE to_status = 'CLOSED'`)
+
+	expectStatus := func(resp *http.Response, status int) {
+		t.Helper()
+		defer resp.Body.Close()
+
+		if resp.StatusCode != status {
+			body, _ := io.ReadAll(resp.Body)
+			t.Fatalf("expected %d, got %d: %s", status, resp.StatusCode, string(body))
+		}
+	}
+
+	expectStatus(doPost(t, ts, "/admin/prStatuses/save", `{"status": "closed", "is_terminal": true}`), http.StatusOK)
+	expectStatus(doPost(t, ts, "/admin/prStatuses/save", `{"status": "MERGED", "is_terminal": false}`), http.StatusBadRequest)
+
+	// The org may close PRs; Backend must pass review first and may not close.
+	expectStatus(doPost(t, ts, "/admin/prStatuses/transitions", `{"transitions": [
+		{"from_status": "OPEN", "to_status": "MERGED"},
+		{"from_status": "OPEN", "to_status": "CLOSED"}]}`), http.StatusOK)
+	expectStatus(doPost(t, ts, "/admin/prStatuses/transitions", `{"team_name": "Backend", "transitions": [
+		{"from_status": "OPEN", "to_status": "IN_REVIEW"},
+		{"from_status": "IN_REVIEW", "to_status": "MERGED"}]}`), http.StatusOK)
+	expectStatus(doPost(t, ts, "/admin/prStatuses/transitions", `{"team_name": "Backend", "transitions": [
+		{"from_status": "OPEN", "to_status": "IN_REVIEW"}]}`), http.StatusBadRequest)
+	expectStatus(doPost(t, ts, "/admin/prStatuses/transitions", `{"team_name": "Backend", "transitions": [
+		{"from_status": "CLOSED", "to_status": "OPEN"}, {"from_status": "OPEN", "to_status": "MERGED"}]}`), http.StatusBadRequest)
+	expectStatus(doPost(t, ts, "/admin/prStatuses/save", `{"status": "IN_REVIEW", "is_terminal": true}`), http.StatusBadRequest)
+	expectStatus(doPost(t, ts, "/admin/prStatuses/delete", `{"status": "IN_REVIEW"}`), http.StatusConflict)
+
+	resp := doGet(t, ts, "/pullRequest/statuses?team_name=Backend")
+	var machine struct {
+		Source      string `json:"source"`
+		Transitions []struct {
+			FromStatus string `json:"from_status"`
+			ToStatus   string `json:"to_status"`
+		} `json:"transitions"`
+	}
+	json.NewDecoder(resp.Body).Decode(&machine)
+	resp.Body.Close()
+	if machine.Source != "TEAM" || len(machine.Transitions) != 2 {
+		t.Fatalf("expected Backend's own 2 transitions, got %+v", machine)
+	}
+
+	expectStatus(doPost(t, ts, "/pullRequest/create",
+		`{"pull_request_id": "PR-SM1", "pull_request_name": "Backend", "author_id": "u1"}`), http.StatusCreated)
+	expectStatus(doPost(t, ts, "/pullRequest/create",
+		`{"pull_request_id": "PR-SM2", "pull_request_name": "QA", "author_id": "u10"}`), http.StatusCreated)
+
+	expectStatus(doPost(t, ts, "/pullRequest/setStatus", `{"pull_request_id": "PR-SM1", "status": "CLOSED"}`), http.StatusConflict)
+	expectStatus(doPost(t, ts, "/pullRequest/merge", `{"pull_request_id": "PR-SM1"}`), http.StatusConflict)
+	expectStatus(doPost(t, ts, "/pullRequest/setStatus", `{"pull_request_id": "PR-SM1", "status": "IN_REVIEW"}`), http.StatusOK)
+	expectStatus(doPost(t, ts, "/pullRequest/merge", `{"pull_request_id": "PR-SM1"}`), http.StatusOK)
+
+	// QA follows the org's transitions, and CLOSED is terminal.
+	expectStatus(doPost(t, ts, "/pullRequest/setStatus", `{"pull_request_id": "PR-SM2", "status": "CLOSED"}`), http.StatusOK)
+	resp = doPost(t, ts, "/pullRequest/reassign", `{"pull_request_id": "PR-SM2", "old_reviewer_id": "u11"}`)
+	var failure struct {
+		Error struct {
+			Code string `json:"code"`
+		} `json:"error"`
+	}
+	json.NewDecoder(resp.Body).Decode(&failure)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusConflict || failure.Error.Code != "PR_CLOSED" {
+		t.Fatalf("expected 409 PR_CLOSED for a closed PR, got %d %s", resp.StatusCode, failure.Error.Code)
+	}
+	expectStatus(doPost(t, ts, "/admin/prStatuses/delete", `{"status": "CLOSED"}`), http.StatusConflict)
+
+	// Clearing Backend's transitions makes it follow the org's again.
+	expectStatus(doPost(t, ts, "/admin/prStatuses/transitions", `{"team_name": "Backend", "transitions": []}`), http.StatusOK)
+	expectStatus(doPost(t, ts, "/pullRequest/create",
+		`{"pull_request_id": "PR-SM3", "pull_request_name": "Backend", "author_id": "u2"}`), http.StatusCreated)
+	expectStatus(doPost(t, ts, "/pullRequest/setStatus", `{"pull_request_id": "PR-SM3", "status": "CLOSED"}`), http.StatusOK)
+}
+
+func TestTokenHourlyQuota(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	issue := func(body string) (int64, string) {
+		t.Helper()
+		resp := doPost(t, ts, "/admin/tokens/issue", body)
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("expected 201 on issue, got %d", resp.StatusCode)
+		}
+
+		var issued struct {
+			Token struct {
+				TokenID     int64 `json:"token_id"`
+				HourlyQuota *int  `json:"hourly_quota"`
+			} `json:"token"`
+			Key string `json:"key"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&issued); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		return issued.Token.TokenID, issued.Key
+	}
+
+	get := func(key string) *http.Response {
+		t.Helper()
+		resp := doWithKey(t, ts, http.MethodGet, "/team/get?team_name=Backend", "", key)
+		resp.Body.Close()
+		return resp
+	}
+
+	limitedID, limitedKey := issue(`{"name": "limited", "user_id": "u1", "scopes": ["read"], "hourly_quota": 2}`)
+	_, otherKey := issue(`{"name": "other", "user_id": "u2", "scopes": ["read"], "hourly_quota": 2}`)
+
+	for i := 0; i < 2; i++ {
+		if resp := get(limitedKey); resp.StatusCode != http.StatusOK {
+			t.Fatalf("request %d within the quota: expected 200, got %d", i+1, resp.StatusCode)
+		}
+	}
+
+	resp := doWithKey(t, ts, http.MethodGet, "/team/get?team_name=Backend", "", limitedKey)
+	var rejected struct {
+		Error struct {
+			Code string `json:"code"`
+		} `json:"error"`
+	}
+	json.NewDecoder(resp.Body).Decode(&rejected)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusTooManyRequests || rejected.Error.Code != "QUOTA_EXCEEDED" {
+		t.Fatalf("expected 429 QUOTA_EXCEEDED over the quota, got %d %s", resp.StatusCode, rejected.Error.Code)
+	}
+	if retryAfter, err := strconv.Atoi(resp.Header.Get("Retry-After")); err != nil || retryAfter < 1 || retryAfter > 3600 {
+		t.Fatalf("expected Retry-After within the hour, got %q", resp.Header.Get("Retry-After"))
+	}
+
+	// Keys are counted separately, and so are callers without a key.
+	if resp := get(otherKey); resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected another key to keep its own quota, got %d", resp.StatusCode)
+	}
+	if resp := doGet(t, ts, "/team/get?team_name=Backend"); resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		t.Fatalf("expected a request without a key to pass, got %d", resp.StatusCode)
+	} else {
+		resp.Body.Close()
+	}
+
+	// The counter resets with the hour.
+	if _, err := ts.DB.Exec(`UPDATE api_quota_counters SET window_start = window_start - INTERVAL '1 hour'`); err != nil {
+		t.Fatalf("failed to age quota counters: %v", err)
+	}
+	if resp := get(limitedKey); resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected the quota to reset in the next hour, got %d", resp.StatusCode)
+	}
+	get(limitedKey)
+	if resp := get(limitedKey); resp.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("expected 429 after using the new hour's quota, got %d", resp.StatusCode)
+	}
+
+	setQuota := doPost(t, ts, "/admin/tokens/setQuota", fmt.Sprintf(`{"token_id": %d, "hourly_quota": 0}`, limitedID))
+	setQuota.Body.Close()
+	if setQuota.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200 on setQuota, got %d", setQuota.StatusCode)
+	}
+	if resp := get(limitedKey); resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected quota 0 to lift the limit, got %d", resp.StatusCode)
+	}
+
+	invalid := doPost(t, ts, "/admin/tokens/setQuota", fmt.Sprintf(`{"token_id": %d, "hourly_quota": -1}`, limitedID))
+	invalid.Body.Close()
+	if invalid.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected 400 for a negative quota, got %d", invalid.StatusCode)
+	}
+}
+
+func TestAdminAnonymizeUser(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	type anonymizeResponse struct {
+		Result struct {
+			UserID            string `json:"user_id"`
+			ConfirmationToken string `json:"confirmation_token"`
+			Pseudonym         string `json:"pseudonym"`
+			Anonymized        bool   `json:"anonymized"`
+			PurgedDeliveries  int    `json:"purged_deliveries"`
+		} `json:"result"`
+		Error struct {
+			Code string `json:"code"`
+		} `json:"error"`
+	}
+
+	anonymize := func(body string, status int) anonymizeResponse {
+		t.Helper()
+		resp := doPost(t, ts, "/admin/anonymizeUser", body)
+		defer resp.Body.Close()
+
+		var data anonymizeResponse
+		json.NewDecoder(resp.Body).Decode(&data)
+		if resp.StatusCode != status {
+			t.Fatalf("%s: expected %d, got %d (%s)", body, status, resp.StatusCode, data.Error.Code)
+		}
+		return data
+	}
+
+	if rejected := anonymize(`{"user_id": "u3"}`, http.StatusConflict); rejected.Error.Code != "USER_ACTIVE" {
+		t.Fatalf("expected USER_ACTIVE for an active user, got %s", rejected.Error.Code)
+	}
+
+	for _, userID := range []string{"u3", "u4"} {
+		resp := doPost(t, ts, "/users/setIsActive", fmt.Sprintf(`{"user_id": "%s", "is_active": false}`, userID))
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("failed to deactivate %s: %d", userID, resp.StatusCode)
+		}
+	}
+
+	// Step one only returns the confirmation token.
+	pending := anonymize(`{"user_id": "u3"}`, http.StatusAccepted)
+	if pending.Result.Anonymized || pending.Result.ConfirmationToken == "" || pending.Result.Pseudonym != "" {
+		t.Fatalf("expected only a confirmation token, got %+v", pending.Result)
+	}
+
+	var username string
+	if err := ts.DB.Get(&username, `SELECT username FROM users WHERE user_id = 3`); err != nil {
+		t.Fatalf("failed to read username: %v", err)
+	}
+	if username != "Carol" {
+		t.Fatalf("expected the first step to leave the username, got %s", username)
+	}
+
+	if wrong := anonymize(`{"user_id": "u3", "confirmation_token": "forged"}`, http.StatusBadRequest); wrong.Error.Code != "INVALID_CONFIRMATION" {
+		t.Fatalf("expected INVALID_CONFIRMATION, got %s", wrong.Error.Code)
+	}
+
+	// Stored deliveries about u3 carry the old profile; one about u4 does not.
+	hook := doPost(t, ts, "/team/webhooks/create",
+		`{"team_name": "Backend", "url": "http://127.0.0.1:1/hook", "secret": "s", "events": ["reviewer.assigned"]}`)
+	var created struct {
+		Webhook struct {
+			ID int64 `json:"id"`
+		} `json:"webhook"`
+	}
+	json.NewDecoder(hook.Body).Decode(&created)
+	hook.Body.Close()
+
+	for _, userIDs := range [][]int{{1, 3}, {4}} {
+		_, err := ts.DB.Exec(`
+			INSERT INTO webhook_deliveries (webhook_id, event, pull_request_id, body, user_ids, attempt, status)
+			VALUES ($1, 'reviewer.assigned', 'PR-X', '{"users": {"u3": {"username": "Carol"}}}', $2, 3, 'FAILED')`,
+			created.Webhook.ID, pq.Array(userIDs))
+		if err != nil {
+			t.Fatalf("failed to seed a failed delivery: %v", err)
+		}
+	}
+
+	// Step two anonymizes with a random pseudonym.
+	done := anonymize(fmt.Sprintf(`{"user_id": "u3", "confirmation_token": "%s"}`, pending.Result.ConfirmationToken), http.StatusOK)
+	if !done.Result.Anonymized || !strings.HasPrefix(done.Result.Pseudonym, "anonymous-") {
+		t.Fatalf("expected the user to be anonymized, got %+v", done.Result)
+	}
+	if done.Result.PurgedDeliveries != 1 {
+		t.Fatalf("expected the delivery about u3 to be purged, got %d", done.Result.PurgedDeliveries)
+	}
+
+	var kept int
+	if err := ts.DB.Get(&kept, `SELECT COUNT(*) FROM webhook_deliveries WHERE 3 = ANY(user_ids)`); err != nil || kept != 0 {
+		t.Fatalf("expected no stored delivery to mention u3, got %d: %v", kept, err)
+	}
+
+	if err := ts.DB.Get(&username, `SELECT username FROM users WHERE user_id = 3`); err != nil {
+		t.Fatalf("failed to read username: %v", err)
+	}
+	if username != done.Result.Pseudonym {
+		t.Fatalf("expected username %s, got %s", done.Result.Pseudonym, username)
+	}
+
+	var audited int
+	err = ts.DB.Get(&audited, `
+		SELECT COUNT(*) FROM audit_events
+		WHERE action = 'USER_ANONYMIZED' AND subject_id = 3 AND details NOT LIKE '%Carol%'
+	`)
+	if err != nil {
+		t.Fatalf("failed to query audit events: %v", err)
+	}
+	if audited != 1 {
+		t.Fatalf("expected 1 anonymization audit event without the old username, got %d", audited)
+	}
+
+	replayed := anonymize(fmt.Sprintf(`{"user_id": "u3", "confirmation_token": "%s"}`, pending.Result.ConfirmationToken), http.StatusConflict)
+	if replayed.Error.Code != "ALREADY_ANONYMIZED" {
+		t.Fatalf("expected ALREADY_ANONYMIZED on replay, got %s", replayed.Error.Code)
+	}
+
+	// Every anonymization draws a fresh random pseudonym.
+	other := anonymize(`{"user_id": "u4"}`, http.StatusAccepted)
+	otherDone := anonymize(fmt.Sprintf(`{"user_id": "u4", "confirmation_token": "%s"}`, other.Result.ConfirmationToken), http.StatusOK)
+	if otherDone.Result.Pseudonym == done.Result.Pseudonym {
+		t.Fatalf("expected distinct pseudonyms, both got %s", done.Result.Pseudonym)
+	}
+}
+
+func TestJobClaimRun(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if _, err := ts.DB.Exec(`DELETE FROM jobs WHERE name = 'claim_test'`); err != nil {
+		t.Fatalf("failed to clean up jobs: %v", err)
+	}
+	defer ts.DB.Exec(`DELETE FROM jobs WHERE name = 'claim_test'`)
+
+	jobs := repo.NewJobRepo(ts.DB)
+
+	if err := jobs.RegisterJob("claim_test", time.Hour, time.Now().Add(-time.Minute)); err != nil {
+		t.Fatalf("failed to register job: %v", err)
+	}
+	// A second instance registering the job must not push the due run back.
+	if err := jobs.RegisterJob("claim_test", time.Hour, time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("failed to register job: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	var claims atomic.Int32
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			claimed, err := jobs.ClaimRun("claim_test", time.Hour, time.Minute)
+			if err != nil {
+				t.Errorf("failed to claim job run: %v", err)
+			}
+			if claimed {
+				claims.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := claims.Load(); got != 1 {
+		t.Fatalf("expected exactly one instance to claim the run, got %d", got)
+	}
+
+	var nextRunAt time.Time
+	if err := ts.DB.Get(&nextRunAt, `SELECT next_run_at FROM jobs WHERE name = 'claim_test'`); err != nil {
+		t.Fatalf("failed to get next run: %v", err)
+	}
+	if time.Until(nextRunAt) < 50*time.Minute {
+		t.Fatalf("expected the claim to move the next run an interval ahead, got %v", nextRunAt)
+	}
+}
+
+func TestCreatePRAssignmentPending(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	// Fail the reviewer insert of one PR, as a database fault between
+	// storing the PR and assigning its reviewers would.
+	_, err = ts.DB.Exec(`
+		CREATE OR REPLACE FUNCTION fail_pending_pr_reviewers() RETURNS trigger AS $$
+		BEGIN
+			IF NEW.pull_request_id = 'PR-AP1' THEN
+				RAISE EXCEPTION 'injected reviewer insert failure';
+			END IF;
+			RETURN NEW;
+		END
+		$$ LANGUAGE plpgsql;
+		CREATE TRIGGER fail_pending_pr_reviewers BEFORE INSERT ON pr_reviewers
+			FOR EACH ROW EXECUTE FUNCTION fail_pending_pr_reviewers();
+	`)
+	if err != nil {
+		t.Fatalf("failed to install trigger: %v", err)
+	}
+	dropTrigger := func() {
+		ts.DB.Exec(`
+			DROP TRIGGER IF EXISTS fail_pending_pr_reviewers ON pr_reviewers;
+			DROP FUNCTION IF EXISTS fail_pending_pr_reviewers();
+		`)
+	}
+	defer dropTrigger()
+
+	resp := doPost(t, ts, "/pullRequest/create", `{"pull_request_id": "PR-AP1", "pull_request_name": "Pending", "author_id": "u1"}`)
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", resp.StatusCode)
+	}
+
+	var created struct {
+		PR struct {
+			PullRequestID     string   `json:"pull_request_id"`
+			AssignedReviewers []string `json:"assigned_reviewers"`
+			AssignmentPending bool     `json:"assignment_pending"`
+		} `json:"pr"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if created.PR.PullRequestID != "PR-AP1" || !created.PR.AssignmentPending || len(created.PR.AssignedReviewers) != 0 {
+		t.Fatalf("expected the PR without reviewers and assignment pending, got %+v", created.PR)
+	}
+
+	var queued int
+	if err := ts.DB.Get(&queued, `SELECT COUNT(*) FROM assignment_repairs WHERE pull_request_id = 'PR-AP1'`); err != nil {
+		t.Fatalf("failed to count repairs: %v", err)
+	}
+	if queued != 1 {
+		t.Fatalf("expected PR-AP1 queued for repair, got %d", queued)
+	}
+
+	dropTrigger()
+
+	runResp := doPost(t, ts, "/admin/assignmentRepairs/run", `{}`)
+	defer runResp.Body.Close()
+
+	var run struct {
+		Report struct {
+			Repaired []string `json:"repaired_pull_requests"`
+		} `json:"report"`
+	}
+	if err := json.NewDecoder(runResp.Body).Decode(&run); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if !slices.Equal(run.Report.Repaired, []string{"PR-AP1"}) {
+		t.Fatalf("expected PR-AP1 repaired, got %v", run.Report.Repaired)
+	}
+}
+
+func TestAssignmentRepairConcurrentRuns(t *testing.T) {
+	ts, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("Failed to create test server: %v", err)
+	}
+	defer ts.Close()
+
+	if err := ts.LoadFixtures(); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	const queued = 20
+	for _, query := range []string{
+		`INSERT INTO pull_requests (pull_request_id, pull_request_name, author_id)
+			SELECT 'PR-CR' || i, 'Concurrent repair', 1 FROM generate_series(1, $1) AS i`,
+		`INSERT INTO assignment_repairs (pull_request_id, last_error)
+			SELECT 'PR-CR' || i, 'connection reset' FROM generate_series(1, $1) AS i`,
+	} {
+		if _, err := ts.DB.Exec(query, queued); err != nil {
+			t.Fatalf("failed to seed repairs: %v", err)
+		}
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		repaired []string
+	)
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, err := http.NewRequest(http.MethodPost, ts.Server.URL+"/admin/assignmentRepairs/run", strings.NewReader(`{}`))
+			if err != nil {
+				t.Errorf("failed to build request: %v", err)
+				return
+			}
+			req.Header.Set("X-API-Key", ts.AdminKey)
+
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Errorf("failed to run repairs: %v", err)
+				return
+			}
+			defer resp.Body.Close()
+
+			var run struct {
+				Report struct {
+					Repaired []string `json:"repaired_pull_requests"`
+				} `json:"report"`
+			}
+			if err := json.NewDecoder(resp.Body).Decode(&run); err != nil {
+				t.Errorf("failed to decode response: %v", err)
+				return
+			}
+			mu.Lock()
+			repaired = append(repaired, run.Report.Repaired...)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	slices.Sort(repaired)
+	if len(repaired) != queued || len(slices.Compact(slices.Clone(repaired))) != queued {
+		t.Fatalf("expected each of %d repairs run once, got %v", queued, repaired)
+	}
+
+	var assignments int
+	err = ts.DB.Get(&assignments, `
+		SELECT COUNT(*) FROM (
+			SELECT pull_request_id FROM pr_reviewers
+			WHERE pull_request_id LIKE 'PR-CR%'
+			GROUP BY pull_request_id
+			HAVING COUNT(*) > 2
+		) AS over_assigned
+	`)
+	if err != nil {
+		t.Fatalf("failed to count reviewers: %v", err)
+	}
+	if assignments != 0 {
+		t.Fatalf("expected no PR assigned twice, got %d over-assigned", assignments)
+	}
+}
+
+func doPost(t *testing.T, ts *TestServer, path string, body string) *http.Response {
+	req, err := http.NewRequest(http.MethodPost, ts.Server.URL+path, bytes.NewBuffer([]byte(body)))
+	if err != nil {
+		t.Fatalf("failed to build POST %s: %v", path, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	setAdminKey(ts, req)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("POST %s failed: %v", path, err)
+	}
+	return resp
+}
+
+func doGet(t *testing.T, ts *TestServer, path string) *http.Response {
+	req, err := http.NewRequest(http.MethodGet, ts.Server.URL+path, nil)
+	if err != nil {
+		t.Fatalf("failed to build GET %s: %v", path, err)
+	}
+	setAdminKey(ts, req)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET %s failed: %v", path, err)
+	}
+	return resp
+}
+
+// setAdminKey sends the fixture admin key on admin routes, which reject
+// requests without a key.
+func setAdminKey(ts *TestServer, req *http.Request) {
+	if strings.HasPrefix(req.URL.Path, "/admin/") || req.URL.Path == "/users/offboard" {
+		req.Header.Set("X-API-Key", ts.AdminKey)
+	}
+}
+
+func doPostAs(t *testing.T, ts *TestServer, path string, body string, userID string) *http.Response {
+	req, err := http.NewRequest(http.MethodPost, ts.Server.URL+path, bytes.NewBuffer([]byte(body)))
+	if err != nil {
+		t.Fatalf("failed to build POST %s: %v", path, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-User-ID", userID)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("POST %s failed: %v", path, err)
+	}
+	return resp
+}
+
+func doGetAs(t *testing.T, ts *TestServer, path string, userID string) *http.Response {
+	req, err := http.NewRequest(http.MethodGet, ts.Server.URL+path, nil)
+	if err != nil {
+		t.Fatalf("failed to build GET %s: %v", path, err)
+	}
+	req.Header.Set("X-User-ID", userID)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET %s failed: %v", path, err)
+	}
+	return resp
+}
+
+func doWithKey(t *testing.T, ts *TestServer, method string, path string, body string, key string) *http.Response {
+	return doWithHeader(t, ts, method, path, body, "X-API-Key", key)
+}
+
+func doWithHeader(t *testing.T, ts *TestServer, method string, path string, body string, header string, value string) *http.Response {
+	req, err := http.NewRequest(method, ts.Server.URL+path, bytes.NewBuffer([]byte(body)))
+	if err != nil {
+		t.Fatalf("failed to build %s %s: %v", method, path, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(header, value)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s failed: %v", method, path, err)
 	}
 	return resp
 }
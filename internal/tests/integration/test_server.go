@@ -1,33 +1,130 @@
 package integration
 
 import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"log"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/jmoiron/sqlx"
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 	"log/slog"
+	"net/http"
 	"net/http/httptest"
 	"os"
+	"pull-request-assigner/internal/domain/events"
+	"pull-request-assigner/internal/http/v1/middleware"
 	"pull-request-assigner/internal/http/v1/router"
+	"pull-request-assigner/internal/lib/chaos"
+	"pull-request-assigner/internal/lib/forge"
 	"pull-request-assigner/internal/repo"
 	"pull-request-assigner/internal/service"
+	"pull-request-assigner/internal/storage/postgresql"
+	"sync/atomic"
+	"time"
 )
 
 type TestServer struct {
-	DB     *sqlx.DB
-	Server *httptest.Server
+	DB       *sqlx.DB
+	Server   *httptest.Server
+	Fairness *service.FairnessService
+
+	stopWorkers context.CancelFunc
+
+	PullRequests *service.PullRequestService
+	Stats        *service.StatsService
+	Dashboard    *service.DashboardService
+	Forge        *FakeForge
+
+	// AdminKey is an admin-scoped API key without a user, issued by
+	// LoadFixtures; doPost and doGet send it on admin routes.
+	AdminKey string
 }
 
-func NewTestServer() (*TestServer, error) {
-	dbURL := "host=localhost port=5432 user=postgres password=postgres dbname=pullrequest_db sslmode=disable"
+// FakeForge serves the GitHub endpoints of an "acme" organization with an
+// active and an archived repository. The active one lists its open pull
+// requests two per page, and the first request of the server is answered
+// with a 429 to exercise the rate limit handling.
+type FakeForge struct {
+	Server      *httptest.Server
+	RateLimited atomic.Int32
+	requests    atomic.Int32
+}
+
+// forgeWebhookSecret signs the forge webhook deliveries sent by the tests.
+const forgeWebhookSecret = "forge-secret"
+
+const fakeForgePulls = `[
+	[
+		{"number": 1, "title": "Add retries", "draft": false, "user": {"login": "alice"},
+			"labels": [{"name": "backend"}], "created_at": "2026-01-05T10:00:00Z"},
+		{"number": 2, "title": "WIP cache", "draft": true, "user": {"login": "Bob"},
+			"labels": [], "created_at": "2026-01-06T10:00:00Z"}
+	],
+	[
+		{"number": 3, "title": "Fix typo", "draft": false, "user": {"login": "stranger"},
+			"labels": [], "created_at": "2026-01-07T10:00:00Z"},
+		{"number": 4, "title": "New test plan", "draft": false, "user": {"login": "Ivan"},
+			"labels": [], "created_at": "2026-01-08T10:00:00Z"}
+	]
+]`
+
+func newFakeForge() *FakeForge {
+	fake := &FakeForge{}
+
+	var pages [][]json.RawMessage
+	if err := json.Unmarshal([]byte(fakeForgePulls), &pages); err != nil {
+		panic(err)
+	}
 
-	db, err := sqlx.Connect("postgres", dbURL)
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /orgs/acme/repos", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"full_name": "acme/api"}, {"full_name": "acme/legacy", "archived": true}]`))
+	})
+	mux.HandleFunc("GET /repos/acme/api/pulls", func(w http.ResponseWriter, r *http.Request) {
+		page := 0
+		if r.URL.Query().Get("page") == "2" {
+			page = 1
+		} else {
+			w.Header().Set("Link", fmt.Sprintf(`<http://%s/repos/acme/api/pulls?state=open&per_page=100&page=2>; rel="next"`, r.Host))
+		}
+		json.NewEncoder(w).Encode(pages[page])
+	})
+	mux.HandleFunc("GET /repos/acme/legacy/pulls", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"number": 9, "title": "Archived", "user": {"login": "alice"}, "created_at": "2025-01-01T00:00:00Z"}]`))
+	})
+
+	fake.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fake.requests.Add(1) == 1 {
+			fake.RateLimited.Add(1)
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	}))
+
+	return fake
+}
+
+// testDSN is the database the test servers run against.
+const testDSN = "host=localhost port=5432 user=postgres password=postgres dbname=pullrequest_db sslmode=disable"
+
+func NewTestServer() (*TestServer, error) {
+	connector, err := pq.NewConnector(testDSN)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
+	db := sqlx.NewDb(sql.OpenDB(chaos.Wrap(connector)), "postgres")
+	if err := db.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
 	log := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
 		Level: slog.LevelError,
 	}))
@@ -35,26 +132,133 @@ func NewTestServer() (*TestServer, error) {
 	prRepo := repo.NewPullRequestRepo(db)
 	teamRepo := repo.NewTeamRepo(db)
 	userRepo := repo.NewUserRepo(db)
+	prStatusRepo := repo.NewPRStatusRepo(db)
+	archiveRepo := repo.NewArchiveRepo(db)
+	simulationRepo := repo.NewSimulationRepo(db)
+	dbCheckRepo := repo.NewDBCheckRepo(db)
+	statsRepo := repo.NewStatsRepo(db)
+	usageRepo := repo.NewUsageRepo(db)
+	jobRepo := repo.NewJobRepo(db)
+	auditRepo := repo.NewAuditRepo(db)
+	certificationRepo := repo.NewCertificationRepo(db)
+	tokenRepo := repo.NewTokenRepo(db)
+	impersonationRepo := repo.NewImpersonationRepo(db)
+	freezeRepo := repo.NewFreezeRepo(db)
+	mergeWindowRepo := repo.NewMergeWindowRepo(db)
+	assignmentRepairRepo := repo.NewAssignmentRepairRepo(db)
+	poolRepo := repo.NewPoolRepo(db)
+	policyRepo := repo.NewPolicyRepo(db)
+	webhookRepo := repo.NewWebhookRepo(db)
+	nonceRepo := repo.NewNonceRepo(db)
+	activityRepo := repo.NewActivityRepo(db)
+	templateRepo := repo.NewNotificationTemplateRepo(db)
+	dashboardRepo := repo.NewDashboardRepo(db)
+	notifyRepo := repo.NewNotifyRepo(db)
+
+	bus := events.NewBus()
+	reviewWatcher := service.NewReviewWatcher()
+	bus.Subscribe(reviewWatcher.Handle)
+	bus.Subscribe(service.NewAuditSink(log, auditRepo))
+	bus.Subscribe(service.NewActivitySink(log, activityRepo))
+
+	candidateCache := service.NewCandidateCache(log, time.Minute, notifyRepo)
+	bus.Subscribe(candidateCache.Handle)
+
+	notificationTemplates := service.NewNotificationTemplates(log, templateRepo)
+	bus.Subscribe(notificationTemplates.Handle)
+
+	dashboardService := service.NewDashboardService(log, dashboardRepo, 24*time.Hour)
+	bus.Subscribe(dashboardService.Handle)
+
+	webhookHealth := service.NewIntegrationTracker(service.IntegrationWebhooks, true)
+	webhookService := service.NewWebhookService(log, webhookRepo, time.Second, service.WebhookRetry{
+		MaxAttempts: 3,
+		Backoff:     20 * time.Millisecond,
+	}, webhookHealth, notificationTemplates, 24*time.Hour, 7*24*time.Hour)
+	bus.Subscribe(webhookService.Handle)
+
+	prService := service.NewPullRequestService(log, prRepo, teamRepo, prStatusRepo, certificationRepo, freezeRepo, poolRepo, mergeWindowRepo, assignmentRepairRepo, service.SecurityReviewPolicy{
+		TeamName:     "QA",
+		Labels:       []string{"security"},
+		PathPrefixes: []string{"internal/auth/"},
+	}, 3, candidateCache, 0, bus)
+	assignmentRepairService := service.NewAssignmentRepairService(log, assignmentRepairRepo, prService, time.Minute, 2, bus)
+	teamService := service.NewTeamService(log, teamRepo, auditRepo, bus)
+	userService := service.NewUserService(log, userRepo, bus, 24*time.Hour, "", reviewWatcher, prService)
+	statsService := service.NewStatsService(log, statsRepo, teamRepo)
+	adminService := service.NewAdminService(log, archiveRepo, userRepo, simulationRepo, dbCheckRepo, jobRepo, bus, "test-secret")
+	certificationService := service.NewCertificationService(log, certificationRepo)
+	poolService := service.NewPoolService(log, poolRepo)
+	policyService := service.NewPolicyService(log, policyRepo, bus)
+	tokenService := service.NewTokenService(log, tokenRepo, "test-secret")
+	impersonationService := service.NewImpersonationService(log, impersonationRepo, userRepo, bus, time.Hour)
+	usageService := service.NewUsageService(log, usageRepo, 0)
+	adminSignatureService := service.NewAdminSignatureService(log, nonceRepo, "", time.Minute)
+	fairnessService := service.NewFairnessService(log, statsRepo, bus, 24*time.Hour, 0.5, 4)
 
-	prService := service.NewPullRequestService(log, prRepo, teamRepo)
-	teamService := service.NewTeamService(log, teamRepo)
-	userService := service.NewUserService(log, userRepo)
+	fakeForge := newFakeForge()
+	forgeClient, err := forge.New(forge.Config{
+		Kind:             forge.KindGitHub,
+		BaseURL:          fakeForge.Server.URL,
+		Token:            "test-token",
+		Org:              "acme",
+		Timeout:          time.Second,
+		MaxRateLimitWait: 5 * time.Second,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create forge client: %w", err)
+	}
+	offboardingService := service.NewOffboardingService(log, userRepo, poolRepo, tokenRepo, webhookRepo, userService, prService, adminService, bus)
+	forgeHealth := service.NewIntegrationTracker(service.IntegrationForge, forgeClient != nil)
+	backfillService := service.NewBackfillService(log, forgeClient, userRepo, prService, forgeHealth)
+	activityService := service.NewActivityService(log, activityRepo)
+	forgeEventService := service.NewForgeEventService(log, forge.KindGitHub, forgeWebhookSecret, userRepo, prService)
+	healthService := service.NewHealthService(log, db, forgeHealth, webhookHealth)
+	metaService := service.NewMetaService(log, prStatusRepo)
+	templateService := service.NewNotificationTemplateService(log, templateRepo, bus)
 
 	r := chi.NewRouter()
-	router.NewPullRequestRouter(prService, log).SetupRoutes(r)
-	router.NewTeamRouter(teamService, log).SetupRoutes(r)
-	router.NewUserRouter(userService, log).SetupRoutes(r)
+	r.Use(middleware.Identity)
+	r.Use(middleware.Timezone(log))
+	r.Use(middleware.Auth(tokenService, false, log))
+	r.Use(middleware.Impersonation(impersonationService, log))
+	r.Use(middleware.Usage(usageService, log))
+	router.NewPullRequestRouter(prService, activityService, dashboardService, middleware.NewConcurrencyLimiter(0, 0, log), log).SetupRoutes(r)
+	router.NewTeamRouter(teamService, webhookService, log).SetupRoutes(r)
+	router.NewUserRouter(userService, offboardingService, adminSignatureService, log).SetupRoutes(r)
+	router.NewAdminRouter(adminService, usageService, tokenService, impersonationService, prService, policyService, adminSignatureService, backfillService, teamService, templateService, assignmentRepairService, webhookService, log).SetupRoutes(r)
+	router.NewStatsRouter(statsService, log).SetupRoutes(r)
+	router.NewCertificationRouter(certificationService, log).SetupRoutes(r)
+	router.NewPoolRouter(poolService, log).SetupRoutes(r)
+	router.NewPolicyRouter(policyService, log).SetupRoutes(r)
+	router.NewVersionRouter(log).SetupRoutes(r)
+	router.NewMetaRouter(metaService, log).SetupRoutes(r)
+	router.NewHealthRouter(healthService, log).SetupRoutes(r)
+	router.NewForgeRouter(forgeEventService, log).SetupRoutes(r)
 
 	ts := httptest.NewServer(r)
 
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	go webhookService.Run(workersCtx)
+	go postgresql.Listen(workersCtx, testDSN, service.CandidateCacheChannel, log, candidateCache.HandleNotification)
+
 	return &TestServer{
-		DB:     db,
-		Server: ts,
+		DB:          db,
+		Server:      ts,
+		Fairness:    fairnessService,
+		stopWorkers: stopWorkers,
+
+		PullRequests: prService,
+		Stats:        statsService,
+		Dashboard:    dashboardService,
+		Forge:        fakeForge,
 	}, nil
 }
 
+const fixtureAdminKey = "pra_fixtures_admin"
+
 func (s *TestServer) LoadFixtures() error {
-	tables := []string{"pr_reviewers", "pull_requests", "team_members", "users", "teams"}
+	tables := []string{"api_tokens", "api_quota_counters", "assignment_freezes", "audit_events", "stats_history", "impersonation_sessions", "pr_events", "pr_reviewers", "pull_requests", "dashboard_prs", "reviewer_pool_members", "reviewer_pools", "webhook_deliveries", "team_webhooks", "notification_templates", "team_members", "users", "teams", "admin_request_nonces"}
 	for _, table := range tables {
 		_, err := s.DB.Exec(fmt.Sprintf("TRUNCATE %s RESTART IDENTITY CASCADE", table))
 		if err != nil {
@@ -63,6 +267,17 @@ func (s *TestServer) LoadFixtures() error {
 	}
 
 	fixtures := `
+		UPDATE org_policy SET
+			hold_until_ci_green = FALSE,
+			min_reviewers = 1,
+			reviewers_per_pr = 2,
+			default_priority = NULL,
+			default_labels = '{}',
+			default_required_skills = '{}',
+			exclude_co_authors = FALSE,
+			exclude_pairing_session = FALSE,
+			diversify_reviewer_pairs = FALSE;
+
 		INSERT INTO teams(team_name) VALUES 
 			('Backend'),
 			('QA');
@@ -91,11 +306,20 @@ func (s *TestServer) LoadFixtures() error {
 		return fmt.Errorf("failed to load fixtures: %w", err)
 	}
 
+	hash := sha256.Sum256([]byte(fixtureAdminKey))
+	_, err = s.DB.Exec(`INSERT INTO api_tokens (name, token_hash, scopes) VALUES ('fixtures', $1, '{admin}')`, hex.EncodeToString(hash[:]))
+	if err != nil {
+		return fmt.Errorf("failed to issue fixture admin key: %w", err)
+	}
+	s.AdminKey = fixtureAdminKey
+
 	log.Println("Fixtures loaded successfully")
 	return nil
 }
 
 func (s *TestServer) Close() {
+	s.stopWorkers()
 	s.Server.Close()
+	s.Forge.Server.Close()
 	s.DB.Close()
 }
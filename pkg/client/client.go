@@ -0,0 +1,165 @@
+// Package client is a Go client for the reviewer assignment HTTP API. It
+// covers the calls other services make most, creating PRs, reassigning
+// reviewers and reading a review queue, and verifies the team webhook
+// deliveries the service sends.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	apiKeyHeader = "X-API-Key"
+	userIDHeader = "X-User-ID"
+)
+
+// Client calls the API at baseURL. APIKey is sent with every request when
+// set; the zero HTTPClient uses a 10 second timeout.
+type Client struct {
+	baseURL    string
+	apiKey     string
+	httpClient *http.Client
+}
+
+func New(baseURL string, apiKey string, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	return &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		apiKey:     apiKey,
+		httpClient: httpClient,
+	}
+}
+
+// Error is an error response of the API. Code is the machine-readable code,
+// such as NOT_FOUND or PR_EXISTS; Message may be localized.
+type Error struct {
+	StatusCode int
+	Code       string
+	Message    string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("assigner API: %d %s: %s", e.StatusCode, e.Code, e.Message)
+}
+
+// CreatePR creates a PR and assigns its reviewers.
+func (c *Client) CreatePR(ctx context.Context, req CreatePRRequest) (*PullRequest, error) {
+	var resp struct {
+		PR *PullRequest `json:"pr"`
+	}
+
+	if err := c.do(ctx, http.MethodPost, "/pullRequest/create", "", req, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.PR, nil
+}
+
+// Reassign replaces oldReviewerID on the PR with another team member and
+// returns the PR with the ID of the new reviewer. reason is one of the
+// Reason constants.
+func (c *Client) Reassign(ctx context.Context, prID string, oldReviewerID string, reason string) (*PullRequest, string, error) {
+	return c.ReassignWithNote(ctx, prID, oldReviewerID, reason, "")
+}
+
+// ReassignWithNote is Reassign passing a hand-off note to the new reviewer,
+// for example the files already reviewed.
+func (c *Client) ReassignWithNote(ctx context.Context, prID string, oldReviewerID string, reason string, note string) (*PullRequest, string, error) {
+	req := struct {
+		PullRequestID string `json:"pull_request_id"`
+		OldReviewerID string `json:"old_reviewer_id"`
+		Reason        string `json:"reason,omitempty"`
+		Note          string `json:"note,omitempty"`
+	}{prID, oldReviewerID, reason, note}
+
+	var resp struct {
+		PR         *PullRequest `json:"pr"`
+		ReplacedBy string       `json:"replaced_by"`
+	}
+
+	if err := c.do(ctx, http.MethodPost, "/pullRequest/reassign", "", req, &resp); err != nil {
+		return nil, "", err
+	}
+
+	return resp.PR, resp.ReplacedBy, nil
+}
+
+// GetMyReviews returns the open reviews assigned to userID, most urgent
+// first.
+func (c *Client) GetMyReviews(ctx context.Context, userID string) ([]ReviewAssignment, error) {
+	var resp struct {
+		Reviews []ReviewAssignment `json:"reviews"`
+	}
+
+	if err := c.do(ctx, http.MethodGet, "/users/myReviews", userID, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Reviews, nil
+}
+
+func (c *Client) do(ctx context.Context, method string, path string, userID string, body any, out any) error {
+	var reader io.Reader
+	if body != nil {
+		raw, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("assigner API: encode %s: %w", path, err)
+		}
+		reader = bytes.NewReader(raw)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.apiKey != "" {
+		req.Header.Set(apiKeyHeader, c.apiKey)
+	}
+	if userID != "" {
+		req.Header.Set(userIDHeader, userID)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("assigner API: %s %s: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		apiErr := &Error{StatusCode: resp.StatusCode}
+
+		var errResp struct {
+			Error struct {
+				Code    string `json:"code"`
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if json.NewDecoder(resp.Body).Decode(&errResp) == nil {
+			apiErr.Code = errResp.Error.Code
+			apiErr.Message = errResp.Error.Message
+		}
+
+		return apiErr
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("assigner API: decode %s: %w", path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package client
+
+import "time"
+
+// Reassignment reasons accepted by Reassign.
+const (
+	ReasonVacation   = "VACATION"
+	ReasonOverloaded = "OVERLOADED"
+	ReasonConflict   = "CONFLICT"
+	ReasonDeclined   = "DECLINED"
+	ReasonManual     = "MANUAL"
+)
+
+type ReviewerTeamQuota struct {
+	TeamName  string `json:"team_name"`
+	Reviewers int    `json:"reviewers"`
+}
+
+// CreatePRRequest describes a new PR. Only PullRequestID, PullRequestName
+// and AuthorID are required.
+type CreatePRRequest struct {
+	PullRequestID   string   `json:"pull_request_id"`
+	PullRequestName string   `json:"pull_request_name"`
+	AuthorID        string   `json:"author_id"`
+	CIStatus        string   `json:"ci_status,omitempty"`
+	Priority        string   `json:"priority,omitempty"`
+	Labels          []string `json:"labels,omitempty"`
+	RequiredSkills  []string `json:"required_skills,omitempty"`
+	ChangedPaths    []string `json:"changed_paths,omitempty"`
+	CoAuthors       []string `json:"co_authors,omitempty"`
+	PairingSession  []string `json:"pairing_session,omitempty"`
+
+	AutoMerge          bool `json:"auto_merge,omitempty"`
+	AutoMergeApprovals int  `json:"auto_merge_approvals,omitempty"`
+
+	ReviewerTeams          []ReviewerTeamQuota `json:"reviewer_teams,omitempty"`
+	RequiredCertifications []string            `json:"required_certifications,omitempty"`
+	ReviewerPool           string              `json:"reviewer_pool,omitempty"`
+}
+
+type PullRequest struct {
+	PullRequestID     string   `json:"pull_request_id"`
+	PullRequestName   string   `json:"pull_request_name"`
+	AuthorID          string   `json:"author_id"`
+	Status            string   `json:"status"`
+	CIStatus          string   `json:"ci_status"`
+	Priority          string   `json:"priority"`
+	Labels            []string `json:"labels"`
+	RequiredSkills    []string `json:"required_skills"`
+	AssignedReviewers []string `json:"assigned_reviewers"`
+	MergedAt          string   `json:"mergedAt,omitempty"`
+	MergedBy          string   `json:"merged_by,omitempty"`
+	AutoMerge         bool     `json:"auto_merge,omitempty"`
+	AssignmentQueued  bool     `json:"assignment_queued,omitempty"`
+	ReviewerPool      string   `json:"reviewer_pool,omitempty"`
+
+	ReviewerTeams          []ReviewerTeamQuota `json:"reviewer_teams,omitempty"`
+	RequiredCertifications []string            `json:"required_certifications,omitempty"`
+}
+
+// ReviewAssignment is an open review in a user's queue with its SLA.
+type ReviewAssignment struct {
+	PullRequestID   string    `json:"pull_request_id"`
+	PullRequestName string    `json:"pull_request_name"`
+	AuthorID        string    `json:"author_id"`
+	Status          string    `json:"status"`
+	Priority        string    `json:"priority"`
+	CreatedAt       time.Time `json:"created_at"`
+	DueAt           time.Time `json:"due_at"`
+	Overdue         bool      `json:"overdue"`
+	Link            string    `json:"link,omitempty"`
+}
+
+type User struct {
+	UserID      string `json:"user_id"`
+	Username    string `json:"username"`
+	TeamName    string `json:"team_name"`
+	IsActive    bool   `json:"is_active"`
+	DisplayName string `json:"display_name,omitempty"`
+	Email       string `json:"email,omitempty"`
+	AvatarURL   string `json:"avatar_url,omitempty"`
+	Locale      string `json:"locale,omitempty"`
+}
@@ -0,0 +1,90 @@
+package client
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookSignatureHeader = "X-Webhook-Signature"
+	WebhookAttemptHeader   = "X-Webhook-Attempt"
+)
+
+// Events a team webhook receives.
+const (
+	EventPullRequestCreated = "pr.created"
+	EventPullRequestMerged  = "pr.merged"
+	EventReviewersReleased  = "pr.reviewers_released"
+	EventReviewerAssigned   = "reviewer.assigned"
+	EventReviewerReassigned = "reviewer.reassigned"
+	EventReviewerDelegated  = "reviewer.delegated"
+	EventReviewerUnassigned = "reviewer.unassigned"
+)
+
+// maxWebhookBody bounds the delivery body ParseWebhook reads.
+const maxWebhookBody = 1 << 20
+
+var ErrInvalidWebhookSignature = errors.New("assigner webhook: invalid signature")
+
+// WebhookDelivery is the body of a team webhook delivery. Users holds the
+// profile of every user Data refers to, keyed by user ID.
+type WebhookDelivery struct {
+	Event         string          `json:"event"`
+	TeamName      string          `json:"team_name"`
+	PullRequestID string          `json:"pull_request_id"`
+	Data          map[string]any  `json:"data"`
+	Users         map[string]User `json:"users"`
+	SentAt        time.Time       `json:"sent_at"`
+}
+
+// VerifyWebhook checks the X-Webhook-Signature value of a delivery: the hex
+// HMAC-SHA256 of the body under the webhook secret, prefixed with "sha256=".
+// The comparison takes constant time.
+func VerifyWebhook(secret string, body []byte, signature string) error {
+	sum, ok := strings.CutPrefix(signature, "sha256=")
+	if !ok {
+		return ErrInvalidWebhookSignature
+	}
+
+	got, err := hex.DecodeString(sum)
+	if err != nil {
+		return ErrInvalidWebhookSignature
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	if !hmac.Equal(got, mac.Sum(nil)) {
+		return ErrInvalidWebhookSignature
+	}
+
+	return nil
+}
+
+// ParseWebhook reads a delivery from the request a webhook receiver got and
+// decodes it once its signature is verified.
+func ParseWebhook(r *http.Request, secret string) (*WebhookDelivery, error) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBody))
+	if err != nil {
+		return nil, fmt.Errorf("assigner webhook: read body: %w", err)
+	}
+
+	if err := VerifyWebhook(secret, body, r.Header.Get(WebhookSignatureHeader)); err != nil {
+		return nil, err
+	}
+
+	var delivery WebhookDelivery
+	if err := json.Unmarshal(body, &delivery); err != nil {
+		return nil, fmt.Errorf("assigner webhook: decode body: %w", err)
+	}
+
+	return &delivery, nil
+}